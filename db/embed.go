@@ -0,0 +1,11 @@
+// Package db embeds the goose SQL migrations so the binary can apply them
+// without the db/ directory on disk.
+package db
+
+import "embed"
+
+// Migrations holds migrations/ (the single-database layout used by the
+// all-in-one binary) and its auth/ and wallet/ per-module layouts.
+//
+//go:embed migrations
+var Migrations embed.FS
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE outbox (
+    id bigserial PRIMARY KEY,
+    event_type varchar(64) NOT NULL,
+    transaction_id varchar(100) NOT NULL,
+    payload jsonb NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text,
+    available_at timestamptz NOT NULL DEFAULT now(),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    sent_at timestamptz
+);
+
+CREATE INDEX idx_outbox_unsent_id
+ON outbox (id)
+WHERE sent_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_unsent_id;
+DROP TABLE IF EXISTS outbox;
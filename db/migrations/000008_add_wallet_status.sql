@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE wallets
+ADD COLUMN status varchar(16) NOT NULL DEFAULT 'active'
+CONSTRAINT chk_wallets_status CHECK (status IN ('active', 'frozen'));
+
+-- +goose Down
+ALTER TABLE wallets DROP COLUMN IF EXISTS status;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE idempotency_dead_letter (
+    id bigserial PRIMARY KEY,
+    scope varchar(128) NOT NULL,
+    idempotency_key varchar(128) NOT NULL,
+    request_hash char(64) NOT NULL,
+    response_status integer NOT NULL,
+    response_body bytea,
+    response_content_type varchar(255),
+    last_error text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    resolved_at timestamptz
+);
+
+CREATE INDEX idx_idempotency_dead_letter_unresolved_id
+ON idempotency_dead_letter (id)
+WHERE resolved_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_idempotency_dead_letter_unresolved_id;
+DROP TABLE IF EXISTS idempotency_dead_letter;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE outbox ADD COLUMN failed_at timestamptz;
+
+DROP INDEX IF EXISTS idx_outbox_unsent_id;
+
+CREATE INDEX idx_outbox_unsent_id
+ON outbox (id)
+WHERE sent_at IS NULL AND failed_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_unsent_id;
+
+CREATE INDEX idx_outbox_unsent_id
+ON outbox (id)
+WHERE sent_at IS NULL;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS failed_at;
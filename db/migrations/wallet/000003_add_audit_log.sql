@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE audit_log (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    action varchar(64) NOT NULL,
+    user_id varchar(128) NOT NULL,
+    amount_minor bigint NOT NULL,
+    currency varchar(3),
+    chain_id varchar(128),
+    decision varchar(32) NOT NULL,
+    reason text,
+    reference_id varchar(100),
+    request_id varchar(128),
+    occurred_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_user_occurred_at_desc
+ON audit_log (user_id, occurred_at DESC);
+
+-- +goose StatementBegin
+CREATE FUNCTION audit_log_reject_mutation() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_reject_mutation();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_reject_mutation();
+DROP INDEX IF EXISTS idx_audit_log_user_occurred_at_desc;
+DROP TABLE IF EXISTS audit_log;
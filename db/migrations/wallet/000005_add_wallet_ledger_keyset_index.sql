@@ -0,0 +1,6 @@
+-- +goose Up
+CREATE INDEX idx_wallet_ledger_wallet_created_at_id_desc
+ON wallet_ledger (wallet_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallet_ledger_wallet_created_at_id_desc;
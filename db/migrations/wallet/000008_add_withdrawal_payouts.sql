@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE withdrawal_payouts (
+    reference_id varchar(100) PRIMARY KEY,
+    user_id uuid NOT NULL,
+    amount_minor bigint NOT NULL,
+    fee_minor bigint NOT NULL DEFAULT 0,
+    currency varchar(3) NOT NULL,
+    chain_id varchar(128),
+    status varchar(16) NOT NULL DEFAULT 'pending'
+        CONSTRAINT chk_withdrawal_payouts_status CHECK (status IN ('pending', 'completed', 'reversed')),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_withdrawal_payouts_pending_created_at
+ON withdrawal_payouts (created_at)
+WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_withdrawal_payouts_pending_created_at;
+DROP TABLE IF EXISTS withdrawal_payouts;
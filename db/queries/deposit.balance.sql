@@ -0,0 +1,15 @@
+-- name: DepositWalletBalanceByUserID :one
+UPDATE wallets
+SET
+    balance_minor = balance_minor + sqlc.arg(amount_minor)::bigint,
+    version = version + 1,
+    updated_at = now()
+WHERE user_id = sqlc.arg(user_id)::uuid
+  AND version = sqlc.arg(expected_version)::bigint
+RETURNING
+    id AS wallet_id,
+    user_id::text AS user_id,
+    balance_minor,
+    currency,
+    version,
+    updated_at;
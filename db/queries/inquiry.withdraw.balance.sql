@@ -5,6 +5,15 @@ SELECT EXISTS (
     WHERE user_id = sqlc.arg(user_id)::uuid
 );
 
+-- name: GetWalletVersionByUserID :one
+SELECT
+    id AS wallet_id,
+    balance_minor,
+    version,
+    status
+FROM wallets
+WHERE user_id = sqlc.arg(user_id)::uuid;
+
 -- name: WithdrawWalletBalanceByUserID :one
 UPDATE wallets
 SET
@@ -13,11 +22,13 @@ SET
     updated_at = now()
 WHERE user_id = sqlc.arg(user_id)::uuid
   AND balance_minor >= sqlc.arg(amount_minor)::bigint
+  AND version = sqlc.arg(expected_version)::bigint
 RETURNING
     id AS wallet_id,
     user_id::text AS user_id,
     balance_minor,
     currency,
+    version,
     updated_at;
 
 -- name: InsertWalletLedger :exec
@@ -39,3 +50,25 @@ VALUES (
     sqlc.narg(chain_id),
     now()
 );
+
+-- name: GetWithdrawalLedgerByReferenceID :many
+SELECT
+    l.entry_type,
+    l.amount_minor,
+    l.chain_id,
+    w.currency,
+    l.created_at
+FROM wallet_ledger l
+JOIN wallets w ON w.id = l.wallet_id
+WHERE l.reference_id = sqlc.arg(reference_id)::text
+  AND w.user_id = sqlc.arg(user_id)::uuid
+  AND l.entry_type IN ('withdrawal', 'fee', 'withdrawal_reversal', 'fee_reversal')
+ORDER BY l.created_at, l.id;
+
+-- name: SumWithdrawalsByUserSince :one
+SELECT COALESCE(SUM(-l.amount_minor), 0)::bigint AS withdrawn_minor
+FROM wallet_ledger l
+JOIN wallets w ON w.id = l.wallet_id
+WHERE w.user_id = sqlc.arg(user_id)::uuid
+  AND l.entry_type IN ('withdrawal', 'withdrawal_reversal')
+  AND l.created_at >= sqlc.arg(since)::timestamptz;
@@ -0,0 +1,37 @@
+-- name: GetWalletStatusByIDAndUserID :one
+SELECT status
+FROM wallets
+WHERE id = sqlc.arg(wallet_id)::uuid
+  AND user_id = sqlc.arg(user_id)::uuid;
+
+-- name: DebitWalletBalanceByID :one
+UPDATE wallets
+SET
+    balance_minor = balance_minor - sqlc.arg(amount_minor)::bigint,
+    version = version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(wallet_id)::uuid
+  AND user_id = sqlc.arg(user_id)::uuid
+  AND status = 'active'
+  AND balance_minor >= sqlc.arg(amount_minor)::bigint
+RETURNING
+    id AS wallet_id,
+    user_id::text AS user_id,
+    balance_minor,
+    currency,
+    updated_at;
+
+-- name: CreditWalletBalanceByID :one
+UPDATE wallets
+SET
+    balance_minor = balance_minor + sqlc.arg(amount_minor)::bigint,
+    version = version + 1,
+    updated_at = now()
+WHERE id = sqlc.arg(wallet_id)::uuid
+  AND user_id = sqlc.arg(user_id)::uuid
+RETURNING
+    id AS wallet_id,
+    user_id::text AS user_id,
+    balance_minor,
+    currency,
+    updated_at;
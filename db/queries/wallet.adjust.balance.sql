@@ -0,0 +1,14 @@
+-- name: AdjustWalletBalanceByUserID :one
+UPDATE wallets
+SET
+    balance_minor = balance_minor + sqlc.arg(delta_minor)::bigint,
+    version = version + 1,
+    updated_at = now()
+WHERE user_id = sqlc.arg(user_id)::uuid
+  AND balance_minor + sqlc.arg(delta_minor)::bigint >= 0
+RETURNING
+    id AS wallet_id,
+    user_id::text AS user_id,
+    balance_minor,
+    currency,
+    updated_at;
@@ -0,0 +1,11 @@
+-- name: CreateWallet :one
+INSERT INTO wallets (id, user_id, currency)
+VALUES (sqlc.arg(id)::uuid, sqlc.arg(user_id)::uuid, sqlc.arg(currency)::varchar)
+RETURNING
+    id,
+    user_id::text AS user_id,
+    balance_minor,
+    currency,
+    version,
+    created_at,
+    updated_at;
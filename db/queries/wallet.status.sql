@@ -0,0 +1,11 @@
+-- name: SetWalletStatusByUserID :one
+UPDATE wallets
+SET
+    status = sqlc.arg(status),
+    version = version + 1,
+    updated_at = now()
+WHERE user_id = sqlc.arg(user_id)::uuid
+RETURNING
+    user_id::text AS user_id,
+    status,
+    updated_at;
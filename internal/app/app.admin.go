@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	"go.uber.org/fx"
+)
+
+// adminServer is where the operational routes live: /metrics, /debug/pprof, /debug/config
+// and the /api/v1/admin endpoints. With admin.port set it is a separate app on that port,
+// so none of them are reachable through the public listener; otherwise it is the main app.
+type adminServer struct {
+	App      *fiber.App
+	Port     int
+	Separate bool
+}
+
+func provideAdminServer(cfg config.ConfigProvider, app *fiber.App, logger *slog.Logger) (*adminServer, error) {
+	port := cfg.GetInt("admin.port")
+	if port <= 0 {
+		return &adminServer{App: app}, nil
+	}
+
+	adminApp, err := provideFiberApp(cfg)
+	if err != nil {
+		return nil, err
+	}
+	adminApp.Use(middlewares.NewHTTPRecoveryMiddleware(logger))
+	adminApp.Use(middlewares.NewHTTPRequestIDMiddleware())
+	adminApp.Use(middlewares.NewHTTPRequestResponseLogMiddleware(logger, loadRequestResponseLogConfig(cfg)))
+
+	return &adminServer{App: adminApp, Port: port, Separate: true}, nil
+}
+
+// registerAdminLifecycle serves the separate admin app. Its hooks are appended after the
+// main server's, so fx stops it first; the shared connections are closed after both by
+// registerConnectionClose.
+func registerAdminLifecycle(lifecycle fx.Lifecycle, admin *adminServer, cfg config.ConfigProvider, logger *slog.Logger) {
+	if !admin.Separate {
+		return
+	}
+
+	address := fmt.Sprintf(":%d", admin.Port)
+	shutdownTimeout := loadShutdownTimeout(cfg)
+	var serveErrCh chan error
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return fmt.Errorf("app: failed to bind admin address %s: %w", address, err)
+			}
+
+			serveErrCh = make(chan error, 1)
+			go func() {
+				err := admin.App.Listener(listener)
+				if err != nil && !errors.Is(err, net.ErrClosed) {
+					logger.Error("admin server stopped unexpectedly", "error", err)
+				}
+				serveErrCh <- err
+			}()
+
+			logger.Info("admin server started", "address", address)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			drainCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+			defer cancel()
+
+			var shutdownErrors []error
+			if err := admin.App.ShutdownWithContext(drainCtx); err != nil {
+				shutdownErrors = append(shutdownErrors, err)
+			}
+
+			if serveErrCh != nil {
+				select {
+				case err := <-serveErrCh:
+					if err != nil && !errors.Is(err, net.ErrClosed) {
+						shutdownErrors = append(shutdownErrors, err)
+					}
+				case <-drainCtx.Done():
+					shutdownErrors = append(shutdownErrors, drainCtx.Err())
+				}
+			}
+
+			if len(shutdownErrors) > 0 {
+				return errors.Join(shutdownErrors...)
+			}
+
+			logger.Info("admin server shutdown completed")
+			return nil
+		},
+	})
+}
@@ -1,11 +1,13 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
 	"github.com/joshuarp/withdraw-api/internal/shared/config"
 	sharedhash "github.com/joshuarp/withdraw-api/internal/shared/hash"
 	sharedjwt "github.com/joshuarp/withdraw-api/internal/shared/jwt"
@@ -18,8 +20,16 @@ type configBinIn struct {
 	Bin string `name:"bin"`
 }
 
+// New loads the config before building the graph so fx's stop timeout can follow
+// server.shutdown_timeout; with fx's fixed 15s default a longer drain would be cut off
+// before the DB and Redis connections are closed.
 func New(bin string, modules ...fx.Option) *fx.App {
 	normalizedBin := strings.TrimSpace(strings.ToLower(bin))
+	cfg, err := provideConfig(configBinIn{Bin: normalizedBin})
+	if err != nil {
+		return fx.New(fx.Error(err))
+	}
+
 	opts := []fx.Option{
 		fx.Supply(
 			fx.Annotate(
@@ -27,19 +37,21 @@ func New(bin string, modules ...fx.Option) *fx.App {
 				fx.ResultTags(`name:"bin"`),
 			),
 		),
+		fx.Provide(func() config.ConfigProvider { return cfg }),
+		fx.StopTimeout(appStopTimeout(cfg)),
 		CoreModule(),
 	}
 	opts = append(opts, modules...)
-	opts = append(opts, fx.Invoke(registerLifecycle))
+	opts = append(opts, fx.Invoke(registerLifecycle, registerAdminLifecycle))
 	return fx.New(opts...)
 }
 
 func CoreModule() fx.Option {
 	return fx.Module("core",
 		fx.Provide(
-			provideConfig,
-			sharedlog.NewJSONLogger,
+			sharedlog.NewLogger,
 			provideRedisClient,
+			provideWalletNotFoundCache,
 			fx.Annotate(
 				provideAuthPostgresSQLX,
 				fx.ResultTags(`name:"db_auth"`),
@@ -48,11 +60,24 @@ func CoreModule() fx.Option {
 				provideWalletPostgresSQLX,
 				fx.ResultTags(`name:"db_wallet"`),
 			),
+			fx.Annotate(
+				provideWalletReplicaPostgresSQLX,
+				fx.ResultTags(`name:"db_wallet_replica"`),
+			),
+			provideQueryTimeout,
+			provideUIDGenerator,
 			provideFiberApp,
+			provideAdminServer,
 			providePasswordHasher,
 			provideJWTTokenManager,
+			provideTracer,
+			provideEventPublisher,
+			provideMetricsRegistry,
+			provideInFlightTracker,
+			provideReadinessChecks,
 			provideRouterGroups,
 		),
+		fx.Invoke(registerConnectionClose, registerPprofRoutes, registerConfigWatch),
 	)
 }
 
@@ -99,7 +124,26 @@ func provideConfig(in configBinIn) (config.ConfigProvider, error) {
 	return nil, lastErr
 }
 
-func provideFiberApp(cfg config.ConfigProvider) *fiber.App {
+// registerConfigWatch reloads the YAML config on file changes when config.watch is set, firing
+// the OnChange callbacks registered by modules (e.g. the withdraw rate limiter).
+func registerConfigWatch(lifecycle fx.Lifecycle, cfg config.ConfigProvider) {
+	if !cfg.GetBool("config.watch") {
+		return
+	}
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			cfg.WatchChanges()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cfg.StopWatching()
+			return nil
+		},
+	})
+}
+
+func provideFiberApp(cfg config.ConfigProvider) (*fiber.App, error) {
 	readTimeout := cfg.GetDuration("server.read_timeout")
 	if readTimeout <= 0 {
 		readTimeout = 30 * time.Second
@@ -110,10 +154,54 @@ func provideFiberApp(cfg config.ConfigProvider) *fiber.App {
 		writeTimeout = 30 * time.Second
 	}
 
-	return fiber.New(fiber.Config{
+	fiberCfg := withTrustedProxies(cfg, fiber.Config{
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 	})
+	app := fiber.New(fiberCfg)
+	if !fiberCfg.TrustProxy {
+		return app, nil
+	}
+
+	// Registered first so every later c.IP() sees the resolved client address.
+	forwardedFor, err := middlewares.NewHTTPForwardedForMiddleware(fiberCfg.TrustProxyConfig.Proxies, fiberCfg.ProxyHeader)
+	if err != nil {
+		return nil, fmt.Errorf("app: failed to configure server.trusted_proxies: %w", err)
+	}
+	app.Use(forwardedFor)
+
+	return app, nil
+}
+
+// withTrustedProxies makes c.IP() read the client address from server.proxy_header
+// (default X-Forwarded-For) on requests arriving from server.trusted_proxies, which may
+// list IPs or CIDR ranges. With no trusted proxies the header is ignored, since trusting
+// it from any peer would let clients pick their own IP and dodge per-IP rate limits.
+// provideFiberApp pairs it with NewHTTPForwardedForMiddleware, which picks the rightmost
+// untrusted address of the chain rather than Fiber's leftmost, client-controlled one.
+func withTrustedProxies(cfg config.ConfigProvider, fiberCfg fiber.Config) fiber.Config {
+	var proxies []string
+	for _, proxy := range cfg.GetStringSlice("server.trusted_proxies") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	if len(proxies) == 0 {
+		return fiberCfg
+	}
+
+	proxyHeader := strings.TrimSpace(cfg.GetString("server.proxy_header"))
+	if proxyHeader == "" {
+		proxyHeader = fiber.HeaderXForwardedFor
+	}
+
+	fiberCfg.TrustProxy = true
+	fiberCfg.TrustProxyConfig = fiber.TrustProxyConfig{Proxies: proxies}
+	fiberCfg.ProxyHeader = proxyHeader
+	// Validation makes c.IP() parse the single address left by the forwarded-for
+	// middleware instead of returning the raw header value.
+	fiberCfg.EnableIPValidation = true
+	return fiberCfg
 }
 
 func providePasswordHasher() (sharedhash.Hasher, error) {
@@ -144,6 +232,7 @@ func provideJWTTokenManager(cfg config.ConfigProvider) (sharedjwt.TokenManager,
 		Algorithm: "HS256",
 		TTL:       ttl,
 		Issuer:    cfg.GetString("security.jwt.issuer"),
+		Audience:  cfg.GetStringSlice("security.jwt.audience"),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("app: failed to init JWT manager: %w", err)
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/pprof"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	"go.uber.org/fx"
+)
+
+const redactedValue = "[REDACTED]"
+
+var (
+	debugEnvironments = []string{"local", "development", "dev", "staging", "test"}
+	sensitiveKeyHints = []string{"secret", "password", "token", "credential", "private_key"}
+)
+
+func registerDebugConfigRoute(app *fiber.App, cfg config.ConfigProvider) {
+	if !cfg.GetBool("debug.config_endpoint") || !isDebugEnvironment(cfg.GetString("app.env")) {
+		return
+	}
+
+	app.Get("/debug/config", middlewares.NewHTTPInternalAuthMiddleware(loadInternalAuthOptions(cfg)), func(c fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(redactSettings(cfg.AllSettings()))
+	})
+}
+
+type pprofRoutesIn struct {
+	fx.In
+
+	Admin  *adminServer
+	Config config.ConfigProvider
+	// Public is requested only so pprof is mounted after the global middleware stack.
+	Public fiber.Router `name:"api_public"`
+}
+
+func registerPprofRoutes(in pprofRoutesIn) {
+	if !in.Config.GetBool("debug.pprof.enabled") {
+		return
+	}
+
+	in.Admin.App.Use("/debug/pprof", middlewares.NewHTTPInternalAuthMiddleware(loadInternalAuthOptions(in.Config)), pprof.New())
+}
+
+func isDebugEnvironment(env string) bool {
+	return slices.Contains(debugEnvironments, strings.TrimSpace(strings.ToLower(env)))
+}
+
+func redactSettings(settings map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if isSensitiveKey(key) {
+			redacted[key] = redactedValue
+			continue
+		}
+		redacted[key] = redactValue(value)
+	}
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return redactSettings(typed)
+	case []interface{}:
+		items := make([]interface{}, len(typed))
+		for i, item := range typed {
+			items[i] = redactValue(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, hint := range sensitiveKeyHints {
+		if strings.Contains(key, hint) {
+			return true
+		}
+	}
+	return false
+}
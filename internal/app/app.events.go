@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	sharedevents "github.com/joshuarp/withdraw-api/internal/shared/events"
+	"go.uber.org/fx"
+)
+
+const defaultEventsSubjectPrefix = "wallet"
+
+// provideEventPublisher selects the broker for the outbox relay and for deposit.completed
+// events. events.publisher defaults to "none", which drops events.
+func provideEventPublisher(lifecycle fx.Lifecycle, cfg config.ConfigProvider, logger *slog.Logger) (sharedevents.Publisher, error) {
+	switch publisher := strings.TrimSpace(strings.ToLower(cfg.GetString("events.publisher"))); publisher {
+	case "", "none":
+		return sharedevents.NopPublisher{}, nil
+	case "nats":
+		subjectPrefix := defaultEventsSubjectPrefix
+		if cfg.IsSet("events.nats.subject_prefix") {
+			subjectPrefix = cfg.GetString("events.nats.subject_prefix")
+		}
+
+		natsPublisher, err := sharedevents.NewNATSPublisher(sharedevents.NATSConfig{
+			URL:            cfg.GetString("events.nats.url"),
+			SubjectPrefix:  subjectPrefix,
+			ConnectTimeout: cfg.GetDuration("events.nats.connect_timeout"),
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("app: invalid events config: %w", err)
+		}
+
+		lifecycle.Append(fx.Hook{
+			OnStop: func(_ context.Context) error {
+				if err := natsPublisher.Close(); err != nil {
+					return fmt.Errorf("app: failed to close event publisher: %w", err)
+				}
+				return nil
+			},
+		})
+
+		return natsPublisher, nil
+	default:
+		return nil, fmt.Errorf("app: unknown events publisher %q (expected none|nats)", publisher)
+	}
+}
+
+func provideOutboxRelay(cfg config.ConfigProvider, store sharedevents.OutboxStore, publisher sharedevents.Publisher, logger *slog.Logger) *sharedevents.OutboxRelay {
+	return sharedevents.NewOutboxRelay(store, publisher, sharedevents.OutboxRelayConfig{
+		PollInterval: cfg.GetDuration("events.outbox.poll_interval"),
+		BatchSize:    cfg.GetInt("events.outbox.batch_size"),
+		ClaimLease:   cfg.GetDuration("events.outbox.claim_lease"),
+	}, logger)
+}
+
+// registerOutboxRelay runs the relay in the background for the lifetime of the app. On stop
+// it waits for the batch in flight; unfinished rows are picked up again after their lease.
+func registerOutboxRelay(lifecycle fx.Lifecycle, relay *sharedevents.OutboxRelay) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				relay.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return fmt.Errorf("app: outbox relay did not stop: %w", stopCtx.Err())
+			}
+		},
+	})
+}
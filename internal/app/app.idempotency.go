@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	sharedidempotency "github.com/joshuarp/withdraw-api/internal/shared/idempotency"
+	"go.uber.org/fx"
+)
+
+const (
+	defaultDeadLetterReconcileInterval = 5 * time.Second
+	defaultDeadLetterReconcileBatch    = 100
+)
+
+// registerIdempotencyReconciler completes dead-lettered idempotency responses in the
+// background. Keep idempotency.dead_letter.reconcile_interval well under the 30s key lock:
+// a retry after the lock expires would run the request again instead of replaying it.
+func registerIdempotencyReconciler(lifecycle fx.Lifecycle, cfg config.ConfigProvider, queue sharedidempotency.DeadLetterQueue, logger *slog.Logger) {
+	interval := cfg.GetDuration("idempotency.dead_letter.reconcile_interval")
+	if interval <= 0 {
+		interval = defaultDeadLetterReconcileInterval
+	}
+	batchSize := cfg.GetInt("idempotency.dead_letter.batch_size")
+	if batchSize <= 0 {
+		batchSize = defaultDeadLetterReconcileBatch
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				for {
+					resolved, err := queue.Reconcile(ctx, batchSize)
+					if err != nil && ctx.Err() == nil && logger != nil {
+						logger.WarnContext(ctx, "idempotency dead letter reconcile failed", "error", err)
+					}
+					if resolved > 0 && logger != nil {
+						logger.InfoContext(ctx, "idempotency dead letters reconciled", "resolved", resolved)
+					}
+
+					if err == nil && resolved == batchSize && ctx.Err() == nil {
+						continue
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(interval):
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return fmt.Errorf("app: idempotency reconciler did not stop: %w", stopCtx.Err())
+			}
+		},
+	})
+}
@@ -2,39 +2,74 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
 	"github.com/joshuarp/withdraw-api/internal/shared/config"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
 )
 
+const (
+	defaultShutdownTimeout = 15 * time.Second
+	// stopTimeoutMargin leaves room after the HTTP drain for the hooks that run once it
+	// returns: closing DB and Redis pools, flushing spans, stopping background loops.
+	stopTimeoutMargin = 10 * time.Second
+)
+
+func loadShutdownTimeout(cfg config.ConfigProvider) time.Duration {
+	shutdownTimeout := cfg.GetDuration("server.shutdown_timeout")
+	if shutdownTimeout <= 0 {
+		return defaultShutdownTimeout
+	}
+	return shutdownTimeout
+}
+
+// appStopTimeout bounds fx's whole OnStop sequence, which must outlast the HTTP drain.
+func appStopTimeout(cfg config.ConfigProvider) time.Duration {
+	return loadShutdownTimeout(cfg) + stopTimeoutMargin
+}
+
 func registerLifecycle(
 	lifecycle fx.Lifecycle,
 	app *fiber.App,
 	cfg config.ConfigProvider,
 	logger *slog.Logger,
-	dbs lifecycleDatabasesIn,
+	inFlight *middlewares.InFlightTracker,
 ) {
 	port := cfg.GetInt("server.port")
 	if port == 0 {
 		port = 8080
 	}
 	address := fmt.Sprintf(":%d", port)
+	tlsSettings := loadServerTLSSettings(cfg)
+	shutdownTimeout := loadShutdownTimeout(cfg)
 	var serveErrCh chan error
 
 	lifecycle.Append(fx.Hook{
 		OnStart: func(_ context.Context) error {
+			tlsConfig, err := tlsSettings.load()
+			if err != nil {
+				return err
+			}
+
 			listener, err := net.Listen("tcp", address)
 			if err != nil {
 				return fmt.Errorf("app: failed to bind server address %s: %w", address, err)
 			}
 
+			if tlsConfig != nil {
+				listener = tls.NewListener(listener, tlsConfig)
+			}
+
 			serveErrCh = make(chan error, 1)
 			go func() {
 				err := app.Listener(listener)
@@ -44,14 +79,34 @@ func registerLifecycle(
 				serveErrCh <- err
 			}()
 
-			logger.Info("fiber server started", "address", address)
+			logger.Info("fiber server started", "address", address, "tls", tlsConfig != nil)
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
 			var shutdownErrors []error
 
-			if err := app.ShutdownWithContext(ctx); err != nil {
+			drainCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+			defer cancel()
+
+			// Sampled just before the listener closes; nothing new is accepted after that.
+			inFlightAtStart := inFlight.Count()
+			logger.Info("fiber server shutdown started",
+				"in_flight", inFlightAtStart,
+				"timeout", shutdownTimeout.String(),
+			)
+
+			if err := app.ShutdownWithContext(drainCtx); err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					remaining := inFlight.Count()
+					logger.Warn("fiber server shutdown timed out before draining",
+						"timeout", shutdownTimeout.String(),
+						"in_flight", remaining,
+						"drained", max(inFlightAtStart-remaining, 0),
+					)
+				}
 				shutdownErrors = append(shutdownErrors, err)
+			} else {
+				logger.Info("fiber server drained in-flight requests", "drained", inFlightAtStart)
 			}
 
 			if serveErrCh != nil {
@@ -60,12 +115,30 @@ func registerLifecycle(
 					if err != nil && !errors.Is(err, net.ErrClosed) {
 						shutdownErrors = append(shutdownErrors, err)
 					}
-				case <-ctx.Done():
-					shutdownErrors = append(shutdownErrors, ctx.Err())
+				case <-drainCtx.Done():
+					shutdownErrors = append(shutdownErrors, drainCtx.Err())
 				}
 			}
 
-			closed := make(map[*sqlx.DB]struct{}, 2)
+			if len(shutdownErrors) > 0 {
+				return errors.Join(shutdownErrors...)
+			}
+
+			logger.Info("fiber server shutdown completed")
+			return nil
+		},
+	})
+}
+
+// registerConnectionClose closes the DB and Redis pools. CoreModule invokes it before any
+// module registers its hooks, and fx stops hooks in reverse order, so the pools close only
+// after the HTTP drain and every background loop (outbox relay, reconcilers) has stopped.
+func registerConnectionClose(lifecycle fx.Lifecycle, dbs lifecycleDatabasesIn) {
+	lifecycle.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			var closeErrors []error
+
+			closed := make(map[*sqlx.DB]struct{}, 3)
 			closeDB := func(db *sqlx.DB) {
 				if db == nil {
 					return
@@ -75,25 +148,21 @@ func registerLifecycle(
 				}
 				closed[db] = struct{}{}
 				if err := db.Close(); err != nil {
-					shutdownErrors = append(shutdownErrors, err)
+					closeErrors = append(closeErrors, err)
 				}
 			}
 
 			closeDB(dbs.AuthDB)
 			closeDB(dbs.WalletDB)
+			closeDB(dbs.WalletReplicaDB)
 
 			if dbs.Redis != nil {
 				if err := dbs.Redis.Close(); err != nil {
-					shutdownErrors = append(shutdownErrors, err)
+					closeErrors = append(closeErrors, err)
 				}
 			}
 
-			if len(shutdownErrors) > 0 {
-				return errors.Join(shutdownErrors...)
-			}
-
-			logger.Info("fiber server shutdown completed")
-			return nil
+			return errors.Join(closeErrors...)
 		},
 	})
 }
@@ -101,7 +170,58 @@ func registerLifecycle(
 type lifecycleDatabasesIn struct {
 	fx.In
 
-	AuthDB   *sqlx.DB      `name:"db_auth" optional:"true"`
-	WalletDB *sqlx.DB      `name:"db_wallet" optional:"true"`
-	Redis    *redis.Client `optional:"true"`
+	AuthDB          *sqlx.DB      `name:"db_auth" optional:"true"`
+	WalletDB        *sqlx.DB      `name:"db_wallet" optional:"true"`
+	WalletReplicaDB *sqlx.DB      `name:"db_wallet_replica" optional:"true"`
+	Redis           *redis.Client `optional:"true"`
+}
+
+type serverTLSSettings struct {
+	CertFile   string
+	KeyFile    string
+	MinVersion string
+}
+
+func loadServerTLSSettings(cfg config.ConfigProvider) serverTLSSettings {
+	return serverTLSSettings{
+		CertFile:   strings.TrimSpace(cfg.GetString("server.tls.cert_file")),
+		KeyFile:    strings.TrimSpace(cfg.GetString("server.tls.key_file")),
+		MinVersion: strings.TrimSpace(cfg.GetString("server.tls.min_version")),
+	}
+}
+
+func (s serverTLSSettings) load() (*tls.Config, error) {
+	if s.CertFile == "" && s.KeyFile == "" {
+		return nil, nil
+	}
+
+	if s.CertFile == "" || s.KeyFile == "" {
+		return nil, fmt.Errorf("app: server.tls.cert_file and server.tls.key_file must be set together")
+	}
+
+	minVersion, err := parseTLSVersion(s.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("app: failed to load tls certificate: %w", err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   minVersion,
+	}, nil
+}
+
+func parseTLSVersion(raw string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(raw), "tls") {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("app: unsupported server.tls.min_version %q", raw)
+	}
 }
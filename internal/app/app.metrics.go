@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/adaptor"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var defaultMetricsAllowlist = []string{"127.0.0.1", "::1"}
+
+func provideMetricsRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+func provideInFlightTracker(registry *prometheus.Registry) (*middlewares.InFlightTracker, error) {
+	return middlewares.NewInFlightTracker(registry)
+}
+
+func registerMetricsRoute(app *fiber.App, cfg config.ConfigProvider, registry *prometheus.Registry) error {
+	allowlist := cfg.GetStringSlice("metrics.access.allowlist")
+	if len(allowlist) == 0 {
+		allowlist = defaultMetricsAllowlist
+	}
+
+	access, err := middlewares.NewHTTPMetricsAccessMiddleware(middlewares.MetricsAccessOptions{
+		Mode:         strings.TrimSpace(cfg.GetString("metrics.access.mode")),
+		Allowlist:    allowlist,
+		InternalAuth: loadInternalAuthOptions(cfg),
+	})
+	if err != nil {
+		return fmt.Errorf("app: failed to init metrics access: %w", err)
+	}
+
+	app.Get("/metrics", access, adaptor.HTTPHandler(promhttp.HandlerFor(registry, promhttp.HandlerOpts{})))
+	return nil
+}
+
+func loadInternalAuthOptions(cfg config.ConfigProvider) middlewares.InternalAuthOptions {
+	return middlewares.InternalAuthOptions{
+		Secret:    []byte(cfg.GetString("security.internal_auth.secret")),
+		MaxAge:    cfg.GetDuration("security.internal_auth.max_age"),
+		ClockSkew: cfg.GetDuration("security.internal_auth.clock_skew"),
+	}
+}
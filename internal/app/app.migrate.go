@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	dbfiles "github.com/joshuarp/withdraw-api/db"
+	sharedlog "github.com/joshuarp/withdraw-api/internal/shared/log"
+	sharedmigration "github.com/joshuarp/withdraw-api/internal/shared/migration"
+	"go.uber.org/fx"
+)
+
+type migrationTarget struct {
+	Module string
+	Dir    string // relative to the embedded db/ tree
+}
+
+type migrationDatabasesIn struct {
+	fx.In
+	AuthDB   *sqlx.DB `name:"db_auth" optional:"true"`
+	WalletDB *sqlx.DB `name:"db_wallet" optional:"true"`
+}
+
+// Migrate runs a migration action for the databases used by bin and returns
+// without starting the HTTP server.
+func Migrate(bin, action string) error {
+	parsedAction, err := sharedmigration.ParseAction(action)
+	if err != nil {
+		return err
+	}
+
+	normalizedBin := strings.TrimSpace(strings.ToLower(bin))
+	migrationApp := fx.New(
+		fx.NopLogger,
+		fx.Supply(
+			fx.Annotate(
+				normalizedBin,
+				fx.ResultTags(`name:"bin"`),
+			),
+		),
+		fx.Provide(provideConfig, sharedlog.NewLogger),
+		fx.Provide(migrationDatabaseProviders(normalizedBin)...),
+		fx.Invoke(func(lifecycle fx.Lifecycle, logger *slog.Logger, dbs migrationDatabasesIn) {
+			registerMigrationLifecycle(lifecycle, logger, dbs, normalizedBin, parsedAction)
+		}),
+	)
+	if err := migrationApp.Err(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	startErr := migrationApp.Start(ctx)
+	stopErr := migrationApp.Stop(ctx)
+	return errors.Join(startErr, stopErr)
+}
+
+func migrationDatabaseProviders(bin string) []any {
+	authDB := fx.Annotate(provideAuthPostgresSQLX, fx.ResultTags(`name:"db_auth"`))
+	walletDB := fx.Annotate(provideWalletPostgresSQLX, fx.ResultTags(`name:"db_wallet"`))
+
+	switch bin {
+	case "inquiry", "inqury":
+		return []any{authDB, walletDB}
+	default:
+		return []any{walletDB}
+	}
+}
+
+func migrationTargets(bin string) []migrationTarget {
+	switch bin {
+	case "inquiry", "inqury":
+		return []migrationTarget{
+			{Module: "auth", Dir: "migrations/auth"},
+			{Module: "wallet", Dir: "migrations/wallet"},
+		}
+	case "withdraw":
+		return []migrationTarget{{Module: "wallet", Dir: "migrations/wallet"}}
+	default:
+		return []migrationTarget{{Module: "wallet", Dir: "migrations"}}
+	}
+}
+
+func registerMigrationLifecycle(
+	lifecycle fx.Lifecycle,
+	logger *slog.Logger,
+	dbs migrationDatabasesIn,
+	bin string,
+	action sharedmigration.Action,
+) {
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			for _, target := range migrationTargets(bin) {
+				db := dbs.WalletDB
+				if target.Module == "auth" {
+					db = dbs.AuthDB
+				}
+
+				migrationsFS, err := fs.Sub(dbfiles.Migrations, target.Dir)
+				if err != nil {
+					return fmt.Errorf("migrate(%s): %w", target.Module, err)
+				}
+
+				runner, err := sharedmigration.NewRunner(db, migrationsFS)
+				if err != nil {
+					return fmt.Errorf("migrate(%s): %w", target.Module, err)
+				}
+
+				if err := runMigrationAction(ctx, logger, runner, target, action); err != nil {
+					return fmt.Errorf("migrate(%s): %w", target.Module, err)
+				}
+			}
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			var closeErrors []error
+			for _, db := range []*sqlx.DB{dbs.AuthDB, dbs.WalletDB} {
+				if db == nil {
+					continue
+				}
+				if err := db.Close(); err != nil {
+					closeErrors = append(closeErrors, err)
+				}
+			}
+			return errors.Join(closeErrors...)
+		},
+	})
+}
+
+func runMigrationAction(
+	ctx context.Context,
+	logger *slog.Logger,
+	runner *sharedmigration.Runner,
+	target migrationTarget,
+	action sharedmigration.Action,
+) error {
+	switch action {
+	case sharedmigration.ActionUp:
+		executed, err := runner.Up(ctx)
+		for _, migration := range executed {
+			logger.Info("migration applied", "module", target.Module, "version", migration.Version, "name", migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		logger.Info("migrations up to date", "module", target.Module, "applied", len(executed))
+	case sharedmigration.ActionDown:
+		migration, rolledBack, err := runner.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if !rolledBack {
+			logger.Info("no migration to roll back", "module", target.Module)
+			return nil
+		}
+		logger.Info("migration rolled back", "module", target.Module, "version", migration.Version, "name", migration.Name)
+	case sharedmigration.ActionStatus:
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			logger.Info("migration status", "module", target.Module, "version", status.Version, "name", status.Name, "applied", status.Applied)
+		}
+	}
+
+	return nil
+}
@@ -1,12 +1,23 @@
 package app
 
 import (
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
 	"github.com/joshuarp/withdraw-api/internal/handlers"
 	"github.com/joshuarp/withdraw-api/internal/repository"
 	"github.com/joshuarp/withdraw-api/internal/services"
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	sharedlockout "github.com/joshuarp/withdraw-api/internal/shared/lockout"
+	sharedpassword "github.com/joshuarp/withdraw-api/internal/shared/password"
+	"github.com/joshuarp/withdraw-api/internal/shared/uid"
 	"go.uber.org/fx"
 )
 
+const defaultLoginLockoutCooldown = 15 * time.Minute
+
 func AuthModule() fx.Option {
 	return fx.Module("auth",
 		fx.Provide(
@@ -15,12 +26,106 @@ func AuthModule() fx.Option {
 				fx.ParamTags(`name:"db_auth"`),
 				fx.As(new(services.AuthLoginRepository)),
 			),
+			provideLoginLockoutTracker,
 			fx.Annotate(
 				services.NewAuthLoginService,
 				fx.As(new(handlers.AuthLoginService)),
 			),
 			handlers.NewAuthLoginHandler,
+			providePasswordValidator,
+			fx.Annotate(
+				repository.NewAuthRegisterRepository,
+				fx.ParamTags(`name:"db_auth"`),
+				fx.As(new(services.AuthRegisterRepository)),
+			),
+			// Registration opens the wallet itself, so the auth binary needs its own wallet
+			// repository; naming it keeps it apart from WalletModule's in the all-in-one binary.
+			fx.Annotate(
+				repository.NewWalletCreateRepository,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.ResultTags(`name:"registration_wallet_repository"`),
+				fx.As(new(services.WalletCreateRepository)),
+			),
+			fx.Annotate(
+				uid.NewUUIDv7,
+				fx.ResultTags(`name:"registration_id_generator"`),
+			),
+			fx.Annotate(
+				services.NewAuthRegisterService,
+				fx.ParamTags(``, `name:"registration_wallet_repository"`, ``, ``, `name:"registration_id_generator"`),
+				fx.As(new(handlers.AuthRegisterService)),
+			),
+			handlers.NewAuthRegisterHandler,
+			fx.Annotate(
+				repository.NewAuthChangePasswordRepository,
+				fx.ParamTags(`name:"db_auth"`),
+				fx.As(new(services.AuthChangePasswordRepository)),
+			),
+			fx.Annotate(
+				services.NewAuthChangePasswordService,
+				fx.As(new(handlers.AuthChangePasswordService)),
+			),
+			handlers.NewAuthChangePasswordHandler,
+			handlers.NewAuthMeHandler,
+			fx.Annotate(
+				provideAuthRateLimiter,
+				fx.ResultTags(`name:"auth_rate_limiter"`),
+			),
+		),
+		fx.Invoke(
+			fx.Annotate(
+				registerRateLimiterShutdown,
+				fx.ParamTags(``, `name:"auth_rate_limiter"`),
+			),
+			registerAuthRoutes,
 		),
-		fx.Invoke(registerAuthRoutes),
 	)
 }
+
+func provideLoginLockoutTracker(cfg config.ConfigProvider, redisClient *redis.Client) sharedlockout.Tracker {
+	threshold := cfg.GetInt("security.login_lockout.threshold")
+	if threshold <= 0 || redisClient == nil {
+		return nil
+	}
+
+	cooldown := cfg.GetDuration("security.login_lockout.cooldown")
+	if cooldown <= 0 {
+		cooldown = defaultLoginLockoutCooldown
+	}
+
+	return sharedlockout.NewRedisTracker(redisClient, "login_lockout", sharedlockout.Config{
+		Threshold: int64(threshold),
+		Cooldown:  cooldown,
+	})
+}
+
+// providePasswordValidator builds the policy applied to new passwords from
+// security.password_policy. Character class requirements default to on; the denylist file
+// is optional.
+func providePasswordValidator(cfg config.ConfigProvider) (*services.PasswordValidator, error) {
+	policy := sharedpassword.DefaultPolicy()
+	if minLength := cfg.GetInt("security.password_policy.min_length"); minLength > 0 {
+		policy.MinLength = minLength
+	}
+
+	for key, enabled := range map[string]*bool{
+		"security.password_policy.require_upper":  &policy.RequireUpper,
+		"security.password_policy.require_lower":  &policy.RequireLower,
+		"security.password_policy.require_digit":  &policy.RequireDigit,
+		"security.password_policy.require_symbol": &policy.RequireSymbol,
+	} {
+		if cfg.IsSet(key) {
+			*enabled = cfg.GetBool(key)
+		}
+	}
+
+	if path := strings.TrimSpace(cfg.GetString("security.password_policy.denylist_file")); path != "" {
+		denylist, err := sharedpassword.LoadDenylist(path)
+		if err != nil {
+			return nil, err
+		}
+		policy.Denylist = denylist
+	}
+
+	return services.NewPasswordValidator(policy), nil
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"github.com/joshuarp/withdraw-api/internal/handlers"
+	"github.com/joshuarp/withdraw-api/internal/repository"
+	"github.com/joshuarp/withdraw-api/internal/services"
+	sharedidempotency "github.com/joshuarp/withdraw-api/internal/shared/idempotency"
+	"go.uber.org/fx"
+)
+
+func DepositModule() fx.Option {
+	return fx.Module("deposit",
+		fx.Provide(
+			fx.Annotate(
+				sharedidempotency.NewSQLXStore,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.ResultTags(`name:"deposit_idempotency_store"`),
+				fx.As(new(sharedidempotency.Store)),
+			),
+			fx.Annotate(
+				repository.NewDepositBalanceRepository,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.As(new(services.BalanceDepositRepository)),
+			),
+			fx.Annotate(
+				services.NewDepositBalanceService,
+				fx.As(new(handlers.BalanceDepositService)),
+			),
+			handlers.NewInquiryDepositBalanceHandler,
+		),
+		fx.Invoke(registerDepositRoutes),
+	)
+}
@@ -1,9 +1,13 @@
 package app
 
 import (
+	"github.com/redis/go-redis/v9"
+
 	"github.com/joshuarp/withdraw-api/internal/handlers"
 	"github.com/joshuarp/withdraw-api/internal/repository"
 	"github.com/joshuarp/withdraw-api/internal/services"
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	"github.com/joshuarp/withdraw-api/internal/shared/negcache"
 	"go.uber.org/fx"
 )
 
@@ -12,7 +16,7 @@ func InquiryModule() fx.Option {
 		fx.Provide(
 			fx.Annotate(
 				repository.NewInquiryCheckBalanceRepository,
-				fx.ParamTags(`name:"db_wallet"`),
+				fx.ParamTags(`name:"db_wallet_replica"`),
 				fx.As(new(services.BalanceInquiryRepository)),
 			),
 			fx.Annotate(
@@ -24,3 +28,16 @@ func InquiryModule() fx.Option {
 		fx.Invoke(registerInquiryRoutes),
 	)
 }
+
+// provideWalletNotFoundCache builds the negative cache for balance inquiries of users
+// without a wallet. It lives in Redis, not in the inquiry process, because wallets are
+// opened by the wallet module, which may run in another binary and must clear the entry.
+// A zero inquiry.wallet_not_found_cache_ttl (the default) disables it.
+func provideWalletNotFoundCache(cfg config.ConfigProvider, redisClient *redis.Client) negcache.Cache {
+	ttl := cfg.GetDuration("inquiry.wallet_not_found_cache_ttl")
+	if ttl <= 0 || redisClient == nil {
+		return nil
+	}
+
+	return negcache.NewRedisCache(redisClient, "wallet_not_found", ttl)
+}
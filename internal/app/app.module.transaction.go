@@ -0,0 +1,38 @@
+package app
+
+import (
+	"github.com/joshuarp/withdraw-api/internal/handlers"
+	"github.com/joshuarp/withdraw-api/internal/repository"
+	"github.com/joshuarp/withdraw-api/internal/services"
+	"go.uber.org/fx"
+)
+
+// TransactionExportModule serves a user's ledger history from the wallet replica, streamed
+// as CSV or paged as JSON.
+func TransactionExportModule() fx.Option {
+	return fx.Module("transaction_export",
+		fx.Provide(
+			fx.Annotate(
+				repository.NewTransactionExportRepository,
+				fx.ParamTags(`name:"db_wallet_replica"`),
+				fx.As(new(services.TransactionExportRepository)),
+			),
+			fx.Annotate(
+				services.NewTransactionExportService,
+				fx.As(new(handlers.TransactionExportService)),
+			),
+			handlers.NewTransactionExportHandler,
+			fx.Annotate(
+				repository.NewTransactionHistoryRepository,
+				fx.ParamTags(`name:"db_wallet_replica"`),
+				fx.As(new(services.TransactionHistoryRepository)),
+			),
+			fx.Annotate(
+				services.NewTransactionHistoryService,
+				fx.As(new(handlers.TransactionHistoryService)),
+			),
+			handlers.NewTransactionHistoryHandler,
+		),
+		fx.Invoke(registerTransactionExportRoutes),
+	)
+}
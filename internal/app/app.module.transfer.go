@@ -0,0 +1,35 @@
+package app
+
+import (
+	"github.com/joshuarp/withdraw-api/internal/handlers"
+	"github.com/joshuarp/withdraw-api/internal/repository"
+	"github.com/joshuarp/withdraw-api/internal/services"
+	sharedidempotency "github.com/joshuarp/withdraw-api/internal/shared/idempotency"
+	"go.uber.org/fx"
+)
+
+// TransferModule moves funds between wallets of the same user.
+// It reuses the transaction retry policy provided by WithdrawModule.
+func TransferModule() fx.Option {
+	return fx.Module("transfer",
+		fx.Provide(
+			fx.Annotate(
+				sharedidempotency.NewSQLXStore,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.ResultTags(`name:"transfer_idempotency_store"`),
+				fx.As(new(sharedidempotency.Store)),
+			),
+			fx.Annotate(
+				repository.NewTransferBalanceRepository,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.As(new(services.BalanceTransferRepository)),
+			),
+			fx.Annotate(
+				services.NewTransferService,
+				fx.As(new(handlers.BalanceTransferService)),
+			),
+			handlers.NewTransferBalanceHandler,
+		),
+		fx.Invoke(registerTransferRoutes),
+	)
+}
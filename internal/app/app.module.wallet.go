@@ -0,0 +1,33 @@
+package app
+
+import (
+	"github.com/joshuarp/withdraw-api/internal/handlers"
+	"github.com/joshuarp/withdraw-api/internal/repository"
+	"github.com/joshuarp/withdraw-api/internal/services"
+	"github.com/joshuarp/withdraw-api/internal/shared/uid"
+	"go.uber.org/fx"
+)
+
+// WalletModule lets an authenticated user open their wallet.
+func WalletModule() fx.Option {
+	return fx.Module("wallet",
+		fx.Provide(
+			fx.Annotate(
+				repository.NewWalletCreateRepository,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.As(new(services.WalletCreateRepository)),
+			),
+			fx.Annotate(
+				uid.NewUUIDv7,
+				fx.ResultTags(`name:"wallet_id_generator"`),
+			),
+			fx.Annotate(
+				services.NewWalletCreateService,
+				fx.ParamTags(``, `name:"wallet_id_generator"`, ``),
+				fx.As(new(handlers.WalletCreateService)),
+			),
+			handlers.NewWalletCreateHandler,
+		),
+		fx.Invoke(registerWalletRoutes),
+	)
+}
@@ -1,13 +1,27 @@
 package app
 
 import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
 	"github.com/joshuarp/withdraw-api/internal/handlers"
 	"github.com/joshuarp/withdraw-api/internal/repository"
 	"github.com/joshuarp/withdraw-api/internal/services"
+	sharedaudit "github.com/joshuarp/withdraw-api/internal/shared/audit"
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	sharedevents "github.com/joshuarp/withdraw-api/internal/shared/events"
 	sharedidempotency "github.com/joshuarp/withdraw-api/internal/shared/idempotency"
+	"github.com/joshuarp/withdraw-api/internal/shared/payout"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/fx"
 )
 
+const defaultWithdrawTxMaxRetries = 3
+
 func WithdrawModule() fx.Option {
 	return fx.Module("withdraw",
 		fx.Provide(
@@ -15,12 +29,22 @@ func WithdrawModule() fx.Option {
 				provideWithdrawRateLimiter,
 				fx.ResultTags(`name:"withdraw_rate_limiter"`),
 			),
+			fx.Annotate(
+				provideWithdrawConcurrencyLimiter,
+				fx.ResultTags(`name:"withdraw_concurrency_limiter"`),
+			),
 			fx.Annotate(
 				sharedidempotency.NewSQLXStore,
 				fx.ParamTags(`name:"db_wallet"`),
 				fx.ResultTags(`name:"withdraw_idempotency_store"`),
 				fx.As(new(sharedidempotency.Store)),
 			),
+			fx.Annotate(
+				sharedidempotency.NewSQLXStore,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.ResultTags(`name:"withdraw_idempotency_dead_letter"`),
+				fx.As(new(sharedidempotency.DeadLetterQueue)),
+			),
 			fx.Annotate(
 				repository.NewWithdrawBalanceRepository,
 				fx.ParamTags(`name:"db_wallet"`),
@@ -28,10 +52,222 @@ func WithdrawModule() fx.Option {
 			),
 			fx.Annotate(
 				services.NewInquiryWithdrawBalanceService,
-				fx.As(new(handlers.BalanceWithdrawService)),
+				fx.ParamTags(``, ``, ``, ``, ``, `name:"withdraw_auditor"`, ``),
+				fx.As(new(handlers.BalanceWithdrawService), new(handlers.WithdrawalStatusService), new(payoutReconciler)),
+			),
+			fx.Annotate(
+				provideWithdrawAuditor,
+				fx.ParamTags(``, ``, `name:"db_wallet"`),
+				fx.ResultTags(`name:"withdraw_auditor"`),
+			),
+			fx.Annotate(
+				repository.NewOutboxRepository,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.As(new(sharedevents.OutboxStore)),
 			),
+			provideOutboxRelay,
+			provideWithdrawChainIDPolicy,
+			provideWithdrawBlackoutSchedule,
+			provideWithdrawAmountLimits,
+			provideWithdrawFeeCalculator,
+			provideWithdrawTxRetryPolicy,
+			providePayoutClient,
 			handlers.NewInquiryWithdrawBalanceHandler,
+			handlers.NewWithdrawalStatusHandler,
+			fx.Annotate(
+				repository.NewWalletAdjustBalanceRepository,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.As(new(services.WalletAdjustBalanceRepository)),
+			),
+			fx.Annotate(
+				services.NewWalletAdjustBalanceService,
+				fx.As(new(handlers.WalletAdjustBalanceService)),
+			),
+			handlers.NewWalletAdjustBalanceHandler,
+			fx.Annotate(
+				repository.NewWalletStatusRepository,
+				fx.ParamTags(`name:"db_wallet"`),
+				fx.As(new(services.WalletStatusRepository)),
+			),
+			fx.Annotate(
+				services.NewWalletStatusService,
+				fx.As(new(handlers.WalletStatusService)),
+			),
+			handlers.NewWalletStatusHandler,
+			fx.Annotate(
+				provideRateLimitResetHandler,
+				fx.ParamTags(`name:"withdraw_rate_limiter"`),
+			),
+		),
+		fx.Invoke(
+			registerRedisStartupCheck,
+			registerOutboxRelay,
+			fx.Annotate(
+				registerIdempotencyReconciler,
+				fx.ParamTags(``, ``, `name:"withdraw_idempotency_dead_letter"`),
+			),
+			registerPayoutReconciler,
+			fx.Annotate(
+				registerRateLimiterShutdown,
+				fx.ParamTags(``, `name:"withdraw_rate_limiter"`),
+			),
+			fx.Annotate(
+				registerWithdrawRateLimiterReload,
+				fx.ParamTags(``, ``, `name:"withdraw_rate_limiter"`),
+			),
+			registerWithdrawRoutes,
+			registerWalletAdjustRoutes,
+			registerRateLimitResetRoutes,
 		),
-		fx.Invoke(registerWithdrawRoutes),
 	)
 }
+
+func provideWithdrawChainIDPolicy(cfg config.ConfigProvider) handlers.ChainIDPolicy {
+	return handlers.ChainIDPolicy{
+		Required:  cfg.GetBool("withdraw.require_chain_id"),
+		Supported: cfg.GetStringSlice("withdraw.supported_chains"),
+	}
+}
+
+func provideWithdrawAuditor(cfg config.ConfigProvider, logger *slog.Logger, db *sqlx.DB) (sharedaudit.Auditor, error) {
+	switch sink := strings.TrimSpace(strings.ToLower(cfg.GetString("audit.withdraw.sink"))); sink {
+	case "", "log":
+		return sharedaudit.NewSlogAuditor(logger), nil
+	case "db":
+		return sharedaudit.NewSQLXAuditor(db, logger), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("app: unknown withdraw audit sink %q (expected log|db|none)", sink)
+	}
+}
+
+func provideWithdrawAmountLimits(cfg config.ConfigProvider) (services.WithdrawAmountLimits, error) {
+	limits := services.WithdrawAmountLimits{
+		MinAmountMinor:  int64(cfg.GetInt("withdraw.min_amount_minor")),
+		MaxAmountMinor:  int64(cfg.GetInt("withdraw.max_amount_minor")),
+		DailyLimitMinor: int64(cfg.GetInt("limits.daily_withdraw_minor")),
+	}
+
+	if limits.MinAmountMinor < 0 || limits.MaxAmountMinor < 0 || limits.DailyLimitMinor < 0 {
+		return services.WithdrawAmountLimits{}, fmt.Errorf("app: withdraw amount limits must not be negative")
+	}
+
+	if limits.MaxAmountMinor > 0 && limits.MinAmountMinor > limits.MaxAmountMinor {
+		return services.WithdrawAmountLimits{}, fmt.Errorf("app: withdraw min_amount_minor %d exceeds max_amount_minor %d", limits.MinAmountMinor, limits.MaxAmountMinor)
+	}
+
+	return limits, nil
+}
+
+// providePayoutClient returns nil when payout.base_url is unset, keeping withdrawals ledger-only.
+// The HTTP client is wrapped in a circuit breaker unless payout.circuit_breaker.enabled is false.
+func providePayoutClient(cfg config.ConfigProvider, registry *prometheus.Registry) (services.PayoutClient, error) {
+	baseURL := strings.TrimSpace(cfg.GetString("payout.base_url"))
+	if baseURL == "" {
+		return nil, nil
+	}
+
+	client, err := payout.NewHTTPClient(payout.Config{
+		BaseURL:       baseURL,
+		Secret:        cfg.GetString("payout.secret"),
+		Timeout:       cfg.GetDuration("payout.timeout"),
+		MaxRetries:    cfg.GetInt("payout.max_retries"),
+		RetryBackoff:  cfg.GetDuration("payout.retry_backoff"),
+		RatePerSecond: cfg.GetInt("payout.rate_per_second"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("app: invalid payout config: %w", err)
+	}
+
+	if cfg.IsSet("payout.circuit_breaker.enabled") && !cfg.GetBool("payout.circuit_breaker.enabled") {
+		return client, nil
+	}
+
+	transitions := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "payout_circuit_breaker_transitions_total",
+		Help: "Payout circuit breaker state transitions.",
+	}, []string{"from", "to"})
+	breaker := payout.NewCircuitBreaker(client, payout.BreakerConfig{
+		FailureRatio: cfg.GetFloat64("payout.circuit_breaker.failure_ratio"),
+		MinRequests:  cfg.GetInt("payout.circuit_breaker.min_requests"),
+		Window:       cfg.GetDuration("payout.circuit_breaker.window"),
+		OpenTimeout:  cfg.GetDuration("payout.circuit_breaker.open_timeout"),
+		OnStateChange: func(from, to payout.BreakerState) {
+			transitions.WithLabelValues(from.String(), to.String()).Inc()
+		},
+	})
+
+	state := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "payout_circuit_breaker_state",
+		Help: "Payout circuit breaker state: 0 closed, 1 half-open, 2 open.",
+	}, func() float64 { return float64(breaker.State()) })
+	if err := registry.Register(state); err != nil {
+		return nil, fmt.Errorf("app: failed to register payout breaker metrics: %w", err)
+	}
+	if err := registry.Register(transitions); err != nil {
+		return nil, fmt.Errorf("app: failed to register payout breaker metrics: %w", err)
+	}
+
+	return breaker, nil
+}
+
+func provideWithdrawTxRetryPolicy(cfg config.ConfigProvider) repository.TxRetryPolicy {
+	maxRetries := defaultWithdrawTxMaxRetries
+	if cfg.IsSet("database.tx_max_retries") {
+		maxRetries = max(cfg.GetInt("database.tx_max_retries"), 0)
+	}
+
+	return repository.TxRetryPolicy{
+		MaxRetries:  maxRetries,
+		BaseBackoff: cfg.GetDuration("database.tx_retry_backoff"),
+	}
+}
+
+func provideWithdrawFeeCalculator(cfg config.ConfigProvider) (services.FeeCalculator, error) {
+	flatMinor := int64(cfg.GetInt("fees.flat_minor"))
+	percentageBps := int64(cfg.GetInt("fees.percentage_bps"))
+	if flatMinor < 0 || percentageBps < 0 {
+		return nil, fmt.Errorf("app: withdraw fees must not be negative")
+	}
+
+	return services.CombinedFeeCalculator{
+		services.FlatFeeCalculator{FeeMinor: flatMinor},
+		services.PercentageFeeCalculator{BasisPoints: percentageBps},
+	}, nil
+}
+
+func provideWithdrawBlackoutSchedule(cfg config.ConfigProvider) (services.ChainBlackoutSchedule, error) {
+	entries := cfg.GetStringSlice("withdraw.blackout_windows")
+	schedule := make(services.ChainBlackoutSchedule, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		chainID, window, ok := strings.Cut(entry, "=")
+		rawStart, rawEnd, hasRange := strings.Cut(window, "/")
+		if !ok || !hasRange || strings.TrimSpace(chainID) == "" {
+			return nil, fmt.Errorf("app: invalid withdraw blackout window %q: expected chain=start/end", entry)
+		}
+
+		start, err := time.Parse(time.RFC3339, strings.TrimSpace(rawStart))
+		if err != nil {
+			return nil, fmt.Errorf("app: invalid withdraw blackout start %q: %w", rawStart, err)
+		}
+
+		end, err := time.Parse(time.RFC3339, strings.TrimSpace(rawEnd))
+		if err != nil {
+			return nil, fmt.Errorf("app: invalid withdraw blackout end %q: %w", rawEnd, err)
+		}
+
+		if !end.After(start) {
+			return nil, fmt.Errorf("app: invalid withdraw blackout window %q: end must be after start", entry)
+		}
+
+		schedule = append(schedule, services.ChainBlackout{ChainID: strings.TrimSpace(chainID), Start: start, End: end})
+	}
+
+	return schedule, nil
+}
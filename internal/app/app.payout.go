@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	"go.uber.org/fx"
+)
+
+const (
+	defaultPayoutReconcileInterval    = 30 * time.Second
+	defaultPayoutReconcileSettleAfter = 2 * time.Minute
+	defaultPayoutReconcileBatch       = 100
+)
+
+// payoutReconciler settles withdrawals whose payout outcome was lost.
+type payoutReconciler interface {
+	ReconcilePendingWithdrawals(ctx context.Context, settleAfter time.Duration, limit int) (int, error)
+}
+
+// registerPayoutReconciler settles pending withdrawals in the background.
+// payout.reconcile.settle_after must outlast a whole payout call, every retry included
+// (payout.timeout * (payout.max_retries + 1) plus backoff), or a payout still in flight
+// could be looked up before the provider has it and be reversed.
+func registerPayoutReconciler(lifecycle fx.Lifecycle, cfg config.ConfigProvider, reconciler payoutReconciler, logger *slog.Logger) {
+	interval := cfg.GetDuration("payout.reconcile.interval")
+	if interval <= 0 {
+		interval = defaultPayoutReconcileInterval
+	}
+	settleAfter := cfg.GetDuration("payout.reconcile.settle_after")
+	if settleAfter <= 0 {
+		settleAfter = defaultPayoutReconcileSettleAfter
+	}
+	batchSize := cfg.GetInt("payout.reconcile.batch_size")
+	if batchSize <= 0 {
+		batchSize = defaultPayoutReconcileBatch
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				for {
+					settled, err := reconciler.ReconcilePendingWithdrawals(ctx, settleAfter, batchSize)
+					if err != nil && ctx.Err() == nil && logger != nil {
+						logger.WarnContext(ctx, "pending withdrawal reconcile failed", "error", err)
+					}
+					if settled > 0 && logger != nil {
+						logger.InfoContext(ctx, "pending withdrawals settled", "settled", settled)
+					}
+
+					if err == nil && settled == batchSize && ctx.Err() == nil {
+						continue
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(interval):
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return fmt.Errorf("app: payout reconciler did not stop: %w", stopCtx.Err())
+			}
+		},
+	})
+}
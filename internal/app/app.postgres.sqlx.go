@@ -3,13 +3,28 @@ package app
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/repository"
 	"github.com/joshuarp/withdraw-api/internal/shared/config"
 	"go.uber.org/fx"
 )
 
+const (
+	defaultDBMaxOpenConns    = 25
+	defaultDBMaxIdleConns    = 5
+	defaultDBConnMaxLifetime = 30 * time.Minute
+	defaultDBQueryTimeout    = 5 * time.Second
+)
+
+type dbPoolSettings struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
 type dbProviderIn struct {
 	fx.In
 
@@ -25,11 +40,28 @@ func provideWalletPostgresSQLX(in dbProviderIn) (*sqlx.DB, error) {
 	return providePostgresSQLXForModule(in.Config, in.Bin, "wallet")
 }
 
+type walletReplicaIn struct {
+	fx.In
+
+	Config  config.ConfigProvider
+	Bin     string   `name:"bin"`
+	Primary *sqlx.DB `name:"db_wallet"`
+}
+
+func provideWalletReplicaPostgresSQLX(in walletReplicaIn) (*sqlx.DB, error) {
+	dsn, ok := walletReplicaDSN(in.Config, in.Bin)
+	if !ok {
+		return in.Primary, nil
+	}
+
+	useModuleConfig := !isSingleBinaryBin(in.Bin)
+	return openPostgresSQLX(dsn, "wallet_replica", loadDBPoolSettings(in.Config, "wallet", useModuleConfig))
+}
+
 func providePostgresSQLXForModule(cfg config.ConfigProvider, bin, module string) (*sqlx.DB, error) {
 	useModuleConfig := !isSingleBinaryBin(bin)
 
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+	dsn := postgresDSN(
 		moduleDBString(cfg, module, "host", useModuleConfig),
 		moduleDBInt(cfg, module, "port", useModuleConfig),
 		moduleDBString(cfg, module, "user", useModuleConfig),
@@ -38,19 +70,124 @@ func providePostgresSQLXForModule(cfg config.ConfigProvider, bin, module string)
 		moduleDBString(cfg, module, "ssl_mode", useModuleConfig),
 	)
 
+	return openPostgresSQLX(dsn, module, loadDBPoolSettings(cfg, module, useModuleConfig))
+}
+
+func openPostgresSQLX(dsn, label string, pool dbPoolSettings) (*sqlx.DB, error) {
 	db, err := sqlx.Open("pgx", dsn)
 	if err != nil {
-		return nil, fmt.Errorf("db(%s): failed to open postgres connection: %w", module, err)
+		return nil, fmt.Errorf("db(%s): failed to open postgres connection: %w", label, err)
 	}
 
+	applyDBPoolSettings(db, pool)
+
 	if err := db.Ping(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("db(%s): failed to ping postgres: %w", module, err)
+		return nil, fmt.Errorf("db(%s): failed to ping postgres: %w", label, err)
 	}
 
 	return db, nil
 }
 
+func postgresDSN(host string, port int, user, password, name, sslMode string) string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		host, port, user, password, name, sslMode,
+	)
+}
+
+func walletReplicaDSN(cfg config.ConfigProvider, bin string) (string, bool) {
+	useModuleConfig := !isSingleBinaryBin(bin)
+
+	host := strings.TrimSpace(replicaDBString(cfg, "host", useModuleConfig))
+	if host == "" || !isWalletReplicaConfigured(cfg) {
+		return "", false
+	}
+
+	return postgresDSN(
+		host,
+		replicaDBInt(cfg, "port", useModuleConfig),
+		replicaDBString(cfg, "user", useModuleConfig),
+		replicaDBString(cfg, "password", useModuleConfig),
+		replicaDBString(cfg, "name", useModuleConfig),
+		replicaDBString(cfg, "ssl_mode", useModuleConfig),
+	), true
+}
+
+func isWalletReplicaConfigured(cfg config.ConfigProvider) bool {
+	return cfg.IsSet("database.wallet.replica.host") || cfg.IsSet(replicaDBEnvKey("host"))
+}
+
+func replicaDBString(cfg config.ConfigProvider, key string, useModuleConfig bool) string {
+	replicaKey := fmt.Sprintf("database.wallet.replica.%s", key)
+	if cfg.IsSet(replicaKey) {
+		return cfg.GetString(replicaKey)
+	}
+
+	if envKey := replicaDBEnvKey(key); cfg.IsSet(envKey) {
+		return cfg.GetString(envKey)
+	}
+
+	return moduleDBString(cfg, "wallet", key, useModuleConfig)
+}
+
+func replicaDBInt(cfg config.ConfigProvider, key string, useModuleConfig bool) int {
+	replicaKey := fmt.Sprintf("database.wallet.replica.%s", key)
+	if cfg.IsSet(replicaKey) {
+		return cfg.GetInt(replicaKey)
+	}
+
+	if envKey := replicaDBEnvKey(key); cfg.IsSet(envKey) {
+		return cfg.GetInt(envKey)
+	}
+
+	return moduleDBInt(cfg, "wallet", key, useModuleConfig)
+}
+
+func replicaDBEnvKey(key string) string {
+	return moduleDBEnvKey("wallet", "replica_"+key)
+}
+
+func provideQueryTimeout(cfg config.ConfigProvider) repository.QueryTimeout {
+	timeout := cfg.GetDuration("database.query_timeout")
+	if timeout <= 0 {
+		timeout = defaultDBQueryTimeout
+	}
+	return repository.QueryTimeout(timeout)
+}
+
+func loadDBPoolSettings(cfg config.ConfigProvider, module string, useModuleConfig bool) dbPoolSettings {
+	settings := dbPoolSettings{
+		MaxOpenConns:    moduleDBInt(cfg, module, "max_open_conns", useModuleConfig),
+		MaxIdleConns:    moduleDBInt(cfg, module, "max_idle_conns", useModuleConfig),
+		ConnMaxLifetime: moduleDBDuration(cfg, module, "conn_max_lifetime", useModuleConfig),
+	}
+
+	if settings.MaxOpenConns <= 0 {
+		settings.MaxOpenConns = defaultDBMaxOpenConns
+	}
+
+	if settings.MaxIdleConns <= 0 {
+		settings.MaxIdleConns = defaultDBMaxIdleConns
+	}
+
+	if settings.MaxIdleConns > settings.MaxOpenConns {
+		settings.MaxIdleConns = settings.MaxOpenConns
+	}
+
+	if settings.ConnMaxLifetime <= 0 {
+		settings.ConnMaxLifetime = defaultDBConnMaxLifetime
+	}
+
+	return settings
+}
+
+func applyDBPoolSettings(db *sqlx.DB, settings dbPoolSettings) {
+	db.SetMaxOpenConns(settings.MaxOpenConns)
+	db.SetMaxIdleConns(settings.MaxIdleConns)
+	db.SetConnMaxLifetime(settings.ConnMaxLifetime)
+}
+
 func moduleDBString(cfg config.ConfigProvider, module, key string, useModuleConfig bool) string {
 	if useModuleConfig {
 		moduleKey := fmt.Sprintf("database.%s.%s", module, key)
@@ -93,6 +230,27 @@ func moduleDBInt(cfg config.ConfigProvider, module, key string, useModuleConfig
 	return cfg.GetInt(globalDBEnvKey(key))
 }
 
+func moduleDBDuration(cfg config.ConfigProvider, module, key string, useModuleConfig bool) time.Duration {
+	if useModuleConfig {
+		moduleKey := fmt.Sprintf("database.%s.%s", module, key)
+		if cfg.IsSet(moduleKey) {
+			return cfg.GetDuration(moduleKey)
+		}
+
+		moduleEnvKey := moduleDBEnvKey(module, key)
+		if cfg.IsSet(moduleEnvKey) {
+			return cfg.GetDuration(moduleEnvKey)
+		}
+	}
+
+	globalKey := fmt.Sprintf("database.%s", key)
+	if cfg.IsSet(globalKey) {
+		return cfg.GetDuration(globalKey)
+	}
+
+	return cfg.GetDuration(globalDBEnvKey(key))
+}
+
 func isSingleBinaryBin(bin string) bool {
 	normalized := strings.TrimSpace(strings.ToLower(bin))
 	return normalized == "" || normalized == "all"
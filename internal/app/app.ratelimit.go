@@ -8,11 +8,31 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
 
+	"github.com/joshuarp/withdraw-api/internal/handlers"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
 	"github.com/joshuarp/withdraw-api/internal/shared/config"
 	sharedratelimit "github.com/joshuarp/withdraw-api/internal/shared/ratelimit"
 )
 
+const (
+	defaultRedisStartupMaxAttempts = 5
+	defaultRedisStartupBackoff     = 200 * time.Millisecond
+	defaultRedisStartupPingTimeout = time.Second
+)
+
+type redisPinger interface {
+	Ping(ctx context.Context) *redis.StatusCmd
+}
+
+// redisStartupPolicy bounds how long the service waits for Redis before refusing to start.
+type redisStartupPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	PingTimeout time.Duration
+}
+
 func provideRedisClient(cfg config.ConfigProvider) *redis.Client {
 	host := strings.TrimSpace(cfg.GetString("redis.host"))
 	if host == "" {
@@ -31,49 +51,287 @@ func provideRedisClient(cfg config.ConfigProvider) *redis.Client {
 	})
 }
 
+func loadRedisStartupPolicy(cfg config.ConfigProvider) redisStartupPolicy {
+	policy := redisStartupPolicy{
+		MaxAttempts: cfg.GetInt("redis.startup.max_attempts"),
+		Backoff:     cfg.GetDuration("redis.startup.backoff"),
+		PingTimeout: cfg.GetDuration("redis.startup.ping_timeout"),
+	}
+
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaultRedisStartupMaxAttempts
+	}
+	if policy.Backoff <= 0 {
+		policy.Backoff = defaultRedisStartupBackoff
+	}
+	if policy.PingTimeout <= 0 {
+		policy.PingTimeout = defaultRedisStartupPingTimeout
+	}
+
+	return policy
+}
+
+func registerRedisStartupCheck(lifecycle fx.Lifecycle, cfg config.ConfigProvider, redisClient *redis.Client, logger *slog.Logger) {
+	if redisClient == nil {
+		return
+	}
+
+	policy := loadRedisStartupPolicy(cfg)
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return waitForRedis(ctx, redisClient, policy, logger)
+		},
+	})
+}
+
+func waitForRedis(ctx context.Context, pinger redisPinger, policy redisStartupPolicy, logger *slog.Logger) error {
+	backoff := policy.Backoff
+
+	var lastErr error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, policy.PingTimeout)
+		lastErr = pinger.Ping(pingCtx).Err()
+		cancel()
+
+		if lastErr == nil {
+			if attempt > 1 && logger != nil {
+				logger.Info("redis reachable", "attempts", attempt)
+			}
+			return nil
+		}
+
+		if attempt == policy.MaxAttempts {
+			break
+		}
+
+		if logger != nil {
+			logger.Warn("redis ping failed, retrying",
+				"attempt", attempt,
+				"max_attempts", policy.MaxAttempts,
+				"backoff", backoff.String(),
+				"error", lastErr,
+			)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("app: redis startup check aborted: %w", ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+
+	return fmt.Errorf("app: redis unreachable after %d attempts: %w", policy.MaxAttempts, lastErr)
+}
+
+const (
+	defaultWithdrawRateLimit = 20
+	defaultAuthRateLimit     = 10
+)
+
 func provideWithdrawRateLimiter(cfg config.ConfigProvider, redisClient *redis.Client, logger *slog.Logger) (sharedratelimit.Limiter, error) {
+	return provideScopedRateLimiter(cfg, redisClient, logger, "withdraw", defaultWithdrawRateLimit)
+}
+
+// provideAuthRateLimiter limits login attempts per client IP, independently of the
+// per-user withdraw limiter, so credential stuffing is throttled before auth succeeds.
+func provideAuthRateLimiter(cfg config.ConfigProvider, redisClient *redis.Client, logger *slog.Logger) (sharedratelimit.Limiter, error) {
+	return provideScopedRateLimiter(cfg, redisClient, logger, "auth", defaultAuthRateLimit)
+}
+
+// provideScopedRateLimiter builds a Redis-backed limiter from the rate_limit.<scope>.* keys.
+func provideScopedRateLimiter(cfg config.ConfigProvider, redisClient *redis.Client, logger *slog.Logger, scope string, defaultLimit int) (sharedratelimit.Limiter, error) {
 	if redisClient == nil {
-		return nil, fmt.Errorf("app: redis client is required for withdraw rate limiter")
+		return nil, fmt.Errorf("app: redis client is required for %s rate limiter", scope)
 	}
 
-	limit := cfg.GetInt("rate_limit.withdraw.limit")
+	limiterConfig := scopedRateLimiterConfig(cfg, logger, scope, defaultLimit)
+	store := sharedratelimit.NewRedisStore(redisClient, sharedratelimit.WithRedisPrefix("withdraw-api:"+scope))
+
+	if cfg.GetBool("rate_limit.log_startup") {
+		logRateLimiterConfig(logger, scope, limiterConfig)
+	}
+
+	return sharedratelimit.New(store, limiterConfig)
+}
+
+// scopedRateLimiterConfig reads rate_limit.<scope>.*, falling back to defaults for unset or
+// non-positive values.
+func scopedRateLimiterConfig(cfg config.ConfigProvider, logger *slog.Logger, scope string, defaultLimit int) sharedratelimit.Config {
+	keyPrefix := "rate_limit." + scope + "."
+
+	limit := cfg.GetInt(keyPrefix + "limit")
 	if limit <= 0 {
-		limit = 20
+		limit = defaultLimit
 	}
 
-	window := cfg.GetDuration("rate_limit.withdraw.window")
+	window := cfg.GetDuration(keyPrefix + "window")
 	if window <= 0 {
 		window = time.Minute
 	}
 
-	burst := cfg.GetInt("rate_limit.withdraw.burst")
+	burst := cfg.GetInt(keyPrefix + "burst")
 	if burst <= 0 {
 		burst = limit
 	}
 
-	algorithm := parseRateLimitAlgorithm(cfg.GetString("rate_limit.withdraw.algorithm"))
-	store := sharedratelimit.NewRedisStore(redisClient, sharedratelimit.WithRedisPrefix("withdraw-api:withdraw"))
-
-	return sharedratelimit.New(store, sharedratelimit.Config{
-		Algorithm: algorithm,
+	return sharedratelimit.Config{
+		Algorithm: parseRateLimitAlgorithm(cfg.GetString(keyPrefix + "algorithm")),
 		Limit:     int64(limit),
 		Window:    window,
 		Burst:     int64(burst),
 		OnLimited: func(_ context.Context, key string, result sharedratelimit.Result) {
 			if logger != nil {
-				logger.Warn("rate limit exceeded", "scope", "withdraw", "key", key, "limit", result.Limit)
+				logger.Warn("rate limit exceeded", "scope", scope, "key", key, "limit", result.Limit)
 			}
 		},
+	}
+}
+
+// registerWithdrawRateLimiterReload re-applies rate_limit.withdraw.* whenever the config file
+// is reloaded, so limits can be tuned without a restart.
+func registerWithdrawRateLimiterReload(cfg config.ConfigProvider, logger *slog.Logger, limiter sharedratelimit.Limiter) {
+	registerRateLimiterReload(cfg, logger, limiter, "withdraw", defaultWithdrawRateLimit)
+}
+
+func registerRateLimiterReload(cfg config.ConfigProvider, logger *slog.Logger, limiter sharedratelimit.Limiter, scope string, defaultLimit int) {
+	reconfigurable, ok := limiter.(sharedratelimit.Reconfigurable)
+	if !ok {
+		return
+	}
+
+	cfg.OnChange(func() {
+		limiterConfig, err := reloadRateLimiter(cfg, logger, reconfigurable, scope, defaultLimit)
+		if logger == nil {
+			return
+		}
+		if err != nil {
+			logger.Warn("rate limiter reload rejected, keeping previous config", "scope", scope, "error", err)
+			return
+		}
+		logger.Info("rate limiter reloaded",
+			"scope", scope,
+			"algorithm", string(limiterConfig.Algorithm),
+			"limit", limiterConfig.Limit,
+			"window", limiterConfig.Window.String(),
+			"burst", limiterConfig.Burst,
+		)
 	})
 }
 
+// reloadRateLimiter swaps in the current rate_limit.<scope>.* values. Unlike startup, an
+// explicitly set non-positive value or unknown algorithm is rejected instead of defaulted,
+// so a bad live edit cannot silently loosen or disable the limit.
+func reloadRateLimiter(cfg config.ConfigProvider, logger *slog.Logger, limiter sharedratelimit.Reconfigurable, scope string, defaultLimit int) (sharedratelimit.Config, error) {
+	keyPrefix := "rate_limit." + scope + "."
+
+	for _, key := range []string{"limit", "burst"} {
+		if cfg.IsSet(keyPrefix+key) && cfg.GetInt(keyPrefix+key) <= 0 {
+			return sharedratelimit.Config{}, fmt.Errorf("app: %s%s must be positive", keyPrefix, key)
+		}
+	}
+
+	if cfg.IsSet(keyPrefix+"window") && cfg.GetDuration(keyPrefix+"window") <= 0 {
+		return sharedratelimit.Config{}, fmt.Errorf("app: %swindow must be positive", keyPrefix)
+	}
+
+	if algorithm := strings.TrimSpace(strings.ToLower(cfg.GetString(keyPrefix + "algorithm"))); algorithm != "" &&
+		string(parseRateLimitAlgorithm(algorithm)) != algorithm {
+		return sharedratelimit.Config{}, fmt.Errorf("app: unknown %salgorithm %q", keyPrefix, algorithm)
+	}
+
+	limiterConfig := scopedRateLimiterConfig(cfg, logger, scope, defaultLimit)
+	if err := limiter.UpdateConfig(limiterConfig); err != nil {
+		return sharedratelimit.Config{}, err
+	}
+	return limiterConfig, nil
+}
+
+// provideWithdrawConcurrencyLimiter caps simultaneous withdrawals per user so a single user
+// cannot flood the payout provider. It returns nil (no cap) when max_in_flight is unset.
+func provideWithdrawConcurrencyLimiter(cfg config.ConfigProvider, redisClient *redis.Client) (sharedratelimit.ConcurrencyLimiter, error) {
+	maxInFlight := cfg.GetInt("rate_limit.withdraw.max_in_flight")
+	if maxInFlight <= 0 {
+		return nil, nil
+	}
+
+	limiter, err := sharedratelimit.NewRedisConcurrencyLimiter(redisClient, "withdraw-api:withdraw:inflight", sharedratelimit.ConcurrencyConfig{
+		Limit: int64(maxInFlight),
+		TTL:   cfg.GetDuration("rate_limit.withdraw.in_flight_ttl"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("app: invalid withdraw concurrency limit: %w", err)
+	}
+
+	return limiter, nil
+}
+
+// provideRateLimitResetHandler exposes the per-user limiters to the admin reset endpoint.
+// The auth limiter is keyed by IP, so it has no per-user bucket to clear. Whole-scope
+// resets go through the limiter's ratelimit.PrefixResetter.
+func provideRateLimitResetHandler(withdrawLimiter sharedratelimit.Limiter, logger *slog.Logger) (*handlers.RateLimitResetHandler, error) {
+	withdrawResetter, ok := withdrawLimiter.(handlers.RateLimitResetter)
+	if !ok {
+		return nil, fmt.Errorf("app: withdraw rate limiter does not support prefix reset")
+	}
+
+	return handlers.NewRateLimitResetHandler(map[string]handlers.RateLimitResetter{
+		"withdraw": withdrawResetter,
+	}, logger), nil
+}
+
+// registerRateLimiterShutdown closes the limiter when the app stops. The limiter's
+// store borrows the shared Redis client, which registerLifecycle closes separately.
+func registerRateLimiterShutdown(lifecycle fx.Lifecycle, limiter sharedratelimit.Limiter) {
+	if limiter == nil {
+		return
+	}
+
+	lifecycle.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			if err := limiter.Close(); err != nil {
+				return fmt.Errorf("app: failed to close rate limiter: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+func logRateLimiterConfig(logger *slog.Logger, scope string, limiterConfig sharedratelimit.Config) {
+	if logger == nil {
+		return
+	}
+
+	logger.Info("rate limiter configured",
+		"scope", scope,
+		"algorithm", string(limiterConfig.Algorithm),
+		"limit", limiterConfig.Limit,
+		"window", limiterConfig.Window.String(),
+		"burst", limiterConfig.Burst,
+	)
+}
+
 func parseRateLimitAlgorithm(value string) sharedratelimit.Algorithm {
 	switch strings.TrimSpace(strings.ToLower(value)) {
 	case "sliding_window":
 		return sharedratelimit.AlgorithmSlidingWindow
+	case "sliding_window_counter":
+		return sharedratelimit.AlgorithmSlidingWindowCounter
 	case "fixed_window":
 		return sharedratelimit.AlgorithmFixedWindow
 	default:
 		return sharedratelimit.AlgorithmTokenBucket
 	}
 }
+
+// parseRateLimitHeaderStyle falls back to the legacy X-RateLimit-* headers for unknown values.
+func parseRateLimitHeaderStyle(value string) middlewares.RateLimitHeaderStyle {
+	switch style := middlewares.RateLimitHeaderStyle(strings.TrimSpace(strings.ToLower(value))); style {
+	case middlewares.RateLimitHeaderStyleStandard, middlewares.RateLimitHeaderStyleBoth:
+		return style
+	default:
+		return middlewares.RateLimitHeaderStyleLegacy
+	}
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+)
+
+const defaultReadinessTimeout = 2 * time.Second
+
+type readinessCheck struct {
+	Name string
+	Ping func(ctx context.Context) error
+}
+
+type readinessChecks []readinessCheck
+
+type readinessDepsIn struct {
+	fx.In
+
+	AuthDB          *sqlx.DB      `name:"db_auth" optional:"true"`
+	WalletDB        *sqlx.DB      `name:"db_wallet" optional:"true"`
+	WalletReplicaDB *sqlx.DB      `name:"db_wallet_replica" optional:"true"`
+	Redis           *redis.Client `optional:"true"`
+}
+
+// readinessStatus is the last observed state of a single dependency.
+type readinessStatus struct {
+	Status    string    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type readinessReport struct {
+	ready       bool
+	checks      map[string]readinessStatus
+	refreshedAt time.Time
+}
+
+// readinessProbe runs the dependency checks and, when ttl is positive, serves the
+// last report for up to ttl. Once it expires the next request still gets the stale
+// report and starts a background refresh, so probes never wait on slow dependencies
+// except for the very first one.
+type readinessProbe struct {
+	checks  readinessChecks
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	last    *readinessReport
+	pending chan struct{}
+}
+
+func provideReadinessChecks(in readinessDepsIn) readinessChecks {
+	checks := make(readinessChecks, 0, 4)
+	if in.AuthDB != nil {
+		checks = append(checks, readinessCheck{Name: "db_auth", Ping: in.AuthDB.PingContext})
+	}
+	if in.WalletDB != nil {
+		checks = append(checks, readinessCheck{Name: "db_wallet", Ping: in.WalletDB.PingContext})
+	}
+	if in.WalletReplicaDB != nil && in.WalletReplicaDB != in.WalletDB {
+		checks = append(checks, readinessCheck{Name: "db_wallet_replica", Ping: in.WalletReplicaDB.PingContext})
+	}
+	if in.Redis != nil {
+		checks = append(checks, readinessCheck{Name: "redis", Ping: func(ctx context.Context) error {
+			return in.Redis.Ping(ctx).Err()
+		}})
+	}
+	return checks
+}
+
+func registerReadinessRoute(app *fiber.App, cfg config.ConfigProvider, checks readinessChecks) {
+	timeout := cfg.GetDuration("health.readiness_timeout")
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+
+	app.Get("/readyz", newReadinessHandler(checks, timeout, max(cfg.GetDuration("health.cache_ttl"), 0)))
+}
+
+func newReadinessHandler(checks readinessChecks, timeout, ttl time.Duration) fiber.Handler {
+	probe := &readinessProbe{checks: checks, timeout: timeout, ttl: ttl}
+
+	return func(c fiber.Ctx) error {
+		report := probe.report(c.Context())
+		if !report.ready {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"status": "unavailable", "checks": report.checks})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "ok", "checks": report.checks})
+	}
+}
+
+// report returns the cached result while it is younger than ttl. Past that it still
+// returns the stale result and starts one background refresh; callers arriving while it
+// runs keep getting the stale result instead of pinging the dependencies again. Only
+// before the first result exists do callers wait, all on the same run.
+func (p *readinessProbe) report(ctx context.Context) readinessReport {
+	if p.ttl <= 0 {
+		return p.run(ctx)
+	}
+
+	p.mu.Lock()
+	if p.last != nil {
+		report := *p.last
+		if time.Since(report.refreshedAt) >= p.ttl && p.pending == nil {
+			p.refresh()
+		}
+		p.mu.Unlock()
+		return report
+	}
+
+	pending := p.pending
+	if pending == nil {
+		pending = p.refresh()
+	}
+	p.mu.Unlock()
+
+	<-pending
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return *p.last
+}
+
+// refresh runs the checks in the background and stores the result. The caller must
+// hold p.mu; the returned channel closes once the new report is stored.
+func (p *readinessProbe) refresh() chan struct{} {
+	pending := make(chan struct{})
+	p.pending = pending
+
+	go func() {
+		// Detached from any request: the result outlives the probe that triggered it.
+		report := p.run(context.Background())
+
+		p.mu.Lock()
+		p.last = &report
+		p.pending = nil
+		p.mu.Unlock()
+		close(pending)
+	}()
+
+	return pending
+}
+
+func (p *readinessProbe) run(ctx context.Context) readinessReport {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	report := readinessReport{ready: true, checks: make(map[string]readinessStatus, len(p.checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, check := range p.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			status := "ok"
+			if err := check.Ping(ctx); err != nil {
+				status = "down"
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.checks[check.Name] = readinessStatus{Status: status, CheckedAt: time.Now().UTC()}
+			if status != "ok" {
+				report.ready = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	report.refreshedAt = time.Now()
+	return report
+}
@@ -1,14 +1,21 @@
 package app
 
 import (
+	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/joshuarp/withdraw-api/internal/handlers"
 	"github.com/joshuarp/withdraw-api/internal/middlewares"
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
 	sharedidempotency "github.com/joshuarp/withdraw-api/internal/shared/idempotency"
 	sharedjwt "github.com/joshuarp/withdraw-api/internal/shared/jwt"
 	sharedratelimit "github.com/joshuarp/withdraw-api/internal/shared/ratelimit"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 )
 
@@ -16,24 +23,139 @@ type routerGroupsOut struct {
 	fx.Out
 	Public    fiber.Router `name:"api_public"`
 	Protected fiber.Router `name:"api_protected"`
+	// Admin is /api/v1/admin on the admin server, behind the same JWT check as Protected.
+	Admin fiber.Router `name:"api_admin"`
 }
 
 func provideRouterGroups(
 	app *fiber.App,
+	cfg config.ConfigProvider,
 	logger *slog.Logger,
 	tokenManager sharedjwt.TokenManager,
-) routerGroupsOut {
-	app.Use(middlewares.NewHTTPRecoveryMiddleware())
+	tracer trace.Tracer,
+	registry *prometheus.Registry,
+	inFlight *middlewares.InFlightTracker,
+	checks readinessChecks,
+	admin *adminServer,
+) (routerGroupsOut, error) {
+	publicCORS, err := middlewares.NewHTTPCORSMiddleware(loadCORSGroupConfig(cfg, "public"))
+	if err != nil {
+		return routerGroupsOut{}, fmt.Errorf("app: failed to init public cors: %w", err)
+	}
+
+	protectedCORSConfig := loadCORSGroupConfig(cfg, "protected")
+	protectedCORSConfig.Next = isPublicAuthPath
+	protectedCORS, err := middlewares.NewHTTPCORSMiddleware(protectedCORSConfig)
+	if err != nil {
+		return routerGroupsOut{}, fmt.Errorf("app: failed to init protected cors: %w", err)
+	}
+
+	metricsMiddleware, err := middlewares.NewHTTPMetricsMiddleware(registry)
+	if err != nil {
+		return routerGroupsOut{}, fmt.Errorf("app: failed to init http metrics: %w", err)
+	}
+
+	// Outermost, so the count shutdown reports covers every middleware still running.
+	app.Use(middlewares.NewHTTPInFlightMiddleware(inFlight))
+	app.Use(middlewares.NewHTTPRecoveryMiddleware(logger))
 	app.Use(middlewares.NewHTTPRequestIDMiddleware())
-	app.Use(middlewares.NewHTTPCORSMiddleware())
-	app.Use(middlewares.NewHTTPRequestResponseLogMiddleware(logger))
+	// Registered outside the body logger so it logs the uncompressed JSON.
+	if !cfg.IsSet("server.compression.enabled") || cfg.GetBool("server.compression.enabled") {
+		app.Use(middlewares.NewHTTPCompressionMiddleware(middlewares.CompressionConfig{
+			MinSize: cfg.GetInt("server.compression.min_size"),
+		}))
+	}
+	app.Use(metricsMiddleware)
+	app.Use(middlewares.NewHTTPTracingMiddleware(tracer))
+	app.Use(middlewares.NewHTTPRequestResponseLogMiddleware(logger, loadRequestResponseLogConfig(cfg)))
 
 	app.Get("/healthz", func(c fiber.Ctx) error {
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "ok"})
 	})
+	registerReadinessRoute(app, cfg, checks)
+
+	if err := registerMetricsRoute(admin.App, cfg, registry); err != nil {
+		return routerGroupsOut{}, err
+	}
+
+	registerDebugConfigRoute(admin.App, cfg)
+
+	requestTimeout := cfg.GetDuration("server.request_timeout")
+	if requestTimeout <= 0 {
+		requestTimeout = 30 * time.Second
+	}
+
+	jwtBypass, err := loadJWTBypassRules(cfg)
+	if err != nil {
+		return routerGroupsOut{}, err
+	}
+
+	jwtConfig := middlewares.JWTMiddlewareConfig{
+		VerifyTimeout: cfg.GetDuration("security.jwt.verify_timeout"),
+		Bypass:        jwtBypass,
+	}
+	if jwtConfig.VerifyTimeout <= 0 {
+		jwtConfig.VerifyTimeout = defaultJWTVerifyTimeout
+	}
+
+	groups := newAPIRouterGroups(app, requestTimeout, publicCORS, protectedCORS, tokenManager, jwtConfig)
+	groups.Admin = newAdminRouter(admin, groups.Protected, requestTimeout, tokenManager, jwtConfig)
+	return groups, nil
+}
+
+const (
+	apiPrefix        = "/api/v1"
+	publicAuthPrefix = "/auth"
+
+	defaultJWTVerifyTimeout = 2 * time.Second
+)
+
+var jwtBypassMethods = []string{
+	fiber.MethodGet,
+	fiber.MethodHead,
+	fiber.MethodPost,
+	fiber.MethodPut,
+	fiber.MethodPatch,
+	fiber.MethodDelete,
+}
+
+// loadJWTBypassRules reads security.jwt.public_routes, whose entries look like
+// "POST /auth/register". An unset key keeps middlewares.DefaultJWTBypassRules, while
+// an empty list makes every route require a token.
+func loadJWTBypassRules(cfg config.ConfigProvider) ([]middlewares.JWTBypassRule, error) {
+	if !cfg.IsSet("security.jwt.public_routes") {
+		return nil, nil
+	}
 
-	api := app.Group("/api/v1")
-	protected := api.Group("", middlewares.NewHTTPJWTMiddleware(tokenManager))
+	entries := cfg.GetStringSlice("security.jwt.public_routes")
+	rules := make([]middlewares.JWTBypassRule, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		method, suffix, ok := strings.Cut(entry, " ")
+		method = strings.ToUpper(method)
+		suffix = strings.TrimSpace(suffix)
+		if !ok || !slices.Contains(jwtBypassMethods, method) || strings.Trim(suffix, "/") == "" {
+			return nil, fmt.Errorf("app: invalid security.jwt.public_routes entry %q: expected \"METHOD /path\"", entry)
+		}
+
+		rules = append(rules, middlewares.JWTBypassRule{Method: method, PathSuffix: suffix})
+	}
+
+	return rules, nil
+}
+
+// newAPIRouterGroups mounts the public and protected API groups. Both share the /api/v1
+// prefix, so the public CORS policy is scoped to /auth and the protected one skips those
+// paths; each preflight is answered by exactly one policy, ahead of JWT auth.
+func newAPIRouterGroups(app *fiber.App, requestTimeout time.Duration, publicCORS, protectedCORS fiber.Handler, tokenManager sharedjwt.TokenManager, jwtConfig middlewares.JWTMiddlewareConfig) routerGroupsOut {
+	api := app.Group(apiPrefix, middlewares.NewHTTPTimeoutMiddleware(requestTimeout))
+	api.Use(publicAuthPrefix, publicCORS)
+	jwtConfig.PathPrefix = apiPrefix
+	protected := api.Group("", protectedCORS, middlewares.NewHTTPJWTMiddleware(tokenManager, jwtConfig))
 
 	return routerGroupsOut{
 		Public:    api,
@@ -41,14 +163,97 @@ func provideRouterGroups(
 	}
 }
 
+// newAdminRouter mounts /api/v1/admin under protected, or on the separate admin app with
+// its own request timeout and JWT check. Browsers never call the admin port, so it has
+// no CORS policy.
+func newAdminRouter(admin *adminServer, protected fiber.Router, requestTimeout time.Duration, tokenManager sharedjwt.TokenManager, jwtConfig middlewares.JWTMiddlewareConfig) fiber.Router {
+	if !admin.Separate {
+		return protected.Group("/admin")
+	}
+
+	jwtConfig.PathPrefix = apiPrefix
+	return admin.App.Group(apiPrefix+"/admin",
+		middlewares.NewHTTPTimeoutMiddleware(requestTimeout),
+		middlewares.NewHTTPJWTMiddleware(tokenManager, jwtConfig),
+	)
+}
+
+func isPublicAuthPath(c fiber.Ctx) bool {
+	path := c.Path()
+	authPath := apiPrefix + publicAuthPrefix
+	return path == authPath || strings.HasPrefix(path, authPath+"/")
+}
+
+func loadCORSConfig(cfg config.ConfigProvider) middlewares.CORSConfig {
+	return middlewares.CORSConfig{
+		AllowedOrigins:   cfg.GetStringSlice("cors.allowed_origins"),
+		AllowedMethods:   cfg.GetStringSlice("cors.allowed_methods"),
+		AllowCredentials: cfg.GetBool("cors.allow_credentials"),
+		MaxAge:           cfg.GetDuration("cors.max_age"),
+	}
+}
+
+// loadCORSGroupConfig overlays cors.<group>.* on the shared cors.* settings; keys the
+// group does not set are inherited.
+func loadCORSGroupConfig(cfg config.ConfigProvider, group string) middlewares.CORSConfig {
+	corsConfig := loadCORSConfig(cfg)
+	prefix := "cors." + group + "."
+
+	if cfg.IsSet(prefix + "allowed_origins") {
+		corsConfig.AllowedOrigins = cfg.GetStringSlice(prefix + "allowed_origins")
+	}
+	if cfg.IsSet(prefix + "allowed_methods") {
+		corsConfig.AllowedMethods = cfg.GetStringSlice(prefix + "allowed_methods")
+	}
+	if cfg.IsSet(prefix + "allow_credentials") {
+		corsConfig.AllowCredentials = cfg.GetBool(prefix + "allow_credentials")
+	}
+	if cfg.IsSet(prefix + "max_age") {
+		corsConfig.MaxAge = cfg.GetDuration(prefix + "max_age")
+	}
+
+	return corsConfig
+}
+
+func loadRequestResponseLogConfig(cfg config.ConfigProvider) middlewares.RequestResponseLogConfig {
+	return middlewares.RequestResponseLogConfig{
+		CaptureBody:   cfg.GetBool("logging.http_body.enabled"),
+		RedactFields:  cfg.GetStringSlice("logging.http_body.redact_fields"),
+		MaxBodyBytes:  cfg.GetInt("logging.http_body.max_bytes"),
+		SampleRate:    cfg.GetFloat64("logging.http_request.sample_rate"),
+		SlowThreshold: cfg.GetDuration("logging.http_request.slow_threshold"),
+	}
+}
+
 type authRoutesIn struct {
 	fx.In
-	Public  fiber.Router `name:"api_public"`
-	Handler *handlers.AuthLoginHandler
+	Public                fiber.Router            `name:"api_public"`
+	Protected             fiber.Router            `name:"api_protected"`
+	RateLimiter           sharedratelimit.Limiter `name:"auth_rate_limiter"`
+	Config                config.ConfigProvider
+	Logger                *slog.Logger
+	Handler               *handlers.AuthLoginHandler
+	RegisterHandler       *handlers.AuthRegisterHandler
+	ChangePasswordHandler *handlers.AuthChangePasswordHandler
+	MeHandler             *handlers.AuthMeHandler
 }
 
 func registerAuthRoutes(in authRoutesIn) {
+	rateLimitMiddleware := middlewares.NewHTTPRateLimitMiddleware(middlewares.RateLimitConfig{
+		Limiter:           in.RateLimiter,
+		Logger:            in.Logger,
+		KeyExtractor:      middlewares.PerIPKeyExtractor("auth"),
+		HeaderStyle:       parseRateLimitHeaderStyle(in.Config.GetString("rate_limit.header_style")),
+		PreciseRetryAfter: in.Config.GetBool("rate_limit.precise_retry_after"),
+	})
+	// Only the unauthenticated credential endpoints share the per-IP budget; change-password
+	// and /auth/me sit behind a token and must not be throttled by a neighbour's login attempts.
+	in.Public.Use("/auth/login", rateLimitMiddleware)
+	in.Public.Use("/auth/register", rateLimitMiddleware)
 	in.Handler.Register(in.Public)
+	in.RegisterHandler.Register(in.Public)
+	in.ChangePasswordHandler.Register(in.Protected)
+	in.MeHandler.Register(in.Protected)
 }
 
 type inquiryRoutesIn struct {
@@ -61,22 +266,160 @@ func registerInquiryRoutes(in inquiryRoutesIn) {
 	in.Handler.Register(in.Protected)
 }
 
+type transactionExportRoutesIn struct {
+	fx.In
+	Protected      fiber.Router `name:"api_protected"`
+	Handler        *handlers.TransactionExportHandler
+	HistoryHandler *handlers.TransactionHistoryHandler
+}
+
+func registerTransactionExportRoutes(in transactionExportRoutesIn) {
+	in.Handler.Register(in.Protected)
+	in.HistoryHandler.Register(in.Protected)
+}
+
 type withdrawRoutesIn struct {
 	fx.In
-	Protected        fiber.Router            `name:"api_protected"`
-	IdempotencyStore sharedidempotency.Store `name:"withdraw_idempotency_store"`
-	RateLimiter      sharedratelimit.Limiter `name:"withdraw_rate_limiter"`
+	Protected        fiber.Router `name:"api_protected"`
+	Config           config.ConfigProvider
+	IdempotencyStore sharedidempotency.Store            `name:"withdraw_idempotency_store"`
+	DeadLetter       sharedidempotency.DeadLetterQueue  `name:"withdraw_idempotency_dead_letter"`
+	RateLimiter      sharedratelimit.Limiter            `name:"withdraw_rate_limiter"`
+	InFlightLimiter  sharedratelimit.ConcurrencyLimiter `name:"withdraw_concurrency_limiter"`
 	Logger           *slog.Logger
 	Handler          *handlers.InquiryWithdrawBalanceHandler
+	StatusHandler    *handlers.WithdrawalStatusHandler
 }
 
 func registerWithdrawRoutes(in withdrawRoutesIn) {
 	rateLimitMiddleware := middlewares.NewHTTPRateLimitMiddleware(middlewares.RateLimitConfig{
-		Limiter:      in.RateLimiter,
+		Limiter:           in.RateLimiter,
+		Logger:            in.Logger,
+		KeyExtractor:      middlewares.PerUserKeyExtractor("withdraw"),
+		RetryBudget:       int64(in.Config.GetInt("rate_limit.withdraw.retry_budget")),
+		HeaderStyle:       parseRateLimitHeaderStyle(in.Config.GetString("rate_limit.header_style")),
+		PreciseRetryAfter: in.Config.GetBool("rate_limit.precise_retry_after"),
+	})
+
+	// Runs ahead of the idempotency middleware so a rejection is not stored as the key's response.
+	concurrencyMiddleware := middlewares.NewHTTPConcurrencyLimitMiddleware(middlewares.ConcurrencyLimitConfig{
+		Limiter:      in.InFlightLimiter,
 		Logger:       in.Logger,
 		KeyExtractor: middlewares.PerUserKeyExtractor("withdraw"),
 	})
 
-	withdrawRouter := in.Protected.Group("", rateLimitMiddleware, middlewares.NewHTTPWithdrawIdempotencyMiddleware(in.IdempotencyStore))
-	in.Handler.Register(withdrawRouter)
+	// A withdrawal has moved money by the time its response is stored, so a response that
+	// cannot be stored is dead-lettered for registerIdempotencyReconciler.
+	idempotencyOptions := loadIdempotencyOptions(in.Config, "withdraw")
+	idempotencyOptions.DeadLetter = in.DeadLetter
+	idempotencyOptions.Logger = in.Logger
+	idempotencyMiddleware := middlewares.NewHTTPWithdrawIdempotencyMiddleware(in.IdempotencyStore, idempotencyOptions)
+	// Status polls are read-only, so they are registered ahead of the submit middlewares
+	// and never reach their rate limit or idempotency key requirement.
+	in.StatusHandler.Register(in.Protected)
+
+	// Mounted on the path rather than a "" group, which would also wrap every route other
+	// modules register on the protected router afterwards.
+	in.Protected.Use("/withdrawals", rateLimitMiddleware, concurrencyMiddleware, idempotencyMiddleware)
+	in.Handler.Register(in.Protected)
+}
+
+// loadIdempotencyOptions reads idempotency.<scope>.* for one endpoint family. All families
+// share the key header, the idempotency.key policy, idempotency.max_body_bytes and the
+// idempotency.complete_* retry settings; stored keys are namespaced by scope.
+func loadIdempotencyOptions(cfg config.ConfigProvider, scope string) middlewares.IdempotencyOptions {
+	prefix := "idempotency." + scope + "."
+
+	return middlewares.IdempotencyOptions{
+		Scope:        scope,
+		HeaderName:   middlewares.IdempotencyKeyHeader,
+		StatusHeader: cfg.GetBool(prefix + "status_header"),
+		EchoKey:      cfg.GetBool(prefix + "echo_key"),
+		KeyPolicy: middlewares.IdempotencyKeyPolicy{
+			MinLength: cfg.GetInt("idempotency.key.min_length"),
+			MaxLength: cfg.GetInt("idempotency.key.max_length"),
+			Charset:   cfg.GetString("idempotency.key.charset"),
+		},
+		HashHeaders:      cfg.GetStringSlice(prefix + "hash_headers"),
+		MaxBodyBytes:     cfg.GetInt("idempotency.max_body_bytes"),
+		CompleteAttempts: cfg.GetInt("idempotency.complete_attempts"),
+		CompleteBackoff:  cfg.GetDuration("idempotency.complete_backoff"),
+		CompleteTimeout:  cfg.GetDuration("idempotency.complete_timeout"),
+	}
+}
+
+type depositRoutesIn struct {
+	fx.In
+	Protected        fiber.Router `name:"api_protected"`
+	Config           config.ConfigProvider
+	IdempotencyStore sharedidempotency.Store `name:"deposit_idempotency_store"`
+	Handler          *handlers.InquiryDepositBalanceHandler
+}
+
+func registerDepositRoutes(in depositRoutesIn) {
+	opts := loadIdempotencyOptions(in.Config, "deposit")
+	opts.RequireBody = true
+
+	in.Protected.Use("/deposits", middlewares.NewHTTPIdempotencyMiddleware(in.IdempotencyStore, opts))
+	in.Handler.Register(in.Protected)
+}
+
+type walletRoutesIn struct {
+	fx.In
+	Protected fiber.Router `name:"api_protected"`
+	Handler   *handlers.WalletCreateHandler
+}
+
+func registerWalletRoutes(in walletRoutesIn) {
+	in.Handler.Register(in.Protected)
+}
+
+type transferRoutesIn struct {
+	fx.In
+	Protected        fiber.Router `name:"api_protected"`
+	Config           config.ConfigProvider
+	IdempotencyStore sharedidempotency.Store `name:"transfer_idempotency_store"`
+	Handler          *handlers.TransferBalanceHandler
+}
+
+func registerTransferRoutes(in transferRoutesIn) {
+	// The fingerprint covers the raw body, so retrying a key with another
+	// destination_wallet_id is rejected as a conflict instead of replaying the first transfer.
+	opts := loadIdempotencyOptions(in.Config, "transfer")
+	opts.RequireBody = true
+
+	in.Protected.Use("/transfers", middlewares.NewHTTPIdempotencyMiddleware(in.IdempotencyStore, opts))
+	in.Handler.Register(in.Protected)
+}
+
+const walletAdjustScope = "wallet:adjust"
+
+type walletAdjustRoutesIn struct {
+	fx.In
+	Admin         fiber.Router `name:"api_admin"`
+	Handler       *handlers.WalletAdjustBalanceHandler
+	StatusHandler *handlers.WalletStatusHandler
+}
+
+// registerWalletAdjustRoutes mounts the wallet admin routes, balance adjustments and
+// freezes, which all sit behind the wallet:adjust scope.
+func registerWalletAdjustRoutes(in walletAdjustRoutesIn) {
+	// Scope checks are mounted per path: a middleware on the shared admin router would
+	// also guard every other admin route.
+	in.Admin.Use("/wallets", middlewares.NewHTTPRequireScopeMiddleware(walletAdjustScope))
+	in.Handler.Register(in.Admin)
+	in.StatusHandler.Register(in.Admin)
+}
+
+const rateLimitResetScope = "ratelimit:reset"
+
+type rateLimitResetRoutesIn struct {
+	fx.In
+	Admin   fiber.Router `name:"api_admin"`
+	Handler *handlers.RateLimitResetHandler
+}
+
+func registerRateLimitResetRoutes(in rateLimitResetRoutesIn) {
+	in.Admin.Use("/ratelimit", middlewares.NewHTTPRequireScopeMiddleware(rateLimitResetScope))
+	in.Handler.Register(in.Admin)
 }
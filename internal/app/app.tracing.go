@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+const (
+	defaultTracingServiceName = "withdraw-api"
+	defaultTracingSampleRatio = 1.0
+)
+
+// provideTracer returns nil while tracing.enabled is false, which is the default: the
+// middleware and services skip span creation entirely. When enabled, spans are batched to
+// the OTLP/HTTP collector at tracing.otlp.endpoint and flushed on shutdown.
+func provideTracer(lifecycle fx.Lifecycle, cfg config.ConfigProvider) (trace.Tracer, error) {
+	if !cfg.GetBool("tracing.enabled") {
+		return nil, nil
+	}
+
+	serviceName := strings.TrimSpace(cfg.GetString("tracing.service_name"))
+	if serviceName == "" {
+		serviceName = defaultTracingServiceName
+	}
+
+	endpoint := strings.TrimSpace(cfg.GetString("tracing.otlp.endpoint"))
+	if endpoint == "" {
+		return nil, fmt.Errorf("app: tracing.otlp.endpoint is required when tracing is enabled")
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if cfg.GetBool("tracing.otlp.insecure") {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	// The exporter connects lazily on the first export, so an unreachable collector does
+	// not block startup; failed batches are dropped and logged by the SDK.
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("app: create otlp trace exporter: %w", err)
+	}
+
+	sampleRatio := defaultTracingSampleRatio
+	if cfg.IsSet("tracing.sample_ratio") {
+		sampleRatio = cfg.GetFloat64("tracing.sample_ratio")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			if err := provider.Shutdown(ctx); err != nil {
+				return fmt.Errorf("app: shutdown tracer provider: %w", err)
+			}
+			return nil
+		},
+	})
+
+	return provider.Tracer(serviceName), nil
+}
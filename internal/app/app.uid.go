@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+	"github.com/joshuarp/withdraw-api/internal/shared/uid"
+)
+
+const maxSnowflakeNodeID = 1023
+
+// hostname is swapped in tests.
+var hostname = os.Hostname
+
+// provideUIDGenerator builds the generator for transaction reference IDs from uid.strategy
+// (default uuidv7). Wallet and user IDs stay UUIDv7 since their columns are uuid.
+func provideUIDGenerator(cfg config.ConfigProvider) (uid.UIDGenerator, error) {
+	strategy := uid.Strategy(strings.ToLower(strings.TrimSpace(cfg.GetString("uid.strategy"))))
+	if strategy == "" {
+		strategy = uid.StrategyUUIDv7
+	}
+
+	opts := uid.Options{Strategy: strategy}
+	if strategy == uid.StrategySnowflake {
+		nodeID, err := snowflakeNodeID(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts.NodeID = nodeID
+	}
+
+	generator, err := uid.New(opts)
+	if err != nil {
+		return nil, fmt.Errorf("app: failed to init uid generator: %w", err)
+	}
+	return generator, nil
+}
+
+// snowflakeNodeID reads uid.node_id, deriving it from the hostname when the key is empty
+// so replicas sharing one config do not all claim the same node.
+func snowflakeNodeID(cfg config.ConfigProvider) (int64, error) {
+	raw := strings.TrimSpace(cfg.GetString("uid.node_id"))
+	if raw == "" {
+		name, err := hostname()
+		if err != nil {
+			return 0, fmt.Errorf("app: uid.node_id is empty and the hostname is unavailable: %w", err)
+		}
+		return deriveSnowflakeNodeID(name), nil
+	}
+
+	nodeID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || nodeID < 0 || nodeID > maxSnowflakeNodeID {
+		return 0, fmt.Errorf("app: uid.node_id must be an integer between 0 and %d, got %q", maxSnowflakeNodeID, raw)
+	}
+	return nodeID, nil
+}
+
+// deriveSnowflakeNodeID uses the pod ordinal of a StatefulSet hostname ("withdraw-api-3"),
+// which is unique per replica. Any other hostname is hashed onto the node range, where
+// two replicas can still collide; set uid.node_id explicitly if that matters.
+func deriveSnowflakeNodeID(name string) int64 {
+	if i := strings.LastIndexByte(name, '-'); i >= 0 {
+		ordinal, err := strconv.ParseInt(name[i+1:], 10, 64)
+		if err == nil && ordinal >= 0 && ordinal <= maxSnowflakeNodeID {
+			return ordinal
+		}
+	}
+
+	hasher := fnv.New32a()
+	hasher.Write([]byte(name))
+	return int64(hasher.Sum32() % (maxSnowflakeNodeID + 1))
+}
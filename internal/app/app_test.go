@@ -1,13 +1,66 @@
 package app
 
 import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bwmarrin/snowflake"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
 
+	dbfiles "github.com/joshuarp/withdraw-api/db"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/handlers"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+	"github.com/joshuarp/withdraw-api/internal/services"
+
+	handlermocks "github.com/joshuarp/withdraw-api/internal/mock/handlers"
 	configmocks "github.com/joshuarp/withdraw-api/internal/mock/shared/config"
+	idempotencymocks "github.com/joshuarp/withdraw-api/internal/mock/shared/idempotency"
+	jwtmocks "github.com/joshuarp/withdraw-api/internal/mock/shared/jwt"
+	sharedaudit "github.com/joshuarp/withdraw-api/internal/shared/audit"
+	sharedevents "github.com/joshuarp/withdraw-api/internal/shared/events"
+	sharedidempotency "github.com/joshuarp/withdraw-api/internal/shared/idempotency"
+	sharedjwt "github.com/joshuarp/withdraw-api/internal/shared/jwt"
+	sharedmigration "github.com/joshuarp/withdraw-api/internal/shared/migration"
+	"github.com/joshuarp/withdraw-api/internal/shared/payout"
+	sharedratelimit "github.com/joshuarp/withdraw-api/internal/shared/ratelimit"
 )
 
 type AppHelpersSuite struct {
@@ -140,12 +193,56 @@ func (s *AppHelpersSuite) TestModuleDBInt_TableDriven() {
 
 func (s *AppHelpersSuite) TestProvideFiberApp_TableDriven() {
 	tests := []struct {
-		name       string
-		readValue  time.Duration
-		writeValue time.Duration
+		name           string
+		readValue      time.Duration
+		writeValue     time.Duration
+		trustedProxies []string
+		proxyHeader    string
+		expectErr      bool
+		assertion      func(cfg fiber.Config)
 	}{
-		{name: "defaults when config missing", readValue: 0, writeValue: 0},
-		{name: "uses configured timeout", readValue: 10 * time.Second, writeValue: 12 * time.Second},
+		{
+			name: "defaults when config missing",
+			assertion: func(cfg fiber.Config) {
+				assert.Equal(s.T(), 30*time.Second, cfg.ReadTimeout)
+				assert.Equal(s.T(), 30*time.Second, cfg.WriteTimeout)
+				assert.False(s.T(), cfg.TrustProxy)
+				assert.Empty(s.T(), cfg.ProxyHeader)
+			},
+		},
+		{
+			name:       "uses configured timeout",
+			readValue:  10 * time.Second,
+			writeValue: 12 * time.Second,
+			assertion: func(cfg fiber.Config) {
+				assert.Equal(s.T(), 10*time.Second, cfg.ReadTimeout)
+				assert.Equal(s.T(), 12*time.Second, cfg.WriteTimeout)
+			},
+		},
+		{
+			name:           "trusts configured proxies with default header",
+			trustedProxies: []string{" 10.0.0.0/8 ", "", "192.168.1.10"},
+			assertion: func(cfg fiber.Config) {
+				assert.True(s.T(), cfg.TrustProxy)
+				assert.Equal(s.T(), []string{"10.0.0.0/8", "192.168.1.10"}, cfg.TrustProxyConfig.Proxies)
+				assert.Equal(s.T(), fiber.HeaderXForwardedFor, cfg.ProxyHeader)
+				assert.True(s.T(), cfg.EnableIPValidation)
+			},
+		},
+		{
+			name:           "uses configured proxy header",
+			trustedProxies: []string{"10.0.0.1"},
+			proxyHeader:    "X-Real-IP",
+			assertion: func(cfg fiber.Config) {
+				assert.True(s.T(), cfg.TrustProxy)
+				assert.Equal(s.T(), "X-Real-IP", cfg.ProxyHeader)
+			},
+		},
+		{
+			name:           "rejects invalid trusted proxy",
+			trustedProxies: []string{"10.0.0.300"},
+			expectErr:      true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -153,9 +250,19 @@ func (s *AppHelpersSuite) TestProvideFiberApp_TableDriven() {
 			s.SetupTest()
 			s.cfg.EXPECT().GetDuration("server.read_timeout").Return(tc.readValue)
 			s.cfg.EXPECT().GetDuration("server.write_timeout").Return(tc.writeValue)
+			s.cfg.EXPECT().GetStringSlice("server.trusted_proxies").Return(tc.trustedProxies)
+			if len(tc.trustedProxies) > 0 {
+				s.cfg.EXPECT().GetString("server.proxy_header").Return(tc.proxyHeader)
+			}
 
-			fiberApp := provideFiberApp(s.cfg)
-			assert.NotNil(s.T(), fiberApp)
+			fiberApp, err := provideFiberApp(s.cfg)
+			if tc.expectErr {
+				require.Error(s.T(), err)
+				return
+			}
+			require.NoError(s.T(), err)
+			require.NotNil(s.T(), fiberApp)
+			tc.assertion(fiberApp.Config())
 		})
 	}
 }
@@ -164,7 +271,7 @@ func (s *AppHelpersSuite) TestProvideJWTTokenManager_TableDriven() {
 	tests := []struct {
 		name      string
 		setupMock func()
-		assertion func(error)
+		assertion func(sharedjwt.TokenManager, error)
 	}{
 		{
 			name: "uses security jwt secret and ttl",
@@ -172,8 +279,9 @@ func (s *AppHelpersSuite) TestProvideJWTTokenManager_TableDriven() {
 				s.cfg.EXPECT().GetString("security.jwt.secret").Return("12345678901234567890123456789012")
 				s.cfg.EXPECT().GetDuration("security.jwt.ttl").Return(15 * time.Minute)
 				s.cfg.EXPECT().GetString("security.jwt.issuer").Return("withdraw-api")
+				s.cfg.EXPECT().GetStringSlice("security.jwt.audience").Return(nil)
 			},
-			assertion: func(err error) {
+			assertion: func(_ sharedjwt.TokenManager, err error) {
 				assert.NoError(s.T(), err)
 			},
 		},
@@ -184,11 +292,32 @@ func (s *AppHelpersSuite) TestProvideJWTTokenManager_TableDriven() {
 				s.cfg.EXPECT().GetString("jwt.secret").Return("legacy")
 				s.cfg.EXPECT().GetDuration("security.jwt.ttl").Return(time.Duration(0))
 				s.cfg.EXPECT().GetString("security.jwt.issuer").Return("issuer")
+				s.cfg.EXPECT().GetStringSlice("security.jwt.audience").Return(nil)
 			},
-			assertion: func(err error) {
+			assertion: func(_ sharedjwt.TokenManager, err error) {
 				assert.NoError(s.T(), err)
 			},
 		},
+		{
+			name: "signed tokens carry configured audience",
+			setupMock: func() {
+				s.cfg.EXPECT().GetString("security.jwt.secret").Return("12345678901234567890123456789012")
+				s.cfg.EXPECT().GetDuration("security.jwt.ttl").Return(15 * time.Minute)
+				s.cfg.EXPECT().GetString("security.jwt.issuer").Return("withdraw-api")
+				s.cfg.EXPECT().GetStringSlice("security.jwt.audience").Return([]string{"withdraw-api", "mobile-app"})
+			},
+			assertion: func(manager sharedjwt.TokenManager, err error) {
+				require.NoError(s.T(), err)
+
+				token, err := manager.Sign(context.Background(), sharedjwt.Claims{Subject: "user-1"})
+				require.NoError(s.T(), err)
+
+				claims, err := manager.Verify(context.Background(), token)
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), "withdraw-api", claims.Issuer)
+				assert.Equal(s.T(), []string{"withdraw-api", "mobile-app"}, claims.Audience)
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -198,7 +327,127 @@ func (s *AppHelpersSuite) TestProvideJWTTokenManager_TableDriven() {
 
 			manager, err := provideJWTTokenManager(s.cfg)
 			assert.NotNil(s.T(), manager)
-			tc.assertion(err)
+			tc.assertion(manager, err)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestNewAPIRouterGroups_JWTBypass_TableDriven() {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{name: "login stays public", method: http.MethodPost, path: "/api/v1/auth/login", expectedCode: fiber.StatusOK},
+		{name: "similar auth path requires token", method: http.MethodGet, path: "/api/v1/auth/login-attempts", expectedCode: fiber.StatusUnauthorized},
+		{name: "protected route requires token", method: http.MethodPost, path: "/api/v1/withdrawals", expectedCode: fiber.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			passThrough := func(c fiber.Ctx) error { return c.Next() }
+
+			fiberApp := fiber.New()
+			groups := newAPIRouterGroups(fiberApp, time.Second, passThrough, passThrough, jwtmocks.NewTokenManager(s.T()), middlewares.JWTMiddlewareConfig{})
+			groups.Public.Post("/auth/login", func(c fiber.Ctx) error { return c.SendString("ok") })
+			groups.Protected.Get("/auth/login-attempts", func(c fiber.Ctx) error { return c.SendString("ok") })
+			groups.Protected.Post("/withdrawals", func(c fiber.Ctx) error { return c.SendString("ok") })
+
+			resp, err := fiberApp.Test(httptest.NewRequest(tc.method, tc.path, nil))
+			require.NoError(s.T(), err)
+			defer resp.Body.Close()
+
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestNewAPIRouterGroups_CORS_TableDriven() {
+	const (
+		loginOrigin = "https://login.example.com"
+		appOrigin   = "https://app.example.com"
+	)
+
+	tests := []struct {
+		name              string
+		path              string
+		origin            string
+		expectAllowOrigin string
+		expectMaxAge      string
+		expectMethods     []string
+	}{
+		{
+			name:              "public preflight uses public policy",
+			path:              "/api/v1/auth/login",
+			origin:            loginOrigin,
+			expectAllowOrigin: loginOrigin,
+			expectMaxAge:      "60",
+			expectMethods:     []string{fiber.MethodPost, fiber.MethodOptions},
+		},
+		{
+			name:   "public route denies protected origin",
+			path:   "/api/v1/auth/login",
+			origin: appOrigin,
+		},
+		{
+			name:              "protected preflight uses protected policy",
+			path:              "/api/v1/withdrawals",
+			origin:            appOrigin,
+			expectAllowOrigin: appOrigin,
+			expectMaxAge:      "600",
+			expectMethods:     []string{fiber.MethodGet, fiber.MethodPost},
+		},
+		{
+			name:   "protected route denies public origin",
+			path:   "/api/v1/withdrawals",
+			origin: loginOrigin,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			publicCORS, err := middlewares.NewHTTPCORSMiddleware(middlewares.CORSConfig{
+				AllowedOrigins: []string{loginOrigin},
+				AllowedMethods: []string{fiber.MethodPost, fiber.MethodOptions},
+				MaxAge:         time.Minute,
+			})
+			require.NoError(s.T(), err)
+			protectedCORS, err := middlewares.NewHTTPCORSMiddleware(middlewares.CORSConfig{
+				AllowedOrigins: []string{appOrigin},
+				AllowedMethods: []string{fiber.MethodGet, fiber.MethodPost},
+				MaxAge:         10 * time.Minute,
+				Next:           isPublicAuthPath,
+			})
+			require.NoError(s.T(), err)
+
+			fiberApp := fiber.New()
+			groups := newAPIRouterGroups(fiberApp, time.Second, publicCORS, protectedCORS, jwtmocks.NewTokenManager(s.T()), middlewares.JWTMiddlewareConfig{})
+			groups.Public.Post("/auth/login", func(c fiber.Ctx) error { return c.SendString("ok") })
+			groups.Protected.Post("/withdrawals", func(c fiber.Ctx) error { return c.SendString("ok") })
+
+			req := httptest.NewRequest(http.MethodOptions, tc.path, nil)
+			req.Header.Set(fiber.HeaderOrigin, tc.origin)
+			req.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodPost)
+
+			resp, err := fiberApp.Test(req)
+			require.NoError(s.T(), err)
+			defer resp.Body.Close()
+
+			assert.NotEqual(s.T(), fiber.StatusUnauthorized, resp.StatusCode, "preflight must not reach JWT auth")
+			assert.Equal(s.T(), tc.expectAllowOrigin, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+			if tc.expectAllowOrigin == "" {
+				return
+			}
+
+			assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+			assert.Equal(s.T(), tc.expectMaxAge, resp.Header.Get(fiber.HeaderAccessControlMaxAge))
+
+			var methods []string
+			for _, method := range strings.Split(resp.Header.Get(fiber.HeaderAccessControlAllowMethods), ",") {
+				methods = append(methods, strings.TrimSpace(method))
+			}
+			assert.Equal(s.T(), tc.expectMethods, methods)
 		})
 	}
 }
@@ -268,6 +517,7 @@ func (s *AppHelpersSuite) TestParseRateLimitAlgorithm_TableDriven() {
 	}{
 		{name: "default token bucket", input: "", expect: "token_bucket"},
 		{name: "sliding window", input: "sliding_window", expect: "sliding_window"},
+		{name: "sliding window counter", input: "sliding_window_counter", expect: "sliding_window_counter"},
 		{name: "fixed window", input: "fixed_window", expect: "fixed_window"},
 		{name: "unknown falls back", input: "random", expect: "token_bucket"},
 	}
@@ -279,6 +529,2220 @@ func (s *AppHelpersSuite) TestParseRateLimitAlgorithm_TableDriven() {
 	}
 }
 
-func TestAppHelpersSuite(t *testing.T) {
-	suite.Run(t, new(AppHelpersSuite))
+func (s *AppHelpersSuite) TestParseRateLimitHeaderStyle_TableDriven() {
+	tests := []struct {
+		name   string
+		input  string
+		expect middlewares.RateLimitHeaderStyle
+	}{
+		{name: "default legacy", input: "", expect: middlewares.RateLimitHeaderStyleLegacy},
+		{name: "standard", input: "standard", expect: middlewares.RateLimitHeaderStyleStandard},
+		{name: "both is case insensitive", input: " Both ", expect: middlewares.RateLimitHeaderStyleBoth},
+		{name: "unknown falls back", input: "ietf", expect: middlewares.RateLimitHeaderStyleLegacy},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			assert.Equal(s.T(), tc.expect, parseRateLimitHeaderStyle(tc.input))
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterMetricsRoute_TableDriven() {
+	const secret = "internal-secret"
+
+	tests := []struct {
+		name         string
+		mode         string
+		allowlist    []string
+		signed       bool
+		expectedCode int
+	}{
+		{name: "allowlisted scraper gets metrics", mode: "allowlist", allowlist: []string{"0.0.0.0/32"}, expectedCode: fiber.StatusOK},
+		{name: "default mode uses allowlist", mode: "", allowlist: []string{"0.0.0.0"}, expectedCode: fiber.StatusOK},
+		{name: "non allowlisted client is forbidden", mode: "allowlist", allowlist: []string{"10.0.0.0/8"}, expectedCode: fiber.StatusForbidden},
+		{name: "default allowlist rejects remote client", mode: "allowlist", expectedCode: fiber.StatusForbidden},
+		{name: "internal auth accepts signed scraper", mode: "internal_auth", signed: true, expectedCode: fiber.StatusOK},
+		{name: "internal auth rejects unsigned client", mode: "internal_auth", expectedCode: fiber.StatusForbidden},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetStringSlice("metrics.access.allowlist").Return(tc.allowlist)
+			s.cfg.EXPECT().GetString("metrics.access.mode").Return(tc.mode)
+			s.cfg.EXPECT().GetString("security.internal_auth.secret").Return(secret)
+			s.cfg.EXPECT().GetDuration("security.internal_auth.max_age").Return(0)
+			s.cfg.EXPECT().GetDuration("security.internal_auth.clock_skew").Return(0)
+
+			fiberApp := fiber.New()
+			require.NoError(s.T(), registerMetricsRoute(fiberApp, s.cfg, provideMetricsRegistry()))
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tc.signed {
+				req.Header.Set(middlewares.InternalAuthHeader, middlewares.SignInternalAuth([]byte(secret), http.MethodGet, "/metrics", time.Now()))
+			}
+
+			resp, err := fiberApp.Test(req)
+			require.NoError(s.T(), err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedCode == fiber.StatusOK {
+				assert.Contains(s.T(), string(body), "go_goroutines")
+			}
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvideWithdrawRateLimiter_LogsStartupConfig_TableDriven() {
+	tests := []struct {
+		name      string
+		logStart  bool
+		expectLog bool
+	}{
+		{name: "logs effective parameters when enabled", logStart: true, expectLog: true},
+		{name: "stays quiet when disabled", logStart: false, expectLog: false},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetInt("rate_limit.withdraw.limit").Return(5)
+			s.cfg.EXPECT().GetDuration("rate_limit.withdraw.window").Return(30 * time.Second)
+			s.cfg.EXPECT().GetInt("rate_limit.withdraw.burst").Return(8)
+			s.cfg.EXPECT().GetString("rate_limit.withdraw.algorithm").Return("fixed_window")
+			s.cfg.EXPECT().GetBool("rate_limit.log_startup").Return(tc.logStart)
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			redisClient := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+			defer redisClient.Close()
+
+			limiter, err := provideWithdrawRateLimiter(s.cfg, redisClient, logger)
+			require.NoError(s.T(), err)
+			require.NotNil(s.T(), limiter)
+
+			if !tc.expectLog {
+				assert.Empty(s.T(), buf.String())
+				return
+			}
+
+			var entry map[string]any
+			require.NoError(s.T(), json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(s.T(), "rate limiter configured", entry["msg"])
+			assert.Equal(s.T(), "withdraw", entry["scope"])
+			assert.Equal(s.T(), "fixed_window", entry["algorithm"])
+			assert.EqualValues(s.T(), 5, entry["limit"])
+			assert.Equal(s.T(), "30s", entry["window"])
+			assert.EqualValues(s.T(), 8, entry["burst"])
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvideWithdrawConcurrencyLimiter_TableDriven() {
+	tests := []struct {
+		name        string
+		maxInFlight int
+		expectNil   bool
+	}{
+		{name: "disabled by default", maxInFlight: 0, expectNil: true},
+		{name: "negative disables", maxInFlight: -1, expectNil: true},
+		{name: "enabled with cap", maxInFlight: 2},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetInt("rate_limit.withdraw.max_in_flight").Return(tc.maxInFlight)
+			if !tc.expectNil {
+				s.cfg.EXPECT().GetDuration("rate_limit.withdraw.in_flight_ttl").Return(30 * time.Second)
+			}
+
+			redisClient := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+			defer redisClient.Close()
+
+			limiter, err := provideWithdrawConcurrencyLimiter(s.cfg, redisClient)
+			require.NoError(s.T(), err)
+			if tc.expectNil {
+				assert.Nil(s.T(), limiter)
+				return
+			}
+			assert.NotNil(s.T(), limiter)
+		})
+	}
+}
+
+type closeCountingLimiter struct {
+	sharedratelimit.Limiter
+
+	closeErr error
+	closes   int
+}
+
+func (l *closeCountingLimiter) Close() error {
+	l.closes++
+	return l.closeErr
+}
+
+func (s *AppHelpersSuite) TestRegisterRateLimiterShutdown_TableDriven() {
+	closeErr := errors.New("close failed")
+
+	tests := []struct {
+		name        string
+		closeErr    error
+		expectedErr error
+	}{
+		{name: "closes limiter once on stop"},
+		{name: "surfaces close error", closeErr: closeErr, expectedErr: closeErr},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			limiter := &closeCountingLimiter{closeErr: tc.closeErr}
+
+			lifecycle := fxtest.NewLifecycle(s.T())
+			registerRateLimiterShutdown(lifecycle, limiter)
+			lifecycle.RequireStart()
+			assert.Zero(s.T(), limiter.closes)
+
+			err := lifecycle.Stop(context.Background())
+			assert.Equal(s.T(), 1, limiter.closes)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectedErr)
+				return
+			}
+			assert.NoError(s.T(), err)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterRateLimiterShutdown_LeavesSharedRedisClientOpen() {
+	s.cfg.EXPECT().GetInt("rate_limit.withdraw.limit").Return(5)
+	s.cfg.EXPECT().GetDuration("rate_limit.withdraw.window").Return(time.Minute)
+	s.cfg.EXPECT().GetInt("rate_limit.withdraw.burst").Return(0)
+	s.cfg.EXPECT().GetString("rate_limit.withdraw.algorithm").Return("")
+	s.cfg.EXPECT().GetBool("rate_limit.log_startup").Return(false)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	limiter, err := provideWithdrawRateLimiter(s.cfg, redisClient, nil)
+	require.NoError(s.T(), err)
+
+	lifecycle := fxtest.NewLifecycle(s.T())
+	registerRateLimiterShutdown(lifecycle, limiter)
+	lifecycle.RequireStart().RequireStop()
+
+	// The owner's close must be the first one; a second close would report redis.ErrClosed.
+	assert.NoError(s.T(), redisClient.Close())
+	assert.ErrorIs(s.T(), redisClient.Close(), redis.ErrClosed)
+}
+
+// memoryRateLimitStore is a fixed-budget store so route tests do not need Redis.
+type memoryRateLimitStore struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (m *memoryRateLimitStore) Allow(_ context.Context, key string, config sharedratelimit.Config) (sharedratelimit.Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.counts == nil {
+		m.counts = make(map[string]int64)
+	}
+	m.counts[key]++
+
+	result := sharedratelimit.Result{
+		Allowed:   m.counts[key] <= config.Limit,
+		Limit:     config.Limit,
+		Remaining: max(config.Limit-m.counts[key], 0),
+		ResetAt:   time.Now().Add(config.Window),
+	}
+	if !result.Allowed {
+		result.RetryAfter = config.Window
+	}
+	return result, nil
+}
+
+func (m *memoryRateLimitStore) Reset(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.counts, key)
+	return nil
+}
+
+func (m *memoryRateLimitStore) ResetPrefix(_ context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.counts {
+		if strings.HasPrefix(key, prefix+":") {
+			delete(m.counts, key)
+		}
+	}
+	return nil
+}
+
+func (m *memoryRateLimitStore) Close() error { return nil }
+
+func (s *AppHelpersSuite) TestRegisterAuthRoutes_RateLimitsLoginPerIP() {
+	const limit = 3
+
+	store := &memoryRateLimitStore{}
+	limiter, err := sharedratelimit.New(store, sharedratelimit.Config{Limit: limit, Window: time.Minute})
+	require.NoError(s.T(), err)
+
+	service := handlermocks.NewAuthLoginService(s.T())
+	service.EXPECT().Login(mock.Anything, "user@example.com", "wrong-password").
+		Return(vo.AuthLogin{}, vo.ErrInvalidCredentials).Times(limit)
+
+	s.cfg.EXPECT().GetString("rate_limit.header_style").Return("")
+	s.cfg.EXPECT().GetBool("rate_limit.precise_retry_after").Return(false)
+
+	fiberApp := fiber.New()
+	api := fiberApp.Group("/api/v1")
+	registerAuthRoutes(authRoutesIn{
+		Public:                api,
+		Protected:             api,
+		Config:                s.cfg,
+		RateLimiter:           limiter,
+		Handler:               handlers.NewAuthLoginHandler(service, nil),
+		RegisterHandler:       handlers.NewAuthRegisterHandler(handlermocks.NewAuthRegisterService(s.T()), nil),
+		ChangePasswordHandler: handlers.NewAuthChangePasswordHandler(handlermocks.NewAuthChangePasswordService(s.T()), nil),
+		MeHandler:             handlers.NewAuthMeHandler(),
+	})
+
+	for attempt := 1; attempt <= limit+1; attempt++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"email":"user@example.com","password":"wrong-password"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := fiberApp.Test(req)
+		require.NoError(s.T(), err)
+		resp.Body.Close()
+
+		if attempt <= limit {
+			assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode, "attempt %d", attempt)
+			continue
+		}
+		assert.Equal(s.T(), fiber.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(s.T(), resp.Header.Get(fiber.HeaderRetryAfter))
+	}
+
+	// Register spends the same per-IP budget; routes behind a token are not limited.
+	tests := []struct {
+		method   string
+		path     string
+		expected int
+	}{
+		{method: http.MethodPost, path: "/api/v1/auth/register", expected: fiber.StatusTooManyRequests},
+		{method: http.MethodGet, path: "/api/v1/auth/me", expected: fiber.StatusUnauthorized},
+		{method: http.MethodPost, path: "/api/v1/auth/change-password", expected: fiber.StatusUnauthorized},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		resp, err := fiberApp.Test(req)
+		require.NoError(s.T(), err)
+		resp.Body.Close()
+		assert.Equal(s.T(), tc.expected, resp.StatusCode, "%s %s", tc.method, tc.path)
+	}
+
+	require.Len(s.T(), store.counts, 1)
+	for key := range store.counts {
+		assert.True(s.T(), strings.HasPrefix(key, "auth:ip:"), "unexpected rate limit key %q", key)
+	}
+}
+
+func (s *AppHelpersSuite) TestRateLimitResetRoute_ClearsWholeScope() {
+	store := &memoryRateLimitStore{}
+	limiter, err := sharedratelimit.New(store, sharedratelimit.Config{Limit: 5, Window: time.Minute})
+	require.NoError(s.T(), err)
+
+	for _, key := range []string{
+		middlewares.UserRateLimitKey("withdraw", "user-1"),
+		middlewares.UserRateLimitKey("withdraw", "user-2"),
+		middlewares.UserRateLimitKey("withdrawals", "user-1"),
+	} {
+		_, err := limiter.AllowKey(context.Background(), key)
+		require.NoError(s.T(), err)
+	}
+
+	handler, err := provideRateLimitResetHandler(limiter, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(s.T(), err)
+
+	fiberApp := fiber.New()
+	admin := fiberApp.Group("/api/v1/admin", func(c fiber.Ctx) error {
+		c.Locals("user_id", "admin-1")
+		c.Locals("jwt_claims", &sharedjwt.Claims{Subject: "admin-1", Scopes: []string{rateLimitResetScope}})
+		return c.Next()
+	})
+	registerRateLimitResetRoutes(rateLimitResetRoutesIn{Admin: admin, Handler: handler})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/ratelimit/reset", strings.NewReader(`{"scope":"withdraw","all":true}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := fiberApp.Test(req)
+	require.NoError(s.T(), err)
+	resp.Body.Close()
+
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), map[string]int64{middlewares.UserRateLimitKey("withdrawals", "user-1"): 1}, store.counts)
+}
+
+// memoryIdempotencyStore keeps completed responses in memory with the SQL store's
+// acquire semantics, so route tests can exercise replays and conflicts end to end.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+}
+
+type memoryIdempotencyEntry struct {
+	hash     string
+	response *sharedidempotency.StoredResponse
+}
+
+func (m *memoryIdempotencyStore) Acquire(_ context.Context, request sharedidempotency.Request) (sharedidempotency.Decision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.entries == nil {
+		m.entries = map[string]memoryIdempotencyEntry{}
+	}
+
+	id := request.Scope + "/" + request.Key
+	entry, ok := m.entries[id]
+	switch {
+	case !ok:
+		m.entries[id] = memoryIdempotencyEntry{hash: request.RequestHash}
+		return sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired}, nil
+	case entry.hash != request.RequestHash:
+		return sharedidempotency.Decision{Type: sharedidempotency.DecisionConflict}, nil
+	case entry.response == nil:
+		return sharedidempotency.Decision{Type: sharedidempotency.DecisionInProgress}, nil
+	default:
+		return sharedidempotency.Decision{
+			Type:        sharedidempotency.DecisionReplay,
+			StatusCode:  entry.response.StatusCode,
+			Body:        entry.response.Body,
+			ContentType: entry.response.ContentType,
+		}, nil
+	}
+}
+
+func (m *memoryIdempotencyStore) Complete(_ context.Context, request sharedidempotency.Request, response sharedidempotency.StoredResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := request.Scope + "/" + request.Key
+	entry := m.entries[id]
+	entry.response = &response
+	m.entries[id] = entry
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Release(_ context.Context, request sharedidempotency.Request) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := request.Scope + "/" + request.Key
+	if entry, ok := m.entries[id]; ok && entry.response == nil {
+		delete(m.entries, id)
+	}
+	return nil
+}
+
+func (s *AppHelpersSuite) expectIdempotencyOptions(scope string) {
+	s.cfg.EXPECT().GetBool("idempotency." + scope + ".status_header").Return(true)
+	s.cfg.EXPECT().GetBool("idempotency." + scope + ".echo_key").Return(false)
+	s.cfg.EXPECT().GetStringSlice("idempotency." + scope + ".hash_headers").Return(nil)
+	s.cfg.EXPECT().GetInt("idempotency.key.min_length").Return(0)
+	s.cfg.EXPECT().GetInt("idempotency.key.max_length").Return(0)
+	s.cfg.EXPECT().GetString("idempotency.key.charset").Return("")
+	s.cfg.EXPECT().GetInt("idempotency.max_body_bytes").Return(0)
+	s.cfg.EXPECT().GetInt("idempotency.complete_attempts").Return(0)
+	s.cfg.EXPECT().GetDuration("idempotency.complete_backoff").Return(time.Duration(0))
+	s.cfg.EXPECT().GetDuration("idempotency.complete_timeout").Return(time.Duration(0))
+}
+
+func newIdempotentRouteTestApp() (*fiber.App, fiber.Router) {
+	fiberApp := fiber.New()
+	api := fiberApp.Group("/api/v1", func(c fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		return c.Next()
+	})
+	return fiberApp, api
+}
+
+func (s *AppHelpersSuite) postIdempotent(fiberApp *fiber.App, path, key, body string) (*http.Response, []byte) {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(middlewares.IdempotencyKeyHeader, key)
+
+	resp, err := fiberApp.Test(req)
+	require.NoError(s.T(), err)
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(s.T(), err)
+	return resp, raw
+}
+
+func (s *AppHelpersSuite) TestRegisterDepositRoutes_DuplicateReplaysOriginalResponse() {
+	service := handlermocks.NewBalanceDepositService(s.T())
+	service.EXPECT().DepositBalance(mock.Anything, "user-1", int64(500)).
+		Return(vo.WalletDeposit{ReferenceID: "dep-1", UserID: "user-1", AmountMinor: 500, BalanceMinor: 1500, Currency: "IDR"}, nil).Once()
+	s.expectIdempotencyOptions("deposit")
+
+	store := &memoryIdempotencyStore{}
+	fiberApp, api := newIdempotentRouteTestApp()
+	registerDepositRoutes(depositRoutesIn{
+		Protected:        api,
+		Config:           s.cfg,
+		IdempotencyStore: store,
+		Handler:          handlers.NewInquiryDepositBalanceHandler(service, nil),
+	})
+
+	first, firstBody := s.postIdempotent(fiberApp, "/api/v1/deposits", "dep-key-1", `{"amount_minor":500}`)
+	assert.Equal(s.T(), fiber.StatusOK, first.StatusCode)
+	assert.Equal(s.T(), "acquired", first.Header.Get(middlewares.IdempotencyStatusHeader))
+
+	replay, replayBody := s.postIdempotent(fiberApp, "/api/v1/deposits", "dep-key-1", `{"amount_minor":500}`)
+	assert.Equal(s.T(), fiber.StatusOK, replay.StatusCode)
+	assert.Equal(s.T(), "replayed", replay.Header.Get(middlewares.IdempotencyStatusHeader))
+	assert.JSONEq(s.T(), string(firstBody), string(replayBody))
+
+	require.Len(s.T(), store.entries, 1)
+	assert.Contains(s.T(), store.entries, "deposit:user-1/dep-key-1")
+}
+
+func (s *AppHelpersSuite) TestRegisterTransferRoutes_ChangedDestinationConflicts() {
+	const (
+		sourceWalletID      = "0190a1b2-0000-7000-8000-000000000001"
+		destinationWalletID = "0190a1b2-0000-7000-8000-000000000002"
+		otherWalletID       = "0190a1b2-0000-7000-8000-000000000003"
+	)
+
+	service := handlermocks.NewBalanceTransferService(s.T())
+	service.EXPECT().TransferBalance(mock.Anything, "user-1", sourceWalletID, destinationWalletID, int64(700)).
+		Return(vo.WalletTransfer{ReferenceID: "trf-1", UserID: "user-1", SourceWalletID: sourceWalletID, DestinationWalletID: destinationWalletID, AmountMinor: 700}, nil).Once()
+	s.expectIdempotencyOptions("transfer")
+
+	store := &memoryIdempotencyStore{}
+	fiberApp, api := newIdempotentRouteTestApp()
+	registerTransferRoutes(transferRoutesIn{
+		Protected:        api,
+		Config:           s.cfg,
+		IdempotencyStore: store,
+		Handler:          handlers.NewTransferBalanceHandler(service, nil),
+	})
+
+	body := func(destination string) string {
+		return fmt.Sprintf(`{"source_wallet_id":%q,"destination_wallet_id":%q,"amount_minor":700}`, sourceWalletID, destination)
+	}
+
+	first, _ := s.postIdempotent(fiberApp, "/api/v1/transfers", "trf-key-1", body(destinationWalletID))
+	assert.Equal(s.T(), fiber.StatusOK, first.StatusCode)
+
+	changed, changedBody := s.postIdempotent(fiberApp, "/api/v1/transfers", "trf-key-1", body(otherWalletID))
+	assert.Equal(s.T(), fiber.StatusConflict, changed.StatusCode)
+	assert.Contains(s.T(), string(changedBody), "idempotency key reused with different payload")
+
+	assert.Contains(s.T(), store.entries, "transfer:user-1/trf-key-1")
+}
+
+func (s *AppHelpersSuite) TestRegisterWithdrawRateLimiterReload_TableDriven() {
+	tests := []struct {
+		name            string
+		reloadedLimit   int
+		reloadedWindow  time.Duration
+		expectedAllowed int
+	}{
+		{name: "raised limit applies to subsequent requests", reloadedLimit: 3, reloadedWindow: time.Minute, expectedAllowed: 3},
+		{name: "non-positive limit keeps previous config", reloadedLimit: 0, reloadedWindow: time.Minute, expectedAllowed: 1},
+		{name: "non-positive window keeps previous config", reloadedLimit: 3, reloadedWindow: -time.Second, expectedAllowed: 1},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+
+			store := &memoryRateLimitStore{}
+			limiter, err := sharedratelimit.New(store, sharedratelimit.Config{Limit: 1, Window: time.Minute})
+			require.NoError(s.T(), err)
+
+			var reload func()
+			s.cfg.EXPECT().OnChange(mock.Anything).Run(func(fn func()) { reload = fn }).Once()
+			registerWithdrawRateLimiterReload(s.cfg, nil, limiter)
+			require.NotNil(s.T(), reload)
+
+			s.cfg.EXPECT().IsSet(mock.Anything).Return(true).Maybe()
+			s.cfg.EXPECT().GetInt("rate_limit.withdraw.limit").Return(tc.reloadedLimit).Maybe()
+			s.cfg.EXPECT().GetInt("rate_limit.withdraw.burst").Return(tc.reloadedLimit).Maybe()
+			s.cfg.EXPECT().GetDuration("rate_limit.withdraw.window").Return(tc.reloadedWindow).Maybe()
+			s.cfg.EXPECT().GetString("rate_limit.withdraw.algorithm").Return("fixed_window").Maybe()
+			reload()
+
+			allowed := 0
+			for range 5 {
+				result, err := limiter.AllowKey(context.Background(), "withdraw:user:user-1")
+				require.NoError(s.T(), err)
+				if result.Allowed {
+					allowed++
+				}
+			}
+			assert.Equal(s.T(), tc.expectedAllowed, allowed)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterConfigWatch_TableDriven() {
+	tests := []struct {
+		name        string
+		watch       bool
+		expectWatch bool
+	}{
+		{name: "disabled by default", watch: false},
+		{name: "watches while running", watch: true, expectWatch: true},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetBool("config.watch").Return(tc.watch)
+			if tc.expectWatch {
+				s.cfg.EXPECT().WatchChanges().Once()
+				s.cfg.EXPECT().StopWatching().Once()
+			}
+
+			lifecycle := fxtest.NewLifecycle(s.T())
+			registerConfigWatch(lifecycle, s.cfg)
+			lifecycle.RequireStart().RequireStop()
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvideWithdrawBlackoutSchedule_TableDriven() {
+	tests := []struct {
+		name      string
+		entries   []string
+		expectLen int
+		expectErr bool
+	}{
+		{name: "empty schedule", expectLen: 0},
+		{name: "parses chain window", entries: []string{"ethereum=2026-01-01T00:00:00Z/2026-01-01T02:00:00Z"}, expectLen: 1},
+		{name: "rejects missing chain", entries: []string{"=2026-01-01T00:00:00Z/2026-01-01T02:00:00Z"}, expectErr: true},
+		{name: "rejects missing range", entries: []string{"ethereum=2026-01-01T00:00:00Z"}, expectErr: true},
+		{name: "rejects malformed time", entries: []string{"ethereum=tomorrow/2026-01-01T02:00:00Z"}, expectErr: true},
+		{name: "rejects inverted window", entries: []string{"ethereum=2026-01-01T02:00:00Z/2026-01-01T00:00:00Z"}, expectErr: true},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetStringSlice("withdraw.blackout_windows").Return(tc.entries)
+
+			schedule, err := provideWithdrawBlackoutSchedule(s.cfg)
+			if tc.expectErr {
+				assert.Error(s.T(), err)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			assert.Len(s.T(), schedule, tc.expectLen)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestLoadJWTBypassRules_TableDriven() {
+	tests := []struct {
+		name        string
+		isSet       bool
+		entries     []string
+		expectRules []middlewares.JWTBypassRule
+		expectErr   bool
+	}{
+		{name: "unset keeps middleware default"},
+		{name: "empty list disables bypass", isSet: true, entries: []string{}, expectRules: []middlewares.JWTBypassRule{}},
+		{
+			name:    "parses method and suffix",
+			isSet:   true,
+			entries: []string{"POST /auth/login", " post  /auth/register ", ""},
+			expectRules: []middlewares.JWTBypassRule{
+				{Method: fiber.MethodPost, PathSuffix: "/auth/login"},
+				{Method: fiber.MethodPost, PathSuffix: "/auth/register"},
+			},
+		},
+		{name: "rejects missing suffix", isSet: true, entries: []string{"POST"}, expectErr: true},
+		{name: "rejects root suffix", isSet: true, entries: []string{"GET /"}, expectErr: true},
+		{name: "rejects unknown method", isSet: true, entries: []string{"FETCH /auth/login"}, expectErr: true},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().IsSet("security.jwt.public_routes").Return(tc.isSet)
+			if tc.isSet {
+				s.cfg.EXPECT().GetStringSlice("security.jwt.public_routes").Return(tc.entries)
+			}
+
+			rules, err := loadJWTBypassRules(s.cfg)
+			if tc.expectErr {
+				assert.Error(s.T(), err)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectRules, rules)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterPprofRoutes_TableDriven() {
+	const secret = "internal-secret"
+
+	tests := []struct {
+		name         string
+		enabled      bool
+		signed       bool
+		expectedCode int
+	}{
+		{name: "enabled serves profile index to signed caller", enabled: true, signed: true, expectedCode: fiber.StatusOK},
+		{name: "enabled rejects unsigned caller", enabled: true, expectedCode: fiber.StatusForbidden},
+		{name: "disabled by default", signed: true, expectedCode: fiber.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetBool("debug.pprof.enabled").Return(tc.enabled)
+			s.cfg.EXPECT().GetString("security.internal_auth.secret").Return(secret).Maybe()
+			s.cfg.EXPECT().GetDuration("security.internal_auth.max_age").Return(0).Maybe()
+			s.cfg.EXPECT().GetDuration("security.internal_auth.clock_skew").Return(0).Maybe()
+
+			fiberApp := fiber.New()
+			registerPprofRoutes(pprofRoutesIn{Admin: &adminServer{App: fiberApp}, Config: s.cfg})
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+			if tc.signed {
+				req.Header.Set(middlewares.InternalAuthHeader, middlewares.SignInternalAuth([]byte(secret), http.MethodGet, "/debug/pprof/", time.Now()))
+			}
+
+			resp, err := fiberApp.Test(req)
+			require.NoError(s.T(), err)
+			defer resp.Body.Close()
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterDebugConfigRoute_TableDriven() {
+	const secret = "internal-secret"
+
+	tests := []struct {
+		name         string
+		env          string
+		enabled      bool
+		signed       bool
+		expectedCode int
+	}{
+		{name: "staging exposes redacted config to signed caller", env: "staging", enabled: true, signed: true, expectedCode: fiber.StatusOK},
+		{name: "staging rejects unsigned caller", env: "staging", enabled: true, expectedCode: fiber.StatusForbidden},
+		{name: "production never registers route", env: "production", enabled: true, signed: true, expectedCode: fiber.StatusNotFound},
+		{name: "unset env is treated as production", env: "", enabled: true, signed: true, expectedCode: fiber.StatusNotFound},
+		{name: "disabled option hides route", env: "staging", enabled: false, signed: true, expectedCode: fiber.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetBool("debug.config_endpoint").Return(tc.enabled)
+			s.cfg.EXPECT().GetString("app.env").Return(tc.env).Maybe()
+			s.cfg.EXPECT().GetString("security.internal_auth.secret").Return(secret).Maybe()
+			s.cfg.EXPECT().GetDuration("security.internal_auth.max_age").Return(0).Maybe()
+			s.cfg.EXPECT().GetDuration("security.internal_auth.clock_skew").Return(0).Maybe()
+			s.cfg.EXPECT().AllSettings().Return(map[string]interface{}{
+				"server": map[string]interface{}{"port": 8080},
+				"database": map[string]interface{}{
+					"host":     "db.internal",
+					"password": "db-password",
+				},
+				"security": map[string]interface{}{
+					"jwt":           map[string]interface{}{"secret": "jwt-secret", "issuer": "withdraw-service"},
+					"internal_auth": map[string]interface{}{"secret": secret},
+				},
+			}).Maybe()
+
+			fiberApp := fiber.New()
+			registerDebugConfigRoute(fiberApp, s.cfg)
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/config", nil)
+			if tc.signed {
+				req.Header.Set(middlewares.InternalAuthHeader, middlewares.SignInternalAuth([]byte(secret), http.MethodGet, "/debug/config", time.Now()))
+			}
+
+			resp, err := fiberApp.Test(req)
+			require.NoError(s.T(), err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedCode != fiber.StatusOK {
+				return
+			}
+
+			assert.NotContains(s.T(), string(body), "db-password")
+			assert.NotContains(s.T(), string(body), "jwt-secret")
+			assert.NotContains(s.T(), string(body), secret)
+
+			var payload map[string]map[string]interface{}
+			require.NoError(s.T(), json.Unmarshal(body, &payload))
+			assert.Equal(s.T(), "db.internal", payload["database"]["host"])
+			assert.Equal(s.T(), redactedValue, payload["database"]["password"])
+			assert.Equal(s.T(), redactedValue, payload["security"]["jwt"].(map[string]interface{})["secret"])
+			assert.Equal(s.T(), "withdraw-service", payload["security"]["jwt"].(map[string]interface{})["issuer"])
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestReadinessRoute_TableDriven() {
+	healthy := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name           string
+		checks         readinessChecks
+		expectedCode   int
+		expectedStatus string
+		expectedChecks map[string]interface{}
+	}{
+		{
+			name:           "all dependencies healthy",
+			checks:         readinessChecks{{Name: "db_auth", Ping: healthy}, {Name: "db_wallet", Ping: healthy}, {Name: "redis", Ping: healthy}},
+			expectedCode:   fiber.StatusOK,
+			expectedStatus: "ok",
+			expectedChecks: map[string]interface{}{"db_auth": "ok", "db_wallet": "ok", "redis": "ok"},
+		},
+		{
+			name:           "failing database reports per dependency status",
+			checks:         readinessChecks{{Name: "db_auth", Ping: healthy}, {Name: "db_wallet", Ping: failing}, {Name: "redis", Ping: healthy}},
+			expectedCode:   fiber.StatusServiceUnavailable,
+			expectedStatus: "unavailable",
+			expectedChecks: map[string]interface{}{"db_auth": "ok", "db_wallet": "down", "redis": "ok"},
+		},
+		{
+			name:           "hanging redis is cut off by timeout",
+			checks:         readinessChecks{{Name: "db_wallet", Ping: healthy}, {Name: "redis", Ping: hanging}},
+			expectedCode:   fiber.StatusServiceUnavailable,
+			expectedStatus: "unavailable",
+			expectedChecks: map[string]interface{}{"db_wallet": "ok", "redis": "down"},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetDuration("health.readiness_timeout").Return(50 * time.Millisecond)
+			s.cfg.EXPECT().GetDuration("health.cache_ttl").Return(time.Duration(0))
+
+			fiberApp := fiber.New()
+			registerReadinessRoute(fiberApp, s.cfg, tc.checks)
+
+			code, payload := probeReadiness(s.T(), fiberApp)
+			assert.Equal(s.T(), tc.expectedCode, code)
+			assert.Equal(s.T(), tc.expectedStatus, payload.Status)
+
+			statuses := make(map[string]interface{}, len(payload.Checks))
+			for name, check := range payload.Checks {
+				statuses[name] = check.Status
+				assert.False(s.T(), check.CheckedAt.IsZero(), "checked_at missing for %s", name)
+			}
+			assert.Equal(s.T(), tc.expectedChecks, statuses)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestReadinessRoute_CachesWithinTTL() {
+	var pings atomic.Int32
+	counting := func(context.Context) error {
+		pings.Add(1)
+		return nil
+	}
+
+	s.cfg.EXPECT().GetDuration("health.readiness_timeout").Return(50 * time.Millisecond)
+	s.cfg.EXPECT().GetDuration("health.cache_ttl").Return(time.Minute)
+
+	fiberApp := fiber.New()
+	registerReadinessRoute(fiberApp, s.cfg, readinessChecks{{Name: "db_wallet", Ping: counting}})
+
+	var firstCheckedAt time.Time
+	for i := 0; i < 3; i++ {
+		code, payload := probeReadiness(s.T(), fiberApp)
+		assert.Equal(s.T(), fiber.StatusOK, code)
+		if i == 0 {
+			firstCheckedAt = payload.Checks["db_wallet"].CheckedAt
+		}
+		assert.True(s.T(), firstCheckedAt.Equal(payload.Checks["db_wallet"].CheckedAt))
+	}
+	assert.EqualValues(s.T(), 1, pings.Load())
+}
+
+func (s *AppHelpersSuite) TestReadinessRoute_FailureFlipsAfterRefresh() {
+	var failing atomic.Bool
+	ping := func(context.Context) error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	const ttl = 200 * time.Millisecond
+	s.cfg.EXPECT().GetDuration("health.readiness_timeout").Return(50 * time.Millisecond)
+	s.cfg.EXPECT().GetDuration("health.cache_ttl").Return(ttl)
+
+	fiberApp := fiber.New()
+	registerReadinessRoute(fiberApp, s.cfg, readinessChecks{{Name: "redis", Ping: ping}})
+
+	code, _ := probeReadiness(s.T(), fiberApp)
+	require.Equal(s.T(), fiber.StatusOK, code)
+
+	failing.Store(true)
+	code, _ = probeReadiness(s.T(), fiberApp)
+	assert.Equal(s.T(), fiber.StatusOK, code, "cached result should be served until the ttl expires")
+
+	// The first request past the ttl still gets the stale result and triggers a
+	// background refresh; later requests see the new one.
+	time.Sleep(ttl)
+	code, _ = probeReadiness(s.T(), fiberApp)
+	assert.Equal(s.T(), fiber.StatusOK, code)
+
+	assert.Eventually(s.T(), func() bool {
+		code, payload := probeReadiness(s.T(), fiberApp)
+		return code == fiber.StatusServiceUnavailable && payload.Checks["redis"].Status == "down"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *AppHelpersSuite) TestReadinessRoute_ExpiredCacheServesStaleWhileRefreshing() {
+	var (
+		pings   atomic.Int32
+		release = make(chan struct{})
+	)
+	ping := func(ctx context.Context) error {
+		if pings.Add(1) > 1 {
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+		}
+		return nil
+	}
+
+	const ttl = 50 * time.Millisecond
+	s.cfg.EXPECT().GetDuration("health.readiness_timeout").Return(time.Second)
+	s.cfg.EXPECT().GetDuration("health.cache_ttl").Return(ttl)
+
+	fiberApp := fiber.New()
+	registerReadinessRoute(fiberApp, s.cfg, readinessChecks{{Name: "db_wallet", Ping: ping}})
+
+	code, first := probeReadiness(s.T(), fiberApp)
+	require.Equal(s.T(), fiber.StatusOK, code)
+
+	// The refresh hangs until released, yet every probe answers from the stale cache
+	// and only one refresh is started.
+	time.Sleep(ttl)
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, payload := probeReadiness(s.T(), fiberApp)
+			assert.Equal(s.T(), fiber.StatusOK, code)
+			assert.True(s.T(), first.Checks["db_wallet"].CheckedAt.Equal(payload.Checks["db_wallet"].CheckedAt))
+		}()
+	}
+	wg.Wait()
+	assert.Eventually(s.T(), func() bool { return pings.Load() == 2 }, time.Second, 5*time.Millisecond)
+	assert.EqualValues(s.T(), 2, pings.Load())
+
+	close(release)
+	assert.Eventually(s.T(), func() bool {
+		_, payload := probeReadiness(s.T(), fiberApp)
+		return payload.Checks["db_wallet"].CheckedAt.After(first.Checks["db_wallet"].CheckedAt)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *AppHelpersSuite) TestReadinessRoute_ConcurrentRefreshPingsOnce() {
+	var pings atomic.Int32
+	slow := func(context.Context) error {
+		pings.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+
+	s.cfg.EXPECT().GetDuration("health.readiness_timeout").Return(time.Second)
+	s.cfg.EXPECT().GetDuration("health.cache_ttl").Return(time.Minute)
+
+	fiberApp := fiber.New()
+	registerReadinessRoute(fiberApp, s.cfg, readinessChecks{{Name: "db_wallet", Ping: slow}})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, _ := probeReadiness(s.T(), fiberApp)
+			assert.Equal(s.T(), fiber.StatusOK, code)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(s.T(), 1, pings.Load())
+}
+
+type readinessPayload struct {
+	Status string                     `json:"status"`
+	Checks map[string]readinessStatus `json:"checks"`
+}
+
+func probeReadiness(t *testing.T, fiberApp *fiber.App) (int, readinessPayload) {
+	t.Helper()
+
+	resp, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var payload readinessPayload
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	return resp.StatusCode, payload
+}
+
+func (s *AppHelpersSuite) TestDBPoolSettings_TableDriven() {
+	tests := []struct {
+		name            string
+		useModuleConfig bool
+		setupMock       func()
+		expected        dbPoolSettings
+	}{
+		{
+			name:            "module keys override global keys",
+			useModuleConfig: true,
+			setupMock: func() {
+				s.cfg.EXPECT().IsSet("database.wallet.max_open_conns").Return(true)
+				s.cfg.EXPECT().GetInt("database.wallet.max_open_conns").Return(40)
+				s.cfg.EXPECT().IsSet("database.wallet.max_idle_conns").Return(true)
+				s.cfg.EXPECT().GetInt("database.wallet.max_idle_conns").Return(10)
+				s.cfg.EXPECT().IsSet("database.wallet.conn_max_lifetime").Return(true)
+				s.cfg.EXPECT().GetDuration("database.wallet.conn_max_lifetime").Return(15 * time.Minute)
+			},
+			expected: dbPoolSettings{MaxOpenConns: 40, MaxIdleConns: 10, ConnMaxLifetime: 15 * time.Minute},
+		},
+		{
+			name:            "single binary reads global keys",
+			useModuleConfig: false,
+			setupMock: func() {
+				s.cfg.EXPECT().IsSet("database.max_open_conns").Return(true)
+				s.cfg.EXPECT().GetInt("database.max_open_conns").Return(8)
+				s.cfg.EXPECT().IsSet("database.max_idle_conns").Return(true)
+				s.cfg.EXPECT().GetInt("database.max_idle_conns").Return(20)
+				s.cfg.EXPECT().IsSet("database.conn_max_lifetime").Return(true)
+				s.cfg.EXPECT().GetDuration("database.conn_max_lifetime").Return(time.Hour)
+			},
+			expected: dbPoolSettings{MaxOpenConns: 8, MaxIdleConns: 8, ConnMaxLifetime: time.Hour},
+		},
+		{
+			name:            "defaults when unset",
+			useModuleConfig: false,
+			setupMock: func() {
+				s.cfg.EXPECT().IsSet(mock.Anything).Return(false)
+				s.cfg.EXPECT().GetInt(mock.Anything).Return(0)
+				s.cfg.EXPECT().GetDuration(mock.Anything).Return(0)
+			},
+			expected: dbPoolSettings{MaxOpenConns: defaultDBMaxOpenConns, MaxIdleConns: defaultDBMaxIdleConns, ConnMaxLifetime: defaultDBConnMaxLifetime},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			tc.setupMock()
+
+			settings := loadDBPoolSettings(s.cfg, "wallet", tc.useModuleConfig)
+			assert.Equal(s.T(), tc.expected, settings)
+
+			sqlDB, _, err := sqlmock.New()
+			require.NoError(s.T(), err)
+			db := sqlx.NewDb(sqlDB, "sqlmock")
+			defer db.Close()
+
+			applyDBPoolSettings(db, settings)
+			assert.Equal(s.T(), tc.expected.MaxOpenConns, db.Stats().MaxOpenConnections)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestWalletReplica_TableDriven() {
+	tests := []struct {
+		name      string
+		setupMock func()
+		expectDSN string
+		expectOK  bool
+	}{
+		{
+			name: "configured replica overrides host and inherits the rest",
+			setupMock: func() {
+				s.cfg.EXPECT().IsSet("database.wallet.replica.host").Return(true)
+				s.cfg.EXPECT().GetString("database.wallet.replica.host").Return("replica-host")
+				s.cfg.EXPECT().IsSet("database.wallet.replica.port").Return(true)
+				s.cfg.EXPECT().GetInt("database.wallet.replica.port").Return(5434)
+				s.cfg.EXPECT().IsSet(mock.Anything).Return(false)
+				s.cfg.EXPECT().GetString("DATABASE_USER").Return("admin")
+				s.cfg.EXPECT().GetString("DATABASE_PASSWORD").Return("secret")
+				s.cfg.EXPECT().GetString("DATABASE_NAME").Return("wallet_db")
+				s.cfg.EXPECT().GetString("DATABASE_SSL_MODE").Return("disable")
+			},
+			expectDSN: "host=replica-host port=5434 user=admin password=secret dbname=wallet_db sslmode=disable",
+			expectOK:  true,
+		},
+		{
+			name: "falls back to primary when replica host is unset",
+			setupMock: func() {
+				s.cfg.EXPECT().IsSet(mock.Anything).Return(false)
+				s.cfg.EXPECT().GetString("DATABASE_HOST").Return("primary-host")
+			},
+			expectOK: false,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			tc.setupMock()
+
+			dsn, ok := walletReplicaDSN(s.cfg, "")
+			assert.Equal(s.T(), tc.expectOK, ok)
+			assert.Equal(s.T(), tc.expectDSN, dsn)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvideWalletReplicaPostgresSQLX_FallsBackToPrimary() {
+	s.cfg.EXPECT().IsSet(mock.Anything).Return(false)
+	s.cfg.EXPECT().GetString(mock.Anything).Return("")
+
+	sqlDB, _, err := sqlmock.New()
+	require.NoError(s.T(), err)
+	primary := sqlx.NewDb(sqlDB, "sqlmock")
+	defer primary.Close()
+
+	replica, err := provideWalletReplicaPostgresSQLX(walletReplicaIn{Config: s.cfg, Bin: "", Primary: primary})
+	require.NoError(s.T(), err)
+	assert.Same(s.T(), primary, replica)
+}
+
+func (s *AppHelpersSuite) TestAppStopTimeout_TableDriven() {
+	tests := []struct {
+		name            string
+		shutdownTimeout time.Duration
+		expected        time.Duration
+	}{
+		{name: "defaults when unset", expected: defaultShutdownTimeout + stopTimeoutMargin},
+		{name: "follows configured drain", shutdownTimeout: time.Minute, expected: time.Minute + stopTimeoutMargin},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetDuration("server.shutdown_timeout").Return(tc.shutdownTimeout)
+
+			assert.Equal(s.T(), tc.expected, appStopTimeout(s.cfg))
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterLifecycle_ShutdownTimeout_TableDriven() {
+	const (
+		quickDelay = 100 * time.Millisecond
+		slowDelay  = 600 * time.Millisecond
+	)
+
+	tests := []struct {
+		name            string
+		shutdownTimeout time.Duration
+		expectDrained   bool
+		expectLog       string
+	}{
+		{
+			name:            "in-flight requests complete within timeout",
+			shutdownTimeout: 5 * time.Second,
+			expectDrained:   true,
+			expectLog:       `"msg":"fiber server drained in-flight requests","drained":2`,
+		},
+		{
+			name:            "shutdown stops waiting once timeout elapses",
+			shutdownTimeout: 300 * time.Millisecond,
+			expectDrained:   false,
+			expectLog:       `"msg":"fiber server shutdown timed out before draining","timeout":"300ms","in_flight":1,"drained":1`,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(s.T(), err)
+			port := listener.Addr().(*net.TCPAddr).Port
+			require.NoError(s.T(), listener.Close())
+
+			s.cfg.EXPECT().GetInt("server.port").Return(port)
+			s.cfg.EXPECT().GetDuration("server.shutdown_timeout").Return(tc.shutdownTimeout)
+			s.cfg.EXPECT().GetString(mock.MatchedBy(func(key string) bool {
+				return strings.HasPrefix(key, "server.tls.")
+			})).Return("")
+
+			tracker, err := middlewares.NewInFlightTracker(nil)
+			require.NoError(s.T(), err)
+
+			started := make(chan struct{}, 2)
+			app := fiber.New()
+			app.Use(middlewares.NewHTTPInFlightMiddleware(tracker))
+			app.Get("/slow", func(c fiber.Ctx) error {
+				delay, err := time.ParseDuration(c.Query("delay"))
+				if err != nil {
+					return err
+				}
+				started <- struct{}{}
+				time.Sleep(delay)
+				return c.SendString("done")
+			})
+
+			sqlDB, dbMock, err := sqlmock.New()
+			require.NoError(s.T(), err)
+			dbMock.ExpectClose()
+
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+			lifecycle := fxtest.NewLifecycle(s.T())
+			registerConnectionClose(lifecycle, lifecycleDatabasesIn{WalletDB: sqlx.NewDb(sqlDB, "sqlmock")})
+			registerLifecycle(lifecycle, app, s.cfg, logger, tracker)
+			lifecycle.RequireStart()
+
+			type result struct {
+				status int
+				err    error
+			}
+			responseCh := make(chan result, 2)
+			for _, delay := range []time.Duration{quickDelay, slowDelay} {
+				go func() {
+					resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/slow?delay=%s", port, delay))
+					if err != nil {
+						responseCh <- result{err: err}
+						return
+					}
+					defer resp.Body.Close()
+					responseCh <- result{status: resp.StatusCode}
+				}()
+			}
+
+			for range 2 {
+				select {
+				case <-started:
+				case <-time.After(2 * time.Second):
+					s.FailNow("slow request never reached the handler")
+				}
+			}
+
+			stopStarted := time.Now()
+			stopErr := lifecycle.Stop(context.Background())
+			stopElapsed := time.Since(stopStarted)
+
+			assert.NoError(s.T(), dbMock.ExpectationsWereMet())
+			assert.Contains(s.T(), logs.String(), `"msg":"fiber server shutdown started","in_flight":2`)
+			assert.Contains(s.T(), logs.String(), tc.expectLog)
+
+			if tc.expectDrained {
+				require.NoError(s.T(), stopErr)
+				for range 2 {
+					res := <-responseCh
+					require.NoError(s.T(), res.err)
+					assert.Equal(s.T(), fiber.StatusOK, res.status)
+				}
+				assert.NotContains(s.T(), logs.String(), "shutdown timed out")
+				return
+			}
+
+			require.ErrorIs(s.T(), stopErr, context.DeadlineExceeded)
+			assert.Less(s.T(), stopElapsed, slowDelay)
+			<-responseCh
+			<-responseCh
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterConnectionClose_ClosesAfterLaterHooks() {
+	sqlDB, dbMock, err := sqlmock.New()
+	require.NoError(s.T(), err)
+	dbMock.ExpectClose()
+
+	lifecycle := fxtest.NewLifecycle(s.T())
+	registerConnectionClose(lifecycle, lifecycleDatabasesIn{WalletDB: sqlx.NewDb(sqlDB, "sqlmock")})
+
+	// Stands in for a background loop registered later by a module.
+	var closedBeforeLoop bool
+	lifecycle.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			closedBeforeLoop = dbMock.ExpectationsWereMet() == nil
+			return nil
+		},
+	})
+
+	lifecycle.RequireStart()
+	lifecycle.RequireStop()
+
+	assert.False(s.T(), closedBeforeLoop, "pool closed before the background loop stopped")
+	assert.NoError(s.T(), dbMock.ExpectationsWereMet())
+}
+
+func (s *AppHelpersSuite) TestProvideLoginLockoutTracker_TableDriven() {
+	tests := []struct {
+		name         string
+		setupMock    func()
+		redisClient  *redis.Client
+		expectActive bool
+	}{
+		{
+			name: "disabled when threshold is zero",
+			setupMock: func() {
+				s.cfg.EXPECT().GetInt("security.login_lockout.threshold").Return(0)
+			},
+			redisClient: redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"}),
+		},
+		{
+			name: "disabled without redis client",
+			setupMock: func() {
+				s.cfg.EXPECT().GetInt("security.login_lockout.threshold").Return(5)
+			},
+		},
+		{
+			name: "enabled with threshold and redis",
+			setupMock: func() {
+				s.cfg.EXPECT().GetInt("security.login_lockout.threshold").Return(5)
+				s.cfg.EXPECT().GetDuration("security.login_lockout.cooldown").Return(0)
+			},
+			redisClient:  redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"}),
+			expectActive: true,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			tc.setupMock()
+
+			tracker := provideLoginLockoutTracker(s.cfg, tc.redisClient)
+			assert.Equal(s.T(), tc.expectActive, tracker != nil)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvideWalletNotFoundCache_TableDriven() {
+	tests := []struct {
+		name         string
+		ttl          time.Duration
+		redisClient  *redis.Client
+		expectActive bool
+	}{
+		{name: "disabled when ttl is zero", redisClient: redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})},
+		{name: "disabled without redis client", ttl: 5 * time.Second},
+		{name: "enabled with ttl and redis", ttl: 5 * time.Second, redisClient: redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"}), expectActive: true},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetDuration("inquiry.wallet_not_found_cache_ttl").Return(tc.ttl)
+
+			cache := provideWalletNotFoundCache(s.cfg, tc.redisClient)
+			assert.Equal(s.T(), tc.expectActive, cache != nil)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvidePasswordValidator_TableDriven() {
+	denylistPath := filepath.Join(s.T().TempDir(), "denylist.txt")
+	require.NoError(s.T(), os.WriteFile(denylistPath, []byte("Withdraw2026!\n"), 0o600))
+
+	expectClasses := func(set bool, enabled bool) {
+		for _, key := range []string{"require_upper", "require_lower", "require_digit", "require_symbol"} {
+			s.cfg.EXPECT().IsSet("security.password_policy." + key).Return(set)
+			if set {
+				s.cfg.EXPECT().GetBool("security.password_policy." + key).Return(enabled)
+			}
+		}
+	}
+
+	tests := []struct {
+		name       string
+		setupMock  func()
+		password   string
+		expectWeak bool
+		expectErr  bool
+	}{
+		{
+			name: "defaults reject a short single-class password",
+			setupMock: func() {
+				s.cfg.EXPECT().GetInt("security.password_policy.min_length").Return(0)
+				expectClasses(false, false)
+				s.cfg.EXPECT().GetString("security.password_policy.denylist_file").Return("")
+			},
+			password:   "password",
+			expectWeak: true,
+		},
+		{
+			name: "relaxed classes accept a long lowercase password",
+			setupMock: func() {
+				s.cfg.EXPECT().GetInt("security.password_policy.min_length").Return(8)
+				expectClasses(true, false)
+				s.cfg.EXPECT().GetString("security.password_policy.denylist_file").Return("")
+			},
+			password: "longenough",
+		},
+		{
+			name: "denylist file is loaded",
+			setupMock: func() {
+				s.cfg.EXPECT().GetInt("security.password_policy.min_length").Return(0)
+				expectClasses(false, false)
+				s.cfg.EXPECT().GetString("security.password_policy.denylist_file").Return(denylistPath)
+			},
+			password:   "Withdraw2026!",
+			expectWeak: true,
+		},
+		{
+			name: "missing denylist file fails startup",
+			setupMock: func() {
+				s.cfg.EXPECT().GetInt("security.password_policy.min_length").Return(0)
+				expectClasses(false, false)
+				s.cfg.EXPECT().GetString("security.password_policy.denylist_file").Return(denylistPath + ".missing")
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			tc.setupMock()
+
+			validator, err := providePasswordValidator(s.cfg)
+			if tc.expectErr {
+				require.Error(s.T(), err)
+				assert.Nil(s.T(), validator)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			if tc.expectWeak {
+				assert.ErrorIs(s.T(), validator.Validate(tc.password), vo.ErrWeakPassword)
+				return
+			}
+			assert.NoError(s.T(), validator.Validate(tc.password))
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestWaitForRedis_TableDriven() {
+	errConnRefused := errors.New("connection refused")
+	policy := redisStartupPolicy{MaxAttempts: 3, Backoff: time.Millisecond, PingTimeout: time.Second}
+
+	tests := []struct {
+		name        string
+		failures    int
+		ctx         func() context.Context
+		expectCalls int
+		expectErr   error
+	}{
+		{
+			name:        "succeeds on first ping",
+			expectCalls: 1,
+		},
+		{
+			name:        "retries until ping succeeds",
+			failures:    2,
+			expectCalls: 3,
+		},
+		{
+			name:        "fails fast after max attempts",
+			failures:    5,
+			expectCalls: 3,
+			expectErr:   errConnRefused,
+		},
+		{
+			name:     "stops retrying when context is canceled",
+			failures: 5,
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			expectCalls: 1,
+			expectErr:   context.Canceled,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			ctx := context.Background()
+			if tc.ctx != nil {
+				ctx = tc.ctx()
+			}
+
+			pinger := &flakyRedisPinger{failures: tc.failures, err: errConnRefused}
+			err := waitForRedis(ctx, pinger, policy, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			assert.Equal(s.T(), tc.expectCalls, pinger.calls)
+			if tc.expectErr != nil {
+				require.Error(s.T(), err)
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+				return
+			}
+			assert.NoError(s.T(), err)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestLoadRedisStartupPolicy_TableDriven() {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		backoff     time.Duration
+		pingTimeout time.Duration
+		expected    redisStartupPolicy
+	}{
+		{
+			name:     "uses defaults when not configured",
+			expected: redisStartupPolicy{MaxAttempts: defaultRedisStartupMaxAttempts, Backoff: defaultRedisStartupBackoff, PingTimeout: defaultRedisStartupPingTimeout},
+		},
+		{
+			name:        "uses configured values",
+			maxAttempts: 2,
+			backoff:     time.Second,
+			pingTimeout: 3 * time.Second,
+			expected:    redisStartupPolicy{MaxAttempts: 2, Backoff: time.Second, PingTimeout: 3 * time.Second},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetInt("redis.startup.max_attempts").Return(tc.maxAttempts)
+			s.cfg.EXPECT().GetDuration("redis.startup.backoff").Return(tc.backoff)
+			s.cfg.EXPECT().GetDuration("redis.startup.ping_timeout").Return(tc.pingTimeout)
+
+			assert.Equal(s.T(), tc.expected, loadRedisStartupPolicy(s.cfg))
+		})
+	}
+}
+
+type flakyRedisPinger struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (p *flakyRedisPinger) Ping(ctx context.Context) *redis.StatusCmd {
+	p.calls++
+	cmd := redis.NewStatusCmd(ctx, "ping")
+	if p.calls <= p.failures {
+		cmd.SetErr(p.err)
+		return cmd
+	}
+	cmd.SetVal("PONG")
+	return cmd
+}
+
+func (s *AppHelpersSuite) TestProvideWithdrawAuditor_TableDriven() {
+	tests := []struct {
+		name      string
+		sink      string
+		assertion func(sharedaudit.Auditor, error)
+	}{
+		{
+			name: "defaults to log auditor",
+			assertion: func(auditor sharedaudit.Auditor, err error) {
+				require.NoError(s.T(), err)
+				assert.IsType(s.T(), &sharedaudit.SlogAuditor{}, auditor)
+			},
+		},
+		{
+			name: "db sink writes to audit_log",
+			sink: "DB",
+			assertion: func(auditor sharedaudit.Auditor, err error) {
+				require.NoError(s.T(), err)
+				assert.IsType(s.T(), &sharedaudit.SQLXAuditor{}, auditor)
+			},
+		},
+		{
+			name: "none disables auditing",
+			sink: "none",
+			assertion: func(auditor sharedaudit.Auditor, err error) {
+				require.NoError(s.T(), err)
+				assert.Nil(s.T(), auditor)
+			},
+		},
+		{
+			name: "unknown sink fails",
+			sink: "kafka",
+			assertion: func(auditor sharedaudit.Auditor, err error) {
+				require.Error(s.T(), err)
+				assert.Nil(s.T(), auditor)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetString("audit.withdraw.sink").Return(tc.sink)
+
+			tc.assertion(provideWithdrawAuditor(s.cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil))
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvideUIDGenerator_TableDriven() {
+	tests := []struct {
+		name         string
+		strategy     string
+		nodeID       string
+		hostname     string
+		hostnameErr  error
+		expectNodeID int64
+		expectErr    string
+	}{
+		{name: "defaults to uuidv7"},
+		{name: "explicit node id", strategy: "snowflake", nodeID: "42", hostname: "withdraw-api-7", expectNodeID: 42},
+		{name: "derives node id from pod ordinal", strategy: "snowflake", hostname: "withdraw-api-7", expectNodeID: 7},
+		{name: "hashes other hostnames", strategy: "Snowflake", hostname: "withdraw-api-5d8f7c9b4-x2k4z", expectNodeID: deriveSnowflakeNodeID("withdraw-api-5d8f7c9b4-x2k4z")},
+		{name: "out of range ordinal is hashed", strategy: "snowflake", hostname: "worker-4096", expectNodeID: deriveSnowflakeNodeID("worker-4096")},
+		{name: "rejects node id above range", strategy: "snowflake", nodeID: "1024", expectErr: "between 0 and 1023"},
+		{name: "rejects non-numeric node id", strategy: "snowflake", nodeID: "node-a", expectErr: "between 0 and 1023"},
+		{name: "fails without hostname", strategy: "snowflake", hostnameErr: errors.New("no hostname"), expectErr: "hostname is unavailable"},
+		{name: "rejects unknown strategy", strategy: "ulid", expectErr: `unknown strategy "ulid"`},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetString("uid.strategy").Return(tc.strategy)
+			if strings.EqualFold(tc.strategy, "snowflake") {
+				s.cfg.EXPECT().GetString("uid.node_id").Return(tc.nodeID)
+			}
+
+			original := hostname
+			hostname = func() (string, error) { return tc.hostname, tc.hostnameErr }
+			defer func() { hostname = original }()
+
+			generator, err := provideUIDGenerator(s.cfg)
+			if tc.expectErr != "" {
+				require.ErrorContains(s.T(), err, tc.expectErr)
+				return
+			}
+			require.NoError(s.T(), err)
+
+			id, err := generator.Generate(context.Background())
+			require.NoError(s.T(), err)
+			if tc.strategy == "" {
+				assert.NoError(s.T(), uuid.Validate(id))
+				return
+			}
+
+			parsed, err := snowflake.ParseString(id)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectNodeID, parsed.Node())
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvidePayoutClient_TableDriven() {
+	tests := []struct {
+		name           string
+		baseURL        string
+		secret         string
+		breakerSet     bool
+		breakerEnabled bool
+		assertion      func(services.PayoutClient, *prometheus.Registry, error)
+	}{
+		{
+			name: "disabled without base url",
+			assertion: func(client services.PayoutClient, _ *prometheus.Registry, err error) {
+				require.NoError(s.T(), err)
+				assert.Nil(s.T(), client)
+			},
+		},
+		{
+			name:    "missing secret fails",
+			baseURL: "https://payout.example.com",
+			assertion: func(client services.PayoutClient, _ *prometheus.Registry, err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "signing secret")
+				assert.Nil(s.T(), client)
+			},
+		},
+		{
+			name:       "builds http client",
+			baseURL:    "https://payout.example.com",
+			secret:     "payout-secret",
+			breakerSet: true,
+			assertion: func(client services.PayoutClient, _ *prometheus.Registry, err error) {
+				require.NoError(s.T(), err)
+				assert.IsType(s.T(), &payout.HTTPClient{}, client)
+			},
+		},
+		{
+			name:           "wraps client in circuit breaker",
+			baseURL:        "https://payout.example.com",
+			secret:         "payout-secret",
+			breakerSet:     true,
+			breakerEnabled: true,
+			assertion: func(client services.PayoutClient, registry *prometheus.Registry, err error) {
+				require.NoError(s.T(), err)
+				require.IsType(s.T(), &payout.CircuitBreaker{}, client)
+				assert.Equal(s.T(), payout.BreakerClosed, client.(*payout.CircuitBreaker).State())
+
+				families, err := registry.Gather()
+				require.NoError(s.T(), err)
+				names := make([]string, 0, len(families))
+				for _, family := range families {
+					names = append(names, family.GetName())
+				}
+				assert.Contains(s.T(), names, "payout_circuit_breaker_state")
+			},
+		},
+		{
+			name:    "breaker is on by default",
+			baseURL: "https://payout.example.com",
+			secret:  "payout-secret",
+			assertion: func(client services.PayoutClient, _ *prometheus.Registry, err error) {
+				require.NoError(s.T(), err)
+				assert.IsType(s.T(), &payout.CircuitBreaker{}, client)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetString("payout.base_url").Return(tc.baseURL)
+			if tc.baseURL != "" {
+				s.cfg.EXPECT().GetString("payout.secret").Return(tc.secret)
+				s.cfg.EXPECT().GetDuration("payout.timeout").Return(5 * time.Second)
+				s.cfg.EXPECT().GetInt("payout.max_retries").Return(2)
+				s.cfg.EXPECT().GetDuration("payout.retry_backoff").Return(100 * time.Millisecond)
+				s.cfg.EXPECT().GetInt("payout.rate_per_second").Return(10)
+			}
+			if tc.secret != "" {
+				s.cfg.EXPECT().IsSet("payout.circuit_breaker.enabled").Return(tc.breakerSet)
+				if tc.breakerSet {
+					s.cfg.EXPECT().GetBool("payout.circuit_breaker.enabled").Return(tc.breakerEnabled)
+				}
+				if !tc.breakerSet || tc.breakerEnabled {
+					s.cfg.EXPECT().GetFloat64("payout.circuit_breaker.failure_ratio").Return(0.5)
+					s.cfg.EXPECT().GetInt("payout.circuit_breaker.min_requests").Return(10)
+					s.cfg.EXPECT().GetDuration("payout.circuit_breaker.window").Return(time.Minute)
+					s.cfg.EXPECT().GetDuration("payout.circuit_breaker.open_timeout").Return(30 * time.Second)
+				}
+			}
+
+			registry := prometheus.NewRegistry()
+			client, err := providePayoutClient(s.cfg, registry)
+			tc.assertion(client, registry, err)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvideEventPublisher_TableDriven() {
+	tests := []struct {
+		name      string
+		publisher string
+		natsURL   string
+		assertion func(sharedevents.Publisher, error)
+	}{
+		{
+			name: "defaults to no-op publisher",
+			assertion: func(publisher sharedevents.Publisher, err error) {
+				require.NoError(s.T(), err)
+				assert.IsType(s.T(), sharedevents.NopPublisher{}, publisher)
+			},
+		},
+		{
+			name:      "none drops events",
+			publisher: " None ",
+			assertion: func(publisher sharedevents.Publisher, err error) {
+				require.NoError(s.T(), err)
+				assert.IsType(s.T(), sharedevents.NopPublisher{}, publisher)
+			},
+		},
+		{
+			name:      "nats requires url",
+			publisher: "nats",
+			assertion: func(publisher sharedevents.Publisher, err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "nats url is required")
+				assert.Nil(s.T(), publisher)
+			},
+		},
+		{
+			name:      "unknown publisher fails",
+			publisher: "kafka",
+			assertion: func(publisher sharedevents.Publisher, err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "unknown events publisher")
+				assert.Nil(s.T(), publisher)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetString("events.publisher").Return(tc.publisher)
+			if tc.publisher == "nats" {
+				s.cfg.EXPECT().IsSet("events.nats.subject_prefix").Return(false)
+				s.cfg.EXPECT().GetString("events.nats.url").Return(tc.natsURL)
+				s.cfg.EXPECT().GetDuration("events.nats.connect_timeout").Return(time.Second)
+			}
+
+			lifecycle := fxtest.NewLifecycle(s.T())
+			tc.assertion(provideEventPublisher(lifecycle, s.cfg, slog.New(slog.NewTextHandler(io.Discard, nil))))
+			lifecycle.RequireStart().RequireStop()
+		})
+	}
+}
+
+// singleRowOutboxStore serves one outbox row until it is marked sent.
+type singleRowOutboxStore struct {
+	mu   sync.Mutex
+	sent bool
+}
+
+func (o *singleRowOutboxStore) ClaimUnsent(context.Context, int, time.Duration) ([]sharedevents.OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.sent {
+		return nil, nil
+	}
+	return []sharedevents.OutboxMessage{{ID: 1, Payload: []byte(`{"type":"withdrawal.completed","transaction_id":"ref-1"}`)}}, nil
+}
+
+func (o *singleRowOutboxStore) MarkSent(context.Context, int64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.sent = true
+	return nil
+}
+
+func (o *singleRowOutboxStore) MarkFailed(context.Context, int64, string) error {
+	return nil
+}
+
+func (o *singleRowOutboxStore) MarkDead(context.Context, int64, string) error {
+	return nil
+}
+
+func (o *singleRowOutboxStore) isSent() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.sent
+}
+
+func (s *AppHelpersSuite) TestRegisterOutboxRelay_RunsUntilStop() {
+	s.cfg.EXPECT().GetDuration("events.outbox.poll_interval").Return(time.Millisecond)
+	s.cfg.EXPECT().GetInt("events.outbox.batch_size").Return(0)
+	s.cfg.EXPECT().GetDuration("events.outbox.claim_lease").Return(0)
+
+	store := &singleRowOutboxStore{}
+	relay := provideOutboxRelay(s.cfg, store, sharedevents.NopPublisher{}, nil)
+
+	lifecycle := fxtest.NewLifecycle(s.T())
+	registerOutboxRelay(lifecycle, relay)
+	assert.False(s.T(), store.isSent())
+
+	lifecycle.RequireStart()
+	require.Eventually(s.T(), store.isSent, time.Second, time.Millisecond)
+	lifecycle.RequireStop()
+}
+
+func (s *AppHelpersSuite) TestRegisterIdempotencyReconciler_RunsUntilStop() {
+	s.cfg.EXPECT().GetDuration("idempotency.dead_letter.reconcile_interval").Return(time.Millisecond)
+	s.cfg.EXPECT().GetInt("idempotency.dead_letter.batch_size").Return(2)
+
+	var calls atomic.Int32
+	queue := idempotencymocks.NewDeadLetterQueue(s.T())
+	queue.EXPECT().Reconcile(mock.Anything, 2).RunAndReturn(func(context.Context, int) (int, error) {
+		if calls.Add(1) == 1 {
+			return 1, nil
+		}
+		return 0, errors.New("db down")
+	})
+
+	lifecycle := fxtest.NewLifecycle(s.T())
+	registerIdempotencyReconciler(lifecycle, s.cfg, queue, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.Zero(s.T(), calls.Load())
+
+	lifecycle.RequireStart()
+	// A failed pass is retried on the next tick instead of stopping the loop.
+	require.Eventually(s.T(), func() bool { return calls.Load() >= 3 }, time.Second, time.Millisecond)
+	lifecycle.RequireStop()
+}
+
+type payoutReconcilerFunc func(ctx context.Context, settleAfter time.Duration, limit int) (int, error)
+
+func (f payoutReconcilerFunc) ReconcilePendingWithdrawals(ctx context.Context, settleAfter time.Duration, limit int) (int, error) {
+	return f(ctx, settleAfter, limit)
+}
+
+func (s *AppHelpersSuite) TestRegisterPayoutReconciler_RunsUntilStop() {
+	s.cfg.EXPECT().GetDuration("payout.reconcile.interval").Return(time.Millisecond)
+	s.cfg.EXPECT().GetDuration("payout.reconcile.settle_after").Return(0)
+	s.cfg.EXPECT().GetInt("payout.reconcile.batch_size").Return(2)
+
+	var calls atomic.Int32
+	reconciler := payoutReconcilerFunc(func(_ context.Context, settleAfter time.Duration, limit int) (int, error) {
+		assert.Equal(s.T(), defaultPayoutReconcileSettleAfter, settleAfter)
+		assert.Equal(s.T(), 2, limit)
+		if calls.Add(1) == 1 {
+			return 1, nil
+		}
+		return 0, errors.New("provider down")
+	})
+
+	lifecycle := fxtest.NewLifecycle(s.T())
+	registerPayoutReconciler(lifecycle, s.cfg, reconciler, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.Zero(s.T(), calls.Load())
+
+	lifecycle.RequireStart()
+	// A failed pass is retried on the next tick instead of stopping the loop.
+	require.Eventually(s.T(), func() bool { return calls.Load() >= 3 }, time.Second, time.Millisecond)
+	lifecycle.RequireStop()
+}
+
+func (s *AppHelpersSuite) TestMigrationTargets_EmbeddedFS_TableDriven() {
+	tests := []struct {
+		bin           string
+		expectModules []string
+	}{
+		{bin: "", expectModules: []string{"wallet"}},
+		{bin: "withdraw", expectModules: []string{"wallet"}},
+		{bin: "inquiry", expectModules: []string{"auth", "wallet"}},
+	}
+
+	for _, tc := range tests {
+		s.Run("bin="+tc.bin, func() {
+			targets := migrationTargets(tc.bin)
+
+			modules := make([]string, 0, len(targets))
+			for _, target := range targets {
+				modules = append(modules, target.Module)
+
+				migrationsFS, err := fs.Sub(dbfiles.Migrations, target.Dir)
+				require.NoError(s.T(), err)
+
+				migrations, err := sharedmigration.Load(migrationsFS)
+				require.NoError(s.T(), err)
+				require.NotEmpty(s.T(), migrations, target.Dir)
+				assert.Equal(s.T(), int64(1), migrations[0].Version)
+			}
+			assert.Equal(s.T(), tc.expectModules, modules)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterLifecycle_TLS_TableDriven() {
+	certFile, keyFile, certPool := writeSelfSignedCert(s.T())
+
+	tests := []struct {
+		name        string
+		certFile    string
+		keyFile     string
+		minVersion  string
+		expectStart string
+	}{
+		{name: "serves https with self-signed certificate", certFile: certFile, keyFile: keyFile},
+		{name: "serves https with tls 1.3 minimum", certFile: certFile, keyFile: keyFile, minVersion: "1.3"},
+		{name: "rejects half configured tls", certFile: certFile, expectStart: "must be set together"},
+		{name: "rejects unknown min version", certFile: certFile, keyFile: keyFile, minVersion: "1.0", expectStart: "unsupported server.tls.min_version"},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(s.T(), err)
+			port := listener.Addr().(*net.TCPAddr).Port
+			require.NoError(s.T(), listener.Close())
+
+			s.cfg.EXPECT().GetInt("server.port").Return(port)
+			s.cfg.EXPECT().GetDuration("server.shutdown_timeout").Return(time.Second)
+			s.cfg.EXPECT().GetString("server.tls.cert_file").Return(tc.certFile)
+			s.cfg.EXPECT().GetString("server.tls.key_file").Return(tc.keyFile)
+			s.cfg.EXPECT().GetString("server.tls.min_version").Return(tc.minVersion)
+
+			app := fiber.New()
+			app.Get("/ping", func(c fiber.Ctx) error {
+				return c.SendString("pong")
+			})
+
+			lifecycle := fxtest.NewLifecycle(s.T())
+			registerLifecycle(lifecycle, app, s.cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+			startErr := lifecycle.Start(context.Background())
+			if tc.expectStart != "" {
+				require.Error(s.T(), startErr)
+				assert.Contains(s.T(), startErr.Error(), tc.expectStart)
+				return
+			}
+			require.NoError(s.T(), startErr)
+			defer lifecycle.RequireStop()
+
+			client := &http.Client{
+				Timeout:   2 * time.Second,
+				Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12}},
+			}
+			resp, err := client.Get(fmt.Sprintf("https://127.0.0.1:%d/ping", port))
+			require.NoError(s.T(), err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+			assert.Equal(s.T(), "pong", string(body))
+			require.NotNil(s.T(), resp.TLS)
+			if tc.minVersion == "1.3" {
+				assert.Equal(s.T(), uint16(tls.VersionTLS13), resp.TLS.Version)
+			}
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestProvideAdminServer_TableDriven() {
+	tests := []struct {
+		name           string
+		port           int
+		expectSeparate bool
+	}{
+		{name: "unset port falls back to the main app"},
+		{name: "negative port falls back to the main app", port: -1},
+		{name: "configured port builds a separate app", port: 9091, expectSeparate: true},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetInt("admin.port").Return(tc.port)
+			if tc.expectSeparate {
+				s.cfg.EXPECT().GetDuration("server.read_timeout").Return(time.Duration(0))
+				s.cfg.EXPECT().GetDuration("server.write_timeout").Return(time.Duration(0))
+				s.cfg.EXPECT().GetStringSlice("server.trusted_proxies").Return(nil)
+				s.cfg.EXPECT().GetBool("logging.http_body.enabled").Return(false)
+				s.cfg.EXPECT().GetStringSlice("logging.http_body.redact_fields").Return(nil)
+				s.cfg.EXPECT().GetInt("logging.http_body.max_bytes").Return(0)
+				s.cfg.EXPECT().GetFloat64("logging.http_request.sample_rate").Return(0)
+				s.cfg.EXPECT().GetDuration("logging.http_request.slow_threshold").Return(time.Duration(0))
+			}
+
+			mainApp := fiber.New()
+			admin, err := provideAdminServer(s.cfg, mainApp, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			require.NoError(s.T(), err)
+
+			require.NotNil(s.T(), admin)
+			assert.Equal(s.T(), tc.expectSeparate, admin.Separate)
+			if tc.expectSeparate {
+				assert.NotSame(s.T(), mainApp, admin.App)
+				assert.Equal(s.T(), tc.port, admin.Port)
+			} else {
+				assert.Same(s.T(), mainApp, admin.App)
+			}
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterAdminLifecycle_SeparatesRoutes() {
+	reservePort := func() int {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(s.T(), err)
+		port := listener.Addr().(*net.TCPAddr).Port
+		require.NoError(s.T(), listener.Close())
+		return port
+	}
+	mainPort, adminPort := reservePort(), reservePort()
+
+	s.cfg.EXPECT().GetInt("server.port").Return(mainPort)
+	s.cfg.EXPECT().GetDuration("server.shutdown_timeout").Return(time.Second).Times(2)
+	s.cfg.EXPECT().GetString("server.tls.cert_file").Return("")
+	s.cfg.EXPECT().GetString("server.tls.key_file").Return("")
+	s.cfg.EXPECT().GetString("server.tls.min_version").Return("")
+
+	mainApp := fiber.New()
+	mainApp.Get("/ping", func(c fiber.Ctx) error {
+		return c.SendString("pong")
+	})
+	adminApp := fiber.New()
+	adminApp.Get("/metrics", func(c fiber.Ctx) error {
+		return c.SendString("metrics")
+	})
+	admin := &adminServer{App: adminApp, Port: adminPort, Separate: true}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	lifecycle := fxtest.NewLifecycle(s.T())
+	registerLifecycle(lifecycle, mainApp, s.cfg, logger, nil)
+	registerAdminLifecycle(lifecycle, admin, s.cfg, logger)
+	lifecycle.RequireStart()
+	defer lifecycle.RequireStop()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	tests := []struct {
+		name           string
+		port           int
+		path           string
+		expectedStatus int
+	}{
+		{name: "main port serves the api", port: mainPort, path: "/ping", expectedStatus: fiber.StatusOK},
+		{name: "main port does not serve metrics", port: mainPort, path: "/metrics", expectedStatus: fiber.StatusNotFound},
+		{name: "admin port serves metrics", port: adminPort, path: "/metrics", expectedStatus: fiber.StatusOK},
+		{name: "admin port does not serve the api", port: adminPort, path: "/ping", expectedStatus: fiber.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d%s", tc.port, tc.path))
+			require.NoError(s.T(), err)
+			defer resp.Body.Close()
+			assert.Equal(s.T(), tc.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func (s *AppHelpersSuite) TestRegisterAdminLifecycle_NoopWhenShared() {
+	lifecycle := fxtest.NewLifecycle(s.T())
+	registerAdminLifecycle(lifecycle, &adminServer{App: fiber.New()}, s.cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// The strict config mock fails the test if the hook reads any key.
+	lifecycle.RequireStart()
+	lifecycle.RequireStop()
+}
+
+func writeSelfSignedCert(t *testing.T) (string, string, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+
+	return certFile, keyFile, pool
+}
+
+func TestAppHelpersSuite(t *testing.T) {
+	suite.Run(t, new(AppHelpersSuite))
+}
+
+func (s *AppHelpersSuite) TestProvideTracer_TableDriven() {
+	tests := []struct {
+		name      string
+		enabled   bool
+		endpoint  string
+		assertion func(trace.Tracer, error)
+	}{
+		{
+			name: "disabled returns no tracer",
+			assertion: func(tracer trace.Tracer, err error) {
+				require.NoError(s.T(), err)
+				assert.Nil(s.T(), tracer)
+			},
+		},
+		{
+			name:    "enabled requires endpoint",
+			enabled: true,
+			assertion: func(tracer trace.Tracer, err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "tracing.otlp.endpoint is required")
+				assert.Nil(s.T(), tracer)
+			},
+		},
+		{
+			name:     "enabled records spans through sdk provider",
+			enabled:  true,
+			endpoint: "127.0.0.1:4318",
+			assertion: func(tracer trace.Tracer, err error) {
+				require.NoError(s.T(), err)
+				require.NotNil(s.T(), tracer)
+
+				_, span := tracer.Start(context.Background(), "probe")
+				defer span.End()
+				assert.True(s.T(), span.SpanContext().IsValid())
+				assert.True(s.T(), span.IsRecording())
+			},
+		},
+	}
+
+	previous := otel.GetTracerProvider()
+	s.T().Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.cfg.EXPECT().GetBool("tracing.enabled").Return(tc.enabled)
+			if tc.enabled {
+				s.cfg.EXPECT().GetString("tracing.service_name").Return("withdraw-api")
+				s.cfg.EXPECT().GetString("tracing.otlp.endpoint").Return(tc.endpoint)
+			}
+			if tc.endpoint != "" {
+				s.cfg.EXPECT().GetBool("tracing.otlp.insecure").Return(true)
+				s.cfg.EXPECT().IsSet("tracing.sample_ratio").Return(true)
+				s.cfg.EXPECT().GetFloat64("tracing.sample_ratio").Return(1)
+			}
+
+			lifecycle := fxtest.NewLifecycle(s.T())
+			tc.assertion(provideTracer(lifecycle, s.cfg))
+			// Stop flushes through the exporter; nothing listens on the endpoint, so give it a
+			// short deadline and only require the hook to return.
+			lifecycle.RequireStart()
+			stopCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			_ = lifecycle.Stop(stopCtx)
+		})
+	}
 }
@@ -5,4 +5,5 @@ type UserAuth struct {
 	Email        string
 	PasswordHash string
 	Status       string
+	Scopes       []string
 }
@@ -3,3 +3,4 @@ package vo
 import "errors"
 
 var ErrInvalidCredentials = errors.New("invalid credentials")
+var ErrAccountLocked = errors.New("account locked")
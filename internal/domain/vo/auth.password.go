@@ -0,0 +1,22 @@
+package vo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrWeakPassword = errors.New("weak password")
+
+// WeakPasswordError lists the password policy criteria a new password did not meet.
+type WeakPasswordError struct {
+	Unmet []string
+}
+
+func (e *WeakPasswordError) Error() string {
+	return fmt.Sprintf("weak password: unmet %s", strings.Join(e.Unmet, ", "))
+}
+
+func (e *WeakPasswordError) Unwrap() error {
+	return ErrWeakPassword
+}
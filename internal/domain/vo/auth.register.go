@@ -0,0 +1,17 @@
+package vo
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrEmailAlreadyRegistered = errors.New("email already registered")
+var ErrInvalidEmail = errors.New("invalid email")
+
+type AuthRegistered struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	WalletID  string    `json:"wallet_id"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,71 @@
+package vo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const defaultCurrencyExponent = 2
+
+var (
+	ErrMalformedAmount  = errors.New("malformed decimal amount")
+	ErrAmountTooPrecise = errors.New("amount has too many fractional digits")
+)
+
+// currencyExponents lists ISO 4217 minor-unit exponents that differ from the default of 2.
+var currencyExponents = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+// CurrencyExponent returns the number of minor-unit digits for an ISO 4217 currency code.
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[strings.ToUpper(strings.TrimSpace(currency))]; ok {
+		return exponent
+	}
+	return defaultCurrencyExponent
+}
+
+// ParseMinorUnits converts a non-negative decimal string in major units, such as "12.50",
+// into minor units using the given currency exponent.
+func ParseMinorUnits(amount string, exponent int) (int64, error) {
+	amount = strings.TrimSpace(amount)
+	whole, fraction, hasFraction := strings.Cut(amount, ".")
+	if whole == "" || (hasFraction && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return 0, ErrMalformedAmount
+	}
+
+	if len(fraction) > exponent {
+		return 0, fmt.Errorf("%w: at most %d allowed", ErrAmountTooPrecise, exponent)
+	}
+
+	var minor int64
+	digits := whole + fraction + strings.Repeat("0", exponent-len(fraction))
+	for _, digit := range digits {
+		value := int64(digit - '0')
+		if minor > (math.MaxInt64-value)/10 {
+			return 0, ErrMalformedAmount
+		}
+		minor = minor*10 + value
+	}
+
+	return minor, nil
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,16 @@
+package vo
+
+import "time"
+
+// TransactionRecord is a single ledger entry of the user's wallet, as exported or listed.
+type TransactionRecord struct {
+	EntryID           string    `json:"entry_id"`
+	WalletID          string    `json:"wallet_id"`
+	EntryType         string    `json:"entry_type"`
+	AmountMinor       int64     `json:"amount_minor"`
+	BalanceAfterMinor int64     `json:"balance_after_minor"`
+	Currency          string    `json:"currency"`
+	ReferenceID       string    `json:"reference_id"`
+	ChainID           string    `json:"chain_id"`
+	CreatedAt         time.Time `json:"created_at"`
+}
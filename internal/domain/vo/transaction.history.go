@@ -0,0 +1,12 @@
+package vo
+
+import "errors"
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// TransactionPage is one page of the user's transaction history, newest first.
+// NextCursor is empty on the last page.
+type TransactionPage struct {
+	Items      []TransactionRecord `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
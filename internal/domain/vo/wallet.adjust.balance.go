@@ -0,0 +1,12 @@
+package vo
+
+import "time"
+
+type WalletAdjustment struct {
+	ReferenceID  string    `json:"reference_id"`
+	UserID       string    `json:"user_id"`
+	AmountMinor  int64     `json:"amount_minor"`
+	BalanceMinor int64     `json:"balance_minor"`
+	Currency     string    `json:"currency"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
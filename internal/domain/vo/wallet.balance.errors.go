@@ -4,3 +4,8 @@ import "errors"
 
 var ErrInsufficientBalance = errors.New("insufficient balance")
 var ErrInvalidAmount = errors.New("invalid amount")
+var ErrAmountBelowMinimum = errors.New("amount below minimum")
+var ErrAmountAboveMaximum = errors.New("amount above maximum")
+var ErrDailyLimitExceeded = errors.New("daily withdrawal limit exceeded")
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+var ErrConcurrentModification = errors.New("wallet was modified concurrently")
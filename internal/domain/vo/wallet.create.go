@@ -0,0 +1,16 @@
+package vo
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrWalletAlreadyExists = errors.New("wallet already exists")
+
+type WalletCreated struct {
+	WalletID     string    `json:"wallet_id"`
+	UserID       string    `json:"user_id"`
+	BalanceMinor int64     `json:"balance_minor"`
+	Currency     string    `json:"currency"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package vo
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrWalletFrozen rejects withdrawals from a wallet frozen by fraud response. Inquiries and
+// deposits are unaffected.
+var ErrWalletFrozen = errors.New("wallet is frozen")
+
+const (
+	WalletStatusActive = "active"
+	WalletStatusFrozen = "frozen"
+)
+
+type WalletStatus struct {
+	UserID    string    `json:"user_id"`
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package vo
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrSameWalletTransfer = errors.New("source and destination wallets must differ")
+
+type WalletTransfer struct {
+	ReferenceID             string    `json:"reference_id"`
+	UserID                  string    `json:"user_id"`
+	SourceWalletID          string    `json:"source_wallet_id"`
+	DestinationWalletID     string    `json:"destination_wallet_id"`
+	AmountMinor             int64     `json:"amount_minor"`
+	SourceBalanceMinor      int64     `json:"source_balance_minor"`
+	DestinationBalanceMinor int64     `json:"destination_balance_minor"`
+	Currency                string    `json:"currency"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
@@ -3,10 +3,14 @@ package vo
 import "time"
 
 type WalletWithdrawal struct {
-	UserID       string    `json:"user_id"`
-	AmountMinor  int64     `json:"amount_minor"`
-	BalanceMinor int64     `json:"balance_minor"`
-	Currency     string    `json:"currency"`
-	ChainID      string    `json:"chain_id"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ReferenceID     string    `json:"reference_id"`
+	UserID          string    `json:"user_id"`
+	AmountMinor     int64     `json:"amount_minor"`
+	FeeMinor        int64     `json:"fee_minor"`
+	BalanceMinor    int64     `json:"balance_minor"`
+	Currency        string    `json:"currency"`
+	ChainID         string    `json:"chain_id"`
+	PayoutReference string    `json:"payout_reference,omitempty"`
+	Status          string    `json:"status"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
@@ -0,0 +1,22 @@
+package vo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrChainUnavailable = errors.New("chain unavailable")
+
+type ChainUnavailableError struct {
+	ChainID string
+	Until   time.Time
+}
+
+func (e *ChainUnavailableError) Error() string {
+	return fmt.Sprintf("chain %s unavailable until %s", e.ChainID, e.Until.UTC().Format(time.RFC3339))
+}
+
+func (e *ChainUnavailableError) Unwrap() error {
+	return ErrChainUnavailable
+}
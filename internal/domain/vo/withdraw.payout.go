@@ -0,0 +1,15 @@
+package vo
+
+import "errors"
+
+// ErrPayoutUnavailable means the payout provider could not be reached or kept failing
+// after retries, or its circuit breaker is open; the wallet was not debited, or the debit
+// was reversed, and the withdrawal can be retried later.
+var ErrPayoutUnavailable = errors.New("payout provider unavailable")
+
+// ErrPayoutRejected means the payout provider refused the payout; the withdrawal was reversed.
+var ErrPayoutRejected = errors.New("payout rejected by provider")
+
+// ErrWithdrawalSettled means the withdrawal's payout is no longer pending, so it can be
+// neither completed nor reversed again.
+var ErrWithdrawalSettled = errors.New("withdrawal payout already settled")
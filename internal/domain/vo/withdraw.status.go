@@ -0,0 +1,27 @@
+package vo
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrWithdrawalNotFound = errors.New("withdrawal not found")
+
+const (
+	WithdrawalStatusCompleted = "completed"
+	WithdrawalStatusFailed    = "failed"
+	// WithdrawalStatusPending means the payout outcome is not known yet; the withdrawal
+	// is settled as completed or failed once the provider confirms it.
+	WithdrawalStatusPending = "pending"
+)
+
+type WithdrawalStatus struct {
+	ReferenceID string    `json:"reference_id"`
+	Status      string    `json:"status"`
+	AmountMinor int64     `json:"amount_minor"`
+	FeeMinor    int64     `json:"fee_minor"`
+	Currency    string    `json:"currency"`
+	ChainID     string    `json:"chain_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -6,5 +6,30 @@ type WalletBalance struct {
 	UserID       string
 	BalanceMinor int64
 	Currency     string
+	Version      int64
 	UpdatedAt    time.Time
 }
+
+type Wallet struct {
+	ID           string
+	UserID       string
+	BalanceMinor int64
+	Currency     string
+	Version      int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// BalanceChange announces that the wallet balance of UserID was changed by a
+// committed transaction. It carries no amounts; readers fetch the new balance.
+type BalanceChange struct {
+	UserID     string
+	ReceivedAt time.Time
+}
+
+// WalletStatus is the freeze state of the wallet owned by UserID.
+type WalletStatus struct {
+	UserID    string
+	Status    string
+	UpdatedAt time.Time
+}
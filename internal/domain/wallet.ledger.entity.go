@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// LedgerEntry is one row of a wallet's ledger, tagged with the wallet currency.
+type LedgerEntry struct {
+	ID                string
+	WalletID          string
+	EntryType         string
+	AmountMinor       int64
+	BalanceAfterMinor int64
+	Currency          string
+	ReferenceID       string
+	ChainID           string
+	CreatedAt         time.Time
+}
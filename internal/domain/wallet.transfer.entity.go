@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+type WalletTransfer struct {
+	UserID                  string
+	SourceWalletID          string
+	DestinationWalletID     string
+	SourceBalanceMinor      int64
+	DestinationBalanceMinor int64
+	Currency                string
+	UpdatedAt               time.Time
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "time"
+
+type DailyWithdrawLimit struct {
+	LimitMinor int64
+	Since      time.Time
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// WithdrawalRecord is a withdrawal reassembled from its ledger entries.
+type WithdrawalRecord struct {
+	ReferenceID string
+	UserID      string
+	AmountMinor int64
+	FeeMinor    int64
+	Currency    string
+	ChainID     string
+	Reversed    bool
+	Pending     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PendingWithdrawal is a debited withdrawal whose payout outcome is not settled yet.
+type PendingWithdrawal struct {
+	ReferenceID string
+	UserID      string
+	AmountMinor int64
+	FeeMinor    int64
+	Currency    string
+	ChainID     string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+const (
+	errorCodeInvalidRequestBody  = "INVALID_REQUEST_BODY"
+	errorCodeValidationFailed    = "VALIDATION_FAILED"
+	errorCodeUnauthenticated     = "UNAUTHENTICATED"
+	errorCodeInvalidCredentials  = "INVALID_CREDENTIALS"
+	errorCodeAccountLocked       = "ACCOUNT_LOCKED"
+	errorCodeWeakPassword        = "WEAK_PASSWORD"
+	errorCodeInvalidEmail        = "INVALID_EMAIL"
+	errorCodeEmailRegistered     = "EMAIL_ALREADY_REGISTERED"
+	errorCodeInvalidAmount       = "INVALID_AMOUNT"
+	errorCodeAmountBelowMinimum  = "AMOUNT_BELOW_MINIMUM"
+	errorCodeAmountAboveMaximum  = "AMOUNT_ABOVE_MAXIMUM"
+	errorCodeWalletNotFound      = "WALLET_NOT_FOUND"
+	errorCodeWalletAlreadyExists = "WALLET_ALREADY_EXISTS"
+	errorCodeWalletFrozen        = "WALLET_FROZEN"
+	errorCodeWithdrawalNotFound  = "WITHDRAWAL_NOT_FOUND"
+	errorCodeInvalidCursor       = "INVALID_CURSOR"
+	errorCodeInsufficientBalance = "INSUFFICIENT_BALANCE"
+	errorCodeDailyLimitExceeded  = "DAILY_LIMIT_EXCEEDED"
+	errorCodeCurrencyMismatch    = "CURRENCY_MISMATCH"
+	errorCodeSameWalletTransfer  = "SAME_WALLET_TRANSFER"
+	errorCodeConcurrentUpdate    = "CONCURRENT_MODIFICATION"
+	errorCodeChainUnavailable    = "CHAIN_UNAVAILABLE"
+	errorCodePayoutUnavailable   = "PAYOUT_UNAVAILABLE"
+	errorCodePayoutRejected      = "PAYOUT_REJECTED"
+	errorCodeInternal            = "INTERNAL_ERROR"
+)
+
+type apiError struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	RequestID string       `json:"request_id,omitempty"`
+	Fields    []fieldError `json:"fields,omitempty"`
+}
+
+type apiErrorResponse struct {
+	Error apiError `json:"error"`
+}
+
+func respondError(c fiber.Ctx, status int, code, message string) error {
+	return c.Status(status).JSON(apiErrorResponse{
+		Error: apiError{
+			Code:      code,
+			Message:   localizedMessage(c, code, message),
+			RequestID: middlewares.RequestIDFromContext(c),
+		},
+	})
+}
+
+// domainErrorMapping ties a domain sentinel error to its HTTP status, stable code and
+// default message.
+type domainErrorMapping struct {
+	err     error
+	status  int
+	code    string
+	message string
+}
+
+// domainErrors is the single place where domain errors are given an HTTP meaning. Entries
+// are matched in order with errors.Is, so a new sentinel only needs to be registered here.
+var domainErrors = []domainErrorMapping{
+	{err: vo.ErrInvalidCredentials, status: fiber.StatusUnauthorized, code: errorCodeInvalidCredentials, message: "invalid email or password"},
+	{err: vo.ErrAccountLocked, status: fiber.StatusLocked, code: errorCodeAccountLocked, message: "account temporarily locked due to repeated failed logins"},
+	{err: vo.ErrWalletFrozen, status: fiber.StatusLocked, code: errorCodeWalletFrozen, message: "wallet is frozen, withdrawals and transfers are blocked"},
+	{err: vo.ErrInvalidEmail, status: fiber.StatusUnprocessableEntity, code: errorCodeInvalidEmail, message: "email is not a valid address"},
+	{err: vo.ErrWeakPassword, status: fiber.StatusUnprocessableEntity, code: errorCodeWeakPassword, message: "password does not meet the password policy"},
+	{err: vo.ErrInvalidAmount, status: fiber.StatusBadRequest, code: errorCodeInvalidAmount, message: "amount_minor must be greater than 0"},
+	{err: vo.ErrAmountBelowMinimum, status: fiber.StatusBadRequest, code: errorCodeAmountBelowMinimum, message: "amount_minor is below the minimum withdrawal amount"},
+	{err: vo.ErrAmountAboveMaximum, status: fiber.StatusBadRequest, code: errorCodeAmountAboveMaximum, message: "amount_minor exceeds the maximum withdrawal amount"},
+	{err: vo.ErrSameWalletTransfer, status: fiber.StatusBadRequest, code: errorCodeSameWalletTransfer, message: "source and destination wallets must differ"},
+	{err: vo.ErrInvalidCursor, status: fiber.StatusBadRequest, code: errorCodeInvalidCursor, message: "cursor is invalid or expired"},
+	{err: vo.ErrWalletNotFound, status: fiber.StatusNotFound, code: errorCodeWalletNotFound, message: "wallet not found"},
+	{err: vo.ErrWithdrawalNotFound, status: fiber.StatusNotFound, code: errorCodeWithdrawalNotFound, message: "withdrawal not found"},
+	{err: vo.ErrEmailAlreadyRegistered, status: fiber.StatusConflict, code: errorCodeEmailRegistered, message: "email is already registered"},
+	{err: vo.ErrWalletAlreadyExists, status: fiber.StatusConflict, code: errorCodeWalletAlreadyExists, message: "wallet already exists"},
+	{err: vo.ErrInsufficientBalance, status: fiber.StatusConflict, code: errorCodeInsufficientBalance, message: "insufficient balance"},
+	{err: vo.ErrCurrencyMismatch, status: fiber.StatusConflict, code: errorCodeCurrencyMismatch, message: "currency does not match wallet currency"},
+	{err: vo.ErrConcurrentModification, status: fiber.StatusConflict, code: errorCodeConcurrentUpdate, message: "wallet was modified concurrently, retry the request"},
+	{err: vo.ErrDailyLimitExceeded, status: fiber.StatusConflict, code: errorCodeDailyLimitExceeded, message: "daily withdrawal limit exceeded"},
+	{err: vo.ErrChainUnavailable, status: fiber.StatusServiceUnavailable, code: errorCodeChainUnavailable, message: "chain temporarily unavailable"},
+	{err: vo.ErrPayoutUnavailable, status: fiber.StatusServiceUnavailable, code: errorCodePayoutUnavailable, message: "payout provider unavailable, no funds were withdrawn"},
+	{err: vo.ErrPayoutRejected, status: fiber.StatusUnprocessableEntity, code: errorCodePayoutRejected, message: "payout rejected by provider, the withdrawal was reversed"},
+}
+
+func lookupDomainError(err error) (domainErrorMapping, bool) {
+	for _, mapping := range domainErrors {
+		if errors.Is(err, mapping.err) {
+			return mapping, true
+		}
+	}
+	return domainErrorMapping{}, false
+}
+
+// isDomainError reports whether err has a registered mapping; anything else is unexpected
+// and worth logging.
+func isDomainError(err error) bool {
+	_, ok := lookupDomainError(err)
+	return ok
+}
+
+// StatusForError returns the HTTP status and stable error code for err. Unregistered errors
+// map to 500 INTERNAL_ERROR.
+func StatusForError(err error) (int, string) {
+	if mapping, ok := lookupDomainError(err); ok {
+		return mapping.status, mapping.code
+	}
+	return fiber.StatusInternalServerError, errorCodeInternal
+}
+
+// respondDomainError writes the registered response for err. messages replaces the default
+// message for endpoints that need their own wording. A weak password also lists each unmet
+// policy criterion under fields.
+func respondDomainError(c fiber.Ctx, err error, messages map[error]string) error {
+	mapping, ok := lookupDomainError(err)
+	if !ok {
+		if errors.Is(err, context.DeadlineExceeded) {
+			// Returned so the timeout middleware answers 503 with Retry-After.
+			return err
+		}
+		return respondError(c, fiber.StatusInternalServerError, errorCodeInternal, "internal server error")
+	}
+
+	message := mapping.message
+	if override, ok := messages[mapping.err]; ok {
+		message = override
+	}
+
+	var weak *vo.WeakPasswordError
+	if errors.As(err, &weak) {
+		fields := make([]fieldError, 0, len(weak.Unmet))
+		for _, criterion := range weak.Unmet {
+			fields = append(fields, fieldError{Field: "password", Message: criterion})
+		}
+		return c.Status(mapping.status).JSON(apiErrorResponse{
+			Error: apiError{
+				Code:      mapping.code,
+				Message:   localizedMessage(c, mapping.code, message),
+				RequestID: middlewares.RequestIDFromContext(c),
+				Fields:    fields,
+			},
+		})
+	}
+
+	return respondError(c, mapping.status, mapping.code, message)
+}
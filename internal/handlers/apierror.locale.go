@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// defaultLocale is the language of the messages written at the call sites and in
+// domainErrors; it needs no catalog entry.
+const defaultLocale = "en"
+
+// errorMessageCatalog translates error messages by locale and then by error code. A code
+// is translated as a whole, so endpoint-specific English wording for the same code shares
+// one translation. Codes missing from a locale keep their English message.
+var errorMessageCatalog = map[string]map[string]string{
+	"id": {
+		errorCodeInvalidRequestBody:  "body request tidak valid",
+		errorCodeValidationFailed:    "validasi request gagal",
+		errorCodeUnauthenticated:     "user belum terautentikasi",
+		errorCodeInvalidCredentials:  "email atau password salah",
+		errorCodeAccountLocked:       "akun dikunci sementara karena terlalu banyak login gagal",
+		errorCodeWeakPassword:        "password tidak memenuhi kebijakan password",
+		errorCodeInvalidEmail:        "email bukan alamat yang valid",
+		errorCodeEmailRegistered:     "email sudah terdaftar",
+		errorCodeInvalidAmount:       "nominal tidak valid",
+		errorCodeAmountBelowMinimum:  "amount_minor di bawah nominal penarikan minimum",
+		errorCodeAmountAboveMaximum:  "amount_minor melebihi nominal penarikan maksimum",
+		errorCodeWalletNotFound:      "wallet tidak ditemukan",
+		errorCodeWalletAlreadyExists: "wallet sudah ada",
+		errorCodeWalletFrozen:        "wallet dibekukan, penarikan diblokir",
+		errorCodeWithdrawalNotFound:  "penarikan tidak ditemukan",
+		errorCodeInvalidCursor:       "cursor tidak valid atau sudah kedaluwarsa",
+		errorCodeInsufficientBalance: "saldo tidak mencukupi",
+		errorCodeDailyLimitExceeded:  "batas penarikan harian terlampaui",
+		errorCodeCurrencyMismatch:    "mata uang tidak sesuai",
+		errorCodeSameWalletTransfer:  "wallet sumber dan tujuan harus berbeda",
+		errorCodeConcurrentUpdate:    "wallet sedang diubah secara bersamaan, ulangi request",
+		errorCodeChainUnavailable:    "chain sementara tidak tersedia",
+		errorCodePayoutUnavailable:   "penyedia payout tidak tersedia, penarikan telah dibatalkan",
+		errorCodePayoutRejected:      "payout ditolak penyedia, penarikan telah dibatalkan",
+		errorCodeInternal:            "terjadi kesalahan internal pada server",
+		chainIDCodeRequired:          "chain id wajib diisi",
+		chainIDCodeMalformed:         "format chain id tidak valid",
+		chainIDCodeUnsupported:       "chain id tidak didukung",
+	},
+}
+
+// localizedMessage returns the catalog message for code in the locale preferred by the
+// request's Accept-Language header, or message when that locale is English or has no entry
+// for code. It also sets Content-Language to the language actually used.
+func localizedMessage(c fiber.Ctx, code, message string) string {
+	locale := preferredLocale(c.Get(fiber.HeaderAcceptLanguage))
+	if translated, ok := errorMessageCatalog[locale][code]; ok {
+		c.Set(fiber.HeaderContentLanguage, locale)
+		return translated
+	}
+
+	c.Set(fiber.HeaderContentLanguage, defaultLocale)
+	return message
+}
+
+// preferredLocale picks the supported locale with the highest q value from an
+// Accept-Language header, matching on the primary subtag so "id-ID" selects "id". Ties keep
+// header order, and a missing or unsupported header falls back to defaultLocale.
+func preferredLocale(header string) string {
+	best, bestQuality := defaultLocale, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if language != defaultLocale && errorMessageCatalog[language] == nil {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > bestQuality {
+			best, bestQuality = language, quality
+		}
+	}
+	return best
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type AuthChangePasswordService interface {
+	ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error
+}
+
+type AuthChangePasswordHandler struct {
+	service AuthChangePasswordService
+	logger  *slog.Logger
+}
+
+type authChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (r authChangePasswordRequest) validate() []fieldError {
+	var fields []fieldError
+	if r.CurrentPassword == "" {
+		fields = append(fields, fieldError{Field: "current_password", Message: "is required"})
+	}
+	if r.NewPassword == "" {
+		fields = append(fields, fieldError{Field: "new_password", Message: "is required"})
+	}
+	return fields
+}
+
+func NewAuthChangePasswordHandler(service AuthChangePasswordService, logger *slog.Logger) *AuthChangePasswordHandler {
+	return &AuthChangePasswordHandler{service: service, logger: logger}
+}
+
+func (h *AuthChangePasswordHandler) Register(router fiber.Router) {
+	router.Post("/auth/change-password", h.Handle)
+}
+
+func (h *AuthChangePasswordHandler) Handle(c fiber.Ctx) error {
+	userID, ok := middlewares.UserIDFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
+	}
+
+	var requestBody authChangePasswordRequest
+	fields, err := decodeJSONBody(c.Body(), &requestBody)
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
+	}
+	if len(fields) == 0 {
+		fields = requestBody.validate()
+	}
+	if len(fields) > 0 {
+		return respondValidationError(c, fields)
+	}
+
+	if err := h.service.ChangePassword(c.Context(), userID, requestBody.CurrentPassword, requestBody.NewPassword); err != nil {
+		if !isDomainError(err) {
+			h.logger.Error("failed to change password", "user_id", userID, "error", err)
+		}
+		return respondDomainError(c, err, map[error]string{
+			vo.ErrInvalidCredentials: "current password is incorrect",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
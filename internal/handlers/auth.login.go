@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"errors"
 	"log/slog"
 	"strings"
 
@@ -35,29 +34,19 @@ func (h *AuthLoginHandler) Register(router fiber.Router) {
 func (h *AuthLoginHandler) Handle(c fiber.Ctx) error {
 	var requestBody authLoginRequest
 	if err := c.Bind().JSON(&requestBody); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid request body",
-		})
+		return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
 	}
 
 	if strings.TrimSpace(requestBody.Email) == "" || strings.TrimSpace(requestBody.Password) == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "email and password are required",
-		})
+		return respondError(c, fiber.StatusBadRequest, errorCodeValidationFailed, "email and password are required")
 	}
 
 	loginResult, err := h.service.Login(c.Context(), requestBody.Email, requestBody.Password)
 	if err != nil {
-		if errors.Is(err, vo.ErrInvalidCredentials) {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "invalid email or password",
-			})
+		if !isDomainError(err) {
+			h.logger.Error("failed to login", "email", requestBody.Email, "error", err)
 		}
-
-		h.logger.Error("failed to login", "email", requestBody.Email, "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "internal server error",
-		})
+		return respondDomainError(c, err, nil)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(loginResult)
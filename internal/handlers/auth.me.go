@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+// AuthMeHandler describes the caller's token so front-ends need not decode it themselves.
+type AuthMeHandler struct{}
+
+type authMeResponse struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	Audience  []string  `json:"audience"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Scopes    []string  `json:"scopes"`
+}
+
+func NewAuthMeHandler() *AuthMeHandler {
+	return &AuthMeHandler{}
+}
+
+func (h *AuthMeHandler) Register(router fiber.Router) {
+	router.Get("/auth/me", h.Handle)
+}
+
+func (h *AuthMeHandler) Handle(c fiber.Ctx) error {
+	claims, ok := middlewares.ClaimsFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
+	}
+
+	// Empty lists are sent as [] so clients can iterate without a null check.
+	response := authMeResponse{
+		Subject:   claims.Subject,
+		Issuer:    claims.Issuer,
+		Audience:  []string{},
+		ExpiresAt: claims.ExpiresAt.UTC(),
+		Scopes:    []string{},
+	}
+	response.Audience = append(response.Audience, claims.Audience...)
+	response.Scopes = append(response.Scopes, claims.Scopes...)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+)
+
+type AuthRegisterService interface {
+	Register(ctx context.Context, email, password string) (vo.AuthRegistered, error)
+}
+
+type AuthRegisterHandler struct {
+	service AuthRegisterService
+	logger  *slog.Logger
+}
+
+type authRegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (r authRegisterRequest) validate() []fieldError {
+	var fields []fieldError
+	if strings.TrimSpace(r.Email) == "" {
+		fields = append(fields, fieldError{Field: "email", Message: "is required"})
+	}
+	if r.Password == "" {
+		fields = append(fields, fieldError{Field: "password", Message: "is required"})
+	}
+	return fields
+}
+
+func NewAuthRegisterHandler(service AuthRegisterService, logger *slog.Logger) *AuthRegisterHandler {
+	return &AuthRegisterHandler{service: service, logger: logger}
+}
+
+func (h *AuthRegisterHandler) Register(router fiber.Router) {
+	router.Post("/auth/register", h.Handle)
+}
+
+func (h *AuthRegisterHandler) Handle(c fiber.Ctx) error {
+	var requestBody authRegisterRequest
+	fields, err := decodeJSONBody(c.Body(), &requestBody)
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
+	}
+	if len(fields) == 0 {
+		fields = requestBody.validate()
+	}
+	if len(fields) > 0 {
+		return respondValidationError(c, fields)
+	}
+
+	result, err := h.service.Register(c.Context(), requestBody.Email, requestBody.Password)
+	if err != nil {
+		if !isDomainError(err) {
+			h.logger.Error("failed to register user", "email", requestBody.Email, "error", err)
+		}
+		return respondDomainError(c, err, nil)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type BalanceDepositService interface {
+	DepositBalance(ctx context.Context, userID string, amountMinor int64) (vo.WalletDeposit, error)
+}
+
+type InquiryDepositBalanceHandler struct {
+	service BalanceDepositService
+	logger  *slog.Logger
+}
+
+type depositRequest struct {
+	AmountMinor int64 `json:"amount_minor"`
+}
+
+func NewInquiryDepositBalanceHandler(service BalanceDepositService, logger *slog.Logger) *InquiryDepositBalanceHandler {
+	return &InquiryDepositBalanceHandler{service: service, logger: logger}
+}
+
+func (h *InquiryDepositBalanceHandler) Register(router fiber.Router) {
+	router.Post("/deposits", h.Handle)
+}
+
+func (h *InquiryDepositBalanceHandler) Handle(c fiber.Ctx) error {
+	userID, ok := middlewares.UserIDFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
+	}
+
+	var requestBody depositRequest
+	if err := c.Bind().JSON(&requestBody); err != nil {
+		return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
+	}
+
+	result, err := h.service.DepositBalance(c.Context(), userID, requestBody.AmountMinor)
+	if err != nil {
+		if !isDomainError(err) {
+			h.logger.Error("failed to deposit balance", "user_id", userID, "error", err)
+		}
+		return respondDomainError(c, err, nil)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
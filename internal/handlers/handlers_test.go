@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +24,7 @@ import (
 
 	"github.com/joshuarp/withdraw-api/internal/domain/vo"
 	"github.com/joshuarp/withdraw-api/internal/middlewares"
+	sharedjwt "github.com/joshuarp/withdraw-api/internal/shared/jwt"
 )
 
 func newTestLogger() *slog.Logger {
@@ -48,6 +53,19 @@ func performJSONRequest(app *fiber.App, method, path string, body []byte, header
 	return resp, parsed, rawBody
 }
 
+func errorBody(payload map[string]interface{}) map[string]interface{} {
+	body, _ := payload["error"].(map[string]interface{})
+	return body
+}
+
+func errorMessage(payload map[string]interface{}) interface{} {
+	return errorBody(payload)["message"]
+}
+
+func errorCode(payload map[string]interface{}) interface{} {
+	return errorBody(payload)["code"]
+}
+
 type AuthLoginHandlerSuite struct {
 	suite.Suite
 
@@ -78,7 +96,7 @@ func (s *AuthLoginHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}, _ []byte) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
-				assert.Equal(s.T(), "invalid request body", payload["error"])
+				assert.Equal(s.T(), "invalid request body", errorMessage(payload))
 			},
 		},
 		{
@@ -87,7 +105,7 @@ func (s *AuthLoginHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}, _ []byte) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
-				assert.Equal(s.T(), "email and password are required", payload["error"])
+				assert.Equal(s.T(), "email and password are required", errorMessage(payload))
 			},
 		},
 		{
@@ -101,7 +119,21 @@ func (s *AuthLoginHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}, _ []byte) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
-				assert.Equal(s.T(), "invalid email or password", payload["error"])
+				assert.Equal(s.T(), "invalid email or password", errorMessage(payload))
+			},
+		},
+		{
+			name: "account locked",
+			body: []byte(`{"email":"user@example.com","password":"secret"}`),
+			setupMock: func() {
+				s.service.EXPECT().
+					Login(mock.Anything, "user@example.com", "secret").
+					Return(vo.AuthLogin{}, vo.ErrAccountLocked)
+			},
+			assertion: func(resp *http.Response, payload map[string]interface{}, _ []byte) {
+				require.NotNil(s.T(), resp)
+				assert.Equal(s.T(), fiber.StatusLocked, resp.StatusCode)
+				assert.Equal(s.T(), errorCodeAccountLocked, errorCode(payload))
 			},
 		},
 		{
@@ -115,7 +147,7 @@ func (s *AuthLoginHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}, _ []byte) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
-				assert.Equal(s.T(), "internal server error", payload["error"])
+				assert.Equal(s.T(), "internal server error", errorMessage(payload))
 			},
 		},
 		{
@@ -155,6 +187,298 @@ func TestAuthLoginHandlerSuite(t *testing.T) {
 	suite.Run(t, new(AuthLoginHandlerSuite))
 }
 
+type AuthRegisterHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.AuthRegisterService
+	handler *AuthRegisterHandler
+	app     *fiber.App
+}
+
+func (s *AuthRegisterHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewAuthRegisterService(s.T())
+	s.handler = NewAuthRegisterHandler(s.service, newTestLogger())
+	s.app = fiber.New()
+	s.app.Post("/auth/register", s.handler.Handle)
+}
+
+func (s *AuthRegisterHandlerSuite) TestHandle_TableDriven() {
+	createdAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		body      []byte
+		setupMock func()
+		assertion func(*http.Response, map[string]interface{})
+	}{
+		{
+			name: "invalid body",
+			body: []byte(`{"email":`),
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+				assert.Equal(s.T(), errorCodeInvalidRequestBody, errorCode(payload))
+			},
+		},
+		{
+			name: "missing email and password",
+			body: []byte(`{"email":" "}`),
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				assert.Equal(s.T(), fiber.StatusUnprocessableEntity, resp.StatusCode)
+				assert.Equal(s.T(), errorCodeValidationFailed, errorCode(payload))
+				assert.Len(s.T(), payload["error"].(map[string]interface{})["fields"], 2)
+			},
+		},
+		{
+			name: "weak password lists unmet criteria",
+			body: []byte(`{"email":"jane@example.com","password":"short"}`),
+			setupMock: func() {
+				s.service.EXPECT().Register(mock.Anything, "jane@example.com", "short").
+					Return(vo.AuthRegistered{}, &vo.WeakPasswordError{Unmet: []string{"min_length", "digit"}})
+			},
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				assert.Equal(s.T(), fiber.StatusUnprocessableEntity, resp.StatusCode)
+				assert.Equal(s.T(), errorCodeWeakPassword, errorCode(payload))
+				assert.Len(s.T(), payload["error"].(map[string]interface{})["fields"], 2)
+			},
+		},
+		{
+			name: "duplicate email",
+			body: []byte(`{"email":"jane@example.com","password":"Withdraw2026!"}`),
+			setupMock: func() {
+				s.service.EXPECT().Register(mock.Anything, "jane@example.com", "Withdraw2026!").
+					Return(vo.AuthRegistered{}, vo.ErrEmailAlreadyRegistered)
+			},
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
+				assert.Equal(s.T(), errorCodeEmailRegistered, errorCode(payload))
+			},
+		},
+		{
+			name: "internal error",
+			body: []byte(`{"email":"jane@example.com","password":"Withdraw2026!"}`),
+			setupMock: func() {
+				s.service.EXPECT().Register(mock.Anything, "jane@example.com", "Withdraw2026!").
+					Return(vo.AuthRegistered{}, errors.New("wallet db down"))
+			},
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
+				assert.Equal(s.T(), errorCodeInternal, errorCode(payload))
+			},
+		},
+		{
+			name: "success",
+			body: []byte(`{"email":"jane@example.com","password":"Withdraw2026!"}`),
+			setupMock: func() {
+				s.service.EXPECT().Register(mock.Anything, "jane@example.com", "Withdraw2026!").
+					Return(vo.AuthRegistered{UserID: "user-1", Email: "jane@example.com", WalletID: "wallet-1", Currency: "IDR", CreatedAt: createdAt}, nil)
+			},
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+				assert.Equal(s.T(), "user-1", payload["user_id"])
+				assert.Equal(s.T(), "wallet-1", payload["wallet_id"])
+				assert.NotContains(s.T(), payload, "password")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/auth/register", tc.body, nil)
+			if resp == nil {
+				s.T().Fatal("failed to execute request")
+			}
+			tc.assertion(resp, payload)
+		})
+	}
+}
+
+func TestAuthRegisterHandlerSuite(t *testing.T) {
+	suite.Run(t, new(AuthRegisterHandlerSuite))
+}
+
+type AuthChangePasswordHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.AuthChangePasswordService
+	handler *AuthChangePasswordHandler
+}
+
+func (s *AuthChangePasswordHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewAuthChangePasswordService(s.T())
+	s.handler = NewAuthChangePasswordHandler(s.service, newTestLogger())
+}
+
+func (s *AuthChangePasswordHandlerSuite) TestHandle_TableDriven() {
+	validBody := []byte(`{"current_password":"OldPassw0rd","new_password":"NewPassw0rd!"}`)
+
+	tests := []struct {
+		name         string
+		userID       string
+		body         []byte
+		setupMock    func()
+		expectedCode int
+		expectedErr  string
+		expectedMsg  string
+	}{
+		{
+			name:         "missing authenticated user",
+			body:         validBody,
+			expectedCode: fiber.StatusUnauthorized,
+			expectedErr:  errorCodeUnauthenticated,
+		},
+		{
+			name:         "invalid body",
+			userID:       "user-1",
+			body:         []byte(`{"current_password":`),
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  errorCodeInvalidRequestBody,
+		},
+		{
+			name:         "missing passwords",
+			userID:       "user-1",
+			body:         []byte(`{}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedErr:  errorCodeValidationFailed,
+		},
+		{
+			name:   "wrong current password",
+			userID: "user-1",
+			body:   validBody,
+			setupMock: func() {
+				s.service.EXPECT().ChangePassword(mock.Anything, "user-1", "OldPassw0rd", "NewPassw0rd!").Return(vo.ErrInvalidCredentials)
+			},
+			expectedCode: fiber.StatusUnauthorized,
+			expectedErr:  errorCodeInvalidCredentials,
+			expectedMsg:  "current password is incorrect",
+		},
+		{
+			name:   "weak new password",
+			userID: "user-1",
+			body:   validBody,
+			setupMock: func() {
+				s.service.EXPECT().ChangePassword(mock.Anything, "user-1", "OldPassw0rd", "NewPassw0rd!").
+					Return(&vo.WeakPasswordError{Unmet: []string{"not_common"}})
+			},
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedErr:  errorCodeWeakPassword,
+		},
+		{
+			name:   "service failure",
+			userID: "user-1",
+			body:   validBody,
+			setupMock: func() {
+				s.service.EXPECT().ChangePassword(mock.Anything, "user-1", "OldPassw0rd", "NewPassw0rd!").Return(errors.New("db down"))
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  errorCodeInternal,
+		},
+		{
+			name:   "success",
+			userID: "user-1",
+			body:   validBody,
+			setupMock: func() {
+				s.service.EXPECT().ChangePassword(mock.Anything, "user-1", "OldPassw0rd", "NewPassw0rd!").Return(nil)
+			},
+			expectedCode: fiber.StatusNoContent,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			app := fiber.New()
+			app.Post("/auth/change-password", func(c fiber.Ctx) error {
+				if tc.userID != "" {
+					c.Locals("user_id", tc.userID)
+				}
+				return s.handler.Handle(c)
+			})
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, raw := performJSONRequest(app, http.MethodPost, "/auth/change-password", tc.body, nil)
+			if resp == nil {
+				s.T().Fatal("failed to execute request")
+			}
+
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr == "" {
+				assert.Empty(s.T(), raw)
+				return
+			}
+			assert.Equal(s.T(), tc.expectedErr, errorCode(payload))
+			if tc.expectedMsg != "" {
+				assert.Equal(s.T(), tc.expectedMsg, errorMessage(payload))
+			}
+		})
+	}
+}
+
+func TestAuthChangePasswordHandlerSuite(t *testing.T) {
+	suite.Run(t, new(AuthChangePasswordHandlerSuite))
+}
+
+func TestAuthMeHandler_TableDriven(t *testing.T) {
+	expiresAt := time.Date(2026, 5, 1, 10, 15, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+	tests := []struct {
+		name         string
+		claims       *sharedjwt.Claims
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "returns the token principal",
+			claims: &sharedjwt.Claims{
+				Subject:   "user-1",
+				Issuer:    "withdraw-api",
+				Audience:  []string{"web"},
+				ExpiresAt: expiresAt,
+				Scopes:    []string{"wallet:adjust", "ratelimit:reset"},
+			},
+			expectedCode: fiber.StatusOK,
+			expectedBody: `{"subject":"user-1","issuer":"withdraw-api","audience":["web"],"expires_at":"2026-05-01T03:15:00Z","scopes":["wallet:adjust","ratelimit:reset"]}`,
+		},
+		{
+			name:         "empty audience and scopes are arrays",
+			claims:       &sharedjwt.Claims{Subject: "user-1", Issuer: "withdraw-api", ExpiresAt: expiresAt},
+			expectedCode: fiber.StatusOK,
+			expectedBody: `{"subject":"user-1","issuer":"withdraw-api","audience":[],"expires_at":"2026-05-01T03:15:00Z","scopes":[]}`,
+		},
+		{
+			name:         "missing claims",
+			expectedCode: fiber.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/auth/me", func(c fiber.Ctx) error {
+				if tc.claims != nil {
+					c.Locals("jwt_claims", tc.claims)
+				}
+				return NewAuthMeHandler().Handle(c)
+			})
+
+			resp, payload, raw := performJSONRequest(app, http.MethodGet, "/auth/me", nil, nil)
+			require.NotNil(t, resp)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+			if tc.expectedBody == "" {
+				assert.Equal(t, errorCodeUnauthenticated, errorCode(payload))
+				return
+			}
+			assert.JSONEq(t, tc.expectedBody, string(raw))
+		})
+	}
+}
+
 type InquiryCheckBalanceHandlerSuite struct {
 	suite.Suite
 
@@ -185,7 +509,7 @@ func (s *InquiryCheckBalanceHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
-				assert.Equal(s.T(), "missing authenticated user", payload["error"])
+				assert.Equal(s.T(), "missing authenticated user", errorMessage(payload))
 			},
 		},
 		{
@@ -197,7 +521,7 @@ func (s *InquiryCheckBalanceHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
-				assert.Equal(s.T(), "wallet not found", payload["error"])
+				assert.Equal(s.T(), "wallet not found", errorMessage(payload))
 			},
 		},
 		{
@@ -209,7 +533,7 @@ func (s *InquiryCheckBalanceHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
-				assert.Equal(s.T(), "internal server error", payload["error"])
+				assert.Equal(s.T(), "internal server error", errorMessage(payload))
 			},
 		},
 		{
@@ -269,7 +593,7 @@ type InquiryWithdrawBalanceHandlerSuite struct {
 
 func (s *InquiryWithdrawBalanceHandlerSuite) SetupTest() {
 	s.service = handlermocks.NewBalanceWithdrawService(s.T())
-	s.handler = NewInquiryWithdrawBalanceHandler(s.service, newTestLogger())
+	s.handler = NewInquiryWithdrawBalanceHandler(s.service, newTestLogger(), ChainIDPolicy{})
 	s.app = fiber.New()
 }
 
@@ -292,7 +616,7 @@ func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
-				assert.Equal(s.T(), "missing authenticated user", payload["error"])
+				assert.Equal(s.T(), "missing authenticated user", errorMessage(payload))
 			},
 		},
 		{
@@ -302,21 +626,32 @@ func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
-				assert.Equal(s.T(), "invalid request body", payload["error"])
+				assert.Equal(s.T(), "invalid request body", errorMessage(payload))
 			},
 		},
 		{
-			name:   "invalid amount",
+			name:    "invalid amount",
+			userID:  "user-1",
+			body:    []byte(`{"amount_minor":0}`),
+			headers: map[string]string{middlewares.ChainIDHeader: "chain-1"},
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				require.NotNil(s.T(), resp)
+				assert.Equal(s.T(), fiber.StatusUnprocessableEntity, resp.StatusCode)
+				assert.Equal(s.T(), errorCodeValidationFailed, errorCode(payload))
+			},
+		},
+		{
+			name:   "service rejects amount",
 			userID: "user-1",
-			body:   []byte(`{"amount_minor":0}`),
+			body:   []byte(`{"amount_minor":100}`),
 			setupMock: func() {
-				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(0), "chain-1").Return(vo.WalletWithdrawal{}, vo.ErrInvalidAmount)
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1", "").Return(vo.WalletWithdrawal{}, vo.ErrInvalidAmount)
 			},
 			headers: map[string]string{middlewares.ChainIDHeader: "chain-1"},
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
-				assert.Equal(s.T(), "amount_minor must be greater than 0", payload["error"])
+				assert.Equal(s.T(), "amount_minor must be greater than 0", errorMessage(payload))
 			},
 		},
 		{
@@ -324,13 +659,13 @@ func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_TableDriven() {
 			userID: "user-1",
 			body:   []byte(`{"amount_minor":100}`),
 			setupMock: func() {
-				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1").Return(vo.WalletWithdrawal{}, vo.ErrWalletNotFound)
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1", "").Return(vo.WalletWithdrawal{}, vo.ErrWalletNotFound)
 			},
 			headers: map[string]string{middlewares.ChainIDHeader: "chain-1"},
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
-				assert.Equal(s.T(), "wallet not found", payload["error"])
+				assert.Equal(s.T(), "wallet not found", errorMessage(payload))
 			},
 		},
 		{
@@ -338,13 +673,31 @@ func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_TableDriven() {
 			userID: "user-1",
 			body:   []byte(`{"amount_minor":100}`),
 			setupMock: func() {
-				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1").Return(vo.WalletWithdrawal{}, vo.ErrInsufficientBalance)
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1", "").Return(vo.WalletWithdrawal{}, vo.ErrInsufficientBalance)
 			},
 			headers: map[string]string{middlewares.ChainIDHeader: "chain-1"},
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
-				assert.Equal(s.T(), "insufficient balance", payload["error"])
+				assert.Equal(s.T(), "insufficient balance", errorMessage(payload))
+			},
+		},
+		{
+			name:   "chain in blackout window",
+			userID: "user-1",
+			body:   []byte(`{"amount_minor":100}`),
+			setupMock: func() {
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1", "").
+					Return(vo.WalletWithdrawal{}, &vo.ChainUnavailableError{ChainID: "chain-1", Until: time.Now().Add(90 * time.Second)})
+			},
+			headers: map[string]string{middlewares.ChainIDHeader: "chain-1"},
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				require.NotNil(s.T(), resp)
+				assert.Equal(s.T(), fiber.StatusServiceUnavailable, resp.StatusCode)
+				assert.Equal(s.T(), "chain temporarily unavailable", errorMessage(payload))
+				retryAfter, err := strconv.Atoi(resp.Header.Get(fiber.HeaderRetryAfter))
+				require.NoError(s.T(), err)
+				assert.InDelta(s.T(), 90, retryAfter, 2)
 			},
 		},
 		{
@@ -352,13 +705,13 @@ func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_TableDriven() {
 			userID: "user-1",
 			body:   []byte(`{"amount_minor":100}`),
 			setupMock: func() {
-				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1").Return(vo.WalletWithdrawal{}, serviceErr)
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1", "").Return(vo.WalletWithdrawal{}, serviceErr)
 			},
 			headers: map[string]string{middlewares.ChainIDHeader: "chain-1"},
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
-				assert.Equal(s.T(), "internal server error", payload["error"])
+				assert.Equal(s.T(), "internal server error", errorMessage(payload))
 			},
 		},
 		{
@@ -366,7 +719,8 @@ func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_TableDriven() {
 			userID: "user-1",
 			body:   []byte(`{"amount_minor":100}`),
 			setupMock: func() {
-				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1").Return(vo.WalletWithdrawal{
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1", "").Return(vo.WalletWithdrawal{
+					ReferenceID:  "ref-1",
 					UserID:       "user-1",
 					AmountMinor:  100,
 					BalanceMinor: 900,
@@ -379,11 +733,33 @@ func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_TableDriven() {
 			assertion: func(resp *http.Response, payload map[string]interface{}) {
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+				assert.Equal(s.T(), "ref-1", payload["reference_id"])
 				assert.Equal(s.T(), "user-1", payload["user_id"])
 				assert.Equal(s.T(), float64(100), payload["amount_minor"])
 				assert.Equal(s.T(), "chain-1", payload["chain_id"])
 			},
 		},
+		{
+			name:   "payout outcome unknown is accepted as pending",
+			userID: "user-1",
+			body:   []byte(`{"amount_minor":100}`),
+			setupMock: func() {
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "chain-1", "").Return(vo.WalletWithdrawal{
+					ReferenceID: "ref-1",
+					UserID:      "user-1",
+					AmountMinor: 100,
+					ChainID:     "chain-1",
+					Status:      vo.WithdrawalStatusPending,
+				}, nil)
+			},
+			headers: map[string]string{middlewares.ChainIDHeader: "chain-1"},
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				require.NotNil(s.T(), resp)
+				assert.Equal(s.T(), fiber.StatusAccepted, resp.StatusCode)
+				assert.Equal(s.T(), "ref-1", payload["reference_id"])
+				assert.Equal(s.T(), vo.WithdrawalStatusPending, payload["status"])
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -408,6 +784,1569 @@ func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_TableDriven() {
 	}
 }
 
-func TestInquiryWithdrawBalanceHandlerSuite(t *testing.T) {
-	suite.Run(t, new(InquiryWithdrawBalanceHandlerSuite))
+func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_FieldValidation_TableDriven() {
+	tests := []struct {
+		name           string
+		body           []byte
+		setupMock      func()
+		expectedCode   int
+		expectedFields []interface{}
+	}{
+		{
+			name:         "missing amount",
+			body:         []byte(`{}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedFields: []interface{}{
+				map[string]interface{}{"field": "amount_minor", "message": "required, must be > 0"},
+			},
+		},
+		{
+			name:         "negative amount",
+			body:         []byte(`{"amount_minor":-5}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedFields: []interface{}{
+				map[string]interface{}{"field": "amount_minor", "message": "must be > 0"},
+			},
+		},
+		{
+			name:         "non-numeric amount",
+			body:         []byte(`{"amount_minor":"abc"}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedFields: []interface{}{
+				map[string]interface{}{"field": "amount_minor", "message": "must be an integer"},
+			},
+		},
+		{
+			name:         "malformed currency",
+			body:         []byte(`{"amount_minor":250,"currency":"rupiah"}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedFields: []interface{}{
+				map[string]interface{}{"field": "currency", "message": "must be a 3-letter ISO 4217 code"},
+			},
+		},
+		{
+			name: "currency is normalized before reaching the service",
+			body: []byte(`{"amount_minor":250,"currency":" idr "}`),
+			setupMock: func() {
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(250), "", "IDR").Return(vo.WalletWithdrawal{ReferenceID: "ref-1", AmountMinor: 250}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name: "valid input",
+			body: []byte(`{"amount_minor":250}`),
+			setupMock: func() {
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(250), "", "").Return(vo.WalletWithdrawal{ReferenceID: "ref-1", AmountMinor: 250}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.app.Post("/withdrawals", func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return s.handler.Handle(c)
+			})
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/withdrawals", tc.body, nil)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedFields == nil {
+				assert.Equal(s.T(), "ref-1", payload["reference_id"])
+				return
+			}
+
+			assert.Equal(s.T(), errorCodeValidationFailed, errorCode(payload))
+			assert.Equal(s.T(), tc.expectedFields, errorBody(payload)["fields"])
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_DecimalAmount_TableDriven() {
+	tests := []struct {
+		name           string
+		body           []byte
+		setupMock      func()
+		expectedCode   int
+		expectedFields []interface{}
+	}{
+		{
+			name: "decimal amount converted with wallet currency exponent",
+			body: []byte(`{"amount":"12.50"}`),
+			setupMock: func() {
+				s.service.EXPECT().WalletCurrency(mock.Anything, "user-1").Return("IDR", nil)
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(1250), "", "").Return(vo.WalletWithdrawal{ReferenceID: "ref-1", AmountMinor: 1250}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name: "whole decimal uses requested currency exponent",
+			body: []byte(`{"amount":"1500","currency":"JPY"}`),
+			setupMock: func() {
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(1500), "", "JPY").Return(vo.WalletWithdrawal{ReferenceID: "ref-1", AmountMinor: 1500}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name: "over-precise decimal rejected",
+			body: []byte(`{"amount":"12.505"}`),
+			setupMock: func() {
+				s.service.EXPECT().WalletCurrency(mock.Anything, "user-1").Return("IDR", nil)
+			},
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedFields: []interface{}{
+				map[string]interface{}{"field": "amount", "message": "must have at most 2 fractional digits"},
+			},
+		},
+		{
+			name:         "malformed decimal rejected",
+			body:         []byte(`{"amount":"12,50"}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedFields: []interface{}{
+				map[string]interface{}{"field": "amount", "message": `must be a decimal string such as "12.50"`},
+			},
+		},
+		{
+			name: "zero decimal rejected",
+			body: []byte(`{"amount":"0.00"}`),
+			setupMock: func() {
+				s.service.EXPECT().WalletCurrency(mock.Anything, "user-1").Return("IDR", nil)
+			},
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedFields: []interface{}{
+				map[string]interface{}{"field": "amount", "message": "must be > 0"},
+			},
+		},
+		{
+			name: "amount_minor takes precedence when both are given",
+			body: []byte(`{"amount_minor":250,"amount":"99.99"}`),
+			setupMock: func() {
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(250), "", "").Return(vo.WalletWithdrawal{ReferenceID: "ref-1", AmountMinor: 250}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name: "wallet lookup failure is mapped",
+			body: []byte(`{"amount":"1.00"}`),
+			setupMock: func() {
+				s.service.EXPECT().WalletCurrency(mock.Anything, "user-1").Return("", vo.ErrWalletNotFound)
+			},
+			expectedCode: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.app.Post("/withdrawals", func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return s.handler.Handle(c)
+			})
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/withdrawals", tc.body, nil)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			switch {
+			case tc.expectedCode == fiber.StatusOK:
+				assert.Equal(s.T(), "ref-1", payload["reference_id"])
+			case tc.expectedFields != nil:
+				assert.Equal(s.T(), errorCodeValidationFailed, errorCode(payload))
+				assert.Equal(s.T(), tc.expectedFields, errorBody(payload)["fields"])
+			}
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_ChainIDValidation_TableDriven() {
+	policy := ChainIDPolicy{Required: true, Supported: []string{"ethereum", "polygon"}}
+
+	tests := []struct {
+		name         string
+		policy       ChainIDPolicy
+		headers      map[string]string
+		setupMock    func()
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "missing but required chain id",
+			policy:       policy,
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  chainIDCodeRequired,
+		},
+		{
+			name:         "unsupported chain id",
+			policy:       policy,
+			headers:      map[string]string{ChainIDHeader: "solana"},
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  chainIDCodeUnsupported,
+		},
+		{
+			name:         "malformed chain id",
+			policy:       policy,
+			headers:      map[string]string{ChainIDHeader: "eth/../../mainnet"},
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  chainIDCodeMalformed,
+		},
+		{
+			name:    "supported chain id is forwarded to service",
+			policy:  policy,
+			headers: map[string]string{ChainIDHeader: "polygon"},
+			setupMock: func() {
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "polygon", "").Return(vo.WalletWithdrawal{ChainID: "polygon"}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:    "chain id matched case-insensitively uses configured spelling",
+			policy:  policy,
+			headers: map[string]string{ChainIDHeader: " Polygon "},
+			setupMock: func() {
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "polygon", "").Return(vo.WalletWithdrawal{ChainID: "polygon"}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name: "missing chain id allowed when not required",
+			policy: ChainIDPolicy{
+				Supported: []string{"ethereum"},
+			},
+			setupMock: func() {
+				s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "", "").Return(vo.WalletWithdrawal{}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			handler := NewInquiryWithdrawBalanceHandler(s.service, newTestLogger(), tc.policy)
+			s.app.Post("/withdrawals", func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return handler.Handle(c)
+			})
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), tc.headers)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr != "" {
+				assert.Equal(s.T(), tc.expectedErr, errorCode(payload))
+				assert.NotEmpty(s.T(), errorMessage(payload))
+			}
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceHandlerSuite) TestHandle_ErrorEnvelope_TableDriven() {
+	const requestID = "req-123"
+
+	tests := []struct {
+		name         string
+		serviceErr   error
+		expectedCode int
+		expectedErr  string
+	}{
+		{name: "invalid amount", serviceErr: vo.ErrInvalidAmount, expectedCode: fiber.StatusBadRequest, expectedErr: errorCodeInvalidAmount},
+		{name: "below minimum", serviceErr: vo.ErrAmountBelowMinimum, expectedCode: fiber.StatusBadRequest, expectedErr: errorCodeAmountBelowMinimum},
+		{name: "above maximum", serviceErr: vo.ErrAmountAboveMaximum, expectedCode: fiber.StatusBadRequest, expectedErr: errorCodeAmountAboveMaximum},
+		{name: "wallet not found", serviceErr: vo.ErrWalletNotFound, expectedCode: fiber.StatusNotFound, expectedErr: errorCodeWalletNotFound},
+		{name: "wallet frozen", serviceErr: vo.ErrWalletFrozen, expectedCode: fiber.StatusLocked, expectedErr: errorCodeWalletFrozen},
+		{name: "insufficient balance", serviceErr: vo.ErrInsufficientBalance, expectedCode: fiber.StatusConflict, expectedErr: errorCodeInsufficientBalance},
+		{name: "currency mismatch", serviceErr: vo.ErrCurrencyMismatch, expectedCode: fiber.StatusConflict, expectedErr: errorCodeCurrencyMismatch},
+		{name: "daily limit exceeded", serviceErr: vo.ErrDailyLimitExceeded, expectedCode: fiber.StatusConflict, expectedErr: errorCodeDailyLimitExceeded},
+		{name: "concurrent modification", serviceErr: vo.ErrConcurrentModification, expectedCode: fiber.StatusConflict, expectedErr: errorCodeConcurrentUpdate},
+		{name: "chain unavailable", serviceErr: vo.ErrChainUnavailable, expectedCode: fiber.StatusServiceUnavailable, expectedErr: errorCodeChainUnavailable},
+		{name: "payout unavailable", serviceErr: vo.ErrPayoutUnavailable, expectedCode: fiber.StatusServiceUnavailable, expectedErr: errorCodePayoutUnavailable},
+		{name: "payout rejected", serviceErr: vo.ErrPayoutRejected, expectedCode: fiber.StatusUnprocessableEntity, expectedErr: errorCodePayoutRejected},
+		{name: "unexpected error", serviceErr: errors.New("boom"), expectedCode: fiber.StatusInternalServerError, expectedErr: errorCodeInternal},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.app.Use(middlewares.NewHTTPRequestIDMiddleware())
+			s.app.Post("/withdrawals", func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return s.handler.Handle(c)
+			})
+			s.service.EXPECT().WithdrawBalance(mock.Anything, "user-1", int64(100), "", "").Return(vo.WalletWithdrawal{}, tc.serviceErr)
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), map[string]string{
+				middlewares.RequestIDHeader: requestID,
+			})
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			require.Len(s.T(), payload, 1)
+
+			body := errorBody(payload)
+			require.NotNil(s.T(), body)
+			assert.Equal(s.T(), tc.expectedErr, body["code"])
+			assert.NotEmpty(s.T(), body["message"])
+			assert.Equal(s.T(), requestID, body["request_id"])
+		})
+	}
+}
+
+func TestInquiryWithdrawBalanceHandlerSuite(t *testing.T) {
+	suite.Run(t, new(InquiryWithdrawBalanceHandlerSuite))
+}
+
+type WalletAdjustBalanceHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.WalletAdjustBalanceService
+	handler *WalletAdjustBalanceHandler
+	app     *fiber.App
+}
+
+func (s *WalletAdjustBalanceHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewWalletAdjustBalanceService(s.T())
+	s.handler = NewWalletAdjustBalanceHandler(s.service, newTestLogger())
+	s.app = fiber.New()
+	s.handler.Register(s.app)
+}
+
+func (s *WalletAdjustBalanceHandlerSuite) TestHandle_TableDriven() {
+	serviceErr := errors.New("service failed")
+
+	tests := []struct {
+		name         string
+		body         []byte
+		setupMock    func()
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "invalid request body",
+			body:         []byte(`{"amount_minor":`),
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  "invalid request body",
+		},
+		{
+			name: "zero amount",
+			body: []byte(`{"amount_minor":0}`),
+			setupMock: func() {
+				s.service.EXPECT().AdjustBalance(mock.Anything, "user-1", int64(0)).Return(vo.WalletAdjustment{}, vo.ErrInvalidAmount)
+			},
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  "amount_minor must not be 0",
+		},
+		{
+			name: "wallet not found",
+			body: []byte(`{"amount_minor":100}`),
+			setupMock: func() {
+				s.service.EXPECT().AdjustBalance(mock.Anything, "user-1", int64(100)).Return(vo.WalletAdjustment{}, vo.ErrWalletNotFound)
+			},
+			expectedCode: fiber.StatusNotFound,
+			expectedErr:  "wallet not found",
+		},
+		{
+			name: "debit exceeds balance",
+			body: []byte(`{"amount_minor":-100}`),
+			setupMock: func() {
+				s.service.EXPECT().AdjustBalance(mock.Anything, "user-1", int64(-100)).Return(vo.WalletAdjustment{}, vo.ErrInsufficientBalance)
+			},
+			expectedCode: fiber.StatusConflict,
+			expectedErr:  "insufficient balance",
+		},
+		{
+			name: "unexpected error",
+			body: []byte(`{"amount_minor":100}`),
+			setupMock: func() {
+				s.service.EXPECT().AdjustBalance(mock.Anything, "user-1", int64(100)).Return(vo.WalletAdjustment{}, serviceErr)
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  "internal server error",
+		},
+		{
+			name: "success",
+			body: []byte(`{"amount_minor":100}`),
+			setupMock: func() {
+				s.service.EXPECT().AdjustBalance(mock.Anything, "user-1", int64(100)).Return(vo.WalletAdjustment{
+					ReferenceID:  "ref-1",
+					UserID:       "user-1",
+					AmountMinor:  100,
+					BalanceMinor: 1100,
+					Currency:     "IDR",
+				}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/wallets/user-1/adjustments", tc.body, nil)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr != "" {
+				assert.Equal(s.T(), tc.expectedErr, errorMessage(payload))
+			} else {
+				assert.Equal(s.T(), "ref-1", payload["reference_id"])
+				assert.Equal(s.T(), float64(1100), payload["balance_minor"])
+			}
+		})
+	}
+}
+
+func TestWalletAdjustBalanceHandlerSuite(t *testing.T) {
+	suite.Run(t, new(WalletAdjustBalanceHandlerSuite))
+}
+
+type WalletStatusHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.WalletStatusService
+	handler *WalletStatusHandler
+	app     *fiber.App
+}
+
+func (s *WalletStatusHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewWalletStatusService(s.T())
+	s.handler = NewWalletStatusHandler(s.service, newTestLogger())
+	s.app = fiber.New()
+	s.handler.Register(s.app)
+}
+
+func (s *WalletStatusHandlerSuite) TestHandle_TableDriven() {
+	serviceErr := errors.New("service failed")
+
+	tests := []struct {
+		name         string
+		body         []byte
+		setupMock    func()
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "invalid request body",
+			body:         []byte(`{"status":`),
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  errorCodeInvalidRequestBody,
+		},
+		{
+			name:         "unknown status",
+			body:         []byte(`{"status":"closed"}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedErr:  errorCodeValidationFailed,
+		},
+		{
+			name:         "status must be a string",
+			body:         []byte(`{"status":1}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedErr:  errorCodeValidationFailed,
+		},
+		{
+			name: "wallet not found",
+			body: []byte(`{"status":"frozen"}`),
+			setupMock: func() {
+				s.service.EXPECT().SetStatus(mock.Anything, "user-1", vo.WalletStatusFrozen).Return(vo.WalletStatus{}, vo.ErrWalletNotFound)
+			},
+			expectedCode: fiber.StatusNotFound,
+			expectedErr:  errorCodeWalletNotFound,
+		},
+		{
+			name: "unexpected error",
+			body: []byte(`{"status":"frozen"}`),
+			setupMock: func() {
+				s.service.EXPECT().SetStatus(mock.Anything, "user-1", vo.WalletStatusFrozen).Return(vo.WalletStatus{}, serviceErr)
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  errorCodeInternal,
+		},
+		{
+			name: "status is normalized before freezing",
+			body: []byte(`{"status":" Frozen "}`),
+			setupMock: func() {
+				s.service.EXPECT().SetStatus(mock.Anything, "user-1", vo.WalletStatusFrozen).
+					Return(vo.WalletStatus{UserID: "user-1", Status: vo.WalletStatusFrozen}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPut, "/wallets/user-1/status", tc.body, nil)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr != "" {
+				assert.Equal(s.T(), tc.expectedErr, errorCode(payload))
+			} else {
+				assert.Equal(s.T(), "user-1", payload["user_id"])
+				assert.Equal(s.T(), vo.WalletStatusFrozen, payload["status"])
+			}
+		})
+	}
+}
+
+func TestWalletStatusHandlerSuite(t *testing.T) {
+	suite.Run(t, new(WalletStatusHandlerSuite))
+}
+
+type InquiryDepositBalanceHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.BalanceDepositService
+	handler *InquiryDepositBalanceHandler
+	app     *fiber.App
+}
+
+func (s *InquiryDepositBalanceHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewBalanceDepositService(s.T())
+	s.handler = NewInquiryDepositBalanceHandler(s.service, newTestLogger())
+	s.app = fiber.New()
+}
+
+func (s *InquiryDepositBalanceHandlerSuite) TestHandle_TableDriven() {
+	serviceErr := errors.New("service failed")
+
+	tests := []struct {
+		name         string
+		userID       string
+		body         []byte
+		setupMock    func()
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "missing authenticated user",
+			body:         []byte(`{"amount_minor":100}`),
+			expectedCode: fiber.StatusUnauthorized,
+			expectedErr:  "missing authenticated user",
+		},
+		{
+			name:         "invalid request body",
+			userID:       "user-1",
+			body:         []byte(`{"amount_minor":`),
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  "invalid request body",
+		},
+		{
+			name:   "invalid amount",
+			userID: "user-1",
+			body:   []byte(`{"amount_minor":0}`),
+			setupMock: func() {
+				s.service.EXPECT().DepositBalance(mock.Anything, "user-1", int64(0)).Return(vo.WalletDeposit{}, vo.ErrInvalidAmount)
+			},
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  "amount_minor must be greater than 0",
+		},
+		{
+			name:   "wallet not found",
+			userID: "user-1",
+			body:   []byte(`{"amount_minor":100}`),
+			setupMock: func() {
+				s.service.EXPECT().DepositBalance(mock.Anything, "user-1", int64(100)).Return(vo.WalletDeposit{}, vo.ErrWalletNotFound)
+			},
+			expectedCode: fiber.StatusNotFound,
+			expectedErr:  "wallet not found",
+		},
+		{
+			name:   "concurrent modification",
+			userID: "user-1",
+			body:   []byte(`{"amount_minor":100}`),
+			setupMock: func() {
+				s.service.EXPECT().DepositBalance(mock.Anything, "user-1", int64(100)).Return(vo.WalletDeposit{}, vo.ErrConcurrentModification)
+			},
+			expectedCode: fiber.StatusConflict,
+			expectedErr:  "wallet was modified concurrently, retry the request",
+		},
+		{
+			name:   "unexpected error",
+			userID: "user-1",
+			body:   []byte(`{"amount_minor":100}`),
+			setupMock: func() {
+				s.service.EXPECT().DepositBalance(mock.Anything, "user-1", int64(100)).Return(vo.WalletDeposit{}, serviceErr)
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  "internal server error",
+		},
+		{
+			name:   "success",
+			userID: "user-1",
+			body:   []byte(`{"amount_minor":100}`),
+			setupMock: func() {
+				s.service.EXPECT().DepositBalance(mock.Anything, "user-1", int64(100)).Return(vo.WalletDeposit{
+					ReferenceID:  "ref-1",
+					UserID:       "user-1",
+					AmountMinor:  100,
+					BalanceMinor: 1100,
+					Currency:     "IDR",
+				}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.app.Post("/deposits", func(c fiber.Ctx) error {
+				if tc.userID != "" {
+					c.Locals("user_id", tc.userID)
+				}
+				return s.handler.Handle(c)
+			})
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/deposits", tc.body, nil)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr != "" {
+				assert.Equal(s.T(), tc.expectedErr, errorMessage(payload))
+			} else {
+				assert.Equal(s.T(), "ref-1", payload["reference_id"])
+				assert.Equal(s.T(), float64(1100), payload["balance_minor"])
+			}
+		})
+	}
+}
+
+func TestInquiryDepositBalanceHandlerSuite(t *testing.T) {
+	suite.Run(t, new(InquiryDepositBalanceHandlerSuite))
+}
+
+type WalletCreateHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.WalletCreateService
+	handler *WalletCreateHandler
+	app     *fiber.App
+}
+
+func (s *WalletCreateHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewWalletCreateService(s.T())
+	s.handler = NewWalletCreateHandler(s.service, newTestLogger())
+	s.app = fiber.New()
+}
+
+func (s *WalletCreateHandlerSuite) TestHandle_TableDriven() {
+	serviceErr := errors.New("service failed")
+	created := vo.WalletCreated{WalletID: "wallet-1", UserID: "user-1", Currency: "USD"}
+
+	tests := []struct {
+		name         string
+		userID       string
+		body         []byte
+		setupMock    func()
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "missing authenticated user",
+			body:         []byte(`{"currency":"USD"}`),
+			expectedCode: fiber.StatusUnauthorized,
+			expectedErr:  errorCodeUnauthenticated,
+		},
+		{
+			name:         "invalid request body",
+			userID:       "user-1",
+			body:         []byte(`{"currency":`),
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  errorCodeInvalidRequestBody,
+		},
+		{
+			name:         "malformed currency",
+			userID:       "user-1",
+			body:         []byte(`{"currency":"US"}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedErr:  errorCodeValidationFailed,
+		},
+		{
+			name:   "empty body uses default currency",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().CreateWallet(mock.Anything, "user-1", "").Return(vo.WalletCreated{WalletID: "wallet-1", UserID: "user-1", Currency: "IDR"}, nil)
+			},
+			expectedCode: fiber.StatusCreated,
+		},
+		{
+			name:   "wallet already exists",
+			userID: "user-1",
+			body:   []byte(`{"currency":"USD"}`),
+			setupMock: func() {
+				s.service.EXPECT().CreateWallet(mock.Anything, "user-1", "USD").Return(vo.WalletCreated{}, vo.ErrWalletAlreadyExists)
+			},
+			expectedCode: fiber.StatusConflict,
+			expectedErr:  errorCodeWalletAlreadyExists,
+		},
+		{
+			name:   "unexpected error",
+			userID: "user-1",
+			body:   []byte(`{"currency":"USD"}`),
+			setupMock: func() {
+				s.service.EXPECT().CreateWallet(mock.Anything, "user-1", "USD").Return(vo.WalletCreated{}, serviceErr)
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  errorCodeInternal,
+		},
+		{
+			name:   "success",
+			userID: "user-1",
+			body:   []byte(`{"currency":"USD"}`),
+			setupMock: func() {
+				s.service.EXPECT().CreateWallet(mock.Anything, "user-1", "USD").Return(created, nil)
+			},
+			expectedCode: fiber.StatusCreated,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.app.Post("/wallets", func(c fiber.Ctx) error {
+				if tc.userID != "" {
+					c.Locals("user_id", tc.userID)
+				}
+				return s.handler.Handle(c)
+			})
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/wallets", tc.body, nil)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr != "" {
+				assert.Equal(s.T(), tc.expectedErr, errorCode(payload))
+			} else {
+				assert.Equal(s.T(), "wallet-1", payload["wallet_id"])
+				assert.Equal(s.T(), "user-1", payload["user_id"])
+				assert.Equal(s.T(), float64(0), payload["balance_minor"])
+			}
+		})
+	}
+}
+
+func TestWalletCreateHandlerSuite(t *testing.T) {
+	suite.Run(t, new(WalletCreateHandlerSuite))
+}
+
+type TransferBalanceHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.BalanceTransferService
+	handler *TransferBalanceHandler
+	app     *fiber.App
+}
+
+func (s *TransferBalanceHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewBalanceTransferService(s.T())
+	s.handler = NewTransferBalanceHandler(s.service, newTestLogger())
+	s.app = fiber.New()
+}
+
+func (s *TransferBalanceHandlerSuite) TestHandle_TableDriven() {
+	const (
+		sourceID      = "0198a3f0-0000-7000-8000-000000000001"
+		destinationID = "0198a3f0-0000-7000-8000-000000000002"
+	)
+	serviceErr := errors.New("service failed")
+	validBody := []byte(`{"source_wallet_id":"` + sourceID + `","destination_wallet_id":"` + destinationID + `","amount_minor":100}`)
+
+	tests := []struct {
+		name           string
+		userID         string
+		body           []byte
+		setupMock      func()
+		expectedCode   int
+		expectedErr    string
+		expectedFields []interface{}
+	}{
+		{
+			name:         "missing authenticated user",
+			body:         validBody,
+			expectedCode: fiber.StatusUnauthorized,
+			expectedErr:  "missing authenticated user",
+		},
+		{
+			name:         "invalid request body",
+			userID:       "user-1",
+			body:         []byte(`{"amount_minor":`),
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  "invalid request body",
+		},
+		{
+			name:         "same source and destination",
+			userID:       "user-1",
+			body:         []byte(`{"source_wallet_id":"` + sourceID + `","destination_wallet_id":"` + sourceID + `","amount_minor":100}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedFields: []interface{}{
+				map[string]interface{}{"field": "destination_wallet_id", "message": "must differ from source_wallet_id"},
+			},
+		},
+		{
+			name:         "missing fields",
+			userID:       "user-1",
+			body:         []byte(`{"source_wallet_id":"abc"}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedFields: []interface{}{
+				map[string]interface{}{"field": "source_wallet_id", "message": "must be a UUID"},
+				map[string]interface{}{"field": "destination_wallet_id", "message": "required"},
+				map[string]interface{}{"field": "amount_minor", "message": "required, must be > 0"},
+			},
+		},
+		{
+			name:   "wallet not owned by user",
+			userID: "user-1",
+			body:   validBody,
+			setupMock: func() {
+				s.service.EXPECT().TransferBalance(mock.Anything, "user-1", sourceID, destinationID, int64(100)).Return(vo.WalletTransfer{}, vo.ErrWalletNotFound)
+			},
+			expectedCode: fiber.StatusNotFound,
+			expectedErr:  "wallet not found",
+		},
+		{
+			name:   "insufficient balance",
+			userID: "user-1",
+			body:   validBody,
+			setupMock: func() {
+				s.service.EXPECT().TransferBalance(mock.Anything, "user-1", sourceID, destinationID, int64(100)).Return(vo.WalletTransfer{}, vo.ErrInsufficientBalance)
+			},
+			expectedCode: fiber.StatusConflict,
+			expectedErr:  "insufficient balance",
+		},
+		{
+			name:   "frozen source wallet",
+			userID: "user-1",
+			body:   validBody,
+			setupMock: func() {
+				s.service.EXPECT().TransferBalance(mock.Anything, "user-1", sourceID, destinationID, int64(100)).Return(vo.WalletTransfer{}, vo.ErrWalletFrozen)
+			},
+			expectedCode: fiber.StatusLocked,
+			expectedErr:  "wallet is frozen, withdrawals and transfers are blocked",
+		},
+		{
+			name:   "unexpected error",
+			userID: "user-1",
+			body:   validBody,
+			setupMock: func() {
+				s.service.EXPECT().TransferBalance(mock.Anything, "user-1", sourceID, destinationID, int64(100)).Return(vo.WalletTransfer{}, serviceErr)
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  "internal server error",
+		},
+		{
+			name:   "success",
+			userID: "user-1",
+			body:   validBody,
+			setupMock: func() {
+				s.service.EXPECT().TransferBalance(mock.Anything, "user-1", sourceID, destinationID, int64(100)).Return(vo.WalletTransfer{
+					ReferenceID:             "ref-1",
+					UserID:                  "user-1",
+					SourceWalletID:          sourceID,
+					DestinationWalletID:     destinationID,
+					AmountMinor:             100,
+					SourceBalanceMinor:      900,
+					DestinationBalanceMinor: 300,
+					Currency:                "IDR",
+				}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.app.Post("/transfers", func(c fiber.Ctx) error {
+				if tc.userID != "" {
+					c.Locals("user_id", tc.userID)
+				}
+				return s.handler.Handle(c)
+			})
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/transfers", tc.body, nil)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			switch {
+			case tc.expectedFields != nil:
+				assert.Equal(s.T(), tc.expectedFields, errorBody(payload)["fields"])
+			case tc.expectedErr != "":
+				assert.Equal(s.T(), tc.expectedErr, errorMessage(payload))
+			default:
+				assert.Equal(s.T(), "ref-1", payload["reference_id"])
+				assert.Equal(s.T(), float64(900), payload["source_balance_minor"])
+				assert.Equal(s.T(), float64(300), payload["destination_balance_minor"])
+			}
+		})
+	}
+}
+
+func TestTransferBalanceHandlerSuite(t *testing.T) {
+	suite.Run(t, new(TransferBalanceHandlerSuite))
+}
+
+type RateLimitResetHandlerSuite struct {
+	suite.Suite
+
+	limiter *handlermocks.RateLimitResetter
+	claims  *sharedjwt.Claims
+	app     *fiber.App
+}
+
+func (s *RateLimitResetHandlerSuite) SetupTest() {
+	s.limiter = handlermocks.NewRateLimitResetter(s.T())
+	s.claims = &sharedjwt.Claims{Subject: "admin-1", Scopes: []string{"ratelimit:reset"}}
+
+	handler := NewRateLimitResetHandler(map[string]RateLimitResetter{"withdraw": s.limiter}, newTestLogger())
+	s.app = fiber.New()
+	s.app.Use(func(c fiber.Ctx) error {
+		c.Locals("user_id", s.claims.Subject)
+		c.Locals("jwt_claims", s.claims)
+		return c.Next()
+	})
+	adminRouter := s.app.Group("/admin")
+	adminRouter.Use("/ratelimit", middlewares.NewHTTPRequireScopeMiddleware("ratelimit:reset"))
+	handler.Register(adminRouter)
+}
+
+func (s *RateLimitResetHandlerSuite) TestHandle_TableDriven() {
+	resetErr := errors.New("redis down")
+
+	tests := []struct {
+		name         string
+		scopes       []string
+		body         []byte
+		setupMock    func()
+		expectedCode int
+		expectedErr  string
+		expectedAll  bool
+	}{
+		{
+			name:         "non-admin caller",
+			scopes:       []string{"wallet:read"},
+			body:         []byte(`{"user_id":"user-9","scope":"withdraw"}`),
+			expectedCode: fiber.StatusForbidden,
+		},
+		{
+			name:         "invalid request body",
+			body:         []byte(`{"user_id":`),
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  "invalid request body",
+		},
+		{
+			name:         "missing user id",
+			body:         []byte(`{"scope":"withdraw"}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedErr:  "request validation failed",
+		},
+		{
+			name:         "unknown scope",
+			body:         []byte(`{"user_id":"user-9","scope":"auth"}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedErr:  "request validation failed",
+		},
+		{
+			name: "reset failure",
+			body: []byte(`{"user_id":"user-9","scope":"withdraw"}`),
+			setupMock: func() {
+				s.limiter.EXPECT().ResetKey(mock.Anything, "withdraw:user:user-9").Return(resetErr)
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  "internal server error",
+		},
+		{
+			name: "resets per-user key",
+			body: []byte(`{"user_id":" user-9 ","scope":"withdraw"}`),
+			setupMock: func() {
+				s.limiter.EXPECT().ResetKey(mock.Anything, middlewares.UserRateLimitKey("withdraw", "user-9")).Return(nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:         "non-admin caller cannot reset a whole scope",
+			scopes:       []string{"wallet:read"},
+			body:         []byte(`{"scope":"withdraw","all":true}`),
+			expectedCode: fiber.StatusForbidden,
+		},
+		{
+			name:         "user id given with all",
+			body:         []byte(`{"user_id":"user-9","scope":"withdraw","all":true}`),
+			expectedCode: fiber.StatusUnprocessableEntity,
+			expectedErr:  "request validation failed",
+		},
+		{
+			name: "scope reset failure",
+			body: []byte(`{"scope":"withdraw","all":true}`),
+			setupMock: func() {
+				s.limiter.EXPECT().ResetPrefix(mock.Anything, "withdraw").Return(resetErr)
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  "internal server error",
+		},
+		{
+			name: "resets every key of the scope",
+			body: []byte(`{"scope":"withdraw","all":true}`),
+			setupMock: func() {
+				s.limiter.EXPECT().ResetPrefix(mock.Anything, "withdraw").Return(nil)
+			},
+			expectedCode: fiber.StatusOK,
+			expectedAll:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.scopes != nil {
+				s.claims.Scopes = tc.scopes
+			}
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodPost, "/admin/ratelimit/reset", tc.body, nil)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+
+			switch {
+			case tc.expectedCode == fiber.StatusForbidden:
+				assert.Equal(s.T(), "insufficient scope", payload["error"])
+			case tc.expectedErr != "":
+				assert.Equal(s.T(), tc.expectedErr, errorMessage(payload))
+			case tc.expectedAll:
+				assert.NotContains(s.T(), payload, "user_id")
+				assert.Equal(s.T(), "withdraw", payload["scope"])
+				assert.Equal(s.T(), true, payload["all"])
+				assert.Equal(s.T(), true, payload["reset"])
+			default:
+				assert.Equal(s.T(), "user-9", payload["user_id"])
+				assert.Equal(s.T(), "withdraw", payload["scope"])
+				assert.Equal(s.T(), true, payload["reset"])
+			}
+		})
+	}
+}
+
+func TestRateLimitResetHandlerSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitResetHandlerSuite))
+}
+
+type WithdrawalStatusHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.WithdrawalStatusService
+	handler *WithdrawalStatusHandler
+	app     *fiber.App
+}
+
+func (s *WithdrawalStatusHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewWithdrawalStatusService(s.T())
+	s.handler = NewWithdrawalStatusHandler(s.service, newTestLogger())
+	s.app = fiber.New()
+}
+
+func (s *WithdrawalStatusHandlerSuite) TestHandle_TableDriven() {
+	serviceErr := errors.New("service failed")
+
+	tests := []struct {
+		name         string
+		userID       string
+		setupMock    func()
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "unauthenticated",
+			expectedCode: fiber.StatusUnauthorized,
+			expectedErr:  "missing authenticated user",
+		},
+		{
+			name:   "owned withdrawal",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().GetWithdrawal(mock.Anything, "user-1", "ref-1").Return(vo.WithdrawalStatus{
+					ReferenceID: "ref-1",
+					Status:      vo.WithdrawalStatusCompleted,
+					AmountMinor: 100,
+					Currency:    "IDR",
+				}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:   "unknown reference",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().GetWithdrawal(mock.Anything, "user-1", "ref-1").Return(vo.WithdrawalStatus{}, vo.ErrWithdrawalNotFound)
+			},
+			expectedCode: fiber.StatusNotFound,
+			expectedErr:  "withdrawal not found",
+		},
+		{
+			name:   "withdrawal owned by another user",
+			userID: "user-2",
+			setupMock: func() {
+				s.service.EXPECT().GetWithdrawal(mock.Anything, "user-2", "ref-1").Return(vo.WithdrawalStatus{}, vo.ErrWithdrawalNotFound)
+			},
+			expectedCode: fiber.StatusNotFound,
+			expectedErr:  "withdrawal not found",
+		},
+		{
+			name:   "unexpected error",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().GetWithdrawal(mock.Anything, "user-1", "ref-1").Return(vo.WithdrawalStatus{}, serviceErr)
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  "internal server error",
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.app.Use(func(c fiber.Ctx) error {
+				if tc.userID != "" {
+					c.Locals("user_id", tc.userID)
+				}
+				return c.Next()
+			})
+			s.handler.Register(s.app)
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, payload, _ := performJSONRequest(s.app, http.MethodGet, "/withdrawals/ref-1", nil, nil)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr != "" {
+				assert.Equal(s.T(), tc.expectedErr, errorMessage(payload))
+			} else {
+				assert.Equal(s.T(), "ref-1", payload["reference_id"])
+				assert.Equal(s.T(), "completed", payload["status"])
+			}
+		})
+	}
+}
+
+func TestWithdrawalStatusHandlerSuite(t *testing.T) {
+	suite.Run(t, new(WithdrawalStatusHandlerSuite))
+}
+
+func TestStatusForError_TableDriven(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "invalid credentials", err: vo.ErrInvalidCredentials, expectedStatus: fiber.StatusUnauthorized, expectedCode: errorCodeInvalidCredentials},
+		{name: "account locked", err: vo.ErrAccountLocked, expectedStatus: fiber.StatusLocked, expectedCode: errorCodeAccountLocked},
+		{name: "wallet frozen", err: vo.ErrWalletFrozen, expectedStatus: fiber.StatusLocked, expectedCode: errorCodeWalletFrozen},
+		{name: "invalid email", err: vo.ErrInvalidEmail, expectedStatus: fiber.StatusUnprocessableEntity, expectedCode: errorCodeInvalidEmail},
+		{name: "weak password", err: vo.ErrWeakPassword, expectedStatus: fiber.StatusUnprocessableEntity, expectedCode: errorCodeWeakPassword},
+		{name: "weak password detail", err: &vo.WeakPasswordError{Unmet: []string{"digit"}}, expectedStatus: fiber.StatusUnprocessableEntity, expectedCode: errorCodeWeakPassword},
+		{name: "invalid amount", err: vo.ErrInvalidAmount, expectedStatus: fiber.StatusBadRequest, expectedCode: errorCodeInvalidAmount},
+		{name: "below minimum", err: vo.ErrAmountBelowMinimum, expectedStatus: fiber.StatusBadRequest, expectedCode: errorCodeAmountBelowMinimum},
+		{name: "above maximum", err: vo.ErrAmountAboveMaximum, expectedStatus: fiber.StatusBadRequest, expectedCode: errorCodeAmountAboveMaximum},
+		{name: "same wallet transfer", err: vo.ErrSameWalletTransfer, expectedStatus: fiber.StatusBadRequest, expectedCode: errorCodeSameWalletTransfer},
+		{name: "invalid cursor", err: vo.ErrInvalidCursor, expectedStatus: fiber.StatusBadRequest, expectedCode: errorCodeInvalidCursor},
+		{name: "wallet not found", err: vo.ErrWalletNotFound, expectedStatus: fiber.StatusNotFound, expectedCode: errorCodeWalletNotFound},
+		{name: "withdrawal not found", err: vo.ErrWithdrawalNotFound, expectedStatus: fiber.StatusNotFound, expectedCode: errorCodeWithdrawalNotFound},
+		{name: "email already registered", err: vo.ErrEmailAlreadyRegistered, expectedStatus: fiber.StatusConflict, expectedCode: errorCodeEmailRegistered},
+		{name: "wallet already exists", err: vo.ErrWalletAlreadyExists, expectedStatus: fiber.StatusConflict, expectedCode: errorCodeWalletAlreadyExists},
+		{name: "insufficient balance", err: vo.ErrInsufficientBalance, expectedStatus: fiber.StatusConflict, expectedCode: errorCodeInsufficientBalance},
+		{name: "currency mismatch", err: vo.ErrCurrencyMismatch, expectedStatus: fiber.StatusConflict, expectedCode: errorCodeCurrencyMismatch},
+		{name: "concurrent modification", err: vo.ErrConcurrentModification, expectedStatus: fiber.StatusConflict, expectedCode: errorCodeConcurrentUpdate},
+		{name: "daily limit exceeded", err: vo.ErrDailyLimitExceeded, expectedStatus: fiber.StatusConflict, expectedCode: errorCodeDailyLimitExceeded},
+		{name: "chain unavailable", err: vo.ErrChainUnavailable, expectedStatus: fiber.StatusServiceUnavailable, expectedCode: errorCodeChainUnavailable},
+		{name: "chain unavailable detail", err: &vo.ChainUnavailableError{ChainID: "polygon", Until: time.Now().Add(time.Minute)}, expectedStatus: fiber.StatusServiceUnavailable, expectedCode: errorCodeChainUnavailable},
+		{name: "payout unavailable", err: vo.ErrPayoutUnavailable, expectedStatus: fiber.StatusServiceUnavailable, expectedCode: errorCodePayoutUnavailable},
+		{name: "payout rejected", err: vo.ErrPayoutRejected, expectedStatus: fiber.StatusUnprocessableEntity, expectedCode: errorCodePayoutRejected},
+		{name: "wrapped sentinel", err: fmt.Errorf("repository: %w", vo.ErrInsufficientBalance), expectedStatus: fiber.StatusConflict, expectedCode: errorCodeInsufficientBalance},
+		{name: "unregistered error falls back to internal", err: errors.New("boom"), expectedStatus: fiber.StatusInternalServerError, expectedCode: errorCodeInternal},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status, code := StatusForError(tc.err)
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedCode, code)
+		})
+	}
+
+	// Every registered sentinel must be covered above.
+	covered := make(map[error]bool, len(tests))
+	for _, tc := range tests {
+		covered[tc.err] = true
+	}
+	for _, mapping := range domainErrors {
+		assert.True(t, covered[mapping.err], "missing StatusForError case for %v", mapping.err)
+	}
+}
+
+func TestRespondDomainError_WeakPasswordListsUnmetCriteria(t *testing.T) {
+	app := fiber.New()
+	app.Post("/register", func(c fiber.Ctx) error {
+		return respondDomainError(c, &vo.WeakPasswordError{Unmet: []string{"min_length", "symbol"}}, nil)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/register", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var payload apiErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, errorCodeWeakPassword, payload.Error.Code)
+	assert.Equal(t, []fieldError{
+		{Field: "password", Message: "min_length"},
+		{Field: "password", Message: "symbol"},
+	}, payload.Error.Fields)
+}
+
+func TestRespondDomainError_DeadlineIsAnsweredByTimeoutMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(middlewares.NewHTTPTimeoutMiddleware(5 * time.Millisecond))
+	app.Post("/withdrawals", func(c fiber.Ctx) error {
+		<-c.Context().Done()
+		return respondDomainError(c, fmt.Errorf("repository: withdraw failed: %w", c.Context().Err()), nil)
+	})
+
+	resp, payload, _ := performJSONRequest(app, http.MethodPost, "/withdrawals", nil, nil)
+	require.NotNil(t, resp)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "request timed out", payload["error"])
+}
+
+func TestRespondDomainError_LocalizesMessage_TableDriven(t *testing.T) {
+	tests := []struct {
+		name             string
+		acceptLanguage   string
+		expectedMessage  string
+		expectedLanguage string
+	}{
+		{name: "supported locale", acceptLanguage: "id-ID,id;q=0.9,en;q=0.8", expectedMessage: "saldo tidak mencukupi", expectedLanguage: "id"},
+		{name: "supported locale after unsupported one", acceptLanguage: "fr-FR, id;q=0.5", expectedMessage: "saldo tidak mencukupi", expectedLanguage: "id"},
+		{name: "english preferred over supported locale", acceptLanguage: "id;q=0.2, en", expectedMessage: "insufficient balance", expectedLanguage: "en"},
+		{name: "unsupported locale falls back to english", acceptLanguage: "fr-FR,fr;q=0.9", expectedMessage: "insufficient balance", expectedLanguage: "en"},
+		{name: "missing header falls back to english", expectedMessage: "insufficient balance", expectedLanguage: "en"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/withdrawals", func(c fiber.Ctx) error {
+				return respondDomainError(c, vo.ErrInsufficientBalance, nil)
+			})
+
+			headers := map[string]string{}
+			if tc.acceptLanguage != "" {
+				headers[fiber.HeaderAcceptLanguage] = tc.acceptLanguage
+			}
+			resp, payload, _ := performJSONRequest(app, http.MethodPost, "/withdrawals", nil, headers)
+			require.NotNil(t, resp)
+			assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+			assert.Equal(t, errorCodeInsufficientBalance, errorCode(payload))
+			assert.Equal(t, tc.expectedMessage, errorMessage(payload))
+			assert.Equal(t, tc.expectedLanguage, resp.Header.Get(fiber.HeaderContentLanguage))
+		})
+	}
+
+	// Every registered domain error must have a translation in every catalog locale.
+	for locale, messages := range errorMessageCatalog {
+		for _, mapping := range domainErrors {
+			assert.NotEmpty(t, messages[mapping.code], "missing %s translation for %s", locale, mapping.code)
+		}
+	}
+}
+
+type TransactionExportHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.TransactionExportService
+	handler *TransactionExportHandler
+	app     *fiber.App
+}
+
+func (s *TransactionExportHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewTransactionExportService(s.T())
+	s.handler = NewTransactionExportHandler(s.service, newTestLogger())
+	s.app = fiber.New()
+}
+
+func emitTransactions(records []vo.TransactionRecord, streamErr error) func(context.Context, string, func(vo.TransactionRecord) error) error {
+	return func(_ context.Context, _ string, emit func(vo.TransactionRecord) error) error {
+		for _, record := range records {
+			if err := emit(record); err != nil {
+				return err
+			}
+		}
+		return streamErr
+	}
+}
+
+func (s *TransactionExportHandlerSuite) TestHandle_TableDriven() {
+	createdAt := time.Date(2026, 3, 4, 5, 6, 7, 8000, time.FixedZone("WIB", 7*60*60))
+	records := []vo.TransactionRecord{
+		{EntryID: "entry-1", WalletID: "wallet-1", EntryType: "deposit", AmountMinor: 1000, BalanceAfterMinor: 1000, Currency: "IDR", ReferenceID: "ref,1", CreatedAt: createdAt},
+		{EntryID: "entry-2", WalletID: "wallet-1", EntryType: "withdrawal", AmountMinor: -400, BalanceAfterMinor: 600, Currency: "IDR", ChainID: "polygon", CreatedAt: createdAt.Add(time.Second)},
+	}
+	header := "entry_id,wallet_id,entry_type,amount_minor,balance_after_minor,currency,reference_id,chain_id,created_at\n"
+
+	manyRecords := make([]vo.TransactionRecord, transactionExportFlushRows*2+1)
+	for i := range manyRecords {
+		manyRecords[i] = vo.TransactionRecord{EntryID: fmt.Sprintf("entry-%d", i), CreatedAt: createdAt}
+	}
+
+	tests := []struct {
+		name         string
+		userID       string
+		setupMock    func()
+		expectedCode int
+		expectedErr  string
+		expectedBody string
+		expectedRows int
+	}{
+		{
+			name:         "missing authenticated user",
+			expectedCode: fiber.StatusUnauthorized,
+			expectedErr:  errorCodeUnauthenticated,
+		},
+		{
+			name:   "empty ledger writes header only",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().ExportTransactions(mock.Anything, "user-1", mock.Anything).RunAndReturn(emitTransactions(nil, nil))
+			},
+			expectedCode: fiber.StatusOK,
+			expectedBody: header,
+		},
+		{
+			name:   "rows are formatted and quoted",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().ExportTransactions(mock.Anything, "user-1", mock.Anything).RunAndReturn(emitTransactions(records, nil))
+			},
+			expectedCode: fiber.StatusOK,
+			expectedBody: header +
+				"entry-1,wallet-1,deposit,1000,1000,IDR,\"ref,1\",,2026-03-03T22:06:07.000008Z\n" +
+				"entry-2,wallet-1,withdrawal,-400,600,IDR,,polygon,2026-03-03T22:06:08.000008Z\n",
+		},
+		{
+			name:   "streams past the flush threshold",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().ExportTransactions(mock.Anything, "user-1", mock.Anything).RunAndReturn(emitTransactions(manyRecords, nil))
+			},
+			expectedCode: fiber.StatusOK,
+			expectedRows: len(manyRecords),
+		},
+		{
+			name:   "failure mid-stream truncates the export",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().ExportTransactions(mock.Anything, "user-1", mock.Anything).RunAndReturn(emitTransactions(records[:1], errors.New("db down")))
+			},
+			expectedCode: fiber.StatusOK,
+			expectedBody: header + "entry-1,wallet-1,deposit,1000,1000,IDR,\"ref,1\",,2026-03-03T22:06:07.000008Z\n",
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.app.Get("/transactions/export", func(c fiber.Ctx) error {
+				if tc.userID != "" {
+					c.Locals("user_id", tc.userID)
+				}
+				return s.handler.Handle(c)
+			})
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/transactions/export", nil))
+			require.NoError(s.T(), err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(s.T(), err)
+
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr != "" {
+				var payload map[string]interface{}
+				require.NoError(s.T(), json.Unmarshal(body, &payload))
+				assert.Equal(s.T(), tc.expectedErr, errorCode(payload))
+				return
+			}
+
+			assert.Equal(s.T(), transactionExportContentType, resp.Header.Get(fiber.HeaderContentType))
+			assert.Equal(s.T(), `attachment; filename="transactions-user-1.csv"`, resp.Header.Get(fiber.HeaderContentDisposition))
+			if tc.expectedRows > 0 {
+				lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+				assert.Len(s.T(), lines, tc.expectedRows+1)
+				assert.Equal(s.T(), header, lines[0]+"\n")
+				return
+			}
+			assert.Equal(s.T(), tc.expectedBody, string(body))
+		})
+	}
+}
+
+func TestTransactionExportHandlerSuite(t *testing.T) {
+	suite.Run(t, new(TransactionExportHandlerSuite))
+}
+
+type TransactionHistoryHandlerSuite struct {
+	suite.Suite
+
+	service *handlermocks.TransactionHistoryService
+	handler *TransactionHistoryHandler
+	app     *fiber.App
+}
+
+func (s *TransactionHistoryHandlerSuite) SetupTest() {
+	s.service = handlermocks.NewTransactionHistoryService(s.T())
+	s.handler = NewTransactionHistoryHandler(s.service, newTestLogger())
+	s.app = fiber.New()
+}
+
+func (s *TransactionHistoryHandlerSuite) TestHandle_TableDriven() {
+	createdAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	page := vo.TransactionPage{
+		Items: []vo.TransactionRecord{
+			{EntryID: "entry-2", WalletID: "wallet-1", EntryType: "withdrawal", AmountMinor: -400, BalanceAfterMinor: 600, Currency: "IDR", CreatedAt: createdAt},
+		},
+		NextCursor: "next-token",
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		query          string
+		setupMock      func()
+		expectedCode   int
+		expectedErr    string
+		expectedItems  int
+		expectedCursor string
+	}{
+		{
+			name:         "missing authenticated user",
+			expectedCode: fiber.StatusUnauthorized,
+			expectedErr:  errorCodeUnauthenticated,
+		},
+		{
+			name:         "non-numeric limit",
+			userID:       "user-1",
+			query:        "?limit=ten",
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  errorCodeValidationFailed,
+		},
+		{
+			name:         "zero limit",
+			userID:       "user-1",
+			query:        "?limit=0",
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  errorCodeValidationFailed,
+		},
+		{
+			name:   "first page with default limit",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().ListTransactions(mock.Anything, "user-1", "", 0).Return(page, nil)
+			},
+			expectedCode:   fiber.StatusOK,
+			expectedItems:  1,
+			expectedCursor: "next-token",
+		},
+		{
+			name:   "cursor and limit are passed through",
+			userID: "user-1",
+			query:  "?limit=25&cursor=abc",
+			setupMock: func() {
+				s.service.EXPECT().ListTransactions(mock.Anything, "user-1", "abc", 25).Return(vo.TransactionPage{Items: []vo.TransactionRecord{}}, nil)
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:   "invalid cursor",
+			userID: "user-1",
+			query:  "?cursor=abc",
+			setupMock: func() {
+				s.service.EXPECT().ListTransactions(mock.Anything, "user-1", "abc", 0).Return(vo.TransactionPage{}, vo.ErrInvalidCursor)
+			},
+			expectedCode: fiber.StatusBadRequest,
+			expectedErr:  errorCodeInvalidCursor,
+		},
+		{
+			name:   "service failure",
+			userID: "user-1",
+			setupMock: func() {
+				s.service.EXPECT().ListTransactions(mock.Anything, "user-1", "", 0).Return(vo.TransactionPage{}, errors.New("db down"))
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedErr:  errorCodeInternal,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.app.Get("/transactions", func(c fiber.Ctx) error {
+				if tc.userID != "" {
+					c.Locals("user_id", tc.userID)
+				}
+				return s.handler.Handle(c)
+			})
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			resp, err := s.app.Test(httptest.NewRequest(http.MethodGet, "/transactions"+tc.query, nil))
+			require.NoError(s.T(), err)
+			defer resp.Body.Close()
+
+			var payload map[string]interface{}
+			require.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&payload))
+
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr != "" {
+				assert.Equal(s.T(), tc.expectedErr, errorCode(payload))
+				return
+			}
+
+			items, ok := payload["items"].([]interface{})
+			require.True(s.T(), ok)
+			assert.Len(s.T(), items, tc.expectedItems)
+			if tc.expectedCursor == "" {
+				assert.NotContains(s.T(), payload, "next_cursor")
+				return
+			}
+			assert.Equal(s.T(), tc.expectedCursor, payload["next_cursor"])
+		})
+	}
+}
+
+func TestTransactionHistoryHandlerSuite(t *testing.T) {
+	suite.Run(t, new(TransactionHistoryHandlerSuite))
 }
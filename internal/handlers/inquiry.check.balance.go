@@ -2,11 +2,11 @@ package handlers
 
 import (
 	"context"
-	"errors"
 	"log/slog"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
 )
 
 type BalanceInquiryService interface {
@@ -27,26 +27,17 @@ func (h *InquiryCheckBalanceHandler) Register(router fiber.Router) {
 }
 
 func (h *InquiryCheckBalanceHandler) Handle(c fiber.Ctx) error {
-	userIDValue := c.Locals("user_id")
-	userID, ok := userIDValue.(string)
-	if !ok || userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "missing authenticated user",
-		})
+	userID, ok := middlewares.UserIDFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
 	}
 
 	balance, err := h.service.CheckBalance(c.Context(), userID)
 	if err != nil {
-		if errors.Is(err, vo.ErrWalletNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "wallet not found",
-			})
+		if !isDomainError(err) {
+			h.logger.Error("failed to check balance", "user_id", userID, "error", err)
 		}
-
-		h.logger.Error("failed to check balance", "user_id", userID, "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "internal server error",
-		})
+		return respondDomainError(c, err, nil)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(balance)
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type RateLimitResetter interface {
+	ResetKey(ctx context.Context, key string) error
+	ResetPrefix(ctx context.Context, prefix string) error
+}
+
+// RateLimitResetHandler clears a user's bucket in one of the per-user rate limiters,
+// keyed by the scope the limiter was registered with. With "all" set it clears every
+// bucket of the scope instead, for incidents where a whole limit must be lifted.
+type RateLimitResetHandler struct {
+	limiters map[string]RateLimitResetter
+	logger   *slog.Logger
+}
+
+type rateLimitResetRequest struct {
+	UserID string `json:"user_id"`
+	Scope  string `json:"scope"`
+	All    bool   `json:"all"`
+}
+
+type rateLimitResetResponse struct {
+	UserID string `json:"user_id,omitempty"`
+	Scope  string `json:"scope"`
+	All    bool   `json:"all,omitempty"`
+	Reset  bool   `json:"reset"`
+}
+
+func NewRateLimitResetHandler(limiters map[string]RateLimitResetter, logger *slog.Logger) *RateLimitResetHandler {
+	return &RateLimitResetHandler{limiters: limiters, logger: logger}
+}
+
+func (h *RateLimitResetHandler) Register(router fiber.Router) {
+	router.Post("/ratelimit/reset", h.Handle)
+}
+
+func (h *RateLimitResetHandler) Handle(c fiber.Ctx) error {
+	actorID, _ := middlewares.UserIDFromContext(c)
+
+	var requestBody rateLimitResetRequest
+	if err := c.Bind().JSON(&requestBody); err != nil {
+		return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
+	}
+
+	userID := strings.TrimSpace(requestBody.UserID)
+	scope := strings.TrimSpace(requestBody.Scope)
+
+	var fields []fieldError
+	switch {
+	case requestBody.All && userID != "":
+		fields = append(fields, fieldError{Field: "user_id", Message: "must be empty when all is set"})
+	case !requestBody.All && userID == "":
+		fields = append(fields, fieldError{Field: "user_id", Message: "is required"})
+	}
+	limiter, ok := h.limiters[scope]
+	if !ok {
+		fields = append(fields, fieldError{Field: "scope", Message: "is not a resettable rate limit scope"})
+	}
+	if len(fields) > 0 {
+		return respondValidationError(c, fields)
+	}
+
+	if requestBody.All {
+		// Per-user keys are namespaced by scope, so the scope is the prefix of all of them.
+		if err := limiter.ResetPrefix(c.Context(), scope); err != nil {
+			h.logger.Error("failed to reset rate limit scope", "scope", scope, "actor_id", actorID, "error", err)
+			return respondError(c, fiber.StatusInternalServerError, errorCodeInternal, "internal server error")
+		}
+
+		h.logger.Warn("rate limit scope reset", "scope", scope, "actor_id", actorID)
+		return c.Status(fiber.StatusOK).JSON(rateLimitResetResponse{Scope: scope, All: true, Reset: true})
+	}
+
+	key := middlewares.UserRateLimitKey(scope, userID)
+	if err := limiter.ResetKey(c.Context(), key); err != nil {
+		h.logger.Error("failed to reset rate limit", "user_id", userID, "scope", scope, "actor_id", actorID, "error", err)
+		return respondError(c, fiber.StatusInternalServerError, errorCodeInternal, "internal server error")
+	}
+
+	h.logger.Info("rate limit reset", "user_id", userID, "scope", scope, "actor_id", actorID)
+	return c.Status(fiber.StatusOK).JSON(rateLimitResetResponse{UserID: userID, Scope: scope, Reset: true})
+}
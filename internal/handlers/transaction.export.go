@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+const (
+	transactionExportContentType = "text/csv; charset=utf-8"
+	// transactionExportFlushRows bounds how many rows sit in the buffer before
+	// they are pushed to the client.
+	transactionExportFlushRows = 100
+)
+
+var transactionExportHeader = []string{
+	"entry_id",
+	"wallet_id",
+	"entry_type",
+	"amount_minor",
+	"balance_after_minor",
+	"currency",
+	"reference_id",
+	"chain_id",
+	"created_at",
+}
+
+type TransactionExportService interface {
+	ExportTransactions(ctx context.Context, userID string, emit func(vo.TransactionRecord) error) error
+}
+
+type TransactionExportHandler struct {
+	service TransactionExportService
+	logger  *slog.Logger
+}
+
+func NewTransactionExportHandler(service TransactionExportService, logger *slog.Logger) *TransactionExportHandler {
+	return &TransactionExportHandler{service: service, logger: logger}
+}
+
+func (h *TransactionExportHandler) Register(router fiber.Router) {
+	router.Get("/transactions/export", h.Handle)
+}
+
+// Handle streams the user's full ledger as CSV, oldest entry first. Rows are written
+// as they are read, so the status is already 200 when the first row goes out; a
+// failure mid-stream ends the file early and is only logged.
+func (h *TransactionExportHandler) Handle(c fiber.Ctx) error {
+	userID, ok := middlewares.UserIDFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
+	}
+
+	// The body is written after Handle returns, when the request timeout has already
+	// been cancelled. A disconnected client stops the export through a failed write.
+	ctx := context.WithoutCancel(c.Context())
+
+	c.Set(fiber.HeaderContentType, transactionExportContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="transactions-%s.csv"`, userID))
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		rows, err := writeTransactionsCSV(ctx, w, h.service, userID)
+		if err != nil {
+			h.logger.Error("failed to export transactions", "user_id", userID, "rows", rows, "error", err)
+		}
+	})
+}
+
+func writeTransactionsCSV(ctx context.Context, w *bufio.Writer, service TransactionExportService, userID string) (int, error) {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(transactionExportHeader); err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	err := service.ExportTransactions(ctx, userID, func(record vo.TransactionRecord) error {
+		if err := csvWriter.Write(transactionCSVRow(record)); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%transactionExportFlushRows != 0 {
+			return nil
+		}
+
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return err
+		}
+		return w.Flush()
+	})
+
+	csvWriter.Flush()
+	if err == nil {
+		err = csvWriter.Error()
+	}
+
+	return rows, err
+}
+
+func transactionCSVRow(record vo.TransactionRecord) []string {
+	return []string{
+		record.EntryID,
+		record.WalletID,
+		record.EntryType,
+		strconv.FormatInt(record.AmountMinor, 10),
+		strconv.FormatInt(record.BalanceAfterMinor, 10),
+		record.Currency,
+		record.ReferenceID,
+		record.ChainID,
+		record.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
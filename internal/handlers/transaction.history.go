@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type TransactionHistoryService interface {
+	ListTransactions(ctx context.Context, userID, cursor string, limit int) (vo.TransactionPage, error)
+}
+
+type TransactionHistoryHandler struct {
+	service TransactionHistoryService
+	logger  *slog.Logger
+}
+
+func NewTransactionHistoryHandler(service TransactionHistoryService, logger *slog.Logger) *TransactionHistoryHandler {
+	return &TransactionHistoryHandler{service: service, logger: logger}
+}
+
+func (h *TransactionHistoryHandler) Register(router fiber.Router) {
+	router.Get("/transactions", h.Handle)
+}
+
+// Handle returns one page of the user's ledger, newest first. The next page is requested
+// with ?cursor=<next_cursor>; limit defaults to 50 and is capped at 200.
+func (h *TransactionHistoryHandler) Handle(c fiber.Ctx) error {
+	userID, ok := middlewares.UserIDFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
+	}
+
+	limit := 0
+	if rawLimit := strings.TrimSpace(c.Query("limit")); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil || parsed <= 0 {
+			return respondError(c, fiber.StatusBadRequest, errorCodeValidationFailed, "limit must be a positive integer")
+		}
+		limit = parsed
+	}
+
+	page, err := h.service.ListTransactions(c.Context(), userID, c.Query("cursor"), limit)
+	if err != nil {
+		if !isDomainError(err) {
+			h.logger.Error("failed to list transactions", "user_id", userID, "error", err)
+		}
+		return respondDomainError(c, err, nil)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(page)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type BalanceTransferService interface {
+	TransferBalance(ctx context.Context, userID, sourceWalletID, destinationWalletID string, amountMinor int64) (vo.WalletTransfer, error)
+}
+
+type TransferBalanceHandler struct {
+	service BalanceTransferService
+	logger  *slog.Logger
+}
+
+type transferRequest struct {
+	SourceWalletID      string `json:"source_wallet_id"`
+	DestinationWalletID string `json:"destination_wallet_id"`
+	AmountMinor         *int64 `json:"amount_minor"`
+}
+
+func (r transferRequest) validate() []fieldError {
+	var fields []fieldError
+
+	source := strings.TrimSpace(r.SourceWalletID)
+	destination := strings.TrimSpace(r.DestinationWalletID)
+
+	switch {
+	case source == "":
+		fields = append(fields, fieldError{Field: "source_wallet_id", Message: "required"})
+	case uuid.Validate(source) != nil:
+		fields = append(fields, fieldError{Field: "source_wallet_id", Message: "must be a UUID"})
+	}
+
+	switch {
+	case destination == "":
+		fields = append(fields, fieldError{Field: "destination_wallet_id", Message: "required"})
+	case uuid.Validate(destination) != nil:
+		fields = append(fields, fieldError{Field: "destination_wallet_id", Message: "must be a UUID"})
+	case strings.EqualFold(source, destination):
+		fields = append(fields, fieldError{Field: "destination_wallet_id", Message: "must differ from source_wallet_id"})
+	}
+
+	switch {
+	case r.AmountMinor == nil:
+		fields = append(fields, fieldError{Field: "amount_minor", Message: "required, must be > 0"})
+	case *r.AmountMinor <= 0:
+		fields = append(fields, fieldError{Field: "amount_minor", Message: "must be > 0"})
+	}
+
+	return fields
+}
+
+// transferErrorMessages words the currency mismatch in terms of the two wallets involved.
+var transferErrorMessages = map[error]string{
+	vo.ErrCurrencyMismatch: "source and destination wallet currencies differ",
+}
+
+func NewTransferBalanceHandler(service BalanceTransferService, logger *slog.Logger) *TransferBalanceHandler {
+	return &TransferBalanceHandler{service: service, logger: logger}
+}
+
+func (h *TransferBalanceHandler) Register(router fiber.Router) {
+	router.Post("/transfers", h.Handle)
+}
+
+func (h *TransferBalanceHandler) Handle(c fiber.Ctx) error {
+	userID, ok := middlewares.UserIDFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
+	}
+
+	var requestBody transferRequest
+	fields, err := decodeJSONBody(c.Body(), &requestBody)
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
+	}
+
+	if len(fields) == 0 {
+		fields = requestBody.validate()
+	}
+
+	if len(fields) > 0 {
+		return respondValidationError(c, fields)
+	}
+
+	result, err := h.service.TransferBalance(c.Context(), userID, strings.TrimSpace(requestBody.SourceWalletID), strings.TrimSpace(requestBody.DestinationWalletID), *requestBody.AmountMinor)
+	if err != nil {
+		if !isDomainError(err) {
+			h.logger.Error("failed to transfer balance", "user_id", userID, "error", err)
+		}
+		return respondDomainError(c, err, transferErrorMessages)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
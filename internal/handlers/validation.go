@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func decodeJSONBody(body []byte, out any) ([]fieldError, error) {
+	err := json.Unmarshal(body, out)
+	if err == nil {
+		return nil, nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []fieldError{{Field: typeErr.Field, Message: expectedTypeMessage(typeErr.Type)}}, nil
+	}
+
+	return nil, err
+}
+
+func expectedTypeMessage(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "must be an integer"
+	case reflect.String:
+		return "must be a string"
+	case reflect.Bool:
+		return "must be a boolean"
+	default:
+		return "has an invalid type"
+	}
+}
+
+func respondValidationError(c fiber.Ctx, fields []fieldError) error {
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(apiErrorResponse{
+		Error: apiError{
+			Code:      errorCodeValidationFailed,
+			Message:   localizedMessage(c, errorCodeValidationFailed, "request validation failed"),
+			RequestID: middlewares.RequestIDFromContext(c),
+			Fields:    fields,
+		},
+	})
+}
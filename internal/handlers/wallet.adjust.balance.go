@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type WalletAdjustBalanceService interface {
+	AdjustBalance(ctx context.Context, userID string, deltaMinor int64) (vo.WalletAdjustment, error)
+}
+
+type WalletAdjustBalanceHandler struct {
+	service WalletAdjustBalanceService
+	logger  *slog.Logger
+}
+
+type walletAdjustmentRequest struct {
+	AmountMinor int64 `json:"amount_minor"`
+}
+
+// walletAdjustErrorMessages reflects that adjustments may be negative.
+var walletAdjustErrorMessages = map[error]string{
+	vo.ErrInvalidAmount: "amount_minor must not be 0",
+}
+
+func NewWalletAdjustBalanceHandler(service WalletAdjustBalanceService, logger *slog.Logger) *WalletAdjustBalanceHandler {
+	return &WalletAdjustBalanceHandler{service: service, logger: logger}
+}
+
+func (h *WalletAdjustBalanceHandler) Register(router fiber.Router) {
+	router.Post("/wallets/:user_id/adjustments", h.Handle)
+}
+
+func (h *WalletAdjustBalanceHandler) Handle(c fiber.Ctx) error {
+	actorID, _ := middlewares.UserIDFromContext(c)
+	userID := c.Params("user_id")
+
+	var requestBody walletAdjustmentRequest
+	if err := c.Bind().JSON(&requestBody); err != nil {
+		return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
+	}
+
+	result, err := h.service.AdjustBalance(c.Context(), userID, requestBody.AmountMinor)
+	if err != nil {
+		if !isDomainError(err) {
+			h.logger.Error("failed to adjust balance", "user_id", userID, "actor_id", actorID, "error", err)
+		}
+		return respondDomainError(c, err, walletAdjustErrorMessages)
+	}
+
+	h.logger.Info("wallet balance adjusted", "user_id", userID, "actor_id", actorID, "amount_minor", result.AmountMinor, "reference_id", result.ReferenceID)
+	return c.Status(fiber.StatusOK).JSON(result)
+}
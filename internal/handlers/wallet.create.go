@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type WalletCreateService interface {
+	CreateWallet(ctx context.Context, userID, currency string) (vo.WalletCreated, error)
+}
+
+type WalletCreateHandler struct {
+	service WalletCreateService
+	logger  *slog.Logger
+}
+
+type walletCreateRequest struct {
+	Currency string `json:"currency"`
+}
+
+func (r walletCreateRequest) validate() []fieldError {
+	if currency := strings.TrimSpace(r.Currency); currency != "" && !currencyPattern.MatchString(currency) {
+		return []fieldError{{Field: "currency", Message: "must be a 3-letter ISO 4217 code"}}
+	}
+	return nil
+}
+
+func NewWalletCreateHandler(service WalletCreateService, logger *slog.Logger) *WalletCreateHandler {
+	return &WalletCreateHandler{service: service, logger: logger}
+}
+
+func (h *WalletCreateHandler) Register(router fiber.Router) {
+	router.Post("/wallets", h.Handle)
+}
+
+func (h *WalletCreateHandler) Handle(c fiber.Ctx) error {
+	userID, ok := middlewares.UserIDFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
+	}
+
+	// The body is optional: without one the wallet is opened in the default currency.
+	var requestBody walletCreateRequest
+	var fields []fieldError
+	if len(c.Body()) > 0 {
+		var err error
+		fields, err = decodeJSONBody(c.Body(), &requestBody)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
+		}
+	}
+	if len(fields) == 0 {
+		fields = requestBody.validate()
+	}
+	if len(fields) > 0 {
+		return respondValidationError(c, fields)
+	}
+
+	result, err := h.service.CreateWallet(c.Context(), userID, requestBody.Currency)
+	if err != nil {
+		if !isDomainError(err) {
+			h.logger.Error("failed to create wallet", "user_id", userID, "error", err)
+		}
+		return respondDomainError(c, err, nil)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
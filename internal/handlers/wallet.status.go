@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type WalletStatusService interface {
+	SetStatus(ctx context.Context, userID, status string) (vo.WalletStatus, error)
+}
+
+type WalletStatusHandler struct {
+	service WalletStatusService
+	logger  *slog.Logger
+}
+
+type walletStatusRequest struct {
+	Status string `json:"status"`
+}
+
+func NewWalletStatusHandler(service WalletStatusService, logger *slog.Logger) *WalletStatusHandler {
+	return &WalletStatusHandler{service: service, logger: logger}
+}
+
+func (h *WalletStatusHandler) Register(router fiber.Router) {
+	router.Put("/wallets/:user_id/status", h.Handle)
+}
+
+func (h *WalletStatusHandler) Handle(c fiber.Ctx) error {
+	actorID, _ := middlewares.UserIDFromContext(c)
+	userID := c.Params("user_id")
+
+	var requestBody walletStatusRequest
+	fields, err := decodeJSONBody(c.Body(), &requestBody)
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
+	}
+
+	status := strings.ToLower(strings.TrimSpace(requestBody.Status))
+	if len(fields) == 0 && status != vo.WalletStatusActive && status != vo.WalletStatusFrozen {
+		fields = []fieldError{{Field: "status", Message: `must be "active" or "frozen"`}}
+	}
+	if len(fields) > 0 {
+		return respondValidationError(c, fields)
+	}
+
+	result, err := h.service.SetStatus(c.Context(), userID, status)
+	if err != nil {
+		if !isDomainError(err) {
+			h.logger.Error("failed to set wallet status", "user_id", userID, "actor_id", actorID, "error", err)
+		}
+		return respondDomainError(c, err, nil)
+	}
+
+	h.logger.Info("wallet status changed", "user_id", userID, "actor_id", actorID, "status", result.Status)
+	return c.Status(fiber.StatusOK).JSON(result)
+}
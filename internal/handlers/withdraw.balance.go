@@ -3,28 +3,87 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/joshuarp/withdraw-api/internal/domain/vo"
 	"github.com/joshuarp/withdraw-api/internal/middlewares"
+	sharedaudit "github.com/joshuarp/withdraw-api/internal/shared/audit"
 )
 
 type BalanceWithdrawService interface {
-	WithdrawBalance(ctx context.Context, userID string, amountMinor int64, chainID string) (vo.WalletWithdrawal, error)
+	WalletCurrency(ctx context.Context, userID string) (string, error)
+	WithdrawBalance(ctx context.Context, userID string, amountMinor int64, chainID, currency string) (vo.WalletWithdrawal, error)
+}
+
+const (
+	ChainIDHeader = "X-Chain-ID"
+
+	chainIDCodeRequired    = "CHAIN_ID_REQUIRED"
+	chainIDCodeMalformed   = "CHAIN_ID_MALFORMED"
+	chainIDCodeUnsupported = "CHAIN_ID_UNSUPPORTED"
+)
+
+var (
+	chainIDPattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,63}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Za-z]{3}$`)
+
+	decimalAmountPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+)
+
+type ChainIDPolicy struct {
+	Required  bool
+	Supported []string
+}
+
+func (p ChainIDPolicy) enabled() bool {
+	return p.Required || len(p.Supported) > 0
 }
 
 type InquiryWithdrawBalanceHandler struct {
-	service BalanceWithdrawService
-	logger  *slog.Logger
+	service       BalanceWithdrawService
+	logger        *slog.Logger
+	chainIDPolicy ChainIDPolicy
 }
 
 type withdrawalRequest struct {
-	AmountMinor int64 `json:"amount_minor"`
+	AmountMinor *int64  `json:"amount_minor"`
+	Amount      *string `json:"amount"`
+	Currency    string  `json:"currency"`
+}
+
+func (r withdrawalRequest) validate() []fieldError {
+	var fields []fieldError
+
+	switch {
+	case r.AmountMinor != nil:
+		if *r.AmountMinor <= 0 {
+			fields = append(fields, fieldError{Field: "amount_minor", Message: "must be > 0"})
+		}
+	case r.Amount != nil:
+		if !decimalAmountPattern.MatchString(strings.TrimSpace(*r.Amount)) {
+			fields = append(fields, fieldError{Field: "amount", Message: `must be a decimal string such as "12.50"`})
+		}
+	default:
+		fields = append(fields, fieldError{Field: "amount_minor", Message: "required, must be > 0"})
+	}
+
+	if currency := strings.TrimSpace(r.Currency); currency != "" && !currencyPattern.MatchString(currency) {
+		fields = append(fields, fieldError{Field: "currency", Message: "must be a 3-letter ISO 4217 code"})
+	}
+
+	return fields
 }
 
-func NewInquiryWithdrawBalanceHandler(service BalanceWithdrawService, logger *slog.Logger) *InquiryWithdrawBalanceHandler {
-	return &InquiryWithdrawBalanceHandler{service: service, logger: logger}
+func NewInquiryWithdrawBalanceHandler(service BalanceWithdrawService, logger *slog.Logger, chainIDPolicy ChainIDPolicy) *InquiryWithdrawBalanceHandler {
+	return &InquiryWithdrawBalanceHandler{service: service, logger: logger, chainIDPolicy: chainIDPolicy}
 }
 
 func (h *InquiryWithdrawBalanceHandler) Register(router fiber.Router) {
@@ -32,36 +91,120 @@ func (h *InquiryWithdrawBalanceHandler) Register(router fiber.Router) {
 }
 
 func (h *InquiryWithdrawBalanceHandler) Handle(c fiber.Ctx) error {
-	userIDValue := c.Locals("user_id")
-	userID, ok := userIDValue.(string)
-	if !ok || userID == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "missing authenticated user",
-		})
+	userID, ok := middlewares.UserIDFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
 	}
 
 	var requestBody withdrawalRequest
-	if err := c.Bind().JSON(&requestBody); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid request body",
-		})
+	fields, err := decodeJSONBody(c.Body(), &requestBody)
+	if err != nil {
+		return respondError(c, fiber.StatusBadRequest, errorCodeInvalidRequestBody, "invalid request body")
+	}
+
+	if len(fields) == 0 {
+		fields = requestBody.validate()
 	}
 
 	chainID := middlewares.ChainIDFromContext(c)
-	result, err := h.service.WithdrawBalance(c.Context(), userID, requestBody.AmountMinor, chainID)
-	if err != nil {
+	if h.chainIDPolicy.enabled() {
+		validated, code, message := h.validateChainID(c.Get(ChainIDHeader))
+		if code != "" {
+			return respondError(c, fiber.StatusBadRequest, code, message)
+		}
+		if validated != "" {
+			chainID = validated
+		}
+	}
+
+	if len(fields) > 0 {
+		return respondValidationError(c, fields)
+	}
+
+	ctx := sharedaudit.WithRequestID(c.Context(), middlewares.RequestIDFromContext(c))
+	currency := strings.ToUpper(strings.TrimSpace(requestBody.Currency))
+
+	var amountMinor int64
+	if requestBody.AmountMinor != nil {
+		amountMinor = *requestBody.AmountMinor
+	} else {
+		exponentCurrency := currency
+		if exponentCurrency == "" {
+			exponentCurrency, err = h.service.WalletCurrency(ctx, userID)
+			if err != nil {
+				return h.respondWithdrawError(c, userID, err)
+			}
+		}
+
+		exponent := vo.CurrencyExponent(exponentCurrency)
+		amountMinor, err = vo.ParseMinorUnits(*requestBody.Amount, exponent)
 		switch {
-		case errors.Is(err, vo.ErrInvalidAmount):
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "amount_minor must be greater than 0"})
-		case errors.Is(err, vo.ErrWalletNotFound):
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "wallet not found"})
-		case errors.Is(err, vo.ErrInsufficientBalance):
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "insufficient balance"})
-		default:
-			h.logger.Error("failed to withdraw balance", "user_id", userID, "error", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+		case errors.Is(err, vo.ErrAmountTooPrecise):
+			return respondValidationError(c, []fieldError{{Field: "amount", Message: fmt.Sprintf("must have at most %d fractional digits", exponent)}})
+		case err != nil:
+			return respondValidationError(c, []fieldError{{Field: "amount", Message: `must be a decimal string such as "12.50"`}})
+		case amountMinor <= 0:
+			return respondValidationError(c, []fieldError{{Field: "amount", Message: "must be > 0"}})
 		}
 	}
 
+	result, err := h.service.WithdrawBalance(ctx, userID, amountMinor, chainID, currency)
+	if err != nil {
+		return h.respondWithdrawError(c, userID, err)
+	}
+
+	// The debit is committed but the payout outcome is not known yet; the client polls
+	// GET /withdrawals/{reference_id} until the reconciler settles it.
+	if result.Status == vo.WithdrawalStatusPending {
+		return c.Status(fiber.StatusAccepted).JSON(result)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(result)
 }
+
+// respondWithdrawError adds the withdrawal-specific side effects (Retry-After, payout
+// logging) before writing the registered error response.
+func (h *InquiryWithdrawBalanceHandler) respondWithdrawError(c fiber.Ctx, userID string, err error) error {
+	var unavailable *vo.ChainUnavailableError
+	switch {
+	case errors.As(err, &unavailable):
+		retryAfter := int(math.Ceil(time.Until(unavailable.Until).Seconds()))
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(retryAfter, 1)))
+	case errors.Is(err, vo.ErrPayoutUnavailable):
+		h.logger.Warn("payout provider unavailable, withdrawal not processed", "user_id", userID, "error", err)
+	case errors.Is(err, vo.ErrPayoutRejected):
+		h.logger.Warn("payout rejected, withdrawal reversed", "user_id", userID, "error", err)
+	case !isDomainError(err):
+		h.logger.Error("failed to withdraw balance", "user_id", userID, "error", err)
+	}
+
+	return respondDomainError(c, err, nil)
+}
+
+func (h *InquiryWithdrawBalanceHandler) validateChainID(raw string) (string, string, string) {
+	chainID := strings.TrimSpace(raw)
+	if chainID == "" {
+		if h.chainIDPolicy.Required {
+			return "", chainIDCodeRequired, "chain id is required"
+		}
+		return "", "", ""
+	}
+
+	if !chainIDPattern.MatchString(chainID) {
+		return "", chainIDCodeMalformed, "chain id is malformed"
+	}
+
+	if len(h.chainIDPolicy.Supported) == 0 {
+		return chainID, "", ""
+	}
+
+	// Store the configured spelling so "Polygon" and "polygon" land in the ledger as one chain.
+	index := slices.IndexFunc(h.chainIDPolicy.Supported, func(supported string) bool {
+		return strings.EqualFold(strings.TrimSpace(supported), chainID)
+	})
+	if index < 0 {
+		return "", chainIDCodeUnsupported, "chain id is not supported"
+	}
+
+	return strings.TrimSpace(h.chainIDPolicy.Supported[index]), "", ""
+}
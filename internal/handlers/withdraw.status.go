@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/middlewares"
+)
+
+type WithdrawalStatusService interface {
+	GetWithdrawal(ctx context.Context, userID, referenceID string) (vo.WithdrawalStatus, error)
+}
+
+type WithdrawalStatusHandler struct {
+	service WithdrawalStatusService
+	logger  *slog.Logger
+}
+
+func NewWithdrawalStatusHandler(service WithdrawalStatusService, logger *slog.Logger) *WithdrawalStatusHandler {
+	return &WithdrawalStatusHandler{service: service, logger: logger}
+}
+
+func (h *WithdrawalStatusHandler) Register(router fiber.Router) {
+	router.Get("/withdrawals/:id", h.Handle)
+}
+
+// Handle looks a withdrawal up by the reference_id returned when it was submitted.
+func (h *WithdrawalStatusHandler) Handle(c fiber.Ctx) error {
+	userID, ok := middlewares.UserIDFromContext(c)
+	if !ok {
+		return respondError(c, fiber.StatusUnauthorized, errorCodeUnauthenticated, "missing authenticated user")
+	}
+
+	referenceID := c.Params("id")
+	status, err := h.service.GetWithdrawal(c.Context(), userID, referenceID)
+	if err != nil {
+		if !isDomainError(err) {
+			h.logger.Error("failed to get withdrawal", "user_id", userID, "reference_id", referenceID, "error", err)
+		}
+		return respondDomainError(c, err, nil)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(status)
+}
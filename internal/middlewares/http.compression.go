@@ -0,0 +1,140 @@
+package middlewares
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const defaultCompressionMinSize = 1024
+
+// compressibleContentTypes are the response media types worth compressing; anything else
+// (images, archives) is usually compressed already.
+var compressibleContentTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/xml",
+	"text/",
+}
+
+// CompressionConfig controls response compression. Responses smaller than MinSize bytes
+// are sent as is, since the gzip framing outweighs the saving.
+type CompressionConfig struct {
+	MinSize int
+}
+
+// NewHTTPCompressionMiddleware gzip- or deflate-encodes buffered responses according to
+// the request's Accept-Encoding. Streamed bodies (e.g. the CSV export), error responses
+// rendered by the error handler and already-encoded bodies are left untouched.
+func NewHTTPCompressionMiddleware(cfg CompressionConfig) fiber.Handler {
+	minSize := cfg.MinSize
+	if minSize <= 0 {
+		minSize = defaultCompressionMinSize
+	}
+
+	return func(c fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		response := c.Response()
+		if c.Method() == fiber.MethodHead || response.IsBodyStream() || len(response.Header.Peek(fiber.HeaderContentEncoding)) > 0 {
+			return nil
+		}
+
+		if !isCompressibleContentType(string(response.Header.ContentType())) {
+			return nil
+		}
+
+		c.Vary(fiber.HeaderAcceptEncoding)
+
+		body := response.Body()
+		if len(body) < minSize {
+			return nil
+		}
+
+		encoding := negotiateContentEncoding(c.Get(fiber.HeaderAcceptEncoding))
+		if encoding == "" {
+			return nil
+		}
+
+		compressed, err := compressBody(encoding, body)
+		if err != nil {
+			// Sending the body uncompressed is always a valid response.
+			return nil
+		}
+
+		response.SetBodyRaw(compressed)
+		c.Set(fiber.HeaderContentEncoding, encoding)
+		return nil
+	}
+}
+
+func isCompressibleContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, compressible := range compressibleContentTypes {
+		if mediaType == compressible || (strings.HasSuffix(compressible, "/") && strings.HasPrefix(mediaType, compressible)) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateContentEncoding picks gzip or deflate from an Accept-Encoding header by
+// q-value, preferring gzip on ties. It returns "" when neither is acceptable.
+func negotiateContentEncoding(acceptEncoding string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		quality := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		qualities[name] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range []string{"gzip", "deflate"} {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality, ok = qualities["*"]
+		}
+		if ok && quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// compressBody encodes body for the negotiated coding. HTTP "deflate" is the zlib format
+// (RFC 9110), not a raw deflate stream.
+func compressBody(encoding string, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var writer io.WriteCloser = zlib.NewWriter(&buf)
+	if encoding == "gzip" {
+		writer = gzip.NewWriter(&buf)
+	}
+
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/joshuarp/withdraw-api/internal/shared/ratelimit"
+)
+
+type ConcurrencyLimitConfig struct {
+	Limiter      ratelimit.ConcurrencyLimiter
+	Skipper      func(c fiber.Ctx) bool
+	KeyExtractor func(c fiber.Ctx) string
+	Logger       *slog.Logger
+	// StatusCode is returned when the cap is reached; defaults to 429.
+	StatusCode int
+}
+
+// NewHTTPConcurrencyLimitMiddleware caps simultaneous in-flight requests per key. The slot
+// is released by a deferred call, so it is returned even when a later handler errors or panics.
+func NewHTTPConcurrencyLimitMiddleware(cfg ConcurrencyLimitConfig) fiber.Handler {
+	if cfg.Limiter == nil {
+		return func(c fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	if cfg.Skipper == nil {
+		cfg.Skipper = func(c fiber.Ctx) bool { return false }
+	}
+
+	if cfg.KeyExtractor == nil {
+		cfg.KeyExtractor = defaultKeyExtractor
+	}
+
+	if cfg.StatusCode == 0 {
+		cfg.StatusCode = fiber.StatusTooManyRequests
+	}
+
+	return func(c fiber.Ctx) error {
+		if cfg.Skipper(c) {
+			return c.Next()
+		}
+
+		ctx := c.Context()
+		key := cfg.KeyExtractor(c)
+
+		acquired, err := cfg.Limiter.Acquire(ctx, key)
+		if err != nil {
+			if cfg.Logger != nil {
+				cfg.Logger.Error("concurrency limit check failed", "error", err, "key", key)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "internal server error",
+			})
+		}
+
+		if !acquired {
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return c.Status(cfg.StatusCode).JSON(fiber.Map{
+				"error": "too many concurrent requests",
+			})
+		}
+
+		defer func() {
+			// The request context may already be cancelled (e.g. by the timeout middleware),
+			// which must not stop the slot from being returned.
+			if err := cfg.Limiter.Release(context.WithoutCancel(ctx), key); err != nil && cfg.Logger != nil {
+				cfg.Logger.Error("concurrency limit release failed", "error", err, "key", key)
+			}
+		}()
+
+		return c.Next()
+	}
+}
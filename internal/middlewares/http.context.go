@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	sharedjwt "github.com/joshuarp/withdraw-api/internal/shared/jwt"
+)
+
+const (
+	// ChainIDHeader carries the blockchain a withdrawal targets. It is a business value,
+	// unrelated to request correlation (see RequestIDHeader).
+	ChainIDHeader = "X-Chain-ID"
+
+	userIDLocalKey    = "user_id"
+	jwtClaimsLocalKey = "jwt_claims"
+	outcomesLocalKey  = "request_outcomes"
+)
+
+// Outcomes recorded by middlewares that answer a request themselves, or that could not
+// finish their part of it. The request log middleware writes them to the "outcome" field
+// of the final log line.
+const (
+	OutcomeRateLimited             = "rate_limited"
+	OutcomeIdempotencyReplayed     = "idempotency_replayed"
+	OutcomeIdempotencyInProgress   = "idempotency_in_progress"
+	OutcomeIdempotencyConflict     = "idempotency_conflict"
+	OutcomeIdempotencyDeadLettered = "idempotency_dead_lettered"
+)
+
+// UserIDFromContext returns the authenticated user ID stored by the JWT middleware.
+// ok is false when the local is missing, not a string, or blank.
+func UserIDFromContext(c fiber.Ctx) (string, bool) {
+	userID, ok := c.Locals(userIDLocalKey).(string)
+	if !ok || strings.TrimSpace(userID) == "" {
+		return "", false
+	}
+	return userID, true
+}
+
+// ClaimsFromContext returns the verified token claims stored by the JWT middleware.
+// ok is false when the local is missing, of another type, or a nil pointer.
+func ClaimsFromContext(c fiber.Ctx) (*sharedjwt.Claims, bool) {
+	claims, ok := c.Locals(jwtClaimsLocalKey).(*sharedjwt.Claims)
+	if !ok || claims == nil {
+		return nil, false
+	}
+	return claims, true
+}
+
+// ChainIDFromContext returns the trimmed X-Chain-ID request header.
+func ChainIDFromContext(c fiber.Ctx) string {
+	return strings.TrimSpace(c.Get(ChainIDHeader))
+}
+
+// AddRequestOutcome records outcome on the request so the request log can report it.
+// Outcomes keep the order they were added in; blank values are ignored.
+func AddRequestOutcome(c fiber.Ctx, outcome string) {
+	outcome = strings.TrimSpace(outcome)
+	if outcome == "" {
+		return
+	}
+	c.Locals(outcomesLocalKey, append(RequestOutcomesFromContext(c), outcome))
+}
+
+// RequestOutcomesFromContext returns the outcomes recorded with AddRequestOutcome, or nil
+// when there are none.
+func RequestOutcomesFromContext(c fiber.Ctx) []string {
+	outcomes, _ := c.Locals(outcomesLocalKey).([]string)
+	return outcomes
+}
@@ -1,14 +1,72 @@
 package middlewares
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/cors"
 )
 
-func NewHTTPCORSMiddleware() fiber.Handler {
+var defaultCORSMethods = []string{
+	fiber.MethodGet,
+	fiber.MethodPost,
+	fiber.MethodPut,
+	fiber.MethodDelete,
+	fiber.MethodOptions,
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+
+	// Next skips the middleware when it returns true, leaving the request to
+	// another CORS instance mounted for that path.
+	Next func(c fiber.Ctx) bool
+}
+
+func NewHTTPCORSMiddleware(cfg CORSConfig) (fiber.Handler, error) {
+	origins := make([]string, 0, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+
+		if origin == "*" {
+			if cfg.AllowCredentials {
+				return nil, errors.New("middlewares: cors wildcard origin is not allowed with credentials")
+			}
+		} else if parsed, err := url.Parse(origin); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("middlewares: invalid cors origin %q", origin)
+		}
+
+		origins = append(origins, origin)
+	}
+
+	if len(origins) == 0 {
+		origins = []string{"*"}
+		if cfg.AllowCredentials {
+			return nil, errors.New("middlewares: cors credentials require explicit allowed origins")
+		}
+	}
+
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+
 	return cors.New(cors.Config{
-		AllowOrigins: []string{"*"},
-		AllowHeaders: []string{"Origin, Content-Type, Accept, Authorization"},
-		AllowMethods: []string{"GET, POST, PUT, DELETE, OPTIONS"},
-	})
+		Next:             cfg.Next,
+		AllowOrigins:     origins,
+		AllowHeaders:     []string{"Origin, Content-Type, Accept, Authorization"},
+		AllowMethods:     methods,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge / time.Second),
+	}), nil
 }
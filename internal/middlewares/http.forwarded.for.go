@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// NewHTTPForwardedForMiddleware narrows header, on requests arriving from one of
+// trustedProxies, to the single address c.IP() should report: the rightmost entry of the
+// chain that is not itself a trusted proxy. Each proxy appends the peer it saw, so only
+// the entries added by trusted proxies are reliable; anything further left came from the
+// client and could be forged to dodge per-IP rate limits. When no such entry exists the
+// connecting proxy's own address is used. It must run before anything reads c.IP().
+func NewHTTPForwardedForMiddleware(trustedProxies []string, header string) (fiber.Handler, error) {
+	prefixes, err := parseAllowlist(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("middlewares: invalid trusted proxies: %w", err)
+	}
+
+	return func(c fiber.Ctx) error {
+		peer := c.RequestCtx().RemoteIP().String()
+		if !allowlisted(prefixes, peer) {
+			return c.Next()
+		}
+
+		clientIP, ok := rightmostUntrustedIP(prefixes, c.Get(header))
+		if !ok {
+			clientIP = peer
+		}
+		c.Request().Header.Set(header, clientIP)
+
+		return c.Next()
+	}, nil
+}
+
+// rightmostUntrustedIP walks chain from the right, skipping trusted proxies. It gives up on
+// an unparsable entry, since a trusted proxy would only have appended a valid address.
+func rightmostUntrustedIP(trusted []netip.Prefix, chain string) (string, bool) {
+	entries := strings.Split(chain, ",")
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := strings.TrimSpace(entries[i])
+		if entry == "" {
+			continue
+		}
+
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return "", false
+		}
+		if !allowlisted(trusted, entry) {
+			return addr.Unmap().String(), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,384 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	sharedidempotency "github.com/joshuarp/withdraw-api/internal/shared/idempotency"
+)
+
+const (
+	IdempotencyKeyHeader    = "X-Idempotency-Key"
+	IdempotencyStatusHeader = "X-Idempotency-Status"
+	// IdempotencyReplayedHeader is always "true" on replayed responses, and
+	// IdempotencyCreatedAtHeader carries when the original request was first received.
+	IdempotencyReplayedHeader  = "Idempotency-Replayed"
+	IdempotencyCreatedAtHeader = "Idempotency-Created-At"
+
+	defaultIdempotencyKeyMinLength = 1
+	defaultIdempotencyKeyMaxLength = 128
+	defaultIdempotencyKeyCharset   = "-_.:"
+
+	defaultIdempotencyMaxBodyBytes = 64 << 10
+
+	defaultIdempotencyCompleteAttempts = 3
+	defaultIdempotencyCompleteBackoff  = 50 * time.Millisecond
+	defaultIdempotencyCompleteTimeout  = 2 * time.Second
+)
+
+type IdempotencyOptions struct {
+	// Scope namespaces stored keys per endpoint family (stored as "<scope>:<user_id>"), so
+	// endpoints sharing a store cannot collide. Defaults to "default".
+	Scope string
+	// HeaderName is the request header carrying the key. Defaults to IdempotencyKeyHeader.
+	HeaderName string
+	// RequireBody rejects requests with an empty body before a key is reserved.
+	RequireBody bool
+
+	StatusHeader bool
+	EchoKey      bool
+	KeyPolicy    IdempotencyKeyPolicy
+	// HashHeaders lists request headers that are part of the idempotency fingerprint.
+	HashHeaders []string
+	// MaxBodyBytes caps the response body stored for replay (default 64 KiB). A larger
+	// response is stored as truncation metadata, and replays of its key answer 410 Gone.
+	MaxBodyBytes int
+	// CompleteAttempts is how many times the response is stored before giving up (default
+	// 3), waiting CompleteBackoff (default 50ms, doubled each retry) in between.
+	CompleteAttempts int
+	CompleteBackoff  time.Duration
+	// CompleteTimeout bounds all attempts together (default 2s). They run detached from
+	// the request, so a client hanging up after the handler committed cannot cut them short.
+	CompleteTimeout time.Duration
+	// DeadLetter receives the response when every attempt failed. The handler's work is
+	// already committed, so the client still gets its original response and the key is
+	// completed later by DeadLetter.Reconcile. Without one the client gets a 500. The
+	// SQLX store and queue share one database, so an outage of it defeats both.
+	DeadLetter sharedidempotency.DeadLetterQueue
+	Logger     *slog.Logger
+}
+
+type IdempotencyKeyPolicy struct {
+	MinLength int
+	MaxLength int
+	// Charset lists the characters allowed in addition to ASCII letters and digits.
+	Charset string
+}
+
+func (p IdempotencyKeyPolicy) withDefaults() IdempotencyKeyPolicy {
+	if p.MinLength <= 0 {
+		p.MinLength = defaultIdempotencyKeyMinLength
+	}
+	if p.MaxLength <= 0 {
+		p.MaxLength = defaultIdempotencyKeyMaxLength
+	}
+	if p.MaxLength < p.MinLength {
+		p.MaxLength = p.MinLength
+	}
+	if p.Charset == "" {
+		p.Charset = defaultIdempotencyKeyCharset
+	}
+	return p
+}
+
+func (p IdempotencyKeyPolicy) validate(key string) error {
+	if len(key) == 36 {
+		if _, err := uuid.Parse(key); err == nil {
+			return nil
+		}
+	}
+
+	if len(key) < p.MinLength || len(key) > p.MaxLength {
+		return fmt.Errorf("invalid idempotency key: must be a UUID or %d-%d characters long", p.MinLength, p.MaxLength)
+	}
+
+	for _, r := range key {
+		if !isASCIIAlphanumeric(r) && !strings.ContainsRune(p.Charset, r) {
+			return fmt.Errorf("invalid idempotency key: only letters, digits, and %q are allowed", p.Charset)
+		}
+	}
+
+	return nil
+}
+
+func isASCIIAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// NewHTTPWithdrawIdempotencyMiddleware is NewHTTPIdempotencyMiddleware scoped to withdrawals
+// and reading the key from IdempotencyKeyHeader.
+func NewHTTPWithdrawIdempotencyMiddleware(store sharedidempotency.Store, opts IdempotencyOptions) fiber.Handler {
+	opts.Scope = "withdraw"
+	opts.HeaderName = IdempotencyKeyHeader
+	return NewHTTPIdempotencyMiddleware(store, opts)
+}
+
+// NewHTTPIdempotencyMiddleware makes an authenticated endpoint safe to retry: the first
+// response for a (user, key) pair is stored and replayed for retries with the same request,
+// while a reused key with a different request is rejected.
+func NewHTTPIdempotencyMiddleware(store sharedidempotency.Store, opts IdempotencyOptions) fiber.Handler {
+	keyPolicy := opts.KeyPolicy.withDefaults()
+
+	scope := strings.TrimSpace(opts.Scope)
+	if scope == "" {
+		scope = "default"
+	}
+
+	headerName := strings.TrimSpace(opts.HeaderName)
+	if headerName == "" {
+		headerName = IdempotencyKeyHeader
+	}
+
+	maxBodyBytes := opts.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultIdempotencyMaxBodyBytes
+	}
+
+	completeAttempts := opts.CompleteAttempts
+	if completeAttempts <= 0 {
+		completeAttempts = defaultIdempotencyCompleteAttempts
+	}
+
+	completeBackoff := opts.CompleteBackoff
+	if completeBackoff <= 0 {
+		completeBackoff = defaultIdempotencyCompleteBackoff
+	}
+
+	completeTimeout := opts.CompleteTimeout
+	if completeTimeout <= 0 {
+		completeTimeout = defaultIdempotencyCompleteTimeout
+	}
+
+	return func(c fiber.Ctx) error {
+		if store == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "idempotency store is not available"})
+		}
+
+		userID, ok := UserIDFromContext(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "missing authenticated user"})
+		}
+
+		idempotencyKey := strings.TrimSpace(c.Get(headerName))
+		if idempotencyKey == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing idempotency key"})
+		}
+
+		if err := keyPolicy.validate(idempotencyKey); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		requestBody := append([]byte(nil), c.BodyRaw()...)
+		if opts.RequireBody && len(requestBody) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing request body"})
+		}
+
+		hash := idempotencyRequestHash(c.Method(), c.Path(), string(c.Request().URI().QueryString()), userID, significantHeaders(c, opts.HashHeaders), requestBody)
+		request := sharedidempotency.Request{
+			Scope:       fmt.Sprintf("%s:%s", scope, userID),
+			Key:         idempotencyKey,
+			RequestHash: hash,
+		}
+
+		decision, err := store.Acquire(c.Context(), request)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to acquire idempotency key"})
+		}
+
+		if opts.EchoKey {
+			c.Set(headerName, idempotencyKey)
+		}
+
+		switch decision.Type {
+		case sharedidempotency.DecisionReplay:
+			AddRequestOutcome(c, OutcomeIdempotencyReplayed)
+			c.Set(IdempotencyReplayedHeader, "true")
+			if !decision.CreatedAt.IsZero() {
+				c.Set(IdempotencyCreatedAtHeader, decision.CreatedAt.UTC().Format(time.RFC3339))
+			}
+			if opts.StatusHeader {
+				c.Set(IdempotencyStatusHeader, "replayed")
+			}
+			if decision.ContentType == sharedidempotency.TruncatedContentType {
+				// The request was processed; only its response cannot be reproduced.
+				return c.Status(fiber.StatusGone).JSON(fiber.Map{
+					"error":           "original response is too large to replay",
+					"original_status": decision.StatusCode,
+				})
+			}
+			if decision.ContentType != "" {
+				c.Set(fiber.HeaderContentType, decision.ContentType)
+			}
+			if decision.StatusCode <= 0 {
+				decision.StatusCode = fiber.StatusOK
+			}
+
+			return c.Status(decision.StatusCode).Send(decision.Body)
+		case sharedidempotency.DecisionInProgress:
+			AddRequestOutcome(c, OutcomeIdempotencyInProgress)
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "request is already in progress"})
+		case sharedidempotency.DecisionConflict:
+			AddRequestOutcome(c, OutcomeIdempotencyConflict)
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "idempotency key reused with different payload"})
+		case sharedidempotency.DecisionAcquired:
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "invalid idempotency state"})
+		}
+
+		if opts.StatusHeader {
+			c.Set(IdempotencyStatusHeader, "acquired")
+		}
+
+		handlerErr := c.Next()
+		if handlerErr != nil {
+			// The error is rendered further out (e.g. 503 by the timeout middleware), so
+			// there is no response to store yet; free the key for a retry instead.
+			releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(c.Context()), completeTimeout)
+			if err := store.Release(releaseCtx, request); err != nil && opts.Logger != nil {
+				opts.Logger.Warn("idempotency key release failed", "scope", request.Scope, "key", request.Key, "error", err)
+			}
+			cancelRelease()
+			return handlerErr
+		}
+
+		response := storedIdempotencyResponse(c, maxBodyBytes)
+
+		completeCtx, cancelComplete := context.WithTimeout(context.WithoutCancel(c.Context()), completeTimeout)
+		completeErr := completeWithRetry(completeCtx, store, request, response, completeAttempts, completeBackoff)
+		cancelComplete()
+		if completeErr == nil {
+			return nil
+		}
+
+		if opts.DeadLetter != nil {
+			// Detached from the request so a client that already hung up cannot lose the entry.
+			enqueueErr := opts.DeadLetter.Enqueue(context.WithoutCancel(c.Context()), request, response, completeErr)
+			if enqueueErr == nil {
+				AddRequestOutcome(c, OutcomeIdempotencyDeadLettered)
+				if opts.Logger != nil {
+					opts.Logger.Warn("idempotency response dead-lettered", "scope", request.Scope, "key", request.Key, "error", completeErr)
+				}
+				return nil
+			}
+			if opts.Logger != nil {
+				opts.Logger.Error("idempotency dead letter failed", "scope", request.Scope, "key", request.Key, "error", enqueueErr, "complete_error", completeErr)
+			}
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to persist idempotency response"})
+	}
+}
+
+// completeWithRetry stores response, retrying up to attempts times in total with a doubling
+// backoff. It returns the last error once the attempts or ctx run out.
+func completeWithRetry(ctx context.Context, store sharedidempotency.Store, request sharedidempotency.Request, response sharedidempotency.StoredResponse, attempts int, backoff time.Duration) error {
+	for attempt := 1; ; attempt++ {
+		err := store.Complete(ctx, request, response)
+		if err == nil || attempt >= attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// storedIdempotencyResponse copies the response for replay, replacing a body larger than
+// maxBodyBytes with TruncatedBody metadata so one oversized response cannot bloat the store.
+func storedIdempotencyResponse(c fiber.Ctx, maxBodyBytes int) sharedidempotency.StoredResponse {
+	body := c.Response().Body()
+	response := sharedidempotency.StoredResponse{
+		StatusCode:  c.Response().StatusCode(),
+		ContentType: string(c.Response().Header.ContentType()),
+	}
+	if len(body) <= maxBodyBytes {
+		response.Body = append([]byte(nil), body...)
+		return response
+	}
+
+	metadata, _ := json.Marshal(sharedidempotency.TruncatedBody{
+		OriginalBytes:       len(body),
+		OriginalContentType: response.ContentType,
+	})
+	response.Body = metadata
+	response.ContentType = sharedidempotency.TruncatedContentType
+	return response
+}
+
+func idempotencyRequestHash(method, path, rawQuery, userID string, headers map[string]string, body []byte) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(strings.ToUpper(strings.TrimSpace(method))))
+	hasher.Write([]byte("\n"))
+	hasher.Write([]byte(strings.TrimSpace(path)))
+	hasher.Write([]byte("\n"))
+	hasher.Write([]byte(normalizeQuery(rawQuery)))
+	hasher.Write([]byte("\n"))
+	hasher.Write([]byte(strings.TrimSpace(userID)))
+	hasher.Write([]byte("\n"))
+	hasher.Write([]byte(normalizeHeaders(headers)))
+	hasher.Write([]byte("\n"))
+	hasher.Write(body)
+
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func normalizeQuery(rawQuery string) string {
+	values, err := url.ParseQuery(strings.TrimSpace(rawQuery))
+	if err != nil {
+		return strings.TrimSpace(rawQuery)
+	}
+
+	return values.Encode()
+}
+
+func normalizeHeaders(headers map[string]string) string {
+	if len(headers) == 0 {
+		return ""
+	}
+
+	normalized := make(map[string]string, len(headers))
+	for name, value := range headers {
+		normalized[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+
+	var builder strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(normalized)) {
+		builder.WriteString(name)
+		builder.WriteString(":")
+		builder.WriteString(normalized[name])
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+func significantHeaders(c fiber.Ctx, names []string) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	headers := make(map[string]string, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			headers[name] = c.Get(name)
+		}
+	}
+
+	return headers
+}
@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// InFlightTracker counts requests currently inside the handler chain, so shutdown can
+// report how many it had to drain and how many it cut off.
+type InFlightTracker struct {
+	count atomic.Int64
+}
+
+// NewInFlightTracker exposes the count as the http_server_in_flight_requests gauge when
+// registry is non-nil.
+func NewInFlightTracker(registry prometheus.Registerer) (*InFlightTracker, error) {
+	tracker := &InFlightTracker{}
+	if registry == nil {
+		return tracker, nil
+	}
+
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "http_server_in_flight_requests",
+		Help: "Number of HTTP requests being served, as seen by graceful shutdown.",
+	}, func() float64 { return float64(tracker.Count()) })
+	if err := registry.Register(gauge); err != nil {
+		return nil, fmt.Errorf("middlewares: failed to register in-flight gauge: %w", err)
+	}
+
+	return tracker, nil
+}
+
+func (t *InFlightTracker) Count() int64 {
+	if t == nil {
+		return 0
+	}
+	return t.count.Load()
+}
+
+// NewHTTPInFlightMiddleware keeps tracker up to date. The decrement is deferred, so a
+// request is released even when a later handler errors or panics.
+func NewHTTPInFlightMiddleware(tracker *InFlightTracker) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		tracker.count.Add(1)
+		defer tracker.count.Add(-1)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	InternalAuthHeader = "X-Internal-Auth"
+
+	defaultInternalAuthMaxAge    = 5 * time.Minute
+	defaultInternalAuthClockSkew = 30 * time.Second
+)
+
+type InternalAuthOptions struct {
+	Secret    []byte
+	MaxAge    time.Duration
+	ClockSkew time.Duration
+	Now       func() time.Time
+}
+
+func SignInternalAuth(secret []byte, method, path string, ts time.Time) string {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	return timestamp + "." + internalAuthSignature(secret, timestamp, method, path)
+}
+
+func NewHTTPInternalAuthMiddleware(opts InternalAuthOptions) fiber.Handler {
+	maxAge := opts.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultInternalAuthMaxAge
+	}
+
+	clockSkew := opts.ClockSkew
+	if clockSkew <= 0 {
+		clockSkew = defaultInternalAuthClockSkew
+	}
+
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return func(c fiber.Ctx) error {
+		if len(opts.Secret) == 0 || !verifyInternalAuth(opts.Secret, c.Get(InternalAuthHeader), c.Method(), c.Path(), now(), maxAge, clockSkew) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "forbidden",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+func verifyInternalAuth(secret []byte, value, method, path string, now time.Time, maxAge, clockSkew time.Duration) bool {
+	timestamp, signature, found := strings.Cut(strings.TrimSpace(value), ".")
+	if !found {
+		return false
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > maxAge+clockSkew || age < -clockSkew {
+		return false
+	}
+
+	expected := internalAuthSignature(secret, timestamp, method, path)
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
+
+func internalAuthSignature(secret []byte, timestamp, method, path string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "\n" + method + "\n" + path))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -2,17 +2,85 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	sharedjwt "github.com/joshuarp/withdraw-api/internal/shared/jwt"
 )
 
-func NewHTTPJWTMiddleware(tokenManager sharedjwt.TokenManager) fiber.Handler {
+// JWTMiddlewareConfig tunes token verification. VerifyTimeout bounds each Verify call so a
+// slow key source (e.g. a JWKS endpoint) cannot stall requests; zero leaves it unbounded.
+// Bypass lists the public routes; nil means DefaultJWTBypassRules. PathPrefix is where
+// the middleware is mounted (e.g. "/api/v1") and is prepended to every bypass path.
+type JWTMiddlewareConfig struct {
+	VerifyTimeout time.Duration
+	Bypass        []JWTBypassRule
+	PathPrefix    string
+}
+
+// JWTBypassRule lets a request through without a token when its method matches and its
+// path, below the configured prefix, is exactly PathSuffix. "/auth/login" therefore
+// matches "/api/v1/auth/login" but neither "/api/v1/auth/login-attempts" nor
+// "/api/v1/wallets/auth/login". A trailing slash is ignored.
+type JWTBypassRule struct {
+	Method     string
+	PathSuffix string
+}
+
+// DefaultJWTBypassRules keeps the login and registration endpoints public.
+var DefaultJWTBypassRules = []JWTBypassRule{
+	{Method: fiber.MethodPost, PathSuffix: "/auth/login"},
+	{Method: fiber.MethodPost, PathSuffix: "/auth/register"},
+}
+
+// jwtBypassRoute is a bypass rule resolved against the mount prefix.
+type jwtBypassRoute struct {
+	method string
+	path   string
+}
+
+func newJWTBypassRoute(prefix string, rule JWTBypassRule) (jwtBypassRoute, bool) {
+	suffix := strings.Trim(strings.TrimSpace(rule.PathSuffix), "/")
+	if suffix == "" {
+		return jwtBypassRoute{}, false
+	}
+
+	return jwtBypassRoute{
+		method: strings.ToUpper(strings.TrimSpace(rule.Method)),
+		path:   strings.TrimRight(prefix, "/") + "/" + suffix,
+	}, true
+}
+
+func (r jwtBypassRoute) matches(method, path string) bool {
+	return method == r.method && strings.TrimRight(path, "/") == r.path
+}
+
+type verifyResult struct {
+	claims *sharedjwt.Claims
+	err    error
+}
+
+func NewHTTPJWTMiddleware(tokenManager sharedjwt.TokenManager, cfg JWTMiddlewareConfig) fiber.Handler {
+	rules := cfg.Bypass
+	if rules == nil {
+		rules = DefaultJWTBypassRules
+	}
+
+	bypass := make([]jwtBypassRoute, 0, len(rules))
+	for _, rule := range rules {
+		if route, ok := newJWTBypassRoute(cfg.PathPrefix, rule); ok {
+			bypass = append(bypass, route)
+		}
+	}
+
 	return func(c fiber.Ctx) error {
-		path := c.Path()
-		if c.Method() == fiber.MethodPost && strings.Contains(path, "/auth/login") {
-			return c.Next()
+		method, path := c.Method(), c.Path()
+		for _, rule := range bypass {
+			if rule.matches(method, path) {
+				return c.Next()
+			}
 		}
 
 		authorizationHeader := strings.TrimSpace(c.Get(fiber.HeaderAuthorization))
@@ -30,15 +98,55 @@ func NewHTTPJWTMiddleware(tokenManager sharedjwt.TokenManager) fiber.Handler {
 			})
 		}
 
-		claims, err := tokenManager.Verify(context.Background(), tokenString)
+		claims, err := verifyWithTimeout(c.Context(), tokenManager, tokenString, cfg.VerifyTimeout)
+		if errors.Is(err, context.DeadlineExceeded) {
+			// The token may be fine; tell the client to retry instead of re-authenticating.
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "token verification unavailable",
+			})
+		}
 		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "invalid token",
 			})
 		}
 
-		c.Locals("user_id", claims.Subject)
-		c.Locals("jwt_claims", claims)
-		return c.Next()
+		c.Locals(userIDLocalKey, claims.Subject)
+		c.Locals(jwtClaimsLocalKey, claims)
+
+		// Services only see c.Context(), so expose the claims there as well.
+		parent := c.Context()
+		c.SetContext(sharedjwt.SetClaims(parent, claims))
+		err = c.Next()
+		c.SetContext(parent)
+		return err
+	}
+}
+
+// verifyWithTimeout runs Verify in its own goroutine so the deadline holds even for
+// verifiers that ignore context cancellation.
+func verifyWithTimeout(ctx context.Context, verifier sharedjwt.Verifier, token string, timeout time.Duration) (*sharedjwt.Claims, error) {
+	if timeout <= 0 {
+		return verifier.Verify(ctx, token)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan verifyResult, 1)
+	go func() {
+		claims, err := verifier.Verify(ctx, token)
+		done <- verifyResult{claims: claims, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		if result.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, context.DeadlineExceeded
+		}
+		return result.claims, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 }
@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	MetricsAccessModeAllowlist    = "allowlist"
+	MetricsAccessModeInternalAuth = "internal_auth"
+)
+
+type MetricsAccessOptions struct {
+	Mode         string
+	Allowlist    []string
+	InternalAuth InternalAuthOptions
+}
+
+func NewHTTPMetricsAccessMiddleware(opts MetricsAccessOptions) (fiber.Handler, error) {
+	switch strings.TrimSpace(strings.ToLower(opts.Mode)) {
+	case MetricsAccessModeInternalAuth:
+		return NewHTTPInternalAuthMiddleware(opts.InternalAuth), nil
+	case "", MetricsAccessModeAllowlist:
+		prefixes, err := parseAllowlist(opts.Allowlist)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(c fiber.Ctx) error {
+			if !allowlisted(prefixes, c.IP()) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "forbidden",
+				})
+			}
+
+			return c.Next()
+		}, nil
+	default:
+		return nil, fmt.Errorf("middlewares: unsupported metrics access mode %q", opts.Mode)
+	}
+}
+
+func parseAllowlist(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("middlewares: invalid allowlist entry %q: %w", entry, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("middlewares: invalid allowlist entry %q: %w", entry, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+func allowlisted(prefixes []netip.Prefix, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
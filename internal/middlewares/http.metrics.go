@@ -0,0 +1,101 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type httpMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+}
+
+func NewHTTPMetricsMiddleware(registry prometheus.Registerer) (fiber.Handler, error) {
+	metrics, err := newHTTPMetrics(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c fiber.Ctx) error {
+		method := c.Method()
+		inFlight := metrics.inFlight.WithLabelValues(method)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			} else {
+				status = fiber.StatusInternalServerError
+			}
+		}
+
+		route := c.Route().Path
+		statusLabel := strconv.Itoa(status)
+		metrics.requests.WithLabelValues(method, route, statusLabel).Inc()
+		metrics.duration.WithLabelValues(method, route, statusLabel).Observe(time.Since(start).Seconds())
+
+		return err
+	}, nil
+}
+
+func newHTTPMetrics(registry prometheus.Registerer) (*httpMetrics, error) {
+	metrics := &httpMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by method, route template, and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route template, and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served.",
+		}, []string{"method"}),
+	}
+
+	if registry == nil {
+		return metrics, nil
+	}
+
+	var err error
+	if metrics.requests, err = registerOrExisting(registry, metrics.requests); err != nil {
+		return nil, err
+	}
+	if metrics.duration, err = registerOrExisting(registry, metrics.duration); err != nil {
+		return nil, err
+	}
+	if metrics.inFlight, err = registerOrExisting(registry, metrics.inFlight); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func registerOrExisting[T prometheus.Collector](registry prometheus.Registerer, collector T) (T, error) {
+	if err := registry.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+
+		var zero T
+		return zero, fmt.Errorf("middlewares: failed to register http metrics: %w", err)
+	}
+	return collector, nil
+}
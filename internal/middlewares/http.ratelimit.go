@@ -2,17 +2,45 @@ package middlewares
 
 import (
 	"log/slog"
+	"math"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/joshuarp/withdraw-api/internal/shared/ratelimit"
 )
 
+const RetryBudgetHeader = "X-Retry-Budget"
+
+// RateLimitResetMsHeader carries the wait before a rejected request may be retried, in
+// milliseconds, for clients that need more precision than Retry-After's whole seconds.
+const RateLimitResetMsHeader = "X-RateLimit-Reset-Ms"
+
+// RateLimitHeaderStyle selects which rate limit headers are emitted.
+type RateLimitHeaderStyle string
+
+const (
+	// RateLimitHeaderStyleLegacy emits X-RateLimit-* with Reset as an absolute Unix time.
+	RateLimitHeaderStyleLegacy RateLimitHeaderStyle = "legacy"
+	// RateLimitHeaderStyleStandard emits the IETF draft RateLimit-* headers with Reset
+	// as delta seconds.
+	RateLimitHeaderStyleStandard RateLimitHeaderStyle = "standard"
+	// RateLimitHeaderStyleBoth emits both header sets.
+	RateLimitHeaderStyleBoth RateLimitHeaderStyle = "both"
+)
+
 type RateLimitConfig struct {
 	Limiter      ratelimit.Limiter
 	Skipper      func(c fiber.Ctx) bool
 	KeyExtractor func(c fiber.Ctx) string
 	Logger       *slog.Logger
+	RetryBudget  int64
+	// HeaderStyle defaults to RateLimitHeaderStyleLegacy.
+	HeaderStyle RateLimitHeaderStyle
+	// PreciseRetryAfter emits Retry-After as decimal seconds with millisecond precision
+	// ("0.25") instead of whole seconds rounded up. RFC 9110 only defines whole seconds,
+	// so enable it only for clients known to parse the decimal form.
+	PreciseRetryAfter bool
 }
 
 func NewHTTPRateLimitMiddleware(cfg RateLimitConfig) fiber.Handler {
@@ -40,10 +68,8 @@ func NewHTTPRateLimitMiddleware(cfg RateLimitConfig) fiber.Handler {
 		key := cfg.KeyExtractor(c)
 		ctx = ratelimit.WithIP(ctx, c.IP())
 
-		if userID := c.Locals("user_id"); userID != nil {
-			if uid, ok := userID.(string); ok {
-				ctx = ratelimit.WithUserID(ctx, uid)
-			}
+		if userID, ok := UserIDFromContext(c); ok {
+			ctx = ratelimit.WithUserID(ctx, userID)
 		}
 
 		result, err := cfg.Limiter.AllowKey(ctx, key)
@@ -56,16 +82,20 @@ func NewHTTPRateLimitMiddleware(cfg RateLimitConfig) fiber.Handler {
 			})
 		}
 
-		c.Set("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
-		c.Set("X-RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
-		c.Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+		setRateLimitHeaders(c, cfg.HeaderStyle, result)
+		c.Set(RetryBudgetHeader, strconv.FormatInt(retryBudget(result, cfg.RetryBudget), 10))
 
 		if !result.Allowed {
-			retryAfter := int(result.RetryAfter.Seconds())
-			if retryAfter < 1 {
-				retryAfter = 1
+			waitMs := max(rejectionWait(result).Milliseconds(), 1)
+			// Rounded up: a client retrying after a floored value would be rejected again.
+			retryAfter := int((waitMs + 999) / 1000)
+			if cfg.PreciseRetryAfter {
+				c.Set(fiber.HeaderRetryAfter, strconv.FormatFloat(float64(waitMs)/1000, 'f', -1, 64))
+			} else {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
 			}
-			c.Set("Retry-After", strconv.Itoa(retryAfter))
+			c.Set(RateLimitResetMsHeader, strconv.FormatInt(waitMs, 10))
+			AddRequestOutcome(c, OutcomeRateLimited)
 
 			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
 				"error":       "rate limit exceeded",
@@ -77,11 +107,47 @@ func NewHTTPRateLimitMiddleware(cfg RateLimitConfig) fiber.Handler {
 	}
 }
 
+func setRateLimitHeaders(c fiber.Ctx, style RateLimitHeaderStyle, result ratelimit.Result) {
+	limit := strconv.FormatInt(result.Limit, 10)
+	remaining := strconv.FormatInt(result.Remaining, 10)
+
+	if style != RateLimitHeaderStyleStandard {
+		c.Set("X-RateLimit-Limit", limit)
+		c.Set("X-RateLimit-Remaining", remaining)
+		c.Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+	}
+
+	if style == RateLimitHeaderStyleStandard || style == RateLimitHeaderStyleBoth {
+		resetAfter := max(int64(math.Ceil(time.Until(result.ResetAt).Seconds())), 0)
+		c.Set("RateLimit-Limit", limit)
+		c.Set("RateLimit-Remaining", remaining)
+		c.Set("RateLimit-Reset", strconv.FormatInt(resetAfter, 10))
+	}
+}
+
+// rejectionWait is how long a rejected request should wait: the limiter's RetryAfter, or
+// the time left until ResetAt when the limiter rejected without setting one.
+func rejectionWait(result ratelimit.Result) time.Duration {
+	if result.RetryAfter > 0 {
+		return result.RetryAfter
+	}
+	return max(time.Until(result.ResetAt), 0)
+}
+
+func retryBudget(result ratelimit.Result, max int64) int64 {
+	budget := result.Remaining
+	if !result.Allowed || budget < 0 {
+		budget = 0
+	}
+	if max > 0 && budget > max {
+		budget = max
+	}
+	return budget
+}
+
 func defaultKeyExtractor(c fiber.Ctx) string {
-	if userID := c.Locals("user_id"); userID != nil {
-		if uid, ok := userID.(string); ok && uid != "" {
-			return "user:" + uid
-		}
+	if userID, ok := UserIDFromContext(c); ok {
+		return "user:" + userID
 	}
 	return "ip:" + c.IP()
 }
@@ -97,15 +163,19 @@ func SkipAuthRoutes(c fiber.Ctx) bool {
 
 func PerUserKeyExtractor(prefix string) func(c fiber.Ctx) string {
 	return func(c fiber.Ctx) string {
-		if userID := c.Locals("user_id"); userID != nil {
-			if uid, ok := userID.(string); ok && uid != "" {
-				return prefix + ":user:" + uid
-			}
+		if userID, ok := UserIDFromContext(c); ok {
+			return UserRateLimitKey(prefix, userID)
 		}
 		return prefix + ":ip:" + c.IP()
 	}
 }
 
+// UserRateLimitKey returns the key PerUserKeyExtractor produces for an authenticated user,
+// so callers outside a request (e.g. admin resets) address the same bucket.
+func UserRateLimitKey(prefix, userID string) string {
+	return prefix + ":user:" + userID
+}
+
 func PerIPKeyExtractor(prefix string) func(c fiber.Ctx) string {
 	return func(c fiber.Ctx) string {
 		return prefix + ":ip:" + c.IP()
@@ -114,10 +184,8 @@ func PerIPKeyExtractor(prefix string) func(c fiber.Ctx) string {
 
 func PerEndpointKeyExtractor(prefix string) func(c fiber.Ctx) string {
 	return func(c fiber.Ctx) string {
-		if userID := c.Locals("user_id"); userID != nil {
-			if uid, ok := userID.(string); ok && uid != "" {
-				return prefix + ":" + c.Method() + ":" + c.Path() + ":user:" + uid
-			}
+		if userID, ok := UserIDFromContext(c); ok {
+			return prefix + ":" + c.Method() + ":" + c.Path() + ":user:" + userID
 		}
 		return prefix + ":" + c.Method() + ":" + c.Path() + ":ip:" + c.IP()
 	}
@@ -1,10 +1,40 @@
 package middlewares
 
 import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
 	"github.com/gofiber/fiber/v3"
-	"github.com/gofiber/fiber/v3/middleware/recover"
 )
 
-func NewHTTPRecoveryMiddleware() fiber.Handler {
-	return recover.New()
+// NewHTTPRecoveryMiddleware turns a panic in a downstream handler into a 500 response.
+// The panic value and stack trace are logged with the request ID and route; the client
+// only receives a generic error.
+func NewHTTPRecoveryMiddleware(logger *slog.Logger) fiber.Handler {
+	return func(c fiber.Ctx) (err error) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			if logger != nil {
+				logger.Error("panic recovered",
+					"request_id", RequestIDFromContext(c),
+					"method", c.Method(),
+					"route", c.Route().Path,
+					"path", c.Path(),
+					"panic", fmt.Sprint(recovered),
+					"stack", string(debug.Stack()),
+				)
+			}
+
+			err = c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "internal server error",
+			})
+		}()
+
+		return c.Next()
+	}
 }
@@ -1,23 +1,79 @@
 package middlewares
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v3"
-	"github.com/gofiber/fiber/v3/middleware/requestid"
+	"github.com/google/uuid"
+	sharedaudit "github.com/joshuarp/withdraw-api/internal/shared/audit"
 )
 
-const ChainIDHeader = "X-Request-ID"
+const (
+	RequestIDHeader = "X-Request-ID"
+	// CorrelationIDHeader carries an ID that gateways propagate across services. When a
+	// caller sends both headers, the correlation ID wins so the whole chain shares one ID.
+	CorrelationIDHeader = "X-Correlation-ID"
+
+	requestIDLocalKey     = "request_id"
+	maxForwardedRequestID = 128
+)
 
+// NewHTTPRequestIDMiddleware assigns every request an ID, reusing a well-formed
+// X-Correlation-ID or X-Request-ID from the caller and generating one otherwise. The ID is
+// echoed in both response headers, stored in locals for RequestIDFromContext, and carried
+// on c.Context() so services can log it.
 func NewHTTPRequestIDMiddleware() fiber.Handler {
-	return requestid.New(requestid.Config{
-		Header: ChainIDHeader,
-	})
+	return func(c fiber.Ctx) error {
+		requestID := forwardedRequestID(c)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(RequestIDHeader, requestID)
+		c.Set(CorrelationIDHeader, requestID)
+		c.Locals(requestIDLocalKey, requestID)
+
+		parent := c.Context()
+		c.SetContext(sharedaudit.WithRequestID(parent, requestID))
+		err := c.Next()
+		c.SetContext(parent)
+		return err
+	}
+}
+
+// RequestIDFromContext returns the ID assigned by NewHTTPRequestIDMiddleware, falling back
+// to the raw request header when the middleware did not run.
+func RequestIDFromContext(c fiber.Ctx) string {
+	if requestID, ok := c.Locals(requestIDLocalKey).(string); ok && requestID != "" {
+		return requestID
+	}
+
+	return strings.TrimSpace(c.Get(RequestIDHeader))
 }
 
-func ChainIDFromContext(c fiber.Ctx) string {
-	chainID := requestid.FromContext(c)
-	if chainID != "" {
-		return chainID
+// forwardedRequestID returns the first well-formed ID among the correlation and request ID
+// headers, or "" when neither is usable. An invalid correlation ID does not hide a valid
+// request ID.
+func forwardedRequestID(c fiber.Ctx) string {
+	for _, header := range []string{CorrelationIDHeader, RequestIDHeader} {
+		if requestID := strings.TrimSpace(c.Get(header)); isValidRequestID(requestID) {
+			return requestID
+		}
 	}
+	return ""
+}
 
-	return c.Get(ChainIDHeader)
+// isValidRequestID rejects forwarded IDs that are oversized or contain characters outside
+// printable ASCII, since the value is echoed in headers and written to logs.
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxForwardedRequestID {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < 0x21 || requestID[i] > 0x7e {
+			return false
+		}
+	}
+	return true
 }
@@ -1,25 +1,77 @@
 package middlewares
 
 import (
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
 )
 
-func NewHTTPRequestResponseLogMiddleware(logger *slog.Logger) fiber.Handler {
+const (
+	defaultLogMaxBodyBytes = 4096
+	redactedBodyValue      = "***"
+)
+
+// redactedBodyKeyHints mask any JSON key containing one of them (current_password,
+// client_secret, refresh_token, ...), whatever RedactFields adds.
+var redactedBodyKeyHints = []string{"password", "secret", "token"}
+
+// logSampleRandom draws the sampling decision; it is swapped in tests.
+var logSampleRandom = rand.Float64
+
+// RequestResponseLogConfig controls optional body capture and sampling. Bodies are only
+// logged when CaptureBody is set; JSON fields named in RedactFields or containing
+// "password", "secret" or "token" (case-insensitive, at any depth) are masked before the
+// body is truncated to MaxBodyBytes.
+type RequestResponseLogConfig struct {
+	CaptureBody  bool
+	RedactFields []string
+	MaxBodyBytes int
+	// SampleRate is the fraction of fast 2xx requests that are logged; values outside
+	// (0, 1) log every request. Errors, non-2xx responses and requests taking at least
+	// SlowThreshold (when set) are always logged.
+	SampleRate    float64
+	SlowThreshold time.Duration
+}
+
+func NewHTTPRequestResponseLogMiddleware(logger *slog.Logger, cfg RequestResponseLogConfig) fiber.Handler {
 	if logger == nil {
 		logger = slog.Default()
 	}
 
+	maxBodyBytes := cfg.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultLogMaxBodyBytes
+	}
+
+	redactFields := make(map[string]struct{}, len(cfg.RedactFields))
+	for _, field := range cfg.RedactFields {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			redactFields[field] = struct{}{}
+		}
+	}
+
+	sampling := cfg.SampleRate > 0 && cfg.SampleRate < 1
+
 	return func(c fiber.Ctx) error {
 		start := time.Now().UTC()
 		err := c.Next()
 		latency := time.Since(start)
-
-		requestID := ChainIDFromContext(c)
 		statusCode := c.Response().StatusCode()
 
+		sampled := sampling && err == nil && isSuccessStatus(statusCode) &&
+			(cfg.SlowThreshold <= 0 || latency < cfg.SlowThreshold)
+		if sampled && logSampleRandom() >= cfg.SampleRate {
+			return nil
+		}
+
+		requestID := RequestIDFromContext(c)
+
 		attrs := []any{
 			"request_id", requestID,
 			"method", c.Method(),
@@ -30,6 +82,22 @@ func NewHTTPRequestResponseLogMiddleware(logger *slog.Logger) fiber.Handler {
 			"user_agent", c.Get(fiber.HeaderUserAgent),
 		}
 
+		if outcomes := RequestOutcomesFromContext(c); len(outcomes) > 0 {
+			attrs = append(attrs, "outcome", strings.Join(outcomes, ","))
+		}
+
+		// Lets log queries scale sampled counts back up.
+		if sampled {
+			attrs = append(attrs, "sample_rate", cfg.SampleRate)
+		}
+
+		if cfg.CaptureBody {
+			attrs = append(attrs,
+				"request_body", captureLogBody(c.Body(), redactFields, maxBodyBytes),
+				"response_body", captureLogBody(c.Response().Body(), redactFields, maxBodyBytes),
+			)
+		}
+
 		if err != nil {
 			logger.Error("http_request", append(attrs, "error", err.Error())...)
 			return err
@@ -39,3 +107,61 @@ func NewHTTPRequestResponseLogMiddleware(logger *slog.Logger) fiber.Handler {
 		return nil
 	}
 }
+
+func isSuccessStatus(statusCode int) bool {
+	return statusCode >= fiber.StatusOK && statusCode < fiber.StatusMultipleChoices
+}
+
+// captureLogBody returns a redacted, size-capped copy of body. Non-JSON bodies are not
+// logged verbatim because they cannot be redacted reliably.
+func captureLogBody(body []byte, redactFields map[string]struct{}, maxBytes int) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Sprintf("[non-JSON body omitted, %d bytes]", len(body))
+	}
+
+	redacted, err := json.Marshal(redactLogFields(payload, redactFields))
+	if err != nil {
+		return fmt.Sprintf("[unloggable body omitted, %d bytes]", len(body))
+	}
+
+	if len(redacted) > maxBytes {
+		return string(redacted[:maxBytes]) + fmt.Sprintf("...[truncated %d bytes]", len(redacted)-maxBytes)
+	}
+	return string(redacted)
+}
+
+func redactLogFields(value any, redactFields map[string]struct{}) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			if isRedactedBodyKey(key, redactFields) {
+				typed[key] = redactedBodyValue
+				continue
+			}
+			typed[key] = redactLogFields(nested, redactFields)
+		}
+		return typed
+	case []any:
+		for i, nested := range typed {
+			typed[i] = redactLogFields(nested, redactFields)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+func isRedactedBodyKey(key string, redactFields map[string]struct{}) bool {
+	key = strings.ToLower(key)
+	if _, ok := redactFields[key]; ok {
+		return true
+	}
+	return slices.ContainsFunc(redactedBodyKeyHints, func(hint string) bool {
+		return strings.Contains(key, hint)
+	})
+}
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+func NewHTTPRequireScopeMiddleware(scope string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		claims, ok := ClaimsFromContext(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "missing authenticated user",
+			})
+		}
+
+		if !claims.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "insufficient scope",
+			})
+		}
+
+		return c.Next()
+	}
+}
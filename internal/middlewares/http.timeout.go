@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// NewHTTPTimeoutMiddleware puts a deadline of d on c.Context(). A handler that returns a
+// deadline error is answered with 503; one that returns nil keeps its response even if it
+// finished after the deadline, since its work (e.g. a committed withdrawal) went through.
+func NewHTTPTimeoutMiddleware(d time.Duration) fiber.Handler {
+	if d <= 0 {
+		return func(c fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return func(c fiber.Ctx) error {
+		parent := c.Context()
+		ctx, cancel := context.WithTimeout(parent, d)
+		defer cancel()
+
+		c.SetContext(ctx)
+		err := c.Next()
+		c.SetContext(parent)
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "request timed out",
+			})
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var traceContextPropagator = propagation.TraceContext{}
+
+func NewHTTPTracingMiddleware(tracer trace.Tracer) fiber.Handler {
+	if tracer == nil {
+		return func(c fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return func(c fiber.Ctx) error {
+		parent := c.Context()
+		carrier := propagation.MapCarrier{}
+		for _, key := range traceContextPropagator.Fields() {
+			if value := c.Get(key); value != "" {
+				carrier.Set(key, value)
+			}
+		}
+		extracted := traceContextPropagator.Extract(parent, carrier)
+
+		ctx, span := tracer.Start(extracted, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Method()),
+				attribute.String("url.path", c.Path()),
+			),
+		)
+		defer span.End()
+
+		c.SetContext(ctx)
+		err := c.Next()
+		c.SetContext(parent)
+
+		route := c.Route().Path
+		status := c.Response().StatusCode()
+		if err != nil {
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			} else {
+				status = fiber.StatusInternalServerError
+			}
+			span.RecordError(err)
+		}
+
+		span.SetName(fmt.Sprintf("%s %s", c.Method(), route))
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+
+		return err
+	}
+}
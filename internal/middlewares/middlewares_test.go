@@ -1,24 +1,41 @@
 package middlewares
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
 	idempotencymocks "github.com/joshuarp/withdraw-api/internal/mock/shared/idempotency"
 	jwtmocks "github.com/joshuarp/withdraw-api/internal/mock/shared/jwt"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 
+	sharedaudit "github.com/joshuarp/withdraw-api/internal/shared/audit"
 	sharedidempotency "github.com/joshuarp/withdraw-api/internal/shared/idempotency"
 	sharedjwt "github.com/joshuarp/withdraw-api/internal/shared/jwt"
 	sharedratelimit "github.com/joshuarp/withdraw-api/internal/shared/ratelimit"
@@ -59,17 +76,24 @@ type HTTPJWTMiddlewareSuite struct {
 func (s *HTTPJWTMiddlewareSuite) SetupTest() {
 	s.tokenManager = jwtmocks.NewTokenManager(s.T())
 	s.app = fiber.New()
-	s.app.Use(NewHTTPJWTMiddleware(s.tokenManager))
+	s.app.Use(NewHTTPJWTMiddleware(s.tokenManager, JWTMiddlewareConfig{}))
 	s.app.Get("/secure", func(c fiber.Ctx) error {
-		claims, _ := c.Locals("jwt_claims").(*sharedjwt.Claims)
+		userID, _ := UserIDFromContext(c)
+		claims, ok := ClaimsFromContext(c)
+		if !ok {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
 		return c.JSON(fiber.Map{
-			"user_id": c.Locals("user_id"),
+			"user_id": userID,
 			"subject": claims.Subject,
 		})
 	})
 	s.app.Post("/auth/login", func(c fiber.Ctx) error {
 		return c.JSON(fiber.Map{"ok": true})
 	})
+	s.app.Get("/auth/login-attempts", func(c fiber.Ctx) error {
+		return c.JSON(fiber.Map{"attempts": 0})
+	})
 }
 
 func (s *HTTPJWTMiddlewareSuite) TestNewHTTPJWTMiddleware_TableDriven() {
@@ -93,6 +117,16 @@ func (s *HTTPJWTMiddlewareSuite) TestNewHTTPJWTMiddleware_TableDriven() {
 				assert.Equal(s.T(), true, payload["ok"])
 			},
 		},
+		{
+			name:   "similar login path still requires auth",
+			method: http.MethodGet,
+			path:   "/auth/login-attempts",
+			assertion: func(resp *http.Response, payload map[string]interface{}) {
+				require.NotNil(s.T(), resp)
+				assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+				assert.Equal(s.T(), "missing or invalid authorization header", payload["error"])
+			},
+		},
 		{
 			name:    "missing authorization header",
 			method:  http.MethodGet,
@@ -166,6 +200,184 @@ func (s *HTTPJWTMiddlewareSuite) TestNewHTTPJWTMiddleware_TableDriven() {
 	}
 }
 
+func (s *HTTPJWTMiddlewareSuite) TestNewHTTPJWTMiddleware_BypassRules_TableDriven() {
+	publicRules := []JWTBypassRule{
+		{Method: fiber.MethodPost, PathSuffix: "/auth/login"},
+		{Method: fiber.MethodPost, PathSuffix: "/auth/register"},
+		{Method: "post", PathSuffix: "auth/refresh/"},
+	}
+
+	tests := []struct {
+		name         string
+		bypass       []JWTBypassRule
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{name: "default keeps login public", method: http.MethodPost, path: "/api/v1/auth/login", expectedCode: fiber.StatusOK},
+		{name: "default keeps register public", method: http.MethodPost, path: "/api/v1/auth/register", expectedCode: fiber.StatusOK},
+		{name: "default protects other auth routes", method: http.MethodPost, path: "/api/v1/auth/change-password", expectedCode: fiber.StatusUnauthorized},
+		{name: "register route is public", bypass: publicRules, method: http.MethodPost, path: "/api/v1/auth/register", expectedCode: fiber.StatusOK},
+		{name: "refresh route is public with normalized rule", bypass: publicRules, method: http.MethodPost, path: "/api/v1/auth/refresh", expectedCode: fiber.StatusOK},
+		{name: "trailing slash still matches", bypass: publicRules, method: http.MethodPost, path: "/api/v1/auth/login/", expectedCode: fiber.StatusOK},
+		{name: "near-miss suffix requires auth", bypass: publicRules, method: http.MethodPost, path: "/api/v1/auth/login-history", expectedCode: fiber.StatusUnauthorized},
+		{name: "near-miss segment requires auth", bypass: publicRules, method: http.MethodPost, path: "/api/v1/oauth/login", expectedCode: fiber.StatusUnauthorized},
+		{name: "nested route ending in public path requires auth", bypass: publicRules, method: http.MethodPost, path: "/api/v1/wallets/auth/login", expectedCode: fiber.StatusUnauthorized},
+		{name: "public path outside prefix requires auth", bypass: publicRules, method: http.MethodPost, path: "/auth/login", expectedCode: fiber.StatusUnauthorized},
+		{name: "other method requires auth", bypass: publicRules, method: http.MethodGet, path: "/api/v1/auth/login", expectedCode: fiber.StatusUnauthorized},
+		{name: "empty rule list protects login", bypass: []JWTBypassRule{}, method: http.MethodPost, path: "/api/v1/auth/login", expectedCode: fiber.StatusUnauthorized},
+		{name: "blank suffix never bypasses", bypass: []JWTBypassRule{{Method: fiber.MethodGet, PathSuffix: " "}}, method: http.MethodGet, path: "/api/v1/wallets", expectedCode: fiber.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			app := fiber.New()
+			app.Use(NewHTTPJWTMiddleware(jwtmocks.NewTokenManager(s.T()), JWTMiddlewareConfig{Bypass: tc.bypass, PathPrefix: "/api/v1"}))
+			app.All("/*", func(c fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, _, _, err := doRequest(app, tc.method, tc.path, nil, nil)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func (s *HTTPJWTMiddlewareSuite) TestNewHTTPJWTMiddleware_ForwardsRequestContext() {
+	type ctxKey struct{}
+
+	s.tokenManager.EXPECT().Verify(mock.Anything, "valid-token").
+		RunAndReturn(func(ctx context.Context, _ string) (*sharedjwt.Claims, error) {
+			assert.Equal(s.T(), "upstream", ctx.Value(ctxKey{}))
+			return &sharedjwt.Claims{Subject: "user-1"}, nil
+		}).Once()
+
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.SetContext(context.WithValue(c.Context(), ctxKey{}, "upstream"))
+		return c.Next()
+	})
+	app.Use(NewHTTPJWTMiddleware(s.tokenManager, JWTMiddlewareConfig{}))
+	app.Get("/secure", func(c fiber.Ctx) error {
+		return c.JSON(fiber.Map{"user_id": c.Locals("user_id")})
+	})
+
+	resp, payload, _, err := doRequest(app, http.MethodGet, "/secure", nil, map[string]string{fiber.HeaderAuthorization: "Bearer valid-token"})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "user-1", payload["user_id"])
+}
+
+func (s *HTTPJWTMiddlewareSuite) TestNewHTTPJWTMiddleware_PropagatesClaimsToContext() {
+	s.tokenManager.EXPECT().Verify(mock.Anything, "valid-token").
+		Return(&sharedjwt.Claims{Subject: "user-1", Scopes: []string{"wallet:read"}}, nil).Once()
+
+	app := fiber.New()
+	app.Use(NewHTTPJWTMiddleware(s.tokenManager, JWTMiddlewareConfig{}))
+	app.Get("/secure", func(c fiber.Ctx) error {
+		claims, ok := sharedjwt.GetClaims(c.Context())
+		if !ok {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return c.JSON(fiber.Map{"subject": claims.Subject, "scopes": claims.Scopes})
+	})
+
+	resp, payload, _, err := doRequest(app, http.MethodGet, "/secure", nil, map[string]string{fiber.HeaderAuthorization: "Bearer valid-token"})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "user-1", payload["subject"])
+	assert.Equal(s.T(), []interface{}{"wallet:read"}, payload["scopes"])
+}
+
+func (s *HTTPJWTMiddlewareSuite) TestNewHTTPJWTMiddleware_VerifyTimeout_TableDriven() {
+	const verifyTimeout = 20 * time.Millisecond
+
+	tests := []struct {
+		name          string
+		verify        func(release <-chan struct{}) func(context.Context, string) (*sharedjwt.Claims, error)
+		expectedCode  int
+		expectedError string
+		expectRetry   bool
+	}{
+		{
+			name: "verifier ignoring context is cut off",
+			verify: func(release <-chan struct{}) func(context.Context, string) (*sharedjwt.Claims, error) {
+				return func(context.Context, string) (*sharedjwt.Claims, error) {
+					<-release
+					return &sharedjwt.Claims{Subject: "user-1"}, nil
+				}
+			},
+			expectedCode:  fiber.StatusServiceUnavailable,
+			expectedError: "token verification unavailable",
+			expectRetry:   true,
+		},
+		{
+			name: "verifier honouring context deadline",
+			verify: func(<-chan struct{}) func(context.Context, string) (*sharedjwt.Claims, error) {
+				return func(ctx context.Context, _ string) (*sharedjwt.Claims, error) {
+					<-ctx.Done()
+					return nil, fmt.Errorf("jwt: failed to fetch JWKS: %w", ctx.Err())
+				}
+			},
+			expectedCode:  fiber.StatusServiceUnavailable,
+			expectedError: "token verification unavailable",
+			expectRetry:   true,
+		},
+		{
+			name: "invalid token within deadline stays unauthorized",
+			verify: func(<-chan struct{}) func(context.Context, string) (*sharedjwt.Claims, error) {
+				return func(context.Context, string) (*sharedjwt.Claims, error) {
+					return nil, errors.New("signature mismatch")
+				}
+			},
+			expectedCode:  fiber.StatusUnauthorized,
+			expectedError: "invalid token",
+		},
+		{
+			name: "valid token within deadline",
+			verify: func(<-chan struct{}) func(context.Context, string) (*sharedjwt.Claims, error) {
+				return func(ctx context.Context, _ string) (*sharedjwt.Claims, error) {
+					_, hasDeadline := ctx.Deadline()
+					assert.True(s.T(), hasDeadline)
+					return &sharedjwt.Claims{Subject: "user-1"}, nil
+				}
+			},
+			expectedCode: fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			release := make(chan struct{})
+			defer close(release)
+			s.tokenManager.EXPECT().Verify(mock.Anything, "token-123").RunAndReturn(tc.verify(release)).Once()
+
+			app := fiber.New()
+			app.Use(NewHTTPJWTMiddleware(s.tokenManager, JWTMiddlewareConfig{VerifyTimeout: verifyTimeout}))
+			app.Get("/secure", func(c fiber.Ctx) error {
+				userID, _ := UserIDFromContext(c)
+				return c.JSON(fiber.Map{"user_id": userID})
+			})
+
+			started := time.Now()
+			resp, payload, _, err := doRequest(app, http.MethodGet, "/secure", nil, map[string]string{fiber.HeaderAuthorization: "Bearer token-123"})
+			require.NoError(s.T(), err)
+			assert.Less(s.T(), time.Since(started), time.Second)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedError != "" {
+				assert.Equal(s.T(), tc.expectedError, payload["error"])
+			} else {
+				assert.Equal(s.T(), "user-1", payload["user_id"])
+			}
+			if tc.expectRetry {
+				assert.Equal(s.T(), "1", resp.Header.Get(fiber.HeaderRetryAfter))
+			}
+		})
+	}
+}
+
 func TestHTTPJWTMiddlewareSuite(t *testing.T) {
 	suite.Run(t, new(HTTPJWTMiddlewareSuite))
 }
@@ -274,6 +486,7 @@ func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyM
 				require.NotNil(s.T(), resp)
 				assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
 				assert.Equal(s.T(), "request is already in progress", payload["error"])
+				assert.Equal(s.T(), "1", resp.Header.Get(fiber.HeaderRetryAfter))
 			},
 		},
 		{
@@ -342,12 +555,12 @@ func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyM
 
 			var middleware fiber.Handler
 			if tc.storeNil {
-				middleware = NewHTTPWithdrawIdempotencyMiddleware(nil)
+				middleware = NewHTTPWithdrawIdempotencyMiddleware(nil, IdempotencyOptions{})
 			} else {
 				if tc.setupMock != nil {
 					tc.setupMock(s.store)
 				}
-				middleware = NewHTTPWithdrawIdempotencyMiddleware(s.store)
+				middleware = NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{})
 			}
 
 			s.app.Use(func(c fiber.Ctx) error {
@@ -367,161 +580,2314 @@ func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyM
 	}
 }
 
-func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestWithdrawRequestHash_TableDriven() {
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyMiddleware_StatusHeader_TableDriven() {
 	tests := []struct {
-		name     string
-		method   string
-		path     string
-		userID   string
-		body     []byte
-		other    []byte
-		assertFn func(string, string)
+		name         string
+		statusHeader bool
+		decision     sharedidempotency.Decision
+		complete     bool
+		expectHeader string
 	}{
 		{
-			name:   "same payload produces same hash",
-			method: "post",
-			path:   " /withdrawals ",
-			userID: " user-1 ",
-			body:   []byte(`{"amount_minor":100}`),
-			other:  []byte(`{"amount_minor":100}`),
-			assertFn: func(left, right string) {
-				assert.Equal(s.T(), left, right)
-			},
+			name:         "fresh withdrawal reports acquired",
+			statusHeader: true,
+			decision:     sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired},
+			complete:     true,
+			expectHeader: "acquired",
 		},
 		{
-			name:   "different payload produces different hash",
-			method: "POST",
-			path:   "/withdrawals",
-			userID: "user-1",
-			body:   []byte(`{"amount_minor":100}`),
-			other:  []byte(`{"amount_minor":200}`),
-			assertFn: func(left, right string) {
-				assert.NotEqual(s.T(), left, right)
+			name:         "replayed withdrawal reports replayed",
+			statusHeader: true,
+			decision: sharedidempotency.Decision{
+				Type:        sharedidempotency.DecisionReplay,
+				StatusCode:  fiber.StatusCreated,
+				Body:        []byte(`{"ok":true}`),
+				ContentType: fiber.MIMEApplicationJSON,
 			},
+			expectHeader: "replayed",
+		},
+		{
+			name:         "header omitted when disabled",
+			statusHeader: false,
+			decision:     sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired},
+			complete:     true,
+			expectHeader: "",
 		},
 	}
 
 	for _, tc := range tests {
 		s.Run(tc.name, func() {
-			first := withdrawRequestHash(tc.method, tc.path, tc.userID, tc.body)
-			second := withdrawRequestHash(tc.method, tc.path, tc.userID, tc.other)
-			tc.assertFn(first, second)
+			s.SetupTest()
+			s.store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(tc.decision, nil)
+			if tc.complete {
+				s.store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			}
+
+			s.app.Use(func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return c.Next()
+			})
+			s.app.Post("/withdrawals", NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{StatusHeader: tc.statusHeader}), func(c fiber.Ctx) error {
+				return c.Status(fiber.StatusCreated).JSON(fiber.Map{"ok": true})
+			})
+
+			resp, _, _, err := doRequest(s.app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), map[string]string{IdempotencyKeyHeader: "idem-1"})
+			require.NoError(s.T(), err)
+			require.NotNil(s.T(), resp)
+			assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+			assert.Equal(s.T(), tc.expectHeader, resp.Header.Get(IdempotencyStatusHeader))
 		})
 	}
 }
 
-func TestHTTPWithdrawIdempotencyMiddlewareSuite(t *testing.T) {
-	suite.Run(t, new(HTTPWithdrawIdempotencyMiddlewareSuite))
-}
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyMiddleware_MaxBodyBytes_TableDriven() {
+	tests := []struct {
+		name         string
+		maxBodyBytes int
+		response     string
+		expectStored sharedidempotency.StoredResponse
+	}{
+		{
+			name:         "response under the limit is stored fully",
+			maxBodyBytes: 64,
+			response:     `{"reference_id":"ref-1"}`,
+			expectStored: sharedidempotency.StoredResponse{
+				StatusCode:  fiber.StatusCreated,
+				Body:        []byte(`{"reference_id":"ref-1"}`),
+				ContentType: fiber.MIMEApplicationJSON,
+			},
+		},
+		{
+			name:         "response at the limit is stored fully",
+			maxBodyBytes: len(`{"reference_id":"ref-1"}`),
+			response:     `{"reference_id":"ref-1"}`,
+			expectStored: sharedidempotency.StoredResponse{
+				StatusCode:  fiber.StatusCreated,
+				Body:        []byte(`{"reference_id":"ref-1"}`),
+				ContentType: fiber.MIMEApplicationJSON,
+			},
+		},
+		{
+			name:         "response over the limit is stored as truncation metadata",
+			maxBodyBytes: 16,
+			response:     `{"reference_id":"ref-1"}`,
+			expectStored: sharedidempotency.StoredResponse{
+				StatusCode:  fiber.StatusCreated,
+				Body:        []byte(`{"original_bytes":24,"original_content_type":"application/json"}`),
+				ContentType: sharedidempotency.TruncatedContentType,
+			},
+		},
+		{
+			name:     "default limit stores ordinary responses",
+			response: `{"reference_id":"ref-1"}`,
+			expectStored: sharedidempotency.StoredResponse{
+				StatusCode:  fiber.StatusCreated,
+				Body:        []byte(`{"reference_id":"ref-1"}`),
+				ContentType: fiber.MIMEApplicationJSON,
+			},
+		},
+	}
 
-type stubRateLimiter struct {
-	result  sharedratelimit.Result
-	err     error
-	lastKey string
-}
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
 
-func (s *stubRateLimiter) Allow(_ context.Context) (sharedratelimit.Result, error) {
-	return s.result, s.err
-}
+			var stored sharedidempotency.StoredResponse
+			s.store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired}, nil).Once()
+			s.store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).
+				Run(func(_ context.Context, _ sharedidempotency.Request, response sharedidempotency.StoredResponse) {
+					stored = response
+				}).
+				Return(nil).Once()
 
-func (s *stubRateLimiter) AllowKey(_ context.Context, key string) (sharedratelimit.Result, error) {
-	s.lastKey = key
-	return s.result, s.err
-}
+			s.app.Use(func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return c.Next()
+			})
+			s.app.Post("/withdrawals", NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{MaxBodyBytes: tc.maxBodyBytes}), func(c fiber.Ctx) error {
+				c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+				return c.Status(fiber.StatusCreated).SendString(tc.response)
+			})
 
-func (s *stubRateLimiter) Reset(_ context.Context) error {
-	return nil
+			resp, _, raw, err := doRequest(s.app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), map[string]string{IdempotencyKeyHeader: "idem-1"})
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+			// The first response is always delivered in full; only the stored copy is capped.
+			assert.Equal(s.T(), tc.response, string(raw))
+			assert.Equal(s.T(), tc.expectStored, stored)
+		})
+	}
 }
 
-func (s *stubRateLimiter) ResetKey(_ context.Context, _ string) error {
-	return nil
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyMiddleware_TruncatedReplay() {
+	s.store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(sharedidempotency.Decision{
+		Type:        sharedidempotency.DecisionReplay,
+		StatusCode:  fiber.StatusCreated,
+		Body:        []byte(`{"original_bytes":70000,"original_content_type":"application/json"}`),
+		ContentType: sharedidempotency.TruncatedContentType,
+	}, nil).Once()
+
+	s.app.Use(func(c fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		return c.Next()
+	})
+	s.app.Post("/withdrawals", NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{}), func(c fiber.Ctx) error {
+		s.Fail("handler must not run for a replayed key")
+		return nil
+	})
+
+	resp, payload, _, err := doRequest(s.app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), map[string]string{IdempotencyKeyHeader: "idem-1"})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusGone, resp.StatusCode)
+	assert.Equal(s.T(), "true", resp.Header.Get(IdempotencyReplayedHeader))
+	assert.Equal(s.T(), "original response is too large to replay", payload["error"])
+	assert.Equal(s.T(), float64(fiber.StatusCreated), payload["original_status"])
 }
 
-func (s *stubRateLimiter) Close() error {
-	return nil
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyMiddleware_TimedOutHandlerReleasesKey() {
+	s.store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired}, nil).Once()
+	s.store.EXPECT().Release(mock.Anything, mock.MatchedBy(func(request sharedidempotency.Request) bool {
+		return request.Scope == "withdraw:user-1" && request.Key == "idem-1"
+	})).Return(nil).Once()
+
+	s.app.Use(func(c fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		return c.Next()
+	})
+	s.app.Post("/withdrawals", NewHTTPTimeoutMiddleware(time.Millisecond), NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{}), func(c fiber.Ctx) error {
+		<-c.Context().Done()
+		return c.Context().Err()
+	})
+
+	resp, payload, _, err := doRequest(s.app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), map[string]string{IdempotencyKeyHeader: "idem-1"})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(s.T(), "request timed out", payload["error"])
+	s.store.AssertNotCalled(s.T(), "Complete", mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestHTTPRateLimitMiddleware_TableDriven(t *testing.T) {
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyMiddleware_CompleteDeadLetter_TableDriven() {
+	completeErr := errors.New("complete failed")
+	responseBody := []byte(`{"reference_id":"ref-1"}`)
+
 	tests := []struct {
-		name          string
-		limiter       *stubRateLimiter
-		keyExtractor  func(c fiber.Ctx) string
-		expectedCode  int
-		expectedError string
-		assertHeaders bool
-		expectedKey   string
+		name         string
+		setupMock    func(store *idempotencymocks.Store, deadLetter *idempotencymocks.DeadLetterQueue)
+		expectedCode int
+		expectedBody string
 	}{
 		{
-			name:          "allows request and sets headers",
-			limiter:       &stubRateLimiter{result: sharedratelimit.Result{Allowed: true, Limit: 20, Remaining: 19, ResetAt: time.Unix(200, 0)}},
-			keyExtractor:  func(c fiber.Ctx) string { return "withdraw:user:test-user" },
-			expectedCode:  fiber.StatusOK,
-			assertHeaders: true,
-			expectedKey:   "withdraw:user:test-user",
+			name: "transient failure succeeds on retry",
+			setupMock: func(store *idempotencymocks.Store, _ *idempotencymocks.DeadLetterQueue) {
+				store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).Return(completeErr).Once()
+				store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedCode: fiber.StatusCreated,
+			expectedBody: string(responseBody),
 		},
 		{
-			name:          "rejects when limit exceeded",
-			limiter:       &stubRateLimiter{result: sharedratelimit.Result{Allowed: false, Limit: 20, Remaining: 0, RetryAfter: 5 * time.Second, ResetAt: time.Unix(250, 0)}},
-			keyExtractor:  func(c fiber.Ctx) string { return "withdraw:user:test-user" },
-			expectedCode:  fiber.StatusTooManyRequests,
-			expectedError: "rate limit exceeded",
-			expectedKey:   "withdraw:user:test-user",
+			name: "completion runs under its own deadline",
+			setupMock: func(store *idempotencymocks.Store, _ *idempotencymocks.DeadLetterQueue) {
+				store.EXPECT().Complete(mock.MatchedBy(func(ctx context.Context) bool {
+					deadline, ok := ctx.Deadline()
+					return ok && time.Until(deadline) <= defaultIdempotencyCompleteTimeout && ctx.Err() == nil
+				}), mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			expectedCode: fiber.StatusCreated,
+			expectedBody: string(responseBody),
 		},
 		{
-			name:          "returns internal error when limiter fails",
-			limiter:       &stubRateLimiter{err: errors.New("boom")},
-			keyExtractor:  func(c fiber.Ctx) string { return "withdraw:user:test-user" },
-			expectedCode:  fiber.StatusInternalServerError,
-			expectedError: "internal server error",
-			expectedKey:   "withdraw:user:test-user",
+			name: "permanent failure dead-letters the response",
+			setupMock: func(store *idempotencymocks.Store, deadLetter *idempotencymocks.DeadLetterQueue) {
+				store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).Return(completeErr).Times(3)
+				deadLetter.EXPECT().Enqueue(mock.Anything,
+					mock.MatchedBy(func(request sharedidempotency.Request) bool {
+						return request.Scope == "withdraw:user-1" && request.Key == "idem-1"
+					}),
+					mock.MatchedBy(func(response sharedidempotency.StoredResponse) bool {
+						return response.StatusCode == fiber.StatusCreated && string(response.Body) == string(responseBody)
+					}),
+					completeErr,
+				).Return(nil).Once()
+			},
+			expectedCode: fiber.StatusCreated,
+			expectedBody: string(responseBody),
 		},
 		{
-			name:          "passes through when limiter is nil",
-			limiter:       nil,
-			keyExtractor:  func(c fiber.Ctx) string { return "withdraw:user:test-user" },
-			expectedCode:  fiber.StatusOK,
-			expectedError: "",
+			name: "failed dead letter answers internal error",
+			setupMock: func(store *idempotencymocks.Store, deadLetter *idempotencymocks.DeadLetterQueue) {
+				store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).Return(completeErr).Times(3)
+				deadLetter.EXPECT().Enqueue(mock.Anything, mock.Anything, mock.Anything, completeErr).Return(errors.New("db down")).Once()
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectedBody: `{"error":"failed to persist idempotency response"}`,
 		},
 	}
 
 	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			app := fiber.New()
-			app.Use(func(c fiber.Ctx) error {
-				c.Locals("user_id", "test-user")
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			deadLetter := idempotencymocks.NewDeadLetterQueue(s.T())
+			s.store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired}, nil).Once()
+			tc.setupMock(s.store, deadLetter)
+
+			s.app.Use(func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
 				return c.Next()
 			})
+			s.app.Post("/withdrawals", NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{
+				CompleteBackoff: time.Millisecond,
+				DeadLetter:      deadLetter,
+			}), func(c fiber.Ctx) error {
+				return c.Status(fiber.StatusCreated).Send(responseBody)
+			})
 
-			var limiter sharedratelimit.Limiter
-			if tc.limiter != nil {
-				limiter = tc.limiter
+			resp, _, raw, err := doRequest(s.app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), map[string]string{IdempotencyKeyHeader: "idem-1"})
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			assert.JSONEq(s.T(), tc.expectedBody, string(raw))
+		})
+	}
+}
+
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyMiddleware_KeyPolicy_TableDriven() {
+	policy := IdempotencyKeyPolicy{MinLength: 8, MaxLength: 16}
+
+	tests := []struct {
+		name        string
+		key         string
+		expectValid bool
+		expectError string
+	}{
+		{name: "too short", key: "wd-1", expectError: "invalid idempotency key: must be a UUID or 8-16 characters long"},
+		{name: "too long", key: strings.Repeat("a", 17), expectError: "invalid idempotency key: must be a UUID or 8-16 characters long"},
+		{name: "disallowed characters", key: "wd 0001/x", expectError: `invalid idempotency key: only letters, digits, and "-_.:" are allowed`},
+		{name: "valid opaque token", key: "wd-0001:retry", expectValid: true},
+		{name: "valid uuid beyond max length", key: "3f1c2a9e-8b7d-4c6e-9a1f-2b3c4d5e6f70", expectValid: true},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.expectValid {
+				s.store.EXPECT().Acquire(mock.Anything, mock.MatchedBy(func(request sharedidempotency.Request) bool {
+					return request.Key == tc.key
+				})).Return(sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired}, nil)
+				s.store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).Return(nil)
 			}
 
-			app.Use(NewHTTPRateLimitMiddleware(RateLimitConfig{
-				Limiter:      limiter,
-				KeyExtractor: tc.keyExtractor,
-			}))
+			s.app.Use(func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return c.Next()
+			})
+			s.app.Post("/withdrawals", NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{KeyPolicy: policy}), func(c fiber.Ctx) error {
+				return c.Status(fiber.StatusCreated).JSON(fiber.Map{"ok": true})
+			})
 
-			app.Get("/limited", func(c fiber.Ctx) error {
-				return c.JSON(fiber.Map{"ok": true})
+			resp, payload, _, err := doRequest(s.app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), map[string]string{IdempotencyKeyHeader: tc.key})
+			require.NoError(s.T(), err)
+			require.NotNil(s.T(), resp)
+			if tc.expectValid {
+				assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+				return
+			}
+
+			assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+			assert.Equal(s.T(), tc.expectError, payload["error"])
+		})
+	}
+}
+
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyMiddleware_ReferenceReplay_TableDriven() {
+	tests := []struct {
+		name       string
+		echoKey    bool
+		expectEcho string
+	}{
+		{name: "replay returns original reference and echoes key", echoKey: true, expectEcho: "idem-1"},
+		{name: "replay returns original reference without echo", echoKey: false, expectEcho: ""},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+
+			var stored sharedidempotency.StoredResponse
+			s.store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired}, nil).Once()
+			s.store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).
+				Run(func(_ context.Context, _ sharedidempotency.Request, response sharedidempotency.StoredResponse) {
+					stored = response
+				}).
+				Return(nil).Once()
+
+			issued := 0
+			s.app.Use(func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return c.Next()
+			})
+			s.app.Post("/withdrawals", NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{EchoKey: tc.echoKey}), func(c fiber.Ctx) error {
+				issued++
+				return c.Status(fiber.StatusOK).JSON(fiber.Map{"reference_id": fmt.Sprintf("ref-%d", issued)})
 			})
 
-			resp, payload, _, err := doRequest(app, http.MethodGet, "/limited", nil, nil)
-			require.NoError(t, err)
-			require.NotNil(t, resp)
-			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+			headers := map[string]string{IdempotencyKeyHeader: "idem-1"}
+			body := []byte(`{"amount_minor":100}`)
 
-			if tc.expectedError != "" {
-				assert.Equal(t, tc.expectedError, payload["error"])
-			}
+			firstResp, firstPayload, _, err := doRequest(s.app, http.MethodPost, "/withdrawals", body, headers)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), fiber.StatusOK, firstResp.StatusCode)
+			assert.Equal(s.T(), "ref-1", firstPayload["reference_id"])
+			assert.Equal(s.T(), tc.expectEcho, firstResp.Header.Get(IdempotencyKeyHeader))
 
-			if tc.assertHeaders {
-				assert.Equal(t, "20", resp.Header.Get("X-RateLimit-Limit"))
-				assert.Equal(t, "19", resp.Header.Get("X-RateLimit-Remaining"))
-			}
+			s.store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(sharedidempotency.Decision{
+				Type:        sharedidempotency.DecisionReplay,
+				StatusCode:  stored.StatusCode,
+				Body:        stored.Body,
+				ContentType: stored.ContentType,
+			}, nil).Once()
 
-			if tc.limiter != nil {
-				assert.Equal(t, tc.expectedKey, tc.limiter.lastKey)
+			replayResp, replayPayload, _, err := doRequest(s.app, http.MethodPost, "/withdrawals", body, headers)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), fiber.StatusOK, replayResp.StatusCode)
+			assert.Equal(s.T(), "ref-1", replayPayload["reference_id"])
+			assert.Equal(s.T(), tc.expectEcho, replayResp.Header.Get(IdempotencyKeyHeader))
+			assert.Equal(s.T(), 1, issued)
+		})
+	}
+}
+
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPWithdrawIdempotencyMiddleware_ReplayedHeader() {
+	createdAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("WIB", 7*60*60))
+
+	var stored sharedidempotency.StoredResponse
+	s.store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired}, nil).Once()
+	s.store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).
+		Run(func(_ context.Context, _ sharedidempotency.Request, response sharedidempotency.StoredResponse) {
+			stored = response
+		}).
+		Return(nil).Once()
+
+	s.app.Use(func(c fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		return c.Next()
+	})
+	s.app.Post("/withdrawals", NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{}), func(c fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"reference_id": "ref-1"})
+	})
+
+	headers := map[string]string{IdempotencyKeyHeader: "idem-1"}
+	body := []byte(`{"amount_minor":100}`)
+
+	first, _, firstRaw, err := doRequest(s.app, http.MethodPost, "/withdrawals", body, headers)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, first.StatusCode)
+	assert.Empty(s.T(), first.Header.Get(IdempotencyReplayedHeader))
+	assert.Empty(s.T(), first.Header.Get(IdempotencyCreatedAtHeader))
+
+	s.store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(sharedidempotency.Decision{
+		Type:        sharedidempotency.DecisionReplay,
+		StatusCode:  stored.StatusCode,
+		Body:        stored.Body,
+		ContentType: stored.ContentType,
+		CreatedAt:   createdAt,
+	}, nil).Once()
+
+	replay, _, replayRaw, err := doRequest(s.app, http.MethodPost, "/withdrawals", body, headers)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), first.StatusCode, replay.StatusCode)
+	assert.Equal(s.T(), firstRaw, replayRaw)
+	assert.Equal(s.T(), first.Header.Get(fiber.HeaderContentType), replay.Header.Get(fiber.HeaderContentType))
+	assert.Equal(s.T(), "true", replay.Header.Get(IdempotencyReplayedHeader))
+	assert.Equal(s.T(), "2026-03-03T22:06:07Z", replay.Header.Get(IdempotencyCreatedAtHeader))
+}
+
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestNewHTTPIdempotencyMiddleware_SharedAcrossEndpoints_TableDriven() {
+	const depositKeyHeader = "Idempotency-Key"
+
+	tests := []struct {
+		name          string
+		path          string
+		headers       map[string]string
+		body          []byte
+		expectScope   string
+		expectedCode  int
+		expectedError string
+	}{
+		{
+			name:         "deposit uses its own scope and header",
+			path:         "/deposits",
+			headers:      map[string]string{depositKeyHeader: "idem-1"},
+			body:         []byte(`{"amount_minor":100}`),
+			expectScope:  "deposit:user-1",
+			expectedCode: fiber.StatusCreated,
+		},
+		{
+			name:         "withdraw keeps withdraw scope for the same key",
+			path:         "/withdrawals",
+			headers:      map[string]string{IdempotencyKeyHeader: "idem-1"},
+			body:         []byte(`{"amount_minor":100}`),
+			expectScope:  "withdraw:user-1",
+			expectedCode: fiber.StatusCreated,
+		},
+		{
+			name:          "deposit ignores the withdraw header",
+			path:          "/deposits",
+			headers:       map[string]string{IdempotencyKeyHeader: "idem-1"},
+			body:          []byte(`{"amount_minor":100}`),
+			expectedCode:  fiber.StatusBadRequest,
+			expectedError: "missing idempotency key",
+		},
+		{
+			name:          "deposit requires a body",
+			path:          "/deposits",
+			headers:       map[string]string{depositKeyHeader: "idem-1"},
+			expectedCode:  fiber.StatusBadRequest,
+			expectedError: "missing request body",
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+
+			if tc.expectScope != "" {
+				matchesScope := mock.MatchedBy(func(request sharedidempotency.Request) bool {
+					return request.Scope == tc.expectScope && request.Key == "idem-1"
+				})
+				s.store.EXPECT().Acquire(mock.Anything, matchesScope).Return(sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired}, nil).Once()
+				s.store.EXPECT().Complete(mock.Anything, matchesScope, mock.Anything).Return(nil).Once()
+			}
+
+			s.app.Use(func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return c.Next()
+			})
+			created := func(c fiber.Ctx) error {
+				return c.Status(fiber.StatusCreated).JSON(fiber.Map{"ok": true})
+			}
+			s.app.Post("/withdrawals", NewHTTPWithdrawIdempotencyMiddleware(s.store, IdempotencyOptions{}), created)
+			s.app.Post("/deposits", NewHTTPIdempotencyMiddleware(s.store, IdempotencyOptions{
+				Scope:       "deposit",
+				HeaderName:  depositKeyHeader,
+				RequireBody: true,
+			}), created)
+
+			resp, payload, _, err := doRequest(s.app, http.MethodPost, tc.path, tc.body, tc.headers)
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectedCode, resp.StatusCode)
+			if tc.expectedError != "" {
+				assert.Equal(s.T(), tc.expectedError, payload["error"])
+			}
+		})
+	}
+}
+
+func (s *HTTPWithdrawIdempotencyMiddlewareSuite) TestIdempotencyRequestHash_TableDriven() {
+	type hashInput struct {
+		method  string
+		path    string
+		query   string
+		userID  string
+		headers map[string]string
+		body    []byte
+	}
+
+	base := hashInput{method: "POST", path: "/withdrawals", userID: "user-1", body: []byte(`{"amount_minor":100}`)}
+	with := func(modify func(*hashInput)) hashInput {
+		input := base
+		modify(&input)
+		return input
+	}
+
+	tests := []struct {
+		name       string
+		left       hashInput
+		right      hashInput
+		expectSame bool
+	}{
+		{
+			name:       "same payload produces same hash",
+			left:       hashInput{method: "post", path: " /withdrawals ", userID: " user-1 ", body: []byte(`{"amount_minor":100}`)},
+			right:      base,
+			expectSame: true,
+		},
+		{
+			name:  "different payload produces different hash",
+			left:  base,
+			right: with(func(in *hashInput) { in.body = []byte(`{"amount_minor":200}`) }),
+		},
+		{
+			name:  "different query string produces different hash",
+			left:  with(func(in *hashInput) { in.query = "foo=1" }),
+			right: with(func(in *hashInput) { in.query = "foo=2" }),
+		},
+		{
+			name:  "query string versus none produces different hash",
+			left:  with(func(in *hashInput) { in.query = "foo=1" }),
+			right: base,
+		},
+		{
+			name:       "query parameter order does not matter",
+			left:       with(func(in *hashInput) { in.query = "b=2&a=1" }),
+			right:      with(func(in *hashInput) { in.query = "a=1&b=2" }),
+			expectSame: true,
+		},
+		{
+			name:  "different significant header produces different hash",
+			left:  with(func(in *hashInput) { in.headers = map[string]string{"X-Chain-Id": "polygon"} }),
+			right: with(func(in *hashInput) { in.headers = map[string]string{"X-Chain-Id": "ethereum"} }),
+		},
+		{
+			name: "header name case and order do not matter",
+			left: with(func(in *hashInput) {
+				in.headers = map[string]string{"X-Chain-Id": "polygon", "X-Client": "app"}
+			}),
+			right: with(func(in *hashInput) {
+				in.headers = map[string]string{"x-client": "app", "x-chain-id": " polygon "}
+			}),
+			expectSame: true,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			left := idempotencyRequestHash(tc.left.method, tc.left.path, tc.left.query, tc.left.userID, tc.left.headers, tc.left.body)
+			right := idempotencyRequestHash(tc.right.method, tc.right.path, tc.right.query, tc.right.userID, tc.right.headers, tc.right.body)
+			if tc.expectSame {
+				assert.Equal(s.T(), left, right)
+				return
+			}
+			assert.NotEqual(s.T(), left, right)
+		})
+	}
+}
+
+func TestHTTPWithdrawIdempotencyMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(HTTPWithdrawIdempotencyMiddlewareSuite))
+}
+
+type stubRateLimiter struct {
+	result  sharedratelimit.Result
+	err     error
+	lastKey string
+}
+
+func (s *stubRateLimiter) Allow(_ context.Context) (sharedratelimit.Result, error) {
+	return s.result, s.err
+}
+
+func (s *stubRateLimiter) AllowKey(_ context.Context, key string) (sharedratelimit.Result, error) {
+	s.lastKey = key
+	return s.result, s.err
+}
+
+func (s *stubRateLimiter) Reset(_ context.Context) error {
+	return nil
+}
+
+func (s *stubRateLimiter) ResetKey(_ context.Context, _ string) error {
+	return nil
+}
+
+func (s *stubRateLimiter) Close() error {
+	return nil
+}
+
+func TestHTTPRateLimitMiddleware_TableDriven(t *testing.T) {
+	tests := []struct {
+		name          string
+		limiter       *stubRateLimiter
+		keyExtractor  func(c fiber.Ctx) string
+		expectedCode  int
+		expectedError string
+		assertHeaders bool
+		expectedKey   string
+	}{
+		{
+			name:          "allows request and sets headers",
+			limiter:       &stubRateLimiter{result: sharedratelimit.Result{Allowed: true, Limit: 20, Remaining: 19, ResetAt: time.Unix(200, 0)}},
+			keyExtractor:  func(c fiber.Ctx) string { return "withdraw:user:test-user" },
+			expectedCode:  fiber.StatusOK,
+			assertHeaders: true,
+			expectedKey:   "withdraw:user:test-user",
+		},
+		{
+			name:          "rejects when limit exceeded",
+			limiter:       &stubRateLimiter{result: sharedratelimit.Result{Allowed: false, Limit: 20, Remaining: 0, RetryAfter: 5 * time.Second, ResetAt: time.Unix(250, 0)}},
+			keyExtractor:  func(c fiber.Ctx) string { return "withdraw:user:test-user" },
+			expectedCode:  fiber.StatusTooManyRequests,
+			expectedError: "rate limit exceeded",
+			expectedKey:   "withdraw:user:test-user",
+		},
+		{
+			name:          "returns internal error when limiter fails",
+			limiter:       &stubRateLimiter{err: errors.New("boom")},
+			keyExtractor:  func(c fiber.Ctx) string { return "withdraw:user:test-user" },
+			expectedCode:  fiber.StatusInternalServerError,
+			expectedError: "internal server error",
+			expectedKey:   "withdraw:user:test-user",
+		},
+		{
+			name:          "passes through when limiter is nil",
+			limiter:       nil,
+			keyExtractor:  func(c fiber.Ctx) string { return "withdraw:user:test-user" },
+			expectedCode:  fiber.StatusOK,
+			expectedError: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(c fiber.Ctx) error {
+				c.Locals("user_id", "test-user")
+				return c.Next()
+			})
+
+			var limiter sharedratelimit.Limiter
+			if tc.limiter != nil {
+				limiter = tc.limiter
+			}
+
+			app.Use(NewHTTPRateLimitMiddleware(RateLimitConfig{
+				Limiter:      limiter,
+				KeyExtractor: tc.keyExtractor,
+			}))
+
+			app.Get("/limited", func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{"ok": true})
+			})
+
+			resp, payload, _, err := doRequest(app, http.MethodGet, "/limited", nil, nil)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+
+			if tc.expectedError != "" {
+				assert.Equal(t, tc.expectedError, payload["error"])
+			}
+
+			if tc.assertHeaders {
+				assert.Equal(t, "20", resp.Header.Get("X-RateLimit-Limit"))
+				assert.Equal(t, "19", resp.Header.Get("X-RateLimit-Remaining"))
+			}
+
+			if tc.limiter != nil {
+				assert.Equal(t, tc.expectedKey, tc.limiter.lastKey)
+			}
+		})
+	}
+}
+
+func TestHTTPTimeoutMiddleware_TableDriven(t *testing.T) {
+	tests := []struct {
+		name           string
+		timeout        time.Duration
+		handlerDelay   time.Duration
+		ignoreDeadline bool
+		expectedCode   int
+		expectedError  string
+	}{
+		{
+			name:          "slow handler exceeds deadline",
+			timeout:       20 * time.Millisecond,
+			handlerDelay:  time.Second,
+			expectedCode:  fiber.StatusServiceUnavailable,
+			expectedError: "request timed out",
+		},
+		{
+			name:         "handler finishes in time",
+			timeout:      time.Second,
+			handlerDelay: 5 * time.Millisecond,
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:         "zero timeout disables middleware",
+			timeout:      0,
+			handlerDelay: 5 * time.Millisecond,
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:           "success written after the deadline is kept",
+			timeout:        5 * time.Millisecond,
+			handlerDelay:   30 * time.Millisecond,
+			ignoreDeadline: true,
+			expectedCode:   fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(NewHTTPTimeoutMiddleware(tc.timeout))
+			app.Get("/slow", func(c fiber.Ctx) error {
+				if tc.ignoreDeadline {
+					// Work that cannot be abandoned, like a committed withdrawal.
+					time.Sleep(tc.handlerDelay)
+					return c.JSON(fiber.Map{"ok": true})
+				}
+				select {
+				case <-c.Context().Done():
+					return c.Context().Err()
+				case <-time.After(tc.handlerDelay):
+					return c.JSON(fiber.Map{"ok": true})
+				}
+			})
+
+			resp, payload, _, err := doRequest(app, http.MethodGet, "/slow", nil, nil)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+			if tc.expectedError != "" {
+				assert.Equal(t, tc.expectedError, payload["error"])
+				assert.Equal(t, "1", resp.Header.Get(fiber.HeaderRetryAfter))
+			} else {
+				assert.Equal(t, true, payload["ok"])
+			}
+		})
+	}
+}
+
+func TestHTTPRecoveryMiddleware_TableDriven(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      fiber.Handler
+		expectedCode int
+		expectPanic  bool
+	}{
+		{
+			name: "panicking handler returns 500 and logs stack",
+			handler: func(c fiber.Ctx) error {
+				panic("db password=secret leaked")
+			},
+			expectedCode: fiber.StatusInternalServerError,
+			expectPanic:  true,
+		},
+		{
+			name: "healthy handler passes through",
+			handler: func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{"ok": true})
+			},
+			expectedCode: fiber.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+			app := fiber.New()
+			app.Use(NewHTTPRecoveryMiddleware(logger))
+			app.Use(NewHTTPRequestIDMiddleware())
+			app.Get("/wallets/:id", tc.handler)
+
+			resp, payload, body, err := doRequest(app, http.MethodGet, "/wallets/42", nil, map[string]string{RequestIDHeader: "req-123", ChainIDHeader: "polygon"})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+
+			if !tc.expectPanic {
+				assert.Equal(t, true, payload["ok"])
+				assert.Empty(t, logs.String())
+				return
+			}
+
+			assert.Equal(t, "internal server error", payload["error"])
+			assert.NotContains(t, string(body), "secret")
+			assert.NotContains(t, string(body), "goroutine")
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			assert.Equal(t, "ERROR", entry["level"])
+			assert.Equal(t, "panic recovered", entry["msg"])
+			assert.Equal(t, "req-123", entry["request_id"])
+			assert.Equal(t, "/wallets/:id", entry["route"])
+			assert.Equal(t, "/wallets/42", entry["path"])
+			assert.Equal(t, "db password=secret leaked", entry["panic"])
+			assert.Contains(t, entry["stack"], "goroutine")
+			assert.Contains(t, entry["stack"], "http.recovery.go")
+		})
+	}
+}
+
+func TestHTTPRateLimitMiddleware_PerIPKeyBehindProxy_TableDriven(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      fiber.Config
+		headers     map[string]string
+		expectedKey string
+	}{
+		{
+			name: "trusted proxy forwards the client ip",
+			config: fiber.Config{
+				TrustProxy: true,
+				// app.Test connections come from 0.0.0.0.
+				TrustProxyConfig:   fiber.TrustProxyConfig{Proxies: []string{"0.0.0.0/8"}},
+				ProxyHeader:        fiber.HeaderXForwardedFor,
+				EnableIPValidation: true,
+			},
+			headers:     map[string]string{fiber.HeaderXForwardedFor: "203.0.113.7, 10.0.0.2"},
+			expectedKey: "auth:ip:203.0.113.7",
+		},
+		{
+			name: "untrusted peer cannot spoof the header",
+			config: fiber.Config{
+				TrustProxy:         true,
+				TrustProxyConfig:   fiber.TrustProxyConfig{Proxies: []string{"10.0.0.1"}},
+				ProxyHeader:        fiber.HeaderXForwardedFor,
+				EnableIPValidation: true,
+			},
+			headers:     map[string]string{fiber.HeaderXForwardedFor: "203.0.113.7"},
+			expectedKey: "auth:ip:0.0.0.0",
+		},
+		{
+			name:        "header ignored without proxy config",
+			headers:     map[string]string{fiber.HeaderXForwardedFor: "203.0.113.7"},
+			expectedKey: "auth:ip:0.0.0.0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := &stubRateLimiter{result: sharedratelimit.Result{Allowed: true, Limit: 10, Remaining: 9}}
+
+			app := fiber.New(tc.config)
+			app.Use(NewHTTPRateLimitMiddleware(RateLimitConfig{
+				Limiter:      limiter,
+				KeyExtractor: PerIPKeyExtractor("auth"),
+			}))
+			app.Post("/auth/login", func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{"ok": true})
+			})
+
+			resp, _, _, err := doRequest(app, http.MethodPost, "/auth/login", nil, tc.headers)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, tc.expectedKey, limiter.lastKey)
+		})
+	}
+}
+
+func TestHTTPForwardedForMiddleware_TableDriven(t *testing.T) {
+	// app.Test connections come from 0.0.0.0.
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		forwardedFor   string
+		expectedIP     string
+	}{
+		{
+			name:           "takes the rightmost untrusted address",
+			trustedProxies: []string{"0.0.0.0", "10.0.0.0/8"},
+			forwardedFor:   "6.6.6.6, 203.0.113.7, 10.0.0.5",
+			expectedIP:     "203.0.113.7",
+		},
+		{
+			name:           "forged leftmost entry is ignored",
+			trustedProxies: []string{"0.0.0.0"},
+			forwardedFor:   "1.1.1.1, 198.51.100.2",
+			expectedIP:     "198.51.100.2",
+		},
+		{
+			name:           "chain of trusted proxies falls back to the peer",
+			trustedProxies: []string{"0.0.0.0", "10.0.0.0/8"},
+			forwardedFor:   "10.0.0.7, 10.0.0.5",
+			expectedIP:     "0.0.0.0",
+		},
+		{
+			name:           "garbage before a trusted hop falls back to the peer",
+			trustedProxies: []string{"0.0.0.0", "10.0.0.0/8"},
+			forwardedFor:   "203.0.113.7, not-an-ip, 10.0.0.5",
+			expectedIP:     "0.0.0.0",
+		},
+		{
+			name:           "header from an untrusted peer is ignored",
+			trustedProxies: []string{"10.0.0.0/8"},
+			forwardedFor:   "203.0.113.7",
+			expectedIP:     "0.0.0.0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			forwardedFor, err := NewHTTPForwardedForMiddleware(tc.trustedProxies, fiber.HeaderXForwardedFor)
+			require.NoError(t, err)
+
+			app := fiber.New(fiber.Config{
+				TrustProxy:         true,
+				TrustProxyConfig:   fiber.TrustProxyConfig{Proxies: tc.trustedProxies},
+				ProxyHeader:        fiber.HeaderXForwardedFor,
+				EnableIPValidation: true,
+			})
+			app.Use(forwardedFor)
+			app.Get("/ip", func(c fiber.Ctx) error {
+				return c.SendString(c.IP())
+			})
+
+			_, _, raw, err := doRequest(app, http.MethodGet, "/ip", nil, map[string]string{fiber.HeaderXForwardedFor: tc.forwardedFor})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIP, string(raw))
+		})
+	}
+
+	_, err := NewHTTPForwardedForMiddleware([]string{"10.0.0.300"}, fiber.HeaderXForwardedFor)
+	assert.Error(t, err)
+}
+
+func TestHTTPRequestResponseLogMiddleware_TableDriven(t *testing.T) {
+	longNote := strings.Repeat("x", 200)
+
+	tests := []struct {
+		name      string
+		config    RequestResponseLogConfig
+		body      string
+		response  fiber.Map
+		assertion func(t *testing.T, entry map[string]interface{})
+	}{
+		{
+			name:     "bodies are not captured by default",
+			body:     `{"email":"user@example.com","password":"hunter2"}`,
+			response: fiber.Map{"access_token": "token-1"},
+			assertion: func(t *testing.T, entry map[string]interface{}) {
+				assert.NotContains(t, entry, "request_body")
+				assert.NotContains(t, entry, "response_body")
+			},
+		},
+		{
+			name:     "login credentials and tokens are masked",
+			config:   RequestResponseLogConfig{CaptureBody: true},
+			body:     `{"email":"user@example.com","password":"hunter2"}`,
+			response: fiber.Map{"access_token": "token-1", "refresh_token": "token-2", "expires_in": 900},
+			assertion: func(t *testing.T, entry map[string]interface{}) {
+				assert.JSONEq(t, `{"email":"user@example.com","password":"***"}`, entry["request_body"].(string))
+				assert.JSONEq(t, `{"access_token":"***","refresh_token":"***","expires_in":900}`, entry["response_body"].(string))
+			},
+		},
+		{
+			name:     "any key containing password, secret or token is masked",
+			config:   RequestResponseLogConfig{CaptureBody: true},
+			body:     `{"current_password":"hunter2","new_password":"hunter3","client_secret":"s3cr3t","tokens":{"idToken":"t"}}`,
+			response: fiber.Map{"ok": true},
+			assertion: func(t *testing.T, entry map[string]interface{}) {
+				assert.JSONEq(t, `{"current_password":"***","new_password":"***","client_secret":"***","tokens":"***"}`, entry["request_body"].(string))
+			},
+		},
+		{
+			name:     "configured fields are masked at any depth",
+			config:   RequestResponseLogConfig{CaptureBody: true, RedactFields: []string{"pin"}},
+			body:     `{"amount_minor":100,"auth":{"PIN":"1234"},"items":[{"pin":"9999"}]}`,
+			response: fiber.Map{"ok": true},
+			assertion: func(t *testing.T, entry map[string]interface{}) {
+				assert.JSONEq(t, `{"amount_minor":100,"auth":{"PIN":"***"},"items":[{"pin":"***"}]}`, entry["request_body"].(string))
+			},
+		},
+		{
+			name:     "oversized bodies are truncated after redaction",
+			config:   RequestResponseLogConfig{CaptureBody: true, MaxBodyBytes: 40},
+			body:     `{"password":"hunter2","note":"` + longNote + `"}`,
+			response: fiber.Map{"ok": true},
+			assertion: func(t *testing.T, entry map[string]interface{}) {
+				requestBody := entry["request_body"].(string)
+				assert.True(t, strings.HasPrefix(requestBody, `{"note":"xxxx`), requestBody)
+				assert.Contains(t, requestBody, "...[truncated")
+				assert.Less(t, len(requestBody), 80)
+				assert.NotContains(t, requestBody, "hunter2")
+			},
+		},
+		{
+			name:     "non-json bodies are omitted",
+			config:   RequestResponseLogConfig{CaptureBody: true},
+			body:     `email=user@example.com&password=hunter2`,
+			response: fiber.Map{"ok": true},
+			assertion: func(t *testing.T, entry map[string]interface{}) {
+				assert.Equal(t, "[non-JSON body omitted, 39 bytes]", entry["request_body"])
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+			app := fiber.New()
+			app.Use(NewHTTPRequestResponseLogMiddleware(logger, tc.config))
+			app.Post("/auth/login", func(c fiber.Ctx) error {
+				return c.JSON(tc.response)
+			})
+
+			resp, _, _, err := doRequest(app, http.MethodPost, "/auth/login", []byte(tc.body), nil)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			assert.Equal(t, "http_request", entry["msg"])
+			assert.NotContains(t, logs.String(), "hunter2")
+			tc.assertion(t, entry)
+		})
+	}
+}
+
+func TestHTTPRequestResponseLogMiddleware_Sampling_TableDriven(t *testing.T) {
+	const requests = 8
+
+	tests := []struct {
+		name          string
+		cfg           RequestResponseLogConfig
+		handler       fiber.Handler
+		expectedLines int
+		expectSampled bool
+	}{
+		{
+			name: "fast 200s are sampled at the configured rate",
+			cfg:  RequestResponseLogConfig{SampleRate: 0.25},
+			handler: func(c fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			},
+			expectedLines: 2,
+			expectSampled: true,
+		},
+		{
+			name: "500 responses are always logged",
+			cfg:  RequestResponseLogConfig{SampleRate: 0.25},
+			handler: func(c fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			},
+			expectedLines: requests,
+		},
+		{
+			name: "handler errors are always logged",
+			cfg:  RequestResponseLogConfig{SampleRate: 0.25},
+			handler: func(c fiber.Ctx) error {
+				return errors.New("boom")
+			},
+			expectedLines: requests,
+		},
+		{
+			name: "slow 200s are always logged",
+			cfg:  RequestResponseLogConfig{SampleRate: 0.25, SlowThreshold: time.Millisecond},
+			handler: func(c fiber.Ctx) error {
+				time.Sleep(2 * time.Millisecond)
+				return c.SendStatus(fiber.StatusOK)
+			},
+			expectedLines: requests,
+		},
+		{
+			name: "zero rate disables sampling",
+			handler: func(c fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			},
+			expectedLines: requests,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			draws := []float64{0.05, 0.3, 0.6, 0.9}
+			next := 0
+			original := logSampleRandom
+			logSampleRandom = func() float64 {
+				draw := draws[next%len(draws)]
+				next++
+				return draw
+			}
+			t.Cleanup(func() { logSampleRandom = original })
+
+			var logs bytes.Buffer
+			app := fiber.New()
+			app.Use(NewHTTPRequestResponseLogMiddleware(slog.New(slog.NewJSONHandler(&logs, nil)), tc.cfg))
+			app.Get("/wallets", tc.handler)
+
+			for range requests {
+				_, _, _, err := doRequest(app, http.MethodGet, "/wallets", nil, nil)
+				require.NoError(t, err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+			require.Len(t, lines, tc.expectedLines)
+			for _, line := range lines {
+				var entry map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(line), &entry))
+				if tc.expectSampled {
+					assert.Equal(t, 0.25, entry["sample_rate"])
+				} else {
+					assert.NotContains(t, entry, "sample_rate")
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPRequestResponseLogMiddleware_Outcome_TableDriven(t *testing.T) {
+	tests := []struct {
+		name            string
+		limiter         *stubRateLimiter
+		decision        *sharedidempotency.Decision
+		expectedCode    int
+		expectedOutcome string
+	}{
+		{
+			name:         "allowed request has no outcome",
+			limiter:      &stubRateLimiter{result: sharedratelimit.Result{Allowed: true, Limit: 20, Remaining: 19}},
+			decision:     &sharedidempotency.Decision{Type: sharedidempotency.DecisionAcquired},
+			expectedCode: fiber.StatusCreated,
+		},
+		{
+			name:            "limiter rejection is tagged rate_limited",
+			limiter:         &stubRateLimiter{result: sharedratelimit.Result{Allowed: false, Limit: 20, RetryAfter: time.Second}},
+			expectedCode:    fiber.StatusTooManyRequests,
+			expectedOutcome: OutcomeRateLimited,
+		},
+		{
+			name:    "replayed response is tagged idempotency_replayed",
+			limiter: &stubRateLimiter{result: sharedratelimit.Result{Allowed: true, Limit: 20, Remaining: 19}},
+			decision: &sharedidempotency.Decision{
+				Type:        sharedidempotency.DecisionReplay,
+				StatusCode:  fiber.StatusCreated,
+				Body:        []byte(`{"reference_id":"ref-1"}`),
+				ContentType: fiber.MIMEApplicationJSON,
+			},
+			expectedCode:    fiber.StatusCreated,
+			expectedOutcome: OutcomeIdempotencyReplayed,
+		},
+		{
+			name:            "payload conflict is tagged idempotency_conflict",
+			limiter:         &stubRateLimiter{result: sharedratelimit.Result{Allowed: true, Limit: 20, Remaining: 19}},
+			decision:        &sharedidempotency.Decision{Type: sharedidempotency.DecisionConflict},
+			expectedCode:    fiber.StatusConflict,
+			expectedOutcome: OutcomeIdempotencyConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+			store := idempotencymocks.NewStore(t)
+			if tc.decision != nil {
+				store.EXPECT().Acquire(mock.Anything, mock.Anything).Return(*tc.decision, nil).Once()
+			}
+			if tc.decision != nil && tc.decision.Type == sharedidempotency.DecisionAcquired {
+				store.EXPECT().Complete(mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			app := fiber.New()
+			app.Use(NewHTTPRequestResponseLogMiddleware(logger, RequestResponseLogConfig{}))
+			app.Use(func(c fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return c.Next()
+			})
+			app.Use(NewHTTPRateLimitMiddleware(RateLimitConfig{Limiter: tc.limiter}))
+			app.Post("/withdrawals", NewHTTPWithdrawIdempotencyMiddleware(store, IdempotencyOptions{}), func(c fiber.Ctx) error {
+				return c.Status(fiber.StatusCreated).JSON(fiber.Map{"reference_id": "ref-1"})
+			})
+
+			headers := map[string]string{IdempotencyKeyHeader: "idem-1"}
+			resp, _, _, err := doRequest(app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), headers)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			assert.Equal(t, "http_request", entry["msg"])
+			if tc.expectedOutcome == "" {
+				assert.NotContains(t, entry, "outcome")
+				return
+			}
+			assert.Equal(t, tc.expectedOutcome, entry["outcome"])
+		})
+	}
+}
+
+func TestHTTPRequestIDMiddleware_LogsRequestID_TableDriven(t *testing.T) {
+	tests := []struct {
+		name            string
+		headers         map[string]string
+		expectForwarded string
+	}{
+		{
+			name:            "forwarded request id is kept",
+			headers:         map[string]string{RequestIDHeader: "req-123", ChainIDHeader: "polygon"},
+			expectForwarded: "req-123",
+		},
+		{
+			name:    "generated when missing, regardless of chain id",
+			headers: map[string]string{ChainIDHeader: "polygon"},
+		},
+		{
+			name:    "oversized forwarded id is replaced",
+			headers: map[string]string{RequestIDHeader: strings.Repeat("r", 200)},
+		},
+		{
+			name:            "forwarded correlation id is echoed",
+			headers:         map[string]string{CorrelationIDHeader: "corr-123"},
+			expectForwarded: "corr-123",
+		},
+		{
+			name:            "correlation id wins over request id",
+			headers:         map[string]string{CorrelationIDHeader: "corr-123", RequestIDHeader: "req-123"},
+			expectForwarded: "corr-123",
+		},
+		{
+			name:            "invalid correlation id falls back to request id",
+			headers:         map[string]string{CorrelationIDHeader: "corr 123", RequestIDHeader: "req-123"},
+			expectForwarded: "req-123",
+		},
+		{
+			name:    "invalid correlation id is regenerated",
+			headers: map[string]string{CorrelationIDHeader: strings.Repeat("c", 200)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+			app := fiber.New()
+			app.Use(NewHTTPRequestIDMiddleware())
+			app.Use(NewHTTPRequestResponseLogMiddleware(logger, RequestResponseLogConfig{}))
+			app.Get("/withdrawals/:id", func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{
+					"request_id": RequestIDFromContext(c),
+					"context_id": sharedaudit.RequestIDFromContext(c.Context()),
+					"chain_id":   ChainIDFromContext(c),
+				})
+			})
+
+			resp, payload, _, err := doRequest(app, http.MethodGet, "/withdrawals/ref-1", nil, tc.headers)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			requestID := resp.Header.Get(RequestIDHeader)
+			require.NotEmpty(t, requestID)
+			assert.Equal(t, requestID, resp.Header.Get(CorrelationIDHeader))
+			if tc.expectForwarded != "" {
+				assert.Equal(t, tc.expectForwarded, requestID)
+			} else {
+				_, err := uuid.Parse(requestID)
+				assert.NoError(t, err, "generated request id should be a UUID")
+			}
+			assert.Equal(t, requestID, payload["request_id"])
+			assert.Equal(t, requestID, payload["context_id"], "services must see the request id on c.Context()")
+			assert.Equal(t, tc.headers[ChainIDHeader], payload["chain_id"])
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			assert.Equal(t, requestID, entry["request_id"])
+			assert.NotEqual(t, "polygon", entry["request_id"])
+		})
+	}
+}
+
+func TestHTTPRateLimitMiddleware_RetryHeaders_TableDriven(t *testing.T) {
+	tests := []struct {
+		name               string
+		result             sharedratelimit.Result
+		retryBudget        int64
+		expectedCode       int
+		expectedRetryAfter string
+		expectedBudget     string
+	}{
+		{
+			name:               "rate limited withdrawal has no budget left",
+			result:             sharedratelimit.Result{Allowed: false, Limit: 20, Remaining: 0, RetryAfter: 5 * time.Second, ResetAt: time.Now().Add(5 * time.Second)},
+			retryBudget:        3,
+			expectedCode:       fiber.StatusTooManyRequests,
+			expectedRetryAfter: "5",
+			expectedBudget:     "0",
+		},
+		{
+			name:           "allowed withdrawal budget capped by configured maximum",
+			result:         sharedratelimit.Result{Allowed: true, Limit: 20, Remaining: 19, ResetAt: time.Now().Add(time.Minute)},
+			retryBudget:    3,
+			expectedCode:   fiber.StatusOK,
+			expectedBudget: "3",
+		},
+		{
+			name:           "allowed withdrawal budget follows remaining when uncapped",
+			result:         sharedratelimit.Result{Allowed: true, Limit: 20, Remaining: 2, ResetAt: time.Now().Add(time.Minute)},
+			expectedCode:   fiber.StatusOK,
+			expectedBudget: "2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(NewHTTPRateLimitMiddleware(RateLimitConfig{
+				Limiter:      &stubRateLimiter{result: tc.result},
+				KeyExtractor: PerUserKeyExtractor("withdraw"),
+				RetryBudget:  tc.retryBudget,
+			}))
+			app.Post("/withdrawals", func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{"ok": true})
+			})
+
+			resp, _, _, err := doRequest(app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), nil)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+			assert.Equal(t, tc.expectedRetryAfter, resp.Header.Get(fiber.HeaderRetryAfter))
+			assert.Equal(t, tc.expectedBudget, resp.Header.Get(RetryBudgetHeader))
+		})
+	}
+}
+
+func TestHTTPRateLimitMiddleware_RetryAfterPrecision_TableDriven(t *testing.T) {
+	tests := []struct {
+		name               string
+		result             sharedratelimit.Result
+		precise            bool
+		expectedRetryAfter string
+		expectedBody       float64
+		expectedResetMs    func(t *testing.T, value int64)
+	}{
+		{
+			name:               "sub-second retry after rounds up to one second",
+			result:             sharedratelimit.Result{Allowed: false, Limit: 20, RetryAfter: 250 * time.Millisecond},
+			expectedRetryAfter: "1",
+			expectedBody:       1,
+			expectedResetMs:    func(t *testing.T, value int64) { assert.Equal(t, int64(250), value) },
+		},
+		{
+			name:               "fractional retry after rounds up instead of down",
+			result:             sharedratelimit.Result{Allowed: false, Limit: 20, RetryAfter: 1500 * time.Millisecond},
+			expectedRetryAfter: "2",
+			expectedBody:       2,
+			expectedResetMs:    func(t *testing.T, value int64) { assert.Equal(t, int64(1500), value) },
+		},
+		{
+			name:               "precise sub-second retry after keeps milliseconds",
+			result:             sharedratelimit.Result{Allowed: false, Limit: 20, RetryAfter: 250 * time.Millisecond},
+			precise:            true,
+			expectedRetryAfter: "0.25",
+			expectedBody:       1,
+			expectedResetMs:    func(t *testing.T, value int64) { assert.Equal(t, int64(250), value) },
+		},
+		{
+			name:               "zero retry after falls back to future reset",
+			result:             sharedratelimit.Result{Allowed: false, Limit: 20, ResetAt: time.Now().Add(3 * time.Second)},
+			expectedRetryAfter: "3",
+			expectedBody:       3,
+			expectedResetMs: func(t *testing.T, value int64) {
+				assert.Greater(t, value, int64(2000))
+				assert.LessOrEqual(t, value, int64(3000))
+			},
+		},
+		{
+			name:               "zero retry after with past reset still waits",
+			result:             sharedratelimit.Result{Allowed: false, Limit: 20, ResetAt: time.Now().Add(-time.Second)},
+			precise:            true,
+			expectedRetryAfter: "0.001",
+			expectedBody:       1,
+			expectedResetMs:    func(t *testing.T, value int64) { assert.Equal(t, int64(1), value) },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(NewHTTPRateLimitMiddleware(RateLimitConfig{
+				Limiter:           &stubRateLimiter{result: tc.result},
+				KeyExtractor:      PerUserKeyExtractor("withdraw"),
+				PreciseRetryAfter: tc.precise,
+			}))
+			app.Post("/withdrawals", func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{"ok": true})
+			})
+
+			resp, payload, _, err := doRequest(app, http.MethodPost, "/withdrawals", []byte(`{"amount_minor":100}`), nil)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+			assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+			assert.Equal(t, tc.expectedRetryAfter, resp.Header.Get(fiber.HeaderRetryAfter))
+			assert.Equal(t, tc.expectedBody, payload["retry_after"])
+
+			resetMs, err := strconv.ParseInt(resp.Header.Get(RateLimitResetMsHeader), 10, 64)
+			require.NoError(t, err)
+			tc.expectedResetMs(t, resetMs)
+		})
+	}
+}
+
+func TestHTTPRateLimitMiddleware_HeaderStyle_TableDriven(t *testing.T) {
+	resetAt := time.Now().Add(30 * time.Second)
+	result := sharedratelimit.Result{Allowed: true, Limit: 20, Remaining: 7, ResetAt: resetAt}
+	legacyReset := strconv.FormatInt(resetAt.Unix(), 10)
+
+	tests := []struct {
+		name           string
+		style          RateLimitHeaderStyle
+		expectLegacy   bool
+		expectStandard bool
+	}{
+		{name: "unset defaults to legacy", expectLegacy: true},
+		{name: "legacy", style: RateLimitHeaderStyleLegacy, expectLegacy: true},
+		{name: "standard", style: RateLimitHeaderStyleStandard, expectStandard: true},
+		{name: "both", style: RateLimitHeaderStyleBoth, expectLegacy: true, expectStandard: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(NewHTTPRateLimitMiddleware(RateLimitConfig{
+				Limiter:     &stubRateLimiter{result: result},
+				HeaderStyle: tc.style,
+			}))
+			app.Get("/limited", func(c fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, _, _, err := doRequest(app, http.MethodGet, "/limited", nil, nil)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			if tc.expectLegacy {
+				assert.Equal(t, "20", resp.Header.Get("X-RateLimit-Limit"))
+				assert.Equal(t, "7", resp.Header.Get("X-RateLimit-Remaining"))
+				assert.Equal(t, legacyReset, resp.Header.Get("X-RateLimit-Reset"))
+			} else {
+				assert.Empty(t, resp.Header.Get("X-RateLimit-Limit"))
+				assert.Empty(t, resp.Header.Get("X-RateLimit-Reset"))
+			}
+
+			if tc.expectStandard {
+				assert.Equal(t, "20", resp.Header.Get("RateLimit-Limit"))
+				assert.Equal(t, "7", resp.Header.Get("RateLimit-Remaining"))
+				resetAfter, err := strconv.Atoi(resp.Header.Get("RateLimit-Reset"))
+				require.NoError(t, err, "RateLimit-Reset must be delta seconds")
+				assert.InDelta(t, 30, resetAfter, 1)
+			} else {
+				assert.Empty(t, resp.Header.Get("RateLimit-Limit"))
+				assert.Empty(t, resp.Header.Get("RateLimit-Reset"))
+			}
+		})
+	}
+}
+
+// memoryConcurrencyLimiter counts in-flight slots per key so tests do not need Redis.
+type memoryConcurrencyLimiter struct {
+	mu       sync.Mutex
+	limit    int64
+	inFlight map[string]int64
+	err      error
+}
+
+func newMemoryConcurrencyLimiter(limit int64) *memoryConcurrencyLimiter {
+	return &memoryConcurrencyLimiter{limit: limit, inFlight: make(map[string]int64)}
+}
+
+func (l *memoryConcurrencyLimiter) Acquire(_ context.Context, key string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return false, l.err
+	}
+	if l.inFlight[key] >= l.limit {
+		return false, nil
+	}
+	l.inFlight[key]++
+	return true, nil
+}
+
+func (l *memoryConcurrencyLimiter) Release(_ context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight[key]--
+	return nil
+}
+
+func (l *memoryConcurrencyLimiter) count(key string) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight[key]
+}
+
+func TestHTTPConcurrencyLimitMiddleware_HitsAndReleasesCap(t *testing.T) {
+	const limit = 2
+
+	limiter := newMemoryConcurrencyLimiter(limit)
+	entered := make(chan struct{}, limit)
+	release := make(chan struct{})
+
+	app := fiber.New()
+	app.Use(NewHTTPConcurrencyLimitMiddleware(ConcurrencyLimitConfig{
+		Limiter:      limiter,
+		KeyExtractor: func(fiber.Ctx) string { return "withdraw:user:user-1" },
+	}))
+	app.Post("/withdrawals", func(c fiber.Ctx) error {
+		if c.Query("hold") == "true" {
+			entered <- struct{}{}
+			<-release
+		}
+		return c.JSON(fiber.Map{"ok": true})
+	})
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, limit)
+	for range limit {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, _, _, err := doRequest(app, http.MethodPost, "/withdrawals?hold=true", nil, nil)
+			if err == nil {
+				statuses <- resp.StatusCode
+			}
+		}()
+	}
+	for range limit {
+		<-entered
+	}
+
+	key := "withdraw:user:user-1"
+	assert.Equal(t, int64(limit), limiter.count(key))
+
+	resp, payload, _, err := doRequest(app, http.MethodPost, "/withdrawals", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "too many concurrent requests", payload["error"])
+	assert.Equal(t, "1", resp.Header.Get(fiber.HeaderRetryAfter))
+	assert.Equal(t, int64(limit), limiter.count(key), "rejected request must not hold a slot")
+
+	close(release)
+	wg.Wait()
+	close(statuses)
+	for status := range statuses {
+		assert.Equal(t, fiber.StatusOK, status)
+	}
+	assert.Zero(t, limiter.count(key))
+
+	resp, _, _, err = doRequest(app, http.MethodPost, "/withdrawals", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Zero(t, limiter.count(key))
+}
+
+func TestHTTPConcurrencyLimitMiddleware_TableDriven(t *testing.T) {
+	tests := []struct {
+		name         string
+		limiterErr   error
+		statusCode   int
+		handler      fiber.Handler
+		expectedCode int
+	}{
+		{
+			name: "releases after handler error",
+			handler: func(c fiber.Ctx) error {
+				return fiber.NewError(fiber.StatusBadGateway, "payout provider down")
+			},
+			expectedCode: fiber.StatusBadGateway,
+		},
+		{
+			name: "releases after panic",
+			handler: func(c fiber.Ctx) error {
+				panic("payout client blew up")
+			},
+			expectedCode: fiber.StatusInternalServerError,
+		},
+		{
+			name:       "custom rejection status",
+			statusCode: fiber.StatusServiceUnavailable,
+			handler: func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{"ok": true})
+			},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:       "limiter failure",
+			limiterErr: errors.New("redis down"),
+			handler: func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{"ok": true})
+			},
+			expectedCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := newMemoryConcurrencyLimiter(1)
+			limiter.err = tc.limiterErr
+
+			app := fiber.New()
+			app.Use(NewHTTPRecoveryMiddleware(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+			app.Use(NewHTTPConcurrencyLimitMiddleware(ConcurrencyLimitConfig{
+				Limiter:      limiter,
+				KeyExtractor: func(fiber.Ctx) string { return "withdraw:user:user-1" },
+				StatusCode:   tc.statusCode,
+			}))
+			app.Post("/withdrawals", tc.handler)
+
+			resp, _, _, err := doRequest(app, http.MethodPost, "/withdrawals", nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+			assert.Zero(t, limiter.count("withdraw:user:user-1"))
+
+			if tc.statusCode != 0 {
+				limiter.inFlight["withdraw:user:user-1"] = 1
+				resp, _, _, err = doRequest(app, http.MethodPost, "/withdrawals", nil, nil)
+				require.NoError(t, err)
+				assert.Equal(t, tc.statusCode, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHTTPTracingMiddleware_TableDriven(t *testing.T) {
+	const (
+		remoteTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		remoteSpanID  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name           string
+		path           string
+		traceparent    string
+		expectedCode   int
+		expectedStatus codes.Code
+		expectRemote   bool
+	}{
+		{
+			name:           "starts root server span",
+			path:           "/items/42",
+			expectedCode:   fiber.StatusOK,
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:           "continues incoming traceparent",
+			path:           "/items/42",
+			traceparent:    "00-" + remoteTraceID + "-" + remoteSpanID + "-01",
+			expectedCode:   fiber.StatusOK,
+			expectedStatus: codes.Unset,
+			expectRemote:   true,
+		},
+		{
+			name:           "server error marks span as error",
+			path:           "/fail",
+			expectedCode:   fiber.StatusInternalServerError,
+			expectedStatus: codes.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			tracer := provider.Tracer("middleware-test")
+
+			app := fiber.New()
+			app.Use(NewHTTPTracingMiddleware(tracer))
+			app.Get("/items/:id", func(c fiber.Ctx) error {
+				_, child := tracer.Start(c.Context(), "handler")
+				child.End()
+				return c.JSON(fiber.Map{"ok": true})
+			})
+			app.Get("/fail", func(c fiber.Ctx) error {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "boom"})
+			})
+
+			headers := map[string]string{}
+			if tc.traceparent != "" {
+				headers["traceparent"] = tc.traceparent
+			}
+
+			resp, _, _, err := doRequest(app, http.MethodGet, tc.path, nil, headers)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+
+			var server sdktrace.ReadOnlySpan
+			for _, span := range recorder.Ended() {
+				if span.SpanKind() == trace.SpanKindServer {
+					server = span
+				}
+			}
+			require.NotNil(t, server)
+			assert.Equal(t, tc.expectedStatus, server.Status().Code)
+
+			attributes := map[attribute.Key]attribute.Value{}
+			for _, kv := range server.Attributes() {
+				attributes[kv.Key] = kv.Value
+			}
+			assert.Equal(t, int64(tc.expectedCode), attributes["http.response.status_code"].AsInt64())
+			assert.NotEmpty(t, attributes["http.route"].AsString())
+
+			if tc.expectRemote {
+				assert.Equal(t, remoteTraceID, server.SpanContext().TraceID().String())
+				assert.Equal(t, remoteSpanID, server.Parent().SpanID().String())
+				assert.True(t, server.Parent().IsRemote())
+			} else {
+				assert.False(t, server.Parent().IsValid())
+			}
+
+			for _, span := range recorder.Ended() {
+				if span.Name() == "handler" {
+					assert.Equal(t, server.SpanContext().SpanID(), span.Parent().SpanID())
+				}
+			}
+		})
+	}
+
+	t.Run("nil tracer is a no-op", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(NewHTTPTracingMiddleware(nil))
+		app.Get("/ok", func(c fiber.Ctx) error {
+			assert.False(t, trace.SpanContextFromContext(c.Context()).IsValid())
+			return c.JSON(fiber.Map{"ok": true})
+		})
+
+		resp, payload, _, err := doRequest(app, http.MethodGet, "/ok", nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, true, payload["ok"])
+	})
+}
+
+func TestHTTPMetricsAccessMiddleware_TableDriven(t *testing.T) {
+	secret := []byte("internal-secret")
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name         string
+		opts         MetricsAccessOptions
+		header       string
+		expectedCode int
+		expectErr    bool
+	}{
+		{
+			name:         "allowlisted address passes",
+			opts:         MetricsAccessOptions{Mode: MetricsAccessModeAllowlist, Allowlist: []string{"0.0.0.0/8"}},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:         "address outside allowlist is forbidden",
+			opts:         MetricsAccessOptions{Mode: MetricsAccessModeAllowlist, Allowlist: []string{"127.0.0.1"}},
+			expectedCode: fiber.StatusForbidden,
+		},
+		{
+			name:         "empty allowlist denies everyone",
+			opts:         MetricsAccessOptions{},
+			expectedCode: fiber.StatusForbidden,
+		},
+		{
+			name:      "invalid allowlist entry",
+			opts:      MetricsAccessOptions{Allowlist: []string{"not-an-ip"}},
+			expectErr: true,
+		},
+		{
+			name:      "unsupported mode",
+			opts:      MetricsAccessOptions{Mode: "open"},
+			expectErr: true,
+		},
+		{
+			name: "valid internal auth signature passes",
+			opts: MetricsAccessOptions{
+				Mode:         MetricsAccessModeInternalAuth,
+				InternalAuth: InternalAuthOptions{Secret: secret, Now: func() time.Time { return now }},
+			},
+			header:       SignInternalAuth(secret, http.MethodGet, "/metrics", now),
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name: "signature for another path is forbidden",
+			opts: MetricsAccessOptions{
+				Mode:         MetricsAccessModeInternalAuth,
+				InternalAuth: InternalAuthOptions{Secret: secret, Now: func() time.Time { return now }},
+			},
+			header:       SignInternalAuth(secret, http.MethodGet, "/other", now),
+			expectedCode: fiber.StatusForbidden,
+		},
+		{
+			name: "expired internal auth signature is forbidden",
+			opts: MetricsAccessOptions{
+				Mode:         MetricsAccessModeInternalAuth,
+				InternalAuth: InternalAuthOptions{Secret: secret, Now: func() time.Time { return now }},
+			},
+			header:       SignInternalAuth(secret, http.MethodGet, "/metrics", now.Add(-10*time.Minute)),
+			expectedCode: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, err := NewHTTPMetricsAccessMiddleware(tc.opts)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			app := fiber.New()
+			app.Get("/metrics", handler, func(c fiber.Ctx) error {
+				return c.SendString("metrics")
+			})
+
+			headers := map[string]string{}
+			if tc.header != "" {
+				headers[InternalAuthHeader] = tc.header
+			}
+
+			resp, payload, _, err := doRequest(app, http.MethodGet, "/metrics", nil, headers)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+			if tc.expectedCode == fiber.StatusForbidden {
+				assert.Equal(t, "forbidden", payload["error"])
+			}
+		})
+	}
+}
+
+func TestHTTPMetricsMiddleware_TableDriven(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		expectedRoute string
+		expectedCode  int
+	}{
+		{
+			name:          "labels by route template instead of raw path",
+			path:          "/items/42",
+			expectedRoute: "/items/:id",
+			expectedCode:  fiber.StatusOK,
+		},
+		{
+			name:          "records error status",
+			path:          "/fail",
+			expectedRoute: "/fail",
+			expectedCode:  fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			handler, err := NewHTTPMetricsMiddleware(registry)
+			require.NoError(t, err)
+
+			app := fiber.New()
+			app.Use(handler)
+			app.Get("/items/:id", func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{"ok": true})
+			})
+			app.Get("/fail", func(c fiber.Ctx) error {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "boom"})
+			})
+
+			resp, _, _, err := doRequest(app, http.MethodGet, tc.path, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+
+			families, err := registry.Gather()
+			require.NoError(t, err)
+
+			byName := map[string]*dto.MetricFamily{}
+			for _, family := range families {
+				byName[family.GetName()] = family
+			}
+
+			expectedLabels := map[string]string{
+				"method": http.MethodGet,
+				"route":  tc.expectedRoute,
+				"status": fmt.Sprintf("%d", tc.expectedCode),
+			}
+
+			requests := byName["http_requests_total"]
+			require.NotNil(t, requests)
+			require.Len(t, requests.GetMetric(), 1)
+			assert.Equal(t, expectedLabels, metricLabels(requests.GetMetric()[0]))
+			assert.Equal(t, float64(1), requests.GetMetric()[0].GetCounter().GetValue())
+
+			duration := byName["http_request_duration_seconds"]
+			require.NotNil(t, duration)
+			require.Len(t, duration.GetMetric(), 1)
+			assert.Equal(t, expectedLabels, metricLabels(duration.GetMetric()[0]))
+			assert.Equal(t, uint64(1), duration.GetMetric()[0].GetHistogram().GetSampleCount())
+
+			inFlight := byName["http_requests_in_flight"]
+			require.NotNil(t, inFlight)
+			assert.Equal(t, float64(0), inFlight.GetMetric()[0].GetGauge().GetValue())
+		})
+	}
+}
+
+func TestHTTPMetricsMiddleware_ReusesRegisteredCollectors(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	_, err := NewHTTPMetricsMiddleware(registry)
+	require.NoError(t, err)
+	_, err = NewHTTPMetricsMiddleware(registry)
+	require.NoError(t, err)
+}
+
+func TestHTTPInFlightMiddleware_TracksAndReleasesRequests(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	tracker, err := NewInFlightTracker(registry)
+	require.NoError(t, err)
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	app := fiber.New()
+	app.Use(NewHTTPInFlightMiddleware(tracker))
+	app.Use(NewHTTPRecoveryMiddleware(nil))
+	app.Get("/hold", func(c fiber.Ctx) error {
+		close(entered)
+		<-release
+		return c.SendString("ok")
+	})
+	app.Get("/panic", func(fiber.Ctx) error {
+		panic("boom")
+	})
+
+	gaugeValue := func() float64 {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "http_server_in_flight_requests" {
+				return family.GetMetric()[0].GetGauge().GetValue()
+			}
+		}
+		t.Fatal("http_server_in_flight_requests not registered")
+		return 0
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _, _ = doRequest(app, http.MethodGet, "/hold", nil, nil)
+	}()
+	<-entered
+
+	assert.Equal(t, int64(1), tracker.Count())
+	assert.Equal(t, float64(1), gaugeValue())
+
+	close(release)
+	<-done
+	assert.Zero(t, tracker.Count())
+
+	resp, _, _, err := doRequest(app, http.MethodGet, "/panic", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	assert.Zero(t, tracker.Count(), "a panicking request must still be released")
+	assert.Zero(t, gaugeValue())
+
+	_, err = NewInFlightTracker(registry)
+	require.Error(t, err)
+}
+
+func metricLabels(metric *dto.Metric) map[string]string {
+	labels := map[string]string{}
+	for _, pair := range metric.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+	return labels
+}
+
+func TestHTTPInternalAuthMiddleware_ClockSkew_TableDriven(t *testing.T) {
+	secret := []byte("internal-secret")
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name         string
+		signedAt     time.Time
+		expectedCode int
+	}{
+		{name: "current timestamp passes", signedAt: now, expectedCode: fiber.StatusOK},
+		{name: "slightly future timestamp within skew passes", signedAt: now.Add(20 * time.Second), expectedCode: fiber.StatusOK},
+		{name: "old timestamp within max age plus skew passes", signedAt: now.Add(-time.Minute - 20*time.Second), expectedCode: fiber.StatusOK},
+		{name: "too old timestamp is forbidden", signedAt: now.Add(-time.Minute - 45*time.Second), expectedCode: fiber.StatusForbidden},
+		{name: "too future timestamp is forbidden", signedAt: now.Add(45 * time.Second), expectedCode: fiber.StatusForbidden},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/internal", NewHTTPInternalAuthMiddleware(InternalAuthOptions{
+				Secret:    secret,
+				MaxAge:    time.Minute,
+				ClockSkew: 30 * time.Second,
+				Now:       func() time.Time { return now },
+			}), func(c fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			headers := map[string]string{InternalAuthHeader: SignInternalAuth(secret, http.MethodGet, "/internal", tc.signedAt)}
+			resp, _, _, err := doRequest(app, http.MethodGet, "/internal", nil, headers)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestHTTPCORSMiddleware_TableDriven(t *testing.T) {
+	tests := []struct {
+		name              string
+		cfg               CORSConfig
+		method            string
+		headers           map[string]string
+		expectErr         bool
+		expectedCode      int
+		expectAllowOrigin string
+		expectMaxAge      string
+		expectCredentials string
+	}{
+		{
+			name:              "allowed origin receives cors headers",
+			cfg:               CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+			method:            http.MethodGet,
+			headers:           map[string]string{fiber.HeaderOrigin: "https://app.example.com"},
+			expectedCode:      fiber.StatusOK,
+			expectAllowOrigin: "https://app.example.com",
+		},
+		{
+			name:              "disallowed origin receives no cors headers",
+			cfg:               CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+			method:            http.MethodGet,
+			headers:           map[string]string{fiber.HeaderOrigin: "https://evil.example.com"},
+			expectedCode:      fiber.StatusOK,
+			expectAllowOrigin: "",
+		},
+		{
+			name: "preflight request is answered with max age and credentials",
+			cfg: CORSConfig{
+				AllowedOrigins:   []string{"https://app.example.com"},
+				AllowedMethods:   []string{fiber.MethodPost},
+				AllowCredentials: true,
+				MaxAge:           10 * time.Minute,
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				fiber.HeaderOrigin:                     "https://app.example.com",
+				fiber.HeaderAccessControlRequestMethod: fiber.MethodPost,
+			},
+			expectedCode:      fiber.StatusNoContent,
+			expectAllowOrigin: "https://app.example.com",
+			expectMaxAge:      "600",
+			expectCredentials: "true",
+		},
+		{
+			name: "skipped request receives no cors headers",
+			cfg: CORSConfig{
+				AllowedOrigins: []string{"https://app.example.com"},
+				MaxAge:         10 * time.Minute,
+				Next:           func(fiber.Ctx) bool { return true },
+			},
+			method:       http.MethodGet,
+			headers:      map[string]string{fiber.HeaderOrigin: "https://app.example.com"},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:      "wildcard origin with credentials is rejected",
+			cfg:       CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			expectErr: true,
+		},
+		{
+			name:      "credentials without explicit origins is rejected",
+			cfg:       CORSConfig{AllowCredentials: true},
+			expectErr: true,
+		},
+		{
+			name:      "malformed origin is rejected",
+			cfg:       CORSConfig{AllowedOrigins: []string{"app.example.com"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, err := NewHTTPCORSMiddleware(tc.cfg)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			app := fiber.New()
+			app.Use(handler)
+			app.Get("/resource", func(c fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+			app.Post("/resource", func(c fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			resp, _, _, err := doRequest(app, tc.method, "/resource", nil, tc.headers)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+			assert.Equal(t, tc.expectAllowOrigin, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+			assert.Equal(t, tc.expectMaxAge, resp.Header.Get(fiber.HeaderAccessControlMaxAge))
+			assert.Equal(t, tc.expectCredentials, resp.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+		})
+	}
+}
+
+func TestHTTPRequireScopeMiddleware_TableDriven(t *testing.T) {
+	tests := []struct {
+		name         string
+		claims       any
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "scope present",
+			claims:       &sharedjwt.Claims{Subject: "admin-1", Scopes: []string{"wallet:read", "wallet:adjust"}},
+			expectedCode: fiber.StatusOK,
+		},
+		{
+			name:         "scope absent",
+			claims:       &sharedjwt.Claims{Subject: "user-1", Scopes: []string{"wallet:read"}},
+			expectedCode: fiber.StatusForbidden,
+			expectedErr:  "insufficient scope",
+		},
+		{
+			name:         "missing claims",
+			expectedCode: fiber.StatusUnauthorized,
+			expectedErr:  "missing authenticated user",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(c fiber.Ctx) error {
+				if tc.claims != nil {
+					c.Locals("jwt_claims", tc.claims)
+				}
+				return c.Next()
+			})
+			app.Post("/admin/wallets", NewHTTPRequireScopeMiddleware("wallet:adjust"), func(c fiber.Ctx) error {
+				return c.JSON(fiber.Map{"ok": true})
+			})
+
+			resp, payload, _, err := doRequest(app, http.MethodPost, "/admin/wallets", nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, resp.StatusCode)
+			if tc.expectedErr != "" {
+				assert.Equal(t, tc.expectedErr, payload["error"])
+			} else {
+				assert.Equal(t, true, payload["ok"])
+			}
+		})
+	}
+}
+
+func TestContextAccessors_TableDriven(t *testing.T) {
+	claims := &sharedjwt.Claims{Subject: "user-1"}
+
+	tests := []struct {
+		name         string
+		userID       any
+		claims       any
+		expectUserID string
+		expectUserOK bool
+		expectClaims bool
+	}{
+		{name: "both set", userID: "user-1", claims: claims, expectUserID: "user-1", expectUserOK: true, expectClaims: true},
+		{name: "user id set without claims", userID: "user-1", expectUserID: "user-1", expectUserOK: true},
+		{name: "missing locals"},
+		{name: "nil claims pointer", userID: "user-1", claims: (*sharedjwt.Claims)(nil), expectUserID: "user-1", expectUserOK: true},
+		{name: "wrong types", userID: 42, claims: sharedjwt.Claims{Subject: "user-1"}},
+		{name: "blank user id", userID: "  "},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/ctx", func(c fiber.Ctx) error {
+				if tc.userID != nil {
+					c.Locals("user_id", tc.userID)
+				}
+				if tc.claims != nil {
+					c.Locals("jwt_claims", tc.claims)
+				}
+
+				userID, userOK := UserIDFromContext(c)
+				gotClaims, claimsOK := ClaimsFromContext(c)
+				return c.JSON(fiber.Map{
+					"user_id":   userID,
+					"user_ok":   userOK,
+					"claims_ok": claimsOK && gotClaims != nil,
+				})
+			})
+
+			resp, payload, _, err := doRequest(app, http.MethodGet, "/ctx", nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, tc.expectUserID, payload["user_id"])
+			assert.Equal(t, tc.expectUserOK, payload["user_ok"])
+			assert.Equal(t, tc.expectClaims, payload["claims_ok"])
+		})
+	}
+}
+
+func TestHTTPCompressionMiddleware_TableDriven(t *testing.T) {
+	largeBody := `{"items":"` + strings.Repeat("transaction ", 200) + `"}`
+	smallBody := `{"ok":true}`
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+		stream         bool
+		expectEncoding string
+	}{
+		{name: "large json is gzipped", acceptEncoding: "gzip, deflate, br", contentType: fiber.MIMEApplicationJSON, body: largeBody, expectEncoding: "gzip"},
+		{name: "deflate only client gets zlib deflate", acceptEncoding: "deflate", contentType: fiber.MIMEApplicationJSON, body: largeBody, expectEncoding: "deflate"},
+		{name: "higher q value wins", acceptEncoding: "gzip;q=0.5, deflate;q=0.9", contentType: fiber.MIMEApplicationJSON, body: largeBody, expectEncoding: "deflate"},
+		{name: "client without accept-encoding gets identity", contentType: fiber.MIMEApplicationJSON, body: largeBody},
+		{name: "refused encodings are not used", acceptEncoding: "gzip;q=0, deflate;q=0", contentType: fiber.MIMEApplicationJSON, body: largeBody},
+		{name: "response below min size is left alone", acceptEncoding: "gzip", contentType: fiber.MIMEApplicationJSON, body: smallBody},
+		{name: "binary content type is left alone", acceptEncoding: "gzip", contentType: "image/png", body: largeBody},
+		{name: "streamed body is left alone", acceptEncoding: "gzip", contentType: "text/csv; charset=utf-8", body: largeBody, stream: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(NewHTTPCompressionMiddleware(CompressionConfig{MinSize: 256}))
+			app.Get("/transactions", func(c fiber.Ctx) error {
+				c.Set(fiber.HeaderContentType, tc.contentType)
+				if tc.stream {
+					return c.SendStreamWriter(func(w *bufio.Writer) {
+						_, _ = w.WriteString(tc.body)
+					})
+				}
+				return c.SendString(tc.body)
+			})
+
+			headers := map[string]string{}
+			if tc.acceptEncoding != "" {
+				headers[fiber.HeaderAcceptEncoding] = tc.acceptEncoding
+			}
+			resp, _, rawBody, err := doRequest(app, http.MethodGet, "/transactions", nil, headers)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, tc.expectEncoding, resp.Header.Get(fiber.HeaderContentEncoding))
+
+			var reader io.Reader = bytes.NewReader(rawBody)
+			switch tc.expectEncoding {
+			case "gzip":
+				reader, err = gzip.NewReader(reader)
+				require.NoError(t, err)
+			case "deflate":
+				reader, err = zlib.NewReader(reader)
+				require.NoError(t, err)
+			}
+			decoded, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, tc.body, string(decoded))
+
+			if tc.expectEncoding != "" {
+				assert.Less(t, len(rawBody), len(tc.body))
+				assert.Equal(t, strconv.Itoa(len(rawBody)), resp.Header.Get(fiber.HeaderContentLength))
+			}
+			if tc.contentType == fiber.MIMEApplicationJSON {
+				assert.Contains(t, resp.Header.Get(fiber.HeaderVary), fiber.HeaderAcceptEncoding)
 			}
 		})
 	}
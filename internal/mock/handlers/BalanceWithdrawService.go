@@ -5,9 +5,8 @@ package mocks
 import (
 	context "context"
 
-	mock "github.com/stretchr/testify/mock"
-
 	vo "github.com/joshuarp/withdraw-api/internal/domain/vo"
+	mock "github.com/stretchr/testify/mock"
 )
 
 // BalanceWithdrawService is an autogenerated mock type for the BalanceWithdrawService type
@@ -23,9 +22,66 @@ func (_m *BalanceWithdrawService) EXPECT() *BalanceWithdrawService_Expecter {
 	return &BalanceWithdrawService_Expecter{mock: &_m.Mock}
 }
 
-// WithdrawBalance provides a mock function with given fields: ctx, userID, amountMinor, chainID
-func (_m *BalanceWithdrawService) WithdrawBalance(ctx context.Context, userID string, amountMinor int64, chainID string) (vo.WalletWithdrawal, error) {
-	ret := _m.Called(ctx, userID, amountMinor, chainID)
+// WalletCurrency provides a mock function with given fields: ctx, userID
+func (_m *BalanceWithdrawService) WalletCurrency(ctx context.Context, userID string) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WalletCurrency")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceWithdrawService_WalletCurrency_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WalletCurrency'
+type BalanceWithdrawService_WalletCurrency_Call struct {
+	*mock.Call
+}
+
+// WalletCurrency is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *BalanceWithdrawService_Expecter) WalletCurrency(ctx interface{}, userID interface{}) *BalanceWithdrawService_WalletCurrency_Call {
+	return &BalanceWithdrawService_WalletCurrency_Call{Call: _e.mock.On("WalletCurrency", ctx, userID)}
+}
+
+func (_c *BalanceWithdrawService_WalletCurrency_Call) Run(run func(ctx context.Context, userID string)) *BalanceWithdrawService_WalletCurrency_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *BalanceWithdrawService_WalletCurrency_Call) Return(_a0 string, _a1 error) *BalanceWithdrawService_WalletCurrency_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceWithdrawService_WalletCurrency_Call) RunAndReturn(run func(context.Context, string) (string, error)) *BalanceWithdrawService_WalletCurrency_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithdrawBalance provides a mock function with given fields: ctx, userID, amountMinor, chainID, currency
+func (_m *BalanceWithdrawService) WithdrawBalance(ctx context.Context, userID string, amountMinor int64, chainID string, currency string) (vo.WalletWithdrawal, error) {
+	ret := _m.Called(ctx, userID, amountMinor, chainID, currency)
 
 	if len(ret) == 0 {
 		panic("no return value specified for WithdrawBalance")
@@ -33,17 +89,17 @@ func (_m *BalanceWithdrawService) WithdrawBalance(ctx context.Context, userID st
 
 	var r0 vo.WalletWithdrawal
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) (vo.WalletWithdrawal, error)); ok {
-		return rf(ctx, userID, amountMinor, chainID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string) (vo.WalletWithdrawal, error)); ok {
+		return rf(ctx, userID, amountMinor, chainID, currency)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) vo.WalletWithdrawal); ok {
-		r0 = rf(ctx, userID, amountMinor, chainID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string) vo.WalletWithdrawal); ok {
+		r0 = rf(ctx, userID, amountMinor, chainID, currency)
 	} else {
 		r0 = ret.Get(0).(vo.WalletWithdrawal)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string) error); ok {
-		r1 = rf(ctx, userID, amountMinor, chainID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, string) error); ok {
+		r1 = rf(ctx, userID, amountMinor, chainID, currency)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,13 +117,14 @@ type BalanceWithdrawService_WithdrawBalance_Call struct {
 //   - userID string
 //   - amountMinor int64
 //   - chainID string
-func (_e *BalanceWithdrawService_Expecter) WithdrawBalance(ctx interface{}, userID interface{}, amountMinor interface{}, chainID interface{}) *BalanceWithdrawService_WithdrawBalance_Call {
-	return &BalanceWithdrawService_WithdrawBalance_Call{Call: _e.mock.On("WithdrawBalance", ctx, userID, amountMinor, chainID)}
+//   - currency string
+func (_e *BalanceWithdrawService_Expecter) WithdrawBalance(ctx interface{}, userID interface{}, amountMinor interface{}, chainID interface{}, currency interface{}) *BalanceWithdrawService_WithdrawBalance_Call {
+	return &BalanceWithdrawService_WithdrawBalance_Call{Call: _e.mock.On("WithdrawBalance", ctx, userID, amountMinor, chainID, currency)}
 }
 
-func (_c *BalanceWithdrawService_WithdrawBalance_Call) Run(run func(ctx context.Context, userID string, amountMinor int64, chainID string)) *BalanceWithdrawService_WithdrawBalance_Call {
+func (_c *BalanceWithdrawService_WithdrawBalance_Call) Run(run func(ctx context.Context, userID string, amountMinor int64, chainID string, currency string)) *BalanceWithdrawService_WithdrawBalance_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(string), args[4].(string))
 	})
 	return _c
 }
@@ -77,7 +134,7 @@ func (_c *BalanceWithdrawService_WithdrawBalance_Call) Return(_a0 vo.WalletWithd
 	return _c
 }
 
-func (_c *BalanceWithdrawService_WithdrawBalance_Call) RunAndReturn(run func(context.Context, string, int64, string) (vo.WalletWithdrawal, error)) *BalanceWithdrawService_WithdrawBalance_Call {
+func (_c *BalanceWithdrawService_WithdrawBalance_Call) RunAndReturn(run func(context.Context, string, int64, string, string) (vo.WalletWithdrawal, error)) *BalanceWithdrawService_WithdrawBalance_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	domain "github.com/joshuarp/withdraw-api/internal/domain"
 	mock "github.com/stretchr/testify/mock"
@@ -22,9 +23,292 @@ func (_m *BalanceWithdrawRepository) EXPECT() *BalanceWithdrawRepository_Expecte
 	return &BalanceWithdrawRepository_Expecter{mock: &_m.Mock}
 }
 
-// WithdrawWalletBalanceByUserID provides a mock function with given fields: ctx, userID, amountMinor, chainID
-func (_m *BalanceWithdrawRepository) WithdrawWalletBalanceByUserID(ctx context.Context, userID string, amountMinor int64, chainID string) (domain.WalletBalance, error) {
-	ret := _m.Called(ctx, userID, amountMinor, chainID)
+// CompleteWithdrawalByReferenceID provides a mock function with given fields: ctx, referenceID
+func (_m *BalanceWithdrawRepository) CompleteWithdrawalByReferenceID(ctx context.Context, referenceID string) error {
+	ret := _m.Called(ctx, referenceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteWithdrawalByReferenceID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, referenceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteWithdrawalByReferenceID'
+type BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call struct {
+	*mock.Call
+}
+
+// CompleteWithdrawalByReferenceID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - referenceID string
+func (_e *BalanceWithdrawRepository_Expecter) CompleteWithdrawalByReferenceID(ctx interface{}, referenceID interface{}) *BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call {
+	return &BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call{Call: _e.mock.On("CompleteWithdrawalByReferenceID", ctx, referenceID)}
+}
+
+func (_c *BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call) Run(run func(ctx context.Context, referenceID string)) *BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call) Return(_a0 error) *BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call) RunAndReturn(run func(context.Context, string) error) *BalanceWithdrawRepository_CompleteWithdrawalByReferenceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWalletBalanceByUserID provides a mock function with given fields: ctx, userID
+func (_m *BalanceWithdrawRepository) GetWalletBalanceByUserID(ctx context.Context, userID string) (domain.WalletBalance, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWalletBalanceByUserID")
+	}
+
+	var r0 domain.WalletBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.WalletBalance, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.WalletBalance); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(domain.WalletBalance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceWithdrawRepository_GetWalletBalanceByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWalletBalanceByUserID'
+type BalanceWithdrawRepository_GetWalletBalanceByUserID_Call struct {
+	*mock.Call
+}
+
+// GetWalletBalanceByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *BalanceWithdrawRepository_Expecter) GetWalletBalanceByUserID(ctx interface{}, userID interface{}) *BalanceWithdrawRepository_GetWalletBalanceByUserID_Call {
+	return &BalanceWithdrawRepository_GetWalletBalanceByUserID_Call{Call: _e.mock.On("GetWalletBalanceByUserID", ctx, userID)}
+}
+
+func (_c *BalanceWithdrawRepository_GetWalletBalanceByUserID_Call) Run(run func(ctx context.Context, userID string)) *BalanceWithdrawRepository_GetWalletBalanceByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_GetWalletBalanceByUserID_Call) Return(_a0 domain.WalletBalance, _a1 error) *BalanceWithdrawRepository_GetWalletBalanceByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_GetWalletBalanceByUserID_Call) RunAndReturn(run func(context.Context, string) (domain.WalletBalance, error)) *BalanceWithdrawRepository_GetWalletBalanceByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalByReferenceID provides a mock function with given fields: ctx, userID, referenceID
+func (_m *BalanceWithdrawRepository) GetWithdrawalByReferenceID(ctx context.Context, userID string, referenceID string) (domain.WithdrawalRecord, error) {
+	ret := _m.Called(ctx, userID, referenceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalByReferenceID")
+	}
+
+	var r0 domain.WithdrawalRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.WithdrawalRecord, error)); ok {
+		return rf(ctx, userID, referenceID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.WithdrawalRecord); ok {
+		r0 = rf(ctx, userID, referenceID)
+	} else {
+		r0 = ret.Get(0).(domain.WithdrawalRecord)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, referenceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalByReferenceID'
+type BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalByReferenceID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - referenceID string
+func (_e *BalanceWithdrawRepository_Expecter) GetWithdrawalByReferenceID(ctx interface{}, userID interface{}, referenceID interface{}) *BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call {
+	return &BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call{Call: _e.mock.On("GetWithdrawalByReferenceID", ctx, userID, referenceID)}
+}
+
+func (_c *BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call) Run(run func(ctx context.Context, userID string, referenceID string)) *BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call) Return(_a0 domain.WithdrawalRecord, _a1 error) *BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call) RunAndReturn(run func(context.Context, string, string) (domain.WithdrawalRecord, error)) *BalanceWithdrawRepository_GetWithdrawalByReferenceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPendingWithdrawals provides a mock function with given fields: ctx, createdBefore, limit
+func (_m *BalanceWithdrawRepository) ListPendingWithdrawals(ctx context.Context, createdBefore time.Time, limit int) ([]domain.PendingWithdrawal, error) {
+	ret := _m.Called(ctx, createdBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPendingWithdrawals")
+	}
+
+	var r0 []domain.PendingWithdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]domain.PendingWithdrawal, error)); ok {
+		return rf(ctx, createdBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []domain.PendingWithdrawal); ok {
+		r0 = rf(ctx, createdBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PendingWithdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, createdBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceWithdrawRepository_ListPendingWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPendingWithdrawals'
+type BalanceWithdrawRepository_ListPendingWithdrawals_Call struct {
+	*mock.Call
+}
+
+// ListPendingWithdrawals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - createdBefore time.Time
+//   - limit int
+func (_e *BalanceWithdrawRepository_Expecter) ListPendingWithdrawals(ctx interface{}, createdBefore interface{}, limit interface{}) *BalanceWithdrawRepository_ListPendingWithdrawals_Call {
+	return &BalanceWithdrawRepository_ListPendingWithdrawals_Call{Call: _e.mock.On("ListPendingWithdrawals", ctx, createdBefore, limit)}
+}
+
+func (_c *BalanceWithdrawRepository_ListPendingWithdrawals_Call) Run(run func(ctx context.Context, createdBefore time.Time, limit int)) *BalanceWithdrawRepository_ListPendingWithdrawals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_ListPendingWithdrawals_Call) Return(_a0 []domain.PendingWithdrawal, _a1 error) *BalanceWithdrawRepository_ListPendingWithdrawals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_ListPendingWithdrawals_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]domain.PendingWithdrawal, error)) *BalanceWithdrawRepository_ListPendingWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReverseWithdrawalByUserID provides a mock function with given fields: ctx, userID, amountMinor, chainID, referenceID, feeMinor
+func (_m *BalanceWithdrawRepository) ReverseWithdrawalByUserID(ctx context.Context, userID string, amountMinor int64, chainID string, referenceID string, feeMinor int64) (domain.WalletBalance, error) {
+	ret := _m.Called(ctx, userID, amountMinor, chainID, referenceID, feeMinor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReverseWithdrawalByUserID")
+	}
+
+	var r0 domain.WalletBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string, int64) (domain.WalletBalance, error)); ok {
+		return rf(ctx, userID, amountMinor, chainID, referenceID, feeMinor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string, int64) domain.WalletBalance); ok {
+		r0 = rf(ctx, userID, amountMinor, chainID, referenceID, feeMinor)
+	} else {
+		r0 = ret.Get(0).(domain.WalletBalance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, string, int64) error); ok {
+		r1 = rf(ctx, userID, amountMinor, chainID, referenceID, feeMinor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReverseWithdrawalByUserID'
+type BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call struct {
+	*mock.Call
+}
+
+// ReverseWithdrawalByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - amountMinor int64
+//   - chainID string
+//   - referenceID string
+//   - feeMinor int64
+func (_e *BalanceWithdrawRepository_Expecter) ReverseWithdrawalByUserID(ctx interface{}, userID interface{}, amountMinor interface{}, chainID interface{}, referenceID interface{}, feeMinor interface{}) *BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call {
+	return &BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call{Call: _e.mock.On("ReverseWithdrawalByUserID", ctx, userID, amountMinor, chainID, referenceID, feeMinor)}
+}
+
+func (_c *BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call) Run(run func(ctx context.Context, userID string, amountMinor int64, chainID string, referenceID string, feeMinor int64)) *BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(string), args[4].(string), args[5].(int64))
+	})
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call) Return(_a0 domain.WalletBalance, _a1 error) *BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call) RunAndReturn(run func(context.Context, string, int64, string, string, int64) (domain.WalletBalance, error)) *BalanceWithdrawRepository_ReverseWithdrawalByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithdrawWalletBalanceByUserID provides a mock function with given fields: ctx, userID, amountMinor, chainID, referenceID, feeMinor, dailyLimit
+func (_m *BalanceWithdrawRepository) WithdrawWalletBalanceByUserID(ctx context.Context, userID string, amountMinor int64, chainID string, referenceID string, feeMinor int64, dailyLimit domain.DailyWithdrawLimit) (domain.WalletBalance, error) {
+	ret := _m.Called(ctx, userID, amountMinor, chainID, referenceID, feeMinor, dailyLimit)
 
 	if len(ret) == 0 {
 		panic("no return value specified for WithdrawWalletBalanceByUserID")
@@ -32,17 +316,17 @@ func (_m *BalanceWithdrawRepository) WithdrawWalletBalanceByUserID(ctx context.C
 
 	var r0 domain.WalletBalance
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) (domain.WalletBalance, error)); ok {
-		return rf(ctx, userID, amountMinor, chainID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string, int64, domain.DailyWithdrawLimit) (domain.WalletBalance, error)); ok {
+		return rf(ctx, userID, amountMinor, chainID, referenceID, feeMinor, dailyLimit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) domain.WalletBalance); ok {
-		r0 = rf(ctx, userID, amountMinor, chainID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, string, int64, domain.DailyWithdrawLimit) domain.WalletBalance); ok {
+		r0 = rf(ctx, userID, amountMinor, chainID, referenceID, feeMinor, dailyLimit)
 	} else {
 		r0 = ret.Get(0).(domain.WalletBalance)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string) error); ok {
-		r1 = rf(ctx, userID, amountMinor, chainID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, string, int64, domain.DailyWithdrawLimit) error); ok {
+		r1 = rf(ctx, userID, amountMinor, chainID, referenceID, feeMinor, dailyLimit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -60,13 +344,16 @@ type BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call struct {
 //   - userID string
 //   - amountMinor int64
 //   - chainID string
-func (_e *BalanceWithdrawRepository_Expecter) WithdrawWalletBalanceByUserID(ctx interface{}, userID interface{}, amountMinor interface{}, chainID interface{}) *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call {
-	return &BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call{Call: _e.mock.On("WithdrawWalletBalanceByUserID", ctx, userID, amountMinor, chainID)}
+//   - referenceID string
+//   - feeMinor int64
+//   - dailyLimit domain.DailyWithdrawLimit
+func (_e *BalanceWithdrawRepository_Expecter) WithdrawWalletBalanceByUserID(ctx interface{}, userID interface{}, amountMinor interface{}, chainID interface{}, referenceID interface{}, feeMinor interface{}, dailyLimit interface{}) *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call {
+	return &BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call{Call: _e.mock.On("WithdrawWalletBalanceByUserID", ctx, userID, amountMinor, chainID, referenceID, feeMinor, dailyLimit)}
 }
 
-func (_c *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call) Run(run func(ctx context.Context, userID string, amountMinor int64, chainID string)) *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call {
+func (_c *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call) Run(run func(ctx context.Context, userID string, amountMinor int64, chainID string, referenceID string, feeMinor int64, dailyLimit domain.DailyWithdrawLimit)) *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(string), args[4].(string), args[5].(int64), args[6].(domain.DailyWithdrawLimit))
 	})
 	return _c
 }
@@ -76,7 +363,7 @@ func (_c *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call) Return(_
 	return _c
 }
 
-func (_c *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call) RunAndReturn(run func(context.Context, string, int64, string) (domain.WalletBalance, error)) *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call {
+func (_c *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call) RunAndReturn(run func(context.Context, string, int64, string, string, int64, domain.DailyWithdrawLimit) (domain.WalletBalance, error)) *BalanceWithdrawRepository_WithdrawWalletBalanceByUserID_Call {
 	_c.Call.Return(run)
 	return _c
 }
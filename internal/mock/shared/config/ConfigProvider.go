@@ -252,6 +252,54 @@ func (_c *ConfigProvider_GetInt_Call) RunAndReturn(run func(string) int) *Config
 	return _c
 }
 
+// GetIntSlice provides a mock function with given fields: key
+func (_m *ConfigProvider) GetIntSlice(key string) []int {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIntSlice")
+	}
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(string) []int); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	return r0
+}
+
+// ConfigProvider_GetIntSlice_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIntSlice'
+type ConfigProvider_GetIntSlice_Call struct {
+	*mock.Call
+}
+
+// GetIntSlice is a helper method to define mock.On call
+//   - key string
+func (_e *ConfigProvider_Expecter) GetIntSlice(key interface{}) *ConfigProvider_GetIntSlice_Call {
+	return &ConfigProvider_GetIntSlice_Call{Call: _e.mock.On("GetIntSlice", key)}
+}
+
+func (_c *ConfigProvider_GetIntSlice_Call) Run(run func(key string)) *ConfigProvider_GetIntSlice_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ConfigProvider_GetIntSlice_Call) Return(_a0 []int) *ConfigProvider_GetIntSlice_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ConfigProvider_GetIntSlice_Call) RunAndReturn(run func(string) []int) *ConfigProvider_GetIntSlice_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetString provides a mock function with given fields: key
 func (_m *ConfigProvider) GetString(key string) string {
 	ret := _m.Called(key)
@@ -346,6 +394,54 @@ func (_c *ConfigProvider_GetStringMap_Call) RunAndReturn(run func(string) map[st
 	return _c
 }
 
+// GetStringMapString provides a mock function with given fields: key
+func (_m *ConfigProvider) GetStringMapString(key string) map[string]string {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStringMapString")
+	}
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(string) map[string]string); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	return r0
+}
+
+// ConfigProvider_GetStringMapString_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStringMapString'
+type ConfigProvider_GetStringMapString_Call struct {
+	*mock.Call
+}
+
+// GetStringMapString is a helper method to define mock.On call
+//   - key string
+func (_e *ConfigProvider_Expecter) GetStringMapString(key interface{}) *ConfigProvider_GetStringMapString_Call {
+	return &ConfigProvider_GetStringMapString_Call{Call: _e.mock.On("GetStringMapString", key)}
+}
+
+func (_c *ConfigProvider_GetStringMapString_Call) Run(run func(key string)) *ConfigProvider_GetStringMapString_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ConfigProvider_GetStringMapString_Call) Return(_a0 map[string]string) *ConfigProvider_GetStringMapString_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ConfigProvider_GetStringMapString_Call) RunAndReturn(run func(string) map[string]string) *ConfigProvider_GetStringMapString_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetStringSlice provides a mock function with given fields: key
 func (_m *ConfigProvider) GetStringSlice(key string) []string {
 	ret := _m.Called(key)
@@ -394,6 +490,52 @@ func (_c *ConfigProvider_GetStringSlice_Call) RunAndReturn(run func(string) []st
 	return _c
 }
 
+// GetTime provides a mock function with given fields: key
+func (_m *ConfigProvider) GetTime(key string) time.Time {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTime")
+	}
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(string) time.Time); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// ConfigProvider_GetTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTime'
+type ConfigProvider_GetTime_Call struct {
+	*mock.Call
+}
+
+// GetTime is a helper method to define mock.On call
+//   - key string
+func (_e *ConfigProvider_Expecter) GetTime(key interface{}) *ConfigProvider_GetTime_Call {
+	return &ConfigProvider_GetTime_Call{Call: _e.mock.On("GetTime", key)}
+}
+
+func (_c *ConfigProvider_GetTime_Call) Run(run func(key string)) *ConfigProvider_GetTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ConfigProvider_GetTime_Call) Return(_a0 time.Time) *ConfigProvider_GetTime_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ConfigProvider_GetTime_Call) RunAndReturn(run func(string) time.Time) *ConfigProvider_GetTime_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsSet provides a mock function with given fields: key
 func (_m *ConfigProvider) IsSet(key string) bool {
 	ret := _m.Called(key)
@@ -127,6 +127,53 @@ func (_c *Store_Complete_Call) RunAndReturn(run func(context.Context, idempotenc
 	return _c
 }
 
+// Release provides a mock function with given fields: ctx, request
+func (_m *Store) Release(ctx context.Context, request idempotency.Request) error {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, idempotency.Request) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type Store_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request idempotency.Request
+func (_e *Store_Expecter) Release(ctx interface{}, request interface{}) *Store_Release_Call {
+	return &Store_Release_Call{Call: _e.mock.On("Release", ctx, request)}
+}
+
+func (_c *Store_Release_Call) Run(run func(ctx context.Context, request idempotency.Request)) *Store_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(idempotency.Request))
+	})
+	return _c
+}
+
+func (_c *Store_Release_Call) Return(_a0 error) *Store_Release_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Store_Release_Call) RunAndReturn(run func(context.Context, idempotency.Request) error) *Store_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewStore(t interface {
@@ -24,6 +24,291 @@ func (_m *Querier) EXPECT() *Querier_Expecter {
 	return &Querier_Expecter{mock: &_m.Mock}
 }
 
+// AdjustWalletBalanceByUserID provides a mock function with given fields: ctx, arg
+func (_m *Querier) AdjustWalletBalanceByUserID(ctx context.Context, arg sqlc.AdjustWalletBalanceByUserIDParams) (sqlc.AdjustWalletBalanceByUserIDRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustWalletBalanceByUserID")
+	}
+
+	var r0 sqlc.AdjustWalletBalanceByUserIDRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.AdjustWalletBalanceByUserIDParams) (sqlc.AdjustWalletBalanceByUserIDRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.AdjustWalletBalanceByUserIDParams) sqlc.AdjustWalletBalanceByUserIDRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.AdjustWalletBalanceByUserIDRow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.AdjustWalletBalanceByUserIDParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_AdjustWalletBalanceByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdjustWalletBalanceByUserID'
+type Querier_AdjustWalletBalanceByUserID_Call struct {
+	*mock.Call
+}
+
+// AdjustWalletBalanceByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg sqlc.AdjustWalletBalanceByUserIDParams
+func (_e *Querier_Expecter) AdjustWalletBalanceByUserID(ctx interface{}, arg interface{}) *Querier_AdjustWalletBalanceByUserID_Call {
+	return &Querier_AdjustWalletBalanceByUserID_Call{Call: _e.mock.On("AdjustWalletBalanceByUserID", ctx, arg)}
+}
+
+func (_c *Querier_AdjustWalletBalanceByUserID_Call) Run(run func(ctx context.Context, arg sqlc.AdjustWalletBalanceByUserIDParams)) *Querier_AdjustWalletBalanceByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sqlc.AdjustWalletBalanceByUserIDParams))
+	})
+	return _c
+}
+
+func (_c *Querier_AdjustWalletBalanceByUserID_Call) Return(_a0 sqlc.AdjustWalletBalanceByUserIDRow, _a1 error) *Querier_AdjustWalletBalanceByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_AdjustWalletBalanceByUserID_Call) RunAndReturn(run func(context.Context, sqlc.AdjustWalletBalanceByUserIDParams) (sqlc.AdjustWalletBalanceByUserIDRow, error)) *Querier_AdjustWalletBalanceByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWallet provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreateWallet(ctx context.Context, arg sqlc.CreateWalletParams) (sqlc.CreateWalletRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWallet")
+	}
+
+	var r0 sqlc.CreateWalletRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.CreateWalletParams) (sqlc.CreateWalletRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.CreateWalletParams) sqlc.CreateWalletRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.CreateWalletRow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.CreateWalletParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_CreateWallet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWallet'
+type Querier_CreateWallet_Call struct {
+	*mock.Call
+}
+
+// CreateWallet is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg sqlc.CreateWalletParams
+func (_e *Querier_Expecter) CreateWallet(ctx interface{}, arg interface{}) *Querier_CreateWallet_Call {
+	return &Querier_CreateWallet_Call{Call: _e.mock.On("CreateWallet", ctx, arg)}
+}
+
+func (_c *Querier_CreateWallet_Call) Run(run func(ctx context.Context, arg sqlc.CreateWalletParams)) *Querier_CreateWallet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sqlc.CreateWalletParams))
+	})
+	return _c
+}
+
+func (_c *Querier_CreateWallet_Call) Return(_a0 sqlc.CreateWalletRow, _a1 error) *Querier_CreateWallet_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_CreateWallet_Call) RunAndReturn(run func(context.Context, sqlc.CreateWalletParams) (sqlc.CreateWalletRow, error)) *Querier_CreateWallet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreditWalletBalanceByID provides a mock function with given fields: ctx, arg
+func (_m *Querier) CreditWalletBalanceByID(ctx context.Context, arg sqlc.CreditWalletBalanceByIDParams) (sqlc.CreditWalletBalanceByIDRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreditWalletBalanceByID")
+	}
+
+	var r0 sqlc.CreditWalletBalanceByIDRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.CreditWalletBalanceByIDParams) (sqlc.CreditWalletBalanceByIDRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.CreditWalletBalanceByIDParams) sqlc.CreditWalletBalanceByIDRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.CreditWalletBalanceByIDRow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.CreditWalletBalanceByIDParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_CreditWalletBalanceByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreditWalletBalanceByID'
+type Querier_CreditWalletBalanceByID_Call struct {
+	*mock.Call
+}
+
+// CreditWalletBalanceByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg sqlc.CreditWalletBalanceByIDParams
+func (_e *Querier_Expecter) CreditWalletBalanceByID(ctx interface{}, arg interface{}) *Querier_CreditWalletBalanceByID_Call {
+	return &Querier_CreditWalletBalanceByID_Call{Call: _e.mock.On("CreditWalletBalanceByID", ctx, arg)}
+}
+
+func (_c *Querier_CreditWalletBalanceByID_Call) Run(run func(ctx context.Context, arg sqlc.CreditWalletBalanceByIDParams)) *Querier_CreditWalletBalanceByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sqlc.CreditWalletBalanceByIDParams))
+	})
+	return _c
+}
+
+func (_c *Querier_CreditWalletBalanceByID_Call) Return(_a0 sqlc.CreditWalletBalanceByIDRow, _a1 error) *Querier_CreditWalletBalanceByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_CreditWalletBalanceByID_Call) RunAndReturn(run func(context.Context, sqlc.CreditWalletBalanceByIDParams) (sqlc.CreditWalletBalanceByIDRow, error)) *Querier_CreditWalletBalanceByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DebitWalletBalanceByID provides a mock function with given fields: ctx, arg
+func (_m *Querier) DebitWalletBalanceByID(ctx context.Context, arg sqlc.DebitWalletBalanceByIDParams) (sqlc.DebitWalletBalanceByIDRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DebitWalletBalanceByID")
+	}
+
+	var r0 sqlc.DebitWalletBalanceByIDRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DebitWalletBalanceByIDParams) (sqlc.DebitWalletBalanceByIDRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DebitWalletBalanceByIDParams) sqlc.DebitWalletBalanceByIDRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.DebitWalletBalanceByIDRow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.DebitWalletBalanceByIDParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_DebitWalletBalanceByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DebitWalletBalanceByID'
+type Querier_DebitWalletBalanceByID_Call struct {
+	*mock.Call
+}
+
+// DebitWalletBalanceByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg sqlc.DebitWalletBalanceByIDParams
+func (_e *Querier_Expecter) DebitWalletBalanceByID(ctx interface{}, arg interface{}) *Querier_DebitWalletBalanceByID_Call {
+	return &Querier_DebitWalletBalanceByID_Call{Call: _e.mock.On("DebitWalletBalanceByID", ctx, arg)}
+}
+
+func (_c *Querier_DebitWalletBalanceByID_Call) Run(run func(ctx context.Context, arg sqlc.DebitWalletBalanceByIDParams)) *Querier_DebitWalletBalanceByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sqlc.DebitWalletBalanceByIDParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DebitWalletBalanceByID_Call) Return(_a0 sqlc.DebitWalletBalanceByIDRow, _a1 error) *Querier_DebitWalletBalanceByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_DebitWalletBalanceByID_Call) RunAndReturn(run func(context.Context, sqlc.DebitWalletBalanceByIDParams) (sqlc.DebitWalletBalanceByIDRow, error)) *Querier_DebitWalletBalanceByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DepositWalletBalanceByUserID provides a mock function with given fields: ctx, arg
+func (_m *Querier) DepositWalletBalanceByUserID(ctx context.Context, arg sqlc.DepositWalletBalanceByUserIDParams) (sqlc.DepositWalletBalanceByUserIDRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DepositWalletBalanceByUserID")
+	}
+
+	var r0 sqlc.DepositWalletBalanceByUserIDRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DepositWalletBalanceByUserIDParams) (sqlc.DepositWalletBalanceByUserIDRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DepositWalletBalanceByUserIDParams) sqlc.DepositWalletBalanceByUserIDRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.DepositWalletBalanceByUserIDRow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.DepositWalletBalanceByUserIDParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_DepositWalletBalanceByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DepositWalletBalanceByUserID'
+type Querier_DepositWalletBalanceByUserID_Call struct {
+	*mock.Call
+}
+
+// DepositWalletBalanceByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg sqlc.DepositWalletBalanceByUserIDParams
+func (_e *Querier_Expecter) DepositWalletBalanceByUserID(ctx interface{}, arg interface{}) *Querier_DepositWalletBalanceByUserID_Call {
+	return &Querier_DepositWalletBalanceByUserID_Call{Call: _e.mock.On("DepositWalletBalanceByUserID", ctx, arg)}
+}
+
+func (_c *Querier_DepositWalletBalanceByUserID_Call) Run(run func(ctx context.Context, arg sqlc.DepositWalletBalanceByUserIDParams)) *Querier_DepositWalletBalanceByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sqlc.DepositWalletBalanceByUserIDParams))
+	})
+	return _c
+}
+
+func (_c *Querier_DepositWalletBalanceByUserID_Call) Return(_a0 sqlc.DepositWalletBalanceByUserIDRow, _a1 error) *Querier_DepositWalletBalanceByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_DepositWalletBalanceByUserID_Call) RunAndReturn(run func(context.Context, sqlc.DepositWalletBalanceByUserIDParams) (sqlc.DepositWalletBalanceByUserIDRow, error)) *Querier_DepositWalletBalanceByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetWalletBalanceByUserID provides a mock function with given fields: ctx, userID
 func (_m *Querier) GetWalletBalanceByUserID(ctx context.Context, userID uuid.UUID) (sqlc.GetWalletBalanceByUserIDRow, error) {
 	ret := _m.Called(ctx, userID)
@@ -81,6 +366,179 @@ func (_c *Querier_GetWalletBalanceByUserID_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// GetWalletVersionByUserID provides a mock function with given fields: ctx, userID
+func (_m *Querier) GetWalletVersionByUserID(ctx context.Context, userID uuid.UUID) (sqlc.GetWalletVersionByUserIDRow, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWalletVersionByUserID")
+	}
+
+	var r0 sqlc.GetWalletVersionByUserIDRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (sqlc.GetWalletVersionByUserIDRow, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) sqlc.GetWalletVersionByUserIDRow); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(sqlc.GetWalletVersionByUserIDRow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetWalletVersionByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWalletVersionByUserID'
+type Querier_GetWalletVersionByUserID_Call struct {
+	*mock.Call
+}
+
+// GetWalletVersionByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *Querier_Expecter) GetWalletVersionByUserID(ctx interface{}, userID interface{}) *Querier_GetWalletVersionByUserID_Call {
+	return &Querier_GetWalletVersionByUserID_Call{Call: _e.mock.On("GetWalletVersionByUserID", ctx, userID)}
+}
+
+func (_c *Querier_GetWalletVersionByUserID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *Querier_GetWalletVersionByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *Querier_GetWalletVersionByUserID_Call) Return(_a0 sqlc.GetWalletVersionByUserIDRow, _a1 error) *Querier_GetWalletVersionByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetWalletVersionByUserID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (sqlc.GetWalletVersionByUserIDRow, error)) *Querier_GetWalletVersionByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalLedgerByReferenceID provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetWithdrawalLedgerByReferenceID(ctx context.Context, arg sqlc.GetWithdrawalLedgerByReferenceIDParams) ([]sqlc.GetWithdrawalLedgerByReferenceIDRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalLedgerByReferenceID")
+	}
+
+	var r0 []sqlc.GetWithdrawalLedgerByReferenceIDRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetWithdrawalLedgerByReferenceIDParams) ([]sqlc.GetWithdrawalLedgerByReferenceIDRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetWithdrawalLedgerByReferenceIDParams) []sqlc.GetWithdrawalLedgerByReferenceIDRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.GetWithdrawalLedgerByReferenceIDRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetWithdrawalLedgerByReferenceIDParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_GetWithdrawalLedgerByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalLedgerByReferenceID'
+type Querier_GetWithdrawalLedgerByReferenceID_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalLedgerByReferenceID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg sqlc.GetWithdrawalLedgerByReferenceIDParams
+func (_e *Querier_Expecter) GetWithdrawalLedgerByReferenceID(ctx interface{}, arg interface{}) *Querier_GetWithdrawalLedgerByReferenceID_Call {
+	return &Querier_GetWithdrawalLedgerByReferenceID_Call{Call: _e.mock.On("GetWithdrawalLedgerByReferenceID", ctx, arg)}
+}
+
+func (_c *Querier_GetWithdrawalLedgerByReferenceID_Call) Run(run func(ctx context.Context, arg sqlc.GetWithdrawalLedgerByReferenceIDParams)) *Querier_GetWithdrawalLedgerByReferenceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sqlc.GetWithdrawalLedgerByReferenceIDParams))
+	})
+	return _c
+}
+
+func (_c *Querier_GetWithdrawalLedgerByReferenceID_Call) Return(_a0 []sqlc.GetWithdrawalLedgerByReferenceIDRow, _a1 error) *Querier_GetWithdrawalLedgerByReferenceID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_GetWithdrawalLedgerByReferenceID_Call) RunAndReturn(run func(context.Context, sqlc.GetWithdrawalLedgerByReferenceIDParams) ([]sqlc.GetWithdrawalLedgerByReferenceIDRow, error)) *Querier_GetWithdrawalLedgerByReferenceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HasWalletByIDAndUserID provides a mock function with given fields: ctx, arg
+func (_m *Querier) HasWalletByIDAndUserID(ctx context.Context, arg sqlc.HasWalletByIDAndUserIDParams) (bool, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasWalletByIDAndUserID")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.HasWalletByIDAndUserIDParams) (bool, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.HasWalletByIDAndUserIDParams) bool); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.HasWalletByIDAndUserIDParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_HasWalletByIDAndUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasWalletByIDAndUserID'
+type Querier_HasWalletByIDAndUserID_Call struct {
+	*mock.Call
+}
+
+// HasWalletByIDAndUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg sqlc.HasWalletByIDAndUserIDParams
+func (_e *Querier_Expecter) HasWalletByIDAndUserID(ctx interface{}, arg interface{}) *Querier_HasWalletByIDAndUserID_Call {
+	return &Querier_HasWalletByIDAndUserID_Call{Call: _e.mock.On("HasWalletByIDAndUserID", ctx, arg)}
+}
+
+func (_c *Querier_HasWalletByIDAndUserID_Call) Run(run func(ctx context.Context, arg sqlc.HasWalletByIDAndUserIDParams)) *Querier_HasWalletByIDAndUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sqlc.HasWalletByIDAndUserIDParams))
+	})
+	return _c
+}
+
+func (_c *Querier_HasWalletByIDAndUserID_Call) Return(_a0 bool, _a1 error) *Querier_HasWalletByIDAndUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_HasWalletByIDAndUserID_Call) RunAndReturn(run func(context.Context, sqlc.HasWalletByIDAndUserIDParams) (bool, error)) *Querier_HasWalletByIDAndUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // HasWalletByUserID provides a mock function with given fields: ctx, userID
 func (_m *Querier) HasWalletByUserID(ctx context.Context, userID uuid.UUID) (bool, error) {
 	ret := _m.Called(ctx, userID)
@@ -185,6 +643,120 @@ func (_c *Querier_InsertWalletLedger_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// SetWalletStatusByUserID provides a mock function with given fields: ctx, arg
+func (_m *Querier) SetWalletStatusByUserID(ctx context.Context, arg sqlc.SetWalletStatusByUserIDParams) (sqlc.SetWalletStatusByUserIDRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWalletStatusByUserID")
+	}
+
+	var r0 sqlc.SetWalletStatusByUserIDRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.SetWalletStatusByUserIDParams) (sqlc.SetWalletStatusByUserIDRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.SetWalletStatusByUserIDParams) sqlc.SetWalletStatusByUserIDRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.SetWalletStatusByUserIDRow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.SetWalletStatusByUserIDParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_SetWalletStatusByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetWalletStatusByUserID'
+type Querier_SetWalletStatusByUserID_Call struct {
+	*mock.Call
+}
+
+// SetWalletStatusByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg sqlc.SetWalletStatusByUserIDParams
+func (_e *Querier_Expecter) SetWalletStatusByUserID(ctx interface{}, arg interface{}) *Querier_SetWalletStatusByUserID_Call {
+	return &Querier_SetWalletStatusByUserID_Call{Call: _e.mock.On("SetWalletStatusByUserID", ctx, arg)}
+}
+
+func (_c *Querier_SetWalletStatusByUserID_Call) Run(run func(ctx context.Context, arg sqlc.SetWalletStatusByUserIDParams)) *Querier_SetWalletStatusByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sqlc.SetWalletStatusByUserIDParams))
+	})
+	return _c
+}
+
+func (_c *Querier_SetWalletStatusByUserID_Call) Return(_a0 sqlc.SetWalletStatusByUserIDRow, _a1 error) *Querier_SetWalletStatusByUserID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_SetWalletStatusByUserID_Call) RunAndReturn(run func(context.Context, sqlc.SetWalletStatusByUserIDParams) (sqlc.SetWalletStatusByUserIDRow, error)) *Querier_SetWalletStatusByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SumWithdrawalsByUserSince provides a mock function with given fields: ctx, arg
+func (_m *Querier) SumWithdrawalsByUserSince(ctx context.Context, arg sqlc.SumWithdrawalsByUserSinceParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumWithdrawalsByUserSince")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.SumWithdrawalsByUserSinceParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.SumWithdrawalsByUserSinceParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.SumWithdrawalsByUserSinceParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Querier_SumWithdrawalsByUserSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SumWithdrawalsByUserSince'
+type Querier_SumWithdrawalsByUserSince_Call struct {
+	*mock.Call
+}
+
+// SumWithdrawalsByUserSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - arg sqlc.SumWithdrawalsByUserSinceParams
+func (_e *Querier_Expecter) SumWithdrawalsByUserSince(ctx interface{}, arg interface{}) *Querier_SumWithdrawalsByUserSince_Call {
+	return &Querier_SumWithdrawalsByUserSince_Call{Call: _e.mock.On("SumWithdrawalsByUserSince", ctx, arg)}
+}
+
+func (_c *Querier_SumWithdrawalsByUserSince_Call) Run(run func(ctx context.Context, arg sqlc.SumWithdrawalsByUserSinceParams)) *Querier_SumWithdrawalsByUserSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sqlc.SumWithdrawalsByUserSinceParams))
+	})
+	return _c
+}
+
+func (_c *Querier_SumWithdrawalsByUserSince_Call) Return(_a0 int64, _a1 error) *Querier_SumWithdrawalsByUserSince_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Querier_SumWithdrawalsByUserSince_Call) RunAndReturn(run func(context.Context, sqlc.SumWithdrawalsByUserSinceParams) (int64, error)) *Querier_SumWithdrawalsByUserSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // WithdrawWalletBalanceByUserID provides a mock function with given fields: ctx, arg
 func (_m *Querier) WithdrawWalletBalanceByUserID(ctx context.Context, arg sqlc.WithdrawWalletBalanceByUserIDParams) (sqlc.WithdrawWalletBalanceByUserIDRow, error) {
 	ret := _m.Called(ctx, arg)
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+)
+
+type AuthChangePasswordRepository struct {
+	db           *sqlx.DB
+	queryTimeout QueryTimeout
+}
+
+func NewAuthChangePasswordRepository(db *sqlx.DB, queryTimeout QueryTimeout) *AuthChangePasswordRepository {
+	return &AuthChangePasswordRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// GetPasswordHash returns the stored hash of an active user. Unknown and inactive users
+// are reported as vo.ErrInvalidCredentials, like a failed login.
+func (r *AuthChangePasswordRepository) GetPasswordHash(ctx context.Context, userID string) (_ string, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return "", vo.ErrInvalidCredentials
+	}
+
+	const query = `
+		SELECT password_hash
+		FROM users
+		WHERE id = $1 AND status = 'active'
+	`
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	var passwordHash string
+	if err := r.db.GetContext(ctx, &passwordHash, query, parsedUserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", vo.ErrInvalidCredentials
+		}
+		return "", fmt.Errorf("repository: get password hash failed: %w", err)
+	}
+
+	return passwordHash, nil
+}
+
+// UpdatePasswordHash replaces the user's hash only while it is still currentHash, so two
+// concurrent changes cannot both succeed against the same verified password.
+func (r *AuthChangePasswordRepository) UpdatePasswordHash(ctx context.Context, userID, currentHash, newHash string) (err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return vo.ErrInvalidCredentials
+	}
+
+	const query = `
+		UPDATE users
+		SET password_hash = $3, updated_at = now()
+		WHERE id = $1 AND password_hash = $2 AND status = 'active'
+	`
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	result, err := r.db.ExecContext(ctx, query, parsedUserID, currentHash, newHash)
+	if err != nil {
+		return fmt.Errorf("repository: update password hash failed: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("repository: update password hash failed: %w", err)
+	}
+	if affected == 0 {
+		return vo.ErrInvalidCredentials
+	}
+
+	return nil
+}
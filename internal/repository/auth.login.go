@@ -12,7 +12,8 @@ import (
 )
 
 type AuthLoginRepository struct {
-	db *sqlx.DB
+	db           *sqlx.DB
+	queryTimeout QueryTimeout
 }
 
 type userAuthRow struct {
@@ -20,25 +21,30 @@ type userAuthRow struct {
 	Email        string `db:"email"`
 	PasswordHash string `db:"password_hash"`
 	Status       string `db:"status"`
+	Scopes       string `db:"scopes"`
 }
 
-func NewAuthLoginRepository(db *sqlx.DB) *AuthLoginRepository {
-	return &AuthLoginRepository{db: db}
+func NewAuthLoginRepository(db *sqlx.DB, queryTimeout QueryTimeout) *AuthLoginRepository {
+	return &AuthLoginRepository{db: db, queryTimeout: queryTimeout}
 }
 
-func (r *AuthLoginRepository) GetUserAuthByEmail(ctx context.Context, email string) (domain.UserAuth, error) {
+func (r *AuthLoginRepository) GetUserAuthByEmail(ctx context.Context, email string) (_ domain.UserAuth, err error) {
 	normalizedEmail := strings.TrimSpace(strings.ToLower(email))
 	if normalizedEmail == "" {
 		return domain.UserAuth{}, vo.ErrInvalidCredentials
 	}
 
 	const query = `
-		SELECT id::text AS id, email, password_hash, status
+		SELECT id::text AS id, email, password_hash, status, array_to_string(scopes, ' ') AS scopes
 		FROM users
 		WHERE lower(email) = $1
 		LIMIT 1
 	`
 
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
 	var row userAuthRow
 	if err := r.db.GetContext(ctx, &row, query, normalizedEmail); err != nil {
 		if err == sql.ErrNoRows {
@@ -56,5 +62,6 @@ func (r *AuthLoginRepository) GetUserAuthByEmail(ctx context.Context, email stri
 		Email:        row.Email,
 		PasswordHash: row.PasswordHash,
 		Status:       row.Status,
+		Scopes:       strings.Fields(row.Scopes),
 	}, nil
 }
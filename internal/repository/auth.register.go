@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+)
+
+type AuthRegisterRepository struct {
+	db           *sqlx.DB
+	queryTimeout QueryTimeout
+}
+
+func NewAuthRegisterRepository(db *sqlx.DB, queryTimeout QueryTimeout) *AuthRegisterRepository {
+	return &AuthRegisterRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// CreateUser inserts an active user with the given id, lower-cased email and password
+// hash. The users.email unique constraint surfaces as vo.ErrEmailAlreadyRegistered.
+func (r *AuthRegisterRepository) CreateUser(ctx context.Context, userID, email, passwordHash string) (_ time.Time, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	const query = `
+		INSERT INTO users (id, email, password_hash, status)
+		VALUES ($1, $2, $3, 'active')
+		RETURNING created_at
+	`
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	var createdAt time.Time
+	if err := r.db.GetContext(ctx, &createdAt, query, parsedUserID, email, passwordHash); err != nil {
+		if isUniqueViolation(err) {
+			return time.Time{}, vo.ErrEmailAlreadyRegistered
+		}
+		return time.Time{}, fmt.Errorf("repository: failed to create user: %w", err)
+	}
+
+	return createdAt, nil
+}
+
+// DeleteUser removes a user created by CreateUser. Registration calls it to undo the user
+// when the wallet cannot be opened, since the two live in different databases.
+func (r *AuthRegisterRepository) DeleteUser(ctx context.Context, userID string) (err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, parsedUserID); err != nil {
+		return fmt.Errorf("repository: failed to delete user: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedsqlc "github.com/joshuarp/withdraw-api/internal/shared/sqlc"
+)
+
+type DepositBalanceRepository struct {
+	db           *sqlx.DB
+	queries      *sharedsqlc.Queries
+	queryTimeout QueryTimeout
+}
+
+func NewDepositBalanceRepository(db *sqlx.DB, queryTimeout QueryTimeout) *DepositBalanceRepository {
+	return &DepositBalanceRepository{db: db, queries: sharedsqlc.New(db.DB), queryTimeout: queryTimeout}
+}
+
+func (r *DepositBalanceRepository) DepositWalletBalanceByUserID(ctx context.Context, userID string, amountMinor int64, referenceID string) (_ domain.WalletBalance, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	if amountMinor <= 0 {
+		return domain.WalletBalance{}, vo.ErrInvalidAmount
+	}
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queriesWithTx := r.queries.WithTx(tx.Tx)
+	current, err := queriesWithTx.GetWalletVersionByUserID(ctx, parsedUserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.WalletBalance{}, vo.ErrWalletNotFound
+		}
+
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to read wallet version: %w", err)
+	}
+
+	depositedWallet, err := queriesWithTx.DepositWalletBalanceByUserID(ctx, sharedsqlc.DepositWalletBalanceByUserIDParams{
+		AmountMinor:     amountMinor,
+		UserID:          parsedUserID,
+		ExpectedVersion: current.Version,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.WalletBalance{}, classifyStaleWalletUpdate(ctx, queriesWithTx, parsedUserID, current.Version, vo.ErrConcurrentModification)
+		}
+
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to deposit wallet balance: %w", err)
+	}
+
+	ledgerParams := sharedsqlc.InsertWalletLedgerParams{
+		WalletID:          depositedWallet.WalletID,
+		EntryType:         "deposit",
+		AmountMinor:       amountMinor,
+		BalanceAfterMinor: depositedWallet.BalanceMinor,
+	}
+
+	if referenceID != "" {
+		ledgerParams.ReferenceID = sql.NullString{String: referenceID, Valid: true}
+	}
+
+	if err := queriesWithTx.InsertWalletLedger(ctx, ledgerParams); err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to insert wallet ledger: %w", err)
+	}
+
+	notifyWalletChange(ctx, tx, parsedUserID.String())
+
+	if err := tx.Commit(); err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to commit transaction: %w", err)
+	}
+
+	return domain.WalletBalance{
+		UserID:       depositedWallet.UserID,
+		BalanceMinor: depositedWallet.BalanceMinor,
+		Currency:     depositedWallet.Currency,
+		Version:      depositedWallet.Version,
+		UpdatedAt:    depositedWallet.UpdatedAt,
+	}, nil
+}
@@ -13,20 +13,25 @@ import (
 )
 
 type InquiryCheckBalanceRepository struct {
-	db      *sqlx.DB
-	queries *sharedsqlc.Queries
+	db           *sqlx.DB
+	queries      *sharedsqlc.Queries
+	queryTimeout QueryTimeout
 }
 
-func NewInquiryCheckBalanceRepository(db *sqlx.DB) *InquiryCheckBalanceRepository {
-	return &InquiryCheckBalanceRepository{db: db, queries: sharedsqlc.New(db.DB)}
+func NewInquiryCheckBalanceRepository(db *sqlx.DB, queryTimeout QueryTimeout) *InquiryCheckBalanceRepository {
+	return &InquiryCheckBalanceRepository{db: db, queries: sharedsqlc.New(db.DB), queryTimeout: queryTimeout}
 }
 
-func (r *InquiryCheckBalanceRepository) GetWalletBalanceByUserID(ctx context.Context, userID string) (domain.WalletBalance, error) {
+func (r *InquiryCheckBalanceRepository) GetWalletBalanceByUserID(ctx context.Context, userID string) (_ domain.WalletBalance, err error) {
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
 		return domain.WalletBalance{}, fmt.Errorf("repository: invalid user_id: %w", err)
 	}
 
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
 	balanceRow, err := r.queries.GetWalletBalanceByUserID(ctx, parsedUserID)
 	if err != nil {
 		if err == sql.ErrNoRows {
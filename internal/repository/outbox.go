@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	sharedevents "github.com/joshuarp/withdraw-api/internal/shared/events"
+)
+
+const insertOutboxQuery = `
+INSERT INTO outbox (event_type, transaction_id, payload)
+VALUES ($1, $2, $3)`
+
+// claimOutboxQuery hides the claimed rows from other relays until the lease expires, so
+// several instances can relay concurrently without publishing the same row twice.
+const claimOutboxQuery = `
+UPDATE outbox
+SET available_at = now() + make_interval(secs => $2), attempts = attempts + 1
+WHERE id IN (
+	SELECT id
+	FROM outbox
+	WHERE sent_at IS NULL
+	  AND failed_at IS NULL
+	  AND available_at <= now()
+	ORDER BY id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id, payload`
+
+const markOutboxSentQuery = `UPDATE outbox SET sent_at = now(), last_error = NULL WHERE id = $1`
+
+const markOutboxFailedQuery = `UPDATE outbox SET last_error = $2 WHERE id = $1`
+
+const markOutboxDeadQuery = `UPDATE outbox SET failed_at = now(), last_error = $2 WHERE id = $1`
+
+// insertOutboxEvent stores event in the outbox within tx, so it is relayed if and only
+// if the balance change commits.
+func insertOutboxEvent(ctx context.Context, tx *sqlx.Tx, event sharedevents.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("repository: failed to encode %s outbox event: %w", event.Type, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, insertOutboxQuery, event.Type, event.TransactionID, payload); err != nil {
+		return fmt.Errorf("repository: failed to insert %s outbox event: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// OutboxRepository is the sharedevents.OutboxStore backed by the outbox table.
+type OutboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// ClaimUnsent leases up to limit unsent rows for lease, oldest first.
+func (r *OutboxRepository) ClaimUnsent(ctx context.Context, limit int, lease time.Duration) ([]sharedevents.OutboxMessage, error) {
+	rows, err := r.db.QueryxContext(ctx, claimOutboxQuery, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to claim outbox rows: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]sharedevents.OutboxMessage, 0, limit)
+	for rows.Next() {
+		var message sharedevents.OutboxMessage
+		if err := rows.Scan(&message.ID, &message.Payload); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan outbox row: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: failed to read outbox rows: %w", err)
+	}
+
+	// UPDATE ... RETURNING does not keep the subquery order.
+	slices.SortFunc(messages, func(a, b sharedevents.OutboxMessage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, markOutboxSentQuery, id); err != nil {
+		return fmt.Errorf("repository: failed to mark outbox row %d sent: %w", id, err)
+	}
+
+	return nil
+}
+
+// MarkFailed records why a row was not published. The row is retried once its lease
+// expires.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, reason string) error {
+	if _, err := r.db.ExecContext(ctx, markOutboxFailedQuery, id, reason); err != nil {
+		return fmt.Errorf("repository: failed to mark outbox row %d failed: %w", id, err)
+	}
+
+	return nil
+}
+
+// MarkDead records why a row can never be published and stops it from being claimed
+// again. Dead rows keep failed_at set for manual inspection.
+func (r *OutboxRepository) MarkDead(ctx context.Context, id int64, reason string) error {
+	if _, err := r.db.ExecContext(ctx, markOutboxDeadQuery, id, reason); err != nil {
+		return fmt.Errorf("repository: failed to mark outbox row %d dead: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type QueryTimeout time.Duration
+
+func (t QueryTimeout) withContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, time.Duration(t))
+}
+
+func withQueryDeadline(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	ctxErr := ctx.Err()
+	if ctxErr == nil || errors.Is(err, ctxErr) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ctxErr, err)
+}
@@ -3,19 +3,30 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"regexp"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
+	"github.com/joshuarp/withdraw-api/internal/domain"
 	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedevents "github.com/joshuarp/withdraw-api/internal/shared/events"
+	"github.com/joshuarp/withdraw-api/internal/shared/pagination"
 )
 
 func newSQLXMock(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
@@ -31,6 +42,41 @@ func newSQLXMock(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
 	return sqlx.NewDb(sqlDB, "sqlmock"), mockDB
 }
 
+func expectWalletVersion(mockDB sqlmock.Sqlmock, userUUID, walletUUID uuid.UUID, version int64) {
+	expectWalletVersionWithStatus(mockDB, userUUID, walletUUID, version, vo.WalletStatusActive)
+}
+
+func expectWalletVersionWithStatus(mockDB sqlmock.Sqlmock, userUUID, walletUUID uuid.UUID, version int64, status string) {
+	mockDB.ExpectQuery("SELECT\\s+id AS wallet_id").WithArgs(userUUID).
+		WillReturnRows(sqlmock.NewRows([]string{"wallet_id", "balance_minor", "version", "status"}).AddRow(walletUUID, int64(1000), version, status))
+}
+
+func expectOutboxInsert(mockDB sqlmock.Sqlmock, eventType string) {
+	mockDB.ExpectExec("INSERT INTO outbox").WithArgs(eventType, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func expectWithdrawalPayoutInsert(mockDB sqlmock.Sqlmock) {
+	mockDB.ExpectExec("INSERT INTO withdrawal_payouts").WithArgs("ref-1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+var withdrawalPayoutColumns = []string{"reference_id", "user_id", "amount_minor", "fee_minor", "currency", "chain_id", "updated_at"}
+
+func expectWithdrawalPayoutSettle(mockDB sqlmock.Sqlmock, status string) {
+	mockDB.ExpectQuery("UPDATE withdrawal_payouts").WithArgs("ref-1", status).
+		WillReturnRows(sqlmock.NewRows(withdrawalPayoutColumns).AddRow("ref-1", uuid.NewString(), int64(100), int64(0), "IDR", "chain-1", time.Now().UTC()))
+}
+
+func expectWithdrawalPayoutStatus(mockDB sqlmock.Sqlmock, status string) {
+	mockDB.ExpectQuery("SELECT status FROM withdrawal_payouts").WithArgs("ref-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(status))
+}
+
+func expectWalletNotify(mockDB sqlmock.Sqlmock, userUUID uuid.UUID) {
+	mockDB.ExpectExec("SAVEPOINT wallet_notify").WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectExec("SELECT pg_notify").WithArgs(WalletChangesChannel, userUUID.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
 type AuthLoginRepositorySuite struct{ suite.Suite }
 
 func (s *AuthLoginRepositorySuite) TestGetUserAuthByEmail_TableDriven() {
@@ -81,8 +127,8 @@ func (s *AuthLoginRepositorySuite) TestGetUserAuthByEmail_TableDriven() {
 			name:  "invalid when status not active",
 			email: "user@example.com",
 			setupMock: func(mockDB sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "email", "password_hash", "status"}).
-					AddRow("user-1", "user@example.com", "hashed", "inactive")
+				rows := sqlmock.NewRows([]string{"id", "email", "password_hash", "status", "scopes"}).
+					AddRow("user-1", "user@example.com", "hashed", "inactive", "")
 				mockDB.ExpectQuery(regexp.QuoteMeta("SELECT id::text AS id, email, password_hash, status")).
 					WithArgs("user@example.com").
 					WillReturnRows(rows)
@@ -96,8 +142,8 @@ func (s *AuthLoginRepositorySuite) TestGetUserAuthByEmail_TableDriven() {
 			name:  "success",
 			email: "user@example.com",
 			setupMock: func(mockDB sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "email", "password_hash", "status"}).
-					AddRow("user-1", "user@example.com", "hashed", "active")
+				rows := sqlmock.NewRows([]string{"id", "email", "password_hash", "status", "scopes"}).
+					AddRow("user-1", "user@example.com", "hashed", "active", "wallet:adjust wallet:read")
 				mockDB.ExpectQuery(regexp.QuoteMeta("SELECT id::text AS id, email, password_hash, status")).
 					WithArgs("user@example.com").
 					WillReturnRows(rows)
@@ -111,7 +157,7 @@ func (s *AuthLoginRepositorySuite) TestGetUserAuthByEmail_TableDriven() {
 	for _, tc := range tests {
 		s.Run(tc.name, func() {
 			db, mockDB := newSQLXMock(s.T())
-			repo := NewAuthLoginRepository(db)
+			repo := NewAuthLoginRepository(db, 0)
 			if tc.setupMock != nil {
 				tc.setupMock(mockDB)
 			}
@@ -121,6 +167,7 @@ func (s *AuthLoginRepositorySuite) TestGetUserAuthByEmail_TableDriven() {
 			if err == nil {
 				assert.Equal(s.T(), "user-1", result.ID)
 				assert.Equal(s.T(), "user@example.com", result.Email)
+				assert.Equal(s.T(), []string{"wallet:adjust", "wallet:read"}, result.Scopes)
 			}
 			require.NoError(s.T(), mockDB.ExpectationsWereMet())
 		})
@@ -198,7 +245,7 @@ func (s *InquiryCheckBalanceRepositorySuite) TestGetWalletBalanceByUserID_TableD
 	for _, tc := range tests {
 		s.Run(tc.name, func() {
 			db, mockDB := newSQLXMock(s.T())
-			repo := NewInquiryCheckBalanceRepository(db)
+			repo := NewInquiryCheckBalanceRepository(db, 0)
 			if tc.setupMock != nil {
 				tc.setupMock(mockDB)
 			}
@@ -228,7 +275,7 @@ func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Table
 	now := time.Now().UTC()
 	beginErr := errors.New("begin failed")
 	withdrawErr := errors.New("withdraw failed")
-	hasWalletErr := errors.New("exist check failed")
+	versionErr := errors.New("version read failed")
 	insertLedgerErr := errors.New("insert ledger failed")
 	commitErr := errors.New("commit failed")
 
@@ -277,9 +324,7 @@ func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Table
 			amount: 100,
 			setupMock: func(mockDB sqlmock.Sqlmock) {
 				mockDB.ExpectBegin()
-				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnError(sql.ErrNoRows)
-				rows := sqlmock.NewRows([]string{"exists"}).AddRow(false)
-				mockDB.ExpectQuery("SELECT EXISTS").WithArgs(userUUID).WillReturnRows(rows)
+				mockDB.ExpectQuery("SELECT\\s+id AS wallet_id").WithArgs(userUUID).WillReturnError(sql.ErrNoRows)
 				mockDB.ExpectRollback()
 			},
 			assertion: func(err error) {
@@ -288,30 +333,60 @@ func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Table
 			},
 		},
 		{
-			name:   "has wallet check failed",
+			name:   "frozen wallet is rejected without touching the balance",
 			userID: userUUID.String(),
 			amount: 100,
 			setupMock: func(mockDB sqlmock.Sqlmock) {
 				mockDB.ExpectBegin()
-				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnError(sql.ErrNoRows)
-				mockDB.ExpectQuery("SELECT EXISTS").WithArgs(userUUID).WillReturnError(hasWalletErr)
+				expectWalletVersionWithStatus(mockDB, userUUID, walletUUID, 3, vo.WalletStatusFrozen)
 				mockDB.ExpectRollback()
 			},
 			assertion: func(err error) {
 				require.Error(s.T(), err)
-				assert.ErrorContains(s.T(), err, "failed to check wallet existence")
-				assert.ErrorIs(s.T(), err, hasWalletErr)
+				assert.ErrorIs(s.T(), err, vo.ErrWalletFrozen)
 			},
 		},
 		{
-			name:   "insufficient balance",
+			name:   "read wallet version failed",
 			userID: userUUID.String(),
 			amount: 100,
 			setupMock: func(mockDB sqlmock.Sqlmock) {
 				mockDB.ExpectBegin()
-				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnError(sql.ErrNoRows)
-				rows := sqlmock.NewRows([]string{"exists"}).AddRow(true)
-				mockDB.ExpectQuery("SELECT EXISTS").WithArgs(userUUID).WillReturnRows(rows)
+				mockDB.ExpectQuery("SELECT\\s+id AS wallet_id").WithArgs(userUUID).WillReturnError(versionErr)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "failed to read wallet version")
+				assert.ErrorIs(s.T(), err, versionErr)
+			},
+		},
+		{
+			name:   "re-read wallet version failed",
+			userID: userUUID.String(),
+			amount: 100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectQuery("SELECT\\s+id AS wallet_id").WithArgs(userUUID).WillReturnError(versionErr)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "failed to re-read wallet version")
+				assert.ErrorIs(s.T(), err, versionErr)
+			},
+		},
+		{
+			name:   "no rows because balance is insufficient",
+			userID: userUUID.String(),
+			amount: 100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnError(sql.ErrNoRows)
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
 				mockDB.ExpectRollback()
 			},
 			assertion: func(err error) {
@@ -319,13 +394,30 @@ func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Table
 				assert.ErrorIs(s.T(), err, vo.ErrInsufficientBalance)
 			},
 		},
+		{
+			name:   "no rows because version changed",
+			userID: userUUID.String(),
+			amount: 100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnError(sql.ErrNoRows)
+				expectWalletVersion(mockDB, userUUID, walletUUID, 4)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(err error) {
+				require.Error(s.T(), err)
+				assert.ErrorIs(s.T(), err, vo.ErrConcurrentModification)
+			},
+		},
 		{
 			name:   "withdraw query failed",
 			userID: userUUID.String(),
 			amount: 100,
 			setupMock: func(mockDB sqlmock.Sqlmock) {
 				mockDB.ExpectBegin()
-				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnError(withdrawErr)
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnError(withdrawErr)
 				mockDB.ExpectRollback()
 			},
 			assertion: func(err error) {
@@ -341,10 +433,11 @@ func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Table
 			chainID: "chain-1",
 			setupMock: func(mockDB sqlmock.Sqlmock) {
 				mockDB.ExpectBegin()
-				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "updated_at"}).
-					AddRow(walletUUID, userUUID.String(), int64(900), "IDR", now)
-				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnRows(walletRows)
-				mockDB.ExpectExec("INSERT INTO wallet_ledger").WithArgs(walletUUID, "withdrawal", int64(-100), int64(900), sql.NullString{}, sql.NullString{String: "chain-1", Valid: true}).WillReturnError(insertLedgerErr)
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+					AddRow(walletUUID, userUUID.String(), int64(900), "IDR", int64(4), now)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnRows(walletRows)
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").WithArgs(walletUUID, "withdrawal", int64(-100), int64(900), sql.NullString{String: "ref-1", Valid: true}, sql.NullString{String: "chain-1", Valid: true}).WillReturnError(insertLedgerErr)
 				mockDB.ExpectRollback()
 			},
 			assertion: func(err error) {
@@ -359,10 +452,14 @@ func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Table
 			amount: 100,
 			setupMock: func(mockDB sqlmock.Sqlmock) {
 				mockDB.ExpectBegin()
-				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "updated_at"}).
-					AddRow(walletUUID, userUUID.String(), int64(900), "IDR", now)
-				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnRows(walletRows)
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+					AddRow(walletUUID, userUUID.String(), int64(900), "IDR", int64(4), now)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnRows(walletRows)
 				mockDB.ExpectExec("INSERT INTO wallet_ledger").WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWithdrawalPayoutInsert(mockDB)
+				expectOutboxInsert(mockDB, sharedevents.TypeWithdrawalDebited)
+				expectWalletNotify(mockDB, userUUID)
 				mockDB.ExpectCommit().WillReturnError(commitErr)
 			},
 			assertion: func(err error) {
@@ -377,10 +474,14 @@ func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Table
 			amount: 100,
 			setupMock: func(mockDB sqlmock.Sqlmock) {
 				mockDB.ExpectBegin()
-				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "updated_at"}).
-					AddRow(walletUUID, userUUID.String(), int64(900), "IDR", now)
-				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnRows(walletRows)
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+					AddRow(walletUUID, userUUID.String(), int64(900), "IDR", int64(4), now)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnRows(walletRows)
 				mockDB.ExpectExec("INSERT INTO wallet_ledger").WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWithdrawalPayoutInsert(mockDB)
+				expectOutboxInsert(mockDB, sharedevents.TypeWithdrawalDebited)
+				expectWalletNotify(mockDB, userUUID)
 				mockDB.ExpectCommit()
 			},
 			assertion: func(err error) {
@@ -392,24 +493,2245 @@ func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Table
 	for _, tc := range tests {
 		s.Run(tc.name, func() {
 			db, mockDB := newSQLXMock(s.T())
-			repo := NewWithdrawBalanceRepository(db)
+			repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
 			if tc.setupMock != nil {
 				tc.setupMock(mockDB)
 			}
 
-			result, err := repo.WithdrawWalletBalanceByUserID(context.Background(), tc.userID, tc.amount, tc.chainID)
+			result, err := repo.WithdrawWalletBalanceByUserID(context.Background(), tc.userID, tc.amount, tc.chainID, "ref-1", 0, domain.DailyWithdrawLimit{})
 			tc.assertion(err)
 			if err == nil {
 				assert.Equal(s.T(), userUUID.String(), result.UserID)
 				assert.Equal(s.T(), int64(900), result.BalanceMinor)
 				assert.Equal(s.T(), "IDR", result.Currency)
 				assert.Equal(s.T(), now, result.UpdatedAt)
+				assert.Equal(s.T(), int64(4), result.Version)
 			}
 			require.NoError(s.T(), mockDB.ExpectationsWereMet())
 		})
 	}
 }
 
-func TestWithdrawBalanceRepositorySuite(t *testing.T) {
-	suite.Run(t, new(WithdrawBalanceRepositorySuite))
+func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Tracing_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	beginErr := errors.New("begin failed")
+
+	tests := []struct {
+		name         string
+		setupMock    func(sqlmock.Sqlmock)
+		expectStatus codes.Code
+	}{
+		{
+			name: "successful withdraw records child span",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+					AddRow(walletUUID, userUUID.String(), int64(900), "IDR", int64(4), time.Now().UTC())
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnRows(walletRows)
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxInsert(mockDB, sharedevents.TypeWithdrawalDebited)
+				expectWalletNotify(mockDB, userUUID)
+				mockDB.ExpectCommit()
+			},
+			expectStatus: codes.Unset,
+		},
+		{
+			name: "failed transaction marks span as error",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin().WillReturnError(beginErr)
+			},
+			expectStatus: codes.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			tracer := provider.Tracer("repository-test")
+
+			db, mockDB := newSQLXMock(s.T())
+			tc.setupMock(mockDB)
+			repo := NewWithdrawBalanceRepository(db, tracer, 0, TxRetryPolicy{})
+
+			ctx, parent := tracer.Start(context.Background(), "parent")
+			_, _ = repo.WithdrawWalletBalanceByUserID(ctx, userUUID.String(), 100, "", "", 0, domain.DailyWithdrawLimit{})
+			parent.End()
+
+			spans := recorder.Ended()
+			require.Len(s.T(), spans, 2)
+			child := spans[0]
+			assert.Equal(s.T(), "repository.WithdrawWalletBalanceByUserID", child.Name())
+			assert.Equal(s.T(), parent.SpanContext().SpanID(), child.Parent().SpanID())
+			assert.Equal(s.T(), parent.SpanContext().TraceID(), child.SpanContext().TraceID())
+			assert.Equal(s.T(), tc.expectStatus, child.Status().Code)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_DailyLimit_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	limit := domain.DailyWithdrawLimit{LimitMinor: 1_000, Since: today}
+
+	sumErr := errors.New("sum failed")
+
+	tests := []struct {
+		name           string
+		withdrawnToday int64
+		sumErr         error
+		expectErr      error
+	}{
+		{name: "fresh day has no prior withdrawals", withdrawnToday: 0},
+		{name: "partial sum leaves room", withdrawnToday: 400},
+		{name: "at limit is allowed", withdrawnToday: 900},
+		{name: "over limit is rejected", withdrawnToday: 901, expectErr: vo.ErrDailyLimitExceeded},
+		{name: "sum failure aborts the withdrawal", sumErr: sumErr, expectErr: sumErr},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
+
+			mockDB.ExpectBegin()
+			expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+			walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+				AddRow(walletUUID, userUUID.String(), int64(4_900), "IDR", int64(4), now)
+			mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnRows(walletRows)
+			sumQuery := mockDB.ExpectQuery("-- name: SumWithdrawalsByUserSince").WithArgs(userUUID, today)
+			if tc.sumErr != nil {
+				sumQuery.WillReturnError(tc.sumErr)
+			} else {
+				sumQuery.WillReturnRows(sqlmock.NewRows([]string{"withdrawn_minor"}).AddRow(tc.withdrawnToday))
+			}
+			if tc.expectErr != nil {
+				mockDB.ExpectRollback()
+			} else {
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWithdrawalPayoutInsert(mockDB)
+				expectOutboxInsert(mockDB, sharedevents.TypeWithdrawalDebited)
+				expectWalletNotify(mockDB, userUUID)
+				mockDB.ExpectCommit()
+			}
+
+			_, err := repo.WithdrawWalletBalanceByUserID(context.Background(), userUUID.String(), 100, "", "ref-1", 0, limit)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			} else {
+				require.NoError(s.T(), err)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Notify_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		setupMock func(mockDB sqlmock.Sqlmock)
+	}{
+		{
+			name: "notify is issued inside the transaction before commit",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec("SAVEPOINT wallet_notify").WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectExec("SELECT pg_notify").WithArgs(WalletChangesChannel, userUUID.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed notify is rolled back to savepoint and the withdrawal still commits",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec("SAVEPOINT wallet_notify").WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectExec("SELECT pg_notify").WithArgs(WalletChangesChannel, userUUID.String()).WillReturnError(errors.New("notify queue full"))
+				mockDB.ExpectExec("ROLLBACK TO SAVEPOINT wallet_notify").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "failed savepoint skips notify",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec("SAVEPOINT wallet_notify").WillReturnError(errors.New("savepoint failed"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
+
+			mockDB.ExpectBegin()
+			expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+			walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+				AddRow(walletUUID, userUUID.String(), int64(900), "IDR", int64(4), now)
+			mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnRows(walletRows)
+			mockDB.ExpectExec("INSERT INTO wallet_ledger").WillReturnResult(sqlmock.NewResult(1, 1))
+			expectOutboxInsert(mockDB, sharedevents.TypeWithdrawalDebited)
+			tc.setupMock(mockDB)
+			mockDB.ExpectCommit()
+
+			result, err := repo.WithdrawWalletBalanceByUserID(context.Background(), userUUID.String(), 100, "", "", 0, domain.DailyWithdrawLimit{})
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), int64(900), result.BalanceMinor)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_WithFee() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Now().UTC()
+	db, mockDB := newSQLXMock(s.T())
+	repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
+
+	mockDB.ExpectBegin()
+	expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+	walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+		AddRow(walletUUID, userUUID.String(), int64(875), "IDR", int64(4), now)
+	mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(125), userUUID, int64(3)).WillReturnRows(walletRows)
+	mockDB.ExpectExec("INSERT INTO wallet_ledger").
+		WithArgs(walletUUID, "withdrawal", int64(-100), int64(900), sql.NullString{String: "ref-1", Valid: true}, sql.NullString{String: "chain-1", Valid: true}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectExec("INSERT INTO wallet_ledger").
+		WithArgs(walletUUID, "fee", int64(-25), int64(875), sql.NullString{String: "ref-1", Valid: true}, sql.NullString{String: "chain-1", Valid: true}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectWithdrawalPayoutInsert(mockDB)
+	expectOutboxInsert(mockDB, sharedevents.TypeWithdrawalDebited)
+	expectWalletNotify(mockDB, userUUID)
+	mockDB.ExpectCommit()
+
+	result, err := repo.WithdrawWalletBalanceByUserID(context.Background(), userUUID.String(), 100, "chain-1", "ref-1", 25, domain.DailyWithdrawLimit{})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(875), result.BalanceMinor)
+	require.NoError(s.T(), mockDB.ExpectationsWereMet())
+}
+
+// outboxEventArg matches an outbox payload argument against the expected event.
+type outboxEventArg struct{ expected sharedevents.Event }
+
+func (a outboxEventArg) Match(value driver.Value) bool {
+	payload, ok := value.([]byte)
+	if !ok {
+		return false
+	}
+
+	var event sharedevents.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return false
+	}
+
+	return event.Type == a.expected.Type &&
+		event.TransactionID == a.expected.TransactionID &&
+		event.UserID == a.expected.UserID &&
+		event.AmountMinor == a.expected.AmountMinor &&
+		event.FeeMinor == a.expected.FeeMinor &&
+		event.Currency == a.expected.Currency &&
+		event.ChainID == a.expected.ChainID &&
+		event.OccurredAt.Equal(a.expected.OccurredAt)
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Outbox_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	outboxErr := errors.New("outbox insert failed")
+	expected := sharedevents.Event{
+		Type:          sharedevents.TypeWithdrawalDebited,
+		TransactionID: "ref-1",
+		UserID:        userUUID.String(),
+		AmountMinor:   100,
+		FeeMinor:      25,
+		Currency:      "IDR",
+		ChainID:       "chain-1",
+		OccurredAt:    now,
+	}
+
+	tests := []struct {
+		name      string
+		outboxErr error
+	}{
+		{name: "event is inserted inside the transaction before commit"},
+		{name: "failed insert rolls back the withdrawal", outboxErr: outboxErr},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
+
+			mockDB.ExpectBegin()
+			expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+			walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+				AddRow(walletUUID, userUUID.String(), int64(875), "IDR", int64(4), now)
+			mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(125), userUUID, int64(3)).WillReturnRows(walletRows)
+			mockDB.ExpectExec("INSERT INTO wallet_ledger").WillReturnResult(sqlmock.NewResult(1, 1))
+			mockDB.ExpectExec("INSERT INTO wallet_ledger").WillReturnResult(sqlmock.NewResult(1, 1))
+			expectWithdrawalPayoutInsert(mockDB)
+			insert := mockDB.ExpectExec("INSERT INTO outbox").WithArgs(sharedevents.TypeWithdrawalDebited, "ref-1", outboxEventArg{expected: expected})
+			if tc.outboxErr != nil {
+				insert.WillReturnError(tc.outboxErr)
+				mockDB.ExpectRollback()
+			} else {
+				insert.WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWalletNotify(mockDB, userUUID)
+				mockDB.ExpectCommit()
+			}
+
+			_, err := repo.WithdrawWalletBalanceByUserID(context.Background(), userUUID.String(), 100, "chain-1", "ref-1", 25, domain.DailyWithdrawLimit{})
+			if tc.outboxErr != nil {
+				assert.ErrorIs(s.T(), err, tc.outboxErr)
+				assert.ErrorContains(s.T(), err, "failed to insert withdrawal.debited outbox event")
+			} else {
+				require.NoError(s.T(), err)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestGetWithdrawalByReferenceID_TableDriven() {
+	ownerUUID := uuid.New()
+	otherUUID := uuid.New()
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	reversedAt := createdAt.Add(time.Minute)
+	queryErr := errors.New("query failed")
+	columns := []string{"entry_type", "amount_minor", "chain_id", "currency", "created_at"}
+	chain := sql.NullString{String: "chain-1", Valid: true}
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		setupMock func(sqlmock.Sqlmock)
+		assertion func(domain.WithdrawalRecord, error)
+	}{
+		{
+			name:   "found with fee",
+			userID: ownerUUID,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger l").WithArgs("ref-1", ownerUUID).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("withdrawal", int64(-100), chain, "IDR", createdAt).
+						AddRow("fee", int64(-25), chain, "IDR", createdAt))
+				expectWithdrawalPayoutStatus(mockDB, "completed")
+			},
+			assertion: func(record domain.WithdrawalRecord, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), domain.WithdrawalRecord{
+					ReferenceID: "ref-1",
+					UserID:      ownerUUID.String(),
+					AmountMinor: 100,
+					FeeMinor:    25,
+					Currency:    "IDR",
+					ChainID:     "chain-1",
+					CreatedAt:   createdAt,
+					UpdatedAt:   createdAt,
+				}, record)
+			},
+		},
+		{
+			name:   "reversed withdrawal",
+			userID: ownerUUID,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger l").WithArgs("ref-1", ownerUUID).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("withdrawal", int64(-100), sql.NullString{}, "IDR", createdAt).
+						AddRow("withdrawal_reversal", int64(100), sql.NullString{}, "IDR", reversedAt))
+				expectWithdrawalPayoutStatus(mockDB, "reversed")
+			},
+			assertion: func(record domain.WithdrawalRecord, err error) {
+				require.NoError(s.T(), err)
+				assert.True(s.T(), record.Reversed)
+				assert.False(s.T(), record.Pending)
+				assert.Equal(s.T(), createdAt, record.CreatedAt)
+				assert.Equal(s.T(), reversedAt, record.UpdatedAt)
+			},
+		},
+		{
+			name:   "payout still pending",
+			userID: ownerUUID,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger l").WithArgs("ref-1", ownerUUID).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("withdrawal", int64(-100), chain, "IDR", createdAt))
+				expectWithdrawalPayoutStatus(mockDB, "pending")
+			},
+			assertion: func(record domain.WithdrawalRecord, err error) {
+				require.NoError(s.T(), err)
+				assert.True(s.T(), record.Pending)
+				assert.False(s.T(), record.Reversed)
+			},
+		},
+		{
+			name:   "withdrawal made before payouts were tracked",
+			userID: ownerUUID,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger l").WithArgs("ref-1", ownerUUID).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("withdrawal", int64(-100), chain, "IDR", createdAt))
+				mockDB.ExpectQuery("SELECT status FROM withdrawal_payouts").WithArgs("ref-1").WillReturnError(sql.ErrNoRows)
+			},
+			assertion: func(record domain.WithdrawalRecord, err error) {
+				require.NoError(s.T(), err)
+				assert.False(s.T(), record.Pending)
+			},
+		},
+		{
+			name:   "payout status query error",
+			userID: ownerUUID,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger l").WithArgs("ref-1", ownerUUID).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("withdrawal", int64(-100), chain, "IDR", createdAt))
+				mockDB.ExpectQuery("SELECT status FROM withdrawal_payouts").WithArgs("ref-1").WillReturnError(queryErr)
+			},
+			assertion: func(_ domain.WithdrawalRecord, err error) {
+				assert.ErrorIs(s.T(), err, queryErr)
+				assert.ErrorContains(s.T(), err, "failed to read withdrawal payout status")
+			},
+		},
+		{
+			name:   "not found",
+			userID: ownerUUID,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger l").WithArgs("ref-1", ownerUUID).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assertion: func(_ domain.WithdrawalRecord, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWithdrawalNotFound)
+			},
+		},
+		{
+			name:   "wrong owner is not found",
+			userID: otherUUID,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("AND w.user_id = \\$2::uuid").WithArgs("ref-1", otherUUID).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assertion: func(_ domain.WithdrawalRecord, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWithdrawalNotFound)
+			},
+		},
+		{
+			name:   "query error",
+			userID: ownerUUID,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger l").WithArgs("ref-1", ownerUUID).WillReturnError(queryErr)
+			},
+			assertion: func(_ domain.WithdrawalRecord, err error) {
+				assert.ErrorIs(s.T(), err, queryErr)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
+			tc.setupMock(mockDB)
+
+			record, err := repo.GetWithdrawalByReferenceID(context.Background(), tc.userID.String(), "ref-1")
+			tc.assertion(record, err)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestReverseWithdrawalByUserID_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Now().UTC()
+	reference := sql.NullString{String: "ref-1", Valid: true}
+	chain := sql.NullString{String: "chain-1", Valid: true}
+	creditErr := errors.New("credit failed")
+
+	walletRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "updated_at"}).
+			AddRow(walletUUID, userUUID.String(), int64(1000), "IDR", now)
+	}
+
+	tests := []struct {
+		name      string
+		userID    string
+		feeMinor  int64
+		setupMock func(sqlmock.Sqlmock)
+		assertion func(domain.WalletBalance, error)
+	}{
+		{
+			name:   "invalid user id",
+			userID: "not-uuid",
+			assertion: func(_ domain.WalletBalance, err error) {
+				assert.ErrorContains(s.T(), err, "invalid user_id")
+			},
+		},
+		{
+			name:   "payout already settled",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE withdrawal_payouts").WithArgs("ref-1", "reversed").WillReturnRows(sqlmock.NewRows(withdrawalPayoutColumns))
+				mockDB.ExpectRollback()
+			},
+			assertion: func(_ domain.WalletBalance, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWithdrawalSettled)
+			},
+		},
+		{
+			name:   "wallet not found",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWithdrawalPayoutSettle(mockDB, "reversed")
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(_ domain.WalletBalance, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name:   "credit failure",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWithdrawalPayoutSettle(mockDB, "reversed")
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnError(creditErr)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(_ domain.WalletBalance, err error) {
+				assert.ErrorIs(s.T(), err, creditErr)
+			},
+		},
+		{
+			name:   "credits amount without fee",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWithdrawalPayoutSettle(mockDB, "reversed")
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnRows(walletRows())
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").
+					WithArgs(walletUUID, "withdrawal_reversal", int64(100), int64(1000), reference, chain).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxInsert(mockDB, sharedevents.TypeWithdrawalReversed)
+				expectWalletNotify(mockDB, userUUID)
+				mockDB.ExpectCommit()
+			},
+			assertion: func(result domain.WalletBalance, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), int64(1000), result.BalanceMinor)
+			},
+		},
+		{
+			name:     "credits amount and fee",
+			userID:   userUUID.String(),
+			feeMinor: 25,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWithdrawalPayoutSettle(mockDB, "reversed")
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(125), userUUID).WillReturnRows(walletRows())
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").
+					WithArgs(walletUUID, "withdrawal_reversal", int64(100), int64(975), reference, chain).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").
+					WithArgs(walletUUID, "fee_reversal", int64(25), int64(1000), reference, chain).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxInsert(mockDB, sharedevents.TypeWithdrawalReversed)
+				expectWalletNotify(mockDB, userUUID)
+				mockDB.ExpectCommit()
+			},
+			assertion: func(result domain.WalletBalance, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), int64(1000), result.BalanceMinor)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			result, err := repo.ReverseWithdrawalByUserID(context.Background(), tc.userID, 100, "chain-1", "ref-1", tc.feeMinor)
+			tc.assertion(result, err)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestCompleteWithdrawalByReferenceID_TableDriven() {
+	userUUID := uuid.New()
+	settledAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	queryErr := errors.New("update failed")
+	outboxErr := errors.New("outbox insert failed")
+	completed := sharedevents.Event{
+		Type:          sharedevents.TypeWithdrawalCompleted,
+		TransactionID: "ref-1",
+		UserID:        userUUID.String(),
+		AmountMinor:   100,
+		FeeMinor:      25,
+		Currency:      "IDR",
+		ChainID:       "chain-1",
+		OccurredAt:    settledAt,
+	}
+	settledRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(withdrawalPayoutColumns).AddRow("ref-1", userUUID.String(), int64(100), int64(25), "IDR", "chain-1", settledAt)
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		expectErr error
+	}{
+		{
+			name: "pending payout is completed and its event queued in the same transaction",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE withdrawal_payouts").WithArgs("ref-1", "completed").WillReturnRows(settledRows())
+				mockDB.ExpectExec("INSERT INTO outbox").WithArgs(sharedevents.TypeWithdrawalCompleted, "ref-1", outboxEventArg{expected: completed}).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockDB.ExpectCommit()
+			},
+		},
+		{
+			name: "settled payout queues nothing",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE withdrawal_payouts").WithArgs("ref-1", "completed").WillReturnRows(sqlmock.NewRows(withdrawalPayoutColumns))
+				mockDB.ExpectRollback()
+			},
+			expectErr: vo.ErrWithdrawalSettled,
+		},
+		{
+			name: "update error",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE withdrawal_payouts").WithArgs("ref-1", "completed").WillReturnError(queryErr)
+				mockDB.ExpectRollback()
+			},
+			expectErr: queryErr,
+		},
+		{
+			name: "failed event insert keeps the payout pending",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE withdrawal_payouts").WithArgs("ref-1", "completed").WillReturnRows(settledRows())
+				mockDB.ExpectExec("INSERT INTO outbox").WithArgs(sharedevents.TypeWithdrawalCompleted, "ref-1", sqlmock.AnyArg()).WillReturnError(outboxErr)
+				mockDB.ExpectRollback()
+			},
+			expectErr: outboxErr,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
+			tc.setupMock(mockDB)
+
+			err := repo.CompleteWithdrawalByReferenceID(context.Background(), "ref-1")
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			} else {
+				require.NoError(s.T(), err)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestListPendingWithdrawals_TableDriven() {
+	userUUID := uuid.New()
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	cutoff := createdAt.Add(time.Minute)
+	queryErr := errors.New("query failed")
+	columns := []string{"reference_id", "user_id", "amount_minor", "fee_minor", "currency", "chain_id", "created_at"}
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		assertion func([]domain.PendingWithdrawal, error)
+	}{
+		{
+			name: "pending withdrawals oldest first",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM withdrawal_payouts").WithArgs(cutoff, 10).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("ref-1", userUUID.String(), int64(100), int64(25), "IDR", "chain-1", createdAt).
+						AddRow("ref-2", userUUID.String(), int64(200), int64(0), "IDR", "", createdAt.Add(time.Second)))
+			},
+			assertion: func(pending []domain.PendingWithdrawal, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), []domain.PendingWithdrawal{
+					{ReferenceID: "ref-1", UserID: userUUID.String(), AmountMinor: 100, FeeMinor: 25, Currency: "IDR", ChainID: "chain-1", CreatedAt: createdAt},
+					{ReferenceID: "ref-2", UserID: userUUID.String(), AmountMinor: 200, Currency: "IDR", CreatedAt: createdAt.Add(time.Second)},
+				}, pending)
+			},
+		},
+		{
+			name: "nothing pending",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM withdrawal_payouts").WithArgs(cutoff, 10).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			assertion: func(pending []domain.PendingWithdrawal, err error) {
+				require.NoError(s.T(), err)
+				assert.Empty(s.T(), pending)
+			},
+		},
+		{
+			name: "query error",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM withdrawal_payouts").WithArgs(cutoff, 10).WillReturnError(queryErr)
+			},
+			assertion: func(_ []domain.PendingWithdrawal, err error) {
+				assert.ErrorIs(s.T(), err, queryErr)
+				assert.ErrorContains(s.T(), err, "failed to list pending withdrawals")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
+			tc.setupMock(mockDB)
+
+			pending, err := repo.ListPendingWithdrawals(context.Background(), cutoff, 10)
+			tc.assertion(pending, err)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestWithdrawWalletBalanceByUserID_Retry_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Now().UTC()
+	serializationErr := &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+	deadlockErr := &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+	uniqueErr := &pgconn.PgError{Code: "23505", Message: "duplicate key"}
+
+	expectSuccess := func(mockDB sqlmock.Sqlmock) {
+		mockDB.ExpectBegin()
+		expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+		walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+			AddRow(walletUUID, userUUID.String(), int64(900), "IDR", int64(4), now)
+		mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnRows(walletRows)
+		mockDB.ExpectExec("INSERT INTO wallet_ledger").WillReturnResult(sqlmock.NewResult(1, 1))
+		expectWithdrawalPayoutInsert(mockDB)
+		expectOutboxInsert(mockDB, sharedevents.TypeWithdrawalDebited)
+		expectWalletNotify(mockDB, userUUID)
+		mockDB.ExpectCommit()
+	}
+	expectFailure := func(mockDB sqlmock.Sqlmock, err error) {
+		mockDB.ExpectBegin()
+		expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+		mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnError(err)
+		mockDB.ExpectRollback()
+	}
+
+	tests := []struct {
+		name      string
+		policy    TxRetryPolicy
+		setupMock func(sqlmock.Sqlmock)
+		assertion func(domain.WalletBalance, error)
+	}{
+		{
+			name:   "serialization failure then success",
+			policy: TxRetryPolicy{MaxRetries: 2, BaseBackoff: time.Millisecond},
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				expectFailure(mockDB, serializationErr)
+				expectSuccess(mockDB)
+			},
+			assertion: func(result domain.WalletBalance, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), int64(900), result.BalanceMinor)
+			},
+		},
+		{
+			name:   "deadlock then success",
+			policy: TxRetryPolicy{MaxRetries: 2, BaseBackoff: time.Millisecond},
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				expectFailure(mockDB, deadlockErr)
+				expectSuccess(mockDB)
+			},
+			assertion: func(result domain.WalletBalance, err error) {
+				require.NoError(s.T(), err)
+			},
+		},
+		{
+			name:   "gives up after max retries",
+			policy: TxRetryPolicy{MaxRetries: 1, BaseBackoff: time.Millisecond},
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				expectFailure(mockDB, serializationErr)
+				expectFailure(mockDB, serializationErr)
+			},
+			assertion: func(_ domain.WalletBalance, err error) {
+				assert.ErrorIs(s.T(), err, serializationErr)
+			},
+		},
+		{
+			name:   "non retryable error passes through",
+			policy: TxRetryPolicy{MaxRetries: 3, BaseBackoff: time.Millisecond},
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				expectFailure(mockDB, uniqueErr)
+			},
+			assertion: func(_ domain.WalletBalance, err error) {
+				assert.ErrorIs(s.T(), err, uniqueErr)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWithdrawBalanceRepository(db, nil, 0, tc.policy)
+			tc.setupMock(mockDB)
+
+			result, err := repo.WithdrawWalletBalanceByUserID(context.Background(), userUUID.String(), 100, "", "ref-1", 0, domain.DailyWithdrawLimit{})
+			tc.assertion(result, err)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *WithdrawBalanceRepositorySuite) TestGetWalletBalanceByUserID_TableDriven() {
+	userID := uuid.New()
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		userID    string
+		setupMock func(sqlmock.Sqlmock)
+		expectErr error
+	}{
+		{
+			name:   "wallet not found",
+			userID: userID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(regexp.QuoteMeta("SELECT\n    w.user_id::text AS user_id")).
+					WithArgs(userID).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectErr: vo.ErrWalletNotFound,
+		},
+		{
+			name:   "success",
+			userID: userID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"user_id", "balance_minor", "currency", "updated_at"}).
+					AddRow(userID.String(), int64(2000), "IDR", now)
+				mockDB.ExpectQuery(regexp.QuoteMeta("SELECT\n    w.user_id::text AS user_id")).
+					WithArgs(userID).
+					WillReturnRows(rows)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWithdrawBalanceRepository(db, nil, 0, TxRetryPolicy{})
+			tc.setupMock(mockDB)
+
+			result, err := repo.GetWalletBalanceByUserID(context.Background(), tc.userID)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			} else {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), "IDR", result.Currency)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWithdrawBalanceRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WithdrawBalanceRepositorySuite))
+}
+
+type WalletAdjustBalanceRepositorySuite struct{ suite.Suite }
+
+func (s *WalletAdjustBalanceRepositorySuite) TestAdjustWalletBalanceByUserID_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		userID    string
+		delta     int64
+		setupMock func(sqlmock.Sqlmock)
+		assertion func(error)
+	}{
+		{
+			name:   "invalid user id",
+			userID: "not-uuid",
+			delta:  100,
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name:   "zero delta",
+			userID: userUUID.String(),
+			delta:  0,
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidAmount)
+			},
+		},
+		{
+			name:   "debit below zero is rejected",
+			userID: userUUID.String(),
+			delta:  -100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(-100), userUUID).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectQuery("SELECT EXISTS").WithArgs(userUUID).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mockDB.ExpectRollback()
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInsufficientBalance)
+			},
+		},
+		{
+			name:   "credit writes adjustment ledger entry",
+			userID: userUUID.String(),
+			delta:  100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "updated_at"}).
+					AddRow(walletUUID, userUUID.String(), int64(1100), "IDR", now)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID).WillReturnRows(walletRows)
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").
+					WithArgs(walletUUID, "adjustment", int64(100), int64(1100), sql.NullString{String: "ref-1", Valid: true}, sql.NullString{}).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWalletNotify(mockDB, userUUID)
+				mockDB.ExpectCommit()
+			},
+			assertion: func(err error) {
+				require.NoError(s.T(), err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWalletAdjustBalanceRepository(db, 0)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			result, err := repo.AdjustWalletBalanceByUserID(context.Background(), tc.userID, tc.delta, "ref-1")
+			tc.assertion(err)
+			if err == nil {
+				assert.Equal(s.T(), int64(1100), result.BalanceMinor)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWalletAdjustBalanceRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WalletAdjustBalanceRepositorySuite))
+}
+
+type WalletStatusRepositorySuite struct{ suite.Suite }
+
+func (s *WalletStatusRepositorySuite) TestSetWalletStatusByUserID_TableDriven() {
+	userUUID := uuid.New()
+	now := time.Now().UTC()
+	updateErr := errors.New("update failed")
+
+	tests := []struct {
+		name      string
+		userID    string
+		status    string
+		setupMock func(sqlmock.Sqlmock)
+		assertion func(domain.WalletStatus, error)
+	}{
+		{
+			name:   "invalid user id",
+			userID: "not-uuid",
+			status: vo.WalletStatusFrozen,
+			assertion: func(_ domain.WalletStatus, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name:   "wallet not found",
+			userID: userUUID.String(),
+			status: vo.WalletStatusFrozen,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(vo.WalletStatusFrozen, userUUID).WillReturnError(sql.ErrNoRows)
+			},
+			assertion: func(_ domain.WalletStatus, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name:   "update failed",
+			userID: userUUID.String(),
+			status: vo.WalletStatusFrozen,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(vo.WalletStatusFrozen, userUUID).WillReturnError(updateErr)
+			},
+			assertion: func(_ domain.WalletStatus, err error) {
+				assert.ErrorContains(s.T(), err, "failed to set wallet status")
+				assert.ErrorIs(s.T(), err, updateErr)
+			},
+		},
+		{
+			name:   "freeze bumps the version",
+			userID: userUUID.String(),
+			status: vo.WalletStatusFrozen,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("UPDATE wallets\\s+SET\\s+status = \\$1,\\s+version = version \\+ 1").WithArgs(vo.WalletStatusFrozen, userUUID).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "status", "updated_at"}).AddRow(userUUID.String(), vo.WalletStatusFrozen, now))
+			},
+			assertion: func(result domain.WalletStatus, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), domain.WalletStatus{UserID: userUUID.String(), Status: vo.WalletStatusFrozen, UpdatedAt: now}, result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWalletStatusRepository(db, 0)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			result, err := repo.SetWalletStatusByUserID(context.Background(), tc.userID, tc.status)
+			tc.assertion(result, err)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWalletStatusRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WalletStatusRepositorySuite))
+}
+
+type DepositBalanceRepositorySuite struct{ suite.Suite }
+
+func (s *DepositBalanceRepositorySuite) TestDepositWalletBalanceByUserID_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		userID    string
+		amount    int64
+		setupMock func(sqlmock.Sqlmock)
+		assertion func(error)
+	}{
+		{
+			name:   "invalid user id",
+			userID: "not-uuid",
+			amount: 100,
+			assertion: func(err error) {
+				require.Error(s.T(), err)
+				assert.Contains(s.T(), err.Error(), "invalid user_id")
+			},
+		},
+		{
+			name:   "invalid amount",
+			userID: userUUID.String(),
+			amount: 0,
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidAmount)
+			},
+		},
+		{
+			name:   "wallet not found",
+			userID: userUUID.String(),
+			amount: 100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("SELECT\\s+id AS wallet_id").WithArgs(userUUID).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name:   "no rows because version changed",
+			userID: userUUID.String(),
+			amount: 100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnError(sql.ErrNoRows)
+				expectWalletVersion(mockDB, userUUID, walletUUID, 4)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrConcurrentModification)
+			},
+		},
+		{
+			name:   "wallet deleted before update",
+			userID: userUUID.String(),
+			amount: 100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectQuery("SELECT\\s+id AS wallet_id").WithArgs(userUUID).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name:   "ledger insert failure rolls back",
+			userID: userUUID.String(),
+			amount: 100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+					AddRow(walletUUID, userUUID.String(), int64(1100), "IDR", int64(4), now)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnRows(walletRows)
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").WillReturnError(errors.New("insert failed"))
+				mockDB.ExpectRollback()
+			},
+			assertion: func(err error) {
+				require.Error(s.T(), err)
+				assert.Contains(s.T(), err.Error(), "failed to insert wallet ledger")
+			},
+		},
+		{
+			name:   "success writes deposit ledger entry",
+			userID: userUUID.String(),
+			amount: 100,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				expectWalletVersion(mockDB, userUUID, walletUUID, 3)
+				walletRows := sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "version", "updated_at"}).
+					AddRow(walletUUID, userUUID.String(), int64(1100), "IDR", int64(4), now)
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), userUUID, int64(3)).WillReturnRows(walletRows)
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").
+					WithArgs(walletUUID, "deposit", int64(100), int64(1100), sql.NullString{String: "ref-1", Valid: true}, sql.NullString{}).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWalletNotify(mockDB, userUUID)
+				mockDB.ExpectCommit()
+			},
+			assertion: func(err error) {
+				require.NoError(s.T(), err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewDepositBalanceRepository(db, 0)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			result, err := repo.DepositWalletBalanceByUserID(context.Background(), tc.userID, tc.amount, "ref-1")
+			tc.assertion(err)
+			if err == nil {
+				assert.Equal(s.T(), int64(1100), result.BalanceMinor)
+				assert.Equal(s.T(), int64(4), result.Version)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDepositBalanceRepositorySuite(t *testing.T) {
+	suite.Run(t, new(DepositBalanceRepositorySuite))
+}
+
+type AuthRegisterRepositorySuite struct{ suite.Suite }
+
+func (s *AuthRegisterRepositorySuite) TestCreateUser_TableDriven() {
+	userUUID := uuid.New()
+	now := time.Now().UTC()
+	insertErr := errors.New("insert failed")
+
+	tests := []struct {
+		name      string
+		userID    string
+		setupMock func(sqlmock.Sqlmock)
+		assertion func(time.Time, error)
+	}{
+		{
+			name:   "invalid user id",
+			userID: "not-uuid",
+			assertion: func(_ time.Time, err error) {
+				assert.ErrorContains(s.T(), err, "invalid user_id")
+			},
+		},
+		{
+			name:   "duplicate email",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("INSERT INTO users").
+					WithArgs(userUUID, "jane@example.com", "hashed").
+					WillReturnError(&pgconn.PgError{Code: sqlStateUniqueViolation, ConstraintName: "users_email_key"})
+			},
+			assertion: func(_ time.Time, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrEmailAlreadyRegistered)
+			},
+		},
+		{
+			name:   "wrap insert errors",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("INSERT INTO users").
+					WithArgs(userUUID, "jane@example.com", "hashed").
+					WillReturnError(insertErr)
+			},
+			assertion: func(_ time.Time, err error) {
+				assert.ErrorIs(s.T(), err, insertErr)
+				assert.NotErrorIs(s.T(), err, vo.ErrEmailAlreadyRegistered)
+			},
+		},
+		{
+			name:   "success returns created_at",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("INSERT INTO users \\(id, email, password_hash, status\\)\\s+VALUES \\(\\$1, \\$2, \\$3, 'active'\\)").
+					WithArgs(userUUID, "jane@example.com", "hashed").
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+			},
+			assertion: func(createdAt time.Time, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), now, createdAt)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewAuthRegisterRepository(db, 0)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			createdAt, err := repo.CreateUser(context.Background(), tc.userID, "jane@example.com", "hashed")
+			tc.assertion(createdAt, err)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *AuthRegisterRepositorySuite) TestDeleteUser_TableDriven() {
+	userUUID := uuid.New()
+	deleteErr := errors.New("delete failed")
+
+	tests := []struct {
+		name      string
+		userID    string
+		setupMock func(sqlmock.Sqlmock)
+		expectErr error
+	}{
+		{
+			name:   "deletes by id",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec("DELETE FROM users WHERE id = \\$1").
+					WithArgs(userUUID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "wrap delete errors",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec("DELETE FROM users").WithArgs(userUUID).WillReturnError(deleteErr)
+			},
+			expectErr: deleteErr,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewAuthRegisterRepository(db, 0)
+			tc.setupMock(mockDB)
+
+			err := repo.DeleteUser(context.Background(), tc.userID)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			} else {
+				require.NoError(s.T(), err)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthRegisterRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AuthRegisterRepositorySuite))
+}
+
+type AuthChangePasswordRepositorySuite struct{ suite.Suite }
+
+func (s *AuthChangePasswordRepositorySuite) TestGetPasswordHash_TableDriven() {
+	userUUID := uuid.New()
+	queryErr := errors.New("query failed")
+
+	tests := []struct {
+		name       string
+		userID     string
+		setupMock  func(sqlmock.Sqlmock)
+		expectHash string
+		expectErr  error
+	}{
+		{
+			name:      "invalid user id",
+			userID:    "not-uuid",
+			expectErr: vo.ErrInvalidCredentials,
+		},
+		{
+			name:   "unknown or inactive user",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("SELECT password_hash\\s+FROM users\\s+WHERE id = \\$1 AND status = 'active'").
+					WithArgs(userUUID).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectErr: vo.ErrInvalidCredentials,
+		},
+		{
+			name:   "query error",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("SELECT password_hash").WithArgs(userUUID).WillReturnError(queryErr)
+			},
+			expectErr: queryErr,
+		},
+		{
+			name:   "returns stored hash",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("SELECT password_hash").
+					WithArgs(userUUID).
+					WillReturnRows(sqlmock.NewRows([]string{"password_hash"}).AddRow("hashed"))
+			},
+			expectHash: "hashed",
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewAuthChangePasswordRepository(db, 0)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			hash, err := repo.GetPasswordHash(context.Background(), tc.userID)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			} else {
+				require.NoError(s.T(), err)
+			}
+			assert.Equal(s.T(), tc.expectHash, hash)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *AuthChangePasswordRepositorySuite) TestUpdatePasswordHash_TableDriven() {
+	userUUID := uuid.New()
+	execErr := errors.New("exec failed")
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		expectErr error
+	}{
+		{
+			name: "hash changed since it was verified",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec("UPDATE users").
+					WithArgs(userUUID, "old-hash", "new-hash").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectErr: vo.ErrInvalidCredentials,
+		},
+		{
+			name: "exec error",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec("UPDATE users").
+					WithArgs(userUUID, "old-hash", "new-hash").
+					WillReturnError(execErr)
+			},
+			expectErr: execErr,
+		},
+		{
+			name: "updates when the verified hash is still current",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec("UPDATE users\\s+SET password_hash = \\$3, updated_at = now\\(\\)\\s+WHERE id = \\$1 AND password_hash = \\$2").
+					WithArgs(userUUID, "old-hash", "new-hash").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewAuthChangePasswordRepository(db, 0)
+			tc.setupMock(mockDB)
+
+			err := repo.UpdatePasswordHash(context.Background(), userUUID.String(), "old-hash", "new-hash")
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			} else {
+				require.NoError(s.T(), err)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthChangePasswordRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AuthChangePasswordRepositorySuite))
+}
+
+type WalletCreateRepositorySuite struct{ suite.Suite }
+
+func (s *WalletCreateRepositorySuite) TestCreateWallet_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Now().UTC()
+	insertErr := errors.New("insert failed")
+
+	tests := []struct {
+		name      string
+		walletID  string
+		userID    string
+		setupMock func(sqlmock.Sqlmock)
+		assertion func(error)
+	}{
+		{
+			name:     "invalid wallet id",
+			walletID: "not-uuid",
+			userID:   userUUID.String(),
+			assertion: func(err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "invalid wallet_id")
+			},
+		},
+		{
+			name:     "invalid user id",
+			walletID: walletUUID.String(),
+			userID:   "not-uuid",
+			assertion: func(err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "invalid user_id")
+			},
+		},
+		{
+			name:     "duplicate wallet violates unique user_id",
+			walletID: walletUUID.String(),
+			userID:   userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("INSERT INTO wallets").
+					WithArgs(walletUUID, userUUID, "USD").
+					WillReturnError(&pgconn.PgError{Code: sqlStateUniqueViolation, ConstraintName: "wallets_user_id_key"})
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletAlreadyExists)
+			},
+		},
+		{
+			name:     "wrap insert errors",
+			walletID: walletUUID.String(),
+			userID:   userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("INSERT INTO wallets").
+					WithArgs(walletUUID, userUUID, "USD").
+					WillReturnError(insertErr)
+			},
+			assertion: func(err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "failed to create wallet")
+				assert.ErrorIs(s.T(), err, insertErr)
+				assert.NotErrorIs(s.T(), err, vo.ErrWalletAlreadyExists)
+			},
+		},
+		{
+			name:     "success inserts zero balance wallet",
+			walletID: walletUUID.String(),
+			userID:   userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "balance_minor", "currency", "version", "created_at", "updated_at"}).
+					AddRow(walletUUID, userUUID.String(), int64(0), "USD", int64(0), now, now)
+				mockDB.ExpectQuery("INSERT INTO wallets").
+					WithArgs(walletUUID, userUUID, "USD").
+					WillReturnRows(rows)
+			},
+			assertion: func(err error) {
+				require.NoError(s.T(), err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewWalletCreateRepository(db, 0)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			result, err := repo.CreateWallet(context.Background(), tc.walletID, tc.userID, "USD")
+			tc.assertion(err)
+			if err == nil {
+				assert.Equal(s.T(), walletUUID.String(), result.ID)
+				assert.Equal(s.T(), userUUID.String(), result.UserID)
+				assert.Equal(s.T(), int64(0), result.BalanceMinor)
+				assert.Equal(s.T(), "USD", result.Currency)
+				assert.Equal(s.T(), now, result.CreatedAt)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWalletCreateRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WalletCreateRepositorySuite))
+}
+
+type TransferBalanceRepositorySuite struct{ suite.Suite }
+
+func (s *TransferBalanceRepositorySuite) TestTransferWalletBalance_TableDriven() {
+	userUUID := uuid.New()
+	sourceUUID := uuid.New()
+	destinationUUID := uuid.New()
+	now := time.Now().UTC()
+	creditErr := errors.New("credit failed")
+	reference := sql.NullString{String: "ref-1", Valid: true}
+
+	walletRows := func(walletID uuid.UUID, balance int64, currency string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"wallet_id", "user_id", "balance_minor", "currency", "updated_at"}).
+			AddRow(walletID, userUUID.String(), balance, currency, now)
+	}
+
+	tests := []struct {
+		name          string
+		destinationID string
+		setupMock     func(sqlmock.Sqlmock)
+		assertion     func(domain.WalletTransfer, error)
+	}{
+		{
+			name:          "same wallet rejected",
+			destinationID: sourceUUID.String(),
+			assertion: func(_ domain.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrSameWalletTransfer)
+			},
+		},
+		{
+			name:          "invalid destination wallet id",
+			destinationID: "not-uuid",
+			assertion: func(_ domain.WalletTransfer, err error) {
+				assert.ErrorContains(s.T(), err, "invalid destination_wallet_id")
+			},
+		},
+		{
+			name:          "source wallet not owned by user",
+			destinationID: destinationUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), sourceUUID, userUUID).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectQuery("SELECT status").WithArgs(sourceUUID, userUUID).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(_ domain.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name:          "frozen source wallet rejected",
+			destinationID: destinationUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), sourceUUID, userUUID).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectQuery("SELECT status").WithArgs(sourceUUID, userUUID).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(vo.WalletStatusFrozen))
+				mockDB.ExpectRollback()
+			},
+			assertion: func(_ domain.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletFrozen)
+			},
+		},
+		{
+			name:          "insufficient source balance",
+			destinationID: destinationUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), sourceUUID, userUUID).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectQuery("SELECT status").WithArgs(sourceUUID, userUUID).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(vo.WalletStatusActive))
+				mockDB.ExpectRollback()
+			},
+			assertion: func(_ domain.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInsufficientBalance)
+			},
+		},
+		{
+			name:          "rolls back debit when credit fails",
+			destinationID: destinationUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), sourceUUID, userUUID).WillReturnRows(walletRows(sourceUUID, 900, "IDR"))
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), destinationUUID, userUUID).WillReturnError(creditErr)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(_ domain.WalletTransfer, err error) {
+				require.Error(s.T(), err)
+				assert.ErrorContains(s.T(), err, "failed to credit destination wallet")
+				assert.ErrorIs(s.T(), err, creditErr)
+			},
+		},
+		{
+			name:          "rolls back debit when destination wallet belongs to another user",
+			destinationID: destinationUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), sourceUUID, userUUID).WillReturnRows(walletRows(sourceUUID, 900, "IDR"))
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), destinationUUID, userUUID).WillReturnError(sql.ErrNoRows)
+				mockDB.ExpectRollback()
+			},
+			assertion: func(_ domain.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name:          "rolls back on currency mismatch",
+			destinationID: destinationUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), sourceUUID, userUUID).WillReturnRows(walletRows(sourceUUID, 900, "IDR"))
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), destinationUUID, userUUID).WillReturnRows(walletRows(destinationUUID, 100, "USD"))
+				mockDB.ExpectRollback()
+			},
+			assertion: func(_ domain.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrCurrencyMismatch)
+			},
+		},
+		{
+			name:          "debits and credits atomically with paired ledger rows",
+			destinationID: destinationUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), sourceUUID, userUUID).WillReturnRows(walletRows(sourceUUID, 900, "IDR"))
+				mockDB.ExpectQuery("UPDATE wallets").WithArgs(int64(100), destinationUUID, userUUID).WillReturnRows(walletRows(destinationUUID, 300, "IDR"))
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").WithArgs(sourceUUID, "transfer_out", int64(-100), int64(900), reference, sql.NullString{}).WillReturnResult(sqlmock.NewResult(1, 1))
+				mockDB.ExpectExec("INSERT INTO wallet_ledger").WithArgs(destinationUUID, "transfer_in", int64(100), int64(300), reference, sql.NullString{}).WillReturnResult(sqlmock.NewResult(1, 1))
+				expectWalletNotify(mockDB, userUUID)
+				mockDB.ExpectCommit()
+			},
+			assertion: func(result domain.WalletTransfer, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), domain.WalletTransfer{
+					UserID:                  userUUID.String(),
+					SourceWalletID:          sourceUUID.String(),
+					DestinationWalletID:     destinationUUID.String(),
+					SourceBalanceMinor:      900,
+					DestinationBalanceMinor: 300,
+					Currency:                "IDR",
+					UpdatedAt:               now,
+				}, result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewTransferBalanceRepository(db, nil, 0, TxRetryPolicy{})
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			result, err := repo.TransferWalletBalance(context.Background(), userUUID.String(), sourceUUID.String(), tc.destinationID, 100, "ref-1")
+			tc.assertion(result, err)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransferBalanceRepositorySuite(t *testing.T) {
+	suite.Run(t, new(TransferBalanceRepositorySuite))
+}
+
+type TransactionExportRepositorySuite struct{ suite.Suite }
+
+func (s *TransactionExportRepositorySuite) TestStreamLedgerByUserID_TableDriven() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	now := time.Now().UTC()
+	emitErr := errors.New("client gone")
+	columns := []string{"id", "wallet_id", "entry_type", "amount_minor", "balance_after_minor", "currency", "reference_id", "chain_id", "created_at"}
+	ledgerRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(columns).
+			AddRow("entry-1", walletUUID.String(), "deposit", int64(1000), int64(1000), "IDR", "ref-1", nil, now).
+			AddRow("entry-2", walletUUID.String(), "withdrawal", int64(-400), int64(600), "IDR", nil, "polygon", now.Add(time.Second))
+	}
+
+	tests := []struct {
+		name          string
+		userID        string
+		emitErr       error
+		setupMock     func(mockDB sqlmock.Sqlmock)
+		expectErr     error
+		expectErrText string
+		expectEntries []domain.LedgerEntry
+	}{
+		{
+			name:          "invalid user id",
+			userID:        "not-a-uuid",
+			setupMock:     func(sqlmock.Sqlmock) {},
+			expectErrText: "invalid user_id",
+		},
+		{
+			name:   "streams entries oldest first",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger l\\s+JOIN wallets w").WithArgs(userUUID).WillReturnRows(ledgerRows())
+			},
+			expectEntries: []domain.LedgerEntry{
+				{ID: "entry-1", WalletID: walletUUID.String(), EntryType: "deposit", AmountMinor: 1000, BalanceAfterMinor: 1000, Currency: "IDR", ReferenceID: "ref-1", CreatedAt: now},
+				{ID: "entry-2", WalletID: walletUUID.String(), EntryType: "withdrawal", AmountMinor: -400, BalanceAfterMinor: 600, Currency: "IDR", ChainID: "polygon", CreatedAt: now.Add(time.Second)},
+			},
+		},
+		{
+			name:   "empty ledger emits nothing",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger").WithArgs(userUUID).WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name:    "emit error stops iteration",
+			userID:  userUUID.String(),
+			emitErr: emitErr,
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger").WithArgs(userUUID).WillReturnRows(ledgerRows())
+			},
+			expectErr: emitErr,
+			expectEntries: []domain.LedgerEntry{
+				{ID: "entry-1", WalletID: walletUUID.String(), EntryType: "deposit", AmountMinor: 1000, BalanceAfterMinor: 1000, Currency: "IDR", ReferenceID: "ref-1", CreatedAt: now},
+			},
+		},
+		{
+			name:   "query error",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger").WithArgs(userUUID).WillReturnError(errors.New("db down"))
+			},
+			expectErrText: "failed to query wallet ledger",
+		},
+		{
+			name:   "row iteration error",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				rows := ledgerRows().RowError(1, errors.New("connection reset"))
+				mockDB.ExpectQuery("FROM wallet_ledger").WithArgs(userUUID).WillReturnRows(rows)
+			},
+			expectErrText: "failed to iterate wallet ledger",
+			expectEntries: []domain.LedgerEntry{
+				{ID: "entry-1", WalletID: walletUUID.String(), EntryType: "deposit", AmountMinor: 1000, BalanceAfterMinor: 1000, Currency: "IDR", ReferenceID: "ref-1", CreatedAt: now},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewTransactionExportRepository(db)
+			tc.setupMock(mockDB)
+
+			var entries []domain.LedgerEntry
+			err := repo.StreamLedgerByUserID(context.Background(), tc.userID, func(entry domain.LedgerEntry) error {
+				entries = append(entries, entry)
+				return tc.emitErr
+			})
+
+			switch {
+			case tc.expectErr != nil:
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			case tc.expectErrText != "":
+				require.Error(s.T(), err)
+				assert.Contains(s.T(), err.Error(), tc.expectErrText)
+			default:
+				require.NoError(s.T(), err)
+			}
+			assert.Equal(s.T(), tc.expectEntries, entries)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionExportRepositorySuite(t *testing.T) {
+	suite.Run(t, new(TransactionExportRepositorySuite))
+}
+
+type TransactionHistoryRepositorySuite struct{ suite.Suite }
+
+func (s *TransactionHistoryRepositorySuite) TestListLedgerByUserID_PagesWithoutGapsOrDuplicates() {
+	userUUID := uuid.New()
+	walletUUID := uuid.New()
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	columns := []string{"id", "wallet_id", "entry_type", "amount_minor", "balance_after_minor", "currency", "reference_id", "chain_id", "created_at"}
+
+	// Seven entries, newest first, with ties on created_at that only the id breaks.
+	ledger := make([]domain.LedgerEntry, 0, 7)
+	for i, offset := range []int{5, 4, 4, 4, 2, 1, 1} {
+		ledger = append(ledger, domain.LedgerEntry{
+			ID:        uuid.New().String(),
+			WalletID:  walletUUID.String(),
+			EntryType: "deposit",
+			Currency:  "IDR",
+			CreatedAt: base.Add(time.Duration(offset) * time.Second),
+		})
+		ledger[i].AmountMinor = int64(i + 1)
+	}
+	slices.SortFunc(ledger, func(a, b domain.LedgerEntry) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+
+	// keysetPage plays the database: rows strictly before the cursor, newest first.
+	keysetPage := func(cursor *pagination.Cursor, limit int) *sqlmock.Rows {
+		rows := sqlmock.NewRows(columns)
+		for _, entry := range ledger {
+			if cursor != nil {
+				if entry.CreatedAt.After(cursor.CreatedAt) ||
+					(entry.CreatedAt.Equal(cursor.CreatedAt) && entry.ID >= cursor.ID) {
+					continue
+				}
+			}
+			if limit == 0 {
+				break
+			}
+			rows.AddRow(entry.ID, entry.WalletID, entry.EntryType, entry.AmountMinor, int64(0), entry.Currency, nil, nil, entry.CreatedAt)
+			limit--
+		}
+		return rows
+	}
+
+	db, mockDB := newSQLXMock(s.T())
+	repo := NewTransactionHistoryRepository(db, 0)
+
+	var (
+		cursor *pagination.Cursor
+		seen   []domain.LedgerEntry
+		pages  int
+	)
+	for {
+		if cursor == nil {
+			mockDB.ExpectQuery("ORDER BY l.created_at DESC, l.id DESC\\s+LIMIT \\$2").
+				WithArgs(userUUID, 3).WillReturnRows(keysetPage(nil, 3))
+		} else {
+			mockDB.ExpectQuery("\\(l.created_at, l.id\\) < \\(\\$2, \\$3\\)").
+				WithArgs(userUUID, cursor.CreatedAt, uuid.MustParse(cursor.ID), 3).WillReturnRows(keysetPage(cursor, 3))
+		}
+
+		page, err := repo.ListLedgerByUserID(context.Background(), userUUID.String(), cursor, 3)
+		require.NoError(s.T(), err)
+		pages++
+		if len(page) == 0 {
+			break
+		}
+
+		seen = append(seen, page...)
+		last := page[len(page)-1]
+		cursor = &pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	assert.Equal(s.T(), 4, pages)
+	assert.Equal(s.T(), ledger, seen)
+	require.NoError(s.T(), mockDB.ExpectationsWereMet())
+}
+
+func (s *TransactionHistoryRepositorySuite) TestListLedgerByUserID_Errors_TableDriven() {
+	userUUID := uuid.New()
+	queryErr := errors.New("query failed")
+
+	tests := []struct {
+		name          string
+		userID        string
+		cursor        *pagination.Cursor
+		setupMock     func(sqlmock.Sqlmock)
+		expectErr     error
+		expectErrText string
+	}{
+		{
+			name:          "invalid user id",
+			userID:        "not-a-uuid",
+			expectErrText: "invalid user_id",
+		},
+		{
+			name:      "cursor with non-uuid id",
+			userID:    userUUID.String(),
+			cursor:    &pagination.Cursor{CreatedAt: time.Now().UTC(), ID: "entry-1"},
+			expectErr: pagination.ErrInvalidCursor,
+		},
+		{
+			name:   "query error",
+			userID: userUUID.String(),
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("FROM wallet_ledger").WithArgs(userUUID, 10).WillReturnError(queryErr)
+			},
+			expectErr: queryErr,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewTransactionHistoryRepository(db, 0)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			entries, err := repo.ListLedgerByUserID(context.Background(), tc.userID, tc.cursor, 10)
+			require.Error(s.T(), err)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			}
+			if tc.expectErrText != "" {
+				assert.ErrorContains(s.T(), err, tc.expectErrText)
+			}
+			assert.Nil(s.T(), entries)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionHistoryRepositorySuite(t *testing.T) {
+	suite.Run(t, new(TransactionHistoryRepositorySuite))
+}
+
+type QueryTimeoutRepositorySuite struct{ suite.Suite }
+
+func (s *QueryTimeoutRepositorySuite) TestQueryTimeout_TableDriven() {
+	userUUID := uuid.New()
+	const timeout = QueryTimeout(20 * time.Millisecond)
+	const delay = 200 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		call      func(*sqlx.DB) error
+	}{
+		{
+			name: "auth login query",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("SELECT id::text AS id").WillDelayFor(delay).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user-1"))
+			},
+			call: func(db *sqlx.DB) error {
+				_, err := NewAuthLoginRepository(db, timeout).GetUserAuthByEmail(context.Background(), "user@example.com")
+				return err
+			},
+		},
+		{
+			name: "balance inquiry query",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("SELECT").WillDelayFor(delay).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userUUID.String()))
+			},
+			call: func(db *sqlx.DB) error {
+				_, err := NewInquiryCheckBalanceRepository(db, timeout).GetWalletBalanceByUserID(context.Background(), userUUID.String())
+				return err
+			},
+		},
+		{
+			name: "withdraw transaction holding the wallet lock",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery("SELECT\\s+id AS wallet_id").WillDelayFor(delay).
+					WillReturnRows(sqlmock.NewRows([]string{"wallet_id"}).AddRow(uuid.New()))
+				mockDB.ExpectRollback()
+			},
+			call: func(db *sqlx.DB) error {
+				_, err := NewWithdrawBalanceRepository(db, nil, timeout, TxRetryPolicy{}).
+					WithdrawWalletBalanceByUserID(context.Background(), userUUID.String(), 100, "", "ref-1", 0, domain.DailyWithdrawLimit{})
+				return err
+			},
+		},
+		{
+			name: "deposit transaction",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin().WillDelayFor(delay)
+			},
+			call: func(db *sqlx.DB) error {
+				_, err := NewDepositBalanceRepository(db, timeout).DepositWalletBalanceByUserID(context.Background(), userUUID.String(), 100, "ref-1")
+				return err
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			tc.setupMock(mockDB)
+
+			err := tc.call(db)
+			require.Error(s.T(), err)
+			assert.ErrorIs(s.T(), err, context.DeadlineExceeded)
+		})
+	}
+}
+
+func TestQueryTimeoutRepositorySuite(t *testing.T) {
+	suite.Run(t, new(QueryTimeoutRepositorySuite))
+}
+
+type fakeNotificationConn struct {
+	listenErr     error
+	notifications chan *pgconn.Notification
+	execs         []string
+	closed        bool
+}
+
+func newFakeNotificationConn() *fakeNotificationConn {
+	return &fakeNotificationConn{notifications: make(chan *pgconn.Notification, 4)}
+}
+
+func (c *fakeNotificationConn) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	c.execs = append(c.execs, sql)
+	return pgconn.CommandTag{}, c.listenErr
+}
+
+func (c *fakeNotificationConn) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case notification, ok := <-c.notifications:
+		if !ok {
+			return nil, errors.New("conn closed")
+		}
+		return notification, nil
+	}
+}
+
+func (c *fakeNotificationConn) Close(context.Context) error {
+	c.closed = true
+	return nil
+}
+
+func newTestBalanceChangeListener(conns ...*fakeNotificationConn) *BalanceChangeListener {
+	calls := 0
+	return &BalanceChangeListener{
+		connect: func(context.Context) (notificationConn, error) {
+			if calls >= len(conns) {
+				return nil, errors.New("no more connections")
+			}
+			conn := conns[calls]
+			calls++
+			return conn, nil
+		},
+		reconnectBackoff: time.Millisecond,
+		now:              func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}
+}
+
+func receiveBalanceChange(t *testing.T, events <-chan domain.BalanceChange) domain.BalanceChange {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "events channel closed")
+		return event
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for balance change")
+		return domain.BalanceChange{}
+	}
+}
+
+type BalanceChangeListenerSuite struct{ suite.Suite }
+
+func (s *BalanceChangeListenerSuite) TestListen_TableDriven() {
+	tests := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{
+			name: "notification is emitted as balance change",
+			run: func(t *testing.T) {
+				conn := newFakeNotificationConn()
+				listener := newTestBalanceChangeListener(conn)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				events, err := listener.Listen(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"LISTEN " + WalletChangesChannel}, conn.execs)
+
+				conn.notifications <- &pgconn.Notification{Channel: WalletChangesChannel, Payload: "user-1"}
+				event := receiveBalanceChange(t, events)
+				assert.Equal(t, "user-1", event.UserID)
+				assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), event.ReceivedAt)
+			},
+		},
+		{
+			name: "cancelled context closes the channel",
+			run: func(t *testing.T) {
+				conn := newFakeNotificationConn()
+				listener := newTestBalanceChangeListener(conn)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				events, err := listener.Listen(ctx)
+				require.NoError(t, err)
+
+				cancel()
+				select {
+				case _, ok := <-events:
+					assert.False(t, ok)
+				case <-time.After(time.Second):
+					require.FailNow(t, "events channel not closed")
+				}
+				assert.True(t, conn.closed)
+			},
+		},
+		{
+			name: "lost connection is replaced",
+			run: func(t *testing.T) {
+				first := newFakeNotificationConn()
+				second := newFakeNotificationConn()
+				listener := newTestBalanceChangeListener(first, second)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				events, err := listener.Listen(ctx)
+				require.NoError(t, err)
+
+				close(first.notifications)
+				second.notifications <- &pgconn.Notification{Channel: WalletChangesChannel, Payload: "user-2"}
+				assert.Equal(t, "user-2", receiveBalanceChange(t, events).UserID)
+				assert.Equal(t, []string{"LISTEN " + WalletChangesChannel}, second.execs)
+			},
+		},
+		{
+			name: "failed LISTEN is returned",
+			run: func(t *testing.T) {
+				conn := newFakeNotificationConn()
+				conn.listenErr = errors.New("permission denied")
+				listener := newTestBalanceChangeListener(conn)
+
+				events, err := listener.Listen(context.Background())
+				require.Error(t, err)
+				assert.Nil(t, events)
+				assert.True(t, conn.closed)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			tc.run(s.T())
+		})
+	}
+}
+
+func TestBalanceChangeListenerSuite(t *testing.T) {
+	suite.Run(t, new(BalanceChangeListenerSuite))
+}
+
+type OutboxRepositorySuite struct{ suite.Suite }
+
+func (s *OutboxRepositorySuite) TestClaimUnsent_TableDriven() {
+	queryErr := errors.New("query failed")
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		expectIDs []int64
+		expectErr error
+	}{
+		{
+			name: "returns claimed rows in id order",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("UPDATE outbox\\s+SET available_at").WithArgs(10, float64(30)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "payload"}).
+						AddRow(int64(7), []byte(`{"transaction_id":"ref-7"}`)).
+						AddRow(int64(3), []byte(`{"transaction_id":"ref-3"}`)))
+			},
+			expectIDs: []int64{3, 7},
+		},
+		{
+			name: "no unsent rows",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("UPDATE outbox\\s+SET available_at").WithArgs(10, float64(30)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "payload"}))
+			},
+			expectIDs: []int64{},
+		},
+		{
+			name: "query failure",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery("UPDATE outbox\\s+SET available_at").WithArgs(10, float64(30)).WillReturnError(queryErr)
+			},
+			expectErr: queryErr,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			db, mockDB := newSQLXMock(s.T())
+			repo := NewOutboxRepository(db)
+			tc.setupMock(mockDB)
+
+			messages, err := repo.ClaimUnsent(context.Background(), 10, 30*time.Second)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			} else {
+				require.NoError(s.T(), err)
+				ids := make([]int64, 0, len(messages))
+				for _, message := range messages {
+					ids = append(ids, message.ID)
+				}
+				assert.Equal(s.T(), tc.expectIDs, ids)
+			}
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *OutboxRepositorySuite) TestMarkSentFailedAndDead() {
+	markErr := errors.New("update failed")
+	db, mockDB := newSQLXMock(s.T())
+	repo := NewOutboxRepository(db)
+
+	mockDB.ExpectExec("UPDATE outbox SET sent_at = now\\(\\)").WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectExec("UPDATE outbox SET last_error").WithArgs(int64(4), "broker unavailable").WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectExec("UPDATE outbox SET failed_at = now\\(\\), last_error").WithArgs(int64(6), "undecodable").WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectExec("UPDATE outbox SET sent_at = now\\(\\)").WithArgs(int64(5)).WillReturnError(markErr)
+
+	require.NoError(s.T(), repo.MarkSent(context.Background(), 3))
+	require.NoError(s.T(), repo.MarkFailed(context.Background(), 4, "broker unavailable"))
+	require.NoError(s.T(), repo.MarkDead(context.Background(), 6, "undecodable"))
+	assert.ErrorIs(s.T(), repo.MarkSent(context.Background(), 5), markErr)
+	require.NoError(s.T(), mockDB.ExpectationsWereMet())
+}
+
+func TestOutboxRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OutboxRepositorySuite))
 }
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain"
+)
+
+type TransactionExportRepository struct {
+	db *sqlx.DB
+}
+
+func NewTransactionExportRepository(db *sqlx.DB) *TransactionExportRepository {
+	return &TransactionExportRepository{db: db}
+}
+
+type ledgerEntryRow struct {
+	ID                string         `db:"id"`
+	WalletID          string         `db:"wallet_id"`
+	EntryType         string         `db:"entry_type"`
+	AmountMinor       int64          `db:"amount_minor"`
+	BalanceAfterMinor int64          `db:"balance_after_minor"`
+	Currency          string         `db:"currency"`
+	ReferenceID       sql.NullString `db:"reference_id"`
+	ChainID           sql.NullString `db:"chain_id"`
+	CreatedAt         time.Time      `db:"created_at"`
+}
+
+func (row ledgerEntryRow) toDomain() domain.LedgerEntry {
+	return domain.LedgerEntry{
+		ID:                row.ID,
+		WalletID:          row.WalletID,
+		EntryType:         row.EntryType,
+		AmountMinor:       row.AmountMinor,
+		BalanceAfterMinor: row.BalanceAfterMinor,
+		Currency:          row.Currency,
+		ReferenceID:       row.ReferenceID.String,
+		ChainID:           row.ChainID.String,
+		CreatedAt:         row.CreatedAt,
+	}
+}
+
+// StreamLedgerByUserID calls emit for every ledger entry of the user's wallet, oldest
+// first, reading one row at a time so the history is never held in memory. An error
+// from emit stops the iteration and is returned as is. The export is deliberately not
+// bounded by the query timeout, since its duration grows with the history; callers
+// cancel it through ctx.
+func (r *TransactionExportRepository) StreamLedgerByUserID(ctx context.Context, userID string, emit func(domain.LedgerEntry) error) error {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	const query = `
+		SELECT
+			l.id::text AS id,
+			l.wallet_id::text AS wallet_id,
+			l.entry_type,
+			l.amount_minor,
+			l.balance_after_minor,
+			w.currency,
+			l.reference_id,
+			l.chain_id,
+			l.created_at
+		FROM wallet_ledger l
+		JOIN wallets w ON w.id = l.wallet_id
+		WHERE w.user_id = $1
+		ORDER BY l.created_at, l.id
+	`
+
+	rows, err := r.db.QueryxContext(ctx, query, parsedUserID)
+	if err != nil {
+		return fmt.Errorf("repository: failed to query wallet ledger: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row ledgerEntryRow
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("repository: failed to scan wallet ledger: %w", err)
+		}
+
+		if err := emit(row.toDomain()); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("repository: failed to iterate wallet ledger: %w", err)
+	}
+
+	return nil
+}
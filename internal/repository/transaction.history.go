@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/shared/pagination"
+)
+
+const listLedgerSelect = `
+	SELECT
+		l.id::text AS id,
+		l.wallet_id::text AS wallet_id,
+		l.entry_type,
+		l.amount_minor,
+		l.balance_after_minor,
+		w.currency,
+		l.reference_id,
+		l.chain_id,
+		l.created_at
+	FROM wallet_ledger l
+	JOIN wallets w ON w.id = l.wallet_id
+	WHERE w.user_id = $1`
+
+const listLedgerFirstPageQuery = listLedgerSelect + `
+	ORDER BY l.created_at DESC, l.id DESC
+	LIMIT $2`
+
+// listLedgerAfterCursorQuery seeks past the cursor with a row comparison, so the page
+// costs the same however deep into the history it is.
+const listLedgerAfterCursorQuery = listLedgerSelect + `
+	  AND (l.created_at, l.id) < ($2, $3)
+	ORDER BY l.created_at DESC, l.id DESC
+	LIMIT $4`
+
+type TransactionHistoryRepository struct {
+	db           *sqlx.DB
+	queryTimeout QueryTimeout
+}
+
+func NewTransactionHistoryRepository(db *sqlx.DB, queryTimeout QueryTimeout) *TransactionHistoryRepository {
+	return &TransactionHistoryRepository{db: db, queryTimeout: queryTimeout}
+}
+
+// ListLedgerByUserID returns up to limit ledger entries of the user's wallet, newest
+// first, starting strictly after cursor (nil for the first page).
+func (r *TransactionHistoryRepository) ListLedgerByUserID(ctx context.Context, userID string, cursor *pagination.Cursor, limit int) (_ []domain.LedgerEntry, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	var cursorID uuid.UUID
+	if cursor != nil {
+		if cursorID, err = uuid.Parse(cursor.ID); err != nil {
+			return nil, fmt.Errorf("repository: invalid cursor id: %w", pagination.ErrInvalidCursor)
+		}
+	}
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	rows := make([]ledgerEntryRow, 0, limit)
+	if cursor == nil {
+		err = r.db.SelectContext(ctx, &rows, listLedgerFirstPageQuery, parsedUserID, limit)
+	} else {
+		err = r.db.SelectContext(ctx, &rows, listLedgerAfterCursorQuery, parsedUserID, cursor.CreatedAt, cursorID, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list wallet ledger: %w", err)
+	}
+
+	entries := make([]domain.LedgerEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, row.toDomain())
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedsqlc "github.com/joshuarp/withdraw-api/internal/shared/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type TransferBalanceRepository struct {
+	db           *sqlx.DB
+	queries      *sharedsqlc.Queries
+	tracer       trace.Tracer
+	queryTimeout QueryTimeout
+	txRetry      TxRetryPolicy
+}
+
+func NewTransferBalanceRepository(db *sqlx.DB, tracer trace.Tracer, queryTimeout QueryTimeout, txRetry TxRetryPolicy) *TransferBalanceRepository {
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer("")
+	}
+
+	return &TransferBalanceRepository{db: db, queries: sharedsqlc.New(db.DB), tracer: tracer, queryTimeout: queryTimeout, txRetry: txRetry}
+}
+
+func (r *TransferBalanceRepository) TransferWalletBalance(ctx context.Context, userID, sourceWalletID, destinationWalletID string, amountMinor int64, referenceID string) (result domain.WalletTransfer, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.WalletTransfer{}, fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	parsedSourceID, err := uuid.Parse(sourceWalletID)
+	if err != nil {
+		return domain.WalletTransfer{}, fmt.Errorf("repository: invalid source_wallet_id: %w", err)
+	}
+
+	parsedDestinationID, err := uuid.Parse(destinationWalletID)
+	if err != nil {
+		return domain.WalletTransfer{}, fmt.Errorf("repository: invalid destination_wallet_id: %w", err)
+	}
+
+	if parsedSourceID == parsedDestinationID {
+		return domain.WalletTransfer{}, vo.ErrSameWalletTransfer
+	}
+
+	if amountMinor <= 0 {
+		return domain.WalletTransfer{}, vo.ErrInvalidAmount
+	}
+
+	ctx, span := r.tracer.Start(ctx, "repository.TransferWalletBalance",
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.Int64("transfer.amount_minor", amountMinor),
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	for attempt := 0; ; attempt++ {
+		result, err = r.transferOnce(ctx, parsedUserID, parsedSourceID, parsedDestinationID, amountMinor, referenceID)
+		if err == nil || !isRetryableTxError(err) || attempt >= r.txRetry.MaxRetries {
+			return result, err
+		}
+
+		span.AddEvent("retrying transfer transaction", trace.WithAttributes(attribute.Int("transfer.attempt", attempt+1)))
+		if waitErr := r.txRetry.wait(ctx, attempt); waitErr != nil {
+			return domain.WalletTransfer{}, err
+		}
+	}
+}
+
+func (r *TransferBalanceRepository) transferOnce(ctx context.Context, userID, sourceID, destinationID uuid.UUID, amountMinor int64, referenceID string) (domain.WalletTransfer, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.WalletTransfer{}, fmt.Errorf("repository: failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queriesWithTx := r.queries.WithTx(tx.Tx)
+	debited, err := queriesWithTx.DebitWalletBalanceByID(ctx, sharedsqlc.DebitWalletBalanceByIDParams{
+		AmountMinor: amountMinor,
+		WalletID:    sourceID,
+		UserID:      userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			status, statusErr := queriesWithTx.GetWalletStatusByIDAndUserID(ctx, sharedsqlc.GetWalletStatusByIDAndUserIDParams{WalletID: sourceID, UserID: userID})
+			if statusErr == sql.ErrNoRows {
+				return domain.WalletTransfer{}, vo.ErrWalletNotFound
+			}
+			if statusErr != nil {
+				return domain.WalletTransfer{}, fmt.Errorf("repository: failed to check wallet status: %w", statusErr)
+			}
+
+			// A frozen wallet cannot move funds, as with withdrawals.
+			if status == vo.WalletStatusFrozen {
+				return domain.WalletTransfer{}, vo.ErrWalletFrozen
+			}
+
+			return domain.WalletTransfer{}, vo.ErrInsufficientBalance
+		}
+
+		return domain.WalletTransfer{}, fmt.Errorf("repository: failed to debit source wallet: %w", err)
+	}
+
+	credited, err := queriesWithTx.CreditWalletBalanceByID(ctx, sharedsqlc.CreditWalletBalanceByIDParams{
+		AmountMinor: amountMinor,
+		WalletID:    destinationID,
+		UserID:      userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.WalletTransfer{}, vo.ErrWalletNotFound
+		}
+
+		return domain.WalletTransfer{}, fmt.Errorf("repository: failed to credit destination wallet: %w", err)
+	}
+
+	if debited.Currency != credited.Currency {
+		return domain.WalletTransfer{}, vo.ErrCurrencyMismatch
+	}
+
+	var reference sql.NullString
+	if referenceID != "" {
+		reference = sql.NullString{String: referenceID, Valid: true}
+	}
+
+	if err := queriesWithTx.InsertWalletLedger(ctx, sharedsqlc.InsertWalletLedgerParams{
+		WalletID:          debited.WalletID,
+		EntryType:         "transfer_out",
+		AmountMinor:       -amountMinor,
+		BalanceAfterMinor: debited.BalanceMinor,
+		ReferenceID:       reference,
+	}); err != nil {
+		return domain.WalletTransfer{}, fmt.Errorf("repository: failed to insert transfer_out ledger: %w", err)
+	}
+
+	if err := queriesWithTx.InsertWalletLedger(ctx, sharedsqlc.InsertWalletLedgerParams{
+		WalletID:          credited.WalletID,
+		EntryType:         "transfer_in",
+		AmountMinor:       amountMinor,
+		BalanceAfterMinor: credited.BalanceMinor,
+		ReferenceID:       reference,
+	}); err != nil {
+		return domain.WalletTransfer{}, fmt.Errorf("repository: failed to insert transfer_in ledger: %w", err)
+	}
+
+	notifyWalletChange(ctx, tx, userID.String())
+
+	if err := tx.Commit(); err != nil {
+		return domain.WalletTransfer{}, fmt.Errorf("repository: failed to commit transaction: %w", err)
+	}
+
+	return domain.WalletTransfer{
+		UserID:                  debited.UserID,
+		SourceWalletID:          debited.WalletID.String(),
+		DestinationWalletID:     credited.WalletID.String(),
+		SourceBalanceMinor:      debited.BalanceMinor,
+		DestinationBalanceMinor: credited.BalanceMinor,
+		Currency:                debited.Currency,
+		UpdatedAt:               credited.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+
+	defaultTxRetryBaseBackoff = 10 * time.Millisecond
+)
+
+type TxRetryPolicy struct {
+	MaxRetries  int
+	BaseBackoff time.Duration
+}
+
+func (p TxRetryPolicy) wait(ctx context.Context, attempt int) error {
+	base := p.BaseBackoff
+	if base <= 0 {
+		base = defaultTxRetryBaseBackoff
+	}
+
+	backoff := base << attempt
+	jittered := backoff/2 + time.Duration(rand.Int64N(int64(backoff/2)+1))
+
+	timer := time.NewTimer(jittered)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
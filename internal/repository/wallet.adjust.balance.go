@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedsqlc "github.com/joshuarp/withdraw-api/internal/shared/sqlc"
+)
+
+type WalletAdjustBalanceRepository struct {
+	db           *sqlx.DB
+	queries      *sharedsqlc.Queries
+	queryTimeout QueryTimeout
+}
+
+func NewWalletAdjustBalanceRepository(db *sqlx.DB, queryTimeout QueryTimeout) *WalletAdjustBalanceRepository {
+	return &WalletAdjustBalanceRepository{db: db, queries: sharedsqlc.New(db.DB), queryTimeout: queryTimeout}
+}
+
+func (r *WalletAdjustBalanceRepository) AdjustWalletBalanceByUserID(ctx context.Context, userID string, deltaMinor int64, referenceID string) (_ domain.WalletBalance, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: invalid user_id: %w", vo.ErrWalletNotFound)
+	}
+
+	if deltaMinor == 0 {
+		return domain.WalletBalance{}, vo.ErrInvalidAmount
+	}
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queriesWithTx := r.queries.WithTx(tx.Tx)
+	adjustedWallet, err := queriesWithTx.AdjustWalletBalanceByUserID(ctx, sharedsqlc.AdjustWalletBalanceByUserIDParams{
+		DeltaMinor: deltaMinor,
+		UserID:     parsedUserID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			exists, existsErr := queriesWithTx.HasWalletByUserID(ctx, parsedUserID)
+			if existsErr != nil {
+				return domain.WalletBalance{}, fmt.Errorf("repository: failed to check wallet existence: %w", existsErr)
+			}
+
+			if !exists {
+				return domain.WalletBalance{}, vo.ErrWalletNotFound
+			}
+
+			return domain.WalletBalance{}, vo.ErrInsufficientBalance
+		}
+
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to adjust wallet balance: %w", err)
+	}
+
+	ledgerParams := sharedsqlc.InsertWalletLedgerParams{
+		WalletID:          adjustedWallet.WalletID,
+		EntryType:         "adjustment",
+		AmountMinor:       deltaMinor,
+		BalanceAfterMinor: adjustedWallet.BalanceMinor,
+	}
+
+	if referenceID != "" {
+		ledgerParams.ReferenceID = sql.NullString{String: referenceID, Valid: true}
+	}
+
+	if err := queriesWithTx.InsertWalletLedger(ctx, ledgerParams); err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to insert wallet ledger: %w", err)
+	}
+
+	notifyWalletChange(ctx, tx, parsedUserID.String())
+
+	if err := tx.Commit(); err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to commit transaction: %w", err)
+	}
+
+	return domain.WalletBalance{
+		UserID:       adjustedWallet.UserID,
+		BalanceMinor: adjustedWallet.BalanceMinor,
+		Currency:     adjustedWallet.Currency,
+		UpdatedAt:    adjustedWallet.UpdatedAt,
+	}, nil
+}
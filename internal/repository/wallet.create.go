@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedsqlc "github.com/joshuarp/withdraw-api/internal/shared/sqlc"
+)
+
+const sqlStateUniqueViolation = "23505"
+
+type WalletCreateRepository struct {
+	db           *sqlx.DB
+	queries      *sharedsqlc.Queries
+	queryTimeout QueryTimeout
+}
+
+func NewWalletCreateRepository(db *sqlx.DB, queryTimeout QueryTimeout) *WalletCreateRepository {
+	return &WalletCreateRepository{db: db, queries: sharedsqlc.New(db.DB), queryTimeout: queryTimeout}
+}
+
+// CreateWallet inserts an empty wallet for userID. A user owns at most one wallet, so the
+// wallets.user_id unique constraint surfaces as vo.ErrWalletAlreadyExists.
+func (r *WalletCreateRepository) CreateWallet(ctx context.Context, walletID, userID, currency string) (_ domain.Wallet, err error) {
+	parsedWalletID, err := uuid.Parse(walletID)
+	if err != nil {
+		return domain.Wallet{}, fmt.Errorf("repository: invalid wallet_id: %w", err)
+	}
+
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.Wallet{}, fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	createdWallet, err := r.queries.CreateWallet(ctx, sharedsqlc.CreateWalletParams{
+		ID:       parsedWalletID,
+		UserID:   parsedUserID,
+		Currency: currency,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.Wallet{}, vo.ErrWalletAlreadyExists
+		}
+		return domain.Wallet{}, fmt.Errorf("repository: failed to create wallet: %w", err)
+	}
+
+	return domain.Wallet{
+		ID:           createdWallet.ID.String(),
+		UserID:       createdWallet.UserID,
+		BalanceMinor: createdWallet.BalanceMinor,
+		Currency:     createdWallet.Currency,
+		Version:      createdWallet.Version,
+		CreatedAt:    createdWallet.CreatedAt,
+		UpdatedAt:    createdWallet.UpdatedAt,
+	}, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == sqlStateUniqueViolation
+}
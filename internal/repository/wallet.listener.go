@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/joshuarp/withdraw-api/internal/domain"
+)
+
+const (
+	defaultListenerReconnectBackoff    = 100 * time.Millisecond
+	defaultListenerMaxReconnectBackoff = 5 * time.Second
+)
+
+type notificationConn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	WaitForNotification(ctx context.Context) (*pgconn.Notification, error)
+	Close(ctx context.Context) error
+}
+
+// BalanceChangeListener LISTENs on WalletChangesChannel over a dedicated connection
+// and turns each notification into a domain.BalanceChange.
+type BalanceChangeListener struct {
+	connect          func(ctx context.Context) (notificationConn, error)
+	reconnectBackoff time.Duration
+	now              func() time.Time
+}
+
+func NewBalanceChangeListener(dsn string) *BalanceChangeListener {
+	return &BalanceChangeListener{
+		connect: func(ctx context.Context) (notificationConn, error) {
+			return pgx.Connect(ctx, dsn)
+		},
+		reconnectBackoff: defaultListenerReconnectBackoff,
+		now:              time.Now,
+	}
+}
+
+// Listen subscribes to WalletChangesChannel and streams changes until ctx is done,
+// after which the returned channel is closed. A failed first subscription is returned
+// as an error; later connection losses are retried with backoff. Notifications sent
+// while reconnecting are lost, so consumers should treat events as hints.
+func (l *BalanceChangeListener) Listen(ctx context.Context) (<-chan domain.BalanceChange, error) {
+	conn, err := l.subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan domain.BalanceChange)
+	go l.run(ctx, conn, events)
+
+	return events, nil
+}
+
+func (l *BalanceChangeListener) subscribe(ctx context.Context) (notificationConn, error) {
+	conn, err := l.connect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to connect balance change listener: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, "LISTEN "+WalletChangesChannel); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, fmt.Errorf("repository: failed to listen on %s: %w", WalletChangesChannel, err)
+	}
+
+	return conn, nil
+}
+
+func (l *BalanceChangeListener) run(ctx context.Context, conn notificationConn, events chan<- domain.BalanceChange) {
+	defer close(events)
+	defer func() {
+		if conn != nil {
+			_ = conn.Close(context.Background())
+		}
+	}()
+
+	backoff := l.reconnectBackoff
+	for {
+		if conn == nil {
+			if !sleepContext(ctx, backoff) {
+				return
+			}
+
+			backoff = min(backoff*2, defaultListenerMaxReconnectBackoff)
+
+			var err error
+			if conn, err = l.subscribe(ctx); err != nil {
+				continue
+			}
+
+			backoff = l.reconnectBackoff
+		}
+
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			_ = conn.Close(context.Background())
+			conn = nil
+			continue
+		}
+
+		select {
+		case events <- domain.BalanceChange{UserID: notification.Payload, ReceivedAt: l.now().UTC()}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// WalletChangesChannel is the Postgres NOTIFY channel announcing balance changes.
+// The payload is the id of the user whose wallet changed.
+const WalletChangesChannel = "wallet_changes"
+
+// notifyWalletChange queues a NOTIFY on WalletChangesChannel inside tx, so listeners
+// only hear about it once the balance change commits. It is best-effort: the notify
+// runs under a savepoint and a failure is rolled back without aborting tx.
+func notifyWalletChange(ctx context.Context, tx *sqlx.Tx, userID string) {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT wallet_notify"); err != nil {
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_notify($1, $2)", WalletChangesChannel, userID); err != nil {
+		_, _ = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT wallet_notify")
+	}
+}
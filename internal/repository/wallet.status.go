@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedsqlc "github.com/joshuarp/withdraw-api/internal/shared/sqlc"
+)
+
+type WalletStatusRepository struct {
+	queries      *sharedsqlc.Queries
+	queryTimeout QueryTimeout
+}
+
+func NewWalletStatusRepository(db *sqlx.DB, queryTimeout QueryTimeout) *WalletStatusRepository {
+	return &WalletStatusRepository{queries: sharedsqlc.New(db.DB), queryTimeout: queryTimeout}
+}
+
+// SetWalletStatusByUserID freezes or unfreezes the wallet of userID. The update bumps the
+// wallet version, so a withdrawal that already read the old status fails its version check
+// instead of slipping through.
+func (r *WalletStatusRepository) SetWalletStatusByUserID(ctx context.Context, userID, status string) (_ domain.WalletStatus, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.WalletStatus{}, fmt.Errorf("repository: invalid user_id: %w", vo.ErrWalletNotFound)
+	}
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	updated, err := r.queries.SetWalletStatusByUserID(ctx, sharedsqlc.SetWalletStatusByUserIDParams{
+		Status: status,
+		UserID: parsedUserID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.WalletStatus{}, vo.ErrWalletNotFound
+		}
+		return domain.WalletStatus{}, fmt.Errorf("repository: failed to set wallet status: %w", err)
+	}
+
+	return domain.WalletStatus{
+		UserID:    updated.UserID,
+		Status:    updated.Status,
+		UpdatedAt: updated.UpdatedAt,
+	}, nil
+}
@@ -9,28 +9,99 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/joshuarp/withdraw-api/internal/domain"
 	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedevents "github.com/joshuarp/withdraw-api/internal/shared/events"
 	sharedsqlc "github.com/joshuarp/withdraw-api/internal/shared/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 type WithdrawBalanceRepository struct {
-	db      *sqlx.DB
-	queries *sharedsqlc.Queries
+	db           *sqlx.DB
+	queries      *sharedsqlc.Queries
+	tracer       trace.Tracer
+	queryTimeout QueryTimeout
+	txRetry      TxRetryPolicy
 }
 
-func NewWithdrawBalanceRepository(db *sqlx.DB) *WithdrawBalanceRepository {
-	return &WithdrawBalanceRepository{db: db, queries: sharedsqlc.New(db.DB)}
+func NewWithdrawBalanceRepository(db *sqlx.DB, tracer trace.Tracer, queryTimeout QueryTimeout, txRetry TxRetryPolicy) *WithdrawBalanceRepository {
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer("")
+	}
+
+	return &WithdrawBalanceRepository{db: db, queries: sharedsqlc.New(db.DB), tracer: tracer, queryTimeout: queryTimeout, txRetry: txRetry}
+}
+
+func (r *WithdrawBalanceRepository) GetWalletBalanceByUserID(ctx context.Context, userID string) (_ domain.WalletBalance, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	balanceRow, err := r.queries.GetWalletBalanceByUserID(ctx, parsedUserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.WalletBalance{}, vo.ErrWalletNotFound
+		}
+		return domain.WalletBalance{}, fmt.Errorf("repository: get wallet balance by user_id failed: %w", err)
+	}
+
+	return domain.WalletBalance{
+		UserID:       balanceRow.UserID,
+		BalanceMinor: balanceRow.BalanceMinor,
+		Currency:     balanceRow.Currency,
+		UpdatedAt:    balanceRow.UpdatedAt,
+	}, nil
 }
 
-func (r *WithdrawBalanceRepository) WithdrawWalletBalanceByUserID(ctx context.Context, userID string, amountMinor int64, chainID string) (domain.WalletBalance, error) {
+func (r *WithdrawBalanceRepository) WithdrawWalletBalanceByUserID(ctx context.Context, userID string, amountMinor int64, chainID, referenceID string, feeMinor int64, dailyLimit domain.DailyWithdrawLimit) (result domain.WalletBalance, err error) {
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
 		return domain.WalletBalance{}, fmt.Errorf("repository: invalid user_id: %w", err)
 	}
 
-	if amountMinor <= 0 {
+	if amountMinor <= 0 || feeMinor < 0 {
 		return domain.WalletBalance{}, vo.ErrInvalidAmount
 	}
 
+	ctx, span := r.tracer.Start(ctx, "repository.WithdrawWalletBalanceByUserID",
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.Int64("withdraw.amount_minor", amountMinor),
+			attribute.Int64("withdraw.fee_minor", feeMinor),
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	for attempt := 0; ; attempt++ {
+		result, err = r.withdrawOnce(ctx, parsedUserID, amountMinor, chainID, referenceID, feeMinor, dailyLimit)
+		if err == nil || !isRetryableTxError(err) || attempt >= r.txRetry.MaxRetries {
+			return result, err
+		}
+
+		span.AddEvent("retrying withdraw transaction", trace.WithAttributes(attribute.Int("withdraw.attempt", attempt+1)))
+		if waitErr := r.txRetry.wait(ctx, attempt); waitErr != nil {
+			return domain.WalletBalance{}, err
+		}
+	}
+}
+
+func (r *WithdrawBalanceRepository) withdrawOnce(ctx context.Context, parsedUserID uuid.UUID, amountMinor int64, chainID, referenceID string, feeMinor int64, dailyLimit domain.DailyWithdrawLimit) (domain.WalletBalance, error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return domain.WalletBalance{}, fmt.Errorf("repository: failed to start transaction: %w", err)
@@ -38,32 +109,57 @@ func (r *WithdrawBalanceRepository) WithdrawWalletBalanceByUserID(ctx context.Co
 	defer tx.Rollback()
 
 	queriesWithTx := r.queries.WithTx(tx.Tx)
+	current, err := queriesWithTx.GetWalletVersionByUserID(ctx, parsedUserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.WalletBalance{}, vo.ErrWalletNotFound
+		}
+
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to read wallet version: %w", err)
+	}
+
+	// A freeze committed after this read bumps the version, so the guarded update below
+	// fails with ErrConcurrentModification rather than debiting a frozen wallet.
+	if current.Status == vo.WalletStatusFrozen {
+		return domain.WalletBalance{}, vo.ErrWalletFrozen
+	}
+
 	withdrawnWallet, err := queriesWithTx.WithdrawWalletBalanceByUserID(ctx, sharedsqlc.WithdrawWalletBalanceByUserIDParams{
-		AmountMinor: amountMinor,
-		UserID:      parsedUserID,
+		AmountMinor:     amountMinor + feeMinor,
+		UserID:          parsedUserID,
+		ExpectedVersion: current.Version,
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
-			exists, existsErr := queriesWithTx.HasWalletByUserID(ctx, parsedUserID)
-			if existsErr != nil {
-				return domain.WalletBalance{}, fmt.Errorf("repository: failed to check wallet existence: %w", existsErr)
-			}
+			return domain.WalletBalance{}, classifyStaleWalletUpdate(ctx, queriesWithTx, parsedUserID, current.Version, vo.ErrInsufficientBalance)
+		}
 
-			if !exists {
-				return domain.WalletBalance{}, vo.ErrWalletNotFound
-			}
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to withdraw wallet balance: %w", err)
+	}
 
-			return domain.WalletBalance{}, vo.ErrInsufficientBalance
+	if dailyLimit.LimitMinor > 0 {
+		withdrawnTodayMinor, err := queriesWithTx.SumWithdrawalsByUserSince(ctx, sharedsqlc.SumWithdrawalsByUserSinceParams{
+			UserID: parsedUserID,
+			Since:  dailyLimit.Since,
+		})
+		if err != nil {
+			return domain.WalletBalance{}, fmt.Errorf("repository: failed to sum daily withdrawals: %w", err)
 		}
 
-		return domain.WalletBalance{}, fmt.Errorf("repository: failed to withdraw wallet balance: %w", err)
+		if withdrawnTodayMinor+amountMinor > dailyLimit.LimitMinor {
+			return domain.WalletBalance{}, vo.ErrDailyLimitExceeded
+		}
 	}
 
 	ledgerParams := sharedsqlc.InsertWalletLedgerParams{
 		WalletID:          withdrawnWallet.WalletID,
 		EntryType:         "withdrawal",
 		AmountMinor:       -amountMinor,
-		BalanceAfterMinor: withdrawnWallet.BalanceMinor,
+		BalanceAfterMinor: withdrawnWallet.BalanceMinor + feeMinor,
+	}
+
+	if referenceID != "" {
+		ledgerParams.ReferenceID = sql.NullString{String: referenceID, Valid: true}
 	}
 
 	if chainID != "" {
@@ -74,6 +170,40 @@ func (r *WithdrawBalanceRepository) WithdrawWalletBalanceByUserID(ctx context.Co
 		return domain.WalletBalance{}, fmt.Errorf("repository: failed to insert wallet ledger: %w", err)
 	}
 
+	if feeMinor > 0 {
+		feeParams := ledgerParams
+		feeParams.EntryType = "fee"
+		feeParams.AmountMinor = -feeMinor
+		feeParams.BalanceAfterMinor = withdrawnWallet.BalanceMinor
+
+		if err := queriesWithTx.InsertWalletLedger(ctx, feeParams); err != nil {
+			return domain.WalletBalance{}, fmt.Errorf("repository: failed to insert fee ledger: %w", err)
+		}
+	}
+
+	if referenceID != "" {
+		if err := insertWithdrawalPayout(ctx, tx, referenceID, withdrawnWallet.UserID, amountMinor, feeMinor, withdrawnWallet.Currency, chainID); err != nil {
+			return domain.WalletBalance{}, err
+		}
+	}
+
+	// withdrawal.completed follows from CompleteWithdrawalByReferenceID once the payout is
+	// accepted, so consumers never see a completion that is later reversed.
+	if err := insertOutboxEvent(ctx, tx, sharedevents.Event{
+		Type:          sharedevents.TypeWithdrawalDebited,
+		TransactionID: referenceID,
+		UserID:        withdrawnWallet.UserID,
+		AmountMinor:   amountMinor,
+		FeeMinor:      feeMinor,
+		Currency:      withdrawnWallet.Currency,
+		ChainID:       chainID,
+		OccurredAt:    withdrawnWallet.UpdatedAt.UTC(),
+	}); err != nil {
+		return domain.WalletBalance{}, err
+	}
+
+	notifyWalletChange(ctx, tx, parsedUserID.String())
+
 	if err := tx.Commit(); err != nil {
 		return domain.WalletBalance{}, fmt.Errorf("repository: failed to commit transaction: %w", err)
 	}
@@ -82,6 +212,179 @@ func (r *WithdrawBalanceRepository) WithdrawWalletBalanceByUserID(ctx context.Co
 		UserID:       withdrawnWallet.UserID,
 		BalanceMinor: withdrawnWallet.BalanceMinor,
 		Currency:     withdrawnWallet.Currency,
+		Version:      withdrawnWallet.Version,
 		UpdatedAt:    withdrawnWallet.UpdatedAt,
 	}, nil
 }
+
+// GetWithdrawalByReferenceID rebuilds a withdrawal owned by userID from its ledger entries.
+// Withdrawals of other users are reported as not found.
+func (r *WithdrawBalanceRepository) GetWithdrawalByReferenceID(ctx context.Context, userID, referenceID string) (_ domain.WithdrawalRecord, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.WithdrawalRecord{}, fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	entries, err := r.queries.GetWithdrawalLedgerByReferenceID(ctx, sharedsqlc.GetWithdrawalLedgerByReferenceIDParams{
+		ReferenceID: referenceID,
+		UserID:      parsedUserID,
+	})
+	if err != nil {
+		return domain.WithdrawalRecord{}, fmt.Errorf("repository: get withdrawal by reference_id failed: %w", err)
+	}
+
+	record := domain.WithdrawalRecord{ReferenceID: referenceID, UserID: userID}
+	found := false
+	for _, entry := range entries {
+		switch entry.EntryType {
+		case "withdrawal":
+			found = true
+			record.AmountMinor = -entry.AmountMinor
+			record.Currency = entry.Currency
+			record.ChainID = entry.ChainID.String
+			record.CreatedAt = entry.CreatedAt
+		case "fee":
+			record.FeeMinor = -entry.AmountMinor
+		case "withdrawal_reversal", "fee_reversal":
+			record.Reversed = true
+		}
+
+		if entry.CreatedAt.After(record.UpdatedAt) {
+			record.UpdatedAt = entry.CreatedAt
+		}
+	}
+
+	if !found {
+		return domain.WithdrawalRecord{}, vo.ErrWithdrawalNotFound
+	}
+
+	payoutStatus, err := r.withdrawalPayoutStatus(ctx, referenceID)
+	if err != nil {
+		return domain.WithdrawalRecord{}, err
+	}
+	record.Pending = payoutStatus == withdrawalPayoutPending
+
+	return record, nil
+}
+
+// ReverseWithdrawalByUserID credits back a committed withdrawal and its fee, e.g. after the
+// payout provider rejected it. The reversal entries share the withdrawal's reference_id and
+// offset it in the daily limit sum, and a withdrawal.reversed event is queued in the outbox.
+// Only a pending payout can be reversed; a settled one returns vo.ErrWithdrawalSettled.
+func (r *WithdrawBalanceRepository) ReverseWithdrawalByUserID(ctx context.Context, userID string, amountMinor int64, chainID, referenceID string, feeMinor int64) (_ domain.WalletBalance, err error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: invalid user_id: %w", err)
+	}
+
+	if amountMinor <= 0 || feeMinor < 0 {
+		return domain.WalletBalance{}, vo.ErrInvalidAmount
+	}
+
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := settleWithdrawalPayout(ctx, tx, referenceID, withdrawalPayoutReversed); err != nil {
+		return domain.WalletBalance{}, err
+	}
+
+	queriesWithTx := r.queries.WithTx(tx.Tx)
+	creditedWallet, err := queriesWithTx.AdjustWalletBalanceByUserID(ctx, sharedsqlc.AdjustWalletBalanceByUserIDParams{
+		DeltaMinor: amountMinor + feeMinor,
+		UserID:     parsedUserID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.WalletBalance{}, vo.ErrWalletNotFound
+		}
+
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to reverse wallet withdrawal: %w", err)
+	}
+
+	ledgerParams := sharedsqlc.InsertWalletLedgerParams{
+		WalletID:          creditedWallet.WalletID,
+		EntryType:         "withdrawal_reversal",
+		AmountMinor:       amountMinor,
+		BalanceAfterMinor: creditedWallet.BalanceMinor - feeMinor,
+	}
+
+	if referenceID != "" {
+		ledgerParams.ReferenceID = sql.NullString{String: referenceID, Valid: true}
+	}
+
+	if chainID != "" {
+		ledgerParams.ChainID = sql.NullString{String: chainID, Valid: true}
+	}
+
+	if err := queriesWithTx.InsertWalletLedger(ctx, ledgerParams); err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to insert reversal ledger: %w", err)
+	}
+
+	if feeMinor > 0 {
+		feeParams := ledgerParams
+		feeParams.EntryType = "fee_reversal"
+		feeParams.AmountMinor = feeMinor
+		feeParams.BalanceAfterMinor = creditedWallet.BalanceMinor
+
+		if err := queriesWithTx.InsertWalletLedger(ctx, feeParams); err != nil {
+			return domain.WalletBalance{}, fmt.Errorf("repository: failed to insert fee reversal ledger: %w", err)
+		}
+	}
+
+	if err := insertOutboxEvent(ctx, tx, sharedevents.Event{
+		Type:          sharedevents.TypeWithdrawalReversed,
+		TransactionID: referenceID,
+		UserID:        creditedWallet.UserID,
+		AmountMinor:   amountMinor,
+		FeeMinor:      feeMinor,
+		Currency:      creditedWallet.Currency,
+		ChainID:       chainID,
+		OccurredAt:    creditedWallet.UpdatedAt.UTC(),
+	}); err != nil {
+		return domain.WalletBalance{}, err
+	}
+
+	notifyWalletChange(ctx, tx, parsedUserID.String())
+
+	if err := tx.Commit(); err != nil {
+		return domain.WalletBalance{}, fmt.Errorf("repository: failed to commit transaction: %w", err)
+	}
+
+	return domain.WalletBalance{
+		UserID:       creditedWallet.UserID,
+		BalanceMinor: creditedWallet.BalanceMinor,
+		Currency:     creditedWallet.Currency,
+		UpdatedAt:    creditedWallet.UpdatedAt,
+	}, nil
+}
+
+// classifyStaleWalletUpdate explains why a version-guarded wallet update matched no rows.
+// A changed version means another transaction won the race; otherwise the update's
+// own guard (e.g. the balance check) rejected it and fallback is returned.
+func classifyStaleWalletUpdate(ctx context.Context, queries *sharedsqlc.Queries, userID uuid.UUID, expectedVersion int64, fallback error) error {
+	latest, err := queries.GetWalletVersionByUserID(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return vo.ErrWalletNotFound
+		}
+
+		return fmt.Errorf("repository: failed to re-read wallet version: %w", err)
+	}
+
+	if latest.Version != expectedVersion {
+		return vo.ErrConcurrentModification
+	}
+
+	return fallback
+}
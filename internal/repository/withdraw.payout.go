@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedevents "github.com/joshuarp/withdraw-api/internal/shared/events"
+)
+
+const (
+	withdrawalPayoutPending   = "pending"
+	withdrawalPayoutCompleted = "completed"
+	withdrawalPayoutReversed  = "reversed"
+)
+
+const insertWithdrawalPayoutQuery = `
+INSERT INTO withdrawal_payouts (reference_id, user_id, amount_minor, fee_minor, currency, chain_id)
+VALUES ($1, $2, $3, $4, $5, $6)`
+
+// settleWithdrawalPayoutQuery only moves a pending payout, so a withdrawal is completed
+// or reversed at most once even when the request and the reconciler race.
+const settleWithdrawalPayoutQuery = `
+UPDATE withdrawal_payouts
+SET status = $2, updated_at = now()
+WHERE reference_id = $1
+  AND status = 'pending'
+RETURNING reference_id, user_id::text, amount_minor, fee_minor, currency, COALESCE(chain_id, ''), updated_at`
+
+const listPendingWithdrawalPayoutsQuery = `
+SELECT reference_id, user_id::text, amount_minor, fee_minor, currency, COALESCE(chain_id, ''), created_at
+FROM withdrawal_payouts
+WHERE status = 'pending'
+  AND created_at <= $1
+ORDER BY created_at
+LIMIT $2`
+
+const getWithdrawalPayoutStatusQuery = `SELECT status FROM withdrawal_payouts WHERE reference_id = $1`
+
+// insertWithdrawalPayout records the debited withdrawal as pending within tx, so a payout
+// whose outcome is never learned is still found by the reconciler.
+func insertWithdrawalPayout(ctx context.Context, tx *sqlx.Tx, referenceID, userID string, amountMinor, feeMinor int64, currency, chainID string) error {
+	var chain sql.NullString
+	if chainID != "" {
+		chain = sql.NullString{String: chainID, Valid: true}
+	}
+
+	if _, err := tx.ExecContext(ctx, insertWithdrawalPayoutQuery, referenceID, userID, amountMinor, feeMinor, currency, chain); err != nil {
+		return fmt.Errorf("repository: failed to insert pending withdrawal payout: %w", err)
+	}
+
+	return nil
+}
+
+// settleWithdrawalPayout moves a pending payout to status within tx and returns it with
+// CreatedAt set to the settle time, or vo.ErrWithdrawalSettled when it is not pending
+// any more.
+func settleWithdrawalPayout(ctx context.Context, tx *sqlx.Tx, referenceID, status string) (domain.PendingWithdrawal, error) {
+	var withdrawal domain.PendingWithdrawal
+	err := tx.QueryRowContext(ctx, settleWithdrawalPayoutQuery, referenceID, status).
+		Scan(&withdrawal.ReferenceID, &withdrawal.UserID, &withdrawal.AmountMinor, &withdrawal.FeeMinor, &withdrawal.Currency, &withdrawal.ChainID, &withdrawal.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PendingWithdrawal{}, vo.ErrWithdrawalSettled
+		}
+		return domain.PendingWithdrawal{}, fmt.Errorf("repository: failed to mark withdrawal payout %s: %w", status, err)
+	}
+
+	return withdrawal, nil
+}
+
+// CompleteWithdrawalByReferenceID marks the withdrawal's payout as accepted by the provider
+// and, in the same transaction, queues the withdrawal.completed event.
+func (r *WithdrawBalanceRepository) CompleteWithdrawalByReferenceID(ctx context.Context, referenceID string) (err error) {
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("repository: failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	completed, err := settleWithdrawalPayout(ctx, tx, referenceID, withdrawalPayoutCompleted)
+	if err != nil {
+		return err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, sharedevents.Event{
+		Type:          sharedevents.TypeWithdrawalCompleted,
+		TransactionID: completed.ReferenceID,
+		UserID:        completed.UserID,
+		AmountMinor:   completed.AmountMinor,
+		FeeMinor:      completed.FeeMinor,
+		Currency:      completed.Currency,
+		ChainID:       completed.ChainID,
+		OccurredAt:    completed.CreatedAt.UTC(),
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("repository: failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListPendingWithdrawals returns up to limit withdrawals created at or before
+// createdBefore whose payout is still pending, oldest first.
+func (r *WithdrawBalanceRepository) ListPendingWithdrawals(ctx context.Context, createdBefore time.Time, limit int) (_ []domain.PendingWithdrawal, err error) {
+	ctx, cancel := r.queryTimeout.withContext(ctx)
+	defer cancel()
+	defer func() { err = withQueryDeadline(ctx, err) }()
+
+	rows, err := r.db.QueryContext(ctx, listPendingWithdrawalPayoutsQuery, createdBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to list pending withdrawals: %w", err)
+	}
+	defer rows.Close()
+
+	pending := make([]domain.PendingWithdrawal, 0, limit)
+	for rows.Next() {
+		var withdrawal domain.PendingWithdrawal
+		if err := rows.Scan(&withdrawal.ReferenceID, &withdrawal.UserID, &withdrawal.AmountMinor, &withdrawal.FeeMinor, &withdrawal.Currency, &withdrawal.ChainID, &withdrawal.CreatedAt); err != nil {
+			return nil, fmt.Errorf("repository: failed to scan pending withdrawal: %w", err)
+		}
+		pending = append(pending, withdrawal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: failed to read pending withdrawals: %w", err)
+	}
+
+	return pending, nil
+}
+
+// withdrawalPayoutStatus returns the payout status of a withdrawal, or "" for one made
+// before payouts were tracked.
+func (r *WithdrawBalanceRepository) withdrawalPayoutStatus(ctx context.Context, referenceID string) (string, error) {
+	var status string
+	if err := r.db.GetContext(ctx, &status, getWithdrawalPayoutStatusQuery, referenceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("repository: failed to read withdrawal payout status: %w", err)
+	}
+
+	return status, nil
+}
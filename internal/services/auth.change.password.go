@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedhash "github.com/joshuarp/withdraw-api/internal/shared/hash"
+)
+
+type AuthChangePasswordRepository interface {
+	GetPasswordHash(ctx context.Context, userID string) (string, error)
+	UpdatePasswordHash(ctx context.Context, userID, currentHash, newHash string) error
+}
+
+type AuthChangePasswordService struct {
+	repository AuthChangePasswordRepository
+	hasher     sharedhash.Hasher
+	passwords  *PasswordValidator
+}
+
+func NewAuthChangePasswordService(
+	repository AuthChangePasswordRepository,
+	hasher sharedhash.Hasher,
+	passwords *PasswordValidator,
+) *AuthChangePasswordService {
+	return &AuthChangePasswordService{
+		repository: repository,
+		hasher:     hasher,
+		passwords:  passwords,
+	}
+}
+
+// ChangePassword replaces the password of userID after checking currentPassword. A wrong
+// current password is vo.ErrInvalidCredentials; a new password failing the policy is a
+// *vo.WeakPasswordError. Tokens issued before the change stay valid until they expire.
+func (s *AuthChangePasswordService) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+	if strings.TrimSpace(userID) == "" || currentPassword == "" {
+		return vo.ErrInvalidCredentials
+	}
+
+	currentHash, err := s.repository.GetPasswordHash(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.hasher.Compare(ctx, currentHash, currentPassword); err != nil {
+		return vo.ErrInvalidCredentials
+	}
+
+	if err := s.passwords.Validate(newPassword); err != nil {
+		return err
+	}
+
+	newHash, err := s.hasher.Hash(ctx, newPassword)
+	if err != nil {
+		return fmt.Errorf("service: failed to hash password: %w", err)
+	}
+
+	return s.repository.UpdatePasswordHash(ctx, userID, currentHash, newHash)
+}
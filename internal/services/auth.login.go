@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -9,8 +10,14 @@ import (
 	"github.com/joshuarp/withdraw-api/internal/domain/vo"
 	sharedhash "github.com/joshuarp/withdraw-api/internal/shared/hash"
 	sharedjwt "github.com/joshuarp/withdraw-api/internal/shared/jwt"
+	sharedlockout "github.com/joshuarp/withdraw-api/internal/shared/lockout"
 )
 
+// dummyPasswordHash is compared against when the email is unknown, so that path costs a
+// bcrypt comparison like a wrong password does and response times do not reveal which
+// emails are registered. It uses bcrypt.DefaultCost, the cost the app hashes with.
+const dummyPasswordHash = "$2a$10$UinIvINPuJZ1OaHR41S92en/QMJLjREclyEqdaIj2sPUFe7r1CuVq"
+
 type AuthLoginRepository interface {
 	GetUserAuthByEmail(ctx context.Context, email string) (domain.UserAuth, error)
 }
@@ -19,17 +26,20 @@ type AuthLoginService struct {
 	repository   AuthLoginRepository
 	hasher       sharedhash.Hasher
 	tokenManager sharedjwt.TokenManager
+	lockout      sharedlockout.Tracker
 }
 
 func NewAuthLoginService(
 	repository AuthLoginRepository,
 	hasher sharedhash.Hasher,
 	tokenManager sharedjwt.TokenManager,
+	lockout sharedlockout.Tracker,
 ) *AuthLoginService {
 	return &AuthLoginService{
 		repository:   repository,
 		hasher:       hasher,
 		tokenManager: tokenManager,
+		lockout:      lockout,
 	}
 }
 
@@ -39,16 +49,36 @@ func (s *AuthLoginService) Login(ctx context.Context, email, password string) (v
 		return vo.AuthLogin{}, vo.ErrInvalidCredentials
 	}
 
+	if s.lockout != nil {
+		lockedFor, err := s.lockout.LockedFor(ctx, normalizedEmail)
+		if err != nil {
+			return vo.AuthLogin{}, fmt.Errorf("service: failed to check account lockout: %w", err)
+		}
+		if lockedFor > 0 {
+			return vo.AuthLogin{}, vo.ErrAccountLocked
+		}
+	}
+
 	user, err := s.repository.GetUserAuthByEmail(ctx, normalizedEmail)
 	if err != nil {
+		if errors.Is(err, vo.ErrInvalidCredentials) {
+			_ = s.hasher.Compare(ctx, dummyPasswordHash, password)
+			return vo.AuthLogin{}, s.registerFailure(ctx, normalizedEmail)
+		}
 		return vo.AuthLogin{}, err
 	}
 
 	if err := s.hasher.Compare(ctx, user.PasswordHash, password); err != nil {
-		return vo.AuthLogin{}, vo.ErrInvalidCredentials
+		return vo.AuthLogin{}, s.registerFailure(ctx, normalizedEmail)
+	}
+
+	if s.lockout != nil {
+		if err := s.lockout.Reset(ctx, normalizedEmail); err != nil {
+			return vo.AuthLogin{}, fmt.Errorf("service: failed to reset login failures: %w", err)
+		}
 	}
 
-	token, err := s.tokenManager.Sign(ctx, sharedjwt.Claims{Subject: user.ID})
+	token, err := s.tokenManager.Sign(ctx, sharedjwt.Claims{Subject: user.ID, Scopes: user.Scopes})
 	if err != nil {
 		return vo.AuthLogin{}, fmt.Errorf("service: failed to issue token: %w", err)
 	}
@@ -58,3 +88,13 @@ func (s *AuthLoginService) Login(ctx context.Context, email, password string) (v
 		TokenType:   "Bearer",
 	}, nil
 }
+
+func (s *AuthLoginService) registerFailure(ctx context.Context, email string) error {
+	if s.lockout != nil {
+		if err := s.lockout.RegisterFailure(ctx, email); err != nil {
+			return fmt.Errorf("service: failed to record login failure: %w", err)
+		}
+	}
+
+	return vo.ErrInvalidCredentials
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedpassword "github.com/joshuarp/withdraw-api/internal/shared/password"
+)
+
+// PasswordValidator enforces the password policy on passwords being set, e.g. by
+// registration. It is deliberately not used by login, so existing accounts can still sign
+// in after the policy is tightened.
+type PasswordValidator struct {
+	policy sharedpassword.Policy
+}
+
+func NewPasswordValidator(policy sharedpassword.Policy) *PasswordValidator {
+	return &PasswordValidator{policy: policy}
+}
+
+// Validate returns a *vo.WeakPasswordError (matching vo.ErrWeakPassword) listing every
+// unmet criterion, or nil when password satisfies the policy.
+func (v *PasswordValidator) Validate(password string) error {
+	if unmet := v.policy.Unmet(password); len(unmet) > 0 {
+		return &vo.WeakPasswordError{Unmet: unmet}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedhash "github.com/joshuarp/withdraw-api/internal/shared/hash"
+	"github.com/joshuarp/withdraw-api/internal/shared/uid"
+)
+
+type AuthRegisterRepository interface {
+	CreateUser(ctx context.Context, userID, email, passwordHash string) (time.Time, error)
+	DeleteUser(ctx context.Context, userID string) error
+}
+
+type AuthRegisterService struct {
+	users     AuthRegisterRepository
+	wallets   WalletCreateRepository
+	hasher    sharedhash.Hasher
+	passwords *PasswordValidator
+	ids       uid.UIDGenerator
+}
+
+func NewAuthRegisterService(
+	users AuthRegisterRepository,
+	wallets WalletCreateRepository,
+	hasher sharedhash.Hasher,
+	passwords *PasswordValidator,
+	ids uid.UIDGenerator,
+) *AuthRegisterService {
+	return &AuthRegisterService{
+		users:     users,
+		wallets:   wallets,
+		hasher:    hasher,
+		passwords: passwords,
+		ids:       ids,
+	}
+}
+
+// Register creates an active user and their default-currency wallet. Users and wallets
+// live in separate databases, so instead of one transaction the user is inserted first
+// and deleted again if the wallet cannot be opened; a user without a wallet is never
+// left behind by a failed registration.
+func (s *AuthRegisterService) Register(ctx context.Context, email, password string) (vo.AuthRegistered, error) {
+	normalizedEmail := strings.TrimSpace(strings.ToLower(email))
+	if address, err := mail.ParseAddress(normalizedEmail); err != nil || address.Address != normalizedEmail {
+		return vo.AuthRegistered{}, vo.ErrInvalidEmail
+	}
+
+	if err := s.passwords.Validate(password); err != nil {
+		return vo.AuthRegistered{}, err
+	}
+
+	passwordHash, err := s.hasher.Hash(ctx, password)
+	if err != nil {
+		return vo.AuthRegistered{}, fmt.Errorf("service: failed to hash password: %w", err)
+	}
+
+	userID, err := s.ids.Generate(ctx)
+	if err != nil {
+		return vo.AuthRegistered{}, fmt.Errorf("service: failed to generate user id: %w", err)
+	}
+
+	createdAt, err := s.users.CreateUser(ctx, userID, normalizedEmail, passwordHash)
+	if err != nil {
+		return vo.AuthRegistered{}, err
+	}
+
+	walletID, err := s.ids.Generate(ctx)
+	if err == nil {
+		_, err = s.wallets.CreateWallet(ctx, walletID, userID, defaultWalletCurrency)
+	}
+	if err != nil {
+		// The compensation must run even if the request was cancelled mid-way.
+		if deleteErr := s.users.DeleteUser(context.WithoutCancel(ctx), userID); deleteErr != nil {
+			err = errors.Join(err, deleteErr)
+		}
+		return vo.AuthRegistered{}, fmt.Errorf("service: failed to open wallet for new user: %w", err)
+	}
+
+	return vo.AuthRegistered{
+		UserID:    userID,
+		Email:     normalizedEmail,
+		WalletID:  walletID,
+		Currency:  defaultWalletCurrency,
+		CreatedAt: createdAt,
+	}, nil
+}
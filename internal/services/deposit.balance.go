@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedevents "github.com/joshuarp/withdraw-api/internal/shared/events"
+	"github.com/joshuarp/withdraw-api/internal/shared/uid"
+)
+
+type BalanceDepositRepository interface {
+	DepositWalletBalanceByUserID(ctx context.Context, userID string, amountMinor int64, referenceID string) (domain.WalletBalance, error)
+}
+
+type DepositBalanceService struct {
+	repository  BalanceDepositRepository
+	referenceID uid.UIDGenerator
+	events      sharedevents.Publisher
+}
+
+// NewDepositBalanceService builds the deposit service. A nil events publisher skips the
+// deposit.completed event.
+func NewDepositBalanceService(repository BalanceDepositRepository, referenceID uid.UIDGenerator, events sharedevents.Publisher) *DepositBalanceService {
+	return &DepositBalanceService{repository: repository, referenceID: referenceID, events: events}
+}
+
+func (s *DepositBalanceService) DepositBalance(ctx context.Context, userID string, amountMinor int64) (vo.WalletDeposit, error) {
+	if strings.TrimSpace(userID) == "" {
+		return vo.WalletDeposit{}, vo.ErrWalletNotFound
+	}
+
+	if amountMinor <= 0 {
+		return vo.WalletDeposit{}, vo.ErrInvalidAmount
+	}
+
+	referenceID, err := s.referenceID.Generate(ctx)
+	if err != nil {
+		return vo.WalletDeposit{}, fmt.Errorf("service: failed to generate reference id: %w", err)
+	}
+
+	balance, err := s.repository.DepositWalletBalanceByUserID(ctx, userID, amountMinor, referenceID)
+	if err != nil {
+		return vo.WalletDeposit{}, err
+	}
+
+	deposit := vo.WalletDeposit{
+		ReferenceID:  referenceID,
+		UserID:       balance.UserID,
+		AmountMinor:  amountMinor,
+		BalanceMinor: balance.BalanceMinor,
+		Currency:     balance.Currency,
+		UpdatedAt:    balance.UpdatedAt,
+	}
+
+	if s.events != nil {
+		// Best-effort: the deposit is committed, so a publish failure (already logged by the
+		// publisher) is not returned to the caller.
+		_ = s.events.Publish(context.WithoutCancel(ctx), sharedevents.Event{
+			Type:          sharedevents.TypeDepositCompleted,
+			TransactionID: deposit.ReferenceID,
+			UserID:        deposit.UserID,
+			AmountMinor:   deposit.AmountMinor,
+			Currency:      deposit.Currency,
+			OccurredAt:    deposit.UpdatedAt.UTC(),
+		})
+	}
+
+	return deposit, nil
+}
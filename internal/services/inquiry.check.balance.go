@@ -7,6 +7,7 @@ import (
 
 	"github.com/joshuarp/withdraw-api/internal/domain"
 	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/shared/negcache"
 )
 
 type BalanceInquiryRepository interface {
@@ -15,10 +16,13 @@ type BalanceInquiryRepository interface {
 
 type InquiryCheckBalanceService struct {
 	repository BalanceInquiryRepository
+	notFound   negcache.Cache
 }
 
-func NewInquiryCheckBalanceService(repository BalanceInquiryRepository) *InquiryCheckBalanceService {
-	return &InquiryCheckBalanceService{repository: repository}
+// NewInquiryCheckBalanceService builds the balance inquiry service. notFound remembers
+// users without a wallet so repeated inquiries skip the database; nil disables it.
+func NewInquiryCheckBalanceService(repository BalanceInquiryRepository, notFound negcache.Cache) *InquiryCheckBalanceService {
+	return &InquiryCheckBalanceService{repository: repository, notFound: notFound}
 }
 
 func (s *InquiryCheckBalanceService) CheckBalance(ctx context.Context, userID string) (vo.BalanceInquiry, error) {
@@ -26,8 +30,18 @@ func (s *InquiryCheckBalanceService) CheckBalance(ctx context.Context, userID st
 		return vo.BalanceInquiry{}, errors.New("user_id is required")
 	}
 
+	// The cache is an optimisation only: when it fails the database answers instead.
+	if s.notFound != nil {
+		if missing, err := s.notFound.Contains(ctx, userID); err == nil && missing {
+			return vo.BalanceInquiry{}, vo.ErrWalletNotFound
+		}
+	}
+
 	balance, err := s.repository.GetWalletBalanceByUserID(ctx, userID)
 	if err != nil {
+		if s.notFound != nil && errors.Is(err, vo.ErrWalletNotFound) {
+			_ = s.notFound.Add(ctx, userID)
+		}
 		return vo.BalanceInquiry{}, err
 	}
 
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -14,9 +15,18 @@ import (
 	"github.com/joshuarp/withdraw-api/internal/domain"
 	"github.com/joshuarp/withdraw-api/internal/domain/vo"
 	servicemocks "github.com/joshuarp/withdraw-api/internal/mock/services"
+	auditmocks "github.com/joshuarp/withdraw-api/internal/mock/shared/audit"
+	eventmocks "github.com/joshuarp/withdraw-api/internal/mock/shared/events"
 	hashmocks "github.com/joshuarp/withdraw-api/internal/mock/shared/hash"
 	jwtmocks "github.com/joshuarp/withdraw-api/internal/mock/shared/jwt"
+	negcachemocks "github.com/joshuarp/withdraw-api/internal/mock/shared/negcache"
+	uidmocks "github.com/joshuarp/withdraw-api/internal/mock/shared/uid"
+	sharedaudit "github.com/joshuarp/withdraw-api/internal/shared/audit"
+	sharedevents "github.com/joshuarp/withdraw-api/internal/shared/events"
 	sharedjwt "github.com/joshuarp/withdraw-api/internal/shared/jwt"
+	"github.com/joshuarp/withdraw-api/internal/shared/pagination"
+	sharedpassword "github.com/joshuarp/withdraw-api/internal/shared/password"
+	"github.com/joshuarp/withdraw-api/internal/shared/payout"
 )
 
 type AuthLoginServiceSuite struct {
@@ -32,7 +42,7 @@ func (s *AuthLoginServiceSuite) SetupTest() {
 	s.repository = servicemocks.NewAuthLoginRepository(s.T())
 	s.hasher = hashmocks.NewHasher(s.T())
 	s.tokenManager = jwtmocks.NewTokenManager(s.T())
-	s.service = NewAuthLoginService(s.repository, s.hasher, s.tokenManager)
+	s.service = NewAuthLoginService(s.repository, s.hasher, s.tokenManager, nil)
 }
 
 func (s *AuthLoginServiceSuite) TestLogin_TableDriven() {
@@ -81,6 +91,25 @@ func (s *AuthLoginServiceSuite) TestLogin_TableDriven() {
 				assert.Equal(s.T(), vo.AuthLogin{}, result)
 			},
 		},
+		{
+			name:     "unknown email still compares against dummy hash",
+			email:    "ghost@example.com",
+			password: "secret",
+			setupMock: func() {
+				s.repository.EXPECT().
+					GetUserAuthByEmail(mock.Anything, "ghost@example.com").
+					Return(domain.UserAuth{}, vo.ErrInvalidCredentials)
+				s.hasher.EXPECT().
+					Compare(mock.Anything, dummyPasswordHash, "secret").
+					Return(errors.New("mismatch")).
+					Once()
+			},
+			assertion: func(result vo.AuthLogin, err error) {
+				require.Error(s.T(), err)
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidCredentials)
+				assert.Equal(s.T(), vo.AuthLogin{}, result)
+			},
+		},
 		{
 			name:     "invalid when password mismatch",
 			email:    "user@example.com",
@@ -130,14 +159,16 @@ func (s *AuthLoginServiceSuite) TestLogin_TableDriven() {
 			email:    " user@example.com ",
 			password: "secret",
 			setupMock: func() {
-				user := domain.UserAuth{ID: "user-1", PasswordHash: "hashed"}
+				user := domain.UserAuth{ID: "user-1", PasswordHash: "hashed", Scopes: []string{"wallet:adjust"}}
 				s.repository.EXPECT().
 					GetUserAuthByEmail(mock.Anything, "user@example.com").
 					Return(user, nil)
 				s.hasher.EXPECT().
 					Compare(mock.Anything, "hashed", "secret").
 					Return(nil)
-				s.tokenManager.EXPECT().Sign(mock.Anything, mock.Anything).Return("signed-token", nil)
+				s.tokenManager.EXPECT().
+					Sign(mock.Anything, sharedjwt.Claims{Subject: "user-1", Scopes: []string{"wallet:adjust"}}).
+					Return("signed-token", nil)
 			},
 			assertion: func(result vo.AuthLogin, err error) {
 				require.NoError(s.T(), err)
@@ -160,6 +191,117 @@ func (s *AuthLoginServiceSuite) TestLogin_TableDriven() {
 	}
 }
 
+type memoryLockoutTracker struct {
+	threshold int64
+	failures  map[string]int64
+	locked    map[string]bool
+}
+
+func newMemoryLockoutTracker(threshold int64) *memoryLockoutTracker {
+	return &memoryLockoutTracker{threshold: threshold, failures: map[string]int64{}, locked: map[string]bool{}}
+}
+
+func (t *memoryLockoutTracker) LockedFor(_ context.Context, key string) (time.Duration, error) {
+	if t.locked[key] {
+		return time.Minute, nil
+	}
+	return 0, nil
+}
+
+func (t *memoryLockoutTracker) RegisterFailure(_ context.Context, key string) error {
+	t.failures[key]++
+	if t.failures[key] >= t.threshold {
+		t.locked[key] = true
+		delete(t.failures, key)
+	}
+	return nil
+}
+
+func (t *memoryLockoutTracker) Reset(_ context.Context, key string) error {
+	delete(t.failures, key)
+	return nil
+}
+
+func (s *AuthLoginServiceSuite) TestLogin_Lockout_TableDriven() {
+	const threshold = 3
+	user := domain.UserAuth{ID: "user-1", Email: "user@example.com", PasswordHash: "hashed"}
+	mismatch := errors.New("mismatch")
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		run       func(*memoryLockoutTracker)
+	}{
+		{
+			name: "consecutive failures trigger lockout",
+			setupMock: func() {
+				s.repository.EXPECT().GetUserAuthByEmail(mock.Anything, "user@example.com").Return(user, nil).Times(threshold)
+				s.hasher.EXPECT().Compare(mock.Anything, "hashed", "wrong").Return(mismatch).Times(threshold)
+			},
+			run: func(tracker *memoryLockoutTracker) {
+				for range threshold {
+					_, err := s.service.Login(context.Background(), "user@example.com", "wrong")
+					assert.ErrorIs(s.T(), err, vo.ErrInvalidCredentials)
+				}
+
+				_, err := s.service.Login(context.Background(), "USER@example.com", "password")
+				assert.ErrorIs(s.T(), err, vo.ErrAccountLocked)
+			},
+		},
+		{
+			name: "unknown email is locked the same way",
+			setupMock: func() {
+				s.repository.EXPECT().GetUserAuthByEmail(mock.Anything, "ghost@example.com").Return(domain.UserAuth{}, vo.ErrInvalidCredentials).Times(threshold)
+				s.hasher.EXPECT().Compare(mock.Anything, dummyPasswordHash, "wrong").Return(mismatch).Times(threshold)
+			},
+			run: func(tracker *memoryLockoutTracker) {
+				for range threshold {
+					_, err := s.service.Login(context.Background(), "ghost@example.com", "wrong")
+					assert.ErrorIs(s.T(), err, vo.ErrInvalidCredentials)
+				}
+
+				_, err := s.service.Login(context.Background(), "ghost@example.com", "wrong")
+				assert.ErrorIs(s.T(), err, vo.ErrAccountLocked)
+			},
+		},
+		{
+			name: "successful login clears failure counter",
+			setupMock: func() {
+				s.repository.EXPECT().GetUserAuthByEmail(mock.Anything, "user@example.com").Return(user, nil)
+				s.hasher.EXPECT().Compare(mock.Anything, "hashed", "wrong").Return(mismatch).Times(threshold)
+				s.hasher.EXPECT().Compare(mock.Anything, "hashed", "password").Return(nil).Once()
+				s.tokenManager.EXPECT().Sign(mock.Anything, sharedjwt.Claims{Subject: "user-1"}).Return("token", nil).Once()
+			},
+			run: func(tracker *memoryLockoutTracker) {
+				for range threshold - 1 {
+					_, err := s.service.Login(context.Background(), "user@example.com", "wrong")
+					assert.ErrorIs(s.T(), err, vo.ErrInvalidCredentials)
+				}
+
+				result, err := s.service.Login(context.Background(), "user@example.com", "password")
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), "token", result.AccessToken)
+				assert.Zero(s.T(), tracker.failures["user@example.com"])
+
+				_, err = s.service.Login(context.Background(), "user@example.com", "wrong")
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidCredentials)
+				assert.False(s.T(), tracker.locked["user@example.com"])
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			tracker := newMemoryLockoutTracker(threshold)
+			s.service = NewAuthLoginService(s.repository, s.hasher, s.tokenManager, tracker)
+			tc.setupMock()
+
+			tc.run(tracker)
+		})
+	}
+}
+
 func TestAuthLoginServiceSuite(t *testing.T) {
 	suite.Run(t, new(AuthLoginServiceSuite))
 }
@@ -173,7 +315,7 @@ type InquiryCheckBalanceServiceSuite struct {
 
 func (s *InquiryCheckBalanceServiceSuite) SetupTest() {
 	s.repository = servicemocks.NewBalanceInquiryRepository(s.T())
-	s.service = NewInquiryCheckBalanceService(s.repository)
+	s.service = NewInquiryCheckBalanceService(s.repository, nil)
 }
 
 func (s *InquiryCheckBalanceServiceSuite) TestCheckBalance_TableDriven() {
@@ -237,6 +379,123 @@ func (s *InquiryCheckBalanceServiceSuite) TestCheckBalance_TableDriven() {
 	}
 }
 
+// memoryNegativeCache is a negcache.Cache whose entries only expire when expire is called.
+type memoryNegativeCache struct {
+	entries map[string]bool
+}
+
+func newMemoryNegativeCache() *memoryNegativeCache {
+	return &memoryNegativeCache{entries: map[string]bool{}}
+}
+
+func (c *memoryNegativeCache) Contains(_ context.Context, key string) (bool, error) {
+	return c.entries[key], nil
+}
+
+func (c *memoryNegativeCache) Add(_ context.Context, key string) error {
+	c.entries[key] = true
+	return nil
+}
+
+func (c *memoryNegativeCache) Remove(_ context.Context, key string) error {
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *memoryNegativeCache) expire(key string) {
+	delete(c.entries, key)
+}
+
+func (s *InquiryCheckBalanceServiceSuite) TestCheckBalance_NotFoundCache_TableDriven() {
+	tests := []struct {
+		name string
+		run  func()
+	}{
+		{
+			name: "second not-found inquiry within ttl skips the repository",
+			run: func() {
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{}, vo.ErrWalletNotFound).Once()
+				s.service = NewInquiryCheckBalanceService(s.repository, newMemoryNegativeCache())
+
+				for range 2 {
+					_, err := s.service.CheckBalance(context.Background(), "user-1")
+					assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+				}
+			},
+		},
+		{
+			name: "expired entry queries the repository again",
+			run: func() {
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{}, vo.ErrWalletNotFound).Twice()
+				cache := newMemoryNegativeCache()
+				s.service = NewInquiryCheckBalanceService(s.repository, cache)
+
+				_, err := s.service.CheckBalance(context.Background(), "user-1")
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+				cache.expire("user-1")
+				_, err = s.service.CheckBalance(context.Background(), "user-1")
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name: "wallet created after a miss is found",
+			run: func() {
+				cache := newMemoryNegativeCache()
+				s.service = NewInquiryCheckBalanceService(s.repository, cache)
+				walletID := uidmocks.NewUIDGenerator(s.T())
+				walletID.EXPECT().Generate(mock.Anything).Return("wallet-1", nil)
+				wallets := servicemocks.NewWalletCreateRepository(s.T())
+				wallets.EXPECT().CreateWallet(mock.Anything, "wallet-1", "user-1", "IDR").Return(domain.Wallet{ID: "wallet-1", UserID: "user-1", Currency: "IDR"}, nil)
+
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{}, vo.ErrWalletNotFound).Once()
+				_, err := s.service.CheckBalance(context.Background(), "user-1")
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+
+				_, err = NewWalletCreateService(wallets, walletID, cache).CreateWallet(context.Background(), "user-1", "")
+				require.NoError(s.T(), err)
+
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{UserID: "user-1", Currency: "IDR"}, nil).Once()
+				result, err := s.service.CheckBalance(context.Background(), "user-1")
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), "user-1", result.UserID)
+			},
+		},
+		{
+			name: "other repository errors are not cached",
+			run: func() {
+				repoErr := errors.New("db down")
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{}, repoErr).Twice()
+				s.service = NewInquiryCheckBalanceService(s.repository, newMemoryNegativeCache())
+
+				for range 2 {
+					_, err := s.service.CheckBalance(context.Background(), "user-1")
+					assert.ErrorIs(s.T(), err, repoErr)
+				}
+			},
+		},
+		{
+			name: "cache failure falls back to the repository",
+			run: func() {
+				cache := negcachemocks.NewCache(s.T())
+				cache.EXPECT().Contains(mock.Anything, "user-1").Return(false, errors.New("redis down"))
+				cache.EXPECT().Add(mock.Anything, "user-1").Return(errors.New("redis down"))
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{}, vo.ErrWalletNotFound).Once()
+				s.service = NewInquiryCheckBalanceService(s.repository, cache)
+
+				_, err := s.service.CheckBalance(context.Background(), "user-1")
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			tc.run()
+		})
+	}
+}
+
 func TestInquiryCheckBalanceServiceSuite(t *testing.T) {
 	suite.Run(t, new(InquiryCheckBalanceServiceSuite))
 }
@@ -244,17 +503,20 @@ func TestInquiryCheckBalanceServiceSuite(t *testing.T) {
 type InquiryWithdrawBalanceServiceSuite struct {
 	suite.Suite
 
-	repository *servicemocks.BalanceWithdrawRepository
-	service    *InquiryWithdrawBalanceService
+	repository  *servicemocks.BalanceWithdrawRepository
+	referenceID *uidmocks.UIDGenerator
+	service     *InquiryWithdrawBalanceService
 }
 
 func (s *InquiryWithdrawBalanceServiceSuite) SetupTest() {
 	s.repository = servicemocks.NewBalanceWithdrawRepository(s.T())
-	s.service = NewInquiryWithdrawBalanceService(s.repository)
+	s.referenceID = uidmocks.NewUIDGenerator(s.T())
+	s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, WithdrawAmountLimits{}, nil, nil, nil)
 }
 
 func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_TableDriven() {
 	repoErr := errors.New("repository failure")
+	generateErr := errors.New("generator failure")
 	now := time.Now().UTC()
 
 	tests := []struct {
@@ -285,14 +547,29 @@ func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_TableDriven() {
 				assert.Equal(s.T(), vo.WalletWithdrawal{}, result)
 			},
 		},
+		{
+			name:    "reference id generation failed",
+			userID:  "user-1",
+			amount:  100,
+			chainID: "chain-1",
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("", generateErr)
+			},
+			assertion: func(result vo.WalletWithdrawal, err error) {
+				require.Error(s.T(), err)
+				assert.ErrorIs(s.T(), err, generateErr)
+				assert.Equal(s.T(), vo.WalletWithdrawal{}, result)
+			},
+		},
 		{
 			name:    "propagates repository error",
 			userID:  "user-1",
 			amount:  100,
 			chainID: "chain-1",
 			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
 				s.repository.EXPECT().
-					WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1").
+					WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(0), mock.Anything).
 					Return(domain.WalletBalance{}, repoErr)
 			},
 			assertion: func(result vo.WalletWithdrawal, err error) {
@@ -307,18 +584,22 @@ func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_TableDriven() {
 			amount:  100,
 			chainID: "chain-1",
 			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
 				s.repository.EXPECT().
-					WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1").
+					WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(0), mock.Anything).
 					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 900, Currency: "IDR", UpdatedAt: now}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
 			},
 			assertion: func(result vo.WalletWithdrawal, err error) {
 				require.NoError(s.T(), err)
 				assert.Equal(s.T(), vo.WalletWithdrawal{
+					ReferenceID:  "ref-1",
 					UserID:       "user-1",
 					AmountMinor:  100,
 					BalanceMinor: 900,
 					Currency:     "IDR",
 					ChainID:      "chain-1",
+					Status:       vo.WithdrawalStatusCompleted,
 					UpdatedAt:    now,
 				}, result)
 			},
@@ -332,12 +613,1989 @@ func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_TableDriven() {
 				tc.setupMock()
 			}
 
-			result, err := s.service.WithdrawBalance(context.Background(), tc.userID, tc.amount, tc.chainID)
+			result, err := s.service.WithdrawBalance(context.Background(), tc.userID, tc.amount, tc.chainID, "")
 			tc.assertion(result, err)
 		})
 	}
 }
 
-func TestInquiryWithdrawBalanceServiceSuite(t *testing.T) {
-	suite.Run(t, new(InquiryWithdrawBalanceServiceSuite))
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_Blackout_TableDriven() {
+	fixedNow := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)
+	schedule := ChainBlackoutSchedule{
+		{ChainID: "ethereum", Start: fixedNow.Add(-time.Hour), End: fixedNow.Add(time.Hour)},
+		{ChainID: "ethereum", Start: fixedNow.Add(time.Hour), End: fixedNow.Add(2 * time.Hour)},
+		{ChainID: "polygon", Start: fixedNow.Add(time.Hour), End: fixedNow.Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name        string
+		chainID     string
+		setupMock   func()
+		expectUntil time.Time
+	}{
+		{
+			name:        "rejects chain inside window until the adjoining window ends",
+			chainID:     "Ethereum",
+			expectUntil: fixedNow.Add(2 * time.Hour),
+		},
+		{
+			name:    "allows chain outside its window",
+			chainID: "polygon",
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "polygon", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 900, Currency: "IDR"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+		},
+		{
+			name:    "allows chain without schedule",
+			chainID: "solana",
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "solana", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 900, Currency: "IDR"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, schedule, WithdrawAmountLimits{}, nil, nil, nil)
+			s.service.now = func() time.Time { return fixedNow }
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			result, err := s.service.WithdrawBalance(context.Background(), "user-1", 100, tc.chainID, "")
+			if tc.expectUntil.IsZero() {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), int64(900), result.BalanceMinor)
+				return
+			}
+
+			require.ErrorIs(s.T(), err, vo.ErrChainUnavailable)
+			var unavailable *vo.ChainUnavailableError
+			require.ErrorAs(s.T(), err, &unavailable)
+			assert.Equal(s.T(), tc.expectUntil, unavailable.Until)
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_AmountLimits_TableDriven() {
+	tests := []struct {
+		name      string
+		limits    WithdrawAmountLimits
+		amount    int64
+		setupMock func()
+		expectErr error
+	}{
+		{
+			name:      "below minimum",
+			limits:    WithdrawAmountLimits{MinAmountMinor: 10_000, MaxAmountMinor: 1_000_000},
+			amount:    9_999,
+			expectErr: vo.ErrAmountBelowMinimum,
+		},
+		{
+			name:      "above maximum",
+			limits:    WithdrawAmountLimits{MinAmountMinor: 10_000, MaxAmountMinor: 1_000_000},
+			amount:    1_000_001,
+			expectErr: vo.ErrAmountAboveMaximum,
+		},
+		{
+			name:   "within range",
+			limits: WithdrawAmountLimits{MinAmountMinor: 10_000, MaxAmountMinor: 1_000_000},
+			amount: 10_000,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(10_000), "", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 90_000, Currency: "IDR"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+		},
+		{
+			name:   "zero limits mean no limit",
+			amount: 50_000_000,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(50_000_000), "", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 0, Currency: "IDR"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, tc.limits, nil, nil, nil)
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			result, err := s.service.WithdrawBalance(context.Background(), "user-1", tc.amount, "", "")
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+				assert.Equal(s.T(), vo.WalletWithdrawal{}, result)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.amount, result.AmountMinor)
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_DailyLimit_TableDriven() {
+	tests := []struct {
+		name        string
+		now         time.Time
+		repoErr     error
+		expectSince time.Time
+		expectErr   error
+	}{
+		{
+			name:        "passes limit and start of current day",
+			now:         time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC),
+			expectSince: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "fresh day resets the window",
+			now:         time.Date(2026, 3, 11, 0, 5, 0, 0, time.UTC),
+			expectSince: time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "non UTC clock still uses UTC day",
+			now:         time.Date(2026, 3, 11, 6, 0, 0, 0, time.FixedZone("WIB", 7*60*60)),
+			expectSince: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "propagates daily limit exceeded",
+			now:         time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC),
+			repoErr:     vo.ErrDailyLimitExceeded,
+			expectSince: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+			expectErr:   vo.ErrDailyLimitExceeded,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, WithdrawAmountLimits{DailyLimitMinor: 5_000}, nil, nil, nil)
+			s.service.now = func() time.Time { return tc.now }
+
+			expectedLimit := domain.DailyWithdrawLimit{LimitMinor: 5_000, Since: tc.expectSince}
+			s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+			s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(1_000), "", "ref-1", int64(0), expectedLimit).
+				Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 9_000, Currency: "IDR"}, tc.repoErr)
+			if tc.repoErr == nil {
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			}
+
+			_, err := s.service.WithdrawBalance(context.Background(), "user-1", 1_000, "", "")
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+				return
+			}
+			require.NoError(s.T(), err)
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_Fees_TableDriven() {
+	tests := []struct {
+		name      string
+		fees      FeeCalculator
+		amount    int64
+		expectFee int64
+	}{
+		{name: "flat fee", fees: FlatFeeCalculator{FeeMinor: 2_500}, amount: 100_000, expectFee: 2_500},
+		{name: "percentage fee", fees: PercentageFeeCalculator{BasisPoints: 150}, amount: 100_000, expectFee: 1_500},
+		{name: "percentage fee rounds up", fees: PercentageFeeCalculator{BasisPoints: 150}, amount: 1_001, expectFee: 16},
+		{
+			name:      "combined flat and percentage fee",
+			fees:      CombinedFeeCalculator{FlatFeeCalculator{FeeMinor: 2_500}, PercentageFeeCalculator{BasisPoints: 150}},
+			amount:    100_000,
+			expectFee: 4_000,
+		},
+		{name: "no fee calculator", amount: 100_000, expectFee: 0},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, WithdrawAmountLimits{}, tc.fees, nil, nil)
+
+			s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+			s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", tc.amount, "", "ref-1", tc.expectFee, mock.Anything).
+				Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 500_000, Currency: "IDR"}, nil)
+			s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+
+			result, err := s.service.WithdrawBalance(context.Background(), "user-1", tc.amount, "", "")
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.amount, result.AmountMinor)
+			assert.Equal(s.T(), tc.expectFee, result.FeeMinor)
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_Payout_TableDriven() {
+	ambiguousErr := &payout.ProviderError{StatusCode: 503, Retryable: true, Ambiguous: true}
+	rateLimitedErr := &payout.ProviderError{StatusCode: 429, Retryable: true}
+	permanentErr := &payout.ProviderError{StatusCode: 422, Message: "account closed"}
+	reverseErr := errors.New("reverse failed")
+	completeErr := errors.New("complete failed")
+	fees := FlatFeeCalculator{FeeMinor: 25}
+
+	tests := []struct {
+		name      string
+		setupMock func(*servicemocks.PayoutClient)
+		assertion func(vo.WalletWithdrawal, error)
+	}{
+		{
+			name: "success records provider reference",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				client.EXPECT().Payout(mock.Anything, payout.Request{
+					ReferenceID: "ref-1",
+					UserID:      "user-1",
+					AmountMinor: 100,
+					Currency:    "IDR",
+					ChainID:     "chain-1",
+				}).Return(payout.Result{ProviderReference: "po-1", Status: "accepted"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+			assertion: func(result vo.WalletWithdrawal, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), "po-1", result.PayoutReference)
+				assert.Equal(s.T(), int64(875), result.BalanceMinor)
+				assert.Equal(s.T(), vo.WithdrawalStatusCompleted, result.Status)
+			},
+		},
+		{
+			name: "success already settled by the reconciler is completed",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{ProviderReference: "po-1", Status: "accepted"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(vo.ErrWithdrawalSettled)
+			},
+			assertion: func(result vo.WalletWithdrawal, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.WithdrawalStatusCompleted, result.Status)
+			},
+		},
+		{
+			name: "success that cannot be recorded stays pending",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{ProviderReference: "po-1", Status: "accepted"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(completeErr)
+			},
+			assertion: func(result vo.WalletWithdrawal, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.WithdrawalStatusPending, result.Status)
+			},
+		},
+		{
+			name: "ambiguous error keeps withdrawal pending",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{}, ambiguousErr)
+			},
+			assertion: func(result vo.WalletWithdrawal, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.WithdrawalStatusPending, result.Status)
+				assert.Equal(s.T(), "ref-1", result.ReferenceID)
+				assert.Equal(s.T(), int64(875), result.BalanceMinor)
+			},
+		},
+		{
+			name: "timeout keeps withdrawal pending",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{}, context.DeadlineExceeded)
+			},
+			assertion: func(result vo.WalletWithdrawal, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.WithdrawalStatusPending, result.Status)
+			},
+		},
+		{
+			name: "rate limited error reverses withdrawal",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{}, rateLimitedErr)
+				s.repository.EXPECT().ReverseWithdrawalByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25)).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 1_000, Currency: "IDR"}, nil)
+			},
+			assertion: func(result vo.WalletWithdrawal, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrPayoutUnavailable)
+				assert.ErrorIs(s.T(), err, rateLimitedErr)
+				assert.Equal(s.T(), vo.WalletWithdrawal{}, result)
+			},
+		},
+		{
+			name: "open circuit reverses withdrawal",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{}, &payout.ProviderError{Retryable: true, Err: payout.ErrCircuitOpen})
+				s.repository.EXPECT().ReverseWithdrawalByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25)).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 1_000, Currency: "IDR"}, nil)
+			},
+			assertion: func(result vo.WalletWithdrawal, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrPayoutUnavailable)
+				assert.Equal(s.T(), vo.WalletWithdrawal{}, result)
+			},
+		},
+		{
+			name: "permanent error reverses withdrawal",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{}, permanentErr)
+				s.repository.EXPECT().ReverseWithdrawalByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25)).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 1_000, Currency: "IDR"}, nil)
+			},
+			assertion: func(result vo.WalletWithdrawal, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrPayoutRejected)
+				assert.ErrorContains(s.T(), err, "account closed")
+				assert.Equal(s.T(), vo.WalletWithdrawal{}, result)
+			},
+		},
+		{
+			name: "failed reversal surfaces as internal error",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{}, permanentErr)
+				s.repository.EXPECT().ReverseWithdrawalByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25)).
+					Return(domain.WalletBalance{}, reverseErr)
+			},
+			assertion: func(_ vo.WalletWithdrawal, err error) {
+				assert.ErrorIs(s.T(), err, reverseErr)
+				assert.NotErrorIs(s.T(), err, vo.ErrPayoutRejected)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			client := servicemocks.NewPayoutClient(s.T())
+			s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, WithdrawAmountLimits{}, fees, nil, client)
+
+			s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+			s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25), mock.Anything).
+				Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 875, Currency: "IDR"}, nil)
+			tc.setupMock(client)
+
+			result, err := s.service.WithdrawBalance(context.Background(), "user-1", 100, "chain-1", "")
+			tc.assertion(result, err)
+		})
+	}
+}
+
+// gatedPayoutClient is a payout client fronted by a circuit breaker.
+type gatedPayoutClient struct {
+	*servicemocks.PayoutClient
+	allow bool
+}
+
+func (c gatedPayoutClient) Allow() bool {
+	return c.allow
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_OpenBreakerRefusesBeforeDebit() {
+	client := servicemocks.NewPayoutClient(s.T())
+	s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, WithdrawAmountLimits{}, nil, nil, gatedPayoutClient{PayoutClient: client})
+
+	result, err := s.service.WithdrawBalance(context.Background(), "user-1", 100, "chain-1", "")
+
+	assert.ErrorIs(s.T(), err, vo.ErrPayoutUnavailable)
+	assert.ErrorIs(s.T(), err, payout.ErrCircuitOpen)
+	assert.Equal(s.T(), vo.WalletWithdrawal{}, result)
+	s.repository.AssertNotCalled(s.T(), "WithdrawWalletBalanceByUserID", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	client.AssertNotCalled(s.T(), "Payout", mock.Anything, mock.Anything)
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_ClosedBreakerPaysOut() {
+	client := servicemocks.NewPayoutClient(s.T())
+	s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, WithdrawAmountLimits{}, nil, nil, gatedPayoutClient{PayoutClient: client, allow: true})
+
+	s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+	s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(0), mock.Anything).
+		Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 900, Currency: "IDR"}, nil)
+	client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{ProviderReference: "po-1", Status: "accepted"}, nil)
+	s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+
+	result, err := s.service.WithdrawBalance(context.Background(), "user-1", 100, "chain-1", "")
+
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), vo.WithdrawalStatusCompleted, result.Status)
+	assert.Equal(s.T(), "po-1", result.PayoutReference)
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestReconcilePendingWithdrawals_TableDriven() {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	listErr := errors.New("list failed")
+	lookupErr := &payout.ProviderError{StatusCode: 503, Retryable: true, Ambiguous: true}
+	withdrawal := domain.PendingWithdrawal{ReferenceID: "ref-1", UserID: "user-1", AmountMinor: 100, FeeMinor: 25, Currency: "IDR", ChainID: "chain-1"}
+
+	tests := []struct {
+		name          string
+		withoutPayout bool
+		setupMock     func(*servicemocks.PayoutClient)
+		expectSettled int
+		expectErr     error
+	}{
+		{
+			name: "accepted payout is completed",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				s.repository.EXPECT().ListPendingWithdrawals(mock.Anything, now.Add(-time.Minute), 10).Return([]domain.PendingWithdrawal{withdrawal}, nil)
+				client.EXPECT().PayoutStatus(mock.Anything, "ref-1").Return(payout.Result{ProviderReference: "po-1", Status: "accepted"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+			expectSettled: 1,
+		},
+		{
+			name: "rejected payout is reversed",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				s.repository.EXPECT().ListPendingWithdrawals(mock.Anything, now.Add(-time.Minute), 10).Return([]domain.PendingWithdrawal{withdrawal}, nil)
+				client.EXPECT().PayoutStatus(mock.Anything, "ref-1").Return(payout.Result{Status: payout.StatusRejected}, nil)
+				s.repository.EXPECT().ReverseWithdrawalByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25)).
+					Return(domain.WalletBalance{UserID: "user-1"}, nil)
+			},
+			expectSettled: 1,
+		},
+		{
+			name: "payout unknown to the provider is reversed",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				s.repository.EXPECT().ListPendingWithdrawals(mock.Anything, now.Add(-time.Minute), 10).Return([]domain.PendingWithdrawal{withdrawal}, nil)
+				client.EXPECT().PayoutStatus(mock.Anything, "ref-1").Return(payout.Result{}, payout.ErrPayoutNotFound)
+				s.repository.EXPECT().ReverseWithdrawalByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25)).
+					Return(domain.WalletBalance{UserID: "user-1"}, nil)
+			},
+			expectSettled: 1,
+		},
+		{
+			name: "failed lookup stays pending",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				s.repository.EXPECT().ListPendingWithdrawals(mock.Anything, now.Add(-time.Minute), 10).Return([]domain.PendingWithdrawal{withdrawal}, nil)
+				client.EXPECT().PayoutStatus(mock.Anything, "ref-1").Return(payout.Result{}, lookupErr)
+			},
+			expectErr: lookupErr,
+		},
+		{
+			name: "withdrawal settled concurrently is skipped",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				s.repository.EXPECT().ListPendingWithdrawals(mock.Anything, now.Add(-time.Minute), 10).Return([]domain.PendingWithdrawal{withdrawal}, nil)
+				client.EXPECT().PayoutStatus(mock.Anything, "ref-1").Return(payout.Result{Status: "accepted"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(vo.ErrWithdrawalSettled)
+			},
+		},
+		{
+			name:          "without a payout client pending withdrawals are completed",
+			withoutPayout: true,
+			setupMock: func(_ *servicemocks.PayoutClient) {
+				s.repository.EXPECT().ListPendingWithdrawals(mock.Anything, now.Add(-time.Minute), 10).Return([]domain.PendingWithdrawal{withdrawal}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+			expectSettled: 1,
+		},
+		{
+			name: "list error",
+			setupMock: func(_ *servicemocks.PayoutClient) {
+				s.repository.EXPECT().ListPendingWithdrawals(mock.Anything, now.Add(-time.Minute), 10).Return(nil, listErr)
+			},
+			expectErr: listErr,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			client := servicemocks.NewPayoutClient(s.T())
+			var payouts PayoutClient = client
+			if tc.withoutPayout {
+				payouts = nil
+			}
+			s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, WithdrawAmountLimits{}, nil, nil, payouts)
+			s.service.now = func() time.Time { return now }
+			tc.setupMock(client)
+
+			settled, err := s.service.ReconcilePendingWithdrawals(context.Background(), time.Minute, 10)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			} else {
+				require.NoError(s.T(), err)
+			}
+			assert.Equal(s.T(), tc.expectSettled, settled)
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_Currency_TableDriven() {
+	walletErr := errors.New("wallet lookup failure")
+
+	tests := []struct {
+		name      string
+		currency  string
+		setupMock func()
+		expectErr error
+	}{
+		{
+			name:     "matching currency",
+			currency: "IDR",
+			setupMock: func() {
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{UserID: "user-1", Currency: "IDR"}, nil)
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(1_000), "", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 9_000, Currency: "IDR"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+		},
+		{
+			name:     "matching currency ignores case",
+			currency: "idr",
+			setupMock: func() {
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{UserID: "user-1", Currency: "IDR"}, nil)
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(1_000), "", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 9_000, Currency: "IDR"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+		},
+		{
+			name:     "mismatching currency",
+			currency: "USD",
+			setupMock: func() {
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{UserID: "user-1", Currency: "IDR"}, nil)
+			},
+			expectErr: vo.ErrCurrencyMismatch,
+		},
+		{
+			name: "omitted currency defaults to wallet currency",
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(1_000), "", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 9_000, Currency: "IDR"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+		},
+		{
+			name:     "wallet lookup failure",
+			currency: "IDR",
+			setupMock: func() {
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{}, walletErr)
+			},
+			expectErr: walletErr,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			tc.setupMock()
+
+			result, err := s.service.WithdrawBalance(context.Background(), "user-1", 1_000, "", tc.currency)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+				return
+			}
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), "IDR", result.Currency)
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestWalletCurrency_TableDriven() {
+	tests := []struct {
+		name      string
+		userID    string
+		setupMock func()
+		expected  string
+		expectErr error
+	}{
+		{
+			name:      "wallet not found when user empty",
+			userID:    " ",
+			expectErr: vo.ErrWalletNotFound,
+		},
+		{
+			name:   "propagates repository error",
+			userID: "user-1",
+			setupMock: func() {
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{}, vo.ErrWalletNotFound)
+			},
+			expectErr: vo.ErrWalletNotFound,
+		},
+		{
+			name:   "returns wallet currency",
+			userID: "user-1",
+			setupMock: func() {
+				s.repository.EXPECT().GetWalletBalanceByUserID(mock.Anything, "user-1").Return(domain.WalletBalance{Currency: "IDR"}, nil)
+			},
+			expected: "IDR",
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			currency, err := s.service.WalletCurrency(context.Background(), tc.userID)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+				return
+			}
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expected, currency)
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_RecordsAudit_TableDriven() {
+	repoErr := errors.New("repository failure")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		amount    int64
+		setupMock func()
+		expected  sharedaudit.Event
+	}{
+		{
+			name:   "records success",
+			amount: 100,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().
+					WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 900, Currency: "IDR"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil)
+			},
+			expected: sharedaudit.Event{Decision: sharedaudit.DecisionSuccess, AmountMinor: 100, Currency: "IDR", ReferenceID: "ref-1"},
+		},
+		{
+			name:   "records insufficient balance",
+			amount: 100,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().
+					WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{}, vo.ErrInsufficientBalance)
+			},
+			expected: sharedaudit.Event{Decision: sharedaudit.DecisionInsufficient, AmountMinor: 100, Reason: vo.ErrInsufficientBalance.Error()},
+		},
+		{
+			name:   "records frozen wallet as rejected",
+			amount: 100,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().
+					WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{}, vo.ErrWalletFrozen)
+			},
+			expected: sharedaudit.Event{Decision: sharedaudit.DecisionRejected, AmountMinor: 100, Reason: vo.ErrWalletFrozen.Error()},
+		},
+		{
+			name:     "records invalid amount",
+			amount:   0,
+			expected: sharedaudit.Event{Decision: sharedaudit.DecisionInvalid, Reason: vo.ErrInvalidAmount.Error()},
+		},
+		{
+			name:   "records unexpected error",
+			amount: 100,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().
+					WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(0), mock.Anything).
+					Return(domain.WalletBalance{}, repoErr)
+			},
+			expected: sharedaudit.Event{Decision: sharedaudit.DecisionError, AmountMinor: 100, Reason: repoErr.Error()},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			auditor := auditmocks.NewAuditor(s.T())
+			s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, WithdrawAmountLimits{}, nil, auditor, nil)
+			s.service.now = func() time.Time { return now }
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			expected := tc.expected
+			expected.Action = "withdraw"
+			expected.UserID = "user-1"
+			expected.ChainID = "chain-1"
+			expected.RequestID = "req-1"
+			expected.OccurredAt = now
+			auditor.EXPECT().Record(mock.Anything, expected).Once()
+
+			ctx := sharedaudit.WithRequestID(context.Background(), "req-1")
+			_, _ = s.service.WithdrawBalance(ctx, "user-1", tc.amount, "chain-1", "")
+		})
+	}
+}
+
+// TestWithdrawBalance_Events_TableDriven checks that withdrawal.completed, queued by
+// CompleteWithdrawalByReferenceID, is only emitted once the provider accepted the payout.
+func (s *InquiryWithdrawBalanceServiceSuite) TestWithdrawBalance_Events_TableDriven() {
+	repoErr := errors.New("repository failure")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fees := FlatFeeCalculator{FeeMinor: 25}
+
+	tests := []struct {
+		name      string
+		setupMock func(*servicemocks.PayoutClient)
+		expectErr error
+	}{
+		{
+			name: "completes after successful payout",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 875, Currency: "IDR", UpdatedAt: now}, nil)
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{ProviderReference: "prov-1"}, nil)
+				s.repository.EXPECT().CompleteWithdrawalByReferenceID(mock.Anything, "ref-1").Return(nil).Once()
+			},
+		},
+		{
+			name: "does not complete when debit fails",
+			setupMock: func(_ *servicemocks.PayoutClient) {
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25), mock.Anything).
+					Return(domain.WalletBalance{}, repoErr)
+			},
+			expectErr: repoErr,
+		},
+		{
+			name: "does not complete when payout is reversed",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 875, Currency: "IDR", UpdatedAt: now}, nil)
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{}, &payout.ProviderError{StatusCode: 422, Message: "account closed"})
+				s.repository.EXPECT().ReverseWithdrawalByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25)).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 1_000, Currency: "IDR"}, nil)
+			},
+			expectErr: vo.ErrPayoutRejected,
+		},
+		{
+			name: "does not complete while payout outcome is unknown",
+			setupMock: func(client *servicemocks.PayoutClient) {
+				s.repository.EXPECT().WithdrawWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "chain-1", "ref-1", int64(25), mock.Anything).
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 875, Currency: "IDR", UpdatedAt: now}, nil)
+				client.EXPECT().Payout(mock.Anything, mock.Anything).Return(payout.Result{}, &payout.ProviderError{StatusCode: 504, Retryable: true, Ambiguous: true})
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			client := servicemocks.NewPayoutClient(s.T())
+			s.service = NewInquiryWithdrawBalanceService(s.repository, s.referenceID, nil, WithdrawAmountLimits{}, fees, nil, client)
+
+			s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+			tc.setupMock(client)
+
+			result, err := s.service.WithdrawBalance(context.Background(), "user-1", 100, "chain-1", "")
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), "ref-1", result.ReferenceID)
+		})
+	}
+}
+
+func (s *InquiryWithdrawBalanceServiceSuite) TestGetWithdrawal_TableDriven() {
+	repoErr := errors.New("repository failure")
+	now := time.Now().UTC()
+	record := domain.WithdrawalRecord{
+		ReferenceID: "ref-1",
+		UserID:      "user-1",
+		AmountMinor: 100,
+		FeeMinor:    5,
+		Currency:    "IDR",
+		ChainID:     "chain-1",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	reversed := record
+	reversed.Reversed = true
+	pending := record
+	pending.Pending = true
+
+	tests := []struct {
+		name        string
+		referenceID string
+		setupMock   func()
+		expectErr   error
+		expected    string
+	}{
+		{
+			name:        "blank reference id",
+			referenceID: " ",
+			expectErr:   vo.ErrWithdrawalNotFound,
+		},
+		{
+			name:        "completed withdrawal",
+			referenceID: "ref-1",
+			setupMock: func() {
+				s.repository.EXPECT().GetWithdrawalByReferenceID(mock.Anything, "user-1", "ref-1").Return(record, nil)
+			},
+			expected: vo.WithdrawalStatusCompleted,
+		},
+		{
+			name:        "reversed withdrawal is failed",
+			referenceID: "ref-1",
+			setupMock: func() {
+				s.repository.EXPECT().GetWithdrawalByReferenceID(mock.Anything, "user-1", "ref-1").Return(reversed, nil)
+			},
+			expected: vo.WithdrawalStatusFailed,
+		},
+		{
+			name:        "withdrawal awaiting payout outcome is pending",
+			referenceID: "ref-1",
+			setupMock: func() {
+				s.repository.EXPECT().GetWithdrawalByReferenceID(mock.Anything, "user-1", "ref-1").Return(pending, nil)
+			},
+			expected: vo.WithdrawalStatusPending,
+		},
+		{
+			name:        "propagates repository error",
+			referenceID: "ref-1",
+			setupMock: func() {
+				s.repository.EXPECT().GetWithdrawalByReferenceID(mock.Anything, "user-1", "ref-1").Return(domain.WithdrawalRecord{}, repoErr)
+			},
+			expectErr: repoErr,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			result, err := s.service.GetWithdrawal(context.Background(), "user-1", tc.referenceID)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expected, result.Status)
+			assert.Equal(s.T(), "ref-1", result.ReferenceID)
+			assert.Equal(s.T(), int64(100), result.AmountMinor)
+			assert.Equal(s.T(), int64(5), result.FeeMinor)
+		})
+	}
+}
+
+func TestInquiryWithdrawBalanceServiceSuite(t *testing.T) {
+	suite.Run(t, new(InquiryWithdrawBalanceServiceSuite))
+}
+
+type WalletAdjustBalanceServiceSuite struct {
+	suite.Suite
+
+	repository  *servicemocks.WalletAdjustBalanceRepository
+	referenceID *uidmocks.UIDGenerator
+	service     *WalletAdjustBalanceService
+}
+
+func (s *WalletAdjustBalanceServiceSuite) SetupTest() {
+	s.repository = servicemocks.NewWalletAdjustBalanceRepository(s.T())
+	s.referenceID = uidmocks.NewUIDGenerator(s.T())
+	s.service = NewWalletAdjustBalanceService(s.repository, s.referenceID)
+}
+
+func (s *WalletAdjustBalanceServiceSuite) TestAdjustBalance_TableDriven() {
+	repoErr := errors.New("repository failure")
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		userID    string
+		delta     int64
+		setupMock func()
+		assertion func(vo.WalletAdjustment, error)
+	}{
+		{
+			name:   "wallet not found when user empty",
+			userID: " ",
+			delta:  100,
+			assertion: func(result vo.WalletAdjustment, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+				assert.Equal(s.T(), vo.WalletAdjustment{}, result)
+			},
+		},
+		{
+			name:   "zero delta is invalid",
+			userID: "user-1",
+			delta:  0,
+			assertion: func(result vo.WalletAdjustment, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidAmount)
+				assert.Equal(s.T(), vo.WalletAdjustment{}, result)
+			},
+		},
+		{
+			name:   "propagates repository error",
+			userID: "user-1",
+			delta:  -100,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().AdjustWalletBalanceByUserID(mock.Anything, "user-1", int64(-100), "ref-1").Return(domain.WalletBalance{}, repoErr)
+			},
+			assertion: func(result vo.WalletAdjustment, err error) {
+				assert.ErrorIs(s.T(), err, repoErr)
+				assert.Equal(s.T(), vo.WalletAdjustment{}, result)
+			},
+		},
+		{
+			name:   "success",
+			userID: "user-1",
+			delta:  250,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().AdjustWalletBalanceByUserID(mock.Anything, "user-1", int64(250), "ref-1").
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 1250, Currency: "IDR", UpdatedAt: now}, nil)
+			},
+			assertion: func(result vo.WalletAdjustment, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.WalletAdjustment{
+					ReferenceID:  "ref-1",
+					UserID:       "user-1",
+					AmountMinor:  250,
+					BalanceMinor: 1250,
+					Currency:     "IDR",
+					UpdatedAt:    now,
+				}, result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			result, err := s.service.AdjustBalance(context.Background(), tc.userID, tc.delta)
+			tc.assertion(result, err)
+		})
+	}
+}
+
+func TestWalletAdjustBalanceServiceSuite(t *testing.T) {
+	suite.Run(t, new(WalletAdjustBalanceServiceSuite))
+}
+
+type WalletStatusServiceSuite struct {
+	suite.Suite
+
+	repository *servicemocks.WalletStatusRepository
+	service    *WalletStatusService
+}
+
+func (s *WalletStatusServiceSuite) SetupTest() {
+	s.repository = servicemocks.NewWalletStatusRepository(s.T())
+	s.service = NewWalletStatusService(s.repository)
+}
+
+func (s *WalletStatusServiceSuite) TestSetStatus_TableDriven() {
+	repoErr := errors.New("repository failure")
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		userID    string
+		setupMock func()
+		assertion func(vo.WalletStatus, error)
+	}{
+		{
+			name:   "wallet not found when user empty",
+			userID: " ",
+			assertion: func(result vo.WalletStatus, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+				assert.Equal(s.T(), vo.WalletStatus{}, result)
+			},
+		},
+		{
+			name:   "propagates repository error",
+			userID: "user-1",
+			setupMock: func() {
+				s.repository.EXPECT().SetWalletStatusByUserID(mock.Anything, "user-1", vo.WalletStatusFrozen).Return(domain.WalletStatus{}, repoErr)
+			},
+			assertion: func(result vo.WalletStatus, err error) {
+				assert.ErrorIs(s.T(), err, repoErr)
+				assert.Equal(s.T(), vo.WalletStatus{}, result)
+			},
+		},
+		{
+			name:   "success",
+			userID: "user-1",
+			setupMock: func() {
+				s.repository.EXPECT().SetWalletStatusByUserID(mock.Anything, "user-1", vo.WalletStatusFrozen).
+					Return(domain.WalletStatus{UserID: "user-1", Status: vo.WalletStatusFrozen, UpdatedAt: now}, nil)
+			},
+			assertion: func(result vo.WalletStatus, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.WalletStatus{UserID: "user-1", Status: vo.WalletStatusFrozen, UpdatedAt: now}, result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			result, err := s.service.SetStatus(context.Background(), tc.userID, vo.WalletStatusFrozen)
+			tc.assertion(result, err)
+		})
+	}
+}
+
+func TestWalletStatusServiceSuite(t *testing.T) {
+	suite.Run(t, new(WalletStatusServiceSuite))
+}
+
+type DepositBalanceServiceSuite struct {
+	suite.Suite
+
+	repository  *servicemocks.BalanceDepositRepository
+	referenceID *uidmocks.UIDGenerator
+	service     *DepositBalanceService
+}
+
+func (s *DepositBalanceServiceSuite) SetupTest() {
+	s.repository = servicemocks.NewBalanceDepositRepository(s.T())
+	s.referenceID = uidmocks.NewUIDGenerator(s.T())
+	s.service = NewDepositBalanceService(s.repository, s.referenceID, nil)
+}
+
+func (s *DepositBalanceServiceSuite) TestDepositBalance_TableDriven() {
+	repoErr := errors.New("repository failure")
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		userID    string
+		amount    int64
+		setupMock func()
+		assertion func(vo.WalletDeposit, error)
+	}{
+		{
+			name:   "wallet not found when user empty",
+			userID: " ",
+			amount: 100,
+			assertion: func(result vo.WalletDeposit, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+				assert.Equal(s.T(), vo.WalletDeposit{}, result)
+			},
+		},
+		{
+			name:   "invalid amount",
+			userID: "user-1",
+			amount: 0,
+			assertion: func(result vo.WalletDeposit, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidAmount)
+				assert.Equal(s.T(), vo.WalletDeposit{}, result)
+			},
+		},
+		{
+			name:   "reference generator error",
+			userID: "user-1",
+			amount: 100,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("", repoErr)
+			},
+			assertion: func(result vo.WalletDeposit, err error) {
+				assert.ErrorIs(s.T(), err, repoErr)
+				assert.Equal(s.T(), vo.WalletDeposit{}, result)
+			},
+		},
+		{
+			name:   "propagates wallet not found from repository",
+			userID: "user-1",
+			amount: 100,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().DepositWalletBalanceByUserID(mock.Anything, "user-1", int64(100), "ref-1").Return(domain.WalletBalance{}, vo.ErrWalletNotFound)
+			},
+			assertion: func(result vo.WalletDeposit, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+				assert.Equal(s.T(), vo.WalletDeposit{}, result)
+			},
+		},
+		{
+			name:   "success",
+			userID: "user-1",
+			amount: 250,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().DepositWalletBalanceByUserID(mock.Anything, "user-1", int64(250), "ref-1").
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 1250, Currency: "IDR", UpdatedAt: now}, nil)
+			},
+			assertion: func(result vo.WalletDeposit, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.WalletDeposit{
+					ReferenceID:  "ref-1",
+					UserID:       "user-1",
+					AmountMinor:  250,
+					BalanceMinor: 1250,
+					Currency:     "IDR",
+					UpdatedAt:    now,
+				}, result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			result, err := s.service.DepositBalance(context.Background(), tc.userID, tc.amount)
+			tc.assertion(result, err)
+		})
+	}
+}
+
+func (s *DepositBalanceServiceSuite) TestDepositBalance_Events_TableDriven() {
+	repoErr := errors.New("repository failure")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		repoErr    error
+		publishErr error
+	}{
+		{name: "publishes after commit"},
+		{name: "publish failure keeps the deposit", publishErr: errors.New("broker unavailable")},
+		{name: "does not publish when deposit fails", repoErr: repoErr},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			publisher := eventmocks.NewPublisher(s.T())
+			s.service = NewDepositBalanceService(s.repository, s.referenceID, publisher)
+
+			s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+			if tc.repoErr != nil {
+				s.repository.EXPECT().DepositWalletBalanceByUserID(mock.Anything, "user-1", int64(250), "ref-1").Return(domain.WalletBalance{}, tc.repoErr)
+			} else {
+				s.repository.EXPECT().DepositWalletBalanceByUserID(mock.Anything, "user-1", int64(250), "ref-1").
+					Return(domain.WalletBalance{UserID: "user-1", BalanceMinor: 1250, Currency: "IDR", UpdatedAt: now}, nil)
+				publisher.EXPECT().Publish(mock.Anything, sharedevents.Event{
+					Type:          sharedevents.TypeDepositCompleted,
+					TransactionID: "ref-1",
+					UserID:        "user-1",
+					AmountMinor:   250,
+					Currency:      "IDR",
+					OccurredAt:    now,
+				}).Return(tc.publishErr).Once()
+			}
+
+			result, err := s.service.DepositBalance(context.Background(), "user-1", 250)
+			if tc.repoErr != nil {
+				assert.ErrorIs(s.T(), err, tc.repoErr)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), int64(1250), result.BalanceMinor)
+		})
+	}
+}
+
+func TestDepositBalanceServiceSuite(t *testing.T) {
+	suite.Run(t, new(DepositBalanceServiceSuite))
+}
+
+type AuthRegisterServiceSuite struct {
+	suite.Suite
+
+	users   *servicemocks.AuthRegisterRepository
+	wallets *servicemocks.WalletCreateRepository
+	hasher  *hashmocks.Hasher
+	ids     *uidmocks.UIDGenerator
+	service *AuthRegisterService
+}
+
+func (s *AuthRegisterServiceSuite) SetupTest() {
+	s.users = servicemocks.NewAuthRegisterRepository(s.T())
+	s.wallets = servicemocks.NewWalletCreateRepository(s.T())
+	s.hasher = hashmocks.NewHasher(s.T())
+	s.ids = uidmocks.NewUIDGenerator(s.T())
+	passwords := NewPasswordValidator(sharedpassword.Policy{MinLength: 10, RequireUpper: true, RequireDigit: true})
+	s.service = NewAuthRegisterService(s.users, s.wallets, s.hasher, passwords, s.ids)
+}
+
+func (s *AuthRegisterServiceSuite) TestRegister_TableDriven() {
+	now := time.Now().UTC()
+	walletErr := errors.New("wallet db down")
+	deleteErr := errors.New("auth db down")
+
+	tests := []struct {
+		name      string
+		email     string
+		password  string
+		setupMock func()
+		assertion func(vo.AuthRegistered, error)
+	}{
+		{
+			name:     "malformed email is rejected",
+			email:    "Jane <jane@example.com>",
+			password: "Withdraw2026",
+			assertion: func(result vo.AuthRegistered, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidEmail)
+				assert.Equal(s.T(), vo.AuthRegistered{}, result)
+			},
+		},
+		{
+			name:     "weak password is rejected before hashing",
+			email:    "jane@example.com",
+			password: "withdraw",
+			assertion: func(result vo.AuthRegistered, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWeakPassword)
+				var weak *vo.WeakPasswordError
+				require.ErrorAs(s.T(), err, &weak)
+				assert.Equal(s.T(), []string{sharedpassword.CriterionMinLength, sharedpassword.CriterionUpper, sharedpassword.CriterionDigit}, weak.Unmet)
+				assert.Equal(s.T(), vo.AuthRegistered{}, result)
+			},
+		},
+		{
+			name:     "duplicate email does not open a wallet",
+			email:    "jane@example.com",
+			password: "Withdraw2026",
+			setupMock: func() {
+				s.hasher.EXPECT().Hash(mock.Anything, "Withdraw2026").Return("hashed", nil)
+				s.ids.EXPECT().Generate(mock.Anything).Return("user-1", nil).Once()
+				s.users.EXPECT().CreateUser(mock.Anything, "user-1", "jane@example.com", "hashed").Return(time.Time{}, vo.ErrEmailAlreadyRegistered)
+			},
+			assertion: func(result vo.AuthRegistered, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrEmailAlreadyRegistered)
+				assert.Equal(s.T(), vo.AuthRegistered{}, result)
+			},
+		},
+		{
+			name:     "wallet failure deletes the new user",
+			email:    "jane@example.com",
+			password: "Withdraw2026",
+			setupMock: func() {
+				s.hasher.EXPECT().Hash(mock.Anything, "Withdraw2026").Return("hashed", nil)
+				s.ids.EXPECT().Generate(mock.Anything).Return("user-1", nil).Once()
+				s.users.EXPECT().CreateUser(mock.Anything, "user-1", "jane@example.com", "hashed").Return(now, nil)
+				s.ids.EXPECT().Generate(mock.Anything).Return("wallet-1", nil).Once()
+				s.wallets.EXPECT().CreateWallet(mock.Anything, "wallet-1", "user-1", "IDR").Return(domain.Wallet{}, walletErr)
+				s.users.EXPECT().DeleteUser(mock.Anything, "user-1").Return(nil)
+			},
+			assertion: func(result vo.AuthRegistered, err error) {
+				assert.ErrorIs(s.T(), err, walletErr)
+				assert.Equal(s.T(), vo.AuthRegistered{}, result)
+			},
+		},
+		{
+			name:     "failed compensation is reported with the wallet error",
+			email:    "jane@example.com",
+			password: "Withdraw2026",
+			setupMock: func() {
+				s.hasher.EXPECT().Hash(mock.Anything, "Withdraw2026").Return("hashed", nil)
+				s.ids.EXPECT().Generate(mock.Anything).Return("user-1", nil).Once()
+				s.users.EXPECT().CreateUser(mock.Anything, "user-1", "jane@example.com", "hashed").Return(now, nil)
+				s.ids.EXPECT().Generate(mock.Anything).Return("wallet-1", nil).Once()
+				s.wallets.EXPECT().CreateWallet(mock.Anything, "wallet-1", "user-1", "IDR").Return(domain.Wallet{}, walletErr)
+				s.users.EXPECT().DeleteUser(mock.Anything, "user-1").Return(deleteErr)
+			},
+			assertion: func(result vo.AuthRegistered, err error) {
+				assert.ErrorIs(s.T(), err, walletErr)
+				assert.ErrorIs(s.T(), err, deleteErr)
+				assert.Equal(s.T(), vo.AuthRegistered{}, result)
+			},
+		},
+		{
+			name:     "success creates user and wallet",
+			email:    "  Jane@Example.com ",
+			password: "Withdraw2026",
+			setupMock: func() {
+				s.hasher.EXPECT().Hash(mock.Anything, "Withdraw2026").Return("hashed", nil)
+				s.ids.EXPECT().Generate(mock.Anything).Return("user-1", nil).Once()
+				s.users.EXPECT().CreateUser(mock.Anything, "user-1", "jane@example.com", "hashed").Return(now, nil)
+				s.ids.EXPECT().Generate(mock.Anything).Return("wallet-1", nil).Once()
+				s.wallets.EXPECT().CreateWallet(mock.Anything, "wallet-1", "user-1", "IDR").
+					Return(domain.Wallet{ID: "wallet-1", UserID: "user-1", Currency: "IDR", CreatedAt: now}, nil)
+			},
+			assertion: func(result vo.AuthRegistered, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.AuthRegistered{
+					UserID:    "user-1",
+					Email:     "jane@example.com",
+					WalletID:  "wallet-1",
+					Currency:  "IDR",
+					CreatedAt: now,
+				}, result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			result, err := s.service.Register(context.Background(), tc.email, tc.password)
+			tc.assertion(result, err)
+		})
+	}
+}
+
+func TestAuthRegisterServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuthRegisterServiceSuite))
+}
+
+type AuthChangePasswordServiceSuite struct {
+	suite.Suite
+
+	repository *servicemocks.AuthChangePasswordRepository
+	hasher     *hashmocks.Hasher
+	service    *AuthChangePasswordService
+}
+
+func (s *AuthChangePasswordServiceSuite) SetupTest() {
+	s.repository = servicemocks.NewAuthChangePasswordRepository(s.T())
+	s.hasher = hashmocks.NewHasher(s.T())
+	passwords := NewPasswordValidator(sharedpassword.Policy{MinLength: 10, RequireUpper: true, RequireDigit: true})
+	s.service = NewAuthChangePasswordService(s.repository, s.hasher, passwords)
+}
+
+func (s *AuthChangePasswordServiceSuite) TestChangePassword_TableDriven() {
+	repoErr := errors.New("repository failure")
+
+	tests := []struct {
+		name            string
+		userID          string
+		currentPassword string
+		newPassword     string
+		setupMock       func()
+		assertion       func(error)
+	}{
+		{
+			name:            "missing user is unauthenticated",
+			userID:          " ",
+			currentPassword: "OldPassw0rd",
+			newPassword:     "NewPassw0rd",
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidCredentials)
+			},
+		},
+		{
+			name:            "unknown user",
+			userID:          "user-1",
+			currentPassword: "OldPassw0rd",
+			newPassword:     "NewPassw0rd",
+			setupMock: func() {
+				s.repository.EXPECT().GetPasswordHash(mock.Anything, "user-1").Return("", vo.ErrInvalidCredentials)
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidCredentials)
+			},
+		},
+		{
+			name:            "wrong current password",
+			userID:          "user-1",
+			currentPassword: "WrongPassw0rd",
+			newPassword:     "NewPassw0rd",
+			setupMock: func() {
+				s.repository.EXPECT().GetPasswordHash(mock.Anything, "user-1").Return("old-hash", nil)
+				s.hasher.EXPECT().Compare(mock.Anything, "old-hash", "WrongPassw0rd").Return(errors.New("mismatch"))
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidCredentials)
+			},
+		},
+		{
+			name:            "weak new password",
+			userID:          "user-1",
+			currentPassword: "OldPassw0rd",
+			newPassword:     "weak",
+			setupMock: func() {
+				s.repository.EXPECT().GetPasswordHash(mock.Anything, "user-1").Return("old-hash", nil)
+				s.hasher.EXPECT().Compare(mock.Anything, "old-hash", "OldPassw0rd").Return(nil)
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWeakPassword)
+				var weak *vo.WeakPasswordError
+				require.ErrorAs(s.T(), err, &weak)
+				assert.Equal(s.T(), []string{sharedpassword.CriterionMinLength, sharedpassword.CriterionUpper, sharedpassword.CriterionDigit}, weak.Unmet)
+			},
+		},
+		{
+			name:            "update error is returned",
+			userID:          "user-1",
+			currentPassword: "OldPassw0rd",
+			newPassword:     "NewPassw0rd",
+			setupMock: func() {
+				s.repository.EXPECT().GetPasswordHash(mock.Anything, "user-1").Return("old-hash", nil)
+				s.hasher.EXPECT().Compare(mock.Anything, "old-hash", "OldPassw0rd").Return(nil)
+				s.hasher.EXPECT().Hash(mock.Anything, "NewPassw0rd").Return("new-hash", nil)
+				s.repository.EXPECT().UpdatePasswordHash(mock.Anything, "user-1", "old-hash", "new-hash").Return(repoErr)
+			},
+			assertion: func(err error) {
+				assert.ErrorIs(s.T(), err, repoErr)
+			},
+		},
+		{
+			name:            "success stores the new hash",
+			userID:          "user-1",
+			currentPassword: "OldPassw0rd",
+			newPassword:     "NewPassw0rd",
+			setupMock: func() {
+				s.repository.EXPECT().GetPasswordHash(mock.Anything, "user-1").Return("old-hash", nil)
+				s.hasher.EXPECT().Compare(mock.Anything, "old-hash", "OldPassw0rd").Return(nil)
+				s.hasher.EXPECT().Hash(mock.Anything, "NewPassw0rd").Return("new-hash", nil)
+				s.repository.EXPECT().UpdatePasswordHash(mock.Anything, "user-1", "old-hash", "new-hash").Return(nil)
+			},
+			assertion: func(err error) {
+				require.NoError(s.T(), err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			tc.assertion(s.service.ChangePassword(context.Background(), tc.userID, tc.currentPassword, tc.newPassword))
+		})
+	}
+}
+
+func TestAuthChangePasswordServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuthChangePasswordServiceSuite))
+}
+
+type WalletCreateServiceSuite struct {
+	suite.Suite
+
+	repository *servicemocks.WalletCreateRepository
+	walletID   *uidmocks.UIDGenerator
+	notFound   *negcachemocks.Cache
+	service    *WalletCreateService
+}
+
+func (s *WalletCreateServiceSuite) SetupTest() {
+	s.repository = servicemocks.NewWalletCreateRepository(s.T())
+	s.walletID = uidmocks.NewUIDGenerator(s.T())
+	s.notFound = negcachemocks.NewCache(s.T())
+	s.service = NewWalletCreateService(s.repository, s.walletID, s.notFound)
+}
+
+func (s *WalletCreateServiceSuite) TestCreateWallet_TableDriven() {
+	generatorErr := errors.New("generator failure")
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		userID    string
+		currency  string
+		setupMock func()
+		assertion func(vo.WalletCreated, error)
+	}{
+		{
+			name:   "wallet not found when user empty",
+			userID: " ",
+			assertion: func(result vo.WalletCreated, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+				assert.Equal(s.T(), vo.WalletCreated{}, result)
+			},
+		},
+		{
+			name:     "wallet id generator error",
+			userID:   "user-1",
+			currency: "USD",
+			setupMock: func() {
+				s.walletID.EXPECT().Generate(mock.Anything).Return("", generatorErr)
+			},
+			assertion: func(result vo.WalletCreated, err error) {
+				assert.ErrorIs(s.T(), err, generatorErr)
+				assert.Equal(s.T(), vo.WalletCreated{}, result)
+			},
+		},
+		{
+			name:     "propagates existing wallet from repository",
+			userID:   "user-1",
+			currency: "USD",
+			setupMock: func() {
+				s.walletID.EXPECT().Generate(mock.Anything).Return("wallet-1", nil)
+				s.repository.EXPECT().CreateWallet(mock.Anything, "wallet-1", "user-1", "USD").Return(domain.Wallet{}, vo.ErrWalletAlreadyExists)
+			},
+			assertion: func(result vo.WalletCreated, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletAlreadyExists)
+				assert.Equal(s.T(), vo.WalletCreated{}, result)
+			},
+		},
+		{
+			name:   "empty currency defaults to IDR",
+			userID: "user-1",
+			setupMock: func() {
+				s.walletID.EXPECT().Generate(mock.Anything).Return("wallet-1", nil)
+				s.repository.EXPECT().CreateWallet(mock.Anything, "wallet-1", "user-1", "IDR").
+					Return(domain.Wallet{ID: "wallet-1", UserID: "user-1", Currency: "IDR", CreatedAt: now}, nil)
+				s.notFound.EXPECT().Remove(mock.Anything, "user-1").Return(nil)
+			},
+			assertion: func(result vo.WalletCreated, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), "IDR", result.Currency)
+			},
+		},
+		{
+			name:     "success normalizes currency",
+			userID:   "user-1",
+			currency: " usd ",
+			setupMock: func() {
+				s.walletID.EXPECT().Generate(mock.Anything).Return("wallet-1", nil)
+				s.repository.EXPECT().CreateWallet(mock.Anything, "wallet-1", "user-1", "USD").
+					Return(domain.Wallet{ID: "wallet-1", UserID: "user-1", Currency: "USD", CreatedAt: now, UpdatedAt: now}, nil)
+				s.notFound.EXPECT().Remove(mock.Anything, "user-1").Return(nil)
+			},
+			assertion: func(result vo.WalletCreated, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.WalletCreated{
+					WalletID:     "wallet-1",
+					UserID:       "user-1",
+					BalanceMinor: 0,
+					Currency:     "USD",
+					CreatedAt:    now,
+				}, result)
+			},
+		},
+		{
+			name:   "not-found cache failure does not fail creation",
+			userID: "user-1",
+			setupMock: func() {
+				s.walletID.EXPECT().Generate(mock.Anything).Return("wallet-1", nil)
+				s.repository.EXPECT().CreateWallet(mock.Anything, "wallet-1", "user-1", "IDR").
+					Return(domain.Wallet{ID: "wallet-1", UserID: "user-1", Currency: "IDR", CreatedAt: now}, nil)
+				s.notFound.EXPECT().Remove(mock.Anything, "user-1").Return(errors.New("redis down"))
+			},
+			assertion: func(result vo.WalletCreated, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), "wallet-1", result.WalletID)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			result, err := s.service.CreateWallet(context.Background(), tc.userID, tc.currency)
+			tc.assertion(result, err)
+		})
+	}
+}
+
+func TestWalletCreateServiceSuite(t *testing.T) {
+	suite.Run(t, new(WalletCreateServiceSuite))
+}
+
+type TransferServiceSuite struct {
+	suite.Suite
+
+	repository  *servicemocks.BalanceTransferRepository
+	referenceID *uidmocks.UIDGenerator
+	service     *TransferService
+}
+
+func (s *TransferServiceSuite) SetupTest() {
+	s.repository = servicemocks.NewBalanceTransferRepository(s.T())
+	s.referenceID = uidmocks.NewUIDGenerator(s.T())
+	s.service = NewTransferService(s.repository, s.referenceID)
+}
+
+func (s *TransferServiceSuite) TestTransferBalance_TableDriven() {
+	repoErr := errors.New("repository failure")
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name          string
+		userID        string
+		destinationID string
+		amount        int64
+		setupMock     func()
+		assertion     func(vo.WalletTransfer, error)
+	}{
+		{
+			name:          "wallet not found when user empty",
+			userID:        " ",
+			destinationID: "wallet-b",
+			amount:        100,
+			assertion: func(_ vo.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrWalletNotFound)
+			},
+		},
+		{
+			name:          "invalid amount",
+			userID:        "user-1",
+			destinationID: "wallet-b",
+			assertion: func(_ vo.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrInvalidAmount)
+			},
+		},
+		{
+			name:          "same wallet rejected",
+			userID:        "user-1",
+			destinationID: " WALLET-A ",
+			amount:        100,
+			assertion: func(_ vo.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, vo.ErrSameWalletTransfer)
+			},
+		},
+		{
+			name:          "propagates repository error",
+			userID:        "user-1",
+			destinationID: "wallet-b",
+			amount:        100,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().TransferWalletBalance(mock.Anything, "user-1", "wallet-a", "wallet-b", int64(100), "ref-1").Return(domain.WalletTransfer{}, repoErr)
+			},
+			assertion: func(_ vo.WalletTransfer, err error) {
+				assert.ErrorIs(s.T(), err, repoErr)
+			},
+		},
+		{
+			name:          "success",
+			userID:        "user-1",
+			destinationID: "wallet-b",
+			amount:        100,
+			setupMock: func() {
+				s.referenceID.EXPECT().Generate(mock.Anything).Return("ref-1", nil)
+				s.repository.EXPECT().TransferWalletBalance(mock.Anything, "user-1", "wallet-a", "wallet-b", int64(100), "ref-1").Return(domain.WalletTransfer{
+					UserID:                  "user-1",
+					SourceWalletID:          "wallet-a",
+					DestinationWalletID:     "wallet-b",
+					SourceBalanceMinor:      900,
+					DestinationBalanceMinor: 300,
+					Currency:                "IDR",
+					UpdatedAt:               now,
+				}, nil)
+			},
+			assertion: func(result vo.WalletTransfer, err error) {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), vo.WalletTransfer{
+					ReferenceID:             "ref-1",
+					UserID:                  "user-1",
+					SourceWalletID:          "wallet-a",
+					DestinationWalletID:     "wallet-b",
+					AmountMinor:             100,
+					SourceBalanceMinor:      900,
+					DestinationBalanceMinor: 300,
+					Currency:                "IDR",
+					UpdatedAt:               now,
+				}, result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			result, err := s.service.TransferBalance(context.Background(), tc.userID, "wallet-a", tc.destinationID, tc.amount)
+			tc.assertion(result, err)
+		})
+	}
+}
+
+func TestTransferServiceSuite(t *testing.T) {
+	suite.Run(t, new(TransferServiceSuite))
+}
+
+type TransactionExportServiceSuite struct {
+	suite.Suite
+
+	repository *servicemocks.TransactionExportRepository
+	service    *TransactionExportService
+}
+
+func (s *TransactionExportServiceSuite) SetupTest() {
+	s.repository = servicemocks.NewTransactionExportRepository(s.T())
+	s.service = NewTransactionExportService(s.repository)
+}
+
+func (s *TransactionExportServiceSuite) TestExportTransactions_TableDriven() {
+	repositoryErr := errors.New("repository failure")
+	emitErr := errors.New("client gone")
+	now := time.Now().UTC()
+	entries := []domain.LedgerEntry{
+		{ID: "entry-1", WalletID: "wallet-1", EntryType: "deposit", AmountMinor: 1000, BalanceAfterMinor: 1000, Currency: "IDR", ReferenceID: "ref-1", CreatedAt: now},
+		{ID: "entry-2", WalletID: "wallet-1", EntryType: "withdrawal", AmountMinor: -400, BalanceAfterMinor: 600, Currency: "IDR", ChainID: "polygon", CreatedAt: now.Add(time.Second)},
+	}
+	streamEntries := func(_ context.Context, _ string, emit func(domain.LedgerEntry) error) error {
+		for _, entry := range entries {
+			if err := emit(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name          string
+		userID        string
+		emitErr       error
+		setupMock     func()
+		expectErr     error
+		expectRecords []vo.TransactionRecord
+	}{
+		{
+			name:   "blank user is rejected",
+			userID: " ",
+		},
+		{
+			name:   "maps ledger entries in order",
+			userID: "user-1",
+			setupMock: func() {
+				s.repository.EXPECT().StreamLedgerByUserID(mock.Anything, "user-1", mock.Anything).RunAndReturn(streamEntries)
+			},
+			expectRecords: []vo.TransactionRecord{
+				{EntryID: "entry-1", WalletID: "wallet-1", EntryType: "deposit", AmountMinor: 1000, BalanceAfterMinor: 1000, Currency: "IDR", ReferenceID: "ref-1", CreatedAt: now},
+				{EntryID: "entry-2", WalletID: "wallet-1", EntryType: "withdrawal", AmountMinor: -400, BalanceAfterMinor: 600, Currency: "IDR", ChainID: "polygon", CreatedAt: now.Add(time.Second)},
+			},
+		},
+		{
+			name:    "emit error stops the stream",
+			userID:  "user-1",
+			emitErr: emitErr,
+			setupMock: func() {
+				s.repository.EXPECT().StreamLedgerByUserID(mock.Anything, "user-1", mock.Anything).RunAndReturn(streamEntries)
+			},
+			expectErr: emitErr,
+			expectRecords: []vo.TransactionRecord{
+				{EntryID: "entry-1", WalletID: "wallet-1", EntryType: "deposit", AmountMinor: 1000, BalanceAfterMinor: 1000, Currency: "IDR", ReferenceID: "ref-1", CreatedAt: now},
+			},
+		},
+		{
+			name:   "repository error is returned",
+			userID: "user-1",
+			setupMock: func() {
+				s.repository.EXPECT().StreamLedgerByUserID(mock.Anything, "user-1", mock.Anything).Return(repositoryErr)
+			},
+			expectErr: repositoryErr,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			var records []vo.TransactionRecord
+			err := s.service.ExportTransactions(context.Background(), tc.userID, func(record vo.TransactionRecord) error {
+				records = append(records, record)
+				return tc.emitErr
+			})
+
+			switch {
+			case tc.setupMock == nil:
+				require.Error(s.T(), err)
+			case tc.expectErr != nil:
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			default:
+				require.NoError(s.T(), err)
+			}
+			assert.Equal(s.T(), tc.expectRecords, records)
+		})
+	}
+}
+
+func TestTransactionExportServiceSuite(t *testing.T) {
+	suite.Run(t, new(TransactionExportServiceSuite))
+}
+
+type TransactionHistoryServiceSuite struct {
+	suite.Suite
+
+	repository *servicemocks.TransactionHistoryRepository
+	service    *TransactionHistoryService
+}
+
+func (s *TransactionHistoryServiceSuite) SetupTest() {
+	s.repository = servicemocks.NewTransactionHistoryRepository(s.T())
+	s.service = NewTransactionHistoryService(s.repository)
+}
+
+func (s *TransactionHistoryServiceSuite) TestListTransactions_TableDriven() {
+	repositoryErr := errors.New("repository failure")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	entry := func(id string, offset int) domain.LedgerEntry {
+		return domain.LedgerEntry{ID: id, WalletID: "wallet-1", EntryType: "deposit", AmountMinor: 100, Currency: "IDR", CreatedAt: now.Add(-time.Duration(offset) * time.Second)}
+	}
+	record := func(id string, offset int) vo.TransactionRecord {
+		return vo.TransactionRecord{EntryID: id, WalletID: "wallet-1", EntryType: "deposit", AmountMinor: 100, Currency: "IDR", CreatedAt: now.Add(-time.Duration(offset) * time.Second)}
+	}
+	cursor := pagination.Cursor{CreatedAt: now, ID: "entry-0"}
+
+	tests := []struct {
+		name       string
+		userID     string
+		cursor     string
+		limit      int
+		setupMock  func()
+		expectErr  error
+		expectPage vo.TransactionPage
+	}{
+		{
+			name:      "blank user is rejected",
+			userID:    " ",
+			expectErr: vo.ErrWalletNotFound,
+		},
+		{
+			name:      "malformed cursor is rejected",
+			userID:    "user-1",
+			cursor:    "not a cursor",
+			expectErr: vo.ErrInvalidCursor,
+		},
+		{
+			name:   "extra row yields next cursor from last item",
+			userID: "user-1",
+			limit:  2,
+			setupMock: func() {
+				s.repository.EXPECT().ListLedgerByUserID(mock.Anything, "user-1", (*pagination.Cursor)(nil), 3).
+					Return([]domain.LedgerEntry{entry("entry-1", 1), entry("entry-2", 2), entry("entry-3", 3)}, nil)
+			},
+			expectPage: vo.TransactionPage{
+				Items:      []vo.TransactionRecord{record("entry-1", 1), record("entry-2", 2)},
+				NextCursor: pagination.Cursor{CreatedAt: now.Add(-2 * time.Second), ID: "entry-2"}.Encode(),
+			},
+		},
+		{
+			name:   "last page has no next cursor",
+			userID: "user-1",
+			cursor: cursor.Encode(),
+			limit:  2,
+			setupMock: func() {
+				s.repository.EXPECT().ListLedgerByUserID(mock.Anything, "user-1", &cursor, 3).
+					Return([]domain.LedgerEntry{entry("entry-1", 1)}, nil)
+			},
+			expectPage: vo.TransactionPage{Items: []vo.TransactionRecord{record("entry-1", 1)}},
+		},
+		{
+			name:   "empty history returns empty items",
+			userID: "user-1",
+			setupMock: func() {
+				s.repository.EXPECT().ListLedgerByUserID(mock.Anything, "user-1", (*pagination.Cursor)(nil), pagination.DefaultPageSize+1).
+					Return([]domain.LedgerEntry{}, nil)
+			},
+			expectPage: vo.TransactionPage{Items: []vo.TransactionRecord{}},
+		},
+		{
+			name:   "limit above maximum is clamped",
+			userID: "user-1",
+			limit:  500,
+			setupMock: func() {
+				s.repository.EXPECT().ListLedgerByUserID(mock.Anything, "user-1", (*pagination.Cursor)(nil), pagination.MaxPageSize+1).
+					Return([]domain.LedgerEntry{}, nil)
+			},
+			expectPage: vo.TransactionPage{Items: []vo.TransactionRecord{}},
+		},
+		{
+			name:   "repository cursor error is mapped",
+			userID: "user-1",
+			cursor: cursor.Encode(),
+			setupMock: func() {
+				s.repository.EXPECT().ListLedgerByUserID(mock.Anything, "user-1", &cursor, pagination.DefaultPageSize+1).
+					Return(nil, fmt.Errorf("repository: invalid cursor id: %w", pagination.ErrInvalidCursor))
+			},
+			expectErr: vo.ErrInvalidCursor,
+		},
+		{
+			name:   "repository error is returned",
+			userID: "user-1",
+			setupMock: func() {
+				s.repository.EXPECT().ListLedgerByUserID(mock.Anything, "user-1", (*pagination.Cursor)(nil), pagination.DefaultPageSize+1).
+					Return(nil, repositoryErr)
+			},
+			expectErr: repositoryErr,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+			if tc.setupMock != nil {
+				tc.setupMock()
+			}
+
+			page, err := s.service.ListTransactions(context.Background(), tc.userID, tc.cursor, tc.limit)
+			if tc.expectErr != nil {
+				require.ErrorIs(s.T(), err, tc.expectErr)
+				assert.Equal(s.T(), vo.TransactionPage{}, page)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectPage, page)
+		})
+	}
+}
+
+func TestTransactionHistoryServiceSuite(t *testing.T) {
+	suite.Run(t, new(TransactionHistoryServiceSuite))
+}
+
+func TestPasswordValidator_TableDriven(t *testing.T) {
+	validator := NewPasswordValidator(sharedpassword.Policy{
+		MinLength:    10,
+		RequireUpper: true,
+		RequireDigit: true,
+		Denylist:     map[string]struct{}{"password123": {}},
+	})
+
+	tests := []struct {
+		name        string
+		password    string
+		expectUnmet []string
+	}{
+		{name: "acceptable", password: "Withdraw2026"},
+		{name: "too short", password: "Short1", expectUnmet: []string{sharedpassword.CriterionMinLength}},
+		{name: "missing class", password: "withdrawals", expectUnmet: []string{sharedpassword.CriterionUpper, sharedpassword.CriterionDigit}},
+		{name: "denylisted", password: "Password123", expectUnmet: []string{sharedpassword.CriterionCommon}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validator.Validate(tc.password)
+			if tc.expectUnmet == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, vo.ErrWeakPassword)
+			var weak *vo.WeakPasswordError
+			require.ErrorAs(t, err, &weak)
+			assert.Equal(t, tc.expectUnmet, weak.Unmet)
+		})
+	}
 }
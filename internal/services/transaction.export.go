@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+)
+
+type TransactionExportRepository interface {
+	StreamLedgerByUserID(ctx context.Context, userID string, emit func(domain.LedgerEntry) error) error
+}
+
+type TransactionExportService struct {
+	repository TransactionExportRepository
+}
+
+func NewTransactionExportService(repository TransactionExportRepository) *TransactionExportService {
+	return &TransactionExportService{repository: repository}
+}
+
+// ExportTransactions passes the user's ledger to emit one record at a time, oldest first.
+func (s *TransactionExportService) ExportTransactions(ctx context.Context, userID string, emit func(vo.TransactionRecord) error) error {
+	if strings.TrimSpace(userID) == "" {
+		return errors.New("user_id is required")
+	}
+
+	return s.repository.StreamLedgerByUserID(ctx, userID, func(entry domain.LedgerEntry) error {
+		return emit(toTransactionRecord(entry))
+	})
+}
+
+func toTransactionRecord(entry domain.LedgerEntry) vo.TransactionRecord {
+	return vo.TransactionRecord{
+		EntryID:           entry.ID,
+		WalletID:          entry.WalletID,
+		EntryType:         entry.EntryType,
+		AmountMinor:       entry.AmountMinor,
+		BalanceAfterMinor: entry.BalanceAfterMinor,
+		Currency:          entry.Currency,
+		ReferenceID:       entry.ReferenceID,
+		ChainID:           entry.ChainID,
+		CreatedAt:         entry.CreatedAt,
+	}
+}
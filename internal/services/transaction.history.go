@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/shared/pagination"
+)
+
+type TransactionHistoryRepository interface {
+	ListLedgerByUserID(ctx context.Context, userID string, cursor *pagination.Cursor, limit int) ([]domain.LedgerEntry, error)
+}
+
+type TransactionHistoryService struct {
+	repository TransactionHistoryRepository
+}
+
+func NewTransactionHistoryService(repository TransactionHistoryRepository) *TransactionHistoryService {
+	return &TransactionHistoryService{repository: repository}
+}
+
+// ListTransactions returns one page of the user's ledger, newest first. cursor is the
+// next_cursor of the previous page, or empty for the first page; limit is bounded by
+// pagination.PageSize.
+func (s *TransactionHistoryService) ListTransactions(ctx context.Context, userID, cursor string, limit int) (vo.TransactionPage, error) {
+	if strings.TrimSpace(userID) == "" {
+		return vo.TransactionPage{}, vo.ErrWalletNotFound
+	}
+
+	var after *pagination.Cursor
+	if strings.TrimSpace(cursor) != "" {
+		decoded, err := pagination.DecodeCursor(cursor)
+		if err != nil {
+			return vo.TransactionPage{}, vo.ErrInvalidCursor
+		}
+		after = &decoded
+	}
+
+	pageSize := pagination.PageSize(limit)
+
+	// One extra row tells whether another page follows without a separate count.
+	entries, err := s.repository.ListLedgerByUserID(ctx, userID, after, pageSize+1)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			return vo.TransactionPage{}, vo.ErrInvalidCursor
+		}
+		return vo.TransactionPage{}, err
+	}
+
+	page := vo.TransactionPage{Items: make([]vo.TransactionRecord, 0, min(len(entries), pageSize))}
+	if len(entries) > pageSize {
+		entries = entries[:pageSize]
+		last := entries[len(entries)-1]
+		page.NextCursor = pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	for _, entry := range entries {
+		page.Items = append(page.Items, toTransactionRecord(entry))
+	}
+
+	return page, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/shared/uid"
+)
+
+type BalanceTransferRepository interface {
+	TransferWalletBalance(ctx context.Context, userID, sourceWalletID, destinationWalletID string, amountMinor int64, referenceID string) (domain.WalletTransfer, error)
+}
+
+type TransferService struct {
+	repository  BalanceTransferRepository
+	referenceID uid.UIDGenerator
+}
+
+func NewTransferService(repository BalanceTransferRepository, referenceID uid.UIDGenerator) *TransferService {
+	return &TransferService{repository: repository, referenceID: referenceID}
+}
+
+func (s *TransferService) TransferBalance(ctx context.Context, userID, sourceWalletID, destinationWalletID string, amountMinor int64) (vo.WalletTransfer, error) {
+	if strings.TrimSpace(userID) == "" {
+		return vo.WalletTransfer{}, vo.ErrWalletNotFound
+	}
+
+	if amountMinor <= 0 {
+		return vo.WalletTransfer{}, vo.ErrInvalidAmount
+	}
+
+	sourceWalletID = strings.TrimSpace(sourceWalletID)
+	destinationWalletID = strings.TrimSpace(destinationWalletID)
+	if strings.EqualFold(sourceWalletID, destinationWalletID) {
+		return vo.WalletTransfer{}, vo.ErrSameWalletTransfer
+	}
+
+	referenceID, err := s.referenceID.Generate(ctx)
+	if err != nil {
+		return vo.WalletTransfer{}, fmt.Errorf("service: failed to generate reference id: %w", err)
+	}
+
+	transfer, err := s.repository.TransferWalletBalance(ctx, userID, sourceWalletID, destinationWalletID, amountMinor, referenceID)
+	if err != nil {
+		return vo.WalletTransfer{}, err
+	}
+
+	return vo.WalletTransfer{
+		ReferenceID:             referenceID,
+		UserID:                  transfer.UserID,
+		SourceWalletID:          transfer.SourceWalletID,
+		DestinationWalletID:     transfer.DestinationWalletID,
+		AmountMinor:             amountMinor,
+		SourceBalanceMinor:      transfer.SourceBalanceMinor,
+		DestinationBalanceMinor: transfer.DestinationBalanceMinor,
+		Currency:                transfer.Currency,
+		UpdatedAt:               transfer.UpdatedAt,
+	}, nil
+}
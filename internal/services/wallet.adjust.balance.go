@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/shared/uid"
+)
+
+type WalletAdjustBalanceRepository interface {
+	AdjustWalletBalanceByUserID(ctx context.Context, userID string, deltaMinor int64, referenceID string) (domain.WalletBalance, error)
+}
+
+type WalletAdjustBalanceService struct {
+	repository  WalletAdjustBalanceRepository
+	referenceID uid.UIDGenerator
+}
+
+func NewWalletAdjustBalanceService(repository WalletAdjustBalanceRepository, referenceID uid.UIDGenerator) *WalletAdjustBalanceService {
+	return &WalletAdjustBalanceService{repository: repository, referenceID: referenceID}
+}
+
+func (s *WalletAdjustBalanceService) AdjustBalance(ctx context.Context, userID string, deltaMinor int64) (vo.WalletAdjustment, error) {
+	if strings.TrimSpace(userID) == "" {
+		return vo.WalletAdjustment{}, vo.ErrWalletNotFound
+	}
+
+	if deltaMinor == 0 {
+		return vo.WalletAdjustment{}, vo.ErrInvalidAmount
+	}
+
+	referenceID, err := s.referenceID.Generate(ctx)
+	if err != nil {
+		return vo.WalletAdjustment{}, fmt.Errorf("service: failed to generate reference id: %w", err)
+	}
+
+	balance, err := s.repository.AdjustWalletBalanceByUserID(ctx, userID, deltaMinor, referenceID)
+	if err != nil {
+		return vo.WalletAdjustment{}, err
+	}
+
+	return vo.WalletAdjustment{
+		ReferenceID:  referenceID,
+		UserID:       balance.UserID,
+		AmountMinor:  deltaMinor,
+		BalanceMinor: balance.BalanceMinor,
+		Currency:     balance.Currency,
+		UpdatedAt:    balance.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	"github.com/joshuarp/withdraw-api/internal/shared/negcache"
+	"github.com/joshuarp/withdraw-api/internal/shared/uid"
+)
+
+const defaultWalletCurrency = "IDR"
+
+type WalletCreateRepository interface {
+	CreateWallet(ctx context.Context, walletID, userID, currency string) (domain.Wallet, error)
+}
+
+type WalletCreateService struct {
+	repository WalletCreateRepository
+	walletID   uid.UIDGenerator
+	notFound   negcache.Cache
+}
+
+// NewWalletCreateService builds the wallet create service. notFound is the balance
+// inquiry's wallet-not-found cache, cleared for the user once their wallet exists; nil
+// when that cache is disabled.
+func NewWalletCreateService(repository WalletCreateRepository, walletID uid.UIDGenerator, notFound negcache.Cache) *WalletCreateService {
+	return &WalletCreateService{repository: repository, walletID: walletID, notFound: notFound}
+}
+
+// CreateWallet opens a zero-balance wallet for userID. An empty currency falls back to
+// the schema default of IDR.
+func (s *WalletCreateService) CreateWallet(ctx context.Context, userID, currency string) (vo.WalletCreated, error) {
+	if strings.TrimSpace(userID) == "" {
+		return vo.WalletCreated{}, vo.ErrWalletNotFound
+	}
+
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = defaultWalletCurrency
+	}
+
+	walletID, err := s.walletID.Generate(ctx)
+	if err != nil {
+		return vo.WalletCreated{}, fmt.Errorf("service: failed to generate wallet id: %w", err)
+	}
+
+	wallet, err := s.repository.CreateWallet(ctx, walletID, userID, currency)
+	if err != nil {
+		return vo.WalletCreated{}, err
+	}
+
+	// A failed removal leaves inquiries reporting no wallet until the entry expires.
+	if s.notFound != nil {
+		_ = s.notFound.Remove(ctx, userID)
+	}
+
+	return vo.WalletCreated{
+		WalletID:     wallet.ID,
+		UserID:       wallet.UserID,
+		BalanceMinor: wallet.BalanceMinor,
+		Currency:     wallet.Currency,
+		CreatedAt:    wallet.CreatedAt,
+	}, nil
+}
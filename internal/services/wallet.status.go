@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/joshuarp/withdraw-api/internal/domain"
+	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+)
+
+type WalletStatusRepository interface {
+	SetWalletStatusByUserID(ctx context.Context, userID, status string) (domain.WalletStatus, error)
+}
+
+// WalletStatusService freezes and unfreezes wallets for fraud response. A frozen wallet
+// rejects withdrawals but can still be inquired and credited.
+type WalletStatusService struct {
+	repository WalletStatusRepository
+}
+
+func NewWalletStatusService(repository WalletStatusRepository) *WalletStatusService {
+	return &WalletStatusService{repository: repository}
+}
+
+// SetStatus expects vo.WalletStatusActive or vo.WalletStatusFrozen; the handler validates
+// the value before calling.
+func (s *WalletStatusService) SetStatus(ctx context.Context, userID, status string) (vo.WalletStatus, error) {
+	if strings.TrimSpace(userID) == "" {
+		return vo.WalletStatus{}, vo.ErrWalletNotFound
+	}
+
+	updated, err := s.repository.SetWalletStatusByUserID(ctx, userID, status)
+	if err != nil {
+		return vo.WalletStatus{}, err
+	}
+
+	return vo.WalletStatus{
+		UserID:    updated.UserID,
+		Status:    updated.Status,
+		UpdatedAt: updated.UpdatedAt,
+	}, nil
+}
@@ -2,25 +2,170 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/joshuarp/withdraw-api/internal/domain"
 	"github.com/joshuarp/withdraw-api/internal/domain/vo"
+	sharedaudit "github.com/joshuarp/withdraw-api/internal/shared/audit"
+	"github.com/joshuarp/withdraw-api/internal/shared/payout"
+	"github.com/joshuarp/withdraw-api/internal/shared/uid"
 )
 
 type BalanceWithdrawRepository interface {
-	WithdrawWalletBalanceByUserID(ctx context.Context, userID string, amountMinor int64, chainID string) (domain.WalletBalance, error)
+	GetWalletBalanceByUserID(ctx context.Context, userID string) (domain.WalletBalance, error)
+	WithdrawWalletBalanceByUserID(ctx context.Context, userID string, amountMinor int64, chainID, referenceID string, feeMinor int64, dailyLimit domain.DailyWithdrawLimit) (domain.WalletBalance, error)
+	ReverseWithdrawalByUserID(ctx context.Context, userID string, amountMinor int64, chainID, referenceID string, feeMinor int64) (domain.WalletBalance, error)
+	GetWithdrawalByReferenceID(ctx context.Context, userID, referenceID string) (domain.WithdrawalRecord, error)
+	CompleteWithdrawalByReferenceID(ctx context.Context, referenceID string) error
+	ListPendingWithdrawals(ctx context.Context, createdBefore time.Time, limit int) ([]domain.PendingWithdrawal, error)
+}
+
+// PayoutClient sends debited funds to the external payout provider and, for payouts
+// whose outcome was lost, asks it what became of them.
+type PayoutClient interface {
+	Payout(ctx context.Context, req payout.Request) (payout.Result, error)
+	PayoutStatus(ctx context.Context, referenceID string) (payout.Result, error)
+}
+
+// payoutGate is implemented by payout clients that can tell up front that a payout would
+// be refused, such as payout.CircuitBreaker while it is open.
+type payoutGate interface {
+	Allow() bool
+}
+
+type WithdrawAmountLimits struct {
+	MinAmountMinor  int64
+	MaxAmountMinor  int64
+	DailyLimitMinor int64
 }
 
 type InquiryWithdrawBalanceService struct {
-	repository BalanceWithdrawRepository
+	repository  BalanceWithdrawRepository
+	referenceID uid.UIDGenerator
+	blackouts   ChainBlackoutSchedule
+	limits      WithdrawAmountLimits
+	fees        FeeCalculator
+	auditor     sharedaudit.Auditor
+	payouts     PayoutClient
+	now         func() time.Time
+}
+
+// NewInquiryWithdrawBalanceService builds the withdraw service. A nil payouts client keeps
+// withdrawals ledger-only, without calling a payout provider. Withdrawal events are queued
+// in the outbox by the repository: withdrawal.debited with the debit, withdrawal.completed
+// only when CompleteWithdrawalByReferenceID records an accepted payout, and
+// withdrawal.reversed with the reversal.
+func NewInquiryWithdrawBalanceService(repository BalanceWithdrawRepository, referenceID uid.UIDGenerator, blackouts ChainBlackoutSchedule, limits WithdrawAmountLimits, fees FeeCalculator, auditor sharedaudit.Auditor, payouts PayoutClient) *InquiryWithdrawBalanceService {
+	return &InquiryWithdrawBalanceService{repository: repository, referenceID: referenceID, blackouts: blackouts, limits: limits, fees: fees, auditor: auditor, payouts: payouts, now: time.Now}
+}
+
+func (s *InquiryWithdrawBalanceService) WithdrawBalance(ctx context.Context, userID string, amountMinor int64, chainID, currency string) (vo.WalletWithdrawal, error) {
+	withdrawal, err := s.withdrawBalance(ctx, userID, amountMinor, chainID, currency)
+	s.recordAudit(ctx, userID, amountMinor, chainID, currency, withdrawal, err)
+	return withdrawal, err
+}
+
+// WalletCurrency returns the currency of the user's wallet.
+func (s *InquiryWithdrawBalanceService) WalletCurrency(ctx context.Context, userID string) (string, error) {
+	if strings.TrimSpace(userID) == "" {
+		return "", vo.ErrWalletNotFound
+	}
+
+	wallet, err := s.repository.GetWalletBalanceByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return wallet.Currency, nil
+}
+
+// GetWithdrawal returns the state of one of the user's withdrawals. A reversed withdrawal
+// (e.g. after a payout failure) is reported as failed, and one whose payout outcome is
+// still unknown as pending.
+func (s *InquiryWithdrawBalanceService) GetWithdrawal(ctx context.Context, userID, referenceID string) (vo.WithdrawalStatus, error) {
+	referenceID = strings.TrimSpace(referenceID)
+	if strings.TrimSpace(userID) == "" || referenceID == "" {
+		return vo.WithdrawalStatus{}, vo.ErrWithdrawalNotFound
+	}
+
+	record, err := s.repository.GetWithdrawalByReferenceID(ctx, userID, referenceID)
+	if err != nil {
+		return vo.WithdrawalStatus{}, err
+	}
+
+	status := vo.WithdrawalStatusCompleted
+	switch {
+	case record.Reversed:
+		status = vo.WithdrawalStatusFailed
+	case record.Pending:
+		status = vo.WithdrawalStatusPending
+	}
+
+	return vo.WithdrawalStatus{
+		ReferenceID: record.ReferenceID,
+		Status:      status,
+		AmountMinor: record.AmountMinor,
+		FeeMinor:    record.FeeMinor,
+		Currency:    record.Currency,
+		ChainID:     record.ChainID,
+		CreatedAt:   record.CreatedAt,
+		UpdatedAt:   record.UpdatedAt,
+	}, nil
+}
+
+func (s *InquiryWithdrawBalanceService) recordAudit(ctx context.Context, userID string, amountMinor int64, chainID, currency string, withdrawal vo.WalletWithdrawal, err error) {
+	if s.auditor == nil {
+		return
+	}
+
+	event := sharedaudit.Event{
+		Action:      "withdraw",
+		UserID:      userID,
+		AmountMinor: amountMinor,
+		Currency:    strings.TrimSpace(currency),
+		ChainID:     chainID,
+		Decision:    withdrawAuditDecision(err),
+		ReferenceID: withdrawal.ReferenceID,
+		RequestID:   sharedaudit.RequestIDFromContext(ctx),
+		OccurredAt:  s.now().UTC(),
+	}
+	if withdrawal.Currency != "" {
+		event.Currency = withdrawal.Currency
+	}
+	if err != nil {
+		event.Reason = err.Error()
+	}
+
+	s.auditor.Record(ctx, event)
 }
 
-func NewInquiryWithdrawBalanceService(repository BalanceWithdrawRepository) *InquiryWithdrawBalanceService {
-	return &InquiryWithdrawBalanceService{repository: repository}
+func withdrawAuditDecision(err error) sharedaudit.Decision {
+	switch {
+	case err == nil:
+		return sharedaudit.DecisionSuccess
+	case errors.Is(err, vo.ErrInsufficientBalance):
+		return sharedaudit.DecisionInsufficient
+	case errors.Is(err, vo.ErrInvalidAmount),
+		errors.Is(err, vo.ErrAmountBelowMinimum),
+		errors.Is(err, vo.ErrAmountAboveMaximum),
+		errors.Is(err, vo.ErrWalletNotFound),
+		errors.Is(err, vo.ErrCurrencyMismatch):
+		return sharedaudit.DecisionInvalid
+	case errors.Is(err, vo.ErrDailyLimitExceeded),
+		errors.Is(err, vo.ErrWalletFrozen),
+		errors.Is(err, vo.ErrChainUnavailable),
+		errors.Is(err, vo.ErrConcurrentModification),
+		errors.Is(err, vo.ErrPayoutRejected):
+		return sharedaudit.DecisionRejected
+	default:
+		return sharedaudit.DecisionError
+	}
 }
 
-func (s *InquiryWithdrawBalanceService) WithdrawBalance(ctx context.Context, userID string, amountMinor int64, chainID string) (vo.WalletWithdrawal, error) {
+func (s *InquiryWithdrawBalanceService) withdrawBalance(ctx context.Context, userID string, amountMinor int64, chainID, currency string) (vo.WalletWithdrawal, error) {
 	if strings.TrimSpace(userID) == "" {
 		return vo.WalletWithdrawal{}, vo.ErrWalletNotFound
 	}
@@ -29,17 +174,152 @@ func (s *InquiryWithdrawBalanceService) WithdrawBalance(ctx context.Context, use
 		return vo.WalletWithdrawal{}, vo.ErrInvalidAmount
 	}
 
-	balance, err := s.repository.WithdrawWalletBalanceByUserID(ctx, userID, amountMinor, chainID)
+	if s.limits.MinAmountMinor > 0 && amountMinor < s.limits.MinAmountMinor {
+		return vo.WalletWithdrawal{}, vo.ErrAmountBelowMinimum
+	}
+
+	if s.limits.MaxAmountMinor > 0 && amountMinor > s.limits.MaxAmountMinor {
+		return vo.WalletWithdrawal{}, vo.ErrAmountAboveMaximum
+	}
+
+	if until, blocked := s.blackouts.UnavailableUntil(chainID, s.now()); blocked {
+		return vo.WalletWithdrawal{}, &vo.ChainUnavailableError{ChainID: chainID, Until: until}
+	}
+
+	if currency = strings.TrimSpace(currency); currency != "" {
+		wallet, err := s.repository.GetWalletBalanceByUserID(ctx, userID)
+		if err != nil {
+			return vo.WalletWithdrawal{}, err
+		}
+
+		if !strings.EqualFold(wallet.Currency, currency) {
+			return vo.WalletWithdrawal{}, vo.ErrCurrencyMismatch
+		}
+	}
+
+	// Fail before the debit rather than debit and immediately reverse.
+	if gate, ok := s.payouts.(payoutGate); ok && !gate.Allow() {
+		return vo.WalletWithdrawal{}, fmt.Errorf("%w: %w", vo.ErrPayoutUnavailable, payout.ErrCircuitOpen)
+	}
+
+	referenceID, err := s.referenceID.Generate(ctx)
+	if err != nil {
+		return vo.WalletWithdrawal{}, fmt.Errorf("service: failed to generate reference id: %w", err)
+	}
+
+	var feeMinor int64
+	if s.fees != nil {
+		feeMinor = s.fees.Calculate(amountMinor)
+	}
+
+	now := s.now().UTC()
+	dailyLimit := domain.DailyWithdrawLimit{
+		LimitMinor: s.limits.DailyLimitMinor,
+		Since:      time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	balance, err := s.repository.WithdrawWalletBalanceByUserID(ctx, userID, amountMinor, chainID, referenceID, feeMinor, dailyLimit)
 	if err != nil {
 		return vo.WalletWithdrawal{}, err
 	}
 
-	return vo.WalletWithdrawal{
+	withdrawal := vo.WalletWithdrawal{
+		ReferenceID:  referenceID,
 		UserID:       balance.UserID,
 		AmountMinor:  amountMinor,
+		FeeMinor:     feeMinor,
 		BalanceMinor: balance.BalanceMinor,
 		Currency:     balance.Currency,
 		ChainID:      chainID,
+		Status:       vo.WithdrawalStatusPending,
 		UpdatedAt:    balance.UpdatedAt,
-	}, nil
+	}
+
+	if s.payouts != nil {
+		result, err := s.payouts.Payout(ctx, payout.Request{
+			ReferenceID: referenceID,
+			UserID:      balance.UserID,
+			AmountMinor: amountMinor,
+			Currency:    balance.Currency,
+			ChainID:     chainID,
+		})
+		if err != nil {
+			if !payout.NotAccepted(err) {
+				// The provider may have taken the payout. The debit stays pending until
+				// ReconcilePendingWithdrawals learns the outcome from the provider.
+				return withdrawal, nil
+			}
+			return vo.WalletWithdrawal{}, s.reverseFailedPayout(ctx, userID, amountMinor, chainID, referenceID, feeMinor, err)
+		}
+		withdrawal.PayoutReference = result.ProviderReference
+	}
+
+	// The payout went through; if recording that fails the withdrawal stays pending and
+	// the reconciler completes it from the provider's answer.
+	if err := s.repository.CompleteWithdrawalByReferenceID(context.WithoutCancel(ctx), referenceID); err == nil || errors.Is(err, vo.ErrWithdrawalSettled) {
+		withdrawal.Status = vo.WithdrawalStatusCompleted
+	}
+	return withdrawal, nil
+}
+
+// reverseFailedPayout credits the debit back after the provider rejected the payout, or
+// after a failure that proves the request never reached it (rate limited, circuit open).
+func (s *InquiryWithdrawBalanceService) reverseFailedPayout(ctx context.Context, userID string, amountMinor int64, chainID, referenceID string, feeMinor int64, payoutErr error) error {
+	cause := vo.ErrPayoutRejected
+	if payout.IsRetryable(payoutErr) {
+		cause = vo.ErrPayoutUnavailable
+	}
+
+	// The request context may already be cancelled by a timeout; the reversal must still run.
+	if _, err := s.repository.ReverseWithdrawalByUserID(context.WithoutCancel(ctx), userID, amountMinor, chainID, referenceID, feeMinor); err != nil {
+		return fmt.Errorf("service: failed to reverse withdrawal %s after payout failure (%v): %w", referenceID, payoutErr, err)
+	}
+
+	return fmt.Errorf("%w: %w", cause, payoutErr)
+}
+
+// ReconcilePendingWithdrawals settles up to limit withdrawals left pending for at least
+// settleAfter by asking the provider about each under its idempotency key: an accepted
+// payout is completed, and one the provider rejected or never received is reversed.
+// settleAfter must outlast a whole payout call, retries included, or a payout still in
+// flight could be reported as unknown and reversed. Withdrawals whose lookup fails stay
+// pending for the next pass. It returns how many were settled.
+func (s *InquiryWithdrawBalanceService) ReconcilePendingWithdrawals(ctx context.Context, settleAfter time.Duration, limit int) (int, error) {
+	pending, err := s.repository.ListPendingWithdrawals(ctx, s.now().Add(-settleAfter), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	var errs []error
+	for _, withdrawal := range pending {
+		err := s.settlePendingWithdrawal(ctx, withdrawal)
+		switch {
+		case err == nil:
+			settled++
+		case errors.Is(err, vo.ErrWithdrawalSettled):
+			// Settled concurrently, e.g. by another replica.
+		default:
+			errs = append(errs, fmt.Errorf("service: failed to settle withdrawal %s: %w", withdrawal.ReferenceID, err))
+		}
+	}
+
+	return settled, errors.Join(errs...)
+}
+
+func (s *InquiryWithdrawBalanceService) settlePendingWithdrawal(ctx context.Context, withdrawal domain.PendingWithdrawal) error {
+	if s.payouts == nil {
+		return s.repository.CompleteWithdrawalByReferenceID(ctx, withdrawal.ReferenceID)
+	}
+
+	result, err := s.payouts.PayoutStatus(ctx, withdrawal.ReferenceID)
+	switch {
+	case errors.Is(err, payout.ErrPayoutNotFound), err == nil && result.Rejected():
+		_, err = s.repository.ReverseWithdrawalByUserID(ctx, withdrawal.UserID, withdrawal.AmountMinor, withdrawal.ChainID, withdrawal.ReferenceID, withdrawal.FeeMinor)
+		return err
+	case err != nil:
+		return err
+	default:
+		return s.repository.CompleteWithdrawalByReferenceID(ctx, withdrawal.ReferenceID)
+	}
 }
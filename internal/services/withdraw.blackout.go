@@ -0,0 +1,43 @@
+package services
+
+import (
+	"strings"
+	"time"
+)
+
+type ChainBlackout struct {
+	ChainID string
+	Start   time.Time
+	End     time.Time
+}
+
+type ChainBlackoutSchedule []ChainBlackout
+
+func (s ChainBlackoutSchedule) UnavailableUntil(chainID string, now time.Time) (time.Time, bool) {
+	chainID = strings.TrimSpace(chainID)
+	if chainID == "" {
+		return time.Time{}, false
+	}
+
+	var until time.Time
+	for extended := true; extended; {
+		extended = false
+		at := now
+		if !until.IsZero() {
+			at = until
+		}
+
+		for _, window := range s {
+			if !strings.EqualFold(window.ChainID, chainID) {
+				continue
+			}
+
+			if !at.Before(window.Start) && at.Before(window.End) && window.End.After(until) {
+				until = window.End
+				extended = true
+			}
+		}
+	}
+
+	return until, !until.IsZero()
+}
@@ -0,0 +1,35 @@
+package services
+
+type FeeCalculator interface {
+	Calculate(amountMinor int64) int64
+}
+
+type FlatFeeCalculator struct {
+	FeeMinor int64
+}
+
+func (c FlatFeeCalculator) Calculate(_ int64) int64 {
+	return c.FeeMinor
+}
+
+type PercentageFeeCalculator struct {
+	BasisPoints int64
+}
+
+func (c PercentageFeeCalculator) Calculate(amountMinor int64) int64 {
+	if c.BasisPoints <= 0 || amountMinor <= 0 {
+		return 0
+	}
+
+	return (amountMinor*c.BasisPoints + 9_999) / 10_000
+}
+
+type CombinedFeeCalculator []FeeCalculator
+
+func (c CombinedFeeCalculator) Calculate(amountMinor int64) int64 {
+	var total int64
+	for _, calculator := range c {
+		total += calculator.Calculate(amountMinor)
+	}
+	return total
+}
@@ -0,0 +1,67 @@
+// Package audit records an immutable trail of sensitive operations such as
+// withdrawal attempts, including the ones that were rejected.
+package audit
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Decision is the outcome of an audited operation.
+type Decision string
+
+const (
+	DecisionSuccess      Decision = "success"
+	DecisionInsufficient Decision = "insufficient"
+	DecisionInvalid      Decision = "invalid"
+	DecisionRejected     Decision = "rejected"
+	DecisionError        Decision = "error"
+)
+
+// Event is a single audited attempt.
+type Event struct {
+	// Action names the audited operation, e.g. "withdraw".
+	Action string
+
+	UserID      string
+	AmountMinor int64
+	Currency    string
+	ChainID     string
+	Decision    Decision
+
+	// Reason carries the rejection cause; it is empty on success.
+	Reason string
+
+	// ReferenceID is set once the operation was assigned one.
+	ReferenceID string
+
+	// RequestID correlates the event with the HTTP request that triggered it.
+	RequestID string
+
+	OccurredAt time.Time
+}
+
+// Auditor is the interface consumers depend on for recording audit events.
+// Record must not block the caller on failure; implementations report their
+// own write errors. Implementations must be safe for concurrent use.
+type Auditor interface {
+	Record(ctx context.Context, event Event)
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID for audit events.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	requestID = strings.TrimSpace(requestID)
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
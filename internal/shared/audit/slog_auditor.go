@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogAuditor writes audit events as structured log records.
+type SlogAuditor struct {
+	logger *slog.Logger
+}
+
+func NewSlogAuditor(logger *slog.Logger) *SlogAuditor {
+	return &SlogAuditor{logger: logger}
+}
+
+func (a *SlogAuditor) Record(ctx context.Context, event Event) {
+	if a == nil || a.logger == nil {
+		return
+	}
+
+	a.logger.InfoContext(ctx, "audit event",
+		"action", event.Action,
+		"user_id", event.UserID,
+		"amount_minor", event.AmountMinor,
+		"currency", event.Currency,
+		"chain_id", event.ChainID,
+		"decision", string(event.Decision),
+		"reason", event.Reason,
+		"reference_id", event.ReferenceID,
+		"request_id", event.RequestID,
+		"occurred_at", event.OccurredAt,
+	)
+}
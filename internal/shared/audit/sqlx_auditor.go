@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const insertAuditLogQuery = `
+INSERT INTO audit_log (action, user_id, amount_minor, currency, chain_id, decision, reason, reference_id, request_id, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+// SQLXAuditor appends audit events to the audit_log table.
+// Write failures are logged rather than returned so auditing never changes
+// the outcome of the audited operation.
+type SQLXAuditor struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewSQLXAuditor(db *sqlx.DB, logger *slog.Logger) *SQLXAuditor {
+	return &SQLXAuditor{db: db, logger: logger}
+}
+
+func (a *SQLXAuditor) Record(ctx context.Context, event Event) {
+	if a == nil || a.db == nil {
+		return
+	}
+
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+
+	// The audit row must be written even when the request context was canceled
+	// after the operation completed.
+	_, err := a.db.ExecContext(context.WithoutCancel(ctx), insertAuditLogQuery,
+		event.Action,
+		event.UserID,
+		event.AmountMinor,
+		nullString(event.Currency),
+		nullString(event.ChainID),
+		string(event.Decision),
+		nullString(event.Reason),
+		nullString(event.ReferenceID),
+		nullString(event.RequestID),
+		occurredAt,
+	)
+	if err != nil && a.logger != nil {
+		a.logger.ErrorContext(ctx, "audit: failed to write audit log",
+			"action", event.Action,
+			"user_id", event.UserID,
+			"decision", string(event.Decision),
+			"request_id", event.RequestID,
+			"error", err,
+		)
+	}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
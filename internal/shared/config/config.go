@@ -9,6 +9,10 @@ type Options struct {
 
 	// EnvPath is the path to the fallback .env file, used only when YAML is absent.
 	EnvPath string
+
+	// KeepUnresolvedEnv keeps ${VAR} and $VAR references whose environment
+	// variable is unset instead of expanding them to an empty string.
+	KeepUnresolvedEnv bool
 }
 
 // ConfigProvider is the interface consumers depend on for reading configuration.
@@ -32,9 +36,21 @@ type ConfigProvider interface {
 	// GetStringSlice returns the value associated with the key as a slice of strings.
 	GetStringSlice(key string) []string
 
+	// GetIntSlice returns the value associated with the key as a slice of ints.
+	// It returns nil when any element is not an integer.
+	GetIntSlice(key string) []int
+
+	// GetTime returns the value associated with the key parsed as an RFC3339 timestamp.
+	// It returns the zero time when the value is missing or malformed.
+	GetTime(key string) time.Time
+
 	// GetStringMap returns the value associated with the key as a map of interfaces.
 	GetStringMap(key string) map[string]interface{}
 
+	// GetStringMapString returns the value associated with the key as a map of strings.
+	// Non-string values are converted to their string form.
+	GetStringMapString(key string) map[string]string
+
 	// IsSet checks whether the key is set in the config.
 	IsSet(key string) bool
 
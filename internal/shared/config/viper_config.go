@@ -1,10 +1,18 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
@@ -14,7 +22,9 @@ var _ ConfigProvider = (*viperConfig)(nil)
 
 type viperConfig struct {
 	v         *viper.Viper
+	path      string
 	source    string
+	keepEnv   bool
 	callbacks []func()
 	mu        sync.RWMutex
 	done      chan struct{}
@@ -23,10 +33,9 @@ type viperConfig struct {
 // Init loads configuration from a YAML file (primary) or .env file (exclusive fallback).
 // Returns a ConfigProvider interface. Returns error if neither file exists or parsing fails.
 func Init(opts Options) (ConfigProvider, error) {
-	v := viper.New()
 	cfg := &viperConfig{
-		v:    v,
-		done: make(chan struct{}),
+		keepEnv: opts.KeepUnresolvedEnv,
+		done:    make(chan struct{}),
 	}
 
 	yamlExists := fileExists(opts.YAMLPath)
@@ -34,24 +43,79 @@ func Init(opts Options) (ConfigProvider, error) {
 
 	switch {
 	case yamlExists:
-		v.SetConfigFile(opts.YAMLPath)
-		v.SetConfigType("yaml")
+		cfg.path = opts.YAMLPath
 		cfg.source = "yaml"
 	case envExists:
-		v.SetConfigFile(opts.EnvPath)
-		v.SetConfigType("env")
+		cfg.path = opts.EnvPath
 		cfg.source = "env"
 	default:
 		return nil, fmt.Errorf("config: no config file found (tried %q and %q)", opts.YAMLPath, opts.EnvPath)
 	}
 
-	if err := v.ReadInConfig(); err != nil {
+	v, err := cfg.load()
+	if err != nil {
 		return nil, fmt.Errorf("config: failed to read %s file: %w", cfg.source, err)
 	}
+	cfg.v = v
 
 	return cfg, nil
 }
 
+// load reads the active config file into a new viper instance with environment
+// references expanded, so every getter (and nested maps) sees the resolved values.
+func (c *viperConfig) load() (*viper.Viper, error) {
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetConfigType(c.source)
+	if err := v.ReadConfig(bytes.NewReader([]byte(c.expandEnv(string(raw))))); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// reload swaps in a freshly loaded config and runs the OnChange callbacks. On failure the
+// previous config stays active. An empty file is treated as a failure: editors and
+// deploy tools often truncate the file before writing the new content.
+func (c *viperConfig) reload() error {
+	v, err := c.load()
+	if err != nil {
+		return err
+	}
+	if len(v.AllKeys()) == 0 {
+		return errors.New("config file is empty")
+	}
+
+	c.mu.Lock()
+	c.v = v
+	cbs := make([]func(), len(c.callbacks))
+	copy(cbs, c.callbacks)
+	c.mu.Unlock()
+
+	for _, fn := range cbs {
+		fn()
+	}
+	return nil
+}
+
+// expandEnv replaces ${VAR} and $VAR with environment values.
+// "$$" is an escape for a literal "$".
+func (c *viperConfig) expandEnv(raw string) string {
+	return os.Expand(raw, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok && c.keepEnv {
+			return "${" + name + "}"
+		}
+		return value
+	})
+}
+
 func fileExists(path string) bool {
 	if path == "" {
 		return false
@@ -96,12 +160,91 @@ func (c *viperConfig) GetStringSlice(key string) []string {
 	return c.v.GetStringSlice(key)
 }
 
+func (c *viperConfig) GetIntSlice(key string) []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var items []interface{}
+	switch raw := c.v.Get(key).(type) {
+	case nil:
+		return nil
+	case string:
+		for _, field := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+			items = append(items, field)
+		}
+	case []interface{}:
+		items = raw
+	case []int:
+		return append([]int(nil), raw...)
+	default:
+		items = []interface{}{raw}
+	}
+
+	values := make([]int, 0, len(items))
+	for _, item := range items {
+		value, ok := toInt(item)
+		if !ok {
+			return nil
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func (c *viperConfig) GetTime(key string) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	switch raw := c.v.Get(key).(type) {
+	case time.Time:
+		return raw
+	case string:
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+		if err != nil {
+			return time.Time{}
+		}
+		return parsed
+	default:
+		return time.Time{}
+	}
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, false
+		}
+		return int(v), true
+	case string:
+		parsed, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, false
+		}
+		return parsed, true
+	default:
+		return 0, false
+	}
+}
+
 func (c *viperConfig) GetStringMap(key string) map[string]interface{} {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	return c.v.GetStringMap(key)
 }
 
+func (c *viperConfig) GetStringMapString(key string) map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.v.GetStringMapString(key)
+}
+
 func (c *viperConfig) IsSet(key string) bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -122,23 +265,62 @@ func (c *viperConfig) OnChange(fn func()) {
 	c.callbacks = append(c.callbacks, fn)
 }
 
+// WatchChanges reloads the config whenever the file is written or replaced. The directory
+// is watched rather than the file, so atomic renames and Kubernetes ConfigMap symlink
+// swaps are picked up too. Failed reloads are logged and keep the previous config.
 func (c *viperConfig) WatchChanges() {
 	if c.source != "yaml" {
 		return
 	}
 
-	c.v.OnConfigChange(func(e fsnotify.Event) {
-		c.mu.Lock()
-		c.v.ReadInConfig()
-		cbs := make([]func(), len(c.callbacks))
-		copy(cbs, c.callbacks)
-		c.mu.Unlock()
+	logger := slog.Default()
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Error("config watch failed to start", "path", c.path, "error", err)
+		return
+	}
+
+	file := filepath.Clean(c.path)
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		logger.Error("config watch failed to start", "path", c.path, "error", err)
+		return
+	}
+
+	go func() {
+		defer watcher.Close()
 
-		for _, fn := range cbs {
-			fn()
+		realFile, _ := filepath.EvalSymlinks(file)
+		for {
+			select {
+			case <-c.done:
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				currentFile, _ := filepath.EvalSymlinks(file)
+				written := filepath.Clean(event.Name) == file && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create))
+				swapped := currentFile != "" && currentFile != realFile
+				if !written && !swapped {
+					continue
+				}
+				realFile = currentFile
+
+				if err := c.reload(); err != nil {
+					logger.Error("config reload failed, keeping previous config", "path", c.path, "error", err)
+					continue
+				}
+				logger.Info("config reloaded", "path", c.path)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Warn("config watch error", "path", c.path, "error", err)
+			}
 		}
-	})
-	c.v.WatchConfig()
+	}()
 }
 
 func (c *viperConfig) StopWatching() {
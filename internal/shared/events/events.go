@@ -0,0 +1,47 @@
+// Package events publishes money-movement events (debited, completed and reversed
+// withdrawals, completed deposits) to downstream consumers such as reconciliation and
+// notifications.
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// A withdrawal emits withdrawal.debited when its wallet is debited, then exactly one of
+// withdrawal.completed (the payout provider accepted it) or withdrawal.reversed.
+const (
+	TypeWithdrawalDebited   = "withdrawal.debited"
+	TypeWithdrawalCompleted = "withdrawal.completed"
+	TypeWithdrawalReversed  = "withdrawal.reversed"
+	TypeDepositCompleted    = "deposit.completed"
+)
+
+// Event is a committed balance change. TransactionID is the reference_id of the
+// ledger entries; events of one withdrawal share it, so consumers deduplicate
+// redeliveries on Type and TransactionID together.
+type Event struct {
+	Type          string    `json:"type"`
+	TransactionID string    `json:"transaction_id"`
+	UserID        string    `json:"user_id"`
+	AmountMinor   int64     `json:"amount_minor"`
+	FeeMinor      int64     `json:"fee_minor"`
+	Currency      string    `json:"currency"`
+	ChainID       string    `json:"chain_id,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// Publisher is the interface consumers depend on for emitting events. Delivery is
+// best-effort: callers publish after their transaction commits and must not undo it
+// when Publish fails. Implementations log their own failures and must be safe for
+// concurrent use.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// NopPublisher drops every event. It is the default when no broker is configured.
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(context.Context, Event) error {
+	return nil
+}
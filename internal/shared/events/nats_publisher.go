@@ -0,0 +1,105 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const defaultNATSConnectTimeout = 5 * time.Second
+
+// NATSConfig configures NATSPublisher. Events go to "<SubjectPrefix>.<event type>",
+// e.g. "wallet.withdrawal.completed".
+type NATSConfig struct {
+	URL            string
+	SubjectPrefix  string
+	ConnectTimeout time.Duration
+}
+
+// natsConn is the subset of *nats.Conn used by NATSPublisher.
+type natsConn interface {
+	PublishMsg(msg *nats.Msg) error
+	Drain() error
+}
+
+// NATSPublisher publishes events as JSON to NATS core subjects. The event type and
+// transaction ID are set as the Nats-Msg-Id header so JetStream streams on those subjects
+// deduplicate redeliveries without dropping the other events of the same withdrawal.
+type NATSPublisher struct {
+	conn          natsConn
+	subjectPrefix string
+	logger        *slog.Logger
+}
+
+func NewNATSPublisher(cfg NATSConfig, logger *slog.Logger) (*NATSPublisher, error) {
+	url := strings.TrimSpace(cfg.URL)
+	if url == "" {
+		return nil, errors.New("events: nats url is required")
+	}
+
+	timeout := cfg.ConnectTimeout
+	if timeout <= 0 {
+		timeout = defaultNATSConnectTimeout
+	}
+
+	conn, err := nats.Connect(url, nats.Name("withdraw-api"), nats.Timeout(timeout), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("events: failed to connect to nats: %w", err)
+	}
+
+	return newNATSPublisher(conn, cfg.SubjectPrefix, logger), nil
+}
+
+func newNATSPublisher(conn natsConn, subjectPrefix string, logger *slog.Logger) *NATSPublisher {
+	return &NATSPublisher{conn: conn, subjectPrefix: strings.Trim(strings.TrimSpace(subjectPrefix), "."), logger: logger}
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event Event) error {
+	err := p.publish(event)
+	if err != nil && p.logger != nil {
+		p.logger.ErrorContext(ctx, "failed to publish event",
+			"type", event.Type,
+			"transaction_id", event.TransactionID,
+			"user_id", event.UserID,
+			"error", err,
+		)
+	}
+
+	return err
+}
+
+func (p *NATSPublisher) publish(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("events: failed to encode %s event: %w", event.Type, err)
+	}
+
+	msg := nats.NewMsg(p.subject(event.Type))
+	msg.Data = payload
+	msg.Header.Set(nats.MsgIdHdr, event.Type+":"+event.TransactionID)
+
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("events: failed to publish %s event: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+func (p *NATSPublisher) subject(eventType string) string {
+	if p.subjectPrefix == "" {
+		return eventType
+	}
+
+	return p.subjectPrefix + "." + eventType
+}
+
+// Close flushes buffered events and closes the connection.
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
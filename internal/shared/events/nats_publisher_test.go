@@ -0,0 +1,119 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeNATSConn struct {
+	published  []*nats.Msg
+	publishErr error
+	drained    bool
+}
+
+func (c *fakeNATSConn) PublishMsg(msg *nats.Msg) error {
+	if c.publishErr != nil {
+		return c.publishErr
+	}
+	c.published = append(c.published, msg)
+	return nil
+}
+
+func (c *fakeNATSConn) Drain() error {
+	c.drained = true
+	return nil
+}
+
+type NATSPublisherSuite struct{ suite.Suite }
+
+func (s *NATSPublisherSuite) TestPublish_TableDriven() {
+	occurredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := Event{
+		Type:          TypeWithdrawalCompleted,
+		TransactionID: "ref-1",
+		UserID:        "user-1",
+		AmountMinor:   1000,
+		FeeMinor:      25,
+		Currency:      "IDR",
+		ChainID:       "polygon",
+		OccurredAt:    occurredAt,
+	}
+
+	tests := []struct {
+		name          string
+		subjectPrefix string
+		publishErr    error
+		expectSubject string
+		expectErr     bool
+	}{
+		{name: "publishes json under prefixed subject", subjectPrefix: "wallet", expectSubject: "wallet.withdrawal.completed"},
+		{name: "trims dotted prefix", subjectPrefix: " wallet. ", expectSubject: "wallet.withdrawal.completed"},
+		{name: "empty prefix uses event type", expectSubject: "withdrawal.completed"},
+		{name: "publish failure is logged and returned", subjectPrefix: "wallet", publishErr: nats.ErrConnectionClosed, expectErr: true},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			var logs bytes.Buffer
+			conn := &fakeNATSConn{publishErr: tc.publishErr}
+			publisher := newNATSPublisher(conn, tc.subjectPrefix, slog.New(slog.NewJSONHandler(&logs, nil)))
+
+			err := publisher.Publish(context.Background(), event)
+			if tc.expectErr {
+				require.ErrorIs(s.T(), err, tc.publishErr)
+				assert.Contains(s.T(), logs.String(), "failed to publish event")
+				assert.Contains(s.T(), logs.String(), "ref-1")
+				assert.Empty(s.T(), conn.published)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			require.Len(s.T(), conn.published, 1)
+			msg := conn.published[0]
+			assert.Equal(s.T(), tc.expectSubject, msg.Subject)
+			assert.Equal(s.T(), "withdrawal.completed:ref-1", msg.Header.Get(nats.MsgIdHdr))
+
+			var decoded map[string]any
+			require.NoError(s.T(), json.Unmarshal(msg.Data, &decoded))
+			assert.Equal(s.T(), map[string]any{
+				"type":           "withdrawal.completed",
+				"transaction_id": "ref-1",
+				"user_id":        "user-1",
+				"amount_minor":   float64(1000),
+				"fee_minor":      float64(25),
+				"currency":       "IDR",
+				"chain_id":       "polygon",
+				"occurred_at":    "2026-01-02T03:04:05Z",
+			}, decoded)
+			assert.Empty(s.T(), logs.String())
+		})
+	}
+}
+
+func (s *NATSPublisherSuite) TestClose_DrainsConnection() {
+	conn := &fakeNATSConn{}
+	require.NoError(s.T(), newNATSPublisher(conn, "wallet", nil).Close())
+	assert.True(s.T(), conn.drained)
+}
+
+func (s *NATSPublisherSuite) TestNewNATSPublisher_RequiresURL() {
+	_, err := NewNATSPublisher(NATSConfig{URL: " "}, nil)
+	require.Error(s.T(), err)
+}
+
+func (s *NATSPublisherSuite) TestNopPublisher_DropsEvents() {
+	assert.NoError(s.T(), NopPublisher{}.Publish(context.Background(), Event{Type: TypeDepositCompleted}))
+}
+
+func TestNATSPublisherSuite(t *testing.T) {
+	suite.Run(t, new(NATSPublisherSuite))
+}
@@ -0,0 +1,130 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultOutboxPollInterval = time.Second
+	defaultOutboxBatchSize    = 100
+	defaultOutboxClaimLease   = 30 * time.Second
+)
+
+// OutboxMessage is an unsent outbox row. Payload is the JSON-encoded Event.
+type OutboxMessage struct {
+	ID      int64
+	Payload []byte
+}
+
+// OutboxStore is the persistence used by OutboxRelay. Claimed rows stay invisible to
+// other relays for the lease, so a relay that crashes mid-batch only delays them.
+// MarkFailed leaves a row to be retried after its lease; MarkDead takes it out of
+// ClaimUnsent for good, for rows that can never be published.
+type OutboxStore interface {
+	ClaimUnsent(ctx context.Context, limit int, lease time.Duration) ([]OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string) error
+	MarkDead(ctx context.Context, id int64, reason string) error
+}
+
+type OutboxRelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	ClaimLease   time.Duration
+}
+
+// OutboxRelay publishes events written to the outbox inside balance-changing
+// transactions. A row is marked sent only after Publish succeeds, so delivery is
+// at-least-once: consumers deduplicate on Event.Type and Event.TransactionID.
+type OutboxRelay struct {
+	store     OutboxStore
+	publisher Publisher
+	cfg       OutboxRelayConfig
+	logger    *slog.Logger
+}
+
+func NewOutboxRelay(store OutboxStore, publisher Publisher, cfg OutboxRelayConfig, logger *slog.Logger) *OutboxRelay {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultOutboxPollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultOutboxBatchSize
+	}
+	if cfg.ClaimLease <= 0 {
+		cfg.ClaimLease = defaultOutboxClaimLease
+	}
+
+	return &OutboxRelay{store: store, publisher: publisher, cfg: cfg, logger: logger}
+}
+
+// Run relays batches until ctx is cancelled. A full batch is followed immediately by the
+// next one; otherwise the relay waits PollInterval.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	for {
+		sent, err := r.RelayOnce(ctx)
+		if err != nil && ctx.Err() == nil && r.logger != nil {
+			r.logger.WarnContext(ctx, "outbox relay failed", "sent", sent, "error", err)
+		}
+
+		if err == nil && sent == r.cfg.BatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
+
+// RelayOnce publishes one batch in outbox order and returns how many rows were marked
+// sent. It stops at the first publish failure, leaving that row and the rest of the batch
+// to be retried after the claim lease.
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	messages, err := r.store.ClaimUnsent(ctx, r.cfg.BatchSize, r.cfg.ClaimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, message := range messages {
+		var event Event
+		if err := json.Unmarshal(message.Payload, &event); err != nil {
+			// A row that cannot be decoded would fail forever; park it and move on.
+			r.markDead(ctx, message.ID, fmt.Errorf("events: failed to decode outbox row %d: %w", message.ID, err))
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			r.markFailed(ctx, message.ID, err)
+			return sent, fmt.Errorf("events: failed to relay outbox row %d: %w", message.ID, err)
+		}
+
+		if err := r.store.MarkSent(ctx, message.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (r *OutboxRelay) markDead(ctx context.Context, id int64, cause error) {
+	if r.logger != nil {
+		r.logger.ErrorContext(ctx, "outbox row dead-lettered", "id", id, "error", cause)
+	}
+	if err := r.store.MarkDead(ctx, id, cause.Error()); err != nil && r.logger != nil {
+		r.logger.WarnContext(ctx, "outbox relay failed to record error", "id", id, "error", err)
+	}
+}
+
+func (r *OutboxRelay) markFailed(ctx context.Context, id int64, cause error) {
+	if err := r.store.MarkFailed(ctx, id, cause.Error()); err != nil && r.logger != nil {
+		r.logger.WarnContext(ctx, "outbox relay failed to record error", "id", id, "error", err)
+	}
+}
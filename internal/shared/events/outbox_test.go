@@ -0,0 +1,287 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeOutboxRow struct {
+	message   OutboxMessage
+	sent      bool
+	dead      bool
+	lastError string
+}
+
+type fakeOutboxStore struct {
+	mu       sync.Mutex
+	rows     []*fakeOutboxRow
+	claimErr error
+	markErr  error
+	leases   []time.Duration
+}
+
+func (s *fakeOutboxStore) add(t *testing.T, id int64, event Event) {
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+	s.rows = append(s.rows, &fakeOutboxRow{message: OutboxMessage{ID: id, Payload: payload}})
+}
+
+func (s *fakeOutboxStore) ClaimUnsent(_ context.Context, limit int, lease time.Duration) ([]OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.leases = append(s.leases, lease)
+	if s.claimErr != nil {
+		return nil, s.claimErr
+	}
+
+	messages := make([]OutboxMessage, 0, limit)
+	for _, row := range s.rows {
+		if !row.sent && !row.dead && len(messages) < limit {
+			messages = append(messages, row.message)
+		}
+	}
+	return messages, nil
+}
+
+func (s *fakeOutboxStore) MarkSent(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.markErr != nil {
+		return s.markErr
+	}
+	s.row(id).sent = true
+	return nil
+}
+
+func (s *fakeOutboxStore) MarkFailed(_ context.Context, id int64, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.row(id).lastError = reason
+	return nil
+}
+
+func (s *fakeOutboxStore) MarkDead(_ context.Context, id int64, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.row(id).dead = true
+	s.row(id).lastError = reason
+	return nil
+}
+
+func (s *fakeOutboxStore) row(id int64) *fakeOutboxRow {
+	for _, row := range s.rows {
+		if row.message.ID == id {
+			return row
+		}
+	}
+	return nil
+}
+
+func (s *fakeOutboxStore) unsent() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, row := range s.rows {
+		if !row.sent && !row.dead {
+			count++
+		}
+	}
+	return count
+}
+
+// flakyPublisher fails the first failures calls, then records every published event.
+type flakyPublisher struct {
+	mu        sync.Mutex
+	failures  int
+	calls     int
+	published []Event
+}
+
+func (p *flakyPublisher) Publish(_ context.Context, event Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if p.calls <= p.failures {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+func (p *flakyPublisher) transactionIDs() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]string, 0, len(p.published))
+	for _, event := range p.published {
+		ids = append(ids, event.TransactionID)
+	}
+	return ids
+}
+
+type OutboxRelaySuite struct{ suite.Suite }
+
+func (s *OutboxRelaySuite) TestRelayOnce_TableDriven() {
+	claimErr := errors.New("claim failed")
+	markErr := errors.New("mark failed")
+
+	tests := []struct {
+		name          string
+		failures      int
+		claimErr      error
+		markErr       error
+		corruptRow    bool
+		expectErr     error
+		expectSent    int
+		expectIDs     []string
+		expectUnsent  int
+		expectRow1Err string
+		expectDead    bool
+	}{
+		{
+			name:       "publishes in order and marks sent",
+			expectSent: 2,
+			expectIDs:  []string{"ref-1", "ref-2"},
+		},
+		{
+			name:          "publish failure records error and stops the batch",
+			failures:      1,
+			expectSent:    0,
+			expectIDs:     []string{},
+			expectUnsent:  2,
+			expectRow1Err: "broker unavailable",
+		},
+		{
+			name:         "claim failure publishes nothing",
+			claimErr:     claimErr,
+			expectErr:    claimErr,
+			expectIDs:    []string{},
+			expectUnsent: 2,
+		},
+		{
+			name:         "mark failure leaves row for redelivery",
+			markErr:      markErr,
+			expectErr:    markErr,
+			expectIDs:    []string{"ref-1"},
+			expectUnsent: 2,
+		},
+		{
+			name:          "undecodable row is dead-lettered and skipped",
+			corruptRow:    true,
+			expectSent:    1,
+			expectIDs:     []string{"ref-2"},
+			expectUnsent:  0,
+			expectRow1Err: "failed to decode outbox row 1",
+			expectDead:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			store := &fakeOutboxStore{claimErr: tc.claimErr, markErr: tc.markErr}
+			store.add(s.T(), 1, Event{Type: TypeWithdrawalCompleted, TransactionID: "ref-1"})
+			store.add(s.T(), 2, Event{Type: TypeWithdrawalReversed, TransactionID: "ref-2"})
+			if tc.corruptRow {
+				store.rows[0].message.Payload = []byte("{")
+			}
+			publisher := &flakyPublisher{failures: tc.failures}
+			relay := NewOutboxRelay(store, publisher, OutboxRelayConfig{ClaimLease: time.Minute}, nil)
+
+			sent, err := relay.RelayOnce(context.Background())
+			if tc.expectErr != nil {
+				require.ErrorIs(s.T(), err, tc.expectErr)
+			} else if tc.failures > 0 {
+				require.Error(s.T(), err)
+			} else {
+				require.NoError(s.T(), err)
+			}
+
+			assert.Equal(s.T(), tc.expectSent, sent)
+			assert.Equal(s.T(), tc.expectIDs, publisher.transactionIDs())
+			assert.Equal(s.T(), tc.expectUnsent, store.unsent())
+			assert.Contains(s.T(), store.rows[0].lastError, tc.expectRow1Err)
+			assert.Equal(s.T(), tc.expectDead, store.rows[0].dead)
+			assert.Equal(s.T(), []time.Duration{time.Minute}, store.leases)
+		})
+	}
+}
+
+func (s *OutboxRelaySuite) TestRelayOnce_FailingThenSucceedingPublisher() {
+	store := &fakeOutboxStore{}
+	store.add(s.T(), 1, Event{Type: TypeWithdrawalCompleted, TransactionID: "ref-1"})
+	publisher := &flakyPublisher{failures: 1}
+	relay := NewOutboxRelay(store, publisher, OutboxRelayConfig{}, nil)
+
+	sent, err := relay.RelayOnce(context.Background())
+	require.Error(s.T(), err)
+	assert.Zero(s.T(), sent)
+	assert.Equal(s.T(), 1, store.unsent())
+	assert.Equal(s.T(), "broker unavailable", store.rows[0].lastError)
+
+	sent, err = relay.RelayOnce(context.Background())
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, sent)
+	assert.Zero(s.T(), store.unsent())
+	assert.Equal(s.T(), []string{"ref-1"}, publisher.transactionIDs())
+}
+
+func (s *OutboxRelaySuite) TestRelayOnce_DeadRowIsNotClaimedAgain() {
+	store := &fakeOutboxStore{}
+	store.add(s.T(), 1, Event{Type: TypeWithdrawalCompleted, TransactionID: "ref-1"})
+	store.rows[0].message.Payload = []byte("{")
+	publisher := &flakyPublisher{}
+	relay := NewOutboxRelay(store, publisher, OutboxRelayConfig{}, nil)
+
+	for range 2 {
+		sent, err := relay.RelayOnce(context.Background())
+		require.NoError(s.T(), err)
+		assert.Zero(s.T(), sent)
+	}
+
+	assert.True(s.T(), store.rows[0].dead)
+	assert.Zero(s.T(), store.unsent())
+	assert.Empty(s.T(), publisher.transactionIDs())
+}
+
+func (s *OutboxRelaySuite) TestRun_RetriesUntilPublishedAndStopsOnCancel() {
+	store := &fakeOutboxStore{}
+	store.add(s.T(), 1, Event{Type: TypeWithdrawalCompleted, TransactionID: "ref-1"})
+	store.add(s.T(), 2, Event{Type: TypeWithdrawalCompleted, TransactionID: "ref-2"})
+	publisher := &flakyPublisher{failures: 2}
+	relay := NewOutboxRelay(store, publisher, OutboxRelayConfig{PollInterval: time.Millisecond}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(s.T(), func() bool { return store.unsent() == 0 }, time.Second, time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.T().Fatal("relay did not stop after cancel")
+	}
+	assert.Equal(s.T(), []string{"ref-1", "ref-2"}, publisher.transactionIDs())
+}
+
+func TestOutboxRelaySuite(t *testing.T) {
+	suite.Run(t, new(OutboxRelaySuite))
+}
@@ -26,6 +26,18 @@ type Decision struct {
 	StatusCode  int
 	Body        []byte
 	ContentType string
+	// CreatedAt is when the key was first acquired; set on DecisionReplay when known.
+	CreatedAt time.Time
+}
+
+// TruncatedContentType marks a stored response whose body was over the size limit. Its
+// body holds TruncatedBody metadata instead of the original, so it cannot be replayed.
+const TruncatedContentType = "application/vnd.idempotency-truncated+json"
+
+// TruncatedBody describes the response body that was too large to store.
+type TruncatedBody struct {
+	OriginalBytes       int    `json:"original_bytes"`
+	OriginalContentType string `json:"original_content_type,omitempty"`
 }
 
 type StoredResponse struct {
@@ -37,4 +49,16 @@ type StoredResponse struct {
 type Store interface {
 	Acquire(ctx context.Context, request Request) (Decision, error)
 	Complete(ctx context.Context, request Request, response StoredResponse) error
+	// Release drops a key that is still in progress, for a request that failed without a
+	// response worth replaying, so a retry with the same key runs again.
+	Release(ctx context.Context, request Request) error
+}
+
+// DeadLetterQueue holds responses whose Complete kept failing after the handler had already
+// committed its work. Reconcile completes them out of band, so the key replays the original
+// response instead of staying in progress until its lock expires and running again.
+type DeadLetterQueue interface {
+	Enqueue(ctx context.Context, request Request, response StoredResponse, cause error) error
+	// Reconcile completes up to limit queued responses and returns how many it resolved.
+	Reconcile(ctx context.Context, limit int) (int, error)
 }
@@ -62,10 +62,11 @@ func (s *SQLXStore) Acquire(ctx context.Context, request Request) (Decision, err
 		ResponseBody   []byte         `db:"response_body"`
 		ResponseType   sql.NullString `db:"response_content_type"`
 		LockedUntil    time.Time      `db:"locked_until"`
+		CreatedAt      time.Time      `db:"created_at"`
 	}
 
 	const selectQuery = `
-SELECT request_hash, status, response_status, response_body, response_content_type, locked_until
+SELECT request_hash, status, response_status, response_body, response_content_type, locked_until, created_at
 FROM withdraw_idempotency
 WHERE scope = $1 AND idempotency_key = $2
 FOR UPDATE`
@@ -107,7 +108,8 @@ INSERT INTO withdraw_idempotency (
 		}
 
 		decision := Decision{
-			Type: DecisionReplay,
+			Type:      DecisionReplay,
+			CreatedAt: existing.CreatedAt,
 		}
 		if existing.ResponseStatus.Valid {
 			decision.StatusCode = int(existing.ResponseStatus.Int64)
@@ -194,3 +196,124 @@ WHERE scope = $1 AND idempotency_key = $2 AND request_hash = $3`
 
 	return nil
 }
+
+func (s *SQLXStore) Release(ctx context.Context, request Request) error {
+	if s == nil || s.db == nil {
+		return errors.New("idempotency: store is not initialized")
+	}
+
+	const deleteQuery = `
+DELETE FROM withdraw_idempotency
+WHERE scope = $1 AND idempotency_key = $2 AND request_hash = $3 AND status = 'in_progress'`
+
+	scope := strings.TrimSpace(request.Scope)
+	key := strings.TrimSpace(request.Key)
+	hash := strings.TrimSpace(request.RequestHash)
+	if _, err := s.db.ExecContext(ctx, deleteQuery, scope, key, hash); err != nil {
+		return fmt.Errorf("idempotency: failed to release key: %w", err)
+	}
+
+	return nil
+}
+
+// Enqueue records a response that could not be completed in idempotency_dead_letter.
+func (s *SQLXStore) Enqueue(ctx context.Context, request Request, response StoredResponse, cause error) error {
+	if s == nil || s.db == nil {
+		return errors.New("idempotency: store is not initialized")
+	}
+
+	lastError := ""
+	if cause != nil {
+		lastError = cause.Error()
+	}
+
+	const insertQuery = `
+INSERT INTO idempotency_dead_letter (
+	scope, idempotency_key, request_hash, response_status, response_body, response_content_type, last_error
+) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := s.db.ExecContext(ctx, insertQuery,
+		strings.TrimSpace(request.Scope),
+		strings.TrimSpace(request.Key),
+		strings.TrimSpace(request.RequestHash),
+		response.StatusCode,
+		response.Body,
+		strings.TrimSpace(response.ContentType),
+		lastError,
+	)
+	if err != nil {
+		return fmt.Errorf("idempotency: failed to enqueue dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// Reconcile completes the oldest unresolved dead letters in one transaction. A key that was
+// completed in the meantime keeps its response; its dead letter is still resolved.
+func (s *SQLXStore) Reconcile(ctx context.Context, limit int) (int, error) {
+	if s == nil || s.db == nil {
+		return 0, errors.New("idempotency: store is not initialized")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("idempotency: failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	type row struct {
+		ID             int64          `db:"id"`
+		Scope          string         `db:"scope"`
+		Key            string         `db:"idempotency_key"`
+		RequestHash    string         `db:"request_hash"`
+		ResponseStatus int            `db:"response_status"`
+		ResponseBody   []byte         `db:"response_body"`
+		ResponseType   sql.NullString `db:"response_content_type"`
+	}
+
+	// SKIP LOCKED lets several replicas reconcile without waiting on each other's rows.
+	const selectQuery = `
+SELECT id, scope, idempotency_key, request_hash, response_status, response_body, response_content_type
+FROM idempotency_dead_letter
+WHERE resolved_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED`
+
+	var rows []row
+	if err := tx.SelectContext(ctx, &rows, selectQuery, limit); err != nil {
+		return 0, fmt.Errorf("idempotency: failed to query dead letters: %w", err)
+	}
+
+	const completeQuery = `
+UPDATE withdraw_idempotency
+SET
+	status = 'completed',
+	response_status = $4,
+	response_body = $5,
+	response_content_type = $6,
+	locked_until = now(),
+	completed_at = now(),
+	updated_at = now()
+WHERE scope = $1 AND idempotency_key = $2 AND request_hash = $3 AND status <> 'completed'`
+
+	const resolveQuery = `
+UPDATE idempotency_dead_letter
+SET resolved_at = now()
+WHERE id = $1`
+
+	for _, entry := range rows {
+		if _, err := tx.ExecContext(ctx, completeQuery, entry.Scope, entry.Key, entry.RequestHash, entry.ResponseStatus, entry.ResponseBody, entry.ResponseType.String); err != nil {
+			return 0, fmt.Errorf("idempotency: failed to complete dead letter %d: %w", entry.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, resolveQuery, entry.ID); err != nil {
+			return 0, fmt.Errorf("idempotency: failed to resolve dead letter %d: %w", entry.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("idempotency: failed to commit dead letter reconcile: %w", err)
+	}
+
+	return len(rows), nil
+}
@@ -0,0 +1,223 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"fmt"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+var _ TokenManager = (*asymmetricManager)(nil)
+
+type asymmetricManager struct {
+	method     jwtlib.SigningMethod
+	signingKID string
+	signingKey crypto.Signer
+	keys       map[string]crypto.PublicKey
+	issuer     string
+	audience   []string
+	ttl        time.Duration
+}
+
+// NewAsymmetric creates a TokenManager for StrategyRSA or StrategyECDSA over Options.Keys.
+// Every key must match the strategy's key type, and exactly one must be Active with a
+// private key.
+func NewAsymmetric(opts Options) (TokenManager, error) {
+	method, err := resolveAsymmetricMethod(opts.Strategy, opts.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Keys) == 0 {
+		return nil, fmt.Errorf("jwt: keyset must not be empty")
+	}
+
+	manager := &asymmetricManager{
+		method:   method,
+		keys:     make(map[string]crypto.PublicKey, len(opts.Keys)),
+		issuer:   opts.Issuer,
+		audience: opts.Audience,
+		ttl:      opts.TTL,
+	}
+
+	for _, entry := range opts.Keys {
+		if entry.KID == "" {
+			return nil, fmt.Errorf("jwt: key entry must have a kid")
+		}
+		if _, exists := manager.keys[entry.KID]; exists {
+			return nil, fmt.Errorf("jwt: duplicate kid %q", entry.KID)
+		}
+
+		privateKey, publicKey, err := parseKeyEntry(opts.Strategy, entry)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid key %q: %w", entry.KID, err)
+		}
+		if err := checkKeySize(method, publicKey); err != nil {
+			return nil, fmt.Errorf("jwt: invalid key %q: %w", entry.KID, err)
+		}
+		manager.keys[entry.KID] = publicKey
+
+		if !entry.Active {
+			continue
+		}
+		if manager.signingKey != nil {
+			return nil, fmt.Errorf("jwt: keys %q and %q are both active", manager.signingKID, entry.KID)
+		}
+		if privateKey == nil {
+			return nil, fmt.Errorf("jwt: active key %q has no private key", entry.KID)
+		}
+		manager.signingKID = entry.KID
+		manager.signingKey = privateKey
+	}
+
+	if manager.signingKey == nil {
+		return nil, fmt.Errorf("jwt: keyset has no active key")
+	}
+
+	return manager, nil
+}
+
+func resolveAsymmetricMethod(strategy Strategy, alg string) (jwtlib.SigningMethod, error) {
+	switch strategy {
+	case StrategyRSA:
+		switch alg {
+		case "", "RS256":
+			return jwtlib.SigningMethodRS256, nil
+		case "RS384":
+			return jwtlib.SigningMethodRS384, nil
+		case "RS512":
+			return jwtlib.SigningMethodRS512, nil
+		case "PS256":
+			return jwtlib.SigningMethodPS256, nil
+		case "PS384":
+			return jwtlib.SigningMethodPS384, nil
+		case "PS512":
+			return jwtlib.SigningMethodPS512, nil
+		}
+	case StrategyECDSA:
+		switch alg {
+		case "", "ES256":
+			return jwtlib.SigningMethodES256, nil
+		case "ES384":
+			return jwtlib.SigningMethodES384, nil
+		case "ES512":
+			return jwtlib.SigningMethodES512, nil
+		}
+	default:
+		return nil, fmt.Errorf("jwt: strategy %q is not asymmetric", strategy)
+	}
+	return nil, fmt.Errorf("jwt: unsupported %s algorithm %q", strategy, alg)
+}
+
+// parseKeyEntry returns the entry's private key (nil when only the public half is given)
+// and its public key.
+func parseKeyEntry(strategy Strategy, entry KeyEntry) (crypto.Signer, crypto.PublicKey, error) {
+	if len(entry.PrivateKeyPEM) == 0 && len(entry.PublicKeyPEM) == 0 {
+		return nil, nil, fmt.Errorf("a private or public key is required")
+	}
+
+	var (
+		privateKey crypto.Signer
+		publicKey  crypto.PublicKey
+		err        error
+	)
+
+	switch strategy {
+	case StrategyRSA:
+		if len(entry.PrivateKeyPEM) > 0 {
+			var key *rsa.PrivateKey
+			if key, err = jwtlib.ParseRSAPrivateKeyFromPEM(entry.PrivateKeyPEM); err != nil {
+				return nil, nil, err
+			}
+			privateKey, publicKey = key, key.Public()
+		}
+		if len(entry.PublicKeyPEM) > 0 {
+			if publicKey, err = jwtlib.ParseRSAPublicKeyFromPEM(entry.PublicKeyPEM); err != nil {
+				return nil, nil, err
+			}
+		}
+	case StrategyECDSA:
+		if len(entry.PrivateKeyPEM) > 0 {
+			var key *ecdsa.PrivateKey
+			if key, err = jwtlib.ParseECPrivateKeyFromPEM(entry.PrivateKeyPEM); err != nil {
+				return nil, nil, err
+			}
+			privateKey, publicKey = key, key.Public()
+		}
+		if len(entry.PublicKeyPEM) > 0 {
+			if publicKey, err = jwtlib.ParseECPublicKeyFromPEM(entry.PublicKeyPEM); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	// A pair that does not match would sign tokens the keyset itself rejects.
+	if privateKey != nil {
+		matcher, ok := privateKey.Public().(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !matcher.Equal(publicKey) {
+			return nil, nil, fmt.Errorf("public key does not match private key")
+		}
+	}
+
+	return privateKey, publicKey, nil
+}
+
+// checkKeySize rejects ECDSA keys on a curve other than the algorithm's, which would only
+// fail once the first token is signed or verified.
+func checkKeySize(method jwtlib.SigningMethod, publicKey crypto.PublicKey) error {
+	ecMethod, ok := method.(*jwtlib.SigningMethodECDSA)
+	if !ok {
+		return nil
+	}
+
+	key := publicKey.(*ecdsa.PublicKey)
+	if bits := key.Curve.Params().BitSize; bits != ecMethod.CurveBits {
+		return fmt.Errorf("%s needs a %d-bit curve, got %d", ecMethod.Alg(), ecMethod.CurveBits, bits)
+	}
+	return nil
+}
+
+func (m *asymmetricManager) Sign(_ context.Context, claims Claims) (string, error) {
+	token := jwtlib.NewWithClaims(m.method, newTokenClaims(claims, m.issuer, m.audience, m.ttl))
+	token.Header["kid"] = m.signingKID
+
+	signed, err := token.SignedString(m.signingKey)
+	if err != nil {
+		return "", fmt.Errorf("jwt: failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+func (m *asymmetricManager) Verify(_ context.Context, tokenString string) (*Claims, error) {
+	token, err := jwtlib.ParseWithClaims(
+		tokenString,
+		&tokenClaims{},
+		func(token *jwtlib.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+			if kid == "" {
+				return nil, fmt.Errorf("jwt: token header has no kid")
+			}
+
+			key, ok := m.keys[kid]
+			if !ok {
+				return nil, fmt.Errorf("%w %q", ErrUnknownKeyID, kid)
+			}
+			return key, nil
+		},
+		jwtlib.WithValidMethods([]string{m.method.Alg()}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: token validation failed: %w", err)
+	}
+
+	parsed, ok := token.Claims.(*tokenClaims)
+	if !ok {
+		return nil, fmt.Errorf("jwt: unexpected claims type")
+	}
+
+	return registeredToClaims(&parsed.RegisteredClaims, parsed.Scopes), nil
+}
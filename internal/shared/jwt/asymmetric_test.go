@@ -0,0 +1,201 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func privateKeyPEM(t *testing.T, key crypto.PrivateKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func publicKeyPEM(t *testing.T, key crypto.PublicKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+type AsymmetricManagerSuite struct {
+	suite.Suite
+
+	oldKey *rsa.PrivateKey
+	newKey *rsa.PrivateKey
+}
+
+func (s *AsymmetricManagerSuite) SetupSuite() {
+	var err error
+	s.oldKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(s.T(), err)
+	s.newKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(s.T(), err)
+}
+
+func (s *AsymmetricManagerSuite) manager(keys ...KeyEntry) TokenManager {
+	manager, err := New(Options{Strategy: StrategyRSA, Keys: keys, Issuer: "test", TTL: time.Minute})
+	require.NoError(s.T(), err)
+	return manager
+}
+
+func (s *AsymmetricManagerSuite) TestSign_UsesActiveKeyKID() {
+	manager := s.manager(
+		KeyEntry{KID: "old", PublicKeyPEM: publicKeyPEM(s.T(), &s.oldKey.PublicKey)},
+		KeyEntry{KID: "new", PrivateKeyPEM: privateKeyPEM(s.T(), s.newKey), Active: true},
+	)
+
+	token, err := manager.Sign(context.Background(), Claims{Subject: "user-1", Scopes: []string{"wallet:adjust"}})
+	require.NoError(s.T(), err)
+
+	parsed, _, err := jwtlib.NewParser().ParseUnverified(token, &tokenClaims{})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "new", parsed.Header["kid"])
+	assert.Equal(s.T(), "RS256", parsed.Header["alg"])
+
+	claims, err := manager.Verify(context.Background(), token)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "user-1", claims.Subject)
+	assert.Equal(s.T(), "test", claims.Issuer)
+	assert.Equal(s.T(), []string{"wallet:adjust"}, claims.Scopes)
+}
+
+func (s *AsymmetricManagerSuite) TestVerify_AcceptsTokensAcrossRotation() {
+	before := s.manager(KeyEntry{KID: "old", PrivateKeyPEM: privateKeyPEM(s.T(), s.oldKey), Active: true})
+	oldToken, err := before.Sign(context.Background(), Claims{Subject: "user-1"})
+	require.NoError(s.T(), err)
+
+	// Step 1: the new key is rolled out to verifiers before anyone signs with it.
+	rotatedIn := s.manager(
+		KeyEntry{KID: "old", PrivateKeyPEM: privateKeyPEM(s.T(), s.oldKey), Active: true},
+		KeyEntry{KID: "new", PublicKeyPEM: publicKeyPEM(s.T(), &s.newKey.PublicKey)},
+	)
+	// Step 2: signing switches to the new key while the old one still verifies.
+	after := s.manager(
+		KeyEntry{KID: "old", PublicKeyPEM: publicKeyPEM(s.T(), &s.oldKey.PublicKey)},
+		KeyEntry{KID: "new", PrivateKeyPEM: privateKeyPEM(s.T(), s.newKey), Active: true},
+	)
+	newToken, err := after.Sign(context.Background(), Claims{Subject: "user-2"})
+	require.NoError(s.T(), err)
+
+	for _, verifier := range []TokenManager{rotatedIn, after} {
+		claims, err := verifier.Verify(context.Background(), oldToken)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), "user-1", claims.Subject)
+
+		claims, err = verifier.Verify(context.Background(), newToken)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), "user-2", claims.Subject)
+	}
+}
+
+func (s *AsymmetricManagerSuite) TestVerify_RejectsUnknownOrMissingKID() {
+	manager := s.manager(KeyEntry{KID: "old", PrivateKeyPEM: privateKeyPEM(s.T(), s.oldKey), Active: true})
+	other := s.manager(KeyEntry{KID: "new", PrivateKeyPEM: privateKeyPEM(s.T(), s.newKey), Active: true})
+
+	token, err := other.Sign(context.Background(), Claims{Subject: "user-1"})
+	require.NoError(s.T(), err)
+	_, err = manager.Verify(context.Background(), token)
+	require.ErrorIs(s.T(), err, ErrUnknownKeyID)
+
+	// A known kid does not help a token signed by a different key.
+	forged := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, tokenClaims{RegisteredClaims: jwtlib.RegisteredClaims{Subject: "user-1"}})
+	forged.Header["kid"] = "old"
+	signed, err := forged.SignedString(s.newKey)
+	require.NoError(s.T(), err)
+	_, err = manager.Verify(context.Background(), signed)
+	require.ErrorIs(s.T(), err, jwtlib.ErrTokenSignatureInvalid)
+
+	unstamped := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, tokenClaims{RegisteredClaims: jwtlib.RegisteredClaims{Subject: "user-1"}})
+	signed, err = unstamped.SignedString(s.oldKey)
+	require.NoError(s.T(), err)
+	_, err = manager.Verify(context.Background(), signed)
+	require.ErrorContains(s.T(), err, "no kid")
+}
+
+func (s *AsymmetricManagerSuite) TestVerify_RejectsOtherAlgorithms() {
+	manager := s.manager(KeyEntry{KID: "old", PrivateKeyPEM: privateKeyPEM(s.T(), s.oldKey), Active: true})
+
+	// HS256 keyed with the public key PEM is the classic algorithm-confusion attack.
+	confused := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, tokenClaims{RegisteredClaims: jwtlib.RegisteredClaims{Subject: "user-1"}})
+	confused.Header["kid"] = "old"
+	signed, err := confused.SignedString(publicKeyPEM(s.T(), &s.oldKey.PublicKey))
+	require.NoError(s.T(), err)
+
+	_, err = manager.Verify(context.Background(), signed)
+	require.ErrorIs(s.T(), err, jwtlib.ErrTokenSignatureInvalid)
+}
+
+func (s *AsymmetricManagerSuite) TestNew_ECDSA() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(s.T(), err)
+
+	manager, err := New(Options{Strategy: StrategyECDSA, Keys: []KeyEntry{{KID: "ec-1", PrivateKeyPEM: privateKeyPEM(s.T(), key), Active: true}}})
+	require.NoError(s.T(), err)
+
+	token, err := manager.Sign(context.Background(), Claims{Subject: "user-1"})
+	require.NoError(s.T(), err)
+	claims, err := manager.Verify(context.Background(), token)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "user-1", claims.Subject)
+
+	_, err = New(Options{Strategy: StrategyECDSA, Algorithm: "ES384", Keys: []KeyEntry{{KID: "ec-1", PrivateKeyPEM: privateKeyPEM(s.T(), key), Active: true}}})
+	require.ErrorContains(s.T(), err, "ES384 needs a 384-bit curve, got 256")
+}
+
+func (s *AsymmetricManagerSuite) TestNew_InvalidKeyset_TableDriven() {
+	oldPrivate := privateKeyPEM(s.T(), s.oldKey)
+	newPublic := publicKeyPEM(s.T(), &s.newKey.PublicKey)
+
+	tests := []struct {
+		name      string
+		algorithm string
+		keys      []KeyEntry
+		expectErr string
+	}{
+		{name: "empty keyset", expectErr: "keyset must not be empty"},
+		{name: "missing kid", keys: []KeyEntry{{PrivateKeyPEM: oldPrivate, Active: true}}, expectErr: "must have a kid"},
+		{
+			name:      "duplicate kid",
+			keys:      []KeyEntry{{KID: "k", PrivateKeyPEM: oldPrivate, Active: true}, {KID: "k", PublicKeyPEM: newPublic}},
+			expectErr: `duplicate kid "k"`,
+		},
+		{name: "no active key", keys: []KeyEntry{{KID: "k", PrivateKeyPEM: oldPrivate}}, expectErr: "no active key"},
+		{
+			name:      "two active keys",
+			keys:      []KeyEntry{{KID: "a", PrivateKeyPEM: oldPrivate, Active: true}, {KID: "b", PrivateKeyPEM: privateKeyPEM(s.T(), s.newKey), Active: true}},
+			expectErr: `keys "a" and "b" are both active`,
+		},
+		{name: "active key without private half", keys: []KeyEntry{{KID: "k", PublicKeyPEM: newPublic, Active: true}}, expectErr: `active key "k" has no private key`},
+		{name: "mismatched pair", keys: []KeyEntry{{KID: "k", PrivateKeyPEM: oldPrivate, PublicKeyPEM: newPublic, Active: true}}, expectErr: "does not match"},
+		{name: "no key material", keys: []KeyEntry{{KID: "k", Active: true}}, expectErr: "private or public key is required"},
+		{name: "unsupported algorithm", algorithm: "ES256", keys: []KeyEntry{{KID: "k", PrivateKeyPEM: oldPrivate, Active: true}}, expectErr: `unsupported rsa algorithm "ES256"`},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			_, err := New(Options{Strategy: StrategyRSA, Algorithm: tc.algorithm, Keys: tc.keys})
+			require.ErrorContains(s.T(), err, tc.expectErr)
+		})
+	}
+}
+
+func TestAsymmetricManagerSuite(t *testing.T) {
+	suite.Run(t, new(AsymmetricManagerSuite))
+}
@@ -10,6 +10,12 @@ import (
 
 var _ TokenManager = (*hmacManager)(nil)
 
+// tokenClaims extends the registered claims with the private claims we issue.
+type tokenClaims struct {
+	jwtlib.RegisteredClaims
+	Scopes []string `json:"scopes,omitempty"`
+}
+
 type hmacManager struct {
 	secret   []byte
 	method   jwtlib.SigningMethod
@@ -57,6 +63,17 @@ func resolveHMACMethod(alg string) (jwtlib.SigningMethod, error) {
 }
 
 func (m *hmacManager) Sign(_ context.Context, claims Claims) (string, error) {
+	token := jwtlib.NewWithClaims(m.method, newTokenClaims(claims, m.issuer, m.audience, m.ttl))
+
+	signed, err := token.SignedString(m.secret)
+	if err != nil {
+		return "", fmt.Errorf("jwt: failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// newTokenClaims fills the fields left zero in claims from the manager's defaults.
+func newTokenClaims(claims Claims, issuer string, audience []string, ttl time.Duration) tokenClaims {
 	now := time.Now()
 
 	registered := jwtlib.RegisteredClaims{
@@ -68,13 +85,13 @@ func (m *hmacManager) Sign(_ context.Context, claims Claims) (string, error) {
 	if claims.Issuer != "" {
 		registered.Issuer = claims.Issuer
 	} else {
-		registered.Issuer = m.issuer
+		registered.Issuer = issuer
 	}
 
 	if claims.Audience != nil {
 		registered.Audience = jwtlib.ClaimStrings(claims.Audience)
-	} else if m.audience != nil {
-		registered.Audience = jwtlib.ClaimStrings(m.audience)
+	} else if audience != nil {
+		registered.Audience = jwtlib.ClaimStrings(audience)
 	}
 
 	if !claims.IssuedAt.IsZero() {
@@ -85,27 +102,24 @@ func (m *hmacManager) Sign(_ context.Context, claims Claims) (string, error) {
 
 	if !claims.ExpiresAt.IsZero() {
 		registered.ExpiresAt = jwtlib.NewNumericDate(claims.ExpiresAt)
-	} else if m.ttl > 0 {
-		registered.ExpiresAt = jwtlib.NewNumericDate(now.Add(m.ttl))
+	} else if ttl > 0 {
+		registered.ExpiresAt = jwtlib.NewNumericDate(now.Add(ttl))
 	}
 
 	if !claims.NotBefore.IsZero() {
 		registered.NotBefore = jwtlib.NewNumericDate(claims.NotBefore)
 	}
 
-	token := jwtlib.NewWithClaims(m.method, registered)
-
-	signed, err := token.SignedString(m.secret)
-	if err != nil {
-		return "", fmt.Errorf("jwt: failed to sign token: %w", err)
+	return tokenClaims{
+		RegisteredClaims: registered,
+		Scopes:           claims.Scopes,
 	}
-	return signed, nil
 }
 
 func (m *hmacManager) Verify(_ context.Context, tokenString string) (*Claims, error) {
 	token, err := jwtlib.ParseWithClaims(
 		tokenString,
-		&jwtlib.RegisteredClaims{},
+		&tokenClaims{},
 		func(token *jwtlib.Token) (any, error) {
 			// Ensure the signing method matches what we expect.
 			if token.Method.Alg() != m.method.Alg() {
@@ -118,20 +132,21 @@ func (m *hmacManager) Verify(_ context.Context, tokenString string) (*Claims, er
 		return nil, fmt.Errorf("jwt: token validation failed: %w", err)
 	}
 
-	registered, ok := token.Claims.(*jwtlib.RegisteredClaims)
+	parsed, ok := token.Claims.(*tokenClaims)
 	if !ok {
 		return nil, fmt.Errorf("jwt: unexpected claims type")
 	}
 
-	return registeredToClaims(registered), nil
+	return registeredToClaims(&parsed.RegisteredClaims, parsed.Scopes), nil
 }
 
-func registeredToClaims(r *jwtlib.RegisteredClaims) *Claims {
+func registeredToClaims(r *jwtlib.RegisteredClaims, scopes []string) *Claims {
 	c := &Claims{
 		Subject:  r.Subject,
 		Issuer:   r.Issuer,
 		Audience: []string(r.Audience),
 		ID:       r.ID,
+		Scopes:   scopes,
 	}
 	if r.ExpiresAt != nil {
 		c.ExpiresAt = r.ExpiresAt.Time
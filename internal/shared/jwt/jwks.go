@@ -0,0 +1,288 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+var _ Verifier = (*jwksVerifier)(nil)
+
+const (
+	defaultJWKSRefreshInterval = 15 * time.Minute
+	defaultJWKSMinRefreshGap   = 10 * time.Second
+	defaultJWKSFetchTimeout    = 5 * time.Second
+)
+
+// ErrUnknownKeyID is returned when no verification key matches the token's "kid" header.
+// The JWKS verifier only returns it after refreshing the key set.
+var ErrUnknownKeyID = errors.New("jwt: no verification key for kid")
+
+// jwksAlgorithms are the asymmetric algorithms accepted from a JWKS. HMAC is excluded
+// so a public key can never be used as a shared secret.
+var jwksAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+type jwksVerifier struct {
+	url           string
+	client        *http.Client
+	refreshEvery  time.Duration
+	minRefreshGap time.Duration
+	now           func() time.Time
+
+	// fetchMu serializes fetches so a burst of unknown kids triggers one request.
+	fetchMu sync.Mutex
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+// NewJWKSVerifier creates a verify-only Verifier that resolves keys from Options.JWKSURL.
+// Keys are cached by "kid" and refetched after Options.JWKSRefreshInterval or when a token
+// carries a kid missing from the cache. When a refresh fails, previously fetched keys keep
+// serving until the endpoint recovers.
+func NewJWKSVerifier(opts Options) (Verifier, error) {
+	if opts.JWKSURL == "" {
+		return nil, fmt.Errorf("jwt: JWKS URL must not be empty")
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultJWKSFetchTimeout}
+	}
+
+	refreshEvery := opts.JWKSRefreshInterval
+	if refreshEvery <= 0 {
+		refreshEvery = defaultJWKSRefreshInterval
+	}
+
+	return &jwksVerifier{
+		url:           opts.JWKSURL,
+		client:        client,
+		refreshEvery:  refreshEvery,
+		minRefreshGap: min(defaultJWKSMinRefreshGap, refreshEvery),
+		now:           time.Now,
+	}, nil
+}
+
+func (v *jwksVerifier) Verify(ctx context.Context, tokenString string) (*Claims, error) {
+	token, err := jwtlib.ParseWithClaims(
+		tokenString,
+		&tokenClaims{},
+		func(token *jwtlib.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+			if kid == "" {
+				return nil, fmt.Errorf("jwt: token header has no kid")
+			}
+			return v.key(ctx, kid)
+		},
+		jwtlib.WithValidMethods(jwksAlgorithms),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: token validation failed: %w", err)
+	}
+
+	parsed, ok := token.Claims.(*tokenClaims)
+	if !ok {
+		return nil, fmt.Errorf("jwt: unexpected claims type")
+	}
+
+	return registeredToClaims(&parsed.RegisteredClaims, parsed.Scopes), nil
+}
+
+// key returns the cached key for kid, refreshing the set when it is stale or kid is unknown.
+func (v *jwksVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, found, stale := v.cached(kid)
+	if found && !stale {
+		return key, nil
+	}
+
+	refreshErr := v.refresh(ctx, !found)
+
+	if refreshed, ok, _ := v.cached(kid); ok {
+		return refreshed, nil
+	}
+	if refreshErr != nil {
+		return nil, refreshErr
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownKeyID, kid)
+}
+
+func (v *jwksVerifier) cached(kid string) (crypto.PublicKey, bool, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	key, ok := v.keys[kid]
+	return key, ok, v.now().Sub(v.fetchedAt) >= v.refreshEvery
+}
+
+// refresh refetches the key set. Unknown-kid refreshes are throttled by minRefreshGap so
+// tokens with bogus kids cannot turn every request into a JWKS fetch.
+func (v *jwksVerifier) refresh(ctx context.Context, unknownKID bool) error {
+	v.fetchMu.Lock()
+	defer v.fetchMu.Unlock()
+
+	v.mu.RLock()
+	sinceFetch := v.now().Sub(v.fetchedAt)
+	sinceAttempt := v.now().Sub(v.attemptedAt)
+	v.mu.RUnlock()
+
+	// Another caller may have refreshed while we waited for fetchMu.
+	if !unknownKID && sinceFetch < v.refreshEvery {
+		return nil
+	}
+	if sinceAttempt < v.minRefreshGap {
+		return nil
+	}
+
+	v.mu.Lock()
+	v.attemptedAt = v.now()
+	v.mu.Unlock()
+
+	keys, err := v.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys = keys
+	v.fetchedAt = v.now()
+	return nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (v *jwksVerifier) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: failed to build JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwt: JWKS endpoint returned status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("jwt: failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kid == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid JWKS key %q: %w", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+// publicKey decodes RSA and EC keys; other key types are skipped by returning nil.
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeJWKInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, err := jwkCurve(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x coordinate: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y coordinate: %w", err)
+		}
+
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("coordinates must be %d bytes", size)
+		}
+
+		point := make([]byte, 0, 1+2*size)
+		point = append(point, 0x04)
+		point = append(point, x...)
+		point = append(point, y...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	default:
+		return nil, nil
+	}
+}
+
+func jwkCurve(crv string) (elliptic.Curve, error) {
+	switch crv {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", crv)
+	}
+}
+
+func decodeJWKInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, fmt.Errorf("missing value")
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
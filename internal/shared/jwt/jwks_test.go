@@ -0,0 +1,305 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeJWKSServer serves a mutable key set so tests can rotate keys or simulate outages.
+type fakeJWKSServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	keys     []map[string]string
+	status   int
+	requests int
+}
+
+func newFakeJWKSServer(t *testing.T) *fakeJWKSServer {
+	t.Helper()
+
+	fake := &fakeJWKSServer{status: http.StatusOK}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+
+		fake.requests++
+		if fake.status != http.StatusOK {
+			w.WriteHeader(fake.status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": fake.keys})
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func (f *fakeJWKSServer) serve(status int, keys ...map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = status
+	f.keys = keys
+}
+
+func (f *fakeJWKSServer) requestCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(t *testing.T, kid string, key *ecdsa.PrivateKey) map[string]string {
+	t.Helper()
+
+	point, err := key.PublicKey.Bytes()
+	require.NoError(t, err)
+	size := (len(point) - 1) / 2
+	return map[string]string{
+		"kty": "EC",
+		"kid": kid,
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+		"y":   base64.RawURLEncoding.EncodeToString(point[1+size:]),
+	}
+}
+
+func signWithKID(t *testing.T, method jwtlib.SigningMethod, kid string, key any) string {
+	t.Helper()
+
+	token := jwtlib.NewWithClaims(method, tokenClaims{
+		RegisteredClaims: jwtlib.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    "identity-provider",
+			ExpiresAt: jwtlib.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Scopes: []string{"wallet:read"},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+type JWKSVerifierSuite struct {
+	suite.Suite
+
+	rsaKey     *rsa.PrivateKey
+	rotatedKey *rsa.PrivateKey
+	ecKey      *ecdsa.PrivateKey
+
+	server *fakeJWKSServer
+	clock  time.Time
+}
+
+func (s *JWKSVerifierSuite) SetupSuite() {
+	var err error
+	s.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(s.T(), err)
+	s.rotatedKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(s.T(), err)
+	s.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(s.T(), err)
+}
+
+func (s *JWKSVerifierSuite) SetupTest() {
+	s.server = newFakeJWKSServer(s.T())
+	s.server.serve(http.StatusOK, rsaJWK("rsa-1", s.rsaKey), ecJWK(s.T(), "ec-1", s.ecKey))
+	s.clock = time.Now()
+}
+
+func (s *JWKSVerifierSuite) newVerifier() *jwksVerifier {
+	verifier, err := NewVerifier(Options{
+		Strategy:            StrategyJWKS,
+		JWKSURL:             s.server.URL,
+		JWKSRefreshInterval: time.Minute,
+	})
+	require.NoError(s.T(), err)
+
+	jwks := verifier.(*jwksVerifier)
+	jwks.now = func() time.Time { return s.clock }
+	return jwks
+}
+
+func (s *JWKSVerifierSuite) TestNewJWKSVerifier_TableDriven() {
+	tests := []struct {
+		name      string
+		opts      Options
+		expectErr string
+	}{
+		{name: "requires url", opts: Options{Strategy: StrategyJWKS}, expectErr: "JWKS URL must not be empty"},
+		{name: "token manager rejects verify-only strategy", opts: Options{Strategy: StrategyJWKS, JWKSURL: "http://jwks"}, expectErr: "verify-only"},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			var err error
+			if tc.opts.JWKSURL == "" {
+				_, err = NewVerifier(tc.opts)
+			} else {
+				_, err = New(tc.opts)
+			}
+			require.Error(s.T(), err)
+			assert.ErrorContains(s.T(), err, tc.expectErr)
+		})
+	}
+}
+
+func (s *JWKSVerifierSuite) TestVerify_TableDriven() {
+	hmacSecret := []byte("12345678901234567890123456789012")
+
+	tests := []struct {
+		name        string
+		token       func() string
+		expectErr   error
+		expectErrIn string
+	}{
+		{
+			name:  "rsa key selected by kid",
+			token: func() string { return signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-1", s.rsaKey) },
+		},
+		{
+			name:  "ec key selected by kid",
+			token: func() string { return signWithKID(s.T(), jwtlib.SigningMethodES256, "ec-1", s.ecKey) },
+		},
+		{
+			name:      "unknown kid",
+			token:     func() string { return signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-9", s.rsaKey) },
+			expectErr: ErrUnknownKeyID,
+		},
+		{
+			name:        "missing kid",
+			token:       func() string { return signWithKID(s.T(), jwtlib.SigningMethodRS256, "", s.rsaKey) },
+			expectErrIn: "no kid",
+		},
+		{
+			name:        "signature from a different key",
+			token:       func() string { return signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-1", s.rotatedKey) },
+			expectErrIn: "token validation failed",
+		},
+		{
+			name:        "hmac token is rejected",
+			token:       func() string { return signWithKID(s.T(), jwtlib.SigningMethodHS256, "rsa-1", hmacSecret) },
+			expectErrIn: "signing method HS256 is invalid",
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			claims, err := s.newVerifier().Verify(context.Background(), tc.token())
+			if tc.expectErr == nil && tc.expectErrIn == "" {
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), "user-1", claims.Subject)
+				assert.Equal(s.T(), "identity-provider", claims.Issuer)
+				assert.Equal(s.T(), []string{"wallet:read"}, claims.Scopes)
+				return
+			}
+
+			require.Error(s.T(), err)
+			if tc.expectErr != nil {
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			}
+			if tc.expectErrIn != "" {
+				assert.ErrorContains(s.T(), err, tc.expectErrIn)
+			}
+		})
+	}
+}
+
+func (s *JWKSVerifierSuite) TestVerify_CachesKeysUntilRefreshInterval() {
+	verifier := s.newVerifier()
+	token := signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-1", s.rsaKey)
+
+	for i := 0; i < 3; i++ {
+		_, err := verifier.Verify(context.Background(), token)
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), 1, s.server.requestCount())
+
+	s.clock = s.clock.Add(time.Minute)
+	_, err := verifier.Verify(context.Background(), token)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, s.server.requestCount())
+}
+
+func (s *JWKSVerifierSuite) TestVerify_KeyRotation() {
+	verifier := s.newVerifier()
+
+	_, err := verifier.Verify(context.Background(), signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-1", s.rsaKey))
+	require.NoError(s.T(), err)
+
+	s.server.serve(http.StatusOK, rsaJWK("rsa-2", s.rotatedKey))
+	rotatedToken := signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-2", s.rotatedKey)
+
+	// Unknown-kid refreshes are throttled right after a fetch.
+	_, err = verifier.Verify(context.Background(), rotatedToken)
+	assert.ErrorIs(s.T(), err, ErrUnknownKeyID)
+	assert.Equal(s.T(), 1, s.server.requestCount())
+
+	s.clock = s.clock.Add(defaultJWKSMinRefreshGap)
+	_, err = verifier.Verify(context.Background(), rotatedToken)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, s.server.requestCount())
+
+	// The retired key is gone from the refreshed set.
+	_, err = verifier.Verify(context.Background(), signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-1", s.rsaKey))
+	assert.ErrorIs(s.T(), err, ErrUnknownKeyID)
+}
+
+func (s *JWKSVerifierSuite) TestVerify_NetworkErrors() {
+	verifier := s.newVerifier()
+	token := signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-1", s.rsaKey)
+
+	_, err := verifier.Verify(context.Background(), token)
+	require.NoError(s.T(), err)
+
+	s.server.serve(http.StatusInternalServerError)
+	s.clock = s.clock.Add(2 * time.Minute)
+
+	_, err = verifier.Verify(context.Background(), token)
+	require.NoError(s.T(), err, "cached keys should keep serving while the endpoint is down")
+	assert.Equal(s.T(), 2, s.server.requestCount())
+
+	s.clock = s.clock.Add(defaultJWKSMinRefreshGap)
+	_, err = verifier.Verify(context.Background(), signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-2", s.rotatedKey))
+	require.Error(s.T(), err)
+	assert.ErrorContains(s.T(), err, "JWKS endpoint returned status 500")
+
+	s.server.Close()
+	s.clock = s.clock.Add(defaultJWKSMinRefreshGap)
+	_, err = verifier.Verify(context.Background(), signWithKID(s.T(), jwtlib.SigningMethodRS256, "rsa-2", s.rotatedKey))
+	require.Error(s.T(), err)
+	assert.ErrorContains(s.T(), err, "failed to fetch JWKS")
+}
+
+func TestJWKSVerifierSuite(t *testing.T) {
+	suite.Run(t, new(JWKSVerifierSuite))
+}
@@ -3,6 +3,7 @@ package jwt
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 )
 
@@ -11,9 +12,13 @@ type Strategy string
 
 const (
 	StrategyHMAC Strategy = "hmac"
+	// StrategyJWKS verifies tokens against keys published at Options.JWKSURL.
+	// It is verify-only; build it with NewVerifier or NewJWKSVerifier.
+	StrategyJWKS Strategy = "jwks"
+	// StrategyRSA and StrategyECDSA sign and verify with the keyset in Options.Keys.
+	StrategyRSA   Strategy = "rsa"
+	StrategyECDSA Strategy = "ecdsa"
 	// Future strategies:
-	// StrategyRSA   Strategy = "rsa"
-	// StrategyECDSA Strategy = "ecdsa"
 	// StrategyEdDSA Strategy = "eddsa"
 )
 
@@ -28,21 +33,32 @@ type Options struct {
 	// Must be at least 32 bytes. Required when Strategy is StrategyHMAC.
 	Secret []byte
 
-	// ── Asymmetric options (future) ──
+	// ── Asymmetric options ──
 
-	// PrivateKeyPEM is the PEM-encoded private key (RSA/ECDSA/EdDSA).
-	// Required for signing with asymmetric strategies.
-	// PrivateKeyPEM []byte
+	// Keys is the keyset for StrategyRSA and StrategyECDSA. Tokens are signed with the
+	// single Active key and stamped with its KID; verification picks the key named by the
+	// token's "kid" header, so a retired key can stay listed until its tokens expire.
+	Keys []KeyEntry
 
-	// PublicKeyPEM is the PEM-encoded public key (RSA/ECDSA/EdDSA).
-	// Required for verification with asymmetric strategies.
-	// If provided without PrivateKeyPEM, only verification is available.
-	// PublicKeyPEM []byte
+	// ── JWKS options ──
+
+	// JWKSURL is the endpoint serving the JSON Web Key Set.
+	// Required when Strategy is StrategyJWKS.
+	JWKSURL string
+
+	// JWKSRefreshInterval is how long fetched keys are trusted before refetching.
+	// Defaults to 15 minutes.
+	JWKSRefreshInterval time.Duration
+
+	// HTTPClient fetches the JWKS. Defaults to a client with a 5 second timeout.
+	HTTPClient *http.Client
 
 	// ── Common options ──
 
 	// Algorithm specifies the exact signing algorithm within the strategy.
 	// HMAC: "HS256" (default), "HS384", "HS512".
+	// RSA: "RS256" (default), "RS384", "RS512", "PS256", "PS384", "PS512".
+	// ECDSA: "ES256" (default), "ES384", "ES512".
 	// If empty, defaults to the strategy's recommended algorithm.
 	Algorithm string
 
@@ -57,6 +73,22 @@ type Options struct {
 	TTL time.Duration
 }
 
+// KeyEntry is one key of an asymmetric keyset.
+type KeyEntry struct {
+	// KID identifies the key in the token's "kid" header. Must be unique within Keys.
+	KID string
+
+	// PrivateKeyPEM is the PEM-encoded private key. Required for the Active key.
+	PrivateKeyPEM []byte
+
+	// PublicKeyPEM is the PEM-encoded public key. Derived from PrivateKeyPEM when empty,
+	// so rotated-in keys only need the public half.
+	PublicKeyPEM []byte
+
+	// Active marks the key used for signing. Exactly one key must be active.
+	Active bool
+}
+
 // Claims represents the standard JWT registered claims (RFC 7519 §4.1).
 // This type is library-agnostic; the underlying JWT library is an implementation detail.
 type Claims struct {
@@ -86,6 +118,23 @@ type Claims struct {
 	// ID is the unique token identifier (jti claim).
 	// If empty, no jti is set.
 	ID string
+
+	// Scopes lists the permissions granted to the subject ("scopes" claim).
+	// Omitted from the token when empty.
+	Scopes []string
+}
+
+// HasScope reports whether the claims grant the given scope.
+func (c *Claims) HasScope(scope string) bool {
+	if c == nil {
+		return false
+	}
+	for _, granted := range c.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
 }
 
 // Signer creates signed JWT tokens.
@@ -119,7 +168,20 @@ func New(opts Options) (TokenManager, error) {
 	switch opts.Strategy {
 	case StrategyHMAC:
 		return NewHMAC(opts)
+	case StrategyRSA, StrategyECDSA:
+		return NewAsymmetric(opts)
+	case StrategyJWKS:
+		return nil, fmt.Errorf("jwt: strategy %q is verify-only, use NewVerifier", opts.Strategy)
 	default:
 		return nil, fmt.Errorf("jwt: unknown strategy %q", opts.Strategy)
 	}
 }
+
+// NewVerifier creates a Verifier based on the provided options.
+// Unlike New it also accepts verify-only strategies such as StrategyJWKS.
+func NewVerifier(opts Options) (Verifier, error) {
+	if opts.Strategy == StrategyJWKS {
+		return NewJWKSVerifier(opts)
+	}
+	return New(opts)
+}
@@ -0,0 +1,31 @@
+// Package lockout tracks consecutive failed attempts per key and locks the key
+// for a cooldown window once a threshold is reached.
+package lockout
+
+import (
+	"context"
+	"time"
+)
+
+// Config configures the lockout policy.
+type Config struct {
+	// Threshold is the number of consecutive failures that triggers a lockout.
+	Threshold int64
+
+	// Cooldown is how long a key stays locked. It also bounds how long
+	// failures are remembered between attempts.
+	Cooldown time.Duration
+}
+
+// Tracker is the interface consumers depend on for attempt tracking.
+// Implementations must be safe for concurrent use.
+type Tracker interface {
+	// LockedFor returns the remaining lock duration for key, or zero when the key is not locked.
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+
+	// RegisterFailure records a failed attempt and locks the key once the threshold is reached.
+	RegisterFailure(ctx context.Context, key string) error
+
+	// Reset clears recorded failures for key.
+	Reset(ctx context.Context, key string) error
+}
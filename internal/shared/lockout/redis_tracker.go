@@ -0,0 +1,91 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisTracker is a distributed lockout tracker using Redis.
+// Safe for multi-instance deployments.
+type RedisTracker struct {
+	client *redis.Client
+	prefix string
+	config Config
+}
+
+// NewRedisTracker creates a new Redis-based lockout tracker.
+func NewRedisTracker(client *redis.Client, prefix string, config Config) *RedisTracker {
+	if prefix == "" {
+		prefix = "lockout"
+	}
+
+	return &RedisTracker{client: client, prefix: prefix, config: config}
+}
+
+func (t *RedisTracker) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	if t == nil || t.client == nil {
+		return 0, errors.New("lockout: redis tracker is not initialized")
+	}
+
+	ttl, err := t.client.PTTL(ctx, t.lockedKey(key)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("lockout: failed to read lock: %w", err)
+	}
+
+	if ttl <= 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (t *RedisTracker) RegisterFailure(ctx context.Context, key string) error {
+	if t == nil || t.client == nil {
+		return errors.New("lockout: redis tracker is not initialized")
+	}
+
+	const script = `
+local failures = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+if failures >= tonumber(ARGV[1]) then
+	redis.call('SET', KEYS[2], '1', 'PX', ARGV[2])
+	redis.call('DEL', KEYS[1])
+end
+return failures
+`
+
+	cooldown := t.config.Cooldown.Milliseconds()
+	if cooldown <= 0 {
+		cooldown = 1
+	}
+
+	if err := t.client.Eval(ctx, script, []string{t.failuresKey(key), t.lockedKey(key)}, t.config.Threshold, cooldown).Err(); err != nil {
+		return fmt.Errorf("lockout: failed to register failure: %w", err)
+	}
+
+	return nil
+}
+
+func (t *RedisTracker) Reset(ctx context.Context, key string) error {
+	if t == nil || t.client == nil {
+		return errors.New("lockout: redis tracker is not initialized")
+	}
+
+	if err := t.client.Del(ctx, t.failuresKey(key)).Err(); err != nil {
+		return fmt.Errorf("lockout: failed to reset failures: %w", err)
+	}
+
+	return nil
+}
+
+func (t *RedisTracker) failuresKey(key string) string {
+	return t.prefix + ":failures:" + key
+}
+
+func (t *RedisTracker) lockedKey(key string) string {
+	return t.prefix + ":locked:" + key
+}
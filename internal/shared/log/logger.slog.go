@@ -0,0 +1,79 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joshuarp/withdraw-api/internal/shared/config"
+)
+
+// NewLogger builds the application logger from logging.level, logging.format (json or
+// text, default json) and logging.output (stdout, stderr or a file path, default stdout).
+// A file is opened for append and stays open for the life of the process; an unknown
+// format or an output that cannot be opened fails startup.
+func NewLogger(cfg config.ConfigProvider) (*slog.Logger, error) {
+	output, err := openOutput(cfg.GetString("logging.output"))
+	if err != nil {
+		return nil, err
+	}
+
+	handler, err := newHandler(cfg.GetString("logging.format"), output, parseLevel(cfg.GetString("logging.level")))
+	if err != nil {
+		return nil, err
+	}
+
+	return slog.New(handler), nil
+}
+
+func openOutput(output string) (io.Writer, error) {
+	switch output = strings.TrimSpace(output); strings.ToLower(output) {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	}
+
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("log: failed to open logging.output %q: %w", output, err)
+	}
+	return file, nil
+}
+
+func newHandler(format string, output io.Writer, level slog.Level) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.String(slog.TimeKey, attr.Value.Time().UTC().Format(time.RFC3339))
+			}
+			return attr
+		},
+	}
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "json":
+		return slog.NewJSONHandler(output, opts), nil
+	case "text":
+		return slog.NewTextHandler(output, opts), nil
+	default:
+		return nil, fmt.Errorf("log: unknown logging.format %q (expected json|text)", format)
+	}
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -1,39 +0,0 @@
-package log
-
-import (
-	"log/slog"
-	"os"
-	"strings"
-	"time"
-
-	"github.com/joshuarp/withdraw-api/internal/shared/config"
-)
-
-func NewJSONLogger(cfg config.ConfigProvider) *slog.Logger {
-	level := parseLevel(cfg.GetString("logging.level"))
-
-	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: level,
-		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
-			if attr.Key == slog.TimeKey {
-				return slog.String(slog.TimeKey, attr.Value.Time().UTC().Format(time.RFC3339))
-			}
-			return attr
-		},
-	})
-
-	return slog.New(handler)
-}
-
-func parseLevel(level string) slog.Level {
-	switch strings.ToLower(strings.TrimSpace(level)) {
-	case "debug":
-		return slog.LevelDebug
-	case "warn", "warning":
-		return slog.LevelWarn
-	case "error":
-		return slog.LevelError
-	default:
-		return slog.LevelInfo
-	}
-}
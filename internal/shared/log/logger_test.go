@@ -0,0 +1,134 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	configmocks "github.com/joshuarp/withdraw-api/internal/mock/shared/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHandler_Format_TableDriven(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		expectErr string
+		assertion func(t *testing.T, handler slog.Handler, output string)
+	}{
+		{
+			name: "defaults to json",
+			assertion: func(t *testing.T, handler slog.Handler, output string) {
+				assert.IsType(t, &slog.JSONHandler{}, handler)
+				var entry map[string]any
+				require.NoError(t, json.Unmarshal([]byte(output), &entry))
+				assert.Equal(t, "hello", entry["msg"])
+				assert.True(t, strings.HasSuffix(entry["time"].(string), "Z"))
+			},
+		},
+		{
+			name:   "json",
+			format: " JSON ",
+			assertion: func(t *testing.T, handler slog.Handler, _ string) {
+				assert.IsType(t, &slog.JSONHandler{}, handler)
+			},
+		},
+		{
+			name:   "text keeps the utc time format",
+			format: "text",
+			assertion: func(t *testing.T, handler slog.Handler, output string) {
+				assert.IsType(t, &slog.TextHandler{}, handler)
+				assert.Contains(t, output, "msg=hello")
+				assert.Regexp(t, `^time=\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z `, output)
+			},
+		},
+		{name: "unknown format", format: "logfmt", expectErr: `unknown logging.format "logfmt"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			handler, err := newHandler(tc.format, &buf, slog.LevelInfo)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+
+			slog.New(handler).Info("hello")
+			tc.assertion(t, handler, buf.String())
+		})
+	}
+}
+
+func TestNewLogger_Output_TableDriven(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.log")
+	require.NoError(t, os.WriteFile(existing, []byte("previous line\n"), 0o600))
+
+	tests := []struct {
+		name      string
+		output    string
+		format    string
+		expectErr string
+		assertion func(t *testing.T)
+	}{
+		{name: "stdout by default"},
+		{name: "stderr", output: "stderr"},
+		{
+			name:   "file is created",
+			output: filepath.Join(dir, "app.log"),
+			assertion: func(t *testing.T) {
+				content, err := os.ReadFile(filepath.Join(dir, "app.log"))
+				require.NoError(t, err)
+				assert.Contains(t, string(content), `"msg":"hello"`)
+			},
+		},
+		{
+			name:   "file is appended to",
+			output: existing,
+			format: "text",
+			assertion: func(t *testing.T) {
+				content, err := os.ReadFile(existing)
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(string(content), "previous line\n"))
+				assert.Contains(t, string(content), "msg=hello")
+			},
+		},
+		{
+			name:      "unwritable path fails fast",
+			output:    filepath.Join(dir, "missing", "app.log"),
+			expectErr: "failed to open logging.output",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := configmocks.NewConfigProvider(t)
+			cfg.EXPECT().GetString("logging.output").Return(tc.output)
+			if tc.expectErr == "" {
+				cfg.EXPECT().GetString("logging.format").Return(tc.format)
+				cfg.EXPECT().GetString("logging.level").Return("info")
+			}
+
+			logger, err := NewLogger(cfg)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				assert.Nil(t, logger)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, logger)
+
+			if tc.assertion != nil {
+				logger.Info("hello")
+				tc.assertion(t)
+			}
+		})
+	}
+}
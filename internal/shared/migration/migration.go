@@ -0,0 +1,146 @@
+// Package migration applies goose-annotated SQL migrations.
+// It shares the goose_db_version table with the goose CLI used by the Makefile,
+// so both tools see the same applied versions.
+package migration
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Action selects what the runner does.
+type Action string
+
+const (
+	ActionUp     Action = "up"
+	ActionDown   Action = "down"
+	ActionStatus Action = "status"
+)
+
+// ParseAction validates a user-supplied action.
+func ParseAction(value string) (Action, error) {
+	switch Action(strings.TrimSpace(strings.ToLower(value))) {
+	case ActionUp:
+		return ActionUp, nil
+	case ActionDown:
+		return ActionDown, nil
+	case ActionStatus:
+		return ActionStatus, nil
+	default:
+		return "", fmt.Errorf("migration: unknown action %q (expected up|down|status)", value)
+	}
+}
+
+// Migration is a single versioned SQL file.
+type Migration struct {
+	// Version is the numeric prefix of the file name.
+	Version int64
+
+	// Name is the file name, used for logging.
+	Name string
+
+	// Up holds the statements under "-- +goose Up".
+	Up string
+
+	// Down holds the statements under "-- +goose Down".
+	Down string
+}
+
+// Status reports whether a migration has been applied.
+type Status struct {
+	Version int64
+	Name    string
+	Applied bool
+}
+
+// Load reads every *.sql file at the root of fsys, ordered by version.
+// Returns an error on duplicate versions or files without a version prefix.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migration: failed to read directory: %w", err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+	seen := make(map[int64]string, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		version, err := parseVersion(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if previous, exists := seen[version]; exists {
+			return nil, fmt.Errorf("migration: duplicate version %d in %q and %q", version, previous, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		raw, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migration: failed to read %q: %w", entry.Name(), err)
+		}
+
+		up, down := splitSections(string(raw))
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    entry.Name(),
+			Up:      up,
+			Down:    down,
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func parseVersion(name string) (int64, error) {
+	prefix, _, found := strings.Cut(name, "_")
+	if !found {
+		return 0, fmt.Errorf("migration: file %q has no version prefix", name)
+	}
+
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("migration: file %q has invalid version prefix", name)
+	}
+
+	return version, nil
+}
+
+func splitSections(raw string) (string, string) {
+	var up, down strings.Builder
+	var current *strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch strings.TrimSpace(line) {
+		case "-- +goose Up":
+			current = &up
+			continue
+		case "-- +goose Down":
+			current = &down
+			continue
+		case "-- +goose StatementBegin", "-- +goose StatementEnd":
+			continue
+		}
+
+		if current != nil {
+			current.WriteString(line)
+			current.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSpace(up.String()), strings.TrimSpace(down.String())
+}
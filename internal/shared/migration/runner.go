@@ -0,0 +1,158 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const versionTable = "goose_db_version"
+
+// Runner applies migrations against a single database.
+type Runner struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+// NewRunner loads the migrations in fsys for the given database.
+func NewRunner(db *sqlx.DB, fsys fs.FS) (*Runner, error) {
+	if db == nil {
+		return nil, errors.New("migration: db is required")
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Runner{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration in version order.
+// Each migration runs in its own transaction; already applied versions are skipped.
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	executed := make([]Migration, 0, len(r.migrations))
+	for _, migration := range r.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		const insertQuery = `INSERT INTO ` + versionTable + ` (version_id, is_applied) VALUES ($1, true)`
+		if err := r.apply(ctx, migration.Up, insertQuery, migration.Version); err != nil {
+			return executed, fmt.Errorf("migration: failed to apply %q: %w", migration.Name, err)
+		}
+		executed = append(executed, migration)
+	}
+
+	return executed, nil
+}
+
+// Down rolls back the most recently applied migration.
+// Returns false when nothing is applied.
+func (r *Runner) Down(ctx context.Context) (Migration, bool, error) {
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return Migration{}, false, err
+	}
+
+	for i := len(r.migrations) - 1; i >= 0; i-- {
+		migration := r.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		const deleteQuery = `DELETE FROM ` + versionTable + ` WHERE version_id = $1`
+		if err := r.apply(ctx, migration.Down, deleteQuery, migration.Version); err != nil {
+			return migration, false, fmt.Errorf("migration: failed to roll back %q: %w", migration.Name, err)
+		}
+		return migration, true, nil
+	}
+
+	return Migration{}, false, nil
+}
+
+// Status lists every known migration and whether it has been applied.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(r.migrations))
+	for _, migration := range r.migrations {
+		_, ok := applied[migration.Version]
+		statuses = append(statuses, Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: ok,
+		})
+	}
+
+	return statuses, nil
+}
+
+func (r *Runner) apply(ctx context.Context, statements, bookkeeping string, version int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if statements != "" {
+		if _, err := tx.ExecContext(ctx, statements); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, bookkeeping, version); err != nil {
+		return fmt.Errorf("failed to record version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *Runner) appliedVersions(ctx context.Context) (map[int64]struct{}, error) {
+	const createQuery = `
+CREATE TABLE IF NOT EXISTS ` + versionTable + ` (
+	id serial PRIMARY KEY,
+	version_id bigint NOT NULL,
+	is_applied boolean NOT NULL,
+	tstamp timestamp DEFAULT now()
+)`
+
+	if _, err := r.db.ExecContext(ctx, createQuery); err != nil {
+		return nil, fmt.Errorf("migration: failed to ensure version table: %w", err)
+	}
+
+	const selectQuery = `
+SELECT DISTINCT ON (version_id) version_id, is_applied
+FROM ` + versionTable + `
+ORDER BY version_id, id DESC`
+
+	type row struct {
+		VersionID int64 `db:"version_id"`
+		IsApplied bool  `db:"is_applied"`
+	}
+
+	var rows []row
+	if err := r.db.SelectContext(ctx, &rows, selectQuery); err != nil {
+		return nil, fmt.Errorf("migration: failed to read applied versions: %w", err)
+	}
+
+	applied := make(map[int64]struct{}, len(rows))
+	for _, current := range rows {
+		if current.IsApplied && current.VersionID > 0 {
+			applied[current.VersionID] = struct{}{}
+		}
+	}
+
+	return applied, nil
+}
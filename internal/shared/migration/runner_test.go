@@ -0,0 +1,255 @@
+package migration
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func migrationFile(up, down string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte("-- +goose Up\n" + up + "\n\n-- +goose Down\n" + down + "\n")}
+}
+
+// unorderedFS mixes padded and unpadded prefixes so that file name order
+// (10, 2, 000001) differs from version order (1, 2, 10).
+func unorderedFS() fstest.MapFS {
+	return fstest.MapFS{
+		"10_add_index.sql":       migrationFile("CREATE INDEX idx_c ON c (id);", "DROP INDEX idx_c;"),
+		"2_create_b.sql":         migrationFile("CREATE TABLE b (id int);", "DROP TABLE b;"),
+		"000001_create_a.sql":    migrationFile("CREATE TABLE a (id int);", "DROP TABLE a;"),
+		"README.md":              &fstest.MapFile{Data: []byte("not a migration")},
+		"nested/000003_skip.sql": migrationFile("CREATE TABLE skipped (id int);", "DROP TABLE skipped;"),
+	}
+}
+
+func newRunnerWithMock(t *testing.T, fsys fstest.MapFS) (*Runner, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	runner, err := NewRunner(sqlx.NewDb(sqlDB, "sqlmock"), fsys)
+	require.NoError(t, err)
+
+	return runner, mockDB
+}
+
+func expectAppliedVersions(mockDB sqlmock.Sqlmock, versions ...int64) {
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS goose_db_version").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version_id", "is_applied"})
+	for _, version := range versions {
+		rows.AddRow(version, true)
+	}
+	mockDB.ExpectQuery("SELECT DISTINCT ON \\(version_id\\)").WillReturnRows(rows)
+}
+
+func expectApply(mockDB sqlmock.Sqlmock, statement string, version int64) {
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(regexp.QuoteMeta(statement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectExec("INSERT INTO goose_db_version").WithArgs(version).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+}
+
+func versionsOf(migrations []Migration) []int64 {
+	versions := make([]int64, 0, len(migrations))
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+type MigrationSuite struct{ suite.Suite }
+
+func (s *MigrationSuite) TestLoad_TableDriven() {
+	tests := []struct {
+		name           string
+		fsys           fstest.MapFS
+		expectVersions []int64
+		expectErr      string
+	}{
+		{
+			name:           "orders by numeric version and ignores non-sql and nested files",
+			fsys:           unorderedFS(),
+			expectVersions: []int64{1, 2, 10},
+		},
+		{
+			name: "duplicate version is rejected",
+			fsys: fstest.MapFS{
+				"000001_a.sql": migrationFile("SELECT 1;", ""),
+				"1_b.sql":      migrationFile("SELECT 2;", ""),
+			},
+			expectErr: "duplicate version 1",
+		},
+		{
+			name:      "file without version prefix is rejected",
+			fsys:      fstest.MapFS{"init.sql": migrationFile("SELECT 1;", "")},
+			expectErr: "has no version prefix",
+		},
+		{
+			name:      "zero version is rejected",
+			fsys:      fstest.MapFS{"000000_init.sql": migrationFile("SELECT 1;", "")},
+			expectErr: "invalid version prefix",
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			migrations, err := Load(tc.fsys)
+			if tc.expectErr != "" {
+				require.Error(s.T(), err)
+				assert.Contains(s.T(), err.Error(), tc.expectErr)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectVersions, versionsOf(migrations))
+			assert.Equal(s.T(), "CREATE TABLE a (id int);", migrations[0].Up)
+			assert.Equal(s.T(), "DROP TABLE a;", migrations[0].Down)
+		})
+	}
+}
+
+func (s *MigrationSuite) TestRunnerUp_TableDriven() {
+	tests := []struct {
+		name           string
+		applied        []int64
+		setupMock      func(mockDB sqlmock.Sqlmock)
+		expectExecuted []int64
+	}{
+		{
+			name: "fresh database applies every migration in version order",
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				expectApply(mockDB, "CREATE TABLE a (id int);", 1)
+				expectApply(mockDB, "CREATE TABLE b (id int);", 2)
+				expectApply(mockDB, "CREATE INDEX idx_c ON c (id);", 10)
+			},
+			expectExecuted: []int64{1, 2, 10},
+		},
+		{
+			name:    "partially migrated database applies only pending versions",
+			applied: []int64{1},
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				expectApply(mockDB, "CREATE TABLE b (id int);", 2)
+				expectApply(mockDB, "CREATE INDEX idx_c ON c (id);", 10)
+			},
+			expectExecuted: []int64{2, 10},
+		},
+		{
+			name:           "re-run on up to date database is a no-op",
+			applied:        []int64{1, 2, 10},
+			setupMock:      func(sqlmock.Sqlmock) {},
+			expectExecuted: []int64{},
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			runner, mockDB := newRunnerWithMock(s.T(), unorderedFS())
+			expectAppliedVersions(mockDB, tc.applied...)
+			tc.setupMock(mockDB)
+
+			executed, err := runner.Up(context.Background())
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectExecuted, versionsOf(executed))
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *MigrationSuite) TestRunnerUp_StopsAtFailedMigration() {
+	runner, mockDB := newRunnerWithMock(s.T(), unorderedFS())
+	expectAppliedVersions(mockDB)
+	expectApply(mockDB, "CREATE TABLE a (id int);", 1)
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(regexp.QuoteMeta("CREATE TABLE b (id int);")).WillReturnError(assert.AnError)
+	mockDB.ExpectRollback()
+
+	executed, err := runner.Up(context.Background())
+	require.ErrorIs(s.T(), err, assert.AnError)
+	assert.Contains(s.T(), err.Error(), "2_create_b.sql")
+	assert.Equal(s.T(), []int64{1}, versionsOf(executed))
+	require.NoError(s.T(), mockDB.ExpectationsWereMet())
+}
+
+func (s *MigrationSuite) TestRunnerDown_TableDriven() {
+	tests := []struct {
+		name             string
+		applied          []int64
+		setupMock        func(mockDB sqlmock.Sqlmock)
+		expectRolledBack bool
+		expectVersion    int64
+	}{
+		{
+			name:    "rolls back the latest applied version",
+			applied: []int64{1, 2},
+			setupMock: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(regexp.QuoteMeta("DROP TABLE b;")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectExec("DELETE FROM goose_db_version").WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectCommit()
+			},
+			expectRolledBack: true,
+			expectVersion:    2,
+		},
+		{
+			name:             "nothing applied is a no-op",
+			setupMock:        func(sqlmock.Sqlmock) {},
+			expectRolledBack: false,
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			runner, mockDB := newRunnerWithMock(s.T(), unorderedFS())
+			expectAppliedVersions(mockDB, tc.applied...)
+			tc.setupMock(mockDB)
+
+			migration, rolledBack, err := runner.Down(context.Background())
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expectRolledBack, rolledBack)
+			assert.Equal(s.T(), tc.expectVersion, migration.Version)
+			require.NoError(s.T(), mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *MigrationSuite) TestParseAction_TableDriven() {
+	tests := []struct {
+		value     string
+		expect    Action
+		expectErr bool
+	}{
+		{value: "up", expect: ActionUp},
+		{value: " DOWN ", expect: ActionDown},
+		{value: "status", expect: ActionStatus},
+		{value: "redo", expectErr: true},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.value, func() {
+			action, err := ParseAction(tc.value)
+			if tc.expectErr {
+				require.Error(s.T(), err)
+				return
+			}
+
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), tc.expect, action)
+		})
+	}
+}
+
+func TestMigrationSuite(t *testing.T) {
+	suite.Run(t, new(MigrationSuite))
+}
@@ -0,0 +1,18 @@
+// Package negcache remembers keys known to have no record for a short time, so repeated
+// lookups of something missing can skip the database.
+package negcache
+
+import "context"
+
+// Cache is the interface consumers depend on for negative caching.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Contains reports whether key was recorded as missing and has not expired.
+	Contains(ctx context.Context, key string) (bool, error)
+
+	// Add records key as missing until the cache TTL elapses.
+	Add(ctx context.Context, key string) error
+
+	// Remove forgets key, e.g. once the record has been created.
+	Remove(ctx context.Context, key string) error
+}
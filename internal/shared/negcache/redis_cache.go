@@ -0,0 +1,70 @@
+package negcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ Cache = (*RedisCache)(nil)
+
+// RedisCache is a negative cache shared by every instance using the same Redis, so a
+// record created by one process clears the entry for all of them.
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisCache creates a Redis-backed negative cache whose entries expire after ttl.
+func NewRedisCache(client *redis.Client, prefix string, ttl time.Duration) *RedisCache {
+	if prefix == "" {
+		prefix = "negcache"
+	}
+
+	return &RedisCache{client: client, prefix: prefix, ttl: ttl}
+}
+
+func (c *RedisCache) Contains(ctx context.Context, key string) (bool, error) {
+	if c == nil || c.client == nil {
+		return false, errors.New("negcache: redis cache is not initialized")
+	}
+
+	count, err := c.client.Exists(ctx, c.key(key)).Result()
+	if err != nil {
+		return false, fmt.Errorf("negcache: failed to read entry: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (c *RedisCache) Add(ctx context.Context, key string) error {
+	if c == nil || c.client == nil {
+		return errors.New("negcache: redis cache is not initialized")
+	}
+
+	if err := c.client.Set(ctx, c.key(key), 1, c.ttl).Err(); err != nil {
+		return fmt.Errorf("negcache: failed to write entry: %w", err)
+	}
+
+	return nil
+}
+
+func (c *RedisCache) Remove(ctx context.Context, key string) error {
+	if c == nil || c.client == nil {
+		return errors.New("negcache: redis cache is not initialized")
+	}
+
+	if err := c.client.Del(ctx, c.key(key)).Err(); err != nil {
+		return fmt.Errorf("negcache: failed to delete entry: %w", err)
+	}
+
+	return nil
+}
+
+func (c *RedisCache) key(key string) string {
+	return c.prefix + ":" + key
+}
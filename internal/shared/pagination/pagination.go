@@ -0,0 +1,62 @@
+// Package pagination encodes keyset cursors for listings ordered by (created_at, id).
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+var ErrInvalidCursor = errors.New("pagination: invalid cursor")
+
+// Cursor is the position of the last row of a page. The next page continues strictly
+// after it in the listing order.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a token produced by Cursor.Encode.
+func DecodeCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	rawTime, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, rawTime)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// PageSize returns requested bounded to [1, MaxPageSize], or DefaultPageSize when the
+// client did not ask for a size.
+func PageSize(requested int) int {
+	switch {
+	case requested <= 0:
+		return DefaultPageSize
+	case requested > MaxPageSize:
+		return MaxPageSize
+	default:
+		return requested
+	}
+}
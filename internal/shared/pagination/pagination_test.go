@@ -0,0 +1,72 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaginationSuite struct{ suite.Suite }
+
+func (s *PaginationSuite) TestCursor_RoundTrip() {
+	cursor := Cursor{
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 123456000, time.FixedZone("WIB", 7*60*60)),
+		ID:        "0190c0de-0000-7000-8000-000000000001",
+	}
+
+	token := cursor.Encode()
+	assert.NotContains(s.T(), token, "|")
+	assert.NotContains(s.T(), token, "=")
+
+	decoded, err := DecodeCursor(token)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(s.T(), cursor.ID, decoded.ID)
+}
+
+func (s *PaginationSuite) TestDecodeCursor_Invalid_TableDriven() {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "not base64", token: "!!!"},
+		{name: "missing separator", token: encode("2026-01-02T03:04:05Z")},
+		{name: "missing id", token: encode("2026-01-02T03:04:05Z|")},
+		{name: "bad timestamp", token: encode("yesterday|id-1")},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			_, err := DecodeCursor(tc.token)
+			assert.ErrorIs(s.T(), err, ErrInvalidCursor)
+		})
+	}
+}
+
+func (s *PaginationSuite) TestPageSize_TableDriven() {
+	tests := []struct {
+		requested int
+		expected  int
+	}{
+		{requested: 0, expected: DefaultPageSize},
+		{requested: -5, expected: DefaultPageSize},
+		{requested: 1, expected: 1},
+		{requested: MaxPageSize, expected: MaxPageSize},
+		{requested: MaxPageSize + 1, expected: MaxPageSize},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(s.T(), tc.expected, PageSize(tc.requested), "requested %d", tc.requested)
+	}
+}
+
+func TestPaginationSuite(t *testing.T) {
+	suite.Run(t, new(PaginationSuite))
+}
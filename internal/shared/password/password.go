@@ -0,0 +1,114 @@
+// Package password checks new passwords against a strength policy. It is meant for
+// flows that set a password (registration, password change); login must keep accepting
+// whatever password the account already has.
+package password
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+const DefaultMinLength = 12
+
+// Criteria reported by Policy.Unmet. They are stable strings so clients can map them to
+// their own wording.
+const (
+	CriterionMinLength = "min_length"
+	CriterionUpper     = "uppercase"
+	CriterionLower     = "lowercase"
+	CriterionDigit     = "digit"
+	CriterionSymbol    = "symbol"
+	CriterionCommon    = "not_common"
+)
+
+// Policy describes what a new password must satisfy. Denylist holds lower-cased
+// passwords that are rejected regardless of the other criteria.
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	Denylist      map[string]struct{}
+}
+
+// DefaultPolicy requires DefaultMinLength characters from all four character classes and
+// has an empty denylist.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:     DefaultMinLength,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+}
+
+// Unmet returns the criteria password fails, in a fixed order, or nil when it satisfies
+// the policy. Length is counted in characters, not bytes.
+func (p Policy) Unmet(password string) []string {
+	var unmet []string
+	if len([]rune(password)) < p.MinLength {
+		unmet = append(unmet, CriterionMinLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		unmet = append(unmet, CriterionUpper)
+	}
+	if p.RequireLower && !hasLower {
+		unmet = append(unmet, CriterionLower)
+	}
+	if p.RequireDigit && !hasDigit {
+		unmet = append(unmet, CriterionDigit)
+	}
+	if p.RequireSymbol && !hasSymbol {
+		unmet = append(unmet, CriterionSymbol)
+	}
+	if _, denied := p.Denylist[strings.ToLower(password)]; denied {
+		unmet = append(unmet, CriterionCommon)
+	}
+
+	return unmet
+}
+
+// LoadDenylist reads one password per line from path. Blank lines and lines starting
+// with '#' are skipped; entries are lower-cased so matching ignores case.
+func LoadDenylist(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("password: failed to open denylist: %w", err)
+	}
+	defer file.Close()
+
+	denylist := map[string]struct{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denylist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("password: failed to read denylist: %w", err)
+	}
+
+	return denylist, nil
+}
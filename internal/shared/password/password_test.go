@@ -0,0 +1,94 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordSuite struct{ suite.Suite }
+
+func (s *PasswordSuite) TestUnmet_TableDriven() {
+	policy := DefaultPolicy()
+	policy.Denylist = map[string]struct{}{"correcthorse1!": {}}
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		password string
+		expected []string
+	}{
+		{
+			name:     "acceptable password",
+			policy:   policy,
+			password: "Tr0ub4dor&3-Horse",
+		},
+		{
+			name:     "too short",
+			policy:   policy,
+			password: "Ab1!",
+			expected: []string{CriterionMinLength},
+		},
+		{
+			name:     "length counts characters not bytes",
+			policy:   Policy{MinLength: 4},
+			password: "ñññ",
+			expected: []string{CriterionMinLength},
+		},
+		{
+			name:     "missing uppercase and symbol",
+			policy:   policy,
+			password: "lowercase12345",
+			expected: []string{CriterionUpper, CriterionSymbol},
+		},
+		{
+			name:     "missing every class",
+			policy:   policy,
+			password: "",
+			expected: []string{CriterionMinLength, CriterionUpper, CriterionLower, CriterionDigit, CriterionSymbol},
+		},
+		{
+			name:     "denylisted regardless of case",
+			policy:   policy,
+			password: "CorrectHorse1!",
+			expected: []string{CriterionCommon},
+		},
+		{
+			name:     "denylisted password meeting every other criterion",
+			policy:   Policy{MinLength: 8, RequireUpper: true, RequireDigit: true, Denylist: map[string]struct{}{"password1": {}}},
+			password: "Password1",
+			expected: []string{CriterionCommon},
+		},
+		{
+			name:     "disabled classes are not required",
+			policy:   Policy{MinLength: 8},
+			password: "alllowercase",
+		},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			assert.Equal(s.T(), tc.expected, tc.policy.Unmet(tc.password))
+		})
+	}
+}
+
+func (s *PasswordSuite) TestLoadDenylist() {
+	path := filepath.Join(s.T().TempDir(), "denylist.txt")
+	require.NoError(s.T(), os.WriteFile(path, []byte("# common passwords\nPassword1\n\n  qwerty123  \n"), 0o600))
+
+	denylist, err := LoadDenylist(path)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), map[string]struct{}{"password1": {}, "qwerty123": {}}, denylist)
+
+	_, err = LoadDenylist(filepath.Join(s.T().TempDir(), "missing.txt"))
+	assert.Error(s.T(), err)
+}
+
+func TestPasswordSuite(t *testing.T) {
+	suite.Run(t, new(PasswordSuite))
+}
@@ -0,0 +1,225 @@
+package payout
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailureRatio = 0.5
+	defaultBreakerMinRequests  = 10
+	defaultBreakerWindow       = time.Minute
+	defaultBreakerOpenTimeout  = 30 * time.Second
+)
+
+// ErrCircuitOpen is wrapped in the retryable ProviderError returned while the breaker is
+// open, so callers treat it like any other temporary provider outage.
+var ErrCircuitOpen = errors.New("payout: circuit breaker is open")
+
+// Client is the payout call guarded by CircuitBreaker; HTTPClient implements it.
+type Client interface {
+	Payout(ctx context.Context, req Request) (Result, error)
+	PayoutStatus(ctx context.Context, referenceID string) (Result, error)
+}
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerHalfOpen
+	BreakerOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half_open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig controls when the breaker trips. It opens once at least MinRequests calls
+// in the current Window failed at FailureRatio or more, and stays open for OpenTimeout
+// before letting a single probe through. OnStateChange, if set, is called after every
+// transition and must not call back into the breaker.
+type BreakerConfig struct {
+	FailureRatio  float64
+	MinRequests   int
+	Window        time.Duration
+	OpenTimeout   time.Duration
+	OnStateChange func(from, to BreakerState)
+}
+
+// CircuitBreaker stops calling the provider while it is failing, so withdrawals fail fast
+// instead of each waiting out the client's timeouts and retries. Only retryable errors
+// count as failures: a rejected payout still proves the provider is up.
+type CircuitBreaker struct {
+	client Client
+	cfg    BreakerConfig
+	now    func() time.Time
+
+	mu          sync.Mutex
+	state       BreakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+}
+
+func NewCircuitBreaker(client Client, cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureRatio <= 0 || cfg.FailureRatio > 1 {
+		cfg.FailureRatio = defaultBreakerFailureRatio
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = defaultBreakerMinRequests
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultBreakerWindow
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = defaultBreakerOpenTimeout
+	}
+
+	return &CircuitBreaker{client: client, cfg: cfg, now: time.Now}
+}
+
+// State reports the current state, moving an expired open breaker to half-open first.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.expireOpen(b.now())
+	return b.state
+}
+
+// Allow reports whether a payout would currently reach the provider, without taking the
+// half-open probe slot. Callers use it to refuse a withdrawal before debiting the wallet;
+// Payout still makes the final decision.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.expireOpen(b.now())
+	switch b.state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		return !b.probing
+	default:
+		return true
+	}
+}
+
+func (b *CircuitBreaker) Payout(ctx context.Context, req Request) (Result, error) {
+	if !b.allow() {
+		return Result{}, &ProviderError{Retryable: true, Err: ErrCircuitOpen}
+	}
+
+	result, err := b.client.Payout(ctx, req)
+	// A call abandoned by its caller says nothing about the provider's health.
+	if err != nil && ctx.Err() != nil {
+		b.release()
+		return result, err
+	}
+
+	b.record(IsRetryable(err))
+	return result, err
+}
+
+// PayoutStatus is passed straight through: a lookup moves no money, and reconciling
+// pending payouts is how the provider's recovery becomes visible.
+func (b *CircuitBreaker) PayoutStatus(ctx context.Context, referenceID string) (Result, error) {
+	return b.client.PayoutStatus(ctx, referenceID)
+}
+
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.expireOpen(now)
+
+	switch b.state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		if now.Sub(b.windowStart) >= b.cfg.Window {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+		return true
+	}
+}
+
+func (b *CircuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+		if failed {
+			b.open(b.now())
+		} else {
+			b.transition(BreakerClosed)
+			b.windowStart, b.requests, b.failures = b.now(), 0, 0
+		}
+		return
+	}
+
+	if b.state != BreakerClosed {
+		return
+	}
+
+	b.requests++
+	if failed {
+		b.failures++
+	}
+	if b.requests >= b.cfg.MinRequests && float64(b.failures)/float64(b.requests) >= b.cfg.FailureRatio {
+		b.open(b.now())
+	}
+}
+
+// release frees the half-open probe slot without judging the provider.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+}
+
+func (b *CircuitBreaker) expireOpen(now time.Time) {
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.transition(BreakerHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) open(now time.Time) {
+	b.openedAt = now
+	b.transition(BreakerOpen)
+}
+
+func (b *CircuitBreaker) transition(to BreakerState) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, to)
+	}
+}
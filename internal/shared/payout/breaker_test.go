@@ -0,0 +1,230 @@
+package payout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	errProviderDown     = &ProviderError{StatusCode: 503, Retryable: true}
+	errProviderRejected = &ProviderError{StatusCode: 422}
+)
+
+// stubClient returns err for every call and counts how many reached it.
+type stubClient struct {
+	err   error
+	calls int
+	block chan struct{}
+}
+
+func (c *stubClient) Payout(ctx context.Context, _ Request) (Result, error) {
+	c.calls++
+	if c.block != nil {
+		select {
+		case <-c.block:
+		case <-ctx.Done():
+			return Result{}, &ProviderError{Retryable: true, Err: ctx.Err()}
+		}
+	}
+	if c.err != nil {
+		return Result{}, c.err
+	}
+	return Result{ProviderReference: "prov-1", Status: "accepted"}, nil
+}
+
+func (c *stubClient) PayoutStatus(context.Context, string) (Result, error) {
+	return Result{ProviderReference: "prov-1", Status: "accepted"}, nil
+}
+
+type CircuitBreakerSuite struct {
+	suite.Suite
+
+	client      *stubClient
+	clock       time.Time
+	transitions []string
+	breaker     *CircuitBreaker
+}
+
+func (s *CircuitBreakerSuite) SetupTest() {
+	s.client = &stubClient{}
+	s.clock = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.transitions = nil
+	s.breaker = NewCircuitBreaker(s.client, BreakerConfig{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		Window:       time.Minute,
+		OpenTimeout:  10 * time.Second,
+		OnStateChange: func(from, to BreakerState) {
+			s.transitions = append(s.transitions, from.String()+"->"+to.String())
+		},
+	})
+	s.breaker.now = func() time.Time { return s.clock }
+}
+
+func (s *CircuitBreakerSuite) call() error {
+	_, err := s.breaker.Payout(context.Background(), Request{ReferenceID: "ref-1"})
+	return err
+}
+
+func (s *CircuitBreakerSuite) trip() {
+	s.client.err = errProviderDown
+	for range 4 {
+		require.ErrorIs(s.T(), s.call(), errProviderDown)
+	}
+	require.Equal(s.T(), BreakerOpen, s.breaker.State())
+}
+
+func (s *CircuitBreakerSuite) TestClosed_TripsOnlyAtFailureRatioAfterMinRequests() {
+	s.client.err = errProviderDown
+	for range 3 {
+		require.Error(s.T(), s.call())
+	}
+	assert.Equal(s.T(), BreakerClosed, s.breaker.State(), "below MinRequests")
+
+	s.client.err = nil
+	require.NoError(s.T(), s.call())
+	assert.Equal(s.T(), BreakerOpen, s.breaker.State(), "3 of 4 failed")
+	assert.Equal(s.T(), []string{"closed->open"}, s.transitions)
+}
+
+func (s *CircuitBreakerSuite) TestClosed_RejectionsAndOldFailuresDoNotTrip() {
+	s.client.err = errProviderRejected
+	for range 4 {
+		require.ErrorIs(s.T(), s.call(), errProviderRejected)
+	}
+	assert.Equal(s.T(), BreakerClosed, s.breaker.State())
+
+	// Failures from an expired window are forgotten.
+	s.clock = s.clock.Add(time.Minute)
+	s.client.err = errProviderDown
+	for range 3 {
+		require.Error(s.T(), s.call())
+	}
+	s.clock = s.clock.Add(time.Minute)
+	require.Error(s.T(), s.call())
+	assert.Equal(s.T(), BreakerClosed, s.breaker.State())
+}
+
+func (s *CircuitBreakerSuite) TestOpen_ShortCircuitsWithRetryableError() {
+	s.trip()
+	calls := s.client.calls
+
+	err := s.call()
+	require.ErrorIs(s.T(), err, ErrCircuitOpen)
+	assert.True(s.T(), IsRetryable(err))
+	assert.Equal(s.T(), calls, s.client.calls, "provider must not be called while open")
+}
+
+func (s *CircuitBreakerSuite) TestAllow_FollowsStateWithoutTakingProbe() {
+	assert.True(s.T(), s.breaker.Allow())
+
+	s.trip()
+	assert.False(s.T(), s.breaker.Allow())
+
+	s.clock = s.clock.Add(10 * time.Second)
+	assert.True(s.T(), s.breaker.Allow())
+	assert.True(s.T(), s.breaker.Allow(), "Allow must not take the probe slot")
+
+	s.client.err = nil
+	s.client.block = make(chan struct{})
+	probeDone := make(chan error)
+	go func() {
+		_, err := s.breaker.Payout(context.Background(), Request{ReferenceID: "probe"})
+		probeDone <- err
+	}()
+
+	require.Eventually(s.T(), func() bool { return !s.breaker.Allow() }, time.Second, time.Millisecond)
+
+	close(s.client.block)
+	require.NoError(s.T(), <-probeDone)
+	assert.True(s.T(), s.breaker.Allow())
+}
+
+func (s *CircuitBreakerSuite) TestHalfOpen_SuccessfulProbeCloses() {
+	s.trip()
+	s.clock = s.clock.Add(10 * time.Second)
+	assert.Equal(s.T(), BreakerHalfOpen, s.breaker.State())
+
+	s.client.err = nil
+	require.NoError(s.T(), s.call())
+	assert.Equal(s.T(), BreakerClosed, s.breaker.State())
+	assert.Equal(s.T(), []string{"closed->open", "open->half_open", "half_open->closed"}, s.transitions)
+
+	// A fresh window: one failure right after closing does not reopen.
+	s.client.err = errProviderDown
+	require.Error(s.T(), s.call())
+	assert.Equal(s.T(), BreakerClosed, s.breaker.State())
+}
+
+func (s *CircuitBreakerSuite) TestHalfOpen_FailedProbeReopens() {
+	s.trip()
+	s.clock = s.clock.Add(10 * time.Second)
+
+	require.ErrorIs(s.T(), s.call(), errProviderDown)
+	assert.Equal(s.T(), BreakerOpen, s.breaker.State())
+	require.ErrorIs(s.T(), s.call(), ErrCircuitOpen)
+
+	s.clock = s.clock.Add(9 * time.Second)
+	assert.Equal(s.T(), BreakerOpen, s.breaker.State(), "open timeout restarts from the failed probe")
+	assert.Equal(s.T(), []string{"closed->open", "open->half_open", "half_open->open"}, s.transitions)
+}
+
+func (s *CircuitBreakerSuite) TestHalfOpen_AllowsSingleProbe() {
+	s.trip()
+	s.clock = s.clock.Add(10 * time.Second)
+	s.client.err = nil
+	s.client.block = make(chan struct{})
+
+	probeDone := make(chan error)
+	go func() {
+		_, err := s.breaker.Payout(context.Background(), Request{ReferenceID: "probe"})
+		probeDone <- err
+	}()
+
+	require.Eventually(s.T(), func() bool {
+		s.breaker.mu.Lock()
+		defer s.breaker.mu.Unlock()
+		return s.breaker.probing
+	}, time.Second, time.Millisecond)
+
+	_, err := s.breaker.Payout(context.Background(), Request{ReferenceID: "second"})
+	require.ErrorIs(s.T(), err, ErrCircuitOpen)
+
+	close(s.client.block)
+	require.NoError(s.T(), <-probeDone)
+	assert.Equal(s.T(), BreakerClosed, s.breaker.State())
+}
+
+func (s *CircuitBreakerSuite) TestHalfOpen_CancelledProbeFreesSlot() {
+	s.trip()
+	s.clock = s.clock.Add(10 * time.Second)
+	s.client.block = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s.breaker.Payout(ctx, Request{ReferenceID: "cancelled"})
+	require.ErrorIs(s.T(), err, context.Canceled)
+	assert.Equal(s.T(), BreakerHalfOpen, s.breaker.State())
+
+	s.client.block = nil
+	s.client.err = nil
+	require.NoError(s.T(), s.call())
+	assert.Equal(s.T(), BreakerClosed, s.breaker.State())
+}
+
+func (s *CircuitBreakerSuite) TestStateString() {
+	assert.Equal(s.T(), "closed", BreakerClosed.String())
+	assert.Equal(s.T(), "half_open", BreakerHalfOpen.String())
+	assert.Equal(s.T(), "open", BreakerOpen.String())
+	assert.True(s.T(), errors.Is(&ProviderError{Retryable: true, Err: ErrCircuitOpen}, ErrCircuitOpen))
+}
+
+func TestCircuitBreakerSuite(t *testing.T) {
+	suite.Run(t, new(CircuitBreakerSuite))
+}
@@ -0,0 +1,274 @@
+package payout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultRetryBackoff = 200 * time.Millisecond
+	maxResponseBytes    = 1 << 20
+	payoutsPath         = "/payouts"
+)
+
+type Config struct {
+	BaseURL string
+	Secret  string
+	// Timeout bounds each attempt, not the whole call including retries.
+	Timeout      time.Duration
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// RatePerSecond caps outbound requests across all callers; 0 disables the cap.
+	RatePerSecond int
+	HTTPClient    *http.Client
+}
+
+// HTTPClient calls the payout provider over HTTP, signing each body and retrying
+// retryable failures with exponential backoff.
+type HTTPClient struct {
+	endpoint     string
+	secret       []byte
+	client       *http.Client
+	timeout      time.Duration
+	maxRetries   int
+	retryBackoff time.Duration
+	throttle     *throttle
+	now          func() time.Time
+}
+
+func NewHTTPClient(cfg Config) (*HTTPClient, error) {
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("payout: base URL must not be empty")
+	}
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("payout: signing secret must not be empty")
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	retryBackoff := cfg.RetryBackoff
+	if retryBackoff <= 0 {
+		retryBackoff = defaultRetryBackoff
+	}
+
+	var limiter *throttle
+	if cfg.RatePerSecond > 0 {
+		limiter = &throttle{interval: time.Second / time.Duration(cfg.RatePerSecond)}
+	}
+
+	return &HTTPClient{
+		endpoint:     baseURL + payoutsPath,
+		secret:       []byte(cfg.Secret),
+		client:       client,
+		timeout:      timeout,
+		maxRetries:   max(cfg.MaxRetries, 0),
+		retryBackoff: retryBackoff,
+		throttle:     limiter,
+		now:          time.Now,
+	}, nil
+}
+
+func (c *HTTPClient) Payout(ctx context.Context, req Request) (Result, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return Result{}, &ProviderError{Err: fmt.Errorf("failed to encode request: %w", err)}
+	}
+
+	backoff := c.retryBackoff
+	ambiguous := false
+	for attempt := 0; ; attempt++ {
+		result, err := c.send(ctx, req.ReferenceID, body)
+		// Once an attempt may have been accepted, a later definite failure (e.g. a 429)
+		// no longer proves the payout was not taken.
+		var providerErr *ProviderError
+		if errors.As(err, &providerErr) {
+			ambiguous = ambiguous || providerErr.Ambiguous
+			providerErr.Ambiguous = ambiguous
+		}
+		if err == nil || !IsRetryable(err) || attempt >= c.maxRetries {
+			return result, err
+		}
+
+		if waitErr := sleep(ctx, backoff); waitErr != nil {
+			return Result{}, err
+		}
+		backoff *= 2
+	}
+}
+
+// PayoutStatus asks the provider for the payout sent under referenceID, the idempotency
+// key of the original request. It returns ErrPayoutNotFound when the provider never
+// received it.
+func (c *HTTPClient) PayoutStatus(ctx context.Context, referenceID string) (Result, error) {
+	if err := c.throttle.wait(ctx); err != nil {
+		return Result{}, &ProviderError{Retryable: true, Err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/"+url.PathEscape(referenceID), nil)
+	if err != nil {
+		return Result{}, &ProviderError{Err: fmt.Errorf("failed to build request: %w", err)}
+	}
+
+	timestamp := strconv.FormatInt(c.now().Unix(), 10)
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set(IdempotencyKeyHeader, referenceID)
+	httpReq.Header.Set(TimestampHeader, timestamp)
+	httpReq.Header.Set(SignatureHeader, Sign(c.secret, timestamp, nil))
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return Result{}, &ProviderError{Retryable: true, Err: err}
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return Result{}, &ProviderError{StatusCode: resp.StatusCode, Retryable: true, Err: err}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return Result{}, ErrPayoutNotFound
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		var result Result
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return Result{}, &ProviderError{StatusCode: resp.StatusCode, Retryable: true, Err: fmt.Errorf("failed to decode status: %w", err)}
+		}
+		return result, nil
+	default:
+		return Result{}, &ProviderError{
+			StatusCode: resp.StatusCode,
+			Message:    providerMessage(raw),
+			Retryable:  resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500,
+		}
+	}
+}
+
+func (c *HTTPClient) send(ctx context.Context, referenceID string, body []byte) (Result, error) {
+	if err := c.throttle.wait(ctx); err != nil {
+		return Result{}, &ProviderError{Retryable: true, Err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, &ProviderError{Err: fmt.Errorf("failed to build request: %w", err)}
+	}
+
+	timestamp := strconv.FormatInt(c.now().Unix(), 10)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set(IdempotencyKeyHeader, referenceID)
+	httpReq.Header.Set(TimestampHeader, timestamp)
+	httpReq.Header.Set(SignatureHeader, Sign(c.secret, timestamp, body))
+
+	// From here on the request may have reached the provider: a transport error or
+	// timeout does not say whether it was accepted.
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return Result{}, &ProviderError{Retryable: true, Ambiguous: true, Err: err}
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return Result{}, &ProviderError{StatusCode: resp.StatusCode, Retryable: true, Ambiguous: true, Err: err}
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		// The provider accepted the payout; an unreadable body must not be treated as a
+		// failure, or the caller would reverse a debit for money that already moved.
+		var result Result
+		_ = json.Unmarshal(raw, &result)
+		return result, nil
+	}
+
+	// A 5xx or 408 may come from a provider that failed after storing the payout; a 429
+	// or any other 4xx is a refusal to process it.
+	ambiguous := resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= 500
+	return Result{}, &ProviderError{
+		StatusCode: resp.StatusCode,
+		Message:    providerMessage(raw),
+		Retryable:  ambiguous || resp.StatusCode == http.StatusTooManyRequests,
+		Ambiguous:  ambiguous,
+	}
+}
+
+func providerMessage(raw []byte) string {
+	var payload struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return ""
+	}
+	if payload.Message != "" {
+		return payload.Message
+	}
+	return payload.Error
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// throttle spaces outbound requests at least interval apart so a burst of withdrawals
+// cannot exceed the provider's published rate limit.
+type throttle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func (t *throttle) wait(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	slot := t.next
+	if slot.Before(now) {
+		slot = now
+	}
+	t.next = slot.Add(t.interval)
+	t.mu.Unlock()
+
+	if delay := slot.Sub(now); delay > 0 {
+		return sleep(ctx, delay)
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package payout
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSecret = "payout-secret"
+
+// fakeProvider replies with the queued statuses in order, repeating the last one.
+type fakeProvider struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	delay    time.Duration
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newFakeProvider(t *testing.T, statuses ...int) *fakeProvider {
+	t.Helper()
+
+	fake := &fakeProvider{statuses: statuses}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, r)
+		fake.bodies = append(fake.bodies, body)
+		status := fake.statuses[min(len(fake.requests), len(fake.statuses))-1]
+		delay := fake.delay
+		fake.mu.Unlock()
+
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status < 300 {
+			_ = json.NewEncoder(w).Encode(map[string]string{"provider_reference": "po-1", "status": "accepted"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": http.StatusText(status)})
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func (f *fakeProvider) requestCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+type HTTPClientSuite struct {
+	suite.Suite
+}
+
+func (s *HTTPClientSuite) newClient(baseURL string, cfg Config) *HTTPClient {
+	cfg.BaseURL = baseURL
+	cfg.Secret = testSecret
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = time.Millisecond
+	}
+
+	client, err := NewHTTPClient(cfg)
+	require.NoError(s.T(), err)
+	return client
+}
+
+func (s *HTTPClientSuite) TestNewHTTPClient_TableDriven() {
+	tests := []struct {
+		name      string
+		cfg       Config
+		expectErr string
+	}{
+		{name: "requires base url", cfg: Config{Secret: testSecret}, expectErr: "base URL must not be empty"},
+		{name: "requires secret", cfg: Config{BaseURL: "http://provider"}, expectErr: "signing secret must not be empty"},
+		{name: "valid", cfg: Config{BaseURL: "http://provider/", Secret: testSecret}},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			client, err := NewHTTPClient(tc.cfg)
+			if tc.expectErr != "" {
+				assert.ErrorContains(s.T(), err, tc.expectErr)
+				return
+			}
+			require.NoError(s.T(), err)
+			assert.Equal(s.T(), "http://provider/payouts", client.endpoint)
+		})
+	}
+}
+
+func (s *HTTPClientSuite) TestPayout_SignsRequest() {
+	provider := newFakeProvider(s.T(), http.StatusAccepted)
+	client := s.newClient(provider.URL, Config{})
+	client.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	result, err := client.Payout(context.Background(), Request{ReferenceID: "ref-1", UserID: "user-1", AmountMinor: 100, Currency: "IDR"})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), Result{ProviderReference: "po-1", Status: "accepted"}, result)
+
+	require.Equal(s.T(), 1, provider.requestCount())
+	req, body := provider.requests[0], provider.bodies[0]
+	assert.Equal(s.T(), "/payouts", req.URL.Path)
+	assert.Equal(s.T(), "ref-1", req.Header.Get(IdempotencyKeyHeader))
+	assert.Equal(s.T(), "1700000000", req.Header.Get(TimestampHeader))
+	assert.Equal(s.T(), Sign([]byte(testSecret), "1700000000", body), req.Header.Get(SignatureHeader))
+	assert.JSONEq(s.T(), `{"reference_id":"ref-1","user_id":"user-1","amount_minor":100,"currency":"IDR"}`, string(body))
+}
+
+func (s *HTTPClientSuite) TestPayout_Retries_TableDriven() {
+	tests := []struct {
+		name           string
+		statuses       []int
+		maxRetries     int
+		expectErr      bool
+		expectRetry    bool
+		expectRequests int
+	}{
+		{name: "succeeds after transient failure", statuses: []int{http.StatusServiceUnavailable, http.StatusOK}, maxRetries: 2, expectRequests: 2},
+		{name: "retries rate limited response", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, maxRetries: 1, expectRequests: 2},
+		{name: "gives up after max retries", statuses: []int{http.StatusInternalServerError}, maxRetries: 2, expectErr: true, expectRetry: true, expectRequests: 3},
+		{name: "permanent failure is not retried", statuses: []int{http.StatusUnprocessableEntity}, maxRetries: 3, expectErr: true, expectRequests: 1},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			provider := newFakeProvider(s.T(), tc.statuses...)
+			client := s.newClient(provider.URL, Config{MaxRetries: tc.maxRetries})
+
+			_, err := client.Payout(context.Background(), Request{ReferenceID: "ref-1", AmountMinor: 100})
+			assert.Equal(s.T(), tc.expectRequests, provider.requestCount())
+			if !tc.expectErr {
+				require.NoError(s.T(), err)
+				return
+			}
+
+			var providerErr *ProviderError
+			require.ErrorAs(s.T(), err, &providerErr)
+			assert.Equal(s.T(), tc.statuses[len(tc.statuses)-1], providerErr.StatusCode)
+			assert.Equal(s.T(), tc.expectRetry, IsRetryable(err))
+			assert.ErrorContains(s.T(), err, http.StatusText(providerErr.StatusCode))
+		})
+	}
+}
+
+func (s *HTTPClientSuite) TestPayout_AttemptTimeoutIsRetryable() {
+	provider := newFakeProvider(s.T(), http.StatusOK)
+	provider.delay = 200 * time.Millisecond
+	client := s.newClient(provider.URL, Config{Timeout: 20 * time.Millisecond, MaxRetries: 1})
+
+	_, err := client.Payout(context.Background(), Request{ReferenceID: "ref-1", AmountMinor: 100})
+	require.Error(s.T(), err)
+	assert.True(s.T(), IsRetryable(err))
+	assert.ErrorIs(s.T(), err, context.DeadlineExceeded)
+	assert.Equal(s.T(), 2, provider.requestCount())
+}
+
+func (s *HTTPClientSuite) TestPayout_NotAccepted_TableDriven() {
+	tests := []struct {
+		name              string
+		statuses          []int
+		maxRetries        int
+		timeout           time.Duration
+		delay             time.Duration
+		expectNotAccepted bool
+	}{
+		{name: "rejection is definite", statuses: []int{http.StatusUnprocessableEntity}, expectNotAccepted: true},
+		{name: "rate limit is definite", statuses: []int{http.StatusTooManyRequests}, expectNotAccepted: true},
+		{name: "server error may hide an accepted payout", statuses: []int{http.StatusBadGateway}},
+		{name: "timeout may hide an accepted payout", statuses: []int{http.StatusOK}, timeout: 20 * time.Millisecond, delay: 200 * time.Millisecond},
+		{name: "definite failure after an unclear attempt stays unclear", statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}, maxRetries: 1},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			provider := newFakeProvider(s.T(), tc.statuses...)
+			provider.delay = tc.delay
+			client := s.newClient(provider.URL, Config{MaxRetries: tc.maxRetries, Timeout: tc.timeout})
+
+			_, err := client.Payout(context.Background(), Request{ReferenceID: "ref-1", AmountMinor: 100})
+			require.Error(s.T(), err)
+			assert.Equal(s.T(), tc.expectNotAccepted, NotAccepted(err))
+		})
+	}
+}
+
+func (s *HTTPClientSuite) TestPayoutStatus_TableDriven() {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		expectResult Result
+		expectErr    error
+		expectRetry  bool
+	}{
+		{name: "accepted payout", status: http.StatusOK, body: `{"provider_reference":"po-1","status":"accepted"}`, expectResult: Result{ProviderReference: "po-1", Status: "accepted"}},
+		{name: "rejected payout", status: http.StatusOK, body: `{"provider_reference":"po-1","status":"rejected"}`, expectResult: Result{ProviderReference: "po-1", Status: "rejected"}},
+		{name: "unknown reference", status: http.StatusNotFound, body: `{}`, expectErr: ErrPayoutNotFound},
+		{name: "provider outage", status: http.StatusServiceUnavailable, body: `{}`, expectRetry: true},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			var request *http.Request
+			provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				request = r
+				w.WriteHeader(tc.status)
+				_, _ = io.WriteString(w, tc.body)
+			}))
+			defer provider.Close()
+
+			client := s.newClient(provider.URL, Config{})
+			client.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+			result, err := client.PayoutStatus(context.Background(), "ref-1")
+			require.NotNil(s.T(), request)
+			assert.Equal(s.T(), http.MethodGet, request.Method)
+			assert.Equal(s.T(), "/payouts/ref-1", request.URL.Path)
+			assert.Equal(s.T(), "ref-1", request.Header.Get(IdempotencyKeyHeader))
+			assert.Equal(s.T(), Sign([]byte(testSecret), "1700000000", nil), request.Header.Get(SignatureHeader))
+
+			switch {
+			case tc.expectErr != nil:
+				assert.ErrorIs(s.T(), err, tc.expectErr)
+			case tc.expectRetry:
+				assert.True(s.T(), IsRetryable(err))
+			default:
+				require.NoError(s.T(), err)
+				assert.Equal(s.T(), tc.expectResult, result)
+				assert.Equal(s.T(), tc.expectResult.Status == StatusRejected, result.Rejected())
+			}
+		})
+	}
+}
+
+func (s *HTTPClientSuite) TestPayout_ThrottlesOutboundRequests() {
+	provider := newFakeProvider(s.T(), http.StatusOK)
+	client := s.newClient(provider.URL, Config{RatePerSecond: 20})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.Payout(context.Background(), Request{ReferenceID: "ref-1", AmountMinor: 100})
+		require.NoError(s.T(), err)
+	}
+
+	assert.GreaterOrEqual(s.T(), time.Since(start), 100*time.Millisecond)
+	assert.Equal(s.T(), 3, provider.requestCount())
+}
+
+func TestHTTPClientSuite(t *testing.T) {
+	suite.Run(t, new(HTTPClientSuite))
+}
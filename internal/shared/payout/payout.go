@@ -0,0 +1,82 @@
+package payout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Request is the payout instruction sent to the provider after the wallet is debited.
+// ReferenceID doubles as the provider idempotency key, so retries never pay out twice.
+type Request struct {
+	ReferenceID string `json:"reference_id"`
+	UserID      string `json:"user_id"`
+	AmountMinor int64  `json:"amount_minor"`
+	Currency    string `json:"currency"`
+	ChainID     string `json:"chain_id,omitempty"`
+}
+
+// Result is the provider's acknowledgement of an accepted payout, or, from PayoutStatus,
+// the provider's current view of one.
+type Result struct {
+	ProviderReference string `json:"provider_reference"`
+	Status            string `json:"status"`
+}
+
+// Statuses the provider reports for a payout that will never be paid.
+const (
+	StatusRejected = "rejected"
+	StatusFailed   = "failed"
+)
+
+// ErrPayoutNotFound means the provider has no payout under the reference: it never
+// received the request.
+var ErrPayoutNotFound = errors.New("payout: provider has no payout for reference")
+
+// Rejected reports whether the provider settled the payout without paying it.
+func (r Result) Rejected() bool {
+	return strings.EqualFold(r.Status, StatusRejected) || strings.EqualFold(r.Status, StatusFailed)
+}
+
+// ProviderError describes a failed payout call. Retryable errors (transport failures,
+// timeouts, 429 and 5xx responses) may succeed later; the rest were rejected outright.
+// Ambiguous errors left the outcome unknown: the request reached, or may have reached,
+// the provider without a definite answer coming back, so it may have been accepted.
+type ProviderError struct {
+	StatusCode int
+	Message    string
+	Retryable  bool
+	Ambiguous  bool
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	switch {
+	case e.StatusCode > 0 && e.Message != "":
+		return fmt.Sprintf("payout: provider returned status %d: %s", e.StatusCode, e.Message)
+	case e.StatusCode > 0:
+		return fmt.Sprintf("payout: provider returned status %d", e.StatusCode)
+	case e.Err != nil:
+		return fmt.Sprintf("payout: request failed: %v", e.Err)
+	default:
+		return "payout: request failed"
+	}
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether err is a ProviderError worth retrying.
+func IsRetryable(err error) bool {
+	var providerErr *ProviderError
+	return errors.As(err, &providerErr) && providerErr.Retryable
+}
+
+// NotAccepted reports whether err proves the provider did not take the payout: it
+// rejected it, or the request was never sent. Only then is it safe to reverse the debit;
+// any other error may hide an accepted payout and must be settled with PayoutStatus.
+func NotAccepted(err error) bool {
+	var providerErr *ProviderError
+	return errors.As(err, &providerErr) && !providerErr.Ambiguous
+}
@@ -0,0 +1,23 @@
+package payout
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	SignatureHeader      = "X-Payout-Signature"
+	TimestampHeader      = "X-Payout-Timestamp"
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>". Binding the timestamp lets
+// the provider reject replays of an otherwise valid signed body.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,26 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// ConcurrencyLimiter caps how many requests per key may be in flight at once.
+// Every successful Acquire must be paired with a Release for the same key.
+type ConcurrencyLimiter interface {
+	// Acquire takes an in-flight slot for key, returning false when all slots are taken.
+	Acquire(ctx context.Context, key string) (bool, error)
+
+	// Release returns a slot taken by Acquire.
+	Release(ctx context.Context, key string) error
+}
+
+// ConcurrencyConfig configures a ConcurrencyLimiter.
+type ConcurrencyConfig struct {
+	// Limit is the maximum number of in-flight requests per key.
+	Limit int64
+
+	// TTL expires a key's counter after the last Acquire, so slots leaked by a crashed
+	// instance (which never released them) are eventually reclaimed.
+	TTL time.Duration
+}
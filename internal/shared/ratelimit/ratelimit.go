@@ -4,7 +4,9 @@ package ratelimit
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -24,6 +26,11 @@ const (
 	// Good for: Simple, memory-efficient rate limiting.
 	// Note: Allows burst at window boundaries.
 	AlgorithmFixedWindow Algorithm = "fixed_window"
+
+	// AlgorithmSlidingWindowCounter weights the previous fixed window's count by its
+	// overlap with the sliding window and adds the current window's count.
+	// Good for: Near-precise limiting of high-traffic keys with O(1) memory per key.
+	AlgorithmSlidingWindowCounter Algorithm = "sliding_window_counter"
 )
 
 // KeyExtractor extracts a rate limit key from context.
@@ -106,24 +113,57 @@ type Limiter interface {
 	Close() error
 }
 
+// Reconfigurable is implemented by limiters whose Config can be replaced at runtime,
+// e.g. after a config reload. Requests already in flight finish under the old Config.
+type Reconfigurable interface {
+	// UpdateConfig validates config and swaps it in atomically. An invalid config is
+	// rejected and the current one stays in effect.
+	UpdateConfig(config Config) error
+}
+
+// PrefixResetter is implemented by stores that can clear a whole scope at once, e.g. every
+// withdraw limit during an incident, rather than one key at a time.
+type PrefixResetter interface {
+	// ResetPrefix resets every key that starts with prefix followed by ":".
+	ResetPrefix(ctx context.Context, prefix string) error
+}
+
+// ErrPrefixResetUnsupported is returned by a limiter's ResetPrefix when its store does not
+// implement PrefixResetter.
+var ErrPrefixResetUnsupported = errors.New("ratelimit: store does not support prefix reset")
+
+var (
+	_ Reconfigurable = (*limiter)(nil)
+	_ PrefixResetter = (*limiter)(nil)
+)
+
 // limiter is the concrete implementation of Limiter.
 type limiter struct {
 	store  Store
-	config Config
+	config atomic.Pointer[Config]
 }
 
 // New creates a new rate limiter with the provided store and configuration.
+// The returned Limiter also implements Reconfigurable and PrefixResetter.
 func New(store Store, config Config) (Limiter, error) {
 	if store == nil {
 		return nil, fmt.Errorf("ratelimit: store is required")
 	}
 
+	l := &limiter{store: store}
+	if err := l.UpdateConfig(config); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *limiter) UpdateConfig(config Config) error {
 	if config.Limit <= 0 {
-		return nil, fmt.Errorf("ratelimit: limit must be positive")
+		return fmt.Errorf("ratelimit: limit must be positive")
 	}
 
 	if config.Window <= 0 {
-		return nil, fmt.Errorf("ratelimit: window must be positive")
+		return fmt.Errorf("ratelimit: window must be positive")
 	}
 
 	if config.Algorithm == "" {
@@ -138,14 +178,12 @@ func New(store Store, config Config) (Limiter, error) {
 		config.KeyExtractor = DefaultKeyExtractor
 	}
 
-	return &limiter{
-		store:  store,
-		config: config,
-	}, nil
+	l.config.Store(&config)
+	return nil
 }
 
 func (l *limiter) Allow(ctx context.Context) (Result, error) {
-	key, err := l.config.KeyExtractor(ctx)
+	key, err := l.config.Load().KeyExtractor(ctx)
 	if err != nil {
 		return Result{}, fmt.Errorf("ratelimit: failed to extract key: %w", err)
 	}
@@ -153,20 +191,22 @@ func (l *limiter) Allow(ctx context.Context) (Result, error) {
 }
 
 func (l *limiter) AllowKey(ctx context.Context, key string) (Result, error) {
-	result, err := l.store.Allow(ctx, key, l.config)
+	config := l.config.Load()
+
+	result, err := l.store.Allow(ctx, key, *config)
 	if err != nil {
 		return Result{}, fmt.Errorf("ratelimit: store error: %w", err)
 	}
 
-	if !result.Allowed && l.config.OnLimited != nil {
-		l.config.OnLimited(ctx, key, result)
+	if !result.Allowed && config.OnLimited != nil {
+		config.OnLimited(ctx, key, result)
 	}
 
 	return result, nil
 }
 
 func (l *limiter) Reset(ctx context.Context) error {
-	key, err := l.config.KeyExtractor(ctx)
+	key, err := l.config.Load().KeyExtractor(ctx)
 	if err != nil {
 		return fmt.Errorf("ratelimit: failed to extract key: %w", err)
 	}
@@ -177,6 +217,15 @@ func (l *limiter) ResetKey(ctx context.Context, key string) error {
 	return l.store.Reset(ctx, key)
 }
 
+// ResetPrefix clears every key under prefix when the store supports it.
+func (l *limiter) ResetPrefix(ctx context.Context, prefix string) error {
+	resetter, ok := l.store.(PrefixResetter)
+	if !ok {
+		return ErrPrefixResetUnsupported
+	}
+	return resetter.ResetPrefix(ctx, prefix)
+}
+
 func (l *limiter) Close() error {
 	return l.store.Close()
 }
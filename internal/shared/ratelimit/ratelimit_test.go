@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// configRecordingStore allows everything and records the Config each call was made with.
+type configRecordingStore struct {
+	last Config
+}
+
+func (s *configRecordingStore) Allow(_ context.Context, _ string, config Config) (Result, error) {
+	s.last = config
+	return Result{Allowed: true, Limit: config.Limit, Remaining: config.Limit - 1}, nil
+}
+
+func (s *configRecordingStore) Reset(context.Context, string) error { return nil }
+
+func (s *configRecordingStore) Close() error { return nil }
+
+// prefixResettingStore records the prefixes ResetPrefix was called with.
+type prefixResettingStore struct {
+	configRecordingStore
+	prefixes []string
+}
+
+func (s *prefixResettingStore) ResetPrefix(_ context.Context, prefix string) error {
+	s.prefixes = append(s.prefixes, prefix)
+	return nil
+}
+
+func TestLimiterResetPrefix_TableDriven(t *testing.T) {
+	tests := []struct {
+		name     string
+		store    Store
+		expected error
+	}{
+		{name: "delegates to a prefix resetting store", store: &prefixResettingStore{}},
+		{name: "store without prefix reset", store: &configRecordingStore{}, expected: ErrPrefixResetUnsupported},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter, err := New(tc.store, Config{Limit: 10, Window: time.Minute})
+			require.NoError(t, err)
+
+			resetter, ok := limiter.(PrefixResetter)
+			require.True(t, ok)
+
+			err = resetter.ResetPrefix(context.Background(), "withdraw")
+			if tc.expected != nil {
+				assert.ErrorIs(t, err, tc.expected)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"withdraw"}, tc.store.(*prefixResettingStore).prefixes)
+		})
+	}
+}
+
+func TestLimiterUpdateConfig_TableDriven(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      Config
+		expectErr   string
+		expectLimit int64
+		expectBurst int64
+	}{
+		{name: "applies new limit with defaults", update: Config{Limit: 50, Window: time.Minute}, expectLimit: 50, expectBurst: 50},
+		{name: "rejects non-positive limit", update: Config{Limit: 0, Window: time.Minute}, expectErr: "limit must be positive", expectLimit: 10, expectBurst: 10},
+		{name: "rejects non-positive window", update: Config{Limit: 50}, expectErr: "window must be positive", expectLimit: 10, expectBurst: 10},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &configRecordingStore{}
+			limiter, err := New(store, Config{Limit: 10, Window: time.Minute})
+			require.NoError(t, err)
+
+			reconfigurable, ok := limiter.(Reconfigurable)
+			require.True(t, ok)
+
+			err = reconfigurable.UpdateConfig(tc.update)
+			if tc.expectErr != "" {
+				assert.ErrorContains(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			_, err = limiter.AllowKey(context.Background(), "user:1")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectLimit, store.last.Limit)
+			assert.Equal(t, tc.expectBurst, store.last.Burst)
+			assert.Equal(t, AlgorithmTokenBucket, store.last.Algorithm)
+		})
+	}
+}
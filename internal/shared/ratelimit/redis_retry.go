@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRedisMaxRetries     = 2
+	defaultRedisRetryBaseDelay = 10 * time.Millisecond
+)
+
+// transientRedisReplies are server error prefixes for conditions that clear on their own,
+// such as a replica still loading its dataset or a failover in progress. Redis refuses the
+// command outright in each case, so the script has not touched the key.
+var transientRedisReplies = []string{"LOADING", "BUSY", "TRYAGAIN", "CLUSTERDOWN", "MASTERDOWN", "READONLY"}
+
+// redisReplyError matches errors returned by the Redis server (go-redis' redis.Error)
+// without tying this file to the client package.
+type redisReplyError interface {
+	error
+	RedisError()
+}
+
+// redisRetryPolicy bounds how often a failed Redis call is repeated.
+type redisRetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+// do runs op, retrying failures that happened before Redis ran the command with
+// exponential backoff. Everything else, including cancelled contexts, is returned
+// immediately.
+func (p redisRetryPolicy) do(ctx context.Context, op func() error) error {
+	delay := p.BaseDelay
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= p.MaxRetries || !isRetryableRedisError(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// isRetryableRedisError reports whether err guarantees the command never ran: Redis
+// refused it, or the connection could not be established. A reset, EOF or timeout on an
+// established connection may come after the script already counted the request, so
+// repeating it would count the same request twice.
+func isRetryableRedisError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var reply redisReplyError
+	if errors.As(err, &reply) {
+		message := reply.Error()
+		for _, prefix := range transientRedisReplies {
+			if strings.HasPrefix(message, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
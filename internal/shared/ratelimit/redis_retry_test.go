@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedisReply behaves like a server error reply from go-redis.
+type fakeRedisReply string
+
+func (e fakeRedisReply) Error() string { return string(e) }
+
+func (fakeRedisReply) RedisError() {}
+
+// flakyEval fails with the queued errors before succeeding.
+type flakyEval struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakyEval) call() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func TestRedisRetryPolicyDo_TableDriven(t *testing.T) {
+	connReset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	dialRefused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	readTimeout := &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	scriptErr := fakeRedisReply("ERR Error running script (call to f_1): user_script:3: bad argument")
+
+	tests := []struct {
+		name          string
+		maxRetries    int
+		errs          []error
+		expectedErr   error
+		expectedCalls int
+	}{
+		{name: "success needs one call", maxRetries: 2, expectedCalls: 1},
+		{name: "loading reply retried once then succeeds", maxRetries: 2, errs: []error{fakeRedisReply("LOADING Redis is loading the dataset in memory")}, expectedCalls: 2},
+		{name: "dial failure retried then succeeds", maxRetries: 2, errs: []error{dialRefused}, expectedCalls: 2},
+		{name: "wrapped connection refused retried then succeeds", maxRetries: 2, errs: []error{fmt.Errorf("dial: %w", syscall.ECONNREFUSED)}, expectedCalls: 2},
+		{name: "reset after the script was sent is not retried", maxRetries: 2, errs: []error{connReset}, expectedErr: connReset, expectedCalls: 1},
+		{name: "read timeout is not retried", maxRetries: 2, errs: []error{readTimeout}, expectedErr: readTimeout, expectedCalls: 1},
+		{name: "eof is not retried", maxRetries: 2, errs: []error{io.EOF}, expectedErr: io.EOF, expectedCalls: 1},
+		{name: "script error is not retried", maxRetries: 2, errs: []error{scriptErr}, expectedErr: scriptErr, expectedCalls: 1},
+		{name: "retries are bounded", maxRetries: 2, errs: []error{dialRefused, dialRefused, dialRefused, dialRefused}, expectedErr: dialRefused, expectedCalls: 3},
+		{name: "zero retries disables retrying", maxRetries: 0, errs: []error{dialRefused}, expectedErr: dialRefused, expectedCalls: 1},
+		{name: "deadline exceeded is not retried", maxRetries: 2, errs: []error{context.DeadlineExceeded}, expectedErr: context.DeadlineExceeded, expectedCalls: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &flakyEval{errs: tc.errs}
+			policy := redisRetryPolicy{MaxRetries: tc.maxRetries, BaseDelay: time.Millisecond}
+
+			err := policy.do(context.Background(), fake.call)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedCalls, fake.calls)
+		})
+	}
+}
+
+func TestRedisRetryPolicyDo_StopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fake := &flakyEval{errs: []error{fakeRedisReply("READONLY You can't write against a read only replica.")}}
+	policy := redisRetryPolicy{MaxRetries: 5, BaseDelay: time.Hour}
+
+	err := policy.do(ctx, fake.call)
+	assert.ErrorContains(t, err, "READONLY")
+	assert.Equal(t, 1, fake.calls)
+}
@@ -4,16 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// redisResetBatchSize is the SCAN COUNT hint used by ResetPrefix, and so roughly the
+// number of keys removed per DEL.
+const redisResetBatchSize = 100
+
+var _ PrefixResetter = (*RedisStore)(nil)
+
 // RedisStore is a distributed rate limit store using Redis.
 // Safe for multi-instance deployments.
 type RedisStore struct {
-	client *redis.Client
-	prefix string
+	client     *redis.Client
+	prefix     string
+	ownsClient bool
+	retry      redisRetryPolicy
 }
 
 // RedisStoreOption configures the Redis store.
@@ -26,11 +36,31 @@ func WithRedisPrefix(prefix string) RedisStoreOption {
 	}
 }
 
+// WithRedisClientOwnership makes Close also close the underlying client.
+// Leave it unset when the client is shared with other components.
+func WithRedisClientOwnership() RedisStoreOption {
+	return func(s *RedisStore) {
+		s.ownsClient = true
+	}
+}
+
+// WithRedisRetry retries script calls that fail before Redis ran them (dial failures,
+// LOADING/READONLY replies) up to maxRetries times, doubling baseDelay between attempts.
+// Errors on an established connection and script errors are never retried, since the
+// request may already have been counted. A maxRetries of 0 disables retries.
+func WithRedisRetry(maxRetries int, baseDelay time.Duration) RedisStoreOption {
+	return func(s *RedisStore) {
+		s.retry = redisRetryPolicy{MaxRetries: max(maxRetries, 0), BaseDelay: max(baseDelay, 0)}
+	}
+}
+
 // NewRedisStore creates a new Redis-based rate limit store.
+// The client is borrowed by default; see WithRedisClientOwnership.
 func NewRedisStore(client *redis.Client, opts ...RedisStoreOption) *RedisStore {
 	s := &RedisStore{
 		client: client,
 		prefix: "ratelimit",
+		retry:  redisRetryPolicy{MaxRetries: defaultRedisMaxRetries, BaseDelay: defaultRedisRetryBaseDelay},
 	}
 
 	for _, opt := range opts {
@@ -54,6 +84,8 @@ func (s *RedisStore) Allow(ctx context.Context, key string, config Config) (Resu
 		return s.slidingWindow(ctx, fullKey, config)
 	case AlgorithmFixedWindow:
 		return s.fixedWindow(ctx, fullKey, config)
+	case AlgorithmSlidingWindowCounter:
+		return s.slidingWindowCounter(ctx, fullKey, config)
 	default:
 		return s.tokenBucket(ctx, fullKey, config)
 	}
@@ -98,13 +130,13 @@ return {allowed, math.floor(remaining), math.floor(retryAfter)}
 	now := float64(time.Now().UnixMilli())
 	windowMs := float64(config.Window.Milliseconds())
 
-	result, err := s.client.Eval(ctx, script, []string{key},
+	result, err := s.eval(ctx, script, []string{key},
 		config.Limit,
 		config.Burst,
 		windowMs,
 		now,
 		1,
-	).Slice()
+	)
 	if err != nil {
 		return Result{}, fmt.Errorf("ratelimit: redis eval failed: %w", err)
 	}
@@ -157,11 +189,11 @@ return {allowed, remaining, math.floor(retryAfter)}
 	now := float64(time.Now().UnixMilli())
 	windowMs := config.Window.Milliseconds()
 
-	result, err := s.client.Eval(ctx, script, []string{key},
+	result, err := s.eval(ctx, script, []string{key},
 		config.Limit,
 		windowMs,
 		now,
-	).Slice()
+	)
 	if err != nil {
 		return Result{}, fmt.Errorf("ratelimit: redis eval failed: %w", err)
 	}
@@ -206,10 +238,10 @@ return {allowed, remaining, ttl}
 
 	windowMs := config.Window.Milliseconds()
 
-	result, err := s.client.Eval(ctx, script, []string{key},
+	result, err := s.eval(ctx, script, []string{key},
 		config.Limit,
 		windowMs,
-	).Slice()
+	)
 	if err != nil {
 		return Result{}, fmt.Errorf("ratelimit: redis eval failed: %w", err)
 	}
@@ -232,6 +264,88 @@ return {allowed, remaining, ttl}
 	}, nil
 }
 
+// slidingWindowCounter keeps only the current and previous window counts in a hash, so
+// memory stays constant no matter how much traffic a key receives.
+func (s *RedisStore) slidingWindowCounter(ctx context.Context, key string, config Config) (Result, error) {
+	const script = `
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local index = tonumber(ARGV[3])
+local weight = tonumber(ARGV[4])
+
+local data = redis.call('HMGET', key, 'index', 'current', 'previous')
+local storedIndex = tonumber(data[1])
+local current = tonumber(data[2]) or 0
+local previous = tonumber(data[3]) or 0
+
+if storedIndex ~= index then
+	if storedIndex == index - 1 then
+		previous = current
+	else
+		previous = 0
+	end
+	current = 0
+end
+
+local allowed = 0
+if previous * weight + current < limit then
+	current = current + 1
+	allowed = 1
+end
+
+redis.call('HMSET', key, 'index', index, 'current', current, 'previous', previous)
+redis.call('PEXPIRE', key, window * 2)
+
+return {allowed, previous, current}
+`
+
+	now := time.Now()
+	index, weight := slidingWindowCounterPosition(now, config.Window)
+
+	result, err := s.eval(ctx, script, []string{key},
+		config.Limit,
+		config.Window.Milliseconds(),
+		index,
+		weight,
+	)
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit: redis eval failed: %w", err)
+	}
+
+	allowed := toInt64(result[0]) == 1
+	previous := toInt64(result[1])
+	current := toInt64(result[2])
+
+	estimate := int64(math.Ceil(slidingWindowCounterEstimate(previous, current, weight)))
+	remaining := max(config.Limit-estimate, 0)
+
+	var retryAfter time.Duration
+	if !allowed {
+		elapsed := time.Duration(float64(config.Window) * (1 - weight))
+		retryAfter = slidingWindowCounterRetryAfter(config.Limit, previous, current, elapsed, config.Window)
+	}
+
+	return Result{
+		Allowed:    allowed,
+		Limit:      config.Limit,
+		Remaining:  remaining,
+		ResetAt:    now.Add(config.Window),
+		RetryAfter: retryAfter,
+	}, nil
+}
+
+// eval runs a script under the store's retry policy.
+func (s *RedisStore) eval(ctx context.Context, script string, keys []string, args ...interface{}) ([]interface{}, error) {
+	var result []interface{}
+	err := s.retry.do(ctx, func() error {
+		var evalErr error
+		result, evalErr = s.client.Eval(ctx, script, keys, args...).Slice()
+		return evalErr
+	})
+	return result, err
+}
+
 func (s *RedisStore) Reset(ctx context.Context, key string) error {
 	if s == nil || s.client == nil {
 		return errors.New("ratelimit: redis store is not initialized")
@@ -241,8 +355,55 @@ func (s *RedisStore) Reset(ctx context.Context, key string) error {
 	return s.client.Del(ctx, fullKey).Err()
 }
 
-func (s *RedisStore) Close() error {
+// ResetPrefix deletes every key under prefix, page by page with SCAN so a large keyspace
+// never blocks Redis the way KEYS would. Keys created while the scan runs may survive.
+func (s *RedisStore) ResetPrefix(ctx context.Context, prefix string) error {
 	if s == nil || s.client == nil {
+		return errors.New("ratelimit: redis store is not initialized")
+	}
+
+	if prefix == "" {
+		return errors.New("ratelimit: reset prefix must not be empty")
+	}
+
+	pattern := escapeRedisPattern(s.prefix+":"+prefix+":") + "*"
+
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, pattern, redisResetBatchSize).Result()
+		if err != nil {
+			return fmt.Errorf("ratelimit: redis scan failed: %w", err)
+		}
+
+		if len(keys) > 0 {
+			if err := s.client.Del(ctx, keys...).Err(); err != nil {
+				return fmt.Errorf("ratelimit: redis delete failed: %w", err)
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// escapeRedisPattern quotes glob metacharacters so a prefix containing them only matches
+// itself.
+func escapeRedisPattern(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+func (s *RedisStore) Close() error {
+	if s == nil || s.client == nil || !s.ownsClient {
 		return nil
 	}
 	return s.client.Close()
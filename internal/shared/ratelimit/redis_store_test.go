@@ -0,0 +1,254 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedisServer speaks just enough RESP for ResetPrefix: SCAN with MATCH/COUNT and DEL.
+// Other commands, including the client's connection handshake, get an error reply. Like
+// Redis, SCAN walks a cursor over the keyspace, so deleting between pages is safe.
+type fakeRedisServer struct {
+	listener net.Listener
+
+	mu     sync.Mutex
+	order  []string
+	values map[string]bool
+	scans  int
+}
+
+func newFakeRedisServer(t *testing.T) *fakeRedisServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeRedisServer{listener: listener, values: map[string]bool{}}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeRedisServer) client(t *testing.T) *redis.Client {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: s.listener.Addr().String(), Protocol: 2, DisableIdentity: true})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func (s *fakeRedisServer) seed(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		s.order = append(s.order, key)
+		s.values[key] = true
+	}
+}
+
+func (s *fakeRedisServer) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *fakeRedisServer) scanCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scans
+}
+
+func (s *fakeRedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readRESPCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, s.handle(args)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedisServer) handle(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "SCAN":
+		s.scans++
+		cursor, _ := strconv.Atoi(args[1])
+		pattern, count := "*", 10
+		for i := 2; i+1 < len(args); i += 2 {
+			switch strings.ToUpper(args[i]) {
+			case "MATCH":
+				pattern = args[i+1]
+			case "COUNT":
+				count, _ = strconv.Atoi(args[i+1])
+			}
+		}
+
+		end := min(cursor+count, len(s.order))
+		var matched []string
+		for _, key := range s.order[cursor:end] {
+			if ok, _ := path.Match(pattern, key); ok && s.values[key] {
+				matched = append(matched, key)
+			}
+		}
+		next := end
+		if next >= len(s.order) {
+			next = 0
+		}
+
+		reply := "*2\r\n" + respBulk(strconv.Itoa(next)) + fmt.Sprintf("*%d\r\n", len(matched))
+		for _, key := range matched {
+			reply += respBulk(key)
+		}
+		return reply
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if s.values[key] {
+				delete(s.values, key)
+				deleted++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+func readRESPCommand(reader *bufio.Reader) ([]string, error) {
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+	for range count {
+		lengthLine, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(lengthLine, "$")))
+		if err != nil {
+			return nil, err
+		}
+
+		value := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, value); err != nil {
+			return nil, err
+		}
+		args = append(args, string(value[:length]))
+	}
+	return args, nil
+}
+
+func respBulk(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+func TestRedisStoreResetPrefix_TableDriven(t *testing.T) {
+	withdrawKeys := make([]string, 0, 250)
+	for i := range 250 {
+		withdrawKeys = append(withdrawKeys, fmt.Sprintf("ratelimit:withdraw:user:%d", i))
+	}
+
+	tests := []struct {
+		name         string
+		opts         []RedisStoreOption
+		seed         []string
+		prefix       string
+		expectErr    string
+		expectedKeys []string
+		multiplePage bool
+	}{
+		{
+			name: "clears only the matching scope across scan pages",
+			seed: append(append([]string{}, withdrawKeys...),
+				"ratelimit:withdrawals:user:1",
+				"ratelimit:deposit:user:1",
+				"other:withdraw:user:1",
+			),
+			prefix:       "withdraw",
+			expectedKeys: []string{"other:withdraw:user:1", "ratelimit:deposit:user:1", "ratelimit:withdrawals:user:1"},
+			multiplePage: true,
+		},
+		{
+			name:         "uses the store prefix",
+			opts:         []RedisStoreOption{WithRedisPrefix("rl")},
+			seed:         []string{"rl:withdraw:user:1", "ratelimit:withdraw:user:1"},
+			prefix:       "withdraw",
+			expectedKeys: []string{"ratelimit:withdraw:user:1"},
+		},
+		{
+			name:         "glob characters in prefix match literally",
+			seed:         []string{"ratelimit:w*:user:1", "ratelimit:withdraw:user:1"},
+			prefix:       "w*",
+			expectedKeys: []string{"ratelimit:withdraw:user:1"},
+		},
+		{
+			name:         "empty prefix is rejected",
+			seed:         []string{"ratelimit:withdraw:user:1"},
+			expectErr:    "reset prefix must not be empty",
+			expectedKeys: []string{"ratelimit:withdraw:user:1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFakeRedisServer(t)
+			server.seed(tc.seed...)
+			store := NewRedisStore(server.client(t), tc.opts...)
+
+			err := store.ResetPrefix(context.Background(), tc.prefix)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedKeys, server.keys())
+			if tc.multiplePage {
+				assert.Greater(t, server.scanCount(), 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// slidingWindowCounterPosition returns the fixed window that now falls into and the share of
+// the previous window that still overlaps the sliding window ending at now.
+func slidingWindowCounterPosition(now time.Time, window time.Duration) (int64, float64) {
+	windowMs := window.Milliseconds()
+	nowMs := now.UnixMilli()
+	index := nowMs / windowMs
+	elapsedMs := nowMs - index*windowMs
+	return index, 1 - float64(elapsedMs)/float64(windowMs)
+}
+
+// slidingWindowCounterEstimate approximates the number of requests in the sliding window by
+// assuming the previous window's requests were spread evenly across it.
+func slidingWindowCounterEstimate(previous, current int64, weight float64) float64 {
+	return float64(previous)*weight + float64(current)
+}
+
+// slidingWindowCounterRetryAfter returns how long until the estimate drops below limit,
+// given counts that were just rejected. elapsed is the time spent in the current window.
+func slidingWindowCounterRetryAfter(limit, previous, current int64, elapsed, window time.Duration) time.Duration {
+	if current < limit && previous > 0 {
+		// The previous window's share drains linearly while the current window runs.
+		drainedAt := (1 - float64(limit-current)/float64(previous)) * float64(window)
+		return roundUpMillisecond(time.Duration(drainedAt) - elapsed)
+	}
+
+	// The current window alone is full; wait for it to become the previous window and drain.
+	untilNext := window - elapsed
+	if current <= 0 {
+		return roundUpMillisecond(untilNext)
+	}
+	drainedAt := (1 - float64(limit)/float64(current)) * float64(window)
+	return roundUpMillisecond(untilNext + time.Duration(drainedAt))
+}
+
+// roundUpMillisecond moves d just past the instant the estimate reaches limit, since a
+// request is only allowed once the estimate is strictly below it.
+func roundUpMillisecond(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return time.Duration(math.Floor(float64(d)/float64(time.Millisecond))+1) * time.Millisecond
+}
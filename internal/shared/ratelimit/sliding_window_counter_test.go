@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exactWindow mirrors the sliding window log: every accepted timestamp is kept.
+type exactWindow struct {
+	limit  int64
+	window time.Duration
+	log    []time.Time
+}
+
+func (w *exactWindow) allow(now time.Time) bool {
+	for len(w.log) > 0 && !w.log[0].After(now.Add(-w.window)) {
+		w.log = w.log[1:]
+	}
+	if int64(len(w.log)) >= w.limit {
+		return false
+	}
+	w.log = append(w.log, now)
+	return true
+}
+
+// counterWindow mirrors the sliding window counter script's rollover and decision.
+type counterWindow struct {
+	limit    int64
+	window   time.Duration
+	index    int64
+	current  int64
+	previous int64
+}
+
+func (w *counterWindow) allow(now time.Time) bool {
+	index, weight := slidingWindowCounterPosition(now, w.window)
+	if index != w.index {
+		if index == w.index+1 {
+			w.previous = w.current
+		} else {
+			w.previous = 0
+		}
+		w.current = 0
+		w.index = index
+	}
+	if slidingWindowCounterEstimate(w.previous, w.current, weight) >= float64(w.limit) {
+		return false
+	}
+	w.current++
+	return true
+}
+
+func TestSlidingWindowCounter_AccuracyUnderSteadyTraffic_TableDriven(t *testing.T) {
+	const (
+		limit  = int64(100)
+		window = 10 * time.Second
+	)
+
+	tests := []struct {
+		name      string
+		rate      float64 // multiple of the configured limit
+		tolerance float64
+	}{
+		{name: "below limit", rate: 0.5, tolerance: 0},
+		{name: "at limit", rate: 1, tolerance: 0.02},
+		{name: "twice the limit", rate: 2, tolerance: 0.02},
+		{name: "five times the limit", rate: 5, tolerance: 0.02},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			exact := &exactWindow{limit: limit, window: window}
+			counter := &counterWindow{limit: limit, window: window}
+
+			interval := time.Duration(float64(window) / (float64(limit) * tc.rate))
+			start := time.UnixMilli(0).Add(window * 1000)
+			end := start.Add(window * 30)
+
+			var exactAllowed, counterAllowed int64
+			var accepted []time.Time
+			for now := start; now.Before(end); now = now.Add(interval) {
+				if exact.allow(now) {
+					exactAllowed++
+				}
+				if counter.allow(now) {
+					counterAllowed++
+					accepted = append(accepted, now)
+				}
+			}
+
+			require.Positive(t, exactAllowed)
+			drift := float64(counterAllowed-exactAllowed) / float64(exactAllowed)
+			assert.LessOrEqual(t, abs(drift), tc.tolerance, "exact=%d counter=%d", exactAllowed, counterAllowed)
+
+			// No sliding window may hold noticeably more than the limit.
+			maxInWindow := int64(0)
+			for i, first := 0, 0; i < len(accepted); i++ {
+				for !accepted[first].After(accepted[i].Add(-window)) {
+					first++
+				}
+				maxInWindow = max(maxInWindow, int64(i-first+1))
+			}
+			assert.LessOrEqual(t, float64(maxInWindow), float64(limit)*(1+tc.tolerance))
+		})
+	}
+}
+
+func TestSlidingWindowCounterPosition_TableDriven(t *testing.T) {
+	window := 10 * time.Second
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		expectIndex int64
+		expectRatio float64
+	}{
+		{name: "window start", now: time.UnixMilli(20_000), expectIndex: 2, expectRatio: 1},
+		{name: "quarter through", now: time.UnixMilli(22_500), expectIndex: 2, expectRatio: 0.75},
+		{name: "just before end", now: time.UnixMilli(29_999), expectIndex: 2, expectRatio: 0.0001},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			index, weight := slidingWindowCounterPosition(tc.now, window)
+			assert.Equal(t, tc.expectIndex, index)
+			assert.InDelta(t, tc.expectRatio, weight, 1e-9)
+		})
+	}
+}
+
+func TestSlidingWindowCounterRetryAfter_TableDriven(t *testing.T) {
+	window := 10 * time.Second
+
+	tests := []struct {
+		name     string
+		previous int64
+		current  int64
+		elapsed  time.Duration
+		expect   time.Duration
+	}{
+		{name: "previous window drains", previous: 100, current: 50, elapsed: 2 * time.Second, expect: 3*time.Second + time.Millisecond},
+		{name: "already drained", previous: 100, current: 50, elapsed: 6 * time.Second, expect: 0},
+		{name: "current window full", previous: 0, current: 100, elapsed: 4 * time.Second, expect: 6*time.Second + time.Millisecond},
+		{name: "current window over limit", previous: 0, current: 200, elapsed: 4 * time.Second, expect: 11*time.Second + time.Millisecond},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, slidingWindowCounterRetryAfter(100, tc.previous, tc.current, tc.elapsed, window))
+		})
+	}
+}
+
+func abs(value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -28,6 +28,35 @@ func (q *Queries) HasWalletByUserID(ctx context.Context, userID uuid.UUID) (bool
 	return exists, err
 }
 
+const getWalletVersionByUserID = `-- name: GetWalletVersionByUserID :one
+SELECT
+    id AS wallet_id,
+    balance_minor,
+    version,
+    status
+FROM wallets
+WHERE user_id = $1::uuid
+`
+
+type GetWalletVersionByUserIDRow struct {
+	WalletID     uuid.UUID `json:"wallet_id"`
+	BalanceMinor int64     `json:"balance_minor"`
+	Version      int64     `json:"version"`
+	Status       string    `json:"status"`
+}
+
+func (q *Queries) GetWalletVersionByUserID(ctx context.Context, userID uuid.UUID) (GetWalletVersionByUserIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getWalletVersionByUserID, userID)
+	var i GetWalletVersionByUserIDRow
+	err := row.Scan(
+		&i.WalletID,
+		&i.BalanceMinor,
+		&i.Version,
+		&i.Status,
+	)
+	return i, err
+}
+
 const insertWalletLedger = `-- name: InsertWalletLedger :exec
 INSERT INTO wallet_ledger (
     wallet_id,
@@ -78,17 +107,20 @@ SET
     updated_at = now()
 WHERE user_id = $2::uuid
   AND balance_minor >= $1::bigint
+  AND version = $3::bigint
 RETURNING
     id AS wallet_id,
     user_id::text AS user_id,
     balance_minor,
     currency,
+    version,
     updated_at
 `
 
 type WithdrawWalletBalanceByUserIDParams struct {
-	AmountMinor int64     `json:"amount_minor"`
-	UserID      uuid.UUID `json:"user_id"`
+	AmountMinor     int64     `json:"amount_minor"`
+	UserID          uuid.UUID `json:"user_id"`
+	ExpectedVersion int64     `json:"expected_version"`
 }
 
 type WithdrawWalletBalanceByUserIDRow struct {
@@ -96,18 +128,98 @@ type WithdrawWalletBalanceByUserIDRow struct {
 	UserID       string    `json:"user_id"`
 	BalanceMinor int64     `json:"balance_minor"`
 	Currency     string    `json:"currency"`
+	Version      int64     `json:"version"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 func (q *Queries) WithdrawWalletBalanceByUserID(ctx context.Context, arg WithdrawWalletBalanceByUserIDParams) (WithdrawWalletBalanceByUserIDRow, error) {
-	row := q.db.QueryRowContext(ctx, withdrawWalletBalanceByUserID, arg.AmountMinor, arg.UserID)
+	row := q.db.QueryRowContext(ctx, withdrawWalletBalanceByUserID, arg.AmountMinor, arg.UserID, arg.ExpectedVersion)
 	var i WithdrawWalletBalanceByUserIDRow
 	err := row.Scan(
 		&i.WalletID,
 		&i.UserID,
 		&i.BalanceMinor,
 		&i.Currency,
+		&i.Version,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
+
+const getWithdrawalLedgerByReferenceID = `-- name: GetWithdrawalLedgerByReferenceID :many
+SELECT
+    l.entry_type,
+    l.amount_minor,
+    l.chain_id,
+    w.currency,
+    l.created_at
+FROM wallet_ledger l
+JOIN wallets w ON w.id = l.wallet_id
+WHERE l.reference_id = $1::text
+  AND w.user_id = $2::uuid
+  AND l.entry_type IN ('withdrawal', 'fee', 'withdrawal_reversal', 'fee_reversal')
+ORDER BY l.created_at, l.id
+`
+
+type GetWithdrawalLedgerByReferenceIDParams struct {
+	ReferenceID string    `json:"reference_id"`
+	UserID      uuid.UUID `json:"user_id"`
+}
+
+type GetWithdrawalLedgerByReferenceIDRow struct {
+	EntryType   string         `json:"entry_type"`
+	AmountMinor int64          `json:"amount_minor"`
+	ChainID     sql.NullString `json:"chain_id"`
+	Currency    string         `json:"currency"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+func (q *Queries) GetWithdrawalLedgerByReferenceID(ctx context.Context, arg GetWithdrawalLedgerByReferenceIDParams) ([]GetWithdrawalLedgerByReferenceIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWithdrawalLedgerByReferenceID, arg.ReferenceID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetWithdrawalLedgerByReferenceIDRow{}
+	for rows.Next() {
+		var i GetWithdrawalLedgerByReferenceIDRow
+		if err := rows.Scan(
+			&i.EntryType,
+			&i.AmountMinor,
+			&i.ChainID,
+			&i.Currency,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumWithdrawalsByUserSince = `-- name: SumWithdrawalsByUserSince :one
+SELECT COALESCE(SUM(-l.amount_minor), 0)::bigint AS withdrawn_minor
+FROM wallet_ledger l
+JOIN wallets w ON w.id = l.wallet_id
+WHERE w.user_id = $1::uuid
+  AND l.entry_type IN ('withdrawal', 'withdrawal_reversal')
+  AND l.created_at >= $2::timestamptz
+`
+
+type SumWithdrawalsByUserSinceParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Since  time.Time `json:"since"`
+}
+
+func (q *Queries) SumWithdrawalsByUserSince(ctx context.Context, arg SumWithdrawalsByUserSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumWithdrawalsByUserSince, arg.UserID, arg.Since)
+	var withdrawn_minor int64
+	err := row.Scan(&withdrawn_minor)
+	return withdrawn_minor, err
+}
@@ -19,6 +19,7 @@ type Wallet struct {
 	Version      int64     `json:"version"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	Status       string    `json:"status"`
 }
 
 type WalletLedger struct {
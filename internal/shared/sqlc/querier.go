@@ -11,9 +11,19 @@ import (
 )
 
 type Querier interface {
+	AdjustWalletBalanceByUserID(ctx context.Context, arg AdjustWalletBalanceByUserIDParams) (AdjustWalletBalanceByUserIDRow, error)
+	CreateWallet(ctx context.Context, arg CreateWalletParams) (CreateWalletRow, error)
+	CreditWalletBalanceByID(ctx context.Context, arg CreditWalletBalanceByIDParams) (CreditWalletBalanceByIDRow, error)
+	DebitWalletBalanceByID(ctx context.Context, arg DebitWalletBalanceByIDParams) (DebitWalletBalanceByIDRow, error)
+	DepositWalletBalanceByUserID(ctx context.Context, arg DepositWalletBalanceByUserIDParams) (DepositWalletBalanceByUserIDRow, error)
 	GetWalletBalanceByUserID(ctx context.Context, userID uuid.UUID) (GetWalletBalanceByUserIDRow, error)
+	GetWalletStatusByIDAndUserID(ctx context.Context, arg GetWalletStatusByIDAndUserIDParams) (string, error)
+	GetWalletVersionByUserID(ctx context.Context, userID uuid.UUID) (GetWalletVersionByUserIDRow, error)
+	GetWithdrawalLedgerByReferenceID(ctx context.Context, arg GetWithdrawalLedgerByReferenceIDParams) ([]GetWithdrawalLedgerByReferenceIDRow, error)
 	HasWalletByUserID(ctx context.Context, userID uuid.UUID) (bool, error)
 	InsertWalletLedger(ctx context.Context, arg InsertWalletLedgerParams) error
+	SetWalletStatusByUserID(ctx context.Context, arg SetWalletStatusByUserIDParams) (SetWalletStatusByUserIDRow, error)
+	SumWithdrawalsByUserSince(ctx context.Context, arg SumWithdrawalsByUserSinceParams) (int64, error)
 	WithdrawWalletBalanceByUserID(ctx context.Context, arg WithdrawWalletBalanceByUserIDParams) (WithdrawWalletBalanceByUserIDRow, error)
 }
 
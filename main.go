@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"log"
 	"strings"
 
 	"go.uber.org/fx"
@@ -11,31 +12,57 @@ import (
 
 var defaultBin string
 
+var (
+	runServer = func(bin string) {
+		app.New(bin, selectedModules(bin)...).Run()
+	}
+	runMigration = app.Migrate
+)
+
 func selectedModules(binValue string) []fx.Option {
 	selected := strings.TrimSpace(strings.ToLower(binValue))
-
 	switch selected {
 	case "inquiry", "inqury":
 		return []fx.Option{
 			app.AuthModule(),
 			app.InquiryModule(),
+			app.TransactionExportModule(),
 		}
 	case "withdraw":
 		return []fx.Option{
 			app.WithdrawModule(),
+			app.DepositModule(),
+			app.TransferModule(),
+			app.WalletModule(),
 		}
 	default:
 		return []fx.Option{
 			app.AuthModule(),
 			app.InquiryModule(),
+			app.TransactionExportModule(),
 			app.WithdrawModule(),
+			app.DepositModule(),
+			app.TransferModule(),
+			app.WalletModule(),
 		}
 	}
 }
 
+func dispatch(bin, migrateAction string) error {
+	if strings.TrimSpace(migrateAction) != "" {
+		return runMigration(bin, migrateAction)
+	}
+
+	runServer(bin)
+	return nil
+}
+
 func main() {
 	bin := flag.String("bin", defaultBin, "select module binary: inquiry|withdraw (default: all)")
+	migrate := flag.String("migrate", "", "run database migrations and exit: up|down|status")
 	flag.Parse()
 
-	app.New(*bin, selectedModules(*bin)...).Run()
+	if err := dispatch(*bin, *migrate); err != nil {
+		log.Fatal(err)
+	}
 }
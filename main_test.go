@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatch_TableDriven(t *testing.T) {
+	migrationErr := errors.New("migration failed")
+
+	tests := []struct {
+		name            string
+		bin             string
+		migrate         string
+		migrationResult error
+		expectServer    bool
+		expectMigration string
+		expectErr       error
+	}{
+		{name: "no migrate flag starts server", bin: "withdraw", expectServer: true},
+		{name: "blank migrate flag starts server", bin: "withdraw", migrate: "  ", expectServer: true},
+		{name: "migrate up skips server", bin: "withdraw", migrate: "up", expectMigration: "up"},
+		{name: "migrate status skips server", bin: "", migrate: "status", expectMigration: "status"},
+		{name: "migration error is returned", bin: "inquiry", migrate: "down", migrationResult: migrationErr, expectMigration: "down", expectErr: migrationErr},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			originalServer, originalMigration := runServer, runMigration
+			t.Cleanup(func() {
+				runServer, runMigration = originalServer, originalMigration
+			})
+
+			serverCalled := false
+			migrationAction := ""
+			runServer = func(bin string) {
+				serverCalled = true
+				assert.Equal(t, tc.bin, bin)
+			}
+			runMigration = func(bin, action string) error {
+				migrationAction = action
+				assert.Equal(t, tc.bin, bin)
+				return tc.migrationResult
+			}
+
+			err := dispatch(tc.bin, tc.migrate)
+			assert.ErrorIs(t, err, tc.expectErr)
+			assert.Equal(t, tc.expectServer, serverCalled)
+			assert.Equal(t, tc.expectMigration, migrationAction)
+		})
+	}
+}
@@ -7,7 +7,8 @@ CREATE TABLE IF NOT EXISTS wallets (
     currency varchar(3) NOT NULL DEFAULT 'IDR',
     version bigint NOT NULL DEFAULT 0,
     created_at timestamptz NOT NULL DEFAULT now(),
-    updated_at timestamptz NOT NULL DEFAULT now()
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    status varchar(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'frozen'))
 );
 
 CREATE TABLE IF NOT EXISTS wallet_ledger (
@@ -23,3 +24,6 @@ CREATE TABLE IF NOT EXISTS wallet_ledger (
 
 CREATE INDEX IF NOT EXISTS idx_wallet_ledger_wallet_created_at_desc
 ON wallet_ledger (wallet_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_ledger_wallet_created_at_id_desc
+ON wallet_ledger (wallet_id, created_at DESC, id DESC);